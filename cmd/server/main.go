@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"rpg-game/pkg/auth"
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/db"
 	"rpg-game/pkg/metrics"
 	"rpg-game/pkg/server"
@@ -17,6 +18,7 @@ import (
 var Version = "0.3.0"
 
 func main() {
+	configPath := flag.String("config", "", "path to JSON config file (optional)")
 	dbPath := flag.String("db", "game.db", "path to SQLite database file")
 	addr := flag.String("addr", ":8080", "listen address")
 	secret := flag.String("secret", "change-me-in-production", "JWT signing secret")
@@ -24,24 +26,54 @@ func main() {
 	maxAgents := flag.Int("max-agents", 20, "maximum number of AI agents")
 	flag.Parse()
 
-	store, err := db.NewStore(*dbPath)
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	// Flags given explicitly on the command line win over file and env values.
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "db":
+			cfg.Server.DBPath = *dbPath
+		case "addr":
+			cfg.Server.Addr = *addr
+		case "secret":
+			cfg.Server.Secret = *secret
+		case "static":
+			cfg.Server.StaticDir = *staticDir
+		case "max-agents":
+			cfg.Server.MaxAgents = *maxAgents
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	config.Set(cfg)
+
+	if *configPath != "" && cfg.Server.ReloadIntervalSeconds > 0 {
+		go config.Watch(*configPath, time.Duration(cfg.Server.ReloadIntervalSeconds)*time.Second, nil)
+	}
+
+	store, err := db.NewStore(cfg.Server.DBPath)
 	if err != nil {
 		log.Fatalf("failed to open database: %v", err)
 	}
 
-	authService := auth.NewAuthService(store, *secret)
+	authService := auth.NewAuthService(store, cfg.Server.Secret)
 	mc := metrics.NewMetricsCollector()
-	srv := server.NewServer(store, authService, *staticDir, Version, mc, *maxAgents)
+	srv := server.NewServer(store, authService, cfg.Server.StaticDir, Version, mc, cfg.Server.MaxAgents)
 
 	httpServer := &http.Server{
-		Addr:         *addr,
+		Addr:         cfg.Server.Addr,
 		Handler:      srv,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
-	fmt.Printf("RPG game server starting on %s\n", *addr)
+	fmt.Printf("RPG game server starting on %s\n", cfg.Server.Addr)
 	if err := httpServer.ListenAndServe(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
@@ -3,8 +3,8 @@ module rpg-game
 go 1.24.0
 
 require (
-	github.com/golang-jwt/jwt/v5 v5.3.1 // indirect
-	github.com/gorilla/websocket v1.5.3 // indirect
-	github.com/mattn/go-sqlite3 v1.14.34 // indirect
-	golang.org/x/crypto v0.48.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.1
+	github.com/gorilla/websocket v1.5.3
+	github.com/mattn/go-sqlite3 v1.14.34
+	golang.org/x/crypto v0.48.0
 )
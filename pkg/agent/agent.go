@@ -33,6 +33,7 @@ type Agent struct {
 	Error     string
 
 	eng        *engine.Engine
+	build      classBuild
 	done       chan struct{}
 	mu         sync.RWMutex
 	stuckCount int
@@ -150,6 +151,17 @@ func (a *Agent) Run() {
 			screen, options = a.extractState(resp)
 		}
 
+		// Spend talent and attribute points whenever the agent is back at
+		// the main menu.
+		if screen == "main_menu" && resp.State.Player != nil && resp.State.Player.TalentPoints > 0 {
+			resp = a.spendTalents()
+			screen, options = a.extractState(resp)
+		}
+		if screen == "main_menu" && resp.State.Player != nil && resp.State.Player.AttributePoints > 0 {
+			resp = a.spendAttributes(resp.State.Player.AttributePoints)
+			screen, options = a.extractState(resp)
+		}
+
 		// Stuck detection: if same screen for 10+ consecutive commands, reset.
 		a.mu.Lock()
 		if screen == a.lastScreen {
@@ -171,6 +183,33 @@ func (a *Agent) Run() {
 	}
 }
 
+// spendTalents opens the talents screen, buys talents following the agent's
+// build until no points are left, and returns to the main menu.
+func (a *Agent) spendTalents() engine.GameResponse {
+	resp := a.eng.ProcessCommand(a.SessionID, selectCmd("15"))
+	for i := 0; i < 50; i++ {
+		_, options := a.extractState(resp)
+		key := pickTalent(a.build.Talents, options)
+		if key == "" {
+			break
+		}
+		resp = a.eng.ProcessCommand(a.SessionID, selectCmd(key))
+	}
+	return a.eng.ProcessCommand(a.SessionID, selectCmd("back"))
+}
+
+// spendAttributes opens the attribute screen, raises the build's attributes
+// in rotation until the points are gone, and returns to the main menu.
+func (a *Agent) spendAttributes(points int) engine.GameResponse {
+	a.eng.ProcessCommand(a.SessionID, selectCmd("16"))
+	if len(a.build.Attributes) > 0 {
+		for i := 0; i < points; i++ {
+			a.eng.ProcessCommand(a.SessionID, selectCmd("add:"+a.build.Attributes[i%len(a.build.Attributes)]))
+		}
+	}
+	return a.eng.ProcessCommand(a.SessionID, selectCmd("back"))
+}
+
 // Stop signals the agent to stop its main loop.
 func (a *Agent) Stop() {
 	a.mu.Lock()
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"rpg-game/pkg/auth"
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/db"
 	"rpg-game/pkg/engine"
 )
@@ -20,7 +21,8 @@ type CreateAgentRequest struct {
 	MaxDelay int    `json:"max_delay_ms"`
 }
 
-// DefaultAgents is the roster of bot agents spawned automatically on server boot.
+// DefaultAgents is the built-in roster of bot agents spawned automatically on
+// server boot. The config's agents.roster replaces it when set.
 var DefaultAgents = []CreateAgentRequest{
 	{Name: "Grimjaw", Strategy: "hunter", MinDelay: 500, MaxDelay: 2000},
 	{Name: "Thornveil", Strategy: "hunter", MinDelay: 500, MaxDelay: 2000},
@@ -117,9 +119,17 @@ func (m *Manager) CreateAgent(req CreateAgentRequest) (*AgentInfo, error) {
 	log.Printf("[AgentManager] Created session: %s for agent %s", sessionID, req.Name)
 
 	// Rename the default "Temp" character to the agent's name.
+	charName := req.Name
 	if err := m.engine.RenameSessionCharacter(sessionID, "Temp", req.Name); err != nil {
 		log.Printf("[AgentManager] Failed to rename character for agent %s: %v", req.Name, err)
 		// Non-fatal: agent will still work with "Temp" name.
+		charName = "Temp"
+	}
+
+	// Give the character the class that suits the strategy.
+	build := strategyBuilds[strategy.Name()]
+	if err := m.engine.SetSessionCharacterClass(sessionID, charName, build.Class); err != nil {
+		log.Printf("[AgentManager] Failed to set class for agent %s: %v", req.Name, err)
 	}
 
 	agent := &Agent{
@@ -132,6 +142,7 @@ func (m *Manager) CreateAgent(req CreateAgentRequest) (*AgentInfo, error) {
 		MinDelay:  minDelay,
 		MaxDelay:  maxDelay,
 		eng:       m.engine,
+		build:     build,
 	}
 
 	m.agents[agentID] = agent
@@ -221,7 +232,7 @@ func (m *Manager) SpawnDefaultAgents() {
 
 	log.Printf("[AgentManager] Spawning default agents...")
 
-	for _, req := range DefaultAgents {
+	for _, req := range defaultRoster() {
 		info, err := m.CreateAgent(req)
 		if err != nil {
 			log.Printf("[AgentManager] Failed to spawn default agent %s: %v", req.Name, err)
@@ -236,6 +247,25 @@ func (m *Manager) SpawnDefaultAgents() {
 	go m.monitorAgents()
 }
 
+// defaultRoster returns the configured bot roster, falling back to
+// DefaultAgents when the config does not override it.
+func defaultRoster() []CreateAgentRequest {
+	roster := config.Current().Agents.Roster
+	if len(roster) == 0 {
+		return DefaultAgents
+	}
+	reqs := make([]CreateAgentRequest, 0, len(roster))
+	for _, spec := range roster {
+		reqs = append(reqs, CreateAgentRequest{
+			Name:     spec.Name,
+			Strategy: spec.Strategy,
+			MinDelay: spec.MinDelay,
+			MaxDelay: spec.MaxDelay,
+		})
+	}
+	return reqs
+}
+
 // monitorAgents periodically checks for crashed agents and respawns them.
 func (m *Manager) monitorAgents() {
 	ticker := time.NewTicker(60 * time.Second)
@@ -307,6 +307,16 @@ func handleCommonScreen(screen string, options []engine.MenuOption) engine.GameC
 		// Should not happen -- agents create characters during setup
 		return inputCmd("AgentChar")
 
+	case "character_class_select":
+		if opt := pickRandom(options); opt != nil && opt.Key != "back" {
+			return selectCmd(opt.Key)
+		}
+		return selectCmd("warrior")
+
+	case "talents", "attributes":
+		// Points are spent by the agent loop; just leave.
+		return selectCmd("back")
+
 	case "harvest_select":
 		// Pick a random resource
 		if opt := pickRandom(options); opt != nil {
@@ -355,7 +365,7 @@ func handleCommonScreen(screen string, options []engine.MenuOption) engine.GameC
 		"village_tide_wave", "village_manage_guards", "village_manage_guard",
 		"village_equip_guard", "village_unequip_guard", "village_give_item",
 		"village_take_item", "village_heal_guard", "village_fortifications",
-		"village_training", "village_healing":
+		"village_training", "village_healing", "village_jobs":
 		return defaultNavigation(screen, options)
 
 	// Town screens
@@ -606,6 +616,48 @@ func handleVillageManagerVillageScreen(options []engine.MenuOption) engine.GameC
 	return mainMenuChoice(keys, weights, options)
 }
 
+// classBuild is the class an agent plays, the order it buys talents in and
+// the attributes it raises in rotation.
+type classBuild struct {
+	Class      string
+	Talents    []string
+	Attributes []string
+}
+
+// strategyBuilds maps each strategy to the class, talents and attributes
+// that suit it.
+var strategyBuilds = map[string]classBuild{
+	"hunter": {Class: "warrior", Talents: []string{"brutality", "executioner", "warlord", "quarryman"},
+		Attributes: []string{"strength", "strength", "vitality"}},
+	"harvester": {Class: "ranger", Talents: []string{"forager", "marksman", "venomcraft", "pack_leader"},
+		Attributes: []string{"luck", "dexterity", "vitality"}},
+	"dungeon_crawler": {Class: "mage", Talents: []string{"pyromancy", "storm_caller", "arcane_focus", "transmutation"},
+		Attributes: []string{"intelligence", "intelligence", "vitality"}},
+	"arena_grinder": {Class: "warrior", Talents: []string{"brutality", "executioner", "warlord", "quarryman"},
+		Attributes: []string{"strength", "dexterity", "vitality"}},
+	"completionist": {Class: "cleric", Talents: []string{"devotion", "smite", "blessed_hands", "sanctuary"},
+		Attributes: []string{"intelligence", "vitality", "luck"}},
+	"village_manager": {Class: "cleric", Talents: []string{"sanctuary", "devotion", "smite", "blessed_hands"},
+		Attributes: []string{"vitality", "intelligence", "luck"}},
+}
+
+// pickTalent returns the "learn:" option to buy next: the first enabled one
+// in priority order, else any enabled one. Returns "" when nothing can be
+// learned.
+func pickTalent(priority []string, options []engine.MenuOption) string {
+	for _, id := range priority {
+		if opt := findOption(options, "learn:"+id); opt != nil {
+			return opt.Key
+		}
+	}
+	for _, opt := range options {
+		if opt.Enabled && strings.HasPrefix(opt.Key, "learn:") {
+			return opt.Key
+		}
+	}
+	return ""
+}
+
 // NewStrategy creates a Strategy by name. Returns nil for unknown names.
 func NewStrategy(name string) Strategy {
 	switch name {
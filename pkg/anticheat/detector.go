@@ -0,0 +1,195 @@
+// Package anticheat scores the WebSocket command stream of human accounts
+// for signs of scripted play. Agents driven by pkg/agent call the engine
+// directly and never pass through the detector.
+package anticheat
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"rpg-game/pkg/config"
+)
+
+// Rule names recorded with each flag.
+const (
+	RuleTiming   = "timing_variance"
+	RuleAlwaysOn = "always_on"
+	RuleXPRate   = "xp_rate"
+	RuleGoldRate = "gold_rate"
+	RuleSequence = "repeated_sequence"
+)
+
+// Observation is one command processed for an account, with the active
+// character's lifetime XP and current gold after the command ran.
+type Observation struct {
+	Time    time.Time
+	Command string // "type:value"
+	TotalXP int
+	Gold    int
+}
+
+// Flag is a single rule violation.
+type Flag struct {
+	Rule   string
+	Detail string
+}
+
+// minHistory is the smallest per-account command and gap history kept.
+const minHistory = 256
+
+type profile struct {
+	last      time.Time
+	gaps      []float64 // seconds between consecutive commands
+	commands  []string
+	hours     map[int64]bool // unix hour -> active
+	rateStart time.Time
+	startXP   int
+	startGold int
+	flagged   map[string]bool
+}
+
+// Detector keeps a rolling behavior profile per account.
+type Detector struct {
+	mu       sync.Mutex
+	cfg      config.AntiCheatConfig
+	history  int
+	profiles map[int64]*profile
+}
+
+// NewDetector creates a detector with the given thresholds.
+func NewDetector(cfg config.AntiCheatConfig) *Detector {
+	history := max(minHistory, cfg.TimingSamples, cfg.SequenceLength*cfg.SequenceRepeats)
+	return &Detector{cfg: cfg, history: history, profiles: make(map[int64]*profile)}
+}
+
+// Observe records a command and returns any rules the account newly broke.
+// Each rule is reported at most once per account until Forget is called.
+func (d *Detector) Observe(accountID int64, obs Observation) []Flag {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p, ok := d.profiles[accountID]
+	if !ok {
+		p = &profile{hours: make(map[int64]bool), flagged: make(map[string]bool)}
+		d.profiles[accountID] = p
+	}
+
+	// A drop in XP means the player switched to a weaker character; restart
+	// the rate window rather than report a negative rate.
+	if p.rateStart.IsZero() || obs.TotalXP < p.startXP {
+		p.rateStart, p.startXP, p.startGold = obs.Time, obs.TotalXP, obs.Gold
+	}
+	if !p.last.IsZero() {
+		p.gaps = appendBounded(p.gaps, obs.Time.Sub(p.last).Seconds(), d.history)
+	}
+	p.last = obs.Time
+	p.commands = appendBounded(p.commands, obs.Command, d.history)
+
+	hour := obs.Time.Unix() / 3600
+	p.hours[hour] = true
+	for h := range p.hours {
+		if h <= hour-24 {
+			delete(p.hours, h)
+		}
+	}
+
+	var flags []Flag
+	report := func(rule, detail string) {
+		if !p.flagged[rule] {
+			p.flagged[rule] = true
+			flags = append(flags, Flag{Rule: rule, Detail: detail})
+		}
+	}
+
+	if len(p.gaps) >= d.cfg.TimingSamples {
+		mean, cv := variation(p.gaps[len(p.gaps)-d.cfg.TimingSamples:])
+		if mean > 0 && cv*100 < float64(d.cfg.MinTimingCVPercent) {
+			report(RuleTiming, fmt.Sprintf("%d commands %.2fs apart with %.1f%% variation", d.cfg.TimingSamples, mean, cv*100))
+		}
+	}
+
+	if len(p.hours) > d.cfg.MaxActiveHours {
+		report(RuleAlwaysOn, fmt.Sprintf("active in %d of the last 24 hours", len(p.hours)))
+	}
+
+	elapsed := obs.Time.Sub(p.rateStart)
+	if elapsed >= time.Duration(d.cfg.RateWindowMinutes)*time.Minute {
+		hours := elapsed.Hours()
+		if xpRate := float64(obs.TotalXP-p.startXP) / hours; xpRate > float64(d.cfg.MaxXPPerHour) {
+			report(RuleXPRate, fmt.Sprintf("%.0f XP/hour over %s (limit %d)", xpRate, elapsed.Round(time.Minute), d.cfg.MaxXPPerHour))
+		}
+		if goldRate := float64(obs.Gold-p.startGold) / hours; goldRate > float64(d.cfg.MaxGoldPerHour) {
+			report(RuleGoldRate, fmt.Sprintf("%.0f gold/hour over %s (limit %d)", goldRate, elapsed.Round(time.Minute), d.cfg.MaxGoldPerHour))
+		}
+	}
+
+	if n := repeatedTail(p.commands, d.cfg.SequenceLength); n >= d.cfg.SequenceRepeats {
+		report(RuleSequence, fmt.Sprintf("same %d-command sequence repeated %d times", d.cfg.SequenceLength, n))
+	}
+
+	return flags
+}
+
+// Forget drops an account's profile, e.g. after an admin clears its flags.
+func (d *Detector) Forget(accountID int64) {
+	d.mu.Lock()
+	delete(d.profiles, accountID)
+	d.mu.Unlock()
+}
+
+func appendBounded[T any](s []T, v T, limit int) []T {
+	s = append(s, v)
+	if len(s) > limit {
+		s = s[len(s)-limit:]
+	}
+	return s
+}
+
+// variation returns the mean and coefficient of variation of xs.
+func variation(xs []float64) (mean, cv float64) {
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	if mean == 0 {
+		return 0, 0
+	}
+	var sq float64
+	for _, x := range xs {
+		sq += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sq/float64(len(xs))) / mean
+}
+
+// repeatedTail counts how many times the last n commands repeat back to back
+// at the end of the history. A tail of one command pressed n times (mashing
+// attack) does not count as a sequence.
+func repeatedTail(cmds []string, n int) int {
+	if len(cmds) < n {
+		return 0
+	}
+	tail := cmds[len(cmds)-n:]
+	distinct := false
+	for _, c := range tail[1:] {
+		if c != tail[0] {
+			distinct = true
+			break
+		}
+	}
+	if !distinct {
+		return 0
+	}
+	count := 1
+	for end := len(cmds) - n; end-n >= 0; end -= n {
+		block := cmds[end-n : end]
+		for i := range block {
+			if block[i] != tail[i] {
+				return count
+			}
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,106 @@
+package anticheat
+
+import (
+	"testing"
+	"time"
+
+	"rpg-game/pkg/config"
+)
+
+func testConfig() config.AntiCheatConfig {
+	return config.Default().AntiCheat
+}
+
+func hasRule(flags []Flag, rule string) bool {
+	for _, f := range flags {
+		if f.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTimingVarianceFlagsMetronomeInput(t *testing.T) {
+	d := NewDetector(testConfig())
+	start := time.Unix(1_700_000_000, 0)
+	var flagged bool
+	for i := 0; i < 80; i++ {
+		obs := Observation{Time: start.Add(time.Duration(i) * 500 * time.Millisecond), Command: "select:" + string(rune('1'+i%3))}
+		if hasRule(d.Observe(1, obs), RuleTiming) {
+			flagged = true
+		}
+	}
+	if !flagged {
+		t.Error("expected perfectly regular input to be flagged")
+	}
+
+	// Irregular human-like gaps should not trip the rule.
+	gaps := []int{300, 1200, 700, 2500, 450, 900, 4000, 650}
+	at := start
+	for i := 0; i < 80; i++ {
+		at = at.Add(time.Duration(gaps[i%len(gaps)]+i*7) * time.Millisecond)
+		if hasRule(d.Observe(2, Observation{Time: at, Command: "select:1"}), RuleTiming) {
+			t.Fatalf("irregular input flagged at command %d", i)
+		}
+	}
+}
+
+func TestRepeatedSequenceFlagged(t *testing.T) {
+	cfg := testConfig()
+	d := NewDetector(cfg)
+	seq := []string{"select:1", "select:2", "select:1", "select:3", "select:2", "select:0"}
+	at := time.Unix(1_700_000_000, 0)
+	var flags []Flag
+	for i := 0; i < len(seq)*cfg.SequenceRepeats; i++ {
+		at = at.Add(time.Duration(400+(i*37)%900) * time.Millisecond)
+		flags = append(flags, d.Observe(1, Observation{Time: at, Command: seq[i%len(seq)]})...)
+	}
+	if !hasRule(flags, RuleSequence) {
+		t.Error("expected repeated option sequence to be flagged")
+	}
+
+	// Mashing one button is not a sequence.
+	if n := repeatedTail([]string{"a", "a", "a", "a", "a", "a"}, 2); n != 0 {
+		t.Errorf("single repeated command counted as sequence: %d", n)
+	}
+}
+
+func TestRateFlagsAndReportOnce(t *testing.T) {
+	cfg := testConfig()
+	d := NewDetector(cfg)
+	start := time.Unix(1_700_000_000, 0)
+	d.Observe(1, Observation{Time: start, Command: "init:", TotalXP: 100, Gold: 50})
+
+	later := start.Add(time.Duration(cfg.RateWindowMinutes) * time.Minute)
+	flags := d.Observe(1, Observation{Time: later, Command: "select:1", TotalXP: 100 + cfg.MaxXPPerHour, Gold: 50})
+	if !hasRule(flags, RuleXPRate) {
+		t.Fatalf("expected XP rate flag, got %+v", flags)
+	}
+	if hasRule(flags, RuleGoldRate) {
+		t.Error("gold did not change and should not be flagged")
+	}
+	if again := d.Observe(1, Observation{Time: later.Add(time.Second), Command: "select:1", TotalXP: 200 + cfg.MaxXPPerHour}); hasRule(again, RuleXPRate) {
+		t.Error("rule should be reported once per account")
+	}
+
+	d.Forget(1)
+	if got := d.Observe(1, Observation{Time: later.Add(time.Minute), Command: "init:", TotalXP: 10 * cfg.MaxXPPerHour}); len(got) != 0 {
+		t.Errorf("expected a fresh profile after Forget, got %+v", got)
+	}
+}
+
+func TestAlwaysOnFlagged(t *testing.T) {
+	cfg := testConfig()
+	d := NewDetector(cfg)
+	start := time.Unix(1_700_000_000, 0).Truncate(time.Hour)
+	var flagged bool
+	for h := 0; h <= cfg.MaxActiveHours; h++ {
+		obs := Observation{Time: start.Add(time.Duration(h)*time.Hour + time.Duration(h*13)*time.Second), Command: "select:1"}
+		if hasRule(d.Observe(1, obs), RuleAlwaysOn) {
+			flagged = true
+		}
+	}
+	if !flagged {
+		t.Errorf("expected activity in %d distinct hours to be flagged", cfg.MaxActiveHours+1)
+	}
+}
@@ -0,0 +1,476 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds every server tunable. Values are layered: built-in defaults,
+// then the JSON config file, then RPG_* environment variables, then any
+// command-line flags the operator set explicitly.
+type Config struct {
+	Server    ServerConfig    `json:"server"`
+	Tickers   TickerConfig    `json:"tickers"`
+	Agents    AgentConfig     `json:"agents"`
+	Calendar  CalendarConfig  `json:"calendar"`
+	Balance   BalanceConfig   `json:"balance"`
+	AntiCheat AntiCheatConfig `json:"anticheat"`
+
+	epoch time.Time // parsed Calendar.Epoch, set by Validate
+}
+
+// ServerConfig holds process-level settings. Changes require a restart.
+type ServerConfig struct {
+	Addr       string   `json:"addr" env:"RPG_ADDR"`
+	DBPath     string   `json:"db" env:"RPG_DB"`
+	Secret     string   `json:"secret" env:"RPG_SECRET"`
+	StaticDir  string   `json:"static" env:"RPG_STATIC"`
+	MaxAgents  int      `json:"max_agents" env:"RPG_MAX_AGENTS"`
+	AdminUsers []string `json:"admin_users" env:"RPG_ADMIN_USERS"`
+	// MetricsToken, when set, must be sent as a bearer token to scrape /metrics.
+	MetricsToken string `json:"metrics_token" env:"RPG_METRICS_TOKEN"`
+	// ReloadIntervalSeconds is how often the config file is polled for
+	// balance changes. 0 disables live reload.
+	ReloadIntervalSeconds int `json:"reload_interval_seconds" env:"RPG_RELOAD_INTERVAL_SECONDS"`
+}
+
+// TickerConfig holds background ticker intervals. Changes require a restart.
+type TickerConfig struct {
+	EvolutionSeconds      int `json:"evolution_seconds" env:"RPG_EVOLUTION_SECONDS"`
+	AutoTideSeconds       int `json:"auto_tide_seconds" env:"RPG_AUTO_TIDE_SECONDS"`
+	VillageManagerSeconds int `json:"village_manager_seconds" env:"RPG_VILLAGE_MANAGER_SECONDS"`
+	TideLeaderSeconds     int `json:"tide_leader_seconds" env:"RPG_TIDE_LEADER_SECONDS"`
+	HarvestSeconds        int `json:"harvest_seconds" env:"RPG_HARVEST_SECONDS"`
+	VillageJobSeconds     int `json:"village_job_seconds" env:"RPG_VILLAGE_JOB_SECONDS"`
+	PresenceSeconds       int `json:"presence_seconds" env:"RPG_PRESENCE_SECONDS"`
+	// MetricsSnapshotTicks is the number of evolution ticks between
+	// metrics snapshots written to the database.
+	MetricsSnapshotTicks int `json:"metrics_snapshot_ticks" env:"RPG_METRICS_SNAPSHOT_TICKS"`
+}
+
+// AgentConfig controls the bot roster spawned at startup.
+type AgentConfig struct {
+	SpawnDefaults bool        `json:"spawn_defaults" env:"RPG_SPAWN_DEFAULT_AGENTS"`
+	Roster        []AgentSpec `json:"roster"` // empty = built-in roster
+}
+
+// AgentSpec mirrors agent.CreateAgentRequest so the roster can be configured
+// without the config package depending on the agent package.
+type AgentSpec struct {
+	Name     string `json:"name"`
+	Strategy string `json:"strategy"`
+	MinDelay int    `json:"min_delay_ms"`
+	MaxDelay int    `json:"max_delay_ms"`
+}
+
+// CalendarConfig defines the game calendar epoch and speed.
+type CalendarConfig struct {
+	Epoch                  string `json:"epoch" env:"RPG_CALENDAR_EPOCH"` // RFC 3339
+	RealSecondsPerGameHour int    `json:"real_seconds_per_game_hour" env:"RPG_REAL_SECONDS_PER_GAME_HOUR"`
+}
+
+// BalanceConfig holds gameplay balance values. This is the only section that
+// is applied live when the config file changes.
+type BalanceConfig struct {
+	ArenaMaxBattlesPerDay int `json:"arena_max_battles_per_day" env:"RPG_ARENA_MAX_BATTLES_PER_DAY"`
+
+	// Inn sleep cost = InnSleepBaseCost + level * InnSleepCostPerLevel.
+	InnSleepBaseCost     int `json:"inn_sleep_base_cost" env:"RPG_INN_SLEEP_BASE_COST"`
+	InnSleepCostPerLevel int `json:"inn_sleep_cost_per_level" env:"RPG_INN_SLEEP_COST_PER_LEVEL"`
+
+	TaxMin int `json:"tax_min" env:"RPG_TAX_MIN"`
+	TaxMax int `json:"tax_max" env:"RPG_TAX_MAX"`
+
+	// XP to next level = level * (XPCurveBase + level * XPCurvePerLevel).
+	XPCurveBase     int `json:"xp_curve_base" env:"RPG_XP_CURVE_BASE"`
+	XPCurvePerLevel int `json:"xp_curve_per_level" env:"RPG_XP_CURVE_PER_LEVEL"`
+
+	// Kill XP = mob level * KillXPPerMobLevel, plus KillXPBonusPerLevel for
+	// each level the mob is above the player, falling to zero once the
+	// player out-levels the mob by KillXPCutoffLevels.
+	KillXPPerMobLevel   int `json:"kill_xp_per_mob_level" env:"RPG_KILL_XP_PER_MOB_LEVEL"`
+	KillXPBonusPerLevel int `json:"kill_xp_bonus_per_level" env:"RPG_KILL_XP_BONUS_PER_LEVEL"`
+	KillXPCutoffLevels  int `json:"kill_xp_cutoff_levels" env:"RPG_KILL_XP_CUTOFF_LEVELS"`
+
+	// Crit chances are percentages (0-100).
+	PlayerCritChance  int `json:"player_crit_chance" env:"RPG_PLAYER_CRIT_CHANCE"`
+	MonsterCritChance int `json:"monster_crit_chance" env:"RPG_MONSTER_CRIT_CHANCE"`
+
+	// Attribute points granted per level, and the gold cost of a respec at
+	// the Training Hall = RespecBaseCost + level * RespecCostPerLevel.
+	AttributePointsPerLevel int `json:"attribute_points_per_level" env:"RPG_ATTRIBUTE_POINTS_PER_LEVEL"`
+	RespecBaseCost          int `json:"respec_base_cost" env:"RPG_RESPEC_BASE_COST"`
+	RespecCostPerLevel      int `json:"respec_cost_per_level" env:"RPG_RESPEC_COST_PER_LEVEL"`
+
+	// Repairs cost RepairGoldPerPoint gold per missing durability point,
+	// scaled by 1 + rarity/3, plus one beast material per
+	// RepairPointsPerMaterial points.
+	RepairGoldPerPoint      int `json:"repair_gold_per_point" env:"RPG_REPAIR_GOLD_PER_POINT"`
+	RepairPointsPerMaterial int `json:"repair_points_per_material" env:"RPG_REPAIR_POINTS_PER_MATERIAL"`
+
+	// Each inventory stack or piece of gear takes one slot. A village stash
+	// holds StashBaseSlots + village level * StashSlotsPerVillageLevel.
+	InventorySlots            int `json:"inventory_slots" env:"RPG_INVENTORY_SLOTS"`
+	StashBaseSlots            int `json:"stash_base_slots" env:"RPG_STASH_BASE_SLOTS"`
+	StashSlotsPerVillageLevel int `json:"stash_slots_per_village_level" env:"RPG_STASH_SLOTS_PER_VILLAGE_LEVEL"`
+
+	// An enchant succeeds EnchantSuccessChance% of the time, less
+	// EnchantChancePerSocket for every socket the item already has filled.
+	EnchantSuccessChance   int `json:"enchant_success_chance" env:"RPG_ENCHANT_SUCCESS_CHANCE"`
+	EnchantChancePerSocket int `json:"enchant_chance_per_socket" env:"RPG_ENCHANT_CHANCE_PER_SOCKET"`
+
+	// Cancelling a village job refunds JobRefundPercent of its cost, less
+	// the share of the work already done.
+	JobRefundPercent int `json:"job_refund_percent" env:"RPG_JOB_REFUND_PERCENT"`
+}
+
+// AntiCheatConfig holds the thresholds for heuristic bot detection on human
+// (WebSocket) accounts. Changes require a restart.
+type AntiCheatConfig struct {
+	Enabled bool `json:"enabled" env:"RPG_ANTICHEAT_ENABLED"`
+
+	// Timing: once TimingSamples gaps between commands are collected, flag
+	// when their coefficient of variation (stddev / mean) is below
+	// MinTimingCVPercent percent. Humans are far noisier than a sleep loop.
+	TimingSamples      int `json:"timing_samples" env:"RPG_ANTICHEAT_TIMING_SAMPLES"`
+	MinTimingCVPercent int `json:"min_timing_cv_percent" env:"RPG_ANTICHEAT_MIN_TIMING_CV_PERCENT"`
+
+	// MaxActiveHours flags accounts that sent commands in more than this many
+	// distinct hours of the trailing 24.
+	MaxActiveHours int `json:"max_active_hours" env:"RPG_ANTICHEAT_MAX_ACTIVE_HOURS"`
+
+	// Rates are measured over at least RateWindowMinutes of play.
+	RateWindowMinutes int `json:"rate_window_minutes" env:"RPG_ANTICHEAT_RATE_WINDOW_MINUTES"`
+	MaxXPPerHour      int `json:"max_xp_per_hour" env:"RPG_ANTICHEAT_MAX_XP_PER_HOUR"`
+	MaxGoldPerHour    int `json:"max_gold_per_hour" env:"RPG_ANTICHEAT_MAX_GOLD_PER_HOUR"`
+
+	// Flag when the same SequenceLength-command sequence repeats
+	// SequenceRepeats times back to back.
+	SequenceLength  int `json:"sequence_length" env:"RPG_ANTICHEAT_SEQUENCE_LENGTH"`
+	SequenceRepeats int `json:"sequence_repeats" env:"RPG_ANTICHEAT_SEQUENCE_REPEATS"`
+}
+
+// Default returns the built-in configuration, matching the values the game
+// shipped with before they were configurable.
+func Default() *Config {
+	cfg := &Config{
+		Server: ServerConfig{
+			Addr:                  ":8080",
+			DBPath:                "game.db",
+			Secret:                "change-me-in-production",
+			StaticDir:             "web/static",
+			MaxAgents:             20,
+			ReloadIntervalSeconds: 30,
+		},
+		Tickers: TickerConfig{
+			EvolutionSeconds:      60,
+			AutoTideSeconds:       60,
+			VillageManagerSeconds: 60,
+			TideLeaderSeconds:     60,
+			HarvestSeconds:        15,
+			VillageJobSeconds:     15,
+			PresenceSeconds:       15,
+			MetricsSnapshotTicks:  60,
+		},
+		Agents: AgentConfig{
+			SpawnDefaults: true,
+		},
+		Calendar: CalendarConfig{
+			Epoch:                  "2024-01-01T00:00:00Z",
+			RealSecondsPerGameHour: 60,
+		},
+		Balance: BalanceConfig{
+			ArenaMaxBattlesPerDay: 5,
+			InnSleepBaseCost:      10,
+			InnSleepCostPerLevel:  5,
+			TaxMin:                0,
+			TaxMax:                50,
+			XPCurveBase:           300,
+			XPCurvePerLevel:       10,
+			KillXPPerMobLevel:     10,
+			KillXPBonusPerLevel:   5,
+			KillXPCutoffLevels:    10,
+			PlayerCritChance:      15,
+			MonsterCritChance:     10,
+
+			AttributePointsPerLevel: 3,
+			RespecBaseCost:          50,
+			RespecCostPerLevel:      10,
+
+			RepairGoldPerPoint:      1,
+			RepairPointsPerMaterial: 20,
+
+			InventorySlots:            40,
+			StashBaseSlots:            20,
+			StashSlotsPerVillageLevel: 5,
+
+			EnchantSuccessChance:   85,
+			EnchantChancePerSocket: 15,
+
+			JobRefundPercent: 50,
+		},
+		AntiCheat: AntiCheatConfig{
+			Enabled:            true,
+			TimingSamples:      60,
+			MinTimingCVPercent: 8,
+			MaxActiveHours:     20,
+			RateWindowMinutes:  15,
+			MaxXPPerHour:       50000,
+			MaxGoldPerHour:     20000,
+			SequenceLength:     6,
+			SequenceRepeats:    15,
+		},
+	}
+	cfg.epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return cfg
+}
+
+// Load builds a config from defaults, the JSON file at path (skipped when
+// path is empty), and environment overrides. The result is not validated.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %q: %w", path, err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %q: %w", path, err)
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that every value is in range and caches derived values.
+func (c *Config) Validate() error {
+	if c.Server.Addr == "" {
+		return fmt.Errorf("server.addr is required")
+	}
+	if c.Server.DBPath == "" {
+		return fmt.Errorf("server.db is required")
+	}
+	if c.Server.Secret == "" {
+		return fmt.Errorf("server.secret is required")
+	}
+	if c.Server.MaxAgents < 0 {
+		return fmt.Errorf("server.max_agents must be >= 0, got %d", c.Server.MaxAgents)
+	}
+	if c.Server.ReloadIntervalSeconds < 0 {
+		return fmt.Errorf("server.reload_interval_seconds must be >= 0, got %d", c.Server.ReloadIntervalSeconds)
+	}
+
+	tickers := map[string]int{
+		"tickers.evolution_seconds":       c.Tickers.EvolutionSeconds,
+		"tickers.auto_tide_seconds":       c.Tickers.AutoTideSeconds,
+		"tickers.village_manager_seconds": c.Tickers.VillageManagerSeconds,
+		"tickers.tide_leader_seconds":     c.Tickers.TideLeaderSeconds,
+		"tickers.harvest_seconds":         c.Tickers.HarvestSeconds,
+		"tickers.village_job_seconds":     c.Tickers.VillageJobSeconds,
+		"tickers.presence_seconds":        c.Tickers.PresenceSeconds,
+		"tickers.metrics_snapshot_ticks":  c.Tickers.MetricsSnapshotTicks,
+	}
+	for name, v := range tickers {
+		if v < 1 {
+			return fmt.Errorf("%s must be >= 1, got %d", name, v)
+		}
+	}
+
+	for i, a := range c.Agents.Roster {
+		if a.Name == "" || a.Strategy == "" {
+			return fmt.Errorf("agents.roster[%d] needs a name and strategy", i)
+		}
+		if a.MinDelay < 0 || a.MaxDelay < a.MinDelay {
+			return fmt.Errorf("agents.roster[%d] has invalid delays %d-%d", i, a.MinDelay, a.MaxDelay)
+		}
+	}
+
+	epoch, err := time.Parse(time.RFC3339, c.Calendar.Epoch)
+	if err != nil {
+		return fmt.Errorf("calendar.epoch must be RFC 3339: %w", err)
+	}
+	if c.Calendar.RealSecondsPerGameHour < 1 {
+		return fmt.Errorf("calendar.real_seconds_per_game_hour must be >= 1, got %d", c.Calendar.RealSecondsPerGameHour)
+	}
+
+	if err := c.Balance.Validate(); err != nil {
+		return err
+	}
+
+	ac := c.AntiCheat
+	if ac.TimingSamples < 2 || ac.MinTimingCVPercent < 0 {
+		return fmt.Errorf("anticheat.timing_samples must be >= 2 and min_timing_cv_percent >= 0")
+	}
+	if ac.MaxActiveHours < 1 || ac.MaxActiveHours > 24 {
+		return fmt.Errorf("anticheat.max_active_hours must be between 1 and 24, got %d", ac.MaxActiveHours)
+	}
+	if ac.RateWindowMinutes < 1 || ac.MaxXPPerHour < 1 || ac.MaxGoldPerHour < 1 {
+		return fmt.Errorf("anticheat rate window and limits must be >= 1")
+	}
+	if ac.SequenceLength < 2 || ac.SequenceRepeats < 2 {
+		return fmt.Errorf("anticheat.sequence_length and sequence_repeats must be >= 2")
+	}
+
+	c.epoch = epoch
+	return nil
+}
+
+// Validate checks the balance section on its own so reloads can reject bad
+// values without touching the rest of the running config.
+func (b BalanceConfig) Validate() error {
+	if b.ArenaMaxBattlesPerDay < 0 {
+		return fmt.Errorf("balance.arena_max_battles_per_day must be >= 0, got %d", b.ArenaMaxBattlesPerDay)
+	}
+	if b.InnSleepBaseCost < 0 || b.InnSleepCostPerLevel < 0 {
+		return fmt.Errorf("balance inn sleep costs must be >= 0")
+	}
+	if b.TaxMin < 0 || b.TaxMax > 100 || b.TaxMin > b.TaxMax {
+		return fmt.Errorf("balance tax bounds must satisfy 0 <= tax_min <= tax_max <= 100, got %d-%d", b.TaxMin, b.TaxMax)
+	}
+	if b.XPCurveBase < 1 || b.XPCurvePerLevel < 0 {
+		return fmt.Errorf("balance.xp_curve_base must be >= 1 and xp_curve_per_level >= 0")
+	}
+	if b.KillXPPerMobLevel < 0 || b.KillXPBonusPerLevel < 0 || b.KillXPCutoffLevels < 1 {
+		return fmt.Errorf("balance kill XP values must be >= 0 and kill_xp_cutoff_levels >= 1")
+	}
+	if b.PlayerCritChance < 0 || b.PlayerCritChance > 100 || b.MonsterCritChance < 0 || b.MonsterCritChance > 100 {
+		return fmt.Errorf("balance crit chances must be between 0 and 100")
+	}
+	if b.AttributePointsPerLevel < 0 || b.RespecBaseCost < 0 || b.RespecCostPerLevel < 0 {
+		return fmt.Errorf("balance attribute points and respec costs must be >= 0")
+	}
+	if b.RepairGoldPerPoint < 0 || b.RepairPointsPerMaterial < 1 {
+		return fmt.Errorf("balance.repair_gold_per_point must be >= 0 and repair_points_per_material >= 1")
+	}
+	if b.InventorySlots < 1 || b.StashBaseSlots < 0 || b.StashSlotsPerVillageLevel < 0 {
+		return fmt.Errorf("balance.inventory_slots must be >= 1 and stash slots >= 0")
+	}
+	if b.EnchantSuccessChance < 1 || b.EnchantSuccessChance > 100 || b.EnchantChancePerSocket < 0 {
+		return fmt.Errorf("balance.enchant_success_chance must be between 1 and 100 and enchant_chance_per_socket >= 0")
+	}
+	if b.JobRefundPercent < 0 || b.JobRefundPercent > 100 {
+		return fmt.Errorf("balance.job_refund_percent must be between 0 and 100, got %d", b.JobRefundPercent)
+	}
+	return nil
+}
+
+// Epoch returns the parsed calendar epoch.
+func (c *Config) Epoch() time.Time {
+	return c.epoch
+}
+
+// IsAdmin reports whether the username may use admin endpoints.
+func (c *Config) IsAdmin(username string) bool {
+	for _, u := range c.Server.AdminUsers {
+		if strings.EqualFold(u, username) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy that is safe to expose over the admin API.
+func (c *Config) Redacted() Config {
+	cp := *c
+	cp.Server.Secret = "[redacted]"
+	if cp.Server.MetricsToken != "" {
+		cp.Server.MetricsToken = "[redacted]"
+	}
+	return cp
+}
+
+// ---------------------------------------------------------------------------
+// Process-wide current config
+// ---------------------------------------------------------------------------
+
+var current atomic.Pointer[Config]
+
+func init() {
+	current.Store(Default())
+}
+
+// Current returns the active config. It is never nil.
+func Current() *Config {
+	return current.Load()
+}
+
+// Set installs cfg as the active config.
+func Set(cfg *Config) {
+	current.Store(cfg)
+}
+
+// ApplyBalance swaps in a new balance section, keeping everything else from
+// the running config. Returns an error if the balance values are invalid.
+func ApplyBalance(b BalanceConfig) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	next := *Current()
+	next.Balance = b
+	Set(&next)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Environment overrides
+// ---------------------------------------------------------------------------
+
+// applyEnv walks the struct and overrides any field whose env tag names a
+// set environment variable. String slices are comma-separated.
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(fv); err != nil {
+				return err
+			}
+			continue
+		}
+		key := field.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(raw)
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("invalid %s=%q: %w", key, raw, err)
+			}
+			fv.SetInt(int64(n))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("invalid %s=%q: %w", key, raw, err)
+			}
+			fv.SetBool(b)
+		case reflect.Slice:
+			var items []string
+			for _, s := range strings.Split(raw, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					items = append(items, s)
+				}
+			}
+			fv.Set(reflect.ValueOf(items))
+		}
+	}
+	return nil
+}
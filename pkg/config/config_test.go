@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	if cfg.Balance.ArenaMaxBattlesPerDay != 5 {
+		t.Errorf("ArenaMaxBattlesPerDay = %d, want 5", cfg.Balance.ArenaMaxBattlesPerDay)
+	}
+	if cfg.Epoch().Year() != 2024 {
+		t.Errorf("Epoch year = %d, want 2024", cfg.Epoch().Year())
+	}
+}
+
+func TestLoadFileOverridesDefaults(t *testing.T) {
+	path := writeConfig(t, `{"server":{"addr":":9000"},"balance":{"player_crit_chance":25}}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Server.Addr != ":9000" {
+		t.Errorf("Addr = %q, want :9000", cfg.Server.Addr)
+	}
+	if cfg.Balance.PlayerCritChance != 25 {
+		t.Errorf("PlayerCritChance = %d, want 25", cfg.Balance.PlayerCritChance)
+	}
+	// Unset values keep their defaults.
+	if cfg.Balance.MonsterCritChance != 10 {
+		t.Errorf("MonsterCritChance = %d, want 10", cfg.Balance.MonsterCritChance)
+	}
+}
+
+func TestEnvOverridesFile(t *testing.T) {
+	path := writeConfig(t, `{"balance":{"tax_max":40}}`)
+	t.Setenv("RPG_TAX_MAX", "30")
+	t.Setenv("RPG_ADMIN_USERS", "alice, bob")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Balance.TaxMax != 30 {
+		t.Errorf("TaxMax = %d, want 30", cfg.Balance.TaxMax)
+	}
+	if !cfg.IsAdmin("Bob") || cfg.IsAdmin("mallory") {
+		t.Errorf("AdminUsers = %v, want [alice bob]", cfg.Server.AdminUsers)
+	}
+}
+
+func TestEnvInvalidNumber(t *testing.T) {
+	t.Setenv("RPG_MAX_AGENTS", "lots")
+	if _, err := Load(""); err == nil {
+		t.Fatal("expected error for non-numeric RPG_MAX_AGENTS")
+	}
+}
+
+func TestValidateRejectsBadValues(t *testing.T) {
+	cases := map[string]func(*Config){
+		"tax bounds":       func(c *Config) { c.Balance.TaxMin = 60 },
+		"crit chance":      func(c *Config) { c.Balance.PlayerCritChance = 101 },
+		"respec cost":      func(c *Config) { c.Balance.RespecBaseCost = -1 },
+		"repair materials": func(c *Config) { c.Balance.RepairPointsPerMaterial = 0 },
+		"inventory slots":  func(c *Config) { c.Balance.InventorySlots = 0 },
+		"enchant chance":   func(c *Config) { c.Balance.EnchantSuccessChance = 101 },
+		"job refund":       func(c *Config) { c.Balance.JobRefundPercent = 150 },
+		"ticker":           func(c *Config) { c.Tickers.AutoTideSeconds = 0 },
+		"epoch":            func(c *Config) { c.Calendar.Epoch = "yesterday" },
+		"anticheat":        func(c *Config) { c.AntiCheat.MaxActiveHours = 25 },
+		"agent delays": func(c *Config) {
+			c.Agents.Roster = []AgentSpec{{Name: "x", Strategy: "hunter", MinDelay: 10, MaxDelay: 5}}
+		},
+	}
+	for name, mutate := range cases {
+		cfg := Default()
+		mutate(cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestReloadAppliesOnlyBalance(t *testing.T) {
+	orig := Current()
+	t.Cleanup(func() { Set(orig) })
+
+	running := Default()
+	running.Server.Addr = ":7000"
+	Set(running)
+
+	path := writeConfig(t, `{"server":{"addr":":9999"},"balance":{"arena_max_battles_per_day":8}}`)
+	if err := Reload(path); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := Current().Balance.ArenaMaxBattlesPerDay; got != 8 {
+		t.Errorf("ArenaMaxBattlesPerDay = %d, want 8", got)
+	}
+	if got := Current().Server.Addr; got != ":7000" {
+		t.Errorf("Addr = %q, want :7000 (server section is not live-reloadable)", got)
+	}
+
+	bad := writeConfig(t, `{"balance":{"tax_min":90,"tax_max":10}}`)
+	if err := Reload(bad); err == nil {
+		t.Fatal("expected invalid balance to be rejected")
+	}
+	if got := Current().Balance.ArenaMaxBattlesPerDay; got != 8 {
+		t.Errorf("rejected reload changed config: ArenaMaxBattlesPerDay = %d", got)
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Reload re-reads the config file and applies its balance section to the
+// running config. Other sections are ignored until the next restart.
+func Reload(path string) error {
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+	return ApplyBalance(cfg.Balance)
+}
+
+// Watch polls the config file every interval and reloads balance values when
+// its modification time changes. It runs until stop is closed.
+func Watch(path string, interval time.Duration, stop <-chan struct{}) {
+	lastMod := modTime(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			mod := modTime(path)
+			if mod.IsZero() || mod.Equal(lastMod) {
+				continue
+			}
+			lastMod = mod
+			if err := Reload(path); err != nil {
+				log.Printf("[Config] Reload of %s rejected: %v", path, err)
+				continue
+			}
+			log.Printf("[Config] Reloaded balance values from %s", path)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
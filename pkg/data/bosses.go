@@ -0,0 +1,99 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// BossDefinitions scripts every boss encounter, keyed by models.Monster.BossID.
+var BossDefinitions = map[string]models.BossDefinition{
+	// Dungeon bosses on floors 5 and 10
+	"dungeon_warlord": {
+		ID: "dungeon_warlord",
+		Phases: []models.BossPhase{
+			{Name: "Onslaught", BelowPct: 100, Narrative: "{name} blocks the way, weapon raised.",
+				Telegraph: models.BossTelegraph{Name: "Crushing Blow", Every: 4, DamagePct: 200, DefendPct: 25,
+					Warning: "{name} raises its weapon high for a Crushing Blow! Brace yourself!",
+					Effect:  models.StatusEffect{Type: "stun", Duration: 1}}},
+			{Name: "War Band", BelowPct: 60, Narrative: "{name} roars and its war band answers the call!",
+				AttackBonus: 3, Summon: "hell hound", SummonCount: 2},
+			{Name: "Last Stand", BelowPct: 25, Narrative: "{name} is badly wounded and fights with desperate fury!",
+				AttackBonus: 6, DefenseBonus: -3,
+				Telegraph: models.BossTelegraph{Name: "Crushing Blow", Every: 3, DamagePct: 250, DefendPct: 25,
+					Warning: "{name} raises its weapon high for a Crushing Blow! Brace yourself!",
+					Effect:  models.StatusEffect{Type: "stun", Duration: 1}}},
+		},
+		EnrageTurn: 20, EnrageAttack: 10,
+		Loot: []models.BossDrop{
+			{Unique: "Warlord's Cleaver", Rarity: 6, Chance: 25},
+			{Unique: "Bloodthirst", Rarity: 6, Chance: 10},
+		},
+	},
+	// Dungeon bosses from floor 15 down
+	"abyssal_overlord": {
+		ID: "abyssal_overlord",
+		Phases: []models.BossPhase{
+			{Name: "Dominion", BelowPct: 100, Narrative: "The air burns around {name}.",
+				Telegraph: models.BossTelegraph{Name: "Hellfire", Every: 4, DamagePct: 200, DamageType: models.Fire, DefendPct: 30,
+					Warning: "{name} gathers a storm of Hellfire! Take cover!",
+					Effect:  models.StatusEffect{Type: "burn", Duration: 3, Potency: 6}}},
+			{Name: "Legion", BelowPct: 70, Narrative: "{name} tears open a rift and demons pour through!",
+				AttackBonus: 4, Summon: "imp", SummonCount: 3},
+			{Name: "Unmade", BelowPct: 30, Narrative: "{name} sheds its mortal shape!",
+				AttackBonus: 8, DefenseBonus: 4,
+				Telegraph: models.BossTelegraph{Name: "Oblivion", Every: 3, DamagePct: 300, DamageType: models.Fire, DefendPct: 20,
+					Warning: "{name} draws the light out of the room for Oblivion! Take cover!"}},
+		},
+		EnrageTurn: 18, EnrageAttack: 15,
+		Loot: []models.BossDrop{
+			{Unique: "Crown of the Abyss", Rarity: 7, Chance: 25},
+			{Unique: "Emberheart", Rarity: 7, Chance: 15},
+		},
+	},
+	// Location guardians
+	"location_guardian": {
+		ID: "location_guardian",
+		Phases: []models.BossPhase{
+			{Name: "Vigil", BelowPct: 100, Narrative: "{name} rises to defend its land."},
+			{Name: "Awakening", BelowPct: 50, Narrative: "The ground shakes as {name} calls on the land itself!",
+				DefenseBonus: 5, Summon: "stone sentinel", SummonCount: 2,
+				Telegraph: models.BossTelegraph{Name: "Earthshatter", Every: 4, DamagePct: 220, DefendPct: 25,
+					Warning: "{name} lifts a foot to bring down an Earthshatter! Brace yourself!"}},
+		},
+		EnrageTurn: 25, EnrageAttack: 8,
+		Loot: []models.BossDrop{
+			{Unique: "Guardian's Bulwark", Rarity: 6, Chance: 30},
+			{Unique: "Frostward Aegis", Rarity: 6, Chance: 10},
+		},
+	},
+	// Skill guardians
+	"skill_guardian": {
+		ID: "skill_guardian",
+		Phases: []models.BossPhase{
+			{Name: "Trial", BelowPct: 100, Narrative: "{name} tests whether you are worthy."},
+			{Name: "Arcane Surge", BelowPct: 50, Narrative: "{name} channels the skill it guards!",
+				AttackBonus: 2,
+				Telegraph: models.BossTelegraph{Name: "Arcane Nova", Every: 3, DamagePct: 180, DamageType: models.Lightning, DefendPct: 30,
+					Warning: "{name} crackles with gathering power for an Arcane Nova! Brace yourself!",
+					Effect:  models.StatusEffect{Type: "silence", Duration: 2}}},
+		},
+		EnrageTurn: 15, EnrageAttack: 6,
+		Loot: []models.BossDrop{
+			{Unique: "Scholar's Sigil", Rarity: 5, Chance: 20},
+		},
+	},
+	// The Tide Leader fought by every village's raids
+	"tide_leader": {
+		ID: "tide_leader",
+		Phases: []models.BossPhase{
+			{Name: "Rising Tide", BelowPct: 100, Narrative: "{name} rises from the deep."},
+			{Name: "Undertow", BelowPct: 66, Narrative: "{name} drags the sea itself against the villages!", AttackBonus: 5},
+			{Name: "Maelstrom", BelowPct: 33, Narrative: "{name} whips the waters into a raging Maelstrom!", AttackBonus: 10},
+		},
+		Loot: []models.BossDrop{
+			{Unique: "Tidebreaker", Rarity: 7, Chance: 20},
+			{Unique: "Crown of the Tide King", Rarity: 7, Chance: 10},
+		},
+	},
+}
+
+// DeepDungeonBossFloor is the first dungeon floor whose boss is an
+// abyssal_overlord rather than a dungeon_warlord.
+const DeepDungeonBossFloor = 15
@@ -0,0 +1,32 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// ElementalReactions are set off when elemental damage hits a target under
+// one of their trigger statuses. A hit can set off several at once.
+var ElementalReactions = []models.ElementalReaction{
+	{Name: "Melt", Element: models.Fire, Trigger: []string{"freeze"}, Consumes: true, BonusPct: 100, Category: "melt",
+		Description: "The fire melts the ice for double damage"},
+	{Name: "Steam", Element: models.Ice, Trigger: []string{"burn"}, Consumes: true, Category: "steam",
+		Effect:      models.StatusEffect{Type: "wet", Duration: 3},
+		Description: "The ice puts out the flames in a cloud of steam, soaking the target"},
+	{Name: "Chain Lightning", Element: models.Lightning, Trigger: []string{"wet", "freeze"}, BonusPct: 50, Category: "chain",
+		Description: "The lightning chains through water and ice for +50% damage"},
+	{Name: "Toxic Fumes", Element: models.Fire, Trigger: []string{"poison"}, Category: "fumes",
+		Effect:      models.StatusEffect{Type: "toxic_fumes", Duration: 3, Potency: 5},
+		Description: "The fire boils the poison into choking fumes"},
+	{Name: "Toxic Fumes", Element: models.Poison, Trigger: []string{"burn"}, Category: "fumes",
+		Effect:      models.StatusEffect{Type: "toxic_fumes", Duration: 3, Potency: 5},
+		Description: "The poison catches light and gives off choking fumes"},
+}
+
+// LocationElements scales elemental damage by where the fight happens.
+var LocationElements = map[string]map[models.DamageType]float64{
+	"Lake":            {models.Ice: 1.25, models.Lightning: 1.25, models.Fire: 0.8},
+	"Lake Ruins":      {models.Ice: 1.25, models.Lightning: 1.25, models.Fire: 0.8},
+	"Forest":          {models.Fire: 1.2, models.Poison: 1.2},
+	"Forest Ruins":    {models.Fire: 1.2, models.Poison: 1.2},
+	"Hills":           {models.Lightning: 1.2},
+	"Ancient Dungeon": {models.Poison: 1.25, models.Fire: 0.9},
+	"The Tower":       {models.Lightning: 1.25, models.Ice: 1.1},
+}
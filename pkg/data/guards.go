@@ -0,0 +1,22 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// GuardStances are the orders guards can be given for a fight. The first is
+// the default.
+var GuardStances = []models.GuardStance{
+	{ID: "defend", Name: "Defend Me", AttackPct: 100, CoverPct: 20, Target: "threat",
+		Description: "Stay close and take blows meant for you"},
+	{ID: "aggressive", Name: "Aggressive", AttackPct: 140, CoverPct: 5, Target: "threat",
+		Description: "Press the attack, hitting harder but leaving you exposed"},
+	{ID: "focus", Name: "Focus Weakest", AttackPct: 100, CoverPct: 10, Target: "weakest",
+		Description: "Pick off the weakest enemy first"},
+}
+
+// Guard combat tuning.
+const (
+	GuardMaxCoverPct   = 60 // most of a hit guards can take between them
+	GuardSkillCooldown = 3  // least turns between a guard's skill uses
+	GuardXPPerLevel    = 40 // XP a guard needs per level it has to level up
+	GuardKillXP        = 10 // bonus XP per level of a foe the guard finishes
+)
@@ -0,0 +1,23 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// GuildRanks are the ranks of a guild, highest first. The first is the
+// leader's; the last is where new members start.
+var GuildRanks = []models.GuildRank{
+	{ID: "leader", Name: "Leader", Level: 0, CanInvite: true, CanKick: true, CanPromote: true, DailyWithdraw: -1, WithdrawItems: true},
+	{ID: "officer", Name: "Officer", Level: 1, CanInvite: true, CanKick: true, CanPromote: true, DailyWithdraw: -1, WithdrawItems: true},
+	{ID: "member", Name: "Member", Level: 2, CanInvite: true, DailyWithdraw: 100},
+	{ID: "recruit", Name: "Recruit", Level: 3},
+}
+
+// Guild tuning.
+const (
+	GuildCreateCost   = 500 // gold to found a guild
+	GuildNameMinLen   = 3
+	GuildNameMaxLen   = 24
+	GuildMaxMembers   = 20
+	GuildChatSize     = 50  // lines each guild keeps
+	GuildChatMaxLen   = 200 // characters in one chat line
+	GuildBankMaxItems = 50
+)
@@ -20,8 +20,3 @@ var DiscoverableLocations = []models.Location{
 	{Name: "The Tower", Weight: 60, Type: "Ruin", LevelMax: 2000, RarityMax: 10},
 	{Name: "Godbeast Domain", Weight: 58, Type: "Ruin"},
 }
-
-var AvailableBuildings = []models.Building{
-	{Name: "Training Grounds", RequiredResourceMap: map[string]int{"Lumber": 30, "Stone": 10}, StatsMod: models.StatMod{AttackMod: 0, DefenseMod: 0, HitPointMod: 0}},
-	{Name: "Blacksmith", RequiredResourceMap: map[string]int{"Lumber": 10, "Stone": 30}, StatsMod: models.StatMod{AttackMod: 0, DefenseMod: 0, HitPointMod: 0}},
-}
@@ -0,0 +1,45 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// MonsterAIProfiles is how each MonsterCategory fights. Monsters outside
+// every category use DefaultAIProfile.
+var MonsterAIProfiles = map[string]models.AIProfile{
+	"beast":      {Name: "Predator", SkillChance: 30, FleePct: 15, Prefers: "damage"},
+	"humanoid":   {Name: "Tactician", SkillChance: 40, FleePct: 20, HealPct: 30, FocusGuards: 40, Prefers: "debuff"},
+	"undead":     {Name: "Relentless", SkillChance: 45, FocusGuards: 25, Prefers: "debuff"},
+	"elemental":  {Name: "Storm", SkillChance: 60, FinisherPct: 35, Prefers: "damage"},
+	"construct":  {Name: "Sentinel", SkillChance: 25, FocusGuards: 50, Prefers: "buff"},
+	"demon":      {Name: "Berserker", SkillChance: 45, EnragePct: 40, EnrageAttack: 4, Prefers: "damage"},
+	"dragon":     {Name: "Tyrant", SkillChance: 50, EnragePct: 30, EnrageAttack: 6, FinisherPct: 40, Prefers: "damage"},
+	"fey":        {Name: "Trickster", SkillChance: 55, FleePct: 20, HealPct: 50, Prefers: "debuff"},
+	"plant":      {Name: "Rooted", SkillChance: 35, HealPct: 40, Prefers: "debuff"},
+	"aberration": {Name: "Mind Flayer", SkillChance: 50, FocusGuards: 30, Prefers: "debuff"},
+}
+
+// DefaultAIProfile is the plain 40% skill user every monster used to be.
+var DefaultAIProfile = models.AIProfile{Name: "Brute", SkillChance: 40, Prefers: "damage"}
+
+// RarityAISkillBonus raises a monster's skill chance by its rarity. Epic and
+// rarer monsters never flee.
+var RarityAISkillBonus = map[models.MonsterRarity]int{
+	models.RarityCommon:    0,
+	models.RarityUncommon:  5,
+	models.RarityRare:      10,
+	models.RarityEpic:      15,
+	models.RarityLegendary: 20,
+	models.RarityMythic:    25,
+}
+
+// MonsterFleeChance is the % chance a monster below its flee threshold
+// gets away on a turn it tries.
+const MonsterFleeChance = 50
+
+// BossRotation is the scripted ability order bosses cycle through, one step a
+// turn. "heal" is skipped while the boss is above its heal threshold and any
+// step the boss has no ready skill for becomes a plain attack.
+var BossRotation = []string{"buff", "damage", "attack", "debuff", "finisher", "heal", "attack"}
+
+// BossAIProfile is layered over a boss's archetype profile: bosses never
+// flee and enrage at a third of their health.
+var BossAIProfile = models.AIProfile{EnragePct: 33, EnrageAttack: 5, HealPct: 35}
@@ -124,6 +124,20 @@ var MonsterCategory = map[string]string{
 	"rot grub":        "plant",
 }
 
+// CategorySpeed is each MonsterCategory's initiative. Characters start at 10.
+var CategorySpeed = map[string]int{
+	"fey":        13,
+	"beast":      12,
+	"elemental":  11,
+	"demon":      11,
+	"humanoid":   10,
+	"dragon":     10,
+	"aberration": 9,
+	"undead":     8,
+	"construct":  7,
+	"plant":      6,
+}
+
 // MonsterNames is the ordered list of all available monster types.
 var MonsterNames = []string{
 	// Beast
@@ -0,0 +1,13 @@
+package data
+
+// Village raid tuning.
+const (
+	RaidLevelBand        = 3        // villages within this many levels of yours can be scouted
+	RaidTargets          = 5        // villages offered by one scouting
+	RaidLootPctPerBreach = 5        // % of each stored resource taken per raider that breaks in
+	RaidRevengeLootPct   = 5        // extra % a revenge raid takes
+	RaidMaxLootPct       = 25       // most of a stored resource one raid can take
+	RaidShieldSeconds    = 4 * 3600 // a raided village's protection from further raids
+	RaidLogSize          = 20       // entries each village keeps
+	RaidRecoveryTime     = 3        // manager ticks a beaten raider needs to recover
+)
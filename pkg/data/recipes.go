@@ -0,0 +1,147 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// potion returns a crafted healing potion.
+func potion(name string, heal int) models.Item {
+	return models.Item{Name: name, ItemType: "consumable", Rarity: 1, Slot: -1,
+		Consumable: models.ConsumableEffect{EffectType: "heal", Value: heal}}
+}
+
+// gear is the flat stat bonus on crafted equipment.
+func gear(atk, def, hp int) models.Item {
+	return models.Item{ItemType: "equipment", StatsMod: models.StatMod{AttackMod: atk, DefenseMod: def, HitPointMod: hp}}
+}
+
+// perRarity is the stat bonus crafted equipment gains per rarity point.
+func perRarity(atk, def, hp int) models.StatMod {
+	return models.StatMod{AttackMod: atk, DefenseMod: def, HitPointMod: hp}
+}
+
+// BasicSkillUpgrade is the upgrade from the village's basic skill upgrade
+// recipe.
+var BasicSkillUpgrade = models.SkillUpgrade{UpgradeLevel: 1, DamageIncrease: 5, CostReduction: 2,
+	Description: "+5 Damage (or +5 Healing), -2 Resource Cost"}
+
+// CraftingRecipes is the crafting registry shared by the web and terminal
+// village menus. Types: potions, armor, weapons, skill_scrolls,
+// skill_upgrades and traps.
+var CraftingRecipes = []models.CraftingRecipe{
+	// Potions
+	{ID: "small_potion", Name: "Small Health Potion", Type: "potions", RequiredCrafting: "potions", RequiredLevel: 3, VillageXP: 20,
+		RequiredResources: map[string]int{"Iron": 5, "Gold": 10}, Output: potion("Small Health Potion", 15)},
+	{ID: "medium_potion", Name: "Medium Health Potion", Type: "potions", RequiredCrafting: "potions", RequiredLevel: 3, VillageXP: 20,
+		RequiredResources: map[string]int{"Iron": 10, "Gold": 20}, Output: potion("Medium Health Potion", 30)},
+	{ID: "large_potion", Name: "Large Health Potion", Type: "potions", RequiredCrafting: "potions", RequiredLevel: 3, VillageXP: 20,
+		RequiredResources: map[string]int{"Iron": 20, "Gold": 40}, Output: potion("Large Health Potion", 50)},
+	{ID: "troll_draught", Name: "Troll Blood Draught", Type: "potions", RequiredCrafting: "potions", RequiredLevel: 5, VillageXP: 40,
+		Discoverable: true, Description: "A thick brew that closes wounds almost at once",
+		RequiredResources: map[string]int{"Tough Hide": 4, "Beast Bone": 4, "Gold": 40}, Output: potion("Troll Blood Draught", 80)},
+
+	// Armor
+	{ID: "enhanced_armor", Name: "Enhanced Armor", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 40,
+		Description: "Defense focus", RequiredResources: map[string]int{"Iron": 30, "Stone": 20},
+		Output: gear(0, 0, 0), RarityMin: 3, RarityMax: 5, PerRarity: perRarity(0, 2, 1)},
+	{ID: "beast_skin_armor", Name: "Beast Skin Armor", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 50,
+		Description: "Light armor", RequiredResources: map[string]int{"Iron": 20, "Beast Skin": 15},
+		Output: gear(0, 0, 3), RarityMin: 4, RarityMax: 6, PerRarity: perRarity(0, 2, 1)},
+	{ID: "bone_plate_armor", Name: "Bone Plate Armor", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 60,
+		Description: "Heavy armor, high defense", RequiredResources: map[string]int{"Iron": 25, "Beast Bone": 12, "Stone": 15},
+		Output: gear(0, 0, 0), RarityMin: 5, RarityMax: 7, PerRarity: perRarity(0, 3, 2)},
+	{ID: "tough_hide_vest", Name: "Tough Hide Vest", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 50,
+		Description: "Medium armor", RequiredResources: map[string]int{"Tough Hide": 10, "Beast Bone": 8},
+		Output: gear(0, 2, 4), RarityMin: 4, RarityMax: 6, PerRarity: perRarity(0, 2, 1)},
+	{ID: "ore_fragment_mail", Name: "Ore Fragment Mail", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 60,
+		Description: "Magic armor", RequiredResources: map[string]int{"Ore Fragment": 20, "Iron": 15},
+		Output: gear(0, 3, 0), RarityMin: 5, RarityMax: 7, PerRarity: perRarity(0, 2, 1)},
+	{ID: "fang_studded_armor", Name: "Fang-Studded Armor", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 70,
+		Description: "Spiked armor, counter-damage", RequiredResources: map[string]int{"Sharp Fang": 15, "Beast Skin": 10, "Iron": 20},
+		Output: gear(0, 5, 0), RarityMin: 6, RarityMax: 8, PerRarity: perRarity(1, 2, 2)},
+	{ID: "claw_guard_armor", Name: "Claw Guard Armor", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 5, VillageXP: 70,
+		RequiredBuilding: "Blacksmith", Description: "Elite armor",
+		RequiredResources: map[string]int{"Monster Claw": 12, "Tough Hide": 8, "Iron": 15},
+		Output:            gear(0, 2, 3), RarityMin: 6, RarityMax: 8, PerRarity: perRarity(0, 3, 2)},
+	{ID: "wardens_hide_cloak", Name: "Warden's Hide Cloak", Type: "armor", RequiredCrafting: "armor", RequiredLevel: 6, VillageXP: 80,
+		Discoverable: true, Description: "Layered hide that turns aside claws and fangs",
+		RequiredResources: map[string]int{"Tough Hide": 15, "Beast Skin": 15, "Sharp Fang": 5},
+		Output:            gear(0, 6, 8), RarityMin: 6, RarityMax: 8, PerRarity: perRarity(0, 3, 3)},
+
+	// Weapons
+	{ID: "enhanced_weapon", Name: "Enhanced Weapon", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 50,
+		Description: "Attack focus", RequiredResources: map[string]int{"Iron": 40, "Gold": 30},
+		Output: gear(0, 0, 2), RarityMin: 4, RarityMax: 6, PerRarity: perRarity(3, 0, 0)},
+	{ID: "beast_claw_blade", Name: "Beast Claw Blade", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 60,
+		Description: "Slashing weapon", RequiredResources: map[string]int{"Iron": 25, "Monster Claw": 15, "Sharp Fang": 10},
+		Output: gear(0, 0, 0), RarityMin: 5, RarityMax: 7, PerRarity: perRarity(4, 0, 1)},
+	{ID: "bone_crusher_mace", Name: "Bone Crusher Mace", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 60,
+		Description: "Crushing weapon", RequiredResources: map[string]int{"Iron": 30, "Beast Bone": 20, "Stone": 15},
+		Output: gear(5, 0, 2), RarityMin: 5, RarityMax: 7, PerRarity: perRarity(3, 1, 1)},
+	{ID: "hide_wrapped_axe", Name: "Hide-Wrapped Axe", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 55,
+		Description: "Balanced weapon", RequiredResources: map[string]int{"Iron": 20, "Tough Hide": 12, "Lumber": 25},
+		Output: gear(3, 0, 5), RarityMin: 4, RarityMax: 6, PerRarity: perRarity(3, 0, 2)},
+	{ID: "ore_fragment_sword", Name: "Ore Fragment Sword", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 70,
+		Description: "Magical weapon", RequiredResources: map[string]int{"Iron": 35, "Ore Fragment": 25, "Gold": 20},
+		Output: gear(5, 0, 3), RarityMin: 6, RarityMax: 8, PerRarity: perRarity(4, 0, 1)},
+	{ID: "fang_spear", Name: "Fang Spear", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 65,
+		Description: "Piercing weapon", RequiredResources: map[string]int{"Sharp Fang": 18, "Beast Bone": 15, "Iron": 20},
+		Output: gear(7, 0, 0), RarityMin: 5, RarityMax: 7, PerRarity: perRarity(3, 0, 1)},
+	{ID: "composite_war_hammer", Name: "Composite War Hammer", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 7, VillageXP: 80,
+		RequiredBuilding: "Blacksmith", Description: "Elite weapon",
+		RequiredResources: map[string]int{"Beast Skin": 10, "Ore Fragment": 15, "Iron": 25, "Stone": 20},
+		Output:            gear(3, 2, 5), RarityMin: 6, RarityMax: 8, PerRarity: perRarity(5, 1, 2)},
+	{ID: "serpent_fang_dagger", Name: "Serpent Fang Dagger", Type: "weapons", RequiredCrafting: "weapons", RequiredLevel: 8, VillageXP: 90,
+		Discoverable: true, RequiredBuilding: "Blacksmith", Description: "A blade ground from the fangs of great serpents",
+		RequiredResources: map[string]int{"Sharp Fang": 25, "Monster Claw": 10, "Iron": 20},
+		Output:            gear(10, 0, 0), RarityMin: 7, RarityMax: 9, PerRarity: perRarity(5, 0, 1)},
+
+	// Skill scrolls
+	{ID: "fireball_scroll", Name: "Fireball Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 100,
+		SkillName: "Fireball", RequiredResources: map[string]int{"Ore Fragment": 15, "Sharp Fang": 10, "Gold": 30}},
+	{ID: "ice_shard_scroll", Name: "Ice Shard Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 90,
+		SkillName: "Ice Shard", RequiredResources: map[string]int{"Beast Skin": 12, "Ore Fragment": 10, "Iron": 20}},
+	{ID: "lightning_bolt_scroll", Name: "Lightning Bolt Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 120,
+		SkillName: "Lightning Bolt", RequiredResources: map[string]int{"Ore Fragment": 20, "Monster Claw": 15, "Gold": 40}},
+	{ID: "power_strike_scroll", Name: "Power Strike Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 70,
+		SkillName: "Power Strike", RequiredResources: map[string]int{"Beast Bone": 10, "Iron": 15}},
+	{ID: "poison_blade_scroll", Name: "Poison Blade Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 85,
+		SkillName: "Poison Blade", RequiredResources: map[string]int{"Beast Skin": 10, "Sharp Fang": 12, "Iron": 15}},
+	{ID: "heal_scroll", Name: "Heal Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 95,
+		SkillName: "Heal", RequiredResources: map[string]int{"Beast Skin": 15, "Beast Bone": 10, "Gold": 25}},
+	{ID: "regeneration_scroll", Name: "Regeneration Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 105,
+		SkillName: "Regeneration", RequiredResources: map[string]int{"Ore Fragment": 12, "Beast Skin": 15, "Gold": 30}},
+	{ID: "shield_wall_scroll", Name: "Shield Wall Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 90,
+		SkillName: "Shield Wall", RequiredResources: map[string]int{"Tough Hide": 15, "Beast Bone": 12, "Stone": 20}},
+	{ID: "battle_cry_scroll", Name: "Battle Cry Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 85,
+		SkillName: "Battle Cry", RequiredResources: map[string]int{"Sharp Fang": 15, "Beast Bone": 10, "Iron": 20}},
+	{ID: "tracking_scroll", Name: "Tracking Scroll", Type: "skill_scrolls", RequiredCrafting: "skill_scrolls", RequiredLevel: 10, VillageXP: 60,
+		SkillName: "Tracking", RequiredResources: map[string]int{"Beast Bone": 8, "Beast Skin": 8}},
+
+	// Skill upgrades
+	{ID: "skill_upgrade", Name: "Skill Upgrade", Type: "skill_upgrades", RequiredCrafting: "skill_upgrades", RequiredLevel: 10, VillageXP: 60,
+		RequiredResources: map[string]int{"Gold": 50, "Iron": 25},
+		SkillUpgrade:      BasicSkillUpgrade},
+	{ID: "masterwork_upgrade", Name: "Masterwork Skill Upgrade", Type: "skill_upgrades", RequiredCrafting: "skill_upgrades", RequiredLevel: 12, VillageXP: 120,
+		Discoverable: true, RequiredResources: map[string]int{"Gold": 120, "Ore Fragment": 20, "Monster Claw": 10},
+		SkillUpgrade: models.SkillUpgrade{UpgradeLevel: 2, DamageIncrease: 12, CostReduction: 4, Description: "+12 Damage (or +12 Healing), -4 Resource Cost"}},
+
+	// Traps
+	{ID: "spike_trap", Name: "Spike Trap", Type: "traps", VillageXP: 35,
+		RequiredResources: map[string]int{"Iron": 10, "Beast Bone": 5},
+		Trap:              models.Trap{Name: "Spike Trap", Type: "spike", Damage: 15, Duration: 3, TriggerRate: 60}},
+	{ID: "fire_trap", Name: "Fire Trap", Type: "traps", VillageXP: 35,
+		RequiredResources: map[string]int{"Iron": 15, "Ore Fragment": 8, "Sharp Fang": 5},
+		Trap:              models.Trap{Name: "Fire Trap", Type: "fire", Damage: 25, Duration: 2, TriggerRate: 50}},
+	{ID: "ice_trap", Name: "Ice Trap", Type: "traps", VillageXP: 35,
+		RequiredResources: map[string]int{"Iron": 12, "Ore Fragment": 10, "Beast Skin": 8},
+		Trap:              models.Trap{Name: "Ice Trap", Type: "ice", Damage: 20, Duration: 3, TriggerRate: 55}},
+	{ID: "poison_trap", Name: "Poison Trap", Type: "traps", VillageXP: 35,
+		RequiredResources: map[string]int{"Beast Skin": 10, "Sharp Fang": 8, "Monster Claw": 5},
+		Trap:              models.Trap{Name: "Poison Trap", Type: "poison", Damage: 18, Duration: 4, TriggerRate: 65}},
+	{ID: "barricade_trap", Name: "Barricade Trap", Type: "traps", VillageXP: 35,
+		RequiredResources: map[string]int{"Lumber": 30, "Tough Hide": 6, "Beast Bone": 8},
+		Trap:              models.Trap{Name: "Barricade Trap", Type: "spike", Damage: 30, Duration: 2, TriggerRate: 70}},
+	{ID: "storm_snare", Name: "Storm Snare", Type: "traps", RequiredLevel: 4, VillageXP: 50,
+		Discoverable: true, Description: "Copper coils that arc through a whole pack",
+		RequiredResources: map[string]int{"Iron": 20, "Ore Fragment": 12, "Monster Claw": 6},
+		Trap:              models.Trap{Name: "Storm Snare", Type: "lightning", Damage: 35, Duration: 3, TriggerRate: 60}},
+}
@@ -0,0 +1,31 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// SiegeRoster is the monsters a tide sends against a village, in the order
+// they join a wave.
+var SiegeRoster = []models.SiegeMonster{
+	{Name: "Goblin", HPPct: 80, AttackPct: 90, Speed: 3},
+	{Name: "Orc", HPPct: 150, AttackPct: 130, Speed: 1},
+	{Name: "Kobold", HPPct: 70, AttackPct: 80, Speed: 3},
+	{Name: "Slime", HPPct: 130, AttackPct: 60, Speed: 1},
+	{Name: "Skeleton", HPPct: 100, AttackPct: 100, Speed: 2},
+	{Name: "Wolf", HPPct: 80, AttackPct: 110, Speed: 4},
+	{Name: "Bandit", HPPct: 100, AttackPct: 120, Speed: 2},
+}
+
+// SiegeTrapTiles are the lane tiles traps are laid on: a lane's first trap
+// on the first tile, its second on the next, and so on round.
+var SiegeTrapTiles = []int{10, 7, 13, 5, 16}
+
+// Siege tuning. Lane tiles count down from the spawn at SiegeLaneLength to
+// the walls at 0.
+const (
+	SiegeLanes            = 3
+	SiegeLaneLength       = 20
+	SiegeGuardPost        = 3  // tile hired guards hold
+	SiegeMaxTicks         = 60 // monsters still outside the walls then withdraw
+	SiegeWallHPPerDefense = 4  // wall HP per point of its Defense
+	SiegeMilitiaDamage    = 3  // damage a guard villager deals at the walls, plus its level
+	SiegeTrapChargeStart  = 50 // a trap fires once its charge reaches 100; each crossing adds its TriggerRate
+)
@@ -2,6 +2,8 @@ package data
 
 import "rpg-game/pkg/models"
 
+// AvailableSkills is the skill catalogue. Cooldown is how many turns a skill
+// rests after use and ChargeTurns how long it charges before going off.
 var AvailableSkills = []models.Skill{
 	{
 		Name:        "Fireball",
@@ -11,6 +13,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Fire,
 		Effect:      models.StatusEffect{Type: "burn", Duration: 3, Potency: 3},
 		Description: "Launch a fireball dealing fire damage and burning the enemy",
+		Cooldown:    2,
 	},
 	{
 		Name:        "Ice Shard",
@@ -18,8 +21,8 @@ var AvailableSkills = []models.Skill{
 		StaminaCost: 0,
 		Damage:      15,
 		DamageType:  models.Ice,
-		Effect:      models.StatusEffect{Type: "none", Duration: 0, Potency: 0},
-		Description: "Fire a shard of ice dealing cold damage",
+		Effect:      models.StatusEffect{Type: "slow", Duration: 3, Potency: 4},
+		Description: "Fire a shard of ice dealing cold damage and slowing the target",
 	},
 	{
 		Name:        "Lightning Bolt",
@@ -29,6 +32,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Lightning,
 		Effect:      models.StatusEffect{Type: "stun", Duration: 1, Potency: 1},
 		Description: "Strike with lightning, high damage with chance to stun",
+		Cooldown:    2,
 	},
 	{
 		Name:        "Heal",
@@ -38,6 +42,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Physical,
 		Effect:      models.StatusEffect{Type: "none", Duration: 0, Potency: 0},
 		Description: "Restore 20 HP",
+		Cooldown:    3,
 	},
 	{
 		Name:        "Power Strike",
@@ -47,6 +52,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Physical,
 		Effect:      models.StatusEffect{Type: "none", Duration: 0, Potency: 0},
 		Description: "Powerful physical attack using stamina",
+		Cooldown:    1,
 	},
 	{
 		Name:        "Shield Wall",
@@ -56,6 +62,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Physical,
 		Effect:      models.StatusEffect{Type: "buff_defense", Duration: 3, Potency: 10},
 		Description: "Increase defense by 10 for 3 turns",
+		Cooldown:    4,
 	},
 	{
 		Name:        "Battle Cry",
@@ -65,6 +72,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Physical,
 		Effect:      models.StatusEffect{Type: "buff_attack", Duration: 3, Potency: 5},
 		Description: "Increase attack by 5 for 3 turns",
+		Cooldown:    4,
 	},
 	{
 		Name:        "Poison Blade",
@@ -74,6 +82,7 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Poison,
 		Effect:      models.StatusEffect{Type: "poison", Duration: 4, Potency: 5},
 		Description: "Attack with poison, dealing damage over time",
+		Cooldown:    2,
 	},
 	{
 		Name:        "Regeneration",
@@ -83,6 +92,98 @@ var AvailableSkills = []models.Skill{
 		DamageType:  models.Physical,
 		Effect:      models.StatusEffect{Type: "regen", Duration: 5, Potency: 5},
 		Description: "Heal 5 HP per turn for 5 turns",
+		Cooldown:    5,
+	},
+	{
+		Name:        "Frost Nova",
+		ManaCost:    16,
+		StaminaCost: 0,
+		Damage:      12,
+		DamageType:  models.Ice,
+		Effect:      models.StatusEffect{Type: "freeze", Duration: 1, Potency: 5},
+		Description: "Freeze the enemy solid: it loses its next turn and 5 defense",
+		Cooldown:    3,
+	},
+	{
+		Name:        "Rend",
+		ManaCost:    0,
+		StaminaCost: 12,
+		Damage:      8,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "bleed", Duration: 4, Potency: 3},
+		Description: "Open a wound that bleeds each turn, stacking up to 5 times",
+		Cooldown:    1,
+	},
+	{
+		Name:        "Enfeeble",
+		ManaCost:    10,
+		StaminaCost: 0,
+		Damage:      0,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "weaken", Duration: 3, Potency: 6},
+		Description: "Reduce the enemy's attack by 6 for 3 turns",
+		Cooldown:    3,
+	},
+	{
+		Name:        "Silencing Strike",
+		ManaCost:    0,
+		StaminaCost: 14,
+		Damage:      10,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "silence", Duration: 2, Potency: 1},
+		Description: "A blow to the throat that stops the enemy using skills for 2 turns",
+		Cooldown:    3,
+	},
+	{
+		Name:        "Arcane Barrier",
+		ManaCost:    14,
+		StaminaCost: 0,
+		Damage:      0,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "shield", Duration: 4, Potency: 20},
+		Description: "Conjure a barrier that absorbs up to 20 damage for 4 turns",
+		Cooldown:    4,
+	},
+	{
+		Name:        "Purify",
+		ManaCost:    8,
+		StaminaCost: 0,
+		Damage:      0,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "cleanse"},
+		Description: "Cleanse yourself of poison, burns, bleeding and other harmful effects",
+		Cooldown:    3,
+	},
+	{
+		Name:        "Dispel Magic",
+		ManaCost:    10,
+		StaminaCost: 0,
+		Damage:      0,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "dispel"},
+		Description: "Strip the enemy's magical buffs and shields",
+		Cooldown:    2,
+	},
+	{
+		Name:        "Meteor",
+		ManaCost:    24,
+		StaminaCost: 0,
+		Damage:      40,
+		DamageType:  models.Fire,
+		Effect:      models.StatusEffect{Type: "burn", Duration: 2, Potency: 4},
+		Description: "Call down a meteor: it takes a turn to charge, then crushes the enemy with fire",
+		Cooldown:    5,
+		ChargeTurns: 1,
+	},
+	{
+		Name:        "Haste",
+		ManaCost:    12,
+		StaminaCost: 0,
+		Damage:      0,
+		DamageType:  models.Physical,
+		Effect:      models.StatusEffect{Type: "haste", Duration: 3, Potency: 5},
+		Description: "Quicken your movements, acting more often for 3 turns",
+		Cooldown:    5,
 	},
 	{
 		Name:        "Tracking",
@@ -94,3 +195,38 @@ var AvailableSkills = []models.Skill{
 		Description: "Allows you to see and choose which monster to fight at a location",
 	},
 }
+
+// SkillCombos are the chains that reward using one skill right after another.
+var SkillCombos = []models.SkillCombo{
+	{Name: "Shatter", First: "Ice Shard", Then: "Lightning Bolt", BonusPct: 100,
+		Description: "Lightning Bolt right after Ice Shard shatters the ice for double damage"},
+	{Name: "Deep Shatter", First: "Frost Nova", Then: "Lightning Bolt", RequiresStatus: "freeze", ConsumesStatus: true, BonusPct: 150,
+		Description: "Lightning Bolt right after Frost Nova shatters a frozen enemy, breaking the ice"},
+	{Name: "Hemorrhage", First: "Poison Blade", Then: "Rend", RequiresStatus: "poison", BonusPct: 50,
+		Effect:      models.StatusEffect{Type: "bleed", Duration: 4, Potency: 3},
+		Description: "Rend right after Poison Blade tears the poisoned wound open, bleeding twice as hard"},
+	{Name: "Rallying Blow", First: "Battle Cry", Then: "Power Strike", BonusPct: 50,
+		Description: "Power Strike right after Battle Cry hits half again as hard"},
+	{Name: "Cataclysm", First: "Fireball", Then: "Meteor", RequiresStatus: "burn", BonusPct: 50,
+		Description: "A Meteor charged right after Fireball lands on a burning enemy for half again the damage"},
+}
+
+// SkillRankXP is the skill experience needed for each rank; using a skill
+// earns SkillUseXP, or SkillComboXP when it finishes a combo.
+var SkillRankXP = []int{0, 5, 15, 30, 50}
+
+const (
+	SkillUseXP   = 1
+	SkillComboXP = 3
+	// SkillStudyXP is earned by learning a skill the character already knows,
+	// from a skill guardian or a crafted scroll.
+	SkillStudyXP = 10
+)
+
+// SkillRankUpgrade is the improvement a skill gains with each rank.
+var SkillRankUpgrade = models.SkillUpgrade{DamageIncrease: 3, CostReduction: 1,
+	Description: "+3 Damage (or +3 Healing), -1 Resource Cost"}
+
+// DefaultSkillCooldown is the cooldown of skills without one of their own,
+// such as the monster skill pools.
+const DefaultSkillCooldown = 2
@@ -0,0 +1,43 @@
+package data
+
+import "rpg-game/pkg/models"
+
+// StatusDefinitions is the status-effect registry shared by characters,
+// monsters and guards. Adding an effect here is enough for skills, item
+// procs and the combat log to use it.
+var StatusDefinitions = []models.StatusDefinition{
+	// Damage and healing over time
+	{Type: "poison", Name: "Poison", Tick: "damage", Stacking: "intensity", MaxStacks: 3, Dispel: "poison"},
+	{Type: "burn", Name: "Burn", Tick: "damage", Stacking: "refresh", Dispel: "magic"},
+	{Type: "bleed", Name: "Bleed", Tick: "damage", Stacking: "intensity", MaxStacks: 5, Dispel: "physical"},
+	{Type: "regen", Name: "Regeneration", Beneficial: true, Tick: "heal", Stacking: "refresh", Dispel: "magic"},
+	{Type: "toxic_fumes", Name: "Toxic Fumes", Tick: "damage", Stacking: "refresh", Dispel: "poison"},
+
+	// Stat modifiers
+	{Type: "buff_attack", Name: "Attack Up", Beneficial: true, AttackScale: 1, Stacking: "refresh", Dispel: "magic"},
+	{Type: "buff_defense", Name: "Defense Up", Beneficial: true, DefenseScale: 1, Stacking: "refresh", Dispel: "magic"},
+	{Type: "weaken", Name: "Weaken", AttackScale: -1, Stacking: "refresh", Dispel: "magic"},
+	{Type: "enrage", Name: "Enrage", Beneficial: true, AttackScale: 1, Stacking: "ignore"},
+	{Type: "haste", Name: "Haste", Beneficial: true, SpeedScale: 1, Stacking: "refresh", Dispel: "magic"},
+
+	// Control
+	{Type: "stun", Name: "Stun", SkipsTurn: true, Stacking: "ignore", Dispel: "physical"},
+	{Type: "freeze", Name: "Freeze", SkipsTurn: true, DefenseScale: -1, Stacking: "ignore", Dispel: "magic"},
+	{Type: "silence", Name: "Silence", Silences: true, Stacking: "refresh", Dispel: "magic"},
+	{Type: "slow", Name: "Slow", SpeedScale: -1, Stacking: "refresh", Dispel: "magic"},
+
+	// Elemental conditions, which set off reactions (see ElementalReactions)
+	{Type: "wet", Name: "Wet", Stacking: "refresh", Dispel: "physical"},
+
+	// Protection
+	{Type: "shield", Name: "Shield", Beneficial: true, Absorbs: true, Stacking: "refresh", Dispel: "magic"},
+}
+
+// StatusImmunities lists the status effects each MonsterCategory shrugs off.
+var StatusImmunities = map[string][]string{
+	"undead":    {"poison", "bleed", "toxic_fumes"},
+	"construct": {"poison", "bleed", "silence", "toxic_fumes"},
+	"elemental": {"poison", "bleed", "toxic_fumes"},
+	"plant":     {"bleed"},
+	"demon":     {"burn"},
+}
@@ -3,7 +3,9 @@ package db
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -20,13 +22,36 @@ type Account struct {
 
 // Store wraps a *sql.DB and provides all database operations.
 type Store struct {
-	db *sql.DB
+	db       *sql.DB
+	observer func(method string, d time.Duration)
+}
+
+// SetQueryObserver registers a callback that receives the duration of every
+// Store method call. It must be set before the store is shared across goroutines.
+func (s *Store) SetQueryObserver(fn func(method string, d time.Duration)) {
+	s.observer = fn
+}
+
+// track starts timing a Store method; call the returned func when it returns.
+func (s *Store) track(method string) func() {
+	if s.observer == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { s.observer(method, time.Since(start)) }
 }
 
 // NewStore opens the SQLite database at dbPath, configures it, creates tables,
 // and returns a ready-to-use Store.
 func NewStore(dbPath string) (*Store, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	// Writers wait up to 5s for the lock rather than failing with "database
+	// is locked" while a background ticker or agent is saving. It goes in
+	// the DSN so every pooled connection gets it.
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	db, err := sql.Open("sqlite3", dbPath+sep+"_busy_timeout=5000")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -137,6 +162,48 @@ func (s *Store) createTables() error {
 			id   INTEGER PRIMARY KEY CHECK (id = 1),
 			data TEXT NOT NULL
 		)`,
+		`CREATE TABLE IF NOT EXISTS ledger (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id INTEGER NOT NULL,
+			character_name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			asset TEXT NOT NULL,
+			delta INTEGER NOT NULL,
+			balance INTEGER NOT NULL DEFAULT 0,
+			reason TEXT NOT NULL,
+			source_ref TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_ledger_char ON ledger(account_id, character_name, asset, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_ledger_time ON ledger(created_at)`,
+		`CREATE TABLE IF NOT EXISTS account_flags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id INTEGER NOT NULL,
+			rule TEXT NOT NULL,
+			detail TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'open',
+			hits INTEGER NOT NULL DEFAULT 1,
+			reviewed_by TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, rule)
+		)`,
+		`CREATE TABLE IF NOT EXISTS guilds (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			data TEXT NOT NULL,
+			tide_damage INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS guild_members (
+			account_id INTEGER NOT NULL,
+			character_name TEXT NOT NULL,
+			guild_id INTEGER NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+			rank TEXT NOT NULL,
+			PRIMARY KEY (account_id, character_name)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_guild_members_guild ON guild_members(guild_id)`,
 	}
 
 	for _, stmt := range statements {
@@ -171,6 +238,7 @@ func (s *Store) Close() error {
 
 // CreateAccount inserts a new account and returns its ID.
 func (s *Store) CreateAccount(username, passwordHash string) (int64, error) {
+	defer s.track("CreateAccount")()
 	result, err := s.db.Exec(
 		"INSERT INTO accounts (username, password_hash) VALUES (?, ?)",
 		username, passwordHash,
@@ -188,6 +256,7 @@ func (s *Store) CreateAccount(username, passwordHash string) (int64, error) {
 // GetAccountByUsername retrieves an account by its username.
 // Returns nil and no error if the account is not found.
 func (s *Store) GetAccountByUsername(username string) (*Account, error) {
+	defer s.track("GetAccountByUsername")()
 	var acct Account
 	err := s.db.QueryRow(
 		"SELECT id, username, password_hash, created_at FROM accounts WHERE username = ?",
@@ -205,6 +274,7 @@ func (s *Store) GetAccountByUsername(username string) (*Account, error) {
 // GetAccountByID retrieves an account by its ID.
 // Returns nil and no error if the account is not found.
 func (s *Store) GetAccountByID(id int64) (*Account, error) {
+	defer s.track("GetAccountByID")()
 	var acct Account
 	err := s.db.QueryRow(
 		"SELECT id, username, password_hash, created_at FROM accounts WHERE id = ?",
@@ -226,6 +296,7 @@ func (s *Store) GetAccountByID(id int64) (*Account, error) {
 // SaveCharacter upserts a character for the given account. The character struct
 // is serialized to JSON and stored in the data column.
 func (s *Store) SaveCharacter(accountID int64, char models.Character) error {
+	defer s.track("SaveCharacter")()
 	data, err := json.Marshal(char)
 	if err != nil {
 		return fmt.Errorf("failed to marshal character: %w", err)
@@ -247,6 +318,7 @@ func (s *Store) SaveCharacter(accountID int64, char models.Character) error {
 // LoadCharacter retrieves a character by account ID and name, deserializing
 // the JSON data column back into a models.Character.
 func (s *Store) LoadCharacter(accountID int64, name string) (models.Character, error) {
+	defer s.track("LoadCharacter")()
 	var data string
 	err := s.db.QueryRow(
 		"SELECT data FROM characters WHERE account_id = ? AND name = ?",
@@ -265,6 +337,7 @@ func (s *Store) LoadCharacter(accountID int64, name string) (models.Character, e
 
 // ListCharacters returns the names of all characters belonging to an account.
 func (s *Store) ListCharacters(accountID int64) ([]string, error) {
+	defer s.track("ListCharacters")()
 	rows, err := s.db.Query(
 		"SELECT name FROM characters WHERE account_id = ? ORDER BY name",
 		accountID,
@@ -290,6 +363,7 @@ func (s *Store) ListCharacters(accountID int64) ([]string, error) {
 
 // DeleteCharacter removes a character by account ID and name.
 func (s *Store) DeleteCharacter(accountID int64, name string) error {
+	defer s.track("DeleteCharacter")()
 	result, err := s.db.Exec(
 		"DELETE FROM characters WHERE account_id = ? AND name = ?",
 		accountID, name,
@@ -307,9 +381,26 @@ func (s *Store) DeleteCharacter(accountID int64, name string) error {
 	return nil
 }
 
+// FindCharacterAccounts returns the IDs of the accounts with a character of
+// the given name.
+func (s *Store) FindCharacterAccounts(name string) ([]int64, error) {
+	defer s.track("FindCharacterAccounts")()
+	var ids []int64
+	err := s.scanAll("character accounts", func(rows *sql.Rows) error {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		return nil
+	}, "SELECT account_id FROM characters WHERE name = ? ORDER BY account_id", name)
+	return ids, err
+}
+
 // GetCharacterID returns the database row ID for a character identified by
 // account ID and character name.
 func (s *Store) GetCharacterID(accountID int64, name string) (int64, error) {
+	defer s.track("GetCharacterID")()
 	var id int64
 	err := s.db.QueryRow(
 		"SELECT id FROM characters WHERE account_id = ? AND name = ?",
@@ -328,6 +419,7 @@ func (s *Store) GetCharacterID(accountID int64, name string) (int64, error) {
 // SaveLocations persists a map of locations. Each location is individually
 // upserted with its name as the primary key and data as a JSON blob.
 func (s *Store) SaveLocations(locations map[string]models.Location) error {
+	defer s.track("SaveLocations")()
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -356,6 +448,7 @@ func (s *Store) SaveLocations(locations map[string]models.Location) error {
 // LoadLocations retrieves all locations from the database and returns them
 // as a map keyed by location name.
 func (s *Store) LoadLocations() (map[string]models.Location, error) {
+	defer s.track("LoadLocations")()
 	rows, err := s.db.Query("SELECT name, data FROM game_locations")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query locations: %w", err)
@@ -387,6 +480,7 @@ func (s *Store) LoadLocations() (map[string]models.Location, error) {
 // SaveVillage persists a village associated with a character row ID.
 // The village struct is serialized to JSON.
 func (s *Store) SaveVillage(characterID int64, village models.Village) error {
+	defer s.track("SaveVillage")()
 	data, err := json.Marshal(village)
 	if err != nil {
 		return fmt.Errorf("failed to marshal village: %w", err)
@@ -410,6 +504,7 @@ func (s *Store) SaveVillage(characterID int64, village models.Village) error {
 // After deduplication it ensures the unique index exists so that future
 // SaveVillage calls can use ON CONFLICT(character_id, name).
 func (s *Store) DeduplicateVillages() (int64, error) {
+	defer s.track("DeduplicateVillages")()
 	res, err := s.db.Exec(
 		`DELETE FROM villages WHERE id NOT IN (
 			SELECT MAX(id) FROM villages GROUP BY character_id, name
@@ -432,6 +527,7 @@ func (s *Store) DeduplicateVillages() (int64, error) {
 
 // LoadVillage retrieves a village by character row ID and village name.
 func (s *Store) LoadVillage(characterID int64, villageName string) (models.Village, error) {
+	defer s.track("LoadVillage")()
 	var data string
 	err := s.db.QueryRow(
 		"SELECT data FROM villages WHERE character_id = ? AND name = ?",
@@ -458,6 +554,7 @@ type VillageWithOwner struct {
 
 // LoadAllVillages retrieves all villages with their owning character/account info.
 func (s *Store) LoadAllVillages() ([]VillageWithOwner, error) {
+	defer s.track("LoadAllVillages")()
 	rows, err := s.db.Query(
 		`SELECT v.character_id, c.account_id, c.name, v.data
 		 FROM villages v JOIN characters c ON v.character_id = c.id`,
@@ -488,6 +585,7 @@ func (s *Store) LoadAllVillages() ([]VillageWithOwner, error) {
 // LoadVillageByCharName retrieves a village by joining the characters and
 // villages tables using account ID, character name, and village name.
 func (s *Store) LoadVillageByCharName(accountID int64, charName string, villageName string) (models.Village, error) {
+	defer s.track("LoadVillageByCharName")()
 	var data string
 	err := s.db.QueryRow(
 		`SELECT v.data FROM villages v
@@ -513,6 +611,7 @@ func (s *Store) LoadVillageByCharName(accountID int64, charName string, villageN
 // SaveQuests persists a map of quests. Each quest is individually upserted
 // with its ID as the primary key and data as a JSON blob.
 func (s *Store) SaveQuests(quests map[string]models.Quest) error {
+	defer s.track("SaveQuests")()
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -541,6 +640,7 @@ func (s *Store) SaveQuests(quests map[string]models.Quest) error {
 // LoadQuests retrieves all quests from the database and returns them as a map
 // keyed by quest ID.
 func (s *Store) LoadQuests() (map[string]models.Quest, error) {
+	defer s.track("LoadQuests")()
 	rows, err := s.db.Query("SELECT id, data FROM quests")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query quests: %w", err)
@@ -571,6 +671,7 @@ func (s *Store) LoadQuests() (map[string]models.Quest, error) {
 
 // SaveTown persists a town record. The town struct is serialized to JSON.
 func (s *Store) SaveTown(town models.Town) error {
+	defer s.track("SaveTown")()
 	data, err := json.Marshal(town)
 	if err != nil {
 		return fmt.Errorf("failed to marshal town: %w", err)
@@ -589,6 +690,7 @@ func (s *Store) SaveTown(town models.Town) error {
 // LoadTown retrieves a town by name.
 // Returns the town and a nil error, or an empty town and an error if not found.
 func (s *Store) LoadTown(name string) (models.Town, error) {
+	defer s.track("LoadTown")()
 	var data string
 	err := s.db.QueryRow(
 		"SELECT data FROM towns WHERE name = ?",
@@ -608,12 +710,35 @@ func (s *Store) LoadTown(name string) (models.Town, error) {
 	return town, nil
 }
 
+// LoadAllTowns retrieves every stored town, ordered by name.
+func (s *Store) LoadAllTowns() ([]models.Town, error) {
+	defer s.track("LoadAllTowns")()
+	var towns []models.Town
+	err := s.scanAll("towns", func(rows *sql.Rows) error {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var town models.Town
+		if err := json.Unmarshal([]byte(data), &town); err != nil {
+			return err
+		}
+		towns = append(towns, town)
+		return nil
+	}, "SELECT data FROM towns ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	return towns, nil
+}
+
 // ---------------------------------------------------------------------------
 // Analytics methods
 // ---------------------------------------------------------------------------
 
 // RecordAnalyticsEvent logs a world event for gossip and history.
 func (s *Store) RecordAnalyticsEvent(accountID int64, charName, eventType, eventData string) error {
+	defer s.track("RecordAnalyticsEvent")()
 	_, err := s.db.Exec(
 		"INSERT INTO world_analytics (account_id, character_name, event_type, event_data) VALUES (?, ?, ?, ?)",
 		accountID, charName, eventType, eventData,
@@ -633,6 +758,7 @@ type AnalyticsEvent struct {
 }
 
 func (s *Store) GetRecentEvents(eventType string, limit int) ([]AnalyticsEvent, error) {
+	defer s.track("GetRecentEvents")()
 	rows, err := s.db.Query(
 		"SELECT character_name, event_type, event_data FROM world_analytics WHERE event_type = ? ORDER BY created_at DESC LIMIT ?",
 		eventType, limit,
@@ -670,10 +796,16 @@ type LeaderboardEntry struct {
 	DungeonsCleared int    `json:"dungeons_cleared"`
 	FloorsCleared   int    `json:"floors_cleared"`
 	RoomsExplored   int    `json:"rooms_explored"`
+	Guild           string `json:"guild,omitempty"`
+
+	// Set on the "guilds" board only, where each row sums a guild's members.
+	Members    int `json:"members,omitempty"`
+	TideDamage int `json:"tide_damage,omitempty"`
 }
 
 // UpdateLeaderboard upserts the leaderboard row for a character.
 func (s *Store) UpdateLeaderboard(accountID int64, charName string, stats models.CharacterStats, level int) error {
+	defer s.track("UpdateLeaderboard")()
 	_, err := s.db.Exec(
 		`INSERT INTO leaderboards (character_name, account_id, total_kills, total_deaths, bosses_killed, pvp_wins, player_level, highest_combo, dungeons_cleared, floors_cleared, rooms_explored, updated_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
@@ -688,8 +820,13 @@ func (s *Store) UpdateLeaderboard(accountID int64, charName string, stats models
 	return nil
 }
 
-// GetLeaderboard returns the top entries for a given category.
+// GetLeaderboard returns the top entries for a given category. The "guilds"
+// category ranks guilds rather than characters.
 func (s *Store) GetLeaderboard(category string, limit int) ([]LeaderboardEntry, error) {
+	defer s.track("GetLeaderboard")()
+	if category == "guilds" {
+		return s.guildLeaderboard(limit)
+	}
 	orderCol := "total_kills"
 	switch category {
 	case "kills":
@@ -711,8 +848,8 @@ func (s *Store) GetLeaderboard(category string, limit int) ([]LeaderboardEntry,
 	}
 
 	query := fmt.Sprintf(
-		"SELECT character_name, account_id, total_kills, total_deaths, bosses_killed, pvp_wins, player_level, highest_combo, dungeons_cleared, floors_cleared, rooms_explored FROM leaderboards ORDER BY %s DESC LIMIT ?",
-		orderCol,
+		"SELECT character_name, account_id, total_kills, total_deaths, bosses_killed, pvp_wins, player_level, highest_combo, dungeons_cleared, floors_cleared, rooms_explored, %s FROM leaderboards WHERE %s ORDER BY %s DESC LIMIT ?",
+		guildNameOf, notFlagged, orderCol,
 	)
 	rows, err := s.db.Query(query, limit)
 	if err != nil {
@@ -723,7 +860,7 @@ func (s *Store) GetLeaderboard(category string, limit int) ([]LeaderboardEntry,
 	var entries []LeaderboardEntry
 	for rows.Next() {
 		var e LeaderboardEntry
-		if err := rows.Scan(&e.CharacterName, &e.AccountID, &e.TotalKills, &e.TotalDeaths, &e.BossesKilled, &e.PvPWins, &e.PlayerLevel, &e.HighestCombo, &e.DungeonsCleared, &e.FloorsCleared, &e.RoomsExplored); err != nil {
+		if err := rows.Scan(&e.CharacterName, &e.AccountID, &e.TotalKills, &e.TotalDeaths, &e.BossesKilled, &e.PvPWins, &e.PlayerLevel, &e.HighestCombo, &e.DungeonsCleared, &e.FloorsCleared, &e.RoomsExplored, &e.Guild); err != nil {
 			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
 		}
 		entries = append(entries, e)
@@ -731,6 +868,42 @@ func (s *Store) GetLeaderboard(category string, limit int) ([]LeaderboardEntry,
 	return entries, rows.Err()
 }
 
+// guildNameOf selects the guild of a leaderboards row's character, if any.
+const guildNameOf = `COALESCE((SELECT g.name FROM guild_members m JOIN guilds g ON g.id = m.guild_id
+	WHERE m.account_id = leaderboards.account_id AND m.character_name = leaderboards.character_name), '')`
+
+// guildLeaderboard ranks guilds by the damage their members' villages have
+// dealt tide leaders, then by their members' kills. Each row sums the
+// members' leaderboard stats; PlayerLevel is their combined level.
+func (s *Store) guildLeaderboard(limit int) ([]LeaderboardEntry, error) {
+	query := `SELECT g.name, g.tide_damage, COUNT(*),
+		COALESCE(SUM(l.total_kills), 0), COALESCE(SUM(l.total_deaths), 0), COALESCE(SUM(l.bosses_killed), 0),
+		COALESCE(SUM(l.pvp_wins), 0), COALESCE(SUM(l.player_level), 0), COALESCE(MAX(l.highest_combo), 0),
+		COALESCE(SUM(l.dungeons_cleared), 0), COALESCE(SUM(l.floors_cleared), 0), COALESCE(SUM(l.rooms_explored), 0)
+		FROM guilds g
+		JOIN guild_members m ON m.guild_id = g.id
+		LEFT JOIN (SELECT * FROM leaderboards WHERE ` + notFlagged + `) l
+			ON l.account_id = m.account_id AND l.character_name = m.character_name
+		GROUP BY g.id
+		ORDER BY g.tide_damage DESC, COALESCE(SUM(l.total_kills), 0) DESC, g.name
+		LIMIT ?`
+	rows, err := s.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guild leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []LeaderboardEntry
+	for rows.Next() {
+		var e LeaderboardEntry
+		if err := rows.Scan(&e.Guild, &e.TideDamage, &e.Members, &e.TotalKills, &e.TotalDeaths, &e.BossesKilled, &e.PvPWins, &e.PlayerLevel, &e.HighestCombo, &e.DungeonsCleared, &e.FloorsCleared, &e.RoomsExplored); err != nil {
+			return nil, fmt.Errorf("failed to scan guild leaderboard entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
 // ---------------------------------------------------------------------------
 // Arena methods
 // ---------------------------------------------------------------------------
@@ -748,6 +921,7 @@ type ArenaEntry struct {
 
 // GetArenaEntry returns a player's arena row, or nil if not registered.
 func (s *Store) GetArenaEntry(accountID int64, charName string) (*ArenaEntry, error) {
+	defer s.track("GetArenaEntry")()
 	var e ArenaEntry
 	err := s.db.QueryRow(
 		"SELECT account_id, character_name, rating, wins, losses, battles_today, last_reset FROM arena WHERE account_id = ? AND character_name = ?",
@@ -764,6 +938,7 @@ func (s *Store) GetArenaEntry(accountID int64, charName string) (*ArenaEntry, er
 
 // UpsertArenaEntry inserts or updates an arena row.
 func (s *Store) UpsertArenaEntry(entry ArenaEntry) error {
+	defer s.track("UpsertArenaEntry")()
 	_, err := s.db.Exec(
 		`INSERT INTO arena (account_id, character_name, rating, wins, losses, battles_today, last_reset, updated_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
@@ -780,8 +955,9 @@ func (s *Store) UpsertArenaEntry(entry ArenaEntry) error {
 
 // GetArenaLeaderboard returns the top arena entries ordered by rating descending.
 func (s *Store) GetArenaLeaderboard(limit int) ([]ArenaEntry, error) {
+	defer s.track("GetArenaLeaderboard")()
 	rows, err := s.db.Query(
-		"SELECT account_id, character_name, rating, wins, losses, battles_today, last_reset FROM arena ORDER BY rating DESC LIMIT ?",
+		"SELECT account_id, character_name, rating, wins, losses, battles_today, last_reset FROM arena WHERE "+notFlagged+" ORDER BY rating DESC LIMIT ?",
 		limit,
 	)
 	if err != nil {
@@ -802,6 +978,7 @@ func (s *Store) GetArenaLeaderboard(limit int) ([]ArenaEntry, error) {
 
 // ResetArenaBattles resets battles_today for all entries whose last_reset != the given date.
 func (s *Store) ResetArenaBattles(resetDate string) error {
+	defer s.track("ResetArenaBattles")()
 	_, err := s.db.Exec(
 		"UPDATE arena SET battles_today = 0, last_reset = ? WHERE last_reset != ?",
 		resetDate, resetDate,
@@ -814,9 +991,10 @@ func (s *Store) ResetArenaBattles(resetDate string) error {
 
 // GetArenaChampion returns the highest-rated arena entry, or nil if no entries exist.
 func (s *Store) GetArenaChampion() (*ArenaEntry, error) {
+	defer s.track("GetArenaChampion")()
 	var e ArenaEntry
 	err := s.db.QueryRow(
-		"SELECT account_id, character_name, rating, wins, losses, battles_today, last_reset FROM arena ORDER BY rating DESC LIMIT 1",
+		"SELECT account_id, character_name, rating, wins, losses, battles_today, last_reset FROM arena WHERE "+notFlagged+" ORDER BY rating DESC LIMIT 1",
 	).Scan(&e.AccountID, &e.CharacterName, &e.Rating, &e.Wins, &e.Losses, &e.BattlesToday, &e.LastReset)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -840,6 +1018,7 @@ type MetricsSnapshotRow struct {
 
 // SaveMetricsSnapshot persists a metrics snapshot to the database.
 func (s *Store) SaveMetricsSnapshot(snapshotTime time.Time, jsonData string) error {
+	defer s.track("SaveMetricsSnapshot")()
 	_, err := s.db.Exec(
 		"INSERT INTO metrics_snapshots (snapshot_time, data) VALUES (?, ?)",
 		snapshotTime, jsonData,
@@ -852,6 +1031,7 @@ func (s *Store) SaveMetricsSnapshot(snapshotTime time.Time, jsonData string) err
 
 // GetMetricsHistory retrieves recent metrics snapshots since the given time.
 func (s *Store) GetMetricsHistory(since time.Time, limit int) ([]MetricsSnapshotRow, error) {
+	defer s.track("GetMetricsHistory")()
 	rows, err := s.db.Query(
 		"SELECT id, snapshot_time, data FROM metrics_snapshots WHERE snapshot_time >= ? ORDER BY snapshot_time DESC LIMIT ?",
 		since, limit,
@@ -878,6 +1058,7 @@ func (s *Store) GetMetricsHistory(since time.Time, limit int) ([]MetricsSnapshot
 
 // SaveTideLeader upserts the global tide leader (single-row table, id=1).
 func (s *Store) SaveTideLeader(leader models.TideLeader) error {
+	defer s.track("SaveTideLeader")()
 	data, err := json.Marshal(leader)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tide leader: %w", err)
@@ -895,6 +1076,7 @@ func (s *Store) SaveTideLeader(leader models.TideLeader) error {
 // LoadTideLeader retrieves the current global tide leader.
 // Returns nil and no error if no leader exists.
 func (s *Store) LoadTideLeader() (*models.TideLeader, error) {
+	defer s.track("LoadTideLeader")()
 	var data string
 	err := s.db.QueryRow("SELECT data FROM tide_leader WHERE id = 1").Scan(&data)
 	if err == sql.ErrNoRows {
@@ -912,9 +1094,630 @@ func (s *Store) LoadTideLeader() (*models.TideLeader, error) {
 
 // DeleteTideLeader removes the tide leader record.
 func (s *Store) DeleteTideLeader() error {
+	defer s.track("DeleteTideLeader")()
 	_, err := s.db.Exec("DELETE FROM tide_leader WHERE id = 1")
 	if err != nil {
 		return fmt.Errorf("failed to delete tide leader: %w", err)
 	}
 	return nil
 }
+
+// ---------------------------------------------------------------------------
+// Guild methods
+// ---------------------------------------------------------------------------
+
+// ErrGuildNameTaken is returned by CreateGuild when the name is in use.
+var ErrGuildNameTaken = errors.New("that guild name is taken")
+
+// CreateGuild inserts a new guild and its members, setting g.ID.
+func (s *Store) CreateGuild(g *models.Guild) error {
+	defer s.track("CreateGuild")()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var taken int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM guilds WHERE name = ?", g.Name).Scan(&taken); err != nil {
+		return fmt.Errorf("failed to check guild name: %w", err)
+	}
+	if taken > 0 {
+		return ErrGuildNameTaken
+	}
+	result, err := tx.Exec("INSERT INTO guilds (name, data) VALUES (?, '{}')", g.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create guild: %w", err)
+	}
+	if g.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get guild ID: %w", err)
+	}
+	if err := saveGuild(tx, *g); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveGuild updates a guild and rewrites its member rows. It fails if a
+// member already belongs to another guild.
+func (s *Store) SaveGuild(g models.Guild) error {
+	defer s.track("SaveGuild")()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := saveGuild(tx, g); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveGuild(tx *sql.Tx, g models.Guild) error {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return fmt.Errorf("failed to marshal guild: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE guilds SET data = ?, tide_damage = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		string(data), g.TideDamage, g.ID); err != nil {
+		return fmt.Errorf("failed to save guild: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM guild_members WHERE guild_id = ?", g.ID); err != nil {
+		return fmt.Errorf("failed to clear guild members: %w", err)
+	}
+	for _, m := range g.Members {
+		if _, err := tx.Exec("INSERT INTO guild_members (account_id, character_name, guild_id, rank) VALUES (?, ?, ?, ?)",
+			m.AccountID, m.Name, g.ID, m.Rank); err != nil {
+			return fmt.Errorf("failed to save guild member %s (already in a guild?): %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// LoadGuild retrieves a guild by ID. Returns nil and no error if there is no
+// such guild.
+func (s *Store) LoadGuild(id int64) (*models.Guild, error) {
+	defer s.track("LoadGuild")()
+	return s.loadGuild("SELECT data FROM guilds WHERE id = ?", id)
+}
+
+// LoadCharacterGuild retrieves the guild a character belongs to. Returns nil
+// and no error if they are in none.
+func (s *Store) LoadCharacterGuild(accountID int64, charName string) (*models.Guild, error) {
+	defer s.track("LoadCharacterGuild")()
+	return s.loadGuild(`SELECT g.data FROM guilds g JOIN guild_members m ON m.guild_id = g.id
+		WHERE m.account_id = ? AND m.character_name = ?`, accountID, charName)
+}
+
+func (s *Store) loadGuild(query string, args ...interface{}) (*models.Guild, error) {
+	var data string
+	err := s.db.QueryRow(query, args...).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load guild: %w", err)
+	}
+	var g models.Guild
+	if err := json.Unmarshal([]byte(data), &g); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal guild: %w", err)
+	}
+	return &g, nil
+}
+
+// LoadAllGuilds retrieves every guild, by name.
+func (s *Store) LoadAllGuilds() ([]models.Guild, error) {
+	defer s.track("LoadAllGuilds")()
+	var guilds []models.Guild
+	err := s.scanAll("guilds", func(rows *sql.Rows) error {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var g models.Guild
+		if err := json.Unmarshal([]byte(data), &g); err != nil {
+			return err
+		}
+		guilds = append(guilds, g)
+		return nil
+	}, "SELECT data FROM guilds ORDER BY name")
+	return guilds, err
+}
+
+// DeleteGuild removes a guild and its member rows.
+func (s *Store) DeleteGuild(id int64) error {
+	defer s.track("DeleteGuild")()
+	if _, err := s.db.Exec("DELETE FROM guild_members WHERE guild_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete guild members: %w", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM guilds WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete guild: %w", err)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Ledger methods
+// ---------------------------------------------------------------------------
+
+// LedgerFilter narrows a ledger query. Zero-valued fields match everything.
+type LedgerFilter struct {
+	AccountID int64
+	Character string
+	Asset     string
+	Reason    string
+	Since     int64 // unix seconds
+	Limit     int
+}
+
+// BalancePoint is one step in a reconstructed balance history.
+type BalancePoint struct {
+	Timestamp int64  `json:"timestamp"`
+	Delta     int    `json:"delta"`
+	Balance   int    `json:"balance"`
+	Reason    string `json:"reason"`
+	SourceRef string `json:"source_ref,omitempty"`
+}
+
+// AppendLedger writes ledger entries for an account in a single transaction.
+func (s *Store) AppendLedger(accountID int64, entries []models.LedgerEntry) error {
+	defer s.track("AppendLedger")()
+	if len(entries) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO ledger
+		(account_id, character_name, kind, asset, delta, balance, reason, source_ref, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		ts := e.Timestamp
+		if ts == 0 {
+			ts = time.Now().Unix()
+		}
+		if _, err := stmt.Exec(accountID, e.Character, e.Kind, e.Asset, e.Delta, e.Balance, e.Reason, e.SourceRef, ts); err != nil {
+			return fmt.Errorf("failed to append ledger entry: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetLedger returns ledger entries matching the filter, newest first.
+func (s *Store) GetLedger(f LedgerFilter) ([]models.LedgerEntry, error) {
+	defer s.track("GetLedger")()
+	query := "SELECT id, account_id, character_name, kind, asset, delta, balance, reason, source_ref, created_at FROM ledger WHERE created_at >= ?"
+	args := []interface{}{f.Since}
+	if f.AccountID > 0 {
+		query += " AND account_id = ?"
+		args = append(args, f.AccountID)
+	}
+	if f.Character != "" {
+		query += " AND character_name = ?"
+		args = append(args, f.Character)
+	}
+	if f.Asset != "" {
+		query += " AND asset = ?"
+		args = append(args, f.Asset)
+	}
+	if f.Reason != "" {
+		query += " AND reason = ?"
+		args = append(args, f.Reason)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.LedgerEntry
+	for rows.Next() {
+		var e models.LedgerEntry
+		if err := rows.Scan(&e.ID, &e.AccountID, &e.Character, &e.Kind, &e.Asset, &e.Delta, &e.Balance, &e.Reason, &e.SourceRef, &e.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetBalanceHistory reconstructs how a character's resource balance changed
+// since the given time, oldest first.
+func (s *Store) GetBalanceHistory(accountID int64, character, asset string, since int64) ([]BalancePoint, error) {
+	defer s.track("GetBalanceHistory")()
+	rows, err := s.db.Query(
+		`SELECT created_at, delta, balance, reason, source_ref FROM ledger
+		WHERE account_id = ? AND character_name = ? AND asset = ? AND kind = 'resource' AND created_at >= ?
+		ORDER BY id ASC`,
+		accountID, character, asset, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance history: %w", err)
+	}
+	defer rows.Close()
+
+	var points []BalancePoint
+	for rows.Next() {
+		var p BalancePoint
+		if err := rows.Scan(&p.Timestamp, &p.Delta, &p.Balance, &p.Reason, &p.SourceRef); err != nil {
+			return nil, fmt.Errorf("failed to scan balance point: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// ---------------------------------------------------------------------------
+// Account flag methods
+// ---------------------------------------------------------------------------
+
+// Account flag review statuses. Open and confirmed flags hide the account
+// from leaderboards; cleared flags do not.
+const (
+	FlagStatusOpen      = "open"
+	FlagStatusConfirmed = "confirmed"
+	FlagStatusCleared   = "cleared"
+)
+
+// notFlagged is a WHERE clause excluding accounts with unresolved flags.
+const notFlagged = "account_id NOT IN (SELECT account_id FROM account_flags WHERE status != 'cleared')"
+
+// AccountFlag is one suspicious-behavior flag awaiting or after review.
+type AccountFlag struct {
+	ID         int64     `json:"id"`
+	AccountID  int64     `json:"account_id"`
+	Username   string    `json:"username"`
+	Rule       string    `json:"rule"`
+	Detail     string    `json:"detail"`
+	Status     string    `json:"status"`
+	Hits       int       `json:"hits"`
+	ReviewedBy string    `json:"reviewed_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// FlagAccount records a rule violation for review. Repeat violations of the
+// same rule bump the hit count and reopen a previously cleared flag.
+func (s *Store) FlagAccount(accountID int64, rule, detail string) error {
+	defer s.track("FlagAccount")()
+	_, err := s.db.Exec(
+		`INSERT INTO account_flags (account_id, rule, detail) VALUES (?, ?, ?)
+		 ON CONFLICT(account_id, rule)
+		 DO UPDATE SET detail = excluded.detail, hits = hits + 1, updated_at = CURRENT_TIMESTAMP,
+		   status = CASE WHEN status = 'cleared' THEN 'open' ELSE status END`,
+		accountID, rule, detail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to flag account: %w", err)
+	}
+	return nil
+}
+
+// ListAccountFlags returns flags with the given status (all when empty),
+// most recently updated first.
+func (s *Store) ListAccountFlags(status string, limit int) ([]AccountFlag, error) {
+	defer s.track("ListAccountFlags")()
+	rows, err := s.db.Query(
+		`SELECT f.id, f.account_id, COALESCE(a.username, ''), f.rule, f.detail, f.status, f.hits, f.reviewed_by, f.created_at, f.updated_at
+		 FROM account_flags f LEFT JOIN accounts a ON a.id = f.account_id
+		 WHERE ? = '' OR f.status = ?
+		 ORDER BY f.updated_at DESC, f.id DESC LIMIT ?`,
+		status, status, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []AccountFlag
+	for rows.Next() {
+		var f AccountFlag
+		if err := rows.Scan(&f.ID, &f.AccountID, &f.Username, &f.Rule, &f.Detail, &f.Status, &f.Hits, &f.ReviewedBy, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account flag: %w", err)
+		}
+		flags = append(flags, f)
+	}
+	return flags, rows.Err()
+}
+
+// ReviewAccountFlag sets a flag's status and records the reviewing admin.
+// It returns the flagged account ID.
+func (s *Store) ReviewAccountFlag(id int64, status, reviewer string) (int64, error) {
+	defer s.track("ReviewAccountFlag")()
+	switch status {
+	case FlagStatusOpen, FlagStatusConfirmed, FlagStatusCleared:
+	default:
+		return 0, fmt.Errorf("invalid flag status %q", status)
+	}
+	var accountID int64
+	err := s.db.QueryRow(
+		`UPDATE account_flags SET status = ?, reviewed_by = ?, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = ? RETURNING account_id`,
+		status, reviewer, id,
+	).Scan(&accountID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("account flag %d not found", id)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to review account flag: %w", err)
+	}
+	return accountID, nil
+}
+
+// ---------------------------------------------------------------------------
+// Account data methods
+// ---------------------------------------------------------------------------
+
+// AccountExport is every row stored under one account. Shared town JSON is
+// not included; the engine adds it.
+type AccountExport struct {
+	Account     AccountInfo          `json:"account"`
+	Characters  []models.Character   `json:"characters"`
+	Villages    []ExportedVillage    `json:"villages"`
+	Leaderboard []LeaderboardEntry   `json:"leaderboard"`
+	Arena       []ArenaEntry         `json:"arena"`
+	Events      []ExportedEvent      `json:"events"`
+	Ledger      []models.LedgerEntry `json:"ledger"`
+	Flags       []AccountFlag        `json:"flags"`
+	Guilds      []ExportedGuild      `json:"guilds"`
+}
+
+// AccountInfo is the exportable part of an account row (no password hash).
+type AccountInfo struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportedVillage is a village with the name of the character that owns it.
+type ExportedVillage struct {
+	Character string         `json:"character"`
+	Village   models.Village `json:"village"`
+}
+
+// ExportedGuild is a guild_members row: a character's guild and rank.
+type ExportedGuild struct {
+	Character string `json:"character"`
+	Guild     string `json:"guild"`
+	Rank      string `json:"rank"`
+}
+
+// ExportedEvent is a world_analytics row.
+type ExportedEvent struct {
+	Character string    `json:"character"`
+	Type      string    `json:"type"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportAccount collects every row tied to the account. Returns nil and no
+// error if the account does not exist.
+func (s *Store) ExportAccount(accountID int64) (*AccountExport, error) {
+	defer s.track("ExportAccount")()
+	exp := &AccountExport{}
+	err := s.db.QueryRow(
+		"SELECT id, username, created_at FROM accounts WHERE id = ?", accountID,
+	).Scan(&exp.Account.ID, &exp.Account.Username, &exp.Account.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account %d: %w", accountID, err)
+	}
+
+	err = s.scanAll("characters", func(rows *sql.Rows) error {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var char models.Character
+		if err := json.Unmarshal([]byte(data), &char); err != nil {
+			return err
+		}
+		exp.Characters = append(exp.Characters, char)
+		return nil
+	}, "SELECT data FROM characters WHERE account_id = ? ORDER BY name", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("villages", func(rows *sql.Rows) error {
+		var ev ExportedVillage
+		var data string
+		if err := rows.Scan(&ev.Character, &data); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(data), &ev.Village); err != nil {
+			return err
+		}
+		exp.Villages = append(exp.Villages, ev)
+		return nil
+	}, `SELECT c.name, v.data FROM villages v JOIN characters c ON v.character_id = c.id
+		WHERE c.account_id = ? ORDER BY c.name, v.name`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("leaderboard", func(rows *sql.Rows) error {
+		var e LeaderboardEntry
+		if err := rows.Scan(&e.CharacterName, &e.AccountID, &e.TotalKills, &e.TotalDeaths, &e.BossesKilled, &e.PvPWins, &e.PlayerLevel, &e.HighestCombo, &e.DungeonsCleared, &e.FloorsCleared, &e.RoomsExplored); err != nil {
+			return err
+		}
+		exp.Leaderboard = append(exp.Leaderboard, e)
+		return nil
+	}, "SELECT character_name, account_id, total_kills, total_deaths, bosses_killed, pvp_wins, player_level, highest_combo, dungeons_cleared, floors_cleared, rooms_explored FROM leaderboards WHERE account_id = ?", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("arena", func(rows *sql.Rows) error {
+		var e ArenaEntry
+		if err := rows.Scan(&e.AccountID, &e.CharacterName, &e.Rating, &e.Wins, &e.Losses, &e.BattlesToday, &e.LastReset); err != nil {
+			return err
+		}
+		exp.Arena = append(exp.Arena, e)
+		return nil
+	}, "SELECT account_id, character_name, rating, wins, losses, battles_today, last_reset FROM arena WHERE account_id = ?", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("analytics events", func(rows *sql.Rows) error {
+		var e ExportedEvent
+		if err := rows.Scan(&e.Character, &e.Type, &e.Data, &e.CreatedAt); err != nil {
+			return err
+		}
+		exp.Events = append(exp.Events, e)
+		return nil
+	}, "SELECT character_name, event_type, event_data, created_at FROM world_analytics WHERE account_id = ? ORDER BY id", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("ledger", func(rows *sql.Rows) error {
+		var e models.LedgerEntry
+		if err := rows.Scan(&e.ID, &e.AccountID, &e.Character, &e.Kind, &e.Asset, &e.Delta, &e.Balance, &e.Reason, &e.SourceRef, &e.Timestamp); err != nil {
+			return err
+		}
+		exp.Ledger = append(exp.Ledger, e)
+		return nil
+	}, "SELECT id, account_id, character_name, kind, asset, delta, balance, reason, source_ref, created_at FROM ledger WHERE account_id = ? ORDER BY id", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("account flags", func(rows *sql.Rows) error {
+		f := AccountFlag{Username: exp.Account.Username}
+		if err := rows.Scan(&f.ID, &f.AccountID, &f.Rule, &f.Detail, &f.Status, &f.Hits, &f.ReviewedBy, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return err
+		}
+		exp.Flags = append(exp.Flags, f)
+		return nil
+	}, "SELECT id, account_id, rule, detail, status, hits, reviewed_by, created_at, updated_at FROM account_flags WHERE account_id = ? ORDER BY id", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanAll("guild memberships", func(rows *sql.Rows) error {
+		var g ExportedGuild
+		if err := rows.Scan(&g.Character, &g.Guild, &g.Rank); err != nil {
+			return err
+		}
+		exp.Guilds = append(exp.Guilds, g)
+		return nil
+	}, `SELECT m.character_name, g.name, m.rank FROM guild_members m JOIN guilds g ON g.id = m.guild_id
+		WHERE m.account_id = ? ORDER BY m.character_name`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+// DeleteAccount removes the account and every row tied to it in a single
+// transaction. scrubTown is called for each stored town and must return true
+// if it removed references to the account; changed towns are saved in the
+// same transaction.
+func (s *Store) DeleteAccount(accountID int64, scrubTown func(*models.Town) bool) error {
+	defer s.track("DeleteAccount")()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Delete first so the transaction takes the write lock before it reads;
+	// upgrading a read transaction fails if another write got in between.
+	statements := []string{
+		"DELETE FROM villages WHERE character_id IN (SELECT id FROM characters WHERE account_id = ?)",
+		"DELETE FROM characters WHERE account_id = ?",
+		"DELETE FROM leaderboards WHERE account_id = ?",
+		"DELETE FROM arena WHERE account_id = ?",
+		"DELETE FROM world_analytics WHERE account_id = ?",
+		"DELETE FROM ledger WHERE account_id = ?",
+		"DELETE FROM account_flags WHERE account_id = ?",
+		"DELETE FROM guild_members WHERE account_id = ?",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt, accountID); err != nil {
+			return fmt.Errorf("failed to delete account data: %w", err)
+		}
+	}
+	if scrubTown != nil {
+		rows, err := tx.Query("SELECT name, data FROM towns")
+		if err != nil {
+			return fmt.Errorf("failed to query towns: %w", err)
+		}
+		var changed []models.Town
+		for rows.Next() {
+			var name, data string
+			if err := rows.Scan(&name, &data); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan town row: %w", err)
+			}
+			var town models.Town
+			if err := json.Unmarshal([]byte(data), &town); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to unmarshal town %q: %w", name, err)
+			}
+			if scrubTown(&town) {
+				changed = append(changed, town)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("row iteration error: %w", err)
+		}
+		for _, town := range changed {
+			data, err := json.Marshal(town)
+			if err != nil {
+				return fmt.Errorf("failed to marshal town: %w", err)
+			}
+			if _, err := tx.Exec("UPDATE towns SET data = ? WHERE name = ?", string(data), town.Name); err != nil {
+				return fmt.Errorf("failed to save town %q: %w", town.Name, err)
+			}
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM accounts WHERE id = ?", accountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("account %d not found", accountID)
+	}
+	return tx.Commit()
+}
+
+// scanAll runs a query and calls scan for each row. what names the rows in
+// error messages.
+func (s *Store) scanAll(what string, scan func(*sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", what, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
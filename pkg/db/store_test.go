@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -27,6 +28,19 @@ func newTestStore(t *testing.T) (*Store, func()) {
 	return store, cleanup
 }
 
+func TestNewStoreKeepsDSNOptions(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?mode=rwc"
+	store, err := NewStore(dsn)
+	if err != nil {
+		t.Fatalf("NewStore(%q): %v", dsn, err)
+	}
+	defer store.Close()
+	var timeout int
+	if err := store.db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil || timeout != 5000 {
+		t.Errorf("busy_timeout = %d, %v", timeout, err)
+	}
+}
+
 func TestCreateAndGetAccount(t *testing.T) {
 	store, cleanup := newTestStore(t)
 	defer cleanup()
@@ -403,3 +417,298 @@ func TestSaveLoadQuests(t *testing.T) {
 		t.Error("quest_001 should be inactive after update")
 	}
 }
+
+func TestLedgerAppendAndQuery(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	entries := []models.LedgerEntry{
+		{Character: "Hero", Kind: "resource", Asset: "Gold", Delta: 100, Balance: 100, Reason: "dungeon_treasure", Timestamp: 1000},
+		{Character: "Hero", Kind: "resource", Asset: "Gold", Delta: -30, Balance: 70, Reason: "inn_sleep", SourceRef: "town:Ravenhold", Timestamp: 1010},
+		{Character: "Hero", Kind: "item", Asset: "Iron Sword", Delta: 1, Reason: "monster_drop", Timestamp: 1020},
+		{Character: "Alt", Kind: "resource", Asset: "Gold", Delta: 5, Balance: 5, Reason: "gamble_win", Timestamp: 1030},
+	}
+	if err := store.AppendLedger(1, entries); err != nil {
+		t.Fatalf("AppendLedger: %v", err)
+	}
+	if err := store.AppendLedger(2, entries[:1]); err != nil {
+		t.Fatalf("AppendLedger other account: %v", err)
+	}
+
+	got, err := store.GetLedger(LedgerFilter{AccountID: 1, Character: "Hero"})
+	if err != nil {
+		t.Fatalf("GetLedger: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries for Hero, got %d", len(got))
+	}
+	if got[0].Asset != "Iron Sword" {
+		t.Errorf("expected newest entry first, got %q", got[0].Asset)
+	}
+
+	sinks, err := store.GetLedger(LedgerFilter{Reason: "inn_sleep"})
+	if err != nil {
+		t.Fatalf("GetLedger by reason: %v", err)
+	}
+	if len(sinks) != 1 || sinks[0].SourceRef != "town:Ravenhold" {
+		t.Errorf("unexpected reason filter result: %+v", sinks)
+	}
+
+	history, err := store.GetBalanceHistory(1, "Hero", "Gold", 0)
+	if err != nil {
+		t.Fatalf("GetBalanceHistory: %v", err)
+	}
+	if len(history) != 2 || history[0].Balance != 100 || history[1].Balance != 70 {
+		t.Errorf("unexpected balance history: %+v", history)
+	}
+
+	recent, err := store.GetBalanceHistory(1, "Hero", "Gold", 1005)
+	if err != nil {
+		t.Fatalf("GetBalanceHistory since: %v", err)
+	}
+	if len(recent) != 1 || recent[0].Reason != "inn_sleep" {
+		t.Errorf("expected only the inn_sleep entry after since, got %+v", recent)
+	}
+}
+
+func TestAccountFlagsHideFromLeaderboards(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	honest, err := store.CreateAccount("honest", "hash")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	scripted, err := store.CreateAccount("scripted", "hash")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	for _, id := range []int64{honest, scripted} {
+		name := fmt.Sprintf("Char%d", id)
+		if err := store.UpdateLeaderboard(id, name, models.CharacterStats{TotalKills: int(id) * 10}, 5); err != nil {
+			t.Fatalf("UpdateLeaderboard: %v", err)
+		}
+		if err := store.UpsertArenaEntry(ArenaEntry{AccountID: id, CharacterName: name, Rating: 1000 + int(id)}); err != nil {
+			t.Fatalf("UpsertArenaEntry: %v", err)
+		}
+	}
+
+	if err := store.FlagAccount(scripted, "timing_variance", "too regular"); err != nil {
+		t.Fatalf("FlagAccount: %v", err)
+	}
+	if err := store.FlagAccount(scripted, "timing_variance", "still too regular"); err != nil {
+		t.Fatalf("FlagAccount repeat: %v", err)
+	}
+
+	flags, err := store.ListAccountFlags(FlagStatusOpen, 10)
+	if err != nil {
+		t.Fatalf("ListAccountFlags: %v", err)
+	}
+	if len(flags) != 1 || flags[0].Username != "scripted" || flags[0].Hits != 2 || flags[0].Detail != "still too regular" {
+		t.Fatalf("unexpected flags: %+v", flags)
+	}
+
+	board, err := store.GetLeaderboard("kills", 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard: %v", err)
+	}
+	if len(board) != 1 || board[0].AccountID != honest {
+		t.Errorf("expected only the honest account on the leaderboard, got %+v", board)
+	}
+	arena, err := store.GetArenaLeaderboard(10)
+	if err != nil {
+		t.Fatalf("GetArenaLeaderboard: %v", err)
+	}
+	if len(arena) != 1 || arena[0].AccountID != honest {
+		t.Errorf("expected only the honest account in the arena, got %+v", arena)
+	}
+
+	accountID, err := store.ReviewAccountFlag(flags[0].ID, FlagStatusCleared, "admin")
+	if err != nil || accountID != scripted {
+		t.Fatalf("ReviewAccountFlag: id=%d err=%v", accountID, err)
+	}
+	board, err = store.GetLeaderboard("kills", 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard after clear: %v", err)
+	}
+	if len(board) != 2 {
+		t.Errorf("expected cleared account back on the leaderboard, got %d entries", len(board))
+	}
+
+	if _, err := store.ReviewAccountFlag(9999, FlagStatusCleared, "admin"); err == nil {
+		t.Error("expected error reviewing a missing flag")
+	}
+}
+
+func TestExportAndDeleteAccount(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	gone, err := store.CreateAccount("gone", "hash")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	other, err := store.CreateAccount("other", "hash")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	for _, id := range []int64{gone, other} {
+		if err := store.SaveCharacter(id, models.Character{Name: "Hero"}); err != nil {
+			t.Fatalf("SaveCharacter: %v", err)
+		}
+		charID, err := store.GetCharacterID(id, "Hero")
+		if err != nil {
+			t.Fatalf("GetCharacterID: %v", err)
+		}
+		if err := store.SaveVillage(charID, models.Village{Name: "Home"}); err != nil {
+			t.Fatalf("SaveVillage: %v", err)
+		}
+		if err := store.UpdateLeaderboard(id, "Hero", models.CharacterStats{}, 1); err != nil {
+			t.Fatalf("UpdateLeaderboard: %v", err)
+		}
+		if err := store.RecordAnalyticsEvent(id, "Hero", "kill", "wolf"); err != nil {
+			t.Fatalf("RecordAnalyticsEvent: %v", err)
+		}
+		if err := store.AppendLedger(id, []models.LedgerEntry{{Character: "Hero", Kind: "resource", Asset: "Gold", Delta: 1, Balance: 1, Reason: "bounty"}}); err != nil {
+			t.Fatalf("AppendLedger: %v", err)
+		}
+	}
+	if err := store.UpsertArenaEntry(ArenaEntry{AccountID: gone, CharacterName: "Hero", Rating: 1000}); err != nil {
+		t.Fatalf("UpsertArenaEntry: %v", err)
+	}
+	if err := store.SaveTown(models.Town{Name: "Ravenhold", GossipBoard: []string{"Hero slew a wolf"}}); err != nil {
+		t.Fatalf("SaveTown: %v", err)
+	}
+
+	exp, err := store.ExportAccount(gone)
+	if err != nil || exp == nil {
+		t.Fatalf("ExportAccount: %v", err)
+	}
+	if exp.Account.Username != "gone" || len(exp.Characters) != 1 || len(exp.Villages) != 1 ||
+		len(exp.Leaderboard) != 1 || len(exp.Arena) != 1 || len(exp.Events) != 1 || len(exp.Ledger) != 1 {
+		t.Fatalf("incomplete export: %+v", exp)
+	}
+
+	scrubbed := 0
+	err = store.DeleteAccount(gone, func(town *models.Town) bool {
+		scrubbed++
+		town.GossipBoard = nil
+		return true
+	})
+	if err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if scrubbed != 1 {
+		t.Errorf("expected scrub callback once per town, got %d", scrubbed)
+	}
+	town, err := store.LoadTown("Ravenhold")
+	if err != nil || len(town.GossipBoard) != 0 {
+		t.Errorf("scrubbed town not saved: %+v, %v", town, err)
+	}
+	if exp, err := store.ExportAccount(gone); err != nil || exp != nil {
+		t.Errorf("expected deleted account to be gone, got %+v, %v", exp, err)
+	}
+	var remaining int
+	store.db.QueryRow(`SELECT (SELECT COUNT(*) FROM villages) + (SELECT COUNT(*) FROM world_analytics) + (SELECT COUNT(*) FROM ledger)`).Scan(&remaining)
+	if remaining != 3 {
+		t.Errorf("expected only the other account's 3 rows to remain, got %d", remaining)
+	}
+	if exp, err := store.ExportAccount(other); err != nil || len(exp.Characters) != 1 {
+		t.Errorf("other account's data should survive: %+v, %v", exp, err)
+	}
+	if err := store.DeleteAccount(gone, nil); err == nil {
+		t.Error("expected error deleting a missing account")
+	}
+}
+
+func TestGuildStoreAndLeaderboard(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	ada, err := store.CreateAccount("ada", "hash")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	bo, err := store.CreateAccount("bo", "hash")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	for _, id := range []int64{ada, bo} {
+		name := fmt.Sprintf("Char%d", id)
+		if err := store.SaveCharacter(id, models.Character{Name: name}); err != nil {
+			t.Fatalf("SaveCharacter: %v", err)
+		}
+		if err := store.UpdateLeaderboard(id, name, models.CharacterStats{TotalKills: int(id) * 10}, 5); err != nil {
+			t.Fatalf("UpdateLeaderboard: %v", err)
+		}
+	}
+	adaName, boName := fmt.Sprintf("Char%d", ada), fmt.Sprintf("Char%d", bo)
+
+	wolves := models.Guild{Name: "Wolves", Members: []models.GuildMember{{AccountID: ada, Name: adaName, Rank: "leader"}}}
+	if err := store.CreateGuild(&wolves); err != nil || wolves.ID == 0 {
+		t.Fatalf("CreateGuild: id=%d err=%v", wolves.ID, err)
+	}
+	if err := store.CreateGuild(&models.Guild{Name: "wolves"}); err != ErrGuildNameTaken {
+		t.Errorf("duplicate guild name: got %v", err)
+	}
+	bears := models.Guild{Name: "Bears", Members: []models.GuildMember{{AccountID: bo, Name: boName, Rank: "leader"}}}
+	if err := store.CreateGuild(&bears); err != nil {
+		t.Fatalf("CreateGuild: %v", err)
+	}
+
+	poach := wolves
+	poach.Members = append(poach.Members, models.GuildMember{AccountID: bo, Name: boName, Rank: "recruit"})
+	if err := store.SaveGuild(poach); err == nil {
+		t.Error("saved a member who already belongs to another guild")
+	}
+	wolves.TideDamage = 500
+	if err := store.SaveGuild(wolves); err != nil {
+		t.Fatalf("SaveGuild: %v", err)
+	}
+
+	g, err := store.LoadCharacterGuild(ada, adaName)
+	if err != nil || g == nil || g.Name != "Wolves" || g.TideDamage != 500 {
+		t.Fatalf("LoadCharacterGuild: %+v, %v", g, err)
+	}
+	if g, err := store.LoadCharacterGuild(ada, "Nobody"); err != nil || g != nil {
+		t.Errorf("guildless character: %+v, %v", g, err)
+	}
+	if accounts, err := store.FindCharacterAccounts(boName); err != nil || len(accounts) != 1 || accounts[0] != bo {
+		t.Errorf("FindCharacterAccounts: %v, %v", accounts, err)
+	}
+
+	board, err := store.GetLeaderboard("guilds", 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard guilds: %v", err)
+	}
+	if len(board) != 2 || board[0].Guild != "Wolves" || board[0].TideDamage != 500 || board[0].Members != 1 || board[1].TotalKills != int(bo)*10 {
+		t.Errorf("guild leaderboard: %+v", board)
+	}
+	players, err := store.GetLeaderboard("kills", 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard kills: %v", err)
+	}
+	if len(players) != 2 || players[0].Guild != "Bears" || players[1].Guild != "Wolves" {
+		t.Errorf("player rows should name their guild: %+v", players)
+	}
+
+	exp, err := store.ExportAccount(bo)
+	if err != nil || len(exp.Guilds) != 1 || exp.Guilds[0].Guild != "Bears" || exp.Guilds[0].Rank != "leader" {
+		t.Fatalf("export guilds: %+v, %v", exp, err)
+	}
+	if err := store.DeleteAccount(bo, nil); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	var rows int
+	store.db.QueryRow("SELECT COUNT(*) FROM guild_members WHERE account_id = ?", bo).Scan(&rows)
+	if rows != 0 {
+		t.Errorf("deleted account left %d guild member rows", rows)
+	}
+
+	if err := store.DeleteGuild(wolves.ID); err != nil {
+		t.Fatalf("DeleteGuild: %v", err)
+	}
+	if g, err := store.LoadGuild(wolves.ID); err != nil || g != nil {
+		t.Errorf("deleted guild loaded: %+v, %v", g, err)
+	}
+}
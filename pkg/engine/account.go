@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"rpg-game/pkg/db"
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// AccountArchive is the full data export for one account: its own rows plus
+// whatever shared town state and other villages' raid logs remember about
+// its characters. Town records
+// name players by character only, so characters whose name another account
+// also uses are listed in SharedNames and left out of Towns.
+type AccountArchive struct {
+	ExportedAt int64 `json:"exported_at"`
+	*db.AccountExport
+	Towns       []game.TownTraces `json:"towns"`
+	Raids       []game.RaidTraces `json:"raids"`
+	SharedNames []string          `json:"shared_names,omitempty"`
+}
+
+// ExportAccount builds the account's data archive. Returns nil and no error
+// if the account does not exist.
+func (e *Engine) ExportAccount(accountID int64) (*AccountArchive, error) {
+	if e.store == nil {
+		return nil, fmt.Errorf("no database configured")
+	}
+	exp, err := e.store.ExportAccount(accountID)
+	if err != nil || exp == nil {
+		return nil, err
+	}
+	archive := &AccountArchive{ExportedAt: time.Now().Unix(), AccountExport: exp,
+		Towns: []game.TownTraces{}, Raids: []game.RaidTraces{}}
+
+	names := make([]string, 0, len(exp.Characters))
+	for _, c := range exp.Characters {
+		names = append(names, c.Name)
+	}
+	names, archive.SharedNames, err = e.splitSharedNames(accountID, names)
+	if err != nil {
+		return nil, err
+	}
+	towns, err := e.store.LoadAllTowns()
+	if err != nil {
+		return nil, err
+	}
+	for i := range towns {
+		if traces := game.FindTownTraces(&towns[i], accountID, names); !traces.Empty() {
+			archive.Towns = append(archive.Towns, traces)
+		}
+	}
+
+	villages, err := e.store.LoadAllVillages()
+	if err != nil {
+		return nil, err
+	}
+	for _, vwo := range villages {
+		if vwo.AccountID == accountID {
+			continue // already in the account's own villages
+		}
+		if entries := game.FindRaidTraces(&vwo.Village, accountID); len(entries) > 0 {
+			archive.Raids = append(archive.Raids, game.RaidTraces{Village: vwo.Village.Name, Entries: entries})
+		}
+	}
+	return archive, nil
+}
+
+// DeleteAccount ends the account's sessions without saving them, then
+// deletes the account, its characters and every row tied to it, and scrubs
+// its characters from shared town and guild state and other villages' raid
+// logs. Town records naming a
+// character another account also has are kept, since they can't be told
+// apart.
+func (e *Engine) DeleteAccount(accountID int64) error {
+	if e.store == nil {
+		return fmt.Errorf("no database configured")
+	}
+	names, err := e.store.ListCharacters(accountID)
+	if err != nil {
+		return err
+	}
+	townNames, _, err := e.splitSharedNames(accountID, names)
+	if err != nil {
+		return err
+	}
+
+	e.broadcastToAccount(accountID, ErrorResponse("This account has been deleted."))
+	e.mu.Lock()
+	for id, sess := range e.sessions {
+		if sess.AccountID == accountID {
+			delete(e.sessions, id)
+		}
+	}
+	e.mu.Unlock()
+
+	if err := e.scrubGuilds(accountID, names); err != nil {
+		return err
+	}
+	if err := e.scrubRaidLogs(accountID); err != nil {
+		return err
+	}
+	return e.store.DeleteAccount(accountID, func(town *models.Town) bool {
+		return game.ScrubTownTraces(town, accountID, townNames)
+	})
+}
+
+// scrubGuilds takes the account's characters out of every guild. A guild
+// left empty is disbanded.
+func (e *Engine) scrubGuilds(accountID int64, names []string) error {
+	e.guildMu.Lock()
+	defer e.guildMu.Unlock()
+	guilds, err := e.store.LoadAllGuilds()
+	if err != nil {
+		return err
+	}
+	for i := range guilds {
+		g := &guilds[i]
+		if !game.ScrubGuildTraces(g, accountID, names) {
+			continue
+		}
+		if len(g.Members) == 0 {
+			err = e.store.DeleteGuild(g.ID)
+		} else {
+			err = e.store.SaveGuild(*g)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scrubRaidLogs removes the account's raids from other villages' raid logs.
+// An online owner's village is changed through their session, as a raid on
+// it would be, so the session doesn't save the raids back.
+func (e *Engine) scrubRaidLogs(accountID int64) error {
+	villages, err := e.store.LoadAllVillages()
+	if err != nil {
+		return err
+	}
+	for _, vwo := range villages {
+		if vwo.AccountID == accountID || len(game.FindRaidTraces(&vwo.Village, accountID)) == 0 {
+			continue
+		}
+		if sess := e.characterSession(vwo.AccountID, vwo.CharacterName); sess != nil {
+			village := sess.SelectedVillage
+			if village == nil || village.Name != vwo.Village.Name {
+				v, ok := sess.GameState.Villages[vwo.Village.Name]
+				if !ok {
+					v = vwo.Village
+				}
+				village = &v
+			}
+			game.ScrubRaidTraces(village, accountID)
+			sess.GameState.Villages[village.Name] = *village
+			e.saveSession(sess)
+			continue
+		}
+		game.ScrubRaidTraces(&vwo.Village, accountID)
+		if err := e.store.SaveVillage(vwo.CharacterID, vwo.Village); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitSharedNames separates the account's character names that no other
+// account uses from those it shares.
+func (e *Engine) splitSharedNames(accountID int64, names []string) (own, shared []string, err error) {
+	for _, name := range names {
+		ids, err := e.store.FindCharacterAccounts(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(ids) == 1 && ids[0] == accountID {
+			own = append(own, name)
+		} else {
+			shared = append(shared, name)
+		}
+	}
+	return own, shared, nil
+}
@@ -3,11 +3,13 @@ package engine
 import (
 	"fmt"
 	"time"
+
+	"rpg-game/pkg/config"
 )
 
 // GameCalendar represents a point in game time.
-// 1 real minute = 1 game hour, 24 game hours = 1 day,
-// 30 days = 1 cycle, 4 cycles = 1 year.
+// By default 1 real minute = 1 game hour (see config.CalendarConfig),
+// 24 game hours = 1 day, 30 days = 1 cycle, 4 cycles = 1 year.
 type GameCalendar struct {
 	Day   int `json:"day"`   // 1-30
 	Cycle int `json:"cycle"` // 1-4
@@ -15,13 +17,11 @@ type GameCalendar struct {
 	Hour  int `json:"hour"`  // 0-23
 }
 
-// gameEpoch is the fixed epoch for the game calendar.
-var gameEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
-
 // CurrentGameCalendar computes the current game calendar from real elapsed time.
 func CurrentGameCalendar() GameCalendar {
-	elapsed := time.Since(gameEpoch)
-	totalGameHours := int(elapsed.Minutes()) // 1 real minute = 1 game hour
+	cfg := config.Current()
+	elapsed := time.Since(cfg.Epoch())
+	totalGameHours := int(elapsed.Seconds()) / cfg.Calendar.RealSecondsPerGameHour
 
 	hour := totalGameHours % 24
 	totalGameDays := totalGameHours / 24
@@ -23,6 +23,7 @@ type Engine struct {
 	mu          sync.RWMutex
 	subscribers map[string]func(GameResponse) // keyed by sessionID
 	subMu       sync.RWMutex                  // separate mutex to avoid deadlock
+	guildMu     sync.Mutex                    // serializes guild load-modify-save
 }
 
 // NewEngine creates a new game engine (file-based persistence only).
@@ -205,6 +206,11 @@ func (e *Engine) ProcessCommand(sessionID string, cmd GameCommand) GameResponse
 		return ErrorResponse("Session not found")
 	}
 
+	if e.metrics != nil {
+		start, state := time.Now(), session.State
+		defer func() { e.metrics.ObserveCommand(state, time.Since(start)) }()
+	}
+
 	// Navbar tab commands work regardless of current session state,
 	// since the frontend tabs can send these from any screen.
 	if cmd.Type == "select" {
@@ -273,8 +279,28 @@ func (e *Engine) ProcessCommand(sessionID string, cmd GameCommand) GameResponse
 		return e.handleMainMenu(session, cmd)
 	case StateCharacterCreate:
 		return e.handleCharacterCreate(session, cmd)
+	case StateCharacterClassSelect:
+		return e.handleCharacterClassSelect(session, cmd)
 	case StateCharacterSelect:
 		return e.handleCharacterSelect(session, cmd)
+	case StateTalents:
+		return e.handleTalents(session, cmd)
+	case StateAttributes:
+		return e.handleAttributes(session, cmd)
+	case StateBlacksmith:
+		return e.handleBlacksmith(session, cmd)
+	case StateInventory:
+		return e.handleInventory(session, cmd)
+	case StateVillageStash:
+		return e.handleVillageStash(session, cmd)
+	case StateEnchanting:
+		return e.handleEnchanting(session, cmd)
+	case StateVillageJobs:
+		return e.handleVillageJobs(session, cmd)
+	case StateVillageBuildings:
+		return e.handleVillageBuildings(session, cmd)
+	case StateVillageRaids:
+		return e.handleVillageRaids(session, cmd)
 	case StateHarvestSelect:
 		return e.handleHarvestSelect(session, cmd)
 	case StateHuntLocationSelect:
@@ -305,8 +331,6 @@ func (e *Engine) ProcessCommand(sessionID string, cmd GameCommand) GameResponse
 		return e.handleLoadSave(session, cmd)
 	case StateLoadSaveCharSelect:
 		return e.handleLoadSaveCharSelect(session, cmd)
-	case StateBuildSelect:
-		return e.handleBuildSelect(session, cmd)
 	case StateVillageMain:
 		return e.handleVillageMain(session, cmd)
 	case StateVillageViewVillagers:
@@ -361,6 +385,8 @@ func (e *Engine) ProcessCommand(sessionID string, cmd GameCommand) GameResponse
 		return e.handleVillageTakeItem(session, cmd)
 	case StateVillageHealGuard:
 		return e.handleVillageHealGuard(session, cmd)
+	case StateVillageGuardSkill:
+		return e.handleVillageGuardSkill(session, cmd)
 	case StateVillageFortifications:
 		return e.handleVillageFortifications(session, cmd)
 	case StateVillageTraining:
@@ -428,6 +454,20 @@ func (e *Engine) ProcessCommand(sessionID string, cmd GameCommand) GameResponse
 		return e.handleArenaChallenge(session, cmd)
 	case StateArenaConfirm:
 		return e.handleArenaConfirm(session, cmd)
+	case StateGuild:
+		return e.handleGuild(session, cmd)
+	case StateGuildCreate:
+		return e.handleGuildCreate(session, cmd)
+	case StateGuildInvite:
+		return e.handleGuildInvite(session, cmd)
+	case StateGuildRoster:
+		return e.handleGuildRoster(session, cmd)
+	case StateGuildChat:
+		return e.handleGuildChat(session, cmd)
+	case StateGuildBank:
+		return e.handleGuildBank(session, cmd)
+	case StateGuildBankAmount:
+		return e.handleGuildBankAmount(session, cmd)
 	case StateDungeonSelect:
 		return e.handleDungeonSelect(session, cmd)
 	case StateDungeonFloorMap:
@@ -448,6 +488,7 @@ func (e *Engine) ProcessCommand(sessionID string, cmd GameCommand) GameResponse
 // saveSession persists session state directly (for use by handlers that already have the session).
 func (e *Engine) saveSession(session *GameSession) {
 	if session.Player != nil {
+		e.flushLedger(session.AccountID, session.Player)
 		session.GameState.CharactersMap[session.Player.Name] = *session.Player
 	}
 	if e.store != nil && session.AccountID > 0 {
@@ -459,6 +500,26 @@ func (e *Engine) saveSession(session *GameSession) {
 	}
 }
 
+// flushLedger drains the character's buffered ledger entries into the store
+// and the economy metrics. It must run before the character is copied into
+// CharactersMap so stale entries are never written twice.
+func (e *Engine) flushLedger(accountID int64, player *models.Character) {
+	entries := game.DrainLedger(player)
+	if len(entries) == 0 {
+		return
+	}
+	if e.metrics != nil {
+		for _, entry := range entries {
+			e.metrics.RecordLedger(entry.Asset, entry.Reason, entry.Delta, entry.Timestamp)
+		}
+	}
+	if e.store != nil && accountID > 0 {
+		if err := e.store.AppendLedger(accountID, entries); err != nil {
+			fmt.Printf("[Ledger] Failed to write %d entries for %s: %v\n", len(entries), player.Name, err)
+		}
+	}
+}
+
 // SaveSession saves the current session state. Uses SQLite if available, otherwise file.
 func (e *Engine) SaveSession(sessionID string) error {
 	e.mu.RLock()
@@ -470,6 +531,7 @@ func (e *Engine) SaveSession(sessionID string) error {
 	}
 
 	if session.Player != nil {
+		e.flushLedger(session.AccountID, session.Player)
 		session.GameState.CharactersMap[session.Player.Name] = *session.Player
 	}
 
@@ -554,27 +616,36 @@ func (e *Engine) ProcessHarvestTick(sessionID string) *HarvestTickResult {
 		return nil
 	}
 
-	village, exists := session.GameState.Villages[villageName]
+	stored, exists := session.GameState.Villages[villageName]
 	if !exists {
 		return nil
 	}
+	// Work on the open village screen's copy if there is one, so villager XP
+	// isn't overwritten when the player next saves it.
+	village := &stored
+	if session.SelectedVillage != nil {
+		village = session.SelectedVillage
+	}
 
-	if !game.HasActiveHarvesters(&village) || !game.ShouldHarvest(&village) {
+	if !game.HasActiveHarvesters(village) || !game.ShouldHarvest(village) {
 		return nil
 	}
 
-	results := game.ProcessVillageResourceCollection(&village, session.Player)
+	results := game.ProcessVillageResourceCollection(village, session.Player)
 	if len(results) == 0 {
 		return nil
 	}
 
 	village.LastHarvestTime = time.Now().Unix()
-	session.GameState.Villages[villageName] = village
+	session.GameState.Villages[villageName] = *village
 	session.GameState.CharactersMap[session.Player.Name] = *session.Player
 
 	msgs := []GameMessage{}
 	for _, r := range results {
 		msgs = append(msgs, Msg(fmt.Sprintf("%s collected %d %s", r.VillagerName, r.Amount, r.ResourceType), "loot"))
+		if r.LeveledUp {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s reached level %d!", r.VillagerName, r.NewLevel), "system"))
+		}
 	}
 
 	// Save
@@ -583,7 +654,7 @@ func (e *Engine) ProcessHarvestTick(sessionID string) *HarvestTickResult {
 	return &HarvestTickResult{
 		Messages: msgs,
 		Player:   MakePlayerState(session.Player),
-		Village:  MakeVillageView(&village),
+		Village:  MakeVillageView(village),
 	}
 }
 
@@ -597,9 +668,10 @@ func (e *Engine) GetOnlinePlayers(excludeSessionID string) []OnlinePlayer {
 			continue
 		}
 		players = append(players, OnlinePlayer{
-			Name:     sess.Player.Name,
-			Level:    sess.Player.Level,
-			Activity: sessionActivity(sess.State),
+			AccountID: sess.AccountID,
+			Name:      sess.Player.Name,
+			Level:     sess.Player.Level,
+			Activity:  sessionActivity(sess.State),
 		})
 	}
 	return players
@@ -710,6 +782,7 @@ func (e *Engine) ProcessAutoTideTick() *AutoTideTickResult {
 			interval = 3600
 		}
 		if vwo.Village.LastTideTime+interval >= now {
+			e.sendTideWarning(&vwo, now)
 			continue
 		}
 
@@ -735,6 +808,7 @@ func (e *Engine) ProcessAutoTideTick() *AutoTideTickResult {
 		}
 
 		// Save character back to DB
+		e.flushLedger(vwo.AccountID, &char)
 		if err := e.store.SaveCharacter(vwo.AccountID, char); err != nil {
 			fmt.Printf("[AutoTide] Failed to save character %s: %v\n", vwo.CharacterName, err)
 		}
@@ -743,8 +817,12 @@ func (e *Engine) ProcessAutoTideTick() *AutoTideTickResult {
 		msgs := []GameMessage{}
 		for _, m := range tideResult.Messages {
 			tag := "combat"
-			if strings.HasPrefix(m, "---") || strings.HasPrefix(m, "-- ") || strings.HasPrefix(m, "Defenders:") {
+			if strings.HasPrefix(m, "---") || strings.HasPrefix(m, "-- ") || strings.HasPrefix(m, "Defenders:") || strings.HasPrefix(m, "Siege ") {
 				tag = "system"
+			} else if strings.HasSuffix(m, "killed!") {
+				tag = "loot"
+			} else if strings.Contains(m, "breach dmg") || strings.HasSuffix(m, "breaks!") || strings.HasSuffix(m, "falls!") {
+				tag = "damage"
 			} else if strings.HasPrefix(m, "VICTORY") || strings.HasPrefix(m, "Strong defense") {
 				tag = "loot"
 			} else if strings.HasPrefix(m, "DEFEAT") || strings.HasPrefix(m, "Village level reset") || strings.HasPrefix(m, "The village must") {
@@ -795,6 +873,39 @@ func (e *Engine) ProcessAutoTideTick() *AutoTideTickResult {
 	return &AutoTideTickResult{TidesProcessed: tidesProcessed}
 }
 
+// sendTideWarning tells a village's owner about a tide its watchtower has
+// spotted, once per tide.
+func (e *Engine) sendTideWarning(vwo *db.VillageWithOwner, now int64) {
+	warning := game.TideWarning(&vwo.Village, now)
+	if warning == "" {
+		return
+	}
+	if err := e.store.SaveVillage(vwo.CharacterID, vwo.Village); err != nil {
+		fmt.Printf("[AutoTide] Failed to save village for %s: %v\n", vwo.CharacterName, err)
+	}
+
+	e.mu.RLock()
+	for _, sess := range e.sessions {
+		if sess.AccountID == vwo.AccountID && sess.Player != nil && sess.Player.Name == vwo.CharacterName {
+			if v, ok := sess.GameState.Villages[vwo.Village.Name]; ok {
+				v.TideWarnedFor = vwo.Village.TideWarnedFor
+				sess.GameState.Villages[vwo.Village.Name] = v
+			}
+			if sess.SelectedVillage != nil {
+				sess.SelectedVillage.TideWarnedFor = vwo.Village.TideWarnedFor
+			}
+		}
+	}
+	e.mu.RUnlock()
+
+	char, err := e.store.LoadCharacter(vwo.AccountID, vwo.CharacterName)
+	if err != nil {
+		fmt.Printf("[AutoTide] Failed to load character %s: %v\n", vwo.CharacterName, err)
+		return
+	}
+	e.broadcastToAccount(vwo.AccountID, villageNoticeResponse("village_event", []string{warning}, &char, &vwo.Village))
+}
+
 // TideLeaderTickResult holds the results of a tide leader processing tick.
 type TideLeaderTickResult struct {
 	LeaderSpawned  bool
@@ -898,6 +1009,7 @@ func (e *Engine) ProcessTideLeaderTick() *TideLeaderTickResult {
 		if saveErr := e.store.SaveVillage(vwo.CharacterID, vwo.Village); saveErr != nil {
 			fmt.Printf("[TideLeader] Failed to save village after raid: %v\n", saveErr)
 		}
+		e.creditGuildTideDamage(leader, vwo.AccountID, vwo.CharacterName, raidResult.DamageDealt)
 
 		// Build broadcast messages
 		msgs := []GameMessage{}
@@ -930,7 +1042,8 @@ func (e *Engine) ProcessTideLeaderTick() *TideLeaderTickResult {
 				if pErr != nil {
 					continue
 				}
-				xp, gold := game.TideLeaderDefeatReward(&pChar, leader)
+				xp, gold, looted := game.TideLeaderDefeatReward(&pChar, leader)
+				e.flushLedger(pVwo.AccountID, &pChar)
 				if saveErr := e.store.SaveCharacter(pVwo.AccountID, pChar); saveErr != nil {
 					fmt.Printf("[TideLeader] Failed to save rewarded character: %v\n", saveErr)
 				}
@@ -939,6 +1052,9 @@ func (e *Engine) ProcessTideLeaderTick() *TideLeaderTickResult {
 					Msg(fmt.Sprintf("The %s has been defeated! All villages celebrate!", leader.Name), "loot"),
 					Msg(fmt.Sprintf("Reward: +%d XP, +%d Gold", xp, gold), "loot"),
 				}
+				for _, item := range looted {
+					rewardMsgs = append(rewardMsgs, Msg(fmt.Sprintf("Boss loot: %s!", item.Name), "loot"))
+				}
 				rewardResp := GameResponse{
 					Type:     "tide_leader_defeated",
 					Messages: rewardMsgs,
@@ -954,6 +1070,8 @@ func (e *Engine) ProcessTideLeaderTick() *TideLeaderTickResult {
 				for _, sess := range e.sessions {
 					if sess.AccountID == pVwo.AccountID && sess.Player != nil && sess.Player.Name == pVwo.CharacterName {
 						sess.Player.Experience = pChar.Experience
+						sess.Player.EquipmentMap = pChar.EquipmentMap
+						sess.Player.Inventory = pChar.Inventory
 						sess.Player.ResourceStorageMap = pChar.ResourceStorageMap
 						sess.GameState.CharactersMap[pChar.Name] = pChar
 					}
@@ -1001,7 +1119,7 @@ func (e *Engine) ProcessVillageManagerTicks() *VillageManagerTickResult {
 
 		// Run the village manager tick
 		messages := game.ProcessVillageManagerTick(&vwo.Village, &char)
-		if len(messages) == 0 {
+		if len(messages) == 0 && len(vwo.Village.Villagers) == 0 {
 			continue
 		}
 		villagesManaged++
@@ -1012,6 +1130,7 @@ func (e *Engine) ProcessVillageManagerTicks() *VillageManagerTickResult {
 		}
 
 		// Save character back to DB
+		e.flushLedger(vwo.AccountID, &char)
 		if err := e.store.SaveCharacter(vwo.AccountID, char); err != nil {
 			fmt.Printf("[VillageManager] Failed to save character %s: %v\n", vwo.CharacterName, err)
 		}
@@ -1023,11 +1142,18 @@ func (e *Engine) ProcessVillageManagerTicks() *VillageManagerTickResult {
 				if sess.GameState.Villages != nil {
 					sess.GameState.Villages[vwo.Village.Name] = vwo.Village
 				}
+				if sess.SelectedVillage != nil {
+					*sess.SelectedVillage = vwo.Village
+				}
 				sess.Player.ResourceStorageMap = char.ResourceStorageMap
 				sess.GameState.CharactersMap[char.Name] = char
 			}
 		}
 		e.mu.RUnlock()
+
+		if len(messages) > 0 {
+			e.broadcastToAccount(vwo.AccountID, villageNoticeResponse("village_event", messages, &char, &vwo.Village))
+		}
 	}
 
 	if villagesManaged == 0 {
@@ -1122,6 +1248,28 @@ func (e *Engine) RenameSessionCharacter(sessionID, oldName, newName string) erro
 	return nil
 }
 
+// SetSessionCharacterClass gives a classless character in the session a
+// class (used to set up agent characters).
+func (e *Engine) SetSessionCharacterClass(sessionID, charName, classID string) error {
+	e.mu.RLock()
+	session, ok := e.sessions[sessionID]
+	e.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	char, exists := session.GameState.CharactersMap[charName]
+	if !exists {
+		return fmt.Errorf("character %q not found in session", charName)
+	}
+	if err := game.AdoptClass(&char, classID); err != nil {
+		return err
+	}
+	session.GameState.CharactersMap[charName] = char
+	return nil
+}
+
 // GetAllSessions returns all active sessions (for testing/admin).
 func (e *Engine) GetAllSessions() []*GameSession {
 	e.mu.RLock()
@@ -1133,6 +1281,18 @@ func (e *Engine) GetAllSessions() []*GameSession {
 	return sessions
 }
 
+// SessionProgress returns the active character's lifetime XP and current
+// gold. ok is false if the session has no character loaded.
+func (e *Engine) SessionProgress(sessionID string) (totalXP, gold int, ok bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	sess, found := e.sessions[sessionID]
+	if !found || sess.Player == nil {
+		return 0, 0, false
+	}
+	return sess.Player.Stats.TotalXPEarned, game.GoldBalance(sess.Player), true
+}
+
 // RemoveSession removes a session from the engine.
 func (e *Engine) RemoveSession(sessionID string) {
 	e.mu.Lock()
@@ -1157,6 +1317,10 @@ func BuildMainMenuResponse(session *GameSession) GameResponse {
 		Opt("12", "Enter Dungeon"),
 		Opt("13", "Bounty Board"),
 		Opt("14", "Arena"),
+		Opt("15", talentsMenuLabel(session.Player)),
+		Opt("16", attributesMenuLabel(session.Player)),
+		Opt("17", fmt.Sprintf("Inventory (%d/%d)", len(session.Player.Inventory), game.InventoryCapacity(session.Player))),
+		Opt("18", "Guild"),
 		Opt("exit", "Exit Game"),
 	}
 
@@ -4,8 +4,15 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/db"
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
 )
 
 func init() {
@@ -78,8 +85,12 @@ func TestCharacterCreation(t *testing.T) {
 		t.Error("Expected name prompt for character creation")
 	}
 
-	// Create a new character
+	// Name the character, then pick a class
 	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "input", Value: "TestHero"})
+	if resp.State == nil || resp.State.Screen != "character_class_select" {
+		t.Fatalf("Expected class selection after naming, got %+v", resp.State)
+	}
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "mage"})
 	if resp.Type == "error" {
 		t.Fatalf("Character creation failed: %v", resp.Messages)
 	}
@@ -89,9 +100,46 @@ func TestCharacterCreation(t *testing.T) {
 	session := eng.sessions[sessionID]
 	eng.mu.RUnlock()
 
-	_, exists := session.GameState.CharactersMap["TestHero"]
+	char, exists := session.GameState.CharactersMap["TestHero"]
 	if !exists {
-		t.Error("TestHero not found in character map")
+		t.Fatal("TestHero not found in character map")
+	}
+	if char.Class != "mage" || len(char.LearnedSkills) != 1 || char.LearnedSkills[0].Name != "Fireball" {
+		t.Errorf("Expected a mage starting with Fireball, got class %q skills %+v", char.Class, char.LearnedSkills)
+	}
+	if resp.State.Player == nil || resp.State.Player.Class != "mage" {
+		t.Error("Expected class in player state")
+	}
+}
+
+func TestTalentsScreen(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	session.Player.Level = 3
+	session.Player.Experience = 500
+
+	// A classless character picks a class on the talents screen first.
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "15"})
+	if resp.State == nil || resp.State.Screen != "talents" {
+		t.Fatalf("Expected talents screen, got %+v", resp.State)
+	}
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "class:warrior"})
+	if session.Player.Class != "warrior" || resp.State.Player.TalentPoints != 2 {
+		t.Fatalf("Expected warrior with 2 points, got %q / %d", session.Player.Class, resp.State.Player.TalentPoints)
+	}
+
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "learn:brutality"})
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "learn:executioner"})
+	if session.Player.Talents["executioner"] != 0 {
+		t.Error("Level-gated talent should not be learnable at level 3")
+	}
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "learn:brutality"})
+	if session.Player.Talents["brutality"] != 2 || resp.State.Player.TalentPoints != 0 {
+		t.Errorf("Expected brutality rank 2 and no points left, got %v / %d", session.Player.Talents, resp.State.Player.TalentPoints)
 	}
 }
 
@@ -254,3 +302,360 @@ func TestDiscoveredLocations(t *testing.T) {
 		t.Error("Expected location messages")
 	}
 }
+
+func TestAttributesScreen(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	session.Player.Level = 2
+
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "16"})
+	if resp.State == nil || resp.State.Screen != "attributes" {
+		t.Fatalf("Expected attributes screen, got %+v", resp.State)
+	}
+	points := resp.State.Player.AttributePoints
+	if points == 0 {
+		t.Fatal("Expected attribute points at level 2")
+	}
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "add:vitality"})
+	if session.Player.Attributes.Vitality != 1 || resp.State.Player.AttributePoints != points-1 {
+		t.Errorf("Expected 1 vitality and %d points left, got %+v / %d", points-1, session.Player.Attributes, resp.State.Player.AttributePoints)
+	}
+
+	// Respec costs gold the fresh character does not have.
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "back"})
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "3"})
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "visit:" + game.RespecLocation})
+	if resp.State == nil || resp.State.Screen != "attributes" {
+		t.Fatalf("Expected the Training Hall to open the attributes screen, got %+v", resp.State)
+	}
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "respec"})
+	if session.Player.Attributes.Vitality != 1 {
+		t.Error("Respec should fail without gold")
+	}
+	game.AdjustResource(session.Player, "Gold", game.RespecCost(session.Player), game.ReasonMonsterDrop, "")
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "respec"})
+	if session.Player.Attributes.Vitality != 0 {
+		t.Error("Respec should reset allocated attributes")
+	}
+}
+
+func TestRespecOnlyAtTrainingHall(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	session.Player.Level = 2
+	game.AdjustResource(session.Player, "Gold", game.RespecCost(session.Player), game.ReasonMonsterDrop, "")
+
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "16"})
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "add:luck"})
+	for _, opt := range resp.Options {
+		if opt.Key == "respec" && opt.Enabled {
+			t.Error("Respec should not be offered away from the Training Hall")
+		}
+	}
+	resp = eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "respec"})
+	if session.Player.Attributes.Luck != 1 || game.GoldBalance(session.Player) != game.RespecCost(session.Player) {
+		t.Error("Respec away from the Training Hall should be refused and cost nothing")
+	}
+	if !strings.Contains(messagesText(resp.Messages), "only offered at the Training Hall") {
+		t.Errorf("Expected a refusal message, got %v", resp.Messages)
+	}
+
+	// Leaving the Training Hall ends the offer.
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "back"})
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "3"})
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "visit:" + game.RespecLocation})
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "back"})
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "16"})
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "respec"})
+	if session.Player.Attributes.Luck != 1 {
+		t.Error("Respec should not carry over after leaving the Training Hall")
+	}
+}
+
+func TestTownBlacksmithRepair(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	boots := models.Item{Name: "Worn Boots", Slot: 4, ItemType: "equipment", Rarity: 1}
+	game.EnsureDurability(&boots)
+	boots.Durability = 0
+	session.Player.EquipmentMap[4] = boots
+	game.AdjustGold(session.Player, 100, game.ReasonMonsterDrop, "")
+	game.AdjustResource(session.Player, "Beast Skin", 5, game.ReasonMonsterDrop, "")
+
+	session.State = StateBlacksmith
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "slot:4"})
+	if resp.State == nil || resp.State.Screen != "blacksmith" {
+		t.Fatalf("Expected blacksmith screen, got %+v", resp.State)
+	}
+	if item := session.Player.EquipmentMap[4]; item.Durability != item.MaxDurability {
+		t.Errorf("Expected boots repaired, got %d/%d", item.Durability, item.MaxDurability)
+	}
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "back"})
+	if session.State != StateTownMain {
+		t.Errorf("Expected to return to town, got %s", session.State)
+	}
+}
+
+func TestInventoryScreenStacks(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	game.CompactInventory(&session.Player.Inventory)
+	before := game.CountItem(session.Player.Inventory, "Small Health Potion")
+	if before == 0 || len(session.Player.Inventory) != 1 {
+		t.Fatalf("Expected starting potions in one stack, got %+v", session.Player.Inventory)
+	}
+
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "17"})
+	if resp.State == nil || resp.State.Screen != "inventory" {
+		t.Fatalf("Expected inventory screen, got %+v", resp.State)
+	}
+	if q := resp.State.Player.Inventory[0].Quantity; q != before {
+		t.Errorf("Expected stack of %d in the view, got %d", before, q)
+	}
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "use:0"})
+	if got := game.CountItem(session.Player.Inventory, "Small Health Potion"); got != before-1 {
+		t.Errorf("Expected one potion used, %d left", got)
+	}
+}
+
+func TestEnchantGuardGear(t *testing.T) {
+	prev := config.Current()
+	cfg := *prev
+	cfg.Balance.EnchantSuccessChance = 100
+	config.Set(&cfg)
+	defer config.Set(prev)
+
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	spear := models.Item{Name: "Guard Spear", Slot: 5, ItemType: "equipment", Rarity: 4}
+	guard := models.Guard{Name: "Bram", EquipmentMap: map[int]models.Item{5: spear}}
+	session.SelectedVillage = &models.Village{Name: "Testville", ActiveGuards: []models.Guard{guard},
+		Buildings: []models.VillageBuilding{{Name: game.BuildingEnchanting, Level: 1}}}
+	session.GameState.Villages = map[string]models.Village{}
+	for res, n := range map[string]int{"Gold": 200, "Sharp Fang": 3} {
+		game.AdjustResource(session.Player, res, n, game.ReasonMonsterDrop, "")
+	}
+
+	session.State = StateEnchanting
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "target:g:0:5"})
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "apply:venom"})
+	if resp.State == nil || resp.State.Screen != "enchanting" {
+		t.Fatalf("Expected enchanting screen, got %+v", resp.State)
+	}
+	g := session.SelectedVillage.ActiveGuards[0]
+	if len(g.EquipmentMap[5].Enchantments) != 1 || g.StatsMod.Effects[game.EffectPoisonOnHit] != 15 {
+		t.Errorf("Expected the guard's spear enchanted, got %+v", g.EquipmentMap[5])
+	}
+}
+
+func TestCraftFromRecipeMenu(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	session.SelectedVillage = &models.Village{Name: "Testville", Level: 3, UnlockedCrafting: []string{"potions"},
+		Villagers: []models.Villager{{Name: "Ada", Role: "crafter", Level: 1, Efficiency: 5, AssignedTask: game.TaskCrafting}}}
+	session.GameState.Villages = map[string]models.Village{}
+	session.Player.VillageName = "Testville"
+	game.AdjustResource(session.Player, "Iron", 5, game.ReasonMonsterDrop, "")
+	game.AdjustGold(session.Player, 10-game.GoldBalance(session.Player), game.ReasonMonsterDrop, "")
+	healPotions := func() int {
+		n := 0
+		for _, item := range session.Player.Inventory {
+			if item.Consumable.EffectType == "heal" {
+				n += max(item.Quantity, 1)
+			}
+		}
+		return n
+	}
+	potions := healPotions()
+
+	session.State = StateVillageCraftPotion
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "1"})
+	if resp.State == nil || resp.State.Screen != "village_craft_potion" {
+		t.Fatalf("Expected potion crafting screen, got %+v", resp.State)
+	}
+	if game.GoldBalance(session.Player) != 0 || len(session.SelectedVillage.Jobs) != 1 {
+		t.Fatalf("Expected the recipe charged and queued, got gold %d jobs %d",
+			game.GoldBalance(session.Player), len(session.SelectedVillage.Jobs))
+	}
+	if healPotions() != potions {
+		t.Error("Expected the potion to wait for the crafters")
+	}
+
+	// A crafter puts in 10 work a tick, so the 20-work potion takes two.
+	if result := eng.ProcessVillageJobTicks(); result != nil {
+		t.Fatalf("Expected the job still in progress, got %+v", result)
+	}
+	result := eng.ProcessVillageJobTicks()
+	if result == nil || result.JobsCompleted != 1 {
+		t.Fatalf("Expected the job completed, got %+v", result)
+	}
+	if len(session.SelectedVillage.Jobs) != 0 || session.SelectedVillage.Experience != 20 {
+		t.Errorf("Expected the queue emptied and village XP granted, got jobs %d xp %d",
+			len(session.SelectedVillage.Jobs), session.SelectedVillage.Experience)
+	}
+	if healPotions() <= potions {
+		t.Error("Expected a crafted potion in the inventory")
+	}
+}
+
+func TestBlacksmithQueuesItsBuilding(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	session.SelectedVillage = &models.Village{Name: "Testville", Level: 1}
+	session.GameState.Villages = map[string]models.Village{}
+	bp, _ := game.FindBuildingBlueprint(game.BuildingBlacksmith)
+	for name, n := range bp.Cost {
+		game.AdjustResource(session.Player, name, n, game.ReasonMonsterDrop, "")
+	}
+
+	session.State = StateBlacksmith
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "build"})
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "build"})
+	if jobs := session.SelectedVillage.Jobs; len(jobs) != 1 || jobs[0].Building != game.BuildingBlacksmith {
+		t.Fatalf("Expected one blacksmith queued, got %+v", jobs)
+	}
+	for _, opt := range resp.Options {
+		if opt.Key == "build" {
+			t.Error("Expected no build option while the blacksmith is under construction")
+		}
+	}
+}
+
+func TestOldBuildingsMoveHomeOnLoad(t *testing.T) {
+	saveFile := filepath.Join(t.TempDir(), "old_save.json")
+	player := game.GenerateCharacter("Elder", 1, 1)
+	player.VillageName = "Home"
+	player.BuiltBuildings = []models.Building{{Name: game.BuildingBlacksmith}}
+	gs := models.GameState{
+		CharactersMap: map[string]models.Character{player.Name: player},
+		Villages:      map[string]models.Village{"Home": {Name: "Home", Level: 1}, "Outpost": {Name: "Outpost", Level: 1}},
+	}
+	game.GenerateGameLocation(&gs)
+	if err := game.WriteGameStateToFile(gs, saveFile); err != nil {
+		t.Fatal(err)
+	}
+
+	eng := NewEngine()
+	sessionID, err := eng.CreateLocalSession(saveFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	saved, err := game.LoadGameStateFromFile(saveFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, outpost := saved.Villages["Home"], saved.Villages["Outpost"]
+	if game.BuildingLevel(&home, game.BuildingBlacksmith) != 1 || len(outpost.Buildings) != 0 {
+		t.Errorf("Expected the blacksmith in the home village only, got %+v and %+v", home.Buildings, outpost.Buildings)
+	}
+	if len(saved.CharactersMap["Elder"].BuiltBuildings) != 0 {
+		t.Error("Expected the old list cleared")
+	}
+}
+
+func TestVillageBuildingsMenu(t *testing.T) {
+	eng, sessionID := createTestEngine(t)
+	eng.ProcessCommand(sessionID, GameCommand{Type: "init"})
+
+	eng.mu.RLock()
+	session := eng.sessions[sessionID]
+	eng.mu.RUnlock()
+	session.SelectedVillage = &models.Village{Name: "Testville", Level: 1}
+	session.GameState.Villages = map[string]models.Village{}
+	session.Player.VillageName = "Testville"
+	bp, _ := game.FindBuildingBlueprint(game.BuildingFarm)
+	for name, n := range bp.Cost {
+		game.AdjustResource(session.Player, name, n, game.ReasonMonsterDrop, "")
+	}
+
+	session.State = StateVillageMain
+	resp := eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "13"})
+	if resp.State == nil || resp.State.Screen != "village_buildings" {
+		t.Fatalf("Expected the buildings screen, got %+v", resp.State)
+	}
+	for _, opt := range resp.Options {
+		if opt.Key == "build:"+game.BuildingLibrary && opt.Enabled {
+			t.Error("Expected the library locked at village level 1")
+		}
+	}
+
+	eng.ProcessCommand(sessionID, GameCommand{Type: "select", Value: "build:" + game.BuildingFarm})
+	jobs := session.SelectedVillage.Jobs
+	if len(jobs) != 1 || jobs[0].Kind != game.JobConstruct {
+		t.Fatalf("Expected the farm queued, got %+v", jobs)
+	}
+	for i := 0; i < 100 && len(session.SelectedVillage.Jobs) > 0; i++ {
+		eng.ProcessVillageJobTicks()
+	}
+	if game.BuildingLevel(session.SelectedVillage, game.BuildingFarm) != 1 {
+		t.Errorf("Expected a finished farm, got %+v", session.SelectedVillage.Buildings)
+	}
+}
+
+func TestGuildBankKeepsPlayerUntilSaved(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "guild.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eng := NewEngineWithStore(store, nil)
+	player := game.GenerateCharacter("Ada", 1, 1)
+	game.AdjustResource(&player, "Gold", 1000, game.ReasonMonsterDrop, "")
+	game.AdjustResource(&player, "Wood", 50, game.ReasonMonsterDrop, "")
+	player.PendingLedger = nil
+	g, err := game.NewGuild("Iron Wolves", 1, &player, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Bank.Items = []models.Item{{Name: "Shield", ItemType: "equipment"}}
+	if err := store.CreateGuild(&g); err != nil {
+		t.Fatal(err)
+	}
+	session := &GameSession{AccountID: 1, Player: &player}
+
+	resp := eng.handleGuildBank(session, GameCommand{Type: "select", Value: "witem:0:Sword"})
+	if len(player.Inventory) != 0 || !strings.Contains(resp.Messages[0].Text, "moved") {
+		t.Errorf("Expected a stale pick refused, got %+v", resp.Messages[0])
+	}
+
+	_, err = eng.updateGuildBank(session, func(g *models.Guild, me int, p *models.Character) error {
+		store.Close() // the save after this deposit fails
+		return game.DepositToGuildBank(g, p, "Wood", 50)
+	})
+	if err == nil {
+		t.Fatal("Expected the save to fail")
+	}
+	if game.ResourceBalance(&player, "Wood") != 50 || len(player.PendingLedger) != 0 {
+		t.Errorf("A failed save should leave the player alone, got %d wood and %d ledger rows",
+			game.ResourceBalance(&player, "Wood"), len(player.PendingLedger))
+	}
+}
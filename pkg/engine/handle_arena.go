@@ -78,14 +78,14 @@ func (e *Engine) handleArenaMain(session *GameSession, cmd GameCommand) GameResp
 	// Get champion
 	champion, _ := e.store.GetArenaChampion()
 
-	battlesRemaining := game.ArenaMaxBattlesPerDay - entry.BattlesToday
+	battlesRemaining := game.ArenaMaxBattlesPerDay() - entry.BattlesToday
 	if battlesRemaining < 0 {
 		battlesRemaining = 0
 	}
 
 	msgs = append(msgs, Msg("=== ARENA ===", "system"))
 	msgs = append(msgs, Msg(fmt.Sprintf("Your Rating: %d  |  W: %d  L: %d", entry.Rating, entry.Wins, entry.Losses), "system"))
-	msgs = append(msgs, Msg(fmt.Sprintf("Battles Today: %d/%d remaining", battlesRemaining, game.ArenaMaxBattlesPerDay), "system"))
+	msgs = append(msgs, Msg(fmt.Sprintf("Battles Today: %d/%d remaining", battlesRemaining, game.ArenaMaxBattlesPerDay()), "system"))
 	if champion != nil {
 		msgs = append(msgs, Msg(fmt.Sprintf("Arena Champion: %s (Rating: %d)", champion.CharacterName, champion.Rating), "narrative"))
 	}
@@ -126,7 +126,7 @@ func (e *Engine) handleArenaChallenge(session *GameSession, cmd GameCommand) Gam
 				entry.LastReset = today
 				e.store.UpsertArenaEntry(*entry)
 			}
-			remaining := game.ArenaMaxBattlesPerDay - entry.BattlesToday
+			remaining := game.ArenaMaxBattlesPerDay() - entry.BattlesToday
 			if remaining <= 0 {
 				msgs = append(msgs, Msg("No arena battles remaining today! Come back tomorrow.", "system"))
 				session.State = StateArenaMain
@@ -399,7 +399,7 @@ func (e *Engine) handleArenaDirectChallenge(session *GameSession, accountIDStr,
 		entry.LastReset = today
 		e.store.UpsertArenaEntry(*entry)
 	}
-	remaining := game.ArenaMaxBattlesPerDay - entry.BattlesToday
+	remaining := game.ArenaMaxBattlesPerDay() - entry.BattlesToday
 	if remaining <= 0 {
 		msgs = append(msgs, Msg("No arena battles remaining today! Come back tomorrow.", "system"))
 		session.State = StateArenaMain
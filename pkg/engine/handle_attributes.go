@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// handleAttributes shows the primary attributes, spends attribute points and,
+// when the screen was opened at the Training Hall, runs a paid respec.
+func (e *Engine) handleAttributes(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	msgs := []GameMessage{}
+
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.State = StateMainMenu
+		session.AtTrainingHall = false
+		return BuildMainMenuResponse(session)
+
+	case strings.HasPrefix(cmd.Value, "add:"):
+		name := strings.TrimPrefix(cmd.Value, "add:")
+		if err := game.AllocateAttribute(player, name); err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		} else {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s increased to %d.", attributeLabel(name), *game.AttributeField(&player.Attributes, name)), "levelup"))
+			e.saveSession(session)
+		}
+
+	case cmd.Value == "respec":
+		if !session.AtTrainingHall {
+			msgs = append(msgs, Msg(fmt.Sprintf("Respecs are only offered at the %s. Visit it from the Hunt menu.", game.RespecLocation), "error"))
+		} else if err := game.RespecAttributes(player); err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		} else {
+			msgs = append(msgs, Msg("The Training Hall masters reset your attributes. All points are unspent again.", "levelup"))
+			e.saveSession(session)
+		}
+	}
+
+	session.State = StateAttributes
+	resp := buildAttributesResponse(player, session.AtTrainingHall)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildAttributesResponse renders the attribute screen. The respec is only
+// offered atHall.
+func buildAttributesResponse(player *models.Character, atHall bool) GameResponse {
+	points := game.AttributePoints(player)
+	total := game.TotalAttributes(player)
+	msgs := []GameMessage{
+		Msg("============ Attributes ============", "system"),
+		Msg(fmt.Sprintf("Unspent points: %d", points), "system"),
+	}
+	options := []MenuOption{}
+	for _, name := range game.AttributeNames {
+		base := *game.AttributeField(&player.Attributes, name)
+		withItems := *game.AttributeField(&total, name)
+		line := fmt.Sprintf("%s: %d", attributeLabel(name), withItems)
+		if withItems != base {
+			line += fmt.Sprintf(" (%d allocated, +%d from items)", base, withItems-base)
+		}
+		msgs = append(msgs, Msg(line+" - "+attributeEffects[name], "system"))
+		label := fmt.Sprintf("+1 %s", attributeLabel(name))
+		if points > 0 {
+			options = append(options, Opt("add:"+name, label))
+		} else {
+			options = append(options, OptDisabled("add:"+name, label))
+		}
+	}
+
+	respec := fmt.Sprintf("Respec at the %s (%d gold)", game.RespecLocation, game.RespecCost(player))
+	if game.AttributeSum(player.Attributes) > 0 && atHall {
+		options = append(options, Opt("respec", respec))
+	} else {
+		options = append(options, OptDisabled("respec", respec))
+	}
+	options = append(options, Opt("back", "Return to Main Menu"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "attributes", Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
+// attributeEffects describes what each attribute does, for the attribute screen.
+var attributeEffects = map[string]string{
+	"strength":     fmt.Sprintf("+1 attack roll per %d, +%d%% physical skill damage each", game.StrengthPerAttackRoll, game.SkillPctPerPoint),
+	"dexterity":    fmt.Sprintf("+1%% crit per %d, +%d stamina each", game.DexterityPerCrit, game.StaminaPerDexterity),
+	"intelligence": fmt.Sprintf("+%d%% elemental skill damage and healing, +%d mana each", game.SkillPctPerPoint, game.ManaPerIntelligence),
+	"vitality":     fmt.Sprintf("+%d HP each, +1 defense roll per %d", game.HPPerVitality, game.VitalityPerDefenseRoll),
+	"luck":         fmt.Sprintf("+1%% drop chance per %d, +1%% crit per %d", game.LuckPerLootChance, game.LuckPerCrit),
+}
+
+// attributeLabel capitalizes an attribute name for display.
+func attributeLabel(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// attributesMenuLabel is the main menu entry for the attribute screen,
+// flagging unspent points.
+func attributesMenuLabel(player *models.Character) string {
+	if points := game.AttributePoints(player); points > 0 {
+		return fmt.Sprintf("Attributes (%d unspent)", points)
+	}
+	return "Attributes"
+}
@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// handleBlacksmith repairs equipment. In town it charges full price; at a
+// village Blacksmith repairs are discounted by village level, guard gear can
+// be repaired and repair kits can be crafted.
+func (e *Engine) handleBlacksmith(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	msgs := []GameMessage{}
+
+	ref := "town_blacksmith"
+	if village != nil {
+		ref = "village:" + village.Name
+	}
+	discount := game.RepairDiscount(village)
+
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		if village != nil {
+			session.State = StateVillageMain
+			return e.handleVillageMain(session, GameCommand{Type: "init"})
+		}
+		session.State = StateTownMain
+		return e.handleTownMain(session, GameCommand{Type: "init"})
+
+	case cmd.Value == "build" && village != nil:
+		msgs = append(msgs, e.queueFirstBuilding(session, game.BuildingBlacksmith)...)
+
+	case cmd.Value == "all" || strings.HasPrefix(cmd.Value, "slot:"):
+		if village != nil && !game.HasBuilding(village, game.BuildingBlacksmith) {
+			break
+		}
+		slots := game.EquipmentSlots(player.EquipmentMap)
+		if slot, err := strconv.Atoi(strings.TrimPrefix(cmd.Value, "slot:")); err == nil {
+			slots = []int{slot}
+		}
+		cost, err := game.RepairEquipment(player, player.EquipmentMap, slots, discount, ref)
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		game.RecalculatePlayerStats(player)
+		msgs = append(msgs, Msg(fmt.Sprintf("Repaired %d durability for %s.", cost.Points, repairCostText(cost)), "system"))
+		if e.metrics != nil {
+			e.metrics.RecordFeatureUse("repair")
+		}
+		e.saveBlacksmith(session)
+
+	case cmd.Value == "guards" && village != nil && game.HasBuilding(village, game.BuildingBlacksmith):
+		repaired := 0
+		for i := range village.ActiveGuards {
+			guard := &village.ActiveGuards[i]
+			if game.QuoteRepair(player, guard.EquipmentMap, game.EquipmentSlots(guard.EquipmentMap), discount).Points == 0 {
+				continue
+			}
+			cost, err := game.RepairEquipment(player, guard.EquipmentMap, game.EquipmentSlots(guard.EquipmentMap), discount, ref)
+			if err != nil {
+				msgs = append(msgs, Msg(fmt.Sprintf("%s: %s", guard.Name, err.Error()), "error"))
+				break
+			}
+			guard.StatsMod = game.CalculateItemMods(guard.EquipmentMap)
+			repaired++
+			msgs = append(msgs, Msg(fmt.Sprintf("Repaired %s's gear for %s.", guard.Name, repairCostText(cost)), "system"))
+		}
+		if repaired > 0 {
+			e.saveVillage(session)
+		}
+
+	case cmd.Value == "kit" && village != nil && game.HasBuilding(village, game.BuildingBlacksmith):
+		if err := game.CraftRepairKit(player, ref); err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		} else {
+			village.Experience += 10
+			msgs = append(msgs, Msg(fmt.Sprintf("Crafted a %s! (+10 Village XP)", game.RepairKitName), "loot"))
+			e.saveVillage(session)
+		}
+	}
+
+	session.State = StateBlacksmith
+	resp := buildBlacksmithResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// saveBlacksmith persists the session after a repair, including the village
+// when the repair happened there.
+func (e *Engine) saveBlacksmith(session *GameSession) {
+	if session.SelectedVillage != nil {
+		e.saveVillage(session)
+		return
+	}
+	e.saveSession(session)
+}
+
+// buildBlacksmithResponse lists equipment durability and repair prices.
+func buildBlacksmithResponse(session *GameSession) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	discount := game.RepairDiscount(village)
+
+	msgs := []GameMessage{Msg("============ Blacksmith ============", "system")}
+	options := []MenuOption{}
+
+	if village != nil && !game.HasBuilding(village, game.BuildingBlacksmith) {
+		gate, gateOptions := missingBuildingMenu(village, game.BuildingBlacksmith)
+		msgs = append(msgs, gate...)
+		options = append(options, gateOptions...)
+		return GameResponse{
+			Type:     "menu",
+			Messages: msgs,
+			State:    &StateData{Screen: "blacksmith", Player: MakePlayerState(player)},
+			Options:  options,
+		}
+	}
+
+	if discount > 0 {
+		msgs = append(msgs, Msg(fmt.Sprintf("Village discount: %d%% off repair gold", discount), "system"))
+	}
+	msgs = append(msgs, Msg(fmt.Sprintf("Gold: %d | Beast materials: %d | Repair kits: %d (cover %d durability each)",
+		game.GoldBalance(player), game.BeastMaterialStock(player), game.CountRepairKits(player.Inventory), game.RepairKitPoints), "system"))
+
+	for _, slot := range game.EquipmentSlots(player.EquipmentMap) {
+		item := player.EquipmentMap[slot]
+		game.EnsureDurability(&item)
+		player.EquipmentMap[slot] = item
+		msgs = append(msgs, Msg(fmt.Sprintf("  [%s] %s - %s", SlotNames[slot], item.Name, game.DurabilityLabel(item)), "system"))
+		if game.MissingDurability(item) > 0 {
+			cost := game.QuoteRepair(player, player.EquipmentMap, []int{slot}, discount)
+			options = append(options, Opt(fmt.Sprintf("slot:%d", slot), fmt.Sprintf("Repair %s (%s)", item.Name, repairCostText(cost))))
+		}
+	}
+	if all := game.QuoteRepair(player, player.EquipmentMap, game.EquipmentSlots(player.EquipmentMap), discount); all.Points > 0 {
+		options = append(options, Opt("all", fmt.Sprintf("Repair All (%s)", repairCostText(all))))
+	} else {
+		msgs = append(msgs, Msg("All your equipment is in good repair.", "system"))
+	}
+
+	if village != nil {
+		guardCost := game.RepairCost{}
+		for _, g := range village.ActiveGuards {
+			c := game.QuoteRepair(player, g.EquipmentMap, game.EquipmentSlots(g.EquipmentMap), discount)
+			guardCost.Points += c.Points
+			guardCost.Gold += c.Gold
+			guardCost.Materials += c.Materials
+		}
+		if guardCost.Points > 0 {
+			options = append(options, Opt("guards", fmt.Sprintf("Repair Guard Gear (%d durability, about %d gold, %d materials)",
+				guardCost.Points, guardCost.Gold, guardCost.Materials)))
+		}
+		options = append(options, Opt("kit", fmt.Sprintf("Craft %s (%d Iron, %d beast materials)",
+			game.RepairKitName, game.RepairKitIronCost, game.RepairKitMaterialCost)))
+	}
+	options = append(options, Opt("back", "Back"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "blacksmith", Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
+// repairCostText renders a repair quote.
+func repairCostText(cost game.RepairCost) string {
+	parts := []string{fmt.Sprintf("%d gold", cost.Gold)}
+	if cost.Materials > 0 {
+		parts = append(parts, fmt.Sprintf("%d materials", cost.Materials))
+	}
+	if cost.Kits > 0 {
+		parts = append(parts, fmt.Sprintf("%d kits", cost.Kits))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// wearPlayerGear applies combat wear, and the death penalty when the player
+// died, to the player's equipment and reports anything that broke.
+func wearPlayerGear(player *models.Character, died bool) []GameMessage {
+	broken := game.WearEquipment(player.EquipmentMap, game.CombatWear)
+	if died {
+		broken = append(broken, game.WearOnDeath(player)...)
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+	game.RecalculatePlayerStats(player)
+	msgs := []GameMessage{}
+	for _, name := range broken {
+		msgs = append(msgs, Msg(fmt.Sprintf("Your %s has broken! Repair it at a blacksmith.", name), "error"))
+	}
+	return msgs
+}
@@ -205,8 +205,7 @@ func (e *Engine) handleMostWantedHunt(session *GameSession, cmd GameCommand) Gam
 		return e.handleMostWantedBoard(session, GameCommand{Type: "init"})
 	}
 
-	goldRes.Stock -= goldCost
-	player.ResourceStorageMap["Gold"] = goldRes
+	game.AdjustGold(player, -goldCost, game.ReasonBounty, "monster:"+mob.Name)
 
 	return e.startCombat(session, &loc, mobIdx, mob)
 }
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// handleVillageBuildings lists the village buildings and queues their
+// construction and upgrades.
+func (e *Engine) handleVillageBuildings(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	msgs := []GameMessage{}
+
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.State = StateVillageMain
+		return e.handleVillageMain(session, GameCommand{Type: "init"})
+
+	case action == "build":
+		bp, ok := game.FindBuildingBlueprint(arg)
+		if !ok {
+			break
+		}
+		job, err := game.QueueBuilding(player, village, bp)
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		msgs = append(msgs, jobQueuedMessages(village, job)...)
+		e.saveVillage(session)
+	}
+
+	session.State = StateVillageBuildings
+	resp := buildVillageBuildingsResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildVillageBuildingsResponse shows each building's level, effect, next
+// cost and upkeep.
+func buildVillageBuildingsResponse(session *GameSession) GameResponse {
+	village := session.SelectedVillage
+
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg("VILLAGE BUILDINGS", "system"),
+		Msg("============================================================", "system"),
+		Msg("Builders raise buildings through the work queue. Each level costs more and adds upkeep,", "system"),
+		Msg("paid every village tick; a building whose upkeep can't be paid stands idle.", "system"),
+		Msg("Upkeep per tick: "+refundText(game.TotalBuildingUpkeep(village)), "system"),
+		Msg("", "system"),
+	}
+
+	options := []MenuOption{}
+	for _, bp := range game.BuildingBlueprints {
+		status := ""
+		if b := game.FindVillageBuilding(village, bp.Name); b != nil && b.Unpaid {
+			status = " - IDLE (upkeep unpaid)"
+		}
+		level := game.PlannedBuildingLevel(village, bp.Name)
+		msgs = append(msgs, Msg(fmt.Sprintf("%s (Level %d/%d)%s - %s", bp.Name, level, game.MaxBuildingLevel, status, bp.Description), "system"))
+
+		label := fmt.Sprintf("Build %s", bp.Name)
+		if level > 0 {
+			label = fmt.Sprintf("Upgrade %s to level %d", bp.Name, level+1)
+		}
+		switch {
+		case village.Level < bp.MinVillageLevel:
+			msgs = append(msgs, Msg(fmt.Sprintf("   Needs village level %d", bp.MinVillageLevel), "system"))
+			options = append(options, OptDisabled("build:"+bp.Name, fmt.Sprintf("%s (needs village level %d)", label, bp.MinVillageLevel)))
+		case level >= game.MaxBuildingLevel:
+			options = append(options, OptDisabled("build:"+bp.Name, bp.Name+" is fully upgraded"))
+		default:
+			msgs = append(msgs, Msg(fmt.Sprintf("   Next level: %s | Upkeep: %s per tick",
+				game.MaterialsText(game.BuildingCost(bp, level+1)), game.MaterialsText(game.BuildingUpkeep(bp, level+1))), "system"))
+			options = append(options, Opt("build:"+bp.Name, label))
+		}
+	}
+	options = append(options, Opt("back", "Back"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "village_buildings", Player: MakePlayerState(session.Player), Village: MakeVillageView(village)},
+		Options:  options,
+	}
+}
+
+// queueFirstBuilding queues construction of a building a village screen
+// needs, unless one is already standing or on the way.
+func (e *Engine) queueFirstBuilding(session *GameSession, name string) []GameMessage {
+	village := session.SelectedVillage
+	bp, ok := game.FindBuildingBlueprint(name)
+	if !ok || game.PlannedBuildingLevel(village, name) > 0 {
+		return nil
+	}
+	job, err := game.QueueBuilding(session.Player, village, bp)
+	if err != nil {
+		return []GameMessage{Msg(err.Error(), "error")}
+	}
+	e.saveVillage(session)
+	return jobQueuedMessages(village, job)
+}
+
+// missingBuildingMenu explains why a screen needs a building the village
+// can't use yet and, if none is on the way, offers to queue one.
+func missingBuildingMenu(village *models.Village, name string) ([]GameMessage, []MenuOption) {
+	bp, _ := game.FindBuildingBlueprint(name)
+	options := []MenuOption{}
+	var msg string
+	switch b := game.FindVillageBuilding(village, name); {
+	case b != nil && b.Unpaid:
+		msg = fmt.Sprintf("Your %s stands idle until its upkeep is paid (%s per tick).",
+			name, game.MaterialsText(game.BuildingUpkeep(bp, b.Level)))
+	case game.PlannedBuildingLevel(village, name) > 0:
+		msg = fmt.Sprintf("Your %s is under construction. Check the work queue.", name)
+	default:
+		msg = fmt.Sprintf("Your village has no %s yet (%s).", name, bp.Description)
+		options = append(options, Opt("build", fmt.Sprintf("Build %s (%s)", name, game.MaterialsText(game.BuildingCost(bp, 1)))))
+	}
+	return []GameMessage{Msg(msg, "system")}, append(options, Opt("back", "Back"))
+}
+
+// migrateBuiltBuildings moves buildings an older save kept on the loaded
+// character into its home village. A character without a village keeps
+// them until it founds one. The caller saves the session.
+func migrateBuiltBuildings(session *GameSession) {
+	player := session.Player
+	village, ok := session.GameState.Villages[player.VillageName]
+	if ok && game.MigrateBuiltBuildings(player, &village) {
+		session.GameState.Villages[player.VillageName] = village
+	}
+}
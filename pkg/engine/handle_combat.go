@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"strconv"
 
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/data"
 	"rpg-game/pkg/game"
 	"rpg-game/pkg/models"
@@ -17,10 +18,17 @@ func (e *Engine) handleCombatGuardPrompt(session *GameSession, cmd GameCommand)
 	mob := &combat.Mob
 	msgs := []GameMessage{}
 
-	if cmd.Value == "y" || cmd.Value == "Y" {
+	if stance, ok := guardStanceChoice(cmd.Value); ok {
+		combat.HasGuards = true
+		game.SetGuardStance(combat.CombatGuards, stance.ID)
 		msgs = append(msgs, Msg("Guards are joining the battle!", "system"))
+		msgs = append(msgs, Msg(fmt.Sprintf("Orders: %s - %s", stance.Name, stance.Description), "system"))
 		for _, g := range combat.CombatGuards {
-			msgs = append(msgs, Msg(fmt.Sprintf("  %s (Lv%d, HP:%d) ready for combat", g.Name, g.Level, g.HitPoints), "system"))
+			line := fmt.Sprintf("  %s (Lv%d, HP:%d) ready for combat", g.Name, g.Level, g.HitPoints)
+			if g.Skill != nil {
+				line += fmt.Sprintf(" [%s]", g.Skill.Name)
+			}
+			msgs = append(msgs, Msg(line, "system"))
 		}
 	} else {
 		combat.CombatGuards = []models.Guard{}
@@ -48,6 +56,29 @@ func (e *Engine) handleCombatGuardPrompt(session *GameSession, cmd GameCommand)
 	}
 }
 
+// guardStanceChoice maps a guard prompt answer to the stance ordered: "y"
+// brings guards in the default stance, a number picks a stance from
+// data.GuardStances.
+func guardStanceChoice(value string) (models.GuardStance, bool) {
+	if value == "y" || value == "Y" {
+		return game.FindGuardStance(""), true
+	}
+	if idx, err := strconv.Atoi(value); err == nil && idx >= 1 && idx <= len(data.GuardStances) {
+		return data.GuardStances[idx-1], true
+	}
+	return models.GuardStance{}, false
+}
+
+// guardPromptOptions lists one option per stance guards can be ordered into,
+// then fighting alone.
+func guardPromptOptions() []MenuOption {
+	options := []MenuOption{}
+	for i, stance := range data.GuardStances {
+		options = append(options, Opt(strconv.Itoa(i+1), fmt.Sprintf("Bring guards: %s (%s)", stance.Name, stance.Description)))
+	}
+	return append(options, Opt("n", "No, fight alone"))
+}
+
 // handleCombatAction processes one full combat turn based on the player's chosen action.
 func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameResponse {
 	combat := session.Combat
@@ -60,77 +91,8 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 	combat.IsDefending = false
 	msgs = append(msgs, Msg(fmt.Sprintf("--- Turn %d ---", combat.Turn), "system"))
 
-	// =====================================================================
-	// Process status effects for player (inline, no calls to game.ProcessStatusEffects)
-	// =====================================================================
-	for i := len(player.StatusEffects) - 1; i >= 0; i-- {
-		effect := &player.StatusEffects[i]
-		switch effect.Type {
-		case "poison":
-			player.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", player.Name, effect.Potency), "damage"))
-		case "burn":
-			player.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", player.Name, effect.Potency), "damage"))
-		case "regen":
-			player.HitpointsRemaining += effect.Potency
-			if player.HitpointsRemaining > player.HitpointsTotal {
-				player.HitpointsRemaining = player.HitpointsTotal
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s regenerates %d HP!", player.Name, effect.Potency), "heal"))
-		case "buff_attack":
-			// already applied when effect was first added
-		case "buff_defense":
-			// already applied when effect was first added
-		}
-		effect.Duration--
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				player.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				player.StatsMod.DefenseMod -= effect.Potency
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s effect has worn off.", player.Name, effect.Type), "debuff"))
-			player.StatusEffects = append(player.StatusEffects[:i], player.StatusEffects[i+1:]...)
-		}
-	}
-
-	// =====================================================================
-	// Process status effects for mob (inline)
-	// =====================================================================
-	for i := len(mob.StatusEffects) - 1; i >= 0; i-- {
-		effect := &mob.StatusEffects[i]
-		switch effect.Type {
-		case "poison":
-			mob.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", mob.Name, effect.Potency), "damage"))
-		case "burn":
-			mob.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", mob.Name, effect.Potency), "damage"))
-		case "regen":
-			mob.HitpointsRemaining += effect.Potency
-			if mob.HitpointsRemaining > mob.HitpointsTotal {
-				mob.HitpointsRemaining = mob.HitpointsTotal
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s regenerates %d HP!", mob.Name, effect.Potency), "heal"))
-		case "buff_attack":
-			// already applied
-		case "buff_defense":
-			// already applied
-		}
-		effect.Duration--
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				mob.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				mob.StatsMod.DefenseMod -= effect.Potency
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s effect has worn off.", mob.Name, effect.Type), "debuff"))
-			mob.StatusEffects = append(mob.StatusEffects[:i], mob.StatusEffects[i+1:]...)
-		}
-	}
+	// Status effects tick for the player, the monster and any guards
+	msgs = append(msgs, tickCombatStatuses(combat, player)...)
 
 	// Check if mob died from effects
 	if mob.HitpointsRemaining <= 0 {
@@ -142,14 +104,30 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 		return e.resolveCombatLoss(session, msgs)
 	}
 
+	// Foes faster than the player move before the player's first action
+	if cmd.Value == "1" || cmd.Value == "2" || cmd.Value == "5" {
+		var over *GameResponse
+		if msgs, over = e.playOpeningTurns(session, msgs); over != nil {
+			return *over
+		}
+	}
+
 	// =====================================================================
 	// Check if player is stunned
 	// =====================================================================
 	if game.IsStunned(player) {
 		msgs = append(msgs, Msg(fmt.Sprintf("%s is STUNNED and cannot act!", player.Name), "debuff"))
-		playerDef := game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-		monsterMsgs := e.processMonsterTurnMsgs(session, playerDef)
-		msgs = append(msgs, monsterMsgs...)
+		if name := combat.PlayerSkills.InterruptCharge(); name != "" {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s is interrupted!", player.Name, name), "debuff"))
+		}
+		playerDef := game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+		msgs = append(msgs, e.playOthersTurns(session, playerDef)...)
+		if mob.HitpointsRemaining <= 0 {
+			return e.resolveCombatWin(session, msgs)
+		}
+		if combat.MobFled {
+			return e.resolveMobFled(session, msgs)
+		}
 
 		if player.HitpointsRemaining <= 0 {
 			return e.resolveCombatLoss(session, msgs)
@@ -167,6 +145,16 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 		}
 	}
 
+	// A charging skill takes the player's turn until it goes off
+	if combat.PlayerSkills.Charging != nil && cmd.Value != "6" && cmd.Value != "7" {
+		if skill, ready := combat.PlayerSkills.ContinueCharge(combat.Turn); ready {
+			msgs = append(msgs, e.castPlayerSkill(combat, player, skill)...)
+		} else {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s keeps charging %s...", player.Name, combat.PlayerSkills.Charging.Name), "combat"))
+		}
+		return e.finishPlayerTurn(session, msgs, game.MultiRoll(player.DefenseRolls)+game.DefenseMod(player))
+	}
+
 	// =====================================================================
 	// Process player action
 	// =====================================================================
@@ -175,9 +163,9 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 
 	switch cmd.Value {
 	case "1": // Attack
-		playerAttack := game.MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-		playerDef = game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-		isCrit := rand.Intn(100) < 15
+		playerAttack := game.MultiRoll(player.AttackRolls) + game.AttackMod(player)
+		playerDef = game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+		isCrit := game.RollPlayerCrit(player)
 		if isCrit {
 			playerAttack *= 2
 			msgs = append(msgs, Msg("*** CRITICAL HIT! ***", "combat"))
@@ -185,7 +173,7 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 				e.metrics.RecordCrit(true)
 			}
 		}
-		mobDef := game.MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+		mobDef := game.MultiRoll(mob.DefenseRolls) + game.DefenseMod(mob)
 		if playerAttack > mobDef {
 			diff := game.ApplyDamage(playerAttack-mobDef, models.Physical, mob)
 			mob.HitpointsRemaining -= diff
@@ -193,6 +181,9 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 			if e.metrics != nil {
 				e.metrics.RecordDamage(diff, "physical", true)
 			}
+			for _, line := range game.ApplyOnHitEffects(player, mob, diff) {
+				msgs = append(msgs, Msg(line, "damage"))
+			}
 		} else {
 			msgs = append(msgs, Msg(fmt.Sprintf("%s's attack missed!", player.Name), "combat"))
 		}
@@ -202,14 +193,17 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 		if e.metrics != nil {
 			e.metrics.RecordDefend()
 		}
-		playerAttack := (game.MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod) / 2
-		playerDef = int(float64(game.MultiRoll(player.DefenseRolls)+player.StatsMod.DefenseMod) * 1.5)
+		playerAttack := (game.MultiRoll(player.AttackRolls) + game.AttackMod(player)) / 2
+		playerDef = int(float64(game.MultiRoll(player.DefenseRolls)+game.DefenseMod(player)) * 1.5)
 		msgs = append(msgs, Msg(fmt.Sprintf("%s takes a defensive stance!", player.Name), "combat"))
-		mobDef := game.MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+		mobDef := game.MultiRoll(mob.DefenseRolls) + game.DefenseMod(mob)
 		if playerAttack > mobDef {
 			diff := game.ApplyDamage(playerAttack-mobDef, models.Physical, mob)
 			mob.HitpointsRemaining -= diff
 			msgs = append(msgs, Msg(fmt.Sprintf("%s counterattacks for %d damage!", player.Name, diff), "damage"))
+			for _, line := range game.ApplyOnHitEffects(player, mob, diff) {
+				msgs = append(msgs, Msg(line, "damage"))
+			}
 		} else {
 			msgs = append(msgs, Msg(fmt.Sprintf("%s's counterattack missed!", player.Name), "combat"))
 		}
@@ -254,7 +248,7 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 				default:
 					label = fmt.Sprintf("%s (Heals %d HP)", item.Name, item.Consumable.Value)
 				}
-				options = append(options, Opt(strconv.Itoa(idx), label))
+				options = append(options, Opt(strconv.Itoa(idx), label+stackSuffix(item)))
 			}
 		}
 		options = append(options, Opt("0", "Cancel"))
@@ -270,8 +264,12 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 		}
 
 	case "4": // Use Skill - switch to skill select
-		if len(player.LearnedSkills) == 0 {
-			msgs = append(msgs, Msg("No skills learned!", "system"))
+		if len(player.LearnedSkills) == 0 || game.IsSilenced(player.StatusEffects) {
+			if len(player.LearnedSkills) == 0 {
+				msgs = append(msgs, Msg("No skills learned!", "system"))
+			} else {
+				msgs = append(msgs, Msg(fmt.Sprintf("%s is SILENCED and cannot use skills!", player.Name), "debuff"))
+			}
 			combat.Turn--
 			session.State = StateCombat
 			return GameResponse{
@@ -290,7 +288,7 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 		options := []MenuOption{}
 		for idx, skill := range player.LearnedSkills {
 			canAfford := skill.ManaCost <= player.ManaRemaining && skill.StaminaCost <= player.StaminaRemaining
-			label := skill.Name
+			label := fmt.Sprintf("%s R%d", skill.Name, game.SkillRank(skill))
 			if skill.UpgradeCount > 0 {
 				label += fmt.Sprintf(" +%d", skill.UpgradeCount)
 			}
@@ -300,10 +298,16 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 			if skill.StaminaCost > 0 {
 				label += fmt.Sprintf(" %dSP", skill.StaminaCost)
 			}
+			if charge := game.SkillChargeTurns(skill); charge > 0 {
+				label += fmt.Sprintf(" (charges %d)", charge)
+			}
 			label += " | " + skill.Description
-			if canAfford {
+			switch left := combat.PlayerSkills.CooldownLeft(skill.Name, combat.Turn+1); {
+			case left > 0:
+				options = append(options, OptDisabled(strconv.Itoa(idx+1), label+fmt.Sprintf(" [cooldown: %d turns]", left)))
+			case canAfford:
 				options = append(options, Opt(strconv.Itoa(idx+1), label))
-			} else {
+			default:
 				options = append(options, OptDisabled(strconv.Itoa(idx+1), label+" [insufficient resources]"))
 			}
 		}
@@ -381,19 +385,22 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 			e.metrics.RecordFlee(false)
 		}
 		msgs = append(msgs, Msg(fmt.Sprintf("%s tried to flee but failed!", player.Name), "combat"))
-		playerDef = game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+		playerDef = game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
 		// Skip to monster turn (failed flee = no player action)
 		skipMonsterTurn = false
 
 	default: // Invalid action, default to attack
 		msgs = append(msgs, Msg("Invalid action! Defaulting to Attack.", "system"))
-		playerAttack := game.MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-		playerDef = game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-		mobDef := game.MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+		playerAttack := game.MultiRoll(player.AttackRolls) + game.AttackMod(player)
+		playerDef = game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+		mobDef := game.MultiRoll(mob.DefenseRolls) + game.DefenseMod(mob)
 		if playerAttack > mobDef {
 			diff := game.ApplyDamage(playerAttack-mobDef, models.Physical, mob)
 			mob.HitpointsRemaining -= diff
 			msgs = append(msgs, Msg(fmt.Sprintf("%s attacks for %d damage!", player.Name, diff), "damage"))
+			for _, line := range game.ApplyOnHitEffects(player, mob, diff) {
+				msgs = append(msgs, Msg(line, "damage"))
+			}
 		} else {
 			msgs = append(msgs, Msg(fmt.Sprintf("%s's attack missed!", player.Name), "combat"))
 		}
@@ -417,26 +424,16 @@ func (e *Engine) handleCombatAction(session *GameSession, cmd GameCommand) GameR
 		return e.resolveCombatWin(session, msgs)
 	}
 
-	// Guard attacks (if guards present and mob still alive)
-	if combat.HasGuards && len(combat.CombatGuards) > 0 && mob.HitpointsRemaining > 0 {
-		msgs = append(msgs, Msg("--- Guard Support ---", "system"))
-		// Call game.GuardAttack for state mutation (it prints to stdout, which is fine)
-		guardDamage := game.GuardAttack(combat.CombatGuards, mob)
-		if guardDamage > 0 {
-			msgs = append(msgs, Msg(fmt.Sprintf("Guards deal %d total damage!", guardDamage), "damage"))
-		}
-	}
-
-	// Check if mob died from guard attacks
+	// =====================================================================
+	// Guards and the monster act until the player's next turn
+	// =====================================================================
+	msgs = append(msgs, e.playOthersTurns(session, playerDef)...)
 	if mob.HitpointsRemaining <= 0 {
 		return e.resolveCombatWin(session, msgs)
 	}
-
-	// =====================================================================
-	// Monster turn
-	// =====================================================================
-	monsterMsgs := e.processMonsterTurnMsgs(session, playerDef)
-	msgs = append(msgs, monsterMsgs...)
+	if combat.MobFled {
+		return e.resolveMobFled(session, msgs)
+	}
 
 	// Check if player died from monster attack
 	if player.HitpointsRemaining <= 0 {
@@ -489,10 +486,7 @@ func (e *Engine) handleCombatItemSelect(session *GameSession, cmd GameCommand) G
 	}
 
 	// Use the selected item
-	selectedItem := consumables[itemIdx-1]
-	originalIdx := consumableIndices[itemIdx-1]
-	game.UseConsumableItem(selectedItem, player)
-	game.RemoveItemFromInventory(&player.Inventory, originalIdx)
+	selectedItem, _ := game.UseInventoryItem(player, consumableIndices[itemIdx-1], game.ReasonItemUsed, "combat")
 	if e.metrics != nil {
 		e.metrics.RecordItemUse(selectedItem.Name)
 	}
@@ -511,65 +505,8 @@ func (e *Engine) handleCombatItemSelect(session *GameSession, cmd GameCommand) G
 
 	session.State = StateCombat
 
-	// Process status effects for player (inline)
-	for i := len(player.StatusEffects) - 1; i >= 0; i-- {
-		effect := &player.StatusEffects[i]
-		switch effect.Type {
-		case "poison":
-			player.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", player.Name, effect.Potency), "damage"))
-		case "burn":
-			player.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", player.Name, effect.Potency), "damage"))
-		case "regen":
-			player.HitpointsRemaining += effect.Potency
-			if player.HitpointsRemaining > player.HitpointsTotal {
-				player.HitpointsRemaining = player.HitpointsTotal
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s regenerates %d HP!", player.Name, effect.Potency), "heal"))
-		}
-		effect.Duration--
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				player.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				player.StatsMod.DefenseMod -= effect.Potency
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s effect has worn off.", player.Name, effect.Type), "debuff"))
-			player.StatusEffects = append(player.StatusEffects[:i], player.StatusEffects[i+1:]...)
-		}
-	}
-
-	// Process status effects for mob (inline)
-	for i := len(mob.StatusEffects) - 1; i >= 0; i-- {
-		effect := &mob.StatusEffects[i]
-		switch effect.Type {
-		case "poison":
-			mob.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", mob.Name, effect.Potency), "damage"))
-		case "burn":
-			mob.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", mob.Name, effect.Potency), "damage"))
-		case "regen":
-			mob.HitpointsRemaining += effect.Potency
-			if mob.HitpointsRemaining > mob.HitpointsTotal {
-				mob.HitpointsRemaining = mob.HitpointsTotal
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s regenerates %d HP!", mob.Name, effect.Potency), "heal"))
-		}
-		effect.Duration--
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				mob.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				mob.StatsMod.DefenseMod -= effect.Potency
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s effect has worn off.", mob.Name, effect.Type), "debuff"))
-			mob.StatusEffects = append(mob.StatusEffects[:i], mob.StatusEffects[i+1:]...)
-		}
-	}
+	// Status effects tick for the player, the monster and any guards
+	msgs = append(msgs, tickCombatStatuses(combat, player)...)
 
 	// Check if mob died from effects
 	if mob.HitpointsRemaining <= 0 {
@@ -581,24 +518,15 @@ func (e *Engine) handleCombatItemSelect(session *GameSession, cmd GameCommand) G
 		return e.resolveCombatLoss(session, msgs)
 	}
 
-	// Guard attacks
-	if combat.HasGuards && len(combat.CombatGuards) > 0 && mob.HitpointsRemaining > 0 {
-		msgs = append(msgs, Msg("--- Guard Support ---", "system"))
-		guardDamage := game.GuardAttack(combat.CombatGuards, mob)
-		if guardDamage > 0 {
-			msgs = append(msgs, Msg(fmt.Sprintf("Guards deal %d total damage!", guardDamage), "damage"))
-		}
-	}
-
-	// Check if mob died from guard attacks
+	// Guards and the monster act - player defense is normal (item usage doesn't boost defense)
+	playerDef := game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+	msgs = append(msgs, e.playOthersTurns(session, playerDef)...)
 	if mob.HitpointsRemaining <= 0 {
 		return e.resolveCombatWin(session, msgs)
 	}
-
-	// Monster turn - player defense is normal (item usage doesn't boost defense)
-	playerDef := game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-	monsterMsgs := e.processMonsterTurnMsgs(session, playerDef)
-	msgs = append(msgs, monsterMsgs...)
+	if combat.MobFled {
+		return e.resolveMobFled(session, msgs)
+	}
 
 	// Check if player died
 	if player.HitpointsRemaining <= 0 {
@@ -657,6 +585,20 @@ func (e *Engine) handleCombatSkillSelect(session *GameSession, cmd GameCommand)
 			Options: combatActionOptions(),
 		}
 	}
+	if game.IsSilenced(player.StatusEffects) {
+		msgs = append(msgs, Msg(fmt.Sprintf("%s is SILENCED and cannot use skills!", player.Name), "error"))
+		session.State = StateCombat
+		return GameResponse{
+			Type:     "combat",
+			Messages: msgs,
+			State: &StateData{
+				Screen: "combat",
+				Player: MakePlayerState(player),
+				Combat: MakeCombatView(session),
+			},
+			Options: combatActionOptions(),
+		}
+	}
 	if skill.StaminaCost > player.StaminaRemaining {
 		msgs = append(msgs, Msg("Not enough stamina!", "error"))
 		session.State = StateCombat
@@ -671,6 +613,20 @@ func (e *Engine) handleCombatSkillSelect(session *GameSession, cmd GameCommand)
 			Options: combatActionOptions(),
 		}
 	}
+	if left := combat.PlayerSkills.CooldownLeft(skill.Name, combat.Turn+1); left > 0 {
+		msgs = append(msgs, Msg(fmt.Sprintf("%s is on cooldown for %d more turn(s)!", skill.Name, left), "error"))
+		session.State = StateCombat
+		return GameResponse{
+			Type:     "combat",
+			Messages: msgs,
+			State: &StateData{
+				Screen: "combat",
+				Player: MakePlayerState(player),
+				Combat: MakeCombatView(session),
+			},
+			Options: combatActionOptions(),
+		}
+	}
 
 	// Consume the turn
 	combat.Turn++
@@ -678,65 +634,8 @@ func (e *Engine) handleCombatSkillSelect(session *GameSession, cmd GameCommand)
 
 	msgs = append(msgs, Msg(fmt.Sprintf("--- Turn %d ---", combat.Turn), "system"))
 
-	// Process status effects for player (inline)
-	for i := len(player.StatusEffects) - 1; i >= 0; i-- {
-		effect := &player.StatusEffects[i]
-		switch effect.Type {
-		case "poison":
-			player.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", player.Name, effect.Potency), "damage"))
-		case "burn":
-			player.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", player.Name, effect.Potency), "damage"))
-		case "regen":
-			player.HitpointsRemaining += effect.Potency
-			if player.HitpointsRemaining > player.HitpointsTotal {
-				player.HitpointsRemaining = player.HitpointsTotal
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s regenerates %d HP!", player.Name, effect.Potency), "heal"))
-		}
-		effect.Duration--
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				player.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				player.StatsMod.DefenseMod -= effect.Potency
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s effect has worn off.", player.Name, effect.Type), "debuff"))
-			player.StatusEffects = append(player.StatusEffects[:i], player.StatusEffects[i+1:]...)
-		}
-	}
-
-	// Process status effects for mob (inline)
-	for i := len(mob.StatusEffects) - 1; i >= 0; i-- {
-		effect := &mob.StatusEffects[i]
-		switch effect.Type {
-		case "poison":
-			mob.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", mob.Name, effect.Potency), "damage"))
-		case "burn":
-			mob.HitpointsRemaining -= effect.Potency
-			msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", mob.Name, effect.Potency), "damage"))
-		case "regen":
-			mob.HitpointsRemaining += effect.Potency
-			if mob.HitpointsRemaining > mob.HitpointsTotal {
-				mob.HitpointsRemaining = mob.HitpointsTotal
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s regenerates %d HP!", mob.Name, effect.Potency), "heal"))
-		}
-		effect.Duration--
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				mob.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				mob.StatsMod.DefenseMod -= effect.Potency
-			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's %s effect has worn off.", mob.Name, effect.Type), "debuff"))
-			mob.StatusEffects = append(mob.StatusEffects[:i], mob.StatusEffects[i+1:]...)
-		}
-	}
+	// Status effects tick for the player, the monster and any guards
+	msgs = append(msgs, tickCombatStatuses(combat, player)...)
 
 	// Check if mob died from effects
 	if mob.HitpointsRemaining <= 0 {
@@ -748,26 +647,49 @@ func (e *Engine) handleCombatSkillSelect(session *GameSession, cmd GameCommand)
 		return e.resolveCombatLoss(session, msgs)
 	}
 
+	// Foes faster than the player move before the player's first action
+	msgs, over := e.playOpeningTurns(session, msgs)
+	if over != nil {
+		return *over
+	}
+
 	// Deduct skill costs
 	player.ManaRemaining -= skill.ManaCost
 	player.StaminaRemaining -= skill.StaminaCost
-	msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s!", player.Name, skill.Name), "combat"))
+	if game.SkillChargeTurns(skill) > 0 {
+		combat.PlayerSkills.BeginCharge(skill, combat.Turn)
+		msgs = append(msgs, Msg(fmt.Sprintf("%s begins charging %s!", player.Name, skill.Name), "combat"))
+	} else {
+		msgs = append(msgs, e.castPlayerSkill(combat, player, skill)...)
+	}
+
+	return e.finishPlayerTurn(session, msgs, game.MultiRoll(player.DefenseRolls)+game.DefenseMod(player))
+}
+
+// castPlayerSkill resolves a skill the player has paid for: combo, damage or
+// healing, status effect and skill experience.
+func (e *Engine) castPlayerSkill(combat *CombatContext, player *models.Character, skill models.Skill) []GameMessage {
+	mob := &combat.Mob
+	msgs := []GameMessage{Msg(fmt.Sprintf("%s uses %s!", player.Name, skill.Name), "combat")}
 	if e.metrics != nil {
 		e.metrics.RecordSkillUse(skill.Name)
 	}
 
-	// Apply skill effects
-	if skill.Damage < 0 {
+	cast := combat.PlayerSkills.Cast(skill, game.SkillPower(player, skill), mob, combat.Turn)
+	msgs = append(msgs, statusMsgs(cast.Messages)...)
+	if cast.Power < 0 {
 		// Healing skill
-		healAmount := -skill.Damage
+		healAmount := -cast.Power
 		player.HitpointsRemaining += healAmount
 		if player.HitpointsRemaining > player.HitpointsTotal {
 			player.HitpointsRemaining = player.HitpointsTotal
 		}
 		msgs = append(msgs, Msg(fmt.Sprintf("%s heals for %d HP!", player.Name, healAmount), "heal"))
-	} else if skill.Damage > 0 {
+	} else if cast.Power > 0 {
 		// Damage skill
-		finalDamage := game.ApplyDamage(skill.Damage, skill.DamageType, mob)
+		damage, reactionMsgs := e.applyElements(combat, cast.Power, skill.DamageType, mob)
+		msgs = append(msgs, reactionMsgs...)
+		finalDamage := game.ApplyDamage(damage, skill.DamageType, mob)
 		mob.HitpointsRemaining -= finalDamage
 		if e.metrics != nil {
 			e.metrics.RecordDamage(finalDamage, string(skill.DamageType), true)
@@ -787,49 +709,166 @@ func (e *Engine) handleCombatSkillSelect(session *GameSession, cmd GameCommand)
 	}
 
 	// Apply status effects from skill
-	if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-		if e.metrics != nil {
-			e.metrics.RecordStatusEffect(skill.Effect.Type)
-		}
-		if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" || skill.Effect.Type == "regen" {
-			// Buff on player
-			player.StatusEffects = append(player.StatusEffects, skill.Effect)
-			if skill.Effect.Type == "buff_attack" {
-				player.StatsMod.AttackMod += skill.Effect.Potency
-			} else if skill.Effect.Type == "buff_defense" {
-				player.StatsMod.DefenseMod += skill.Effect.Potency
+	msgs = append(msgs, e.applySkillEffect(player, mob, skill.Effect)...)
+
+	for _, line := range game.TrainSkill(player, skill.Name, game.CastXP(cast)) {
+		msgs = append(msgs, Msg(line, "levelup"))
+	}
+	return msgs
+}
+
+// turnOrder lists everyone taking turns in the fight: the player first (so
+// the player wins initiative ties), the monster, then any standing guards on
+// either side.
+func turnOrder(combat *CombatContext, player *models.Character, withGuards bool) []game.Combatant {
+	cs := []game.Combatant{
+		{ID: "player", Name: player.Name, Speed: game.Speed(player)},
+		{ID: "mob", Name: combat.Mob.Name, Speed: game.Speed(&combat.Mob)},
+	}
+	if withGuards && combat.HasGuards {
+		for i := range combat.CombatGuards {
+			guard := &combat.CombatGuards[i]
+			if guard.Injured || guard.HitpointsRemaining <= 0 {
+				continue
 			}
-			msgs = append(msgs, Msg(fmt.Sprintf("%s gains %s effect!", player.Name, skill.Effect.Type), "buff"))
-		} else {
-			// Debuff/DoT on mob
-			mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-			msgs = append(msgs, Msg(fmt.Sprintf("%s is afflicted with %s!", mob.Name, skill.Effect.Type), "debuff"))
+			cs = append(cs, game.Combatant{ID: fmt.Sprintf("guard:%d", i), Name: guard.Name, Speed: game.Speed(guard)})
+		}
+	}
+	if withGuards {
+		for i := range combat.EnemyGuards {
+			guard := &combat.EnemyGuards[i]
+			if guard.Injured || guard.HitpointsRemaining <= 0 {
+				continue
+			}
+			cs = append(cs, game.Combatant{ID: fmt.Sprintf("foe:%d", i), Name: guard.Name, Speed: game.Speed(guard)})
 		}
 	}
+	return cs
+}
 
-	// Check if mob died from skill damage
-	if mob.HitpointsRemaining <= 0 {
-		return e.resolveCombatWin(session, msgs)
+// playOthersTurns runs the turn queue until it is the player's turn again:
+// the monster acts against playerDef and guards on either side take their
+// turns, each as often as their speed allows. It stops early if either side falls or the
+// monster flees.
+func (e *Engine) playOthersTurns(session *GameSession, playerDef int) []GameMessage {
+	combat := session.Combat
+	player := session.Player
+	mob := &combat.Mob
+	msgs := []GameMessage{}
+
+	opening := !combat.QueueStarted
+	combat.QueueStarted = true
+	mobActions := 0
+	for n := 0; n < game.MaxActionsBetweenTurns; n++ {
+		if mob.HitpointsRemaining <= 0 || player.HitpointsRemaining <= 0 || combat.MobFled {
+			return msgs
+		}
+		id := combat.Queue.Next(turnOrder(combat, player, true))
+		if id == "player" {
+			break
+		}
+		if id == "mob" {
+			if mobActions > 0 {
+				msgs = append(msgs, Msg(fmt.Sprintf("%s is quicker and acts again!", mob.Name), "combat"))
+			}
+			mobActions++
+			msgs = append(msgs, e.processMonsterTurnMsgs(session, playerDef)...)
+			continue
+		}
+		var i int
+		if _, err := fmt.Sscanf(id, "guard:%d", &i); err == nil && i < len(combat.CombatGuards) {
+			msgs = append(msgs, guardTurnMsgs(combat, player, i)...)
+		} else if _, err := fmt.Sscanf(id, "foe:%d", &i); err == nil && i < len(combat.EnemyGuards) {
+			msgs = append(msgs, enemyGuardTurnMsgs(combat, player, i)...)
+		}
 	}
+	if !opening && mobActions == 0 && mob.HitpointsRemaining > 0 && player.HitpointsRemaining > 0 {
+		msgs = append(msgs, Msg(fmt.Sprintf("%s is quicker and acts again!", player.Name), "buff"))
+	}
+	return msgs
+}
 
-	// Guard attacks
-	if combat.HasGuards && len(combat.CombatGuards) > 0 && mob.HitpointsRemaining > 0 {
-		msgs = append(msgs, Msg("--- Guard Support ---", "system"))
-		guardDamage := game.GuardAttack(combat.CombatGuards, mob)
-		if guardDamage > 0 {
-			msgs = append(msgs, Msg(fmt.Sprintf("Guards deal %d total damage!", guardDamage), "damage"))
+// playOpeningTurns lets foes faster than the player act before the player's
+// first action of the fight. It returns the fight's outcome if that ends it.
+func (e *Engine) playOpeningTurns(session *GameSession, msgs []GameMessage) ([]GameMessage, *GameResponse) {
+	combat := session.Combat
+	player := session.Player
+	if combat.QueueStarted {
+		return msgs, nil
+	}
+	playerDef := game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+	if opening := e.playOthersTurns(session, playerDef); len(opening) > 0 {
+		msgs = append(msgs, Msg("Quicker foes act before you!", "combat"))
+		msgs = append(msgs, opening...)
+	}
+	var resp GameResponse
+	switch {
+	case combat.Mob.HitpointsRemaining <= 0:
+		resp = e.resolveCombatWin(session, msgs)
+	case combat.MobFled:
+		resp = e.resolveMobFled(session, msgs)
+	case player.HitpointsRemaining <= 0:
+		resp = e.resolveCombatLoss(session, msgs)
+	default:
+		return msgs, nil
+	}
+	return msgs, &resp
+}
+
+// guardTurnMsgs plays one of the player's guards' turns against the monster
+// and any boss adds.
+func guardTurnMsgs(combat *CombatContext, player *models.Character, i int) []GameMessage {
+	foes := []interface{}{&combat.Mob}
+	if combat.Boss != nil {
+		for j := range combat.Boss.Adds {
+			foes = append(foes, &combat.Boss.Adds[j])
 		}
 	}
+	act := game.GuardTurn(&combat.CombatGuards[i], foes, player, guardTimers(&combat.GuardSkills, i), combat.Turn)
+	return statusMsgs(act.Messages)
+}
+
+// enemyGuardTurnMsgs plays one of the monster's guards' turns against the
+// player and the player's guards.
+func enemyGuardTurnMsgs(combat *CombatContext, player *models.Character, i int) []GameMessage {
+	foes := []interface{}{player}
+	if combat.HasGuards {
+		for j := range combat.CombatGuards {
+			foes = append(foes, &combat.CombatGuards[j])
+		}
+	}
+	act := game.GuardTurn(&combat.EnemyGuards[i], foes, &combat.Mob, guardTimers(&combat.EnemySkills, i), combat.Turn)
+	return statusMsgs(act.Messages)
+}
+
+// guardTimers returns guard i's skill timers, growing timers to fit.
+func guardTimers(timers *[]game.SkillTimers, i int) *game.SkillTimers {
+	for len(*timers) <= i {
+		*timers = append(*timers, game.SkillTimers{})
+	}
+	return &(*timers)[i]
+}
+
+// finishPlayerTurn plays out the rest of a turn after the player has acted:
+// guards and the monster act against playerDef until the player's next turn.
+func (e *Engine) finishPlayerTurn(session *GameSession, msgs []GameMessage, playerDef int) GameResponse {
+	combat := session.Combat
+	player := session.Player
+	mob := &combat.Mob
 
-	// Check if mob died from guard attacks
+	// Check if mob died from the player's action
 	if mob.HitpointsRemaining <= 0 {
 		return e.resolveCombatWin(session, msgs)
 	}
 
-	// Monster turn
-	playerDef := game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-	monsterMsgs := e.processMonsterTurnMsgs(session, playerDef)
-	msgs = append(msgs, monsterMsgs...)
+	// Guards and the monster act until the player's next turn
+	msgs = append(msgs, e.playOthersTurns(session, playerDef)...)
+	if mob.HitpointsRemaining <= 0 {
+		return e.resolveCombatWin(session, msgs)
+	}
+	if combat.MobFled {
+		return e.resolveMobFled(session, msgs)
+	}
 
 	// Check if player died
 	if player.HitpointsRemaining <= 0 {
@@ -856,7 +895,13 @@ func (e *Engine) handleCombatSkillReward(session *GameSession, cmd GameCommand)
 
 	guardedSkill := combat.Mob.GuardedSkill
 
-	switch cmd.Value {
+	choice := cmd.Value
+	if choice == "1" && !game.CanLearnSkill(player, guardedSkill.Name) {
+		msgs = append(msgs, Msg(fmt.Sprintf("A %s cannot learn %s. You take the scroll instead.", game.Classes[player.Class].Name, guardedSkill.Name), "system"))
+		choice = "2"
+	}
+
+	switch choice {
 	case "1": // Learn or upgrade skill
 		existingIdx := -1
 		for i, s := range player.LearnedSkills {
@@ -866,8 +911,10 @@ func (e *Engine) handleCombatSkillReward(session *GameSession, cmd GameCommand)
 			}
 		}
 		if existingIdx >= 0 {
-			game.UpgradeSkill(&player.LearnedSkills[existingIdx])
-			msgs = append(msgs, Msg(fmt.Sprintf("Your %s has been upgraded! (+5 damage, -2 cost) [+%d]", guardedSkill.Name, player.LearnedSkills[existingIdx].UpgradeCount), "levelup"))
+			msgs = append(msgs, Msg(fmt.Sprintf("You study %s further: +%d skill XP", guardedSkill.Name, data.SkillStudyXP), "levelup"))
+			for _, line := range game.GainSkillXP(&player.LearnedSkills[existingIdx], data.SkillStudyXP) {
+				msgs = append(msgs, Msg(line, "levelup"))
+			}
 			if e.metrics != nil {
 				e.metrics.RecordSkillUpgraded(guardedSkill.Name)
 			}
@@ -881,12 +928,18 @@ func (e *Engine) handleCombatSkillReward(session *GameSession, cmd GameCommand)
 		}
 	case "2": // Take skill scroll
 		scroll := game.CreateSkillScroll(guardedSkill)
-		player.Inventory = append(player.Inventory, scroll)
+		if !game.LootItem(player, scroll, game.ReasonSkillScroll, "guardian:"+guardedSkill.Name) {
+			msgs = append(msgs, Msg(fmt.Sprintf("Your inventory is full! The %s was left behind.", scroll.Name), "error"))
+			break
+		}
 		msgs = append(msgs, Msg(fmt.Sprintf("You received a %s!", scroll.Name), "loot"))
 		msgs = append(msgs, Msg(fmt.Sprintf("Crafting Value: %d", scroll.SkillScroll.CraftingValue), "system"))
 	default: // Default to scroll
 		scroll := game.CreateSkillScroll(guardedSkill)
-		player.Inventory = append(player.Inventory, scroll)
+		if !game.LootItem(player, scroll, game.ReasonSkillScroll, "guardian:"+guardedSkill.Name) {
+			msgs = append(msgs, Msg(fmt.Sprintf("Your inventory is full! The %s was left behind.", scroll.Name), "error"))
+			break
+		}
 		msgs = append(msgs, Msg(fmt.Sprintf("You received a %s!", scroll.Name), "loot"))
 	}
 
@@ -943,6 +996,7 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 		msgs = append(msgs, Msg(fmt.Sprintf("VICTORY! %s Wins! (No XP - enemy too weak)", player.Name), "combat"))
 	}
 	msgs = append(msgs, Msg("========================================", "system"))
+	msgs = append(msgs, wearPlayerGear(player, false)...)
 
 	// Track analytics stats
 	locationName := ""
@@ -989,7 +1043,7 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 
 	// Loot enemy equipment
 	for _, item := range mob.EquipmentMap {
-		game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+		game.LootItem(player, item, game.ReasonMonsterDrop, "monster:"+mob.Name)
 		msgs = append(msgs, Msg(fmt.Sprintf("Looted: %s", item.Name), "loot"))
 		if e.metrics != nil {
 			e.metrics.RecordItemLooted(item.Rarity)
@@ -1001,20 +1055,34 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 	if materialName != "" {
 		msgs = append(msgs, Msg(fmt.Sprintf("Obtained %d %s!", materialQty, materialName), "loot"))
 	}
+	if r, ok := game.RollRecipeDrop(player); ok {
+		msgs = append(msgs, recipeDiscoveryMessage(r))
+	}
 
 	// Loot rarity bonus from monster rarity
 	lootBonus := game.RarityLootBonus(mob.Rarity)
 	if lootBonus > 0 {
 		bonusItem := game.GenerateItem(lootBonus)
-		game.EquipBestItem(bonusItem, &player.EquipmentMap, &player.Inventory)
+		game.LootItem(player, bonusItem, game.ReasonMonsterDrop, "monster:"+mob.Name)
 		msgs = append(msgs, Msg(fmt.Sprintf("Bonus loot from %s monster: %s!", rarityDisplay, bonusItem.Name), "loot"))
 		if e.metrics != nil {
 			e.metrics.RecordItemLooted(bonusItem.Rarity)
 		}
 	}
 
-	// 30% chance to get a potion (health, mana, or stamina)
-	if rand.Intn(100) < 30 {
+	// Scripted bosses roll their own loot table
+	if def, ok := game.FindBoss(mob.BossID); ok {
+		for _, item := range game.RollBossLoot(def) {
+			game.LootItem(player, item, game.ReasonMonsterDrop, "boss:"+mob.Name)
+			msgs = append(msgs, Msg(fmt.Sprintf("Boss loot: %s!", item.Name), "loot"))
+			if e.metrics != nil {
+				e.metrics.RecordItemLooted(item.Rarity)
+			}
+		}
+	}
+
+	// 30% chance (plus luck) to get a potion (health, mana, or stamina)
+	if rand.Intn(100) < game.LootChance(player, 30) {
 		potionSize := "small"
 		sizeRoll := rand.Intn(100)
 		if sizeRoll < 50 {
@@ -1033,8 +1101,11 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 		} else {
 			potion = game.CreateStaminaPotion(potionSize)
 		}
-		player.Inventory = append(player.Inventory, potion)
-		msgs = append(msgs, Msg(fmt.Sprintf("Found a %s!", potion.Name), "loot"))
+		if game.LootItem(player, potion, game.ReasonMonsterDrop, "monster:"+mob.Name) {
+			msgs = append(msgs, Msg(fmt.Sprintf("Found a %s!", potion.Name), "loot"))
+		} else {
+			msgs = append(msgs, Msg(fmt.Sprintf("Found a %s, but your inventory is full.", potion.Name), "error"))
+		}
 	}
 
 	// 15% chance to rescue a villager (only after elder quest completed) or get a hint
@@ -1070,8 +1141,7 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 	}
 
 	// Recalculate player stats from equipment
-	player.StatsMod = game.CalculateItemMods(player.EquipmentMap)
-	player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
+	game.RecalculatePlayerStats(player)
 
 	// Replace monster at location
 	if combat.Location != nil && combat.MobLoc >= 0 && combat.MobLoc < len(combat.Location.Monsters) {
@@ -1094,6 +1164,7 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 							deadGuards = append(deadGuards, combatGuard.Name)
 							village.ActiveGuards = append(village.ActiveGuards[:i], village.ActiveGuards[i+1:]...)
 						} else {
+							game.CarryGuardProgress(&village.ActiveGuards[i], combatGuard)
 							village.ActiveGuards[i].HitpointsRemaining = combatGuard.HitpointsRemaining
 							village.ActiveGuards[i].Injured = combatGuard.Injured
 							village.ActiveGuards[i].RecoveryTime = combatGuard.RecoveryTime
@@ -1128,6 +1199,7 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 	if player.Level > prevLevel {
 		msgs = append(msgs, Msg(fmt.Sprintf("LEVEL UP! Now level %d!", player.Level), "levelup"))
 		msgs = append(msgs, Msg(fmt.Sprintf("HP: %d, MP: %d, SP: %d", player.HitpointsTotal, player.ManaTotal, player.StaminaTotal), "levelup"))
+		msgs = append(msgs, Msg(fmt.Sprintf("Unspent: %d attribute points, %d talent points (see Attributes and Talents in the main menu)", game.AttributePoints(player), game.TalentPoints(player)), "levelup"))
 		if e.metrics != nil {
 			e.metrics.RecordLevelUp(player.Level)
 		}
@@ -1276,6 +1348,11 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 		msgs = append(msgs, Msg(fmt.Sprintf("Description: %s", mob.GuardedSkill.Description), "narrative"))
 		msgs = append(msgs, Msg("Choose your reward:", "system"))
 
+		absorb := Opt("1", "Absorb the skill immediately (learn now)")
+		if !game.CanLearnSkill(player, mob.GuardedSkill.Name) {
+			absorb = OptDisabled("1", fmt.Sprintf("Absorb the skill (not available to %ss)", game.Classes[player.Class].Name))
+		}
+
 		session.State = StateCombatSkillReward
 		return GameResponse{
 			Type:     "combat",
@@ -1286,7 +1363,7 @@ func (e *Engine) resolveCombatWin(session *GameSession, msgs []GameMessage) Game
 				Combat: MakeCombatView(session),
 			},
 			Options: []MenuOption{
-				Opt("1", "Absorb the skill immediately (learn now)"),
+				absorb,
 				Opt("2", "Take a skill scroll (can learn later or use for crafting)"),
 			},
 		}
@@ -1364,6 +1441,8 @@ func (e *Engine) resolveCombatLoss(session *GameSession, msgs []GameMessage) Gam
 		combat.AutoPlayDeaths++
 	}
 
+	msgs = append(msgs, wearPlayerGear(player, true)...)
+
 	// Transfer player equipment to mob
 	for _, item := range player.EquipmentMap {
 		game.EquipBestItem(item, &mob.EquipmentMap, &mob.Inventory)
@@ -1506,6 +1585,54 @@ func (e *Engine) resolveCombatLoss(session *GameSession, msgs []GameMessage) Gam
 	}
 }
 
+// tickCombatStatuses runs one turn of status effects for everyone in the
+// fight and returns the combat log lines.
+func tickCombatStatuses(combat *CombatContext, player *models.Character) []GameMessage {
+	msgs := statusMsgs(game.TickStatusEffects(player))
+	msgs = append(msgs, statusMsgs(game.TickStatusEffects(&combat.Mob))...)
+	for i := range combat.CombatGuards {
+		msgs = append(msgs, statusMsgs(game.TickStatusEffects(&combat.CombatGuards[i]))...)
+	}
+	return msgs
+}
+
+// applySkillEffect applies a skill's status effect from caster to target,
+// recording it in the metrics.
+func (e *Engine) applySkillEffect(caster, target interface{}, effect models.StatusEffect) []GameMessage {
+	if effect.Type == "" || effect.Type == "none" {
+		return nil
+	}
+	if e.metrics != nil {
+		e.metrics.RecordStatusEffect(effect.Type)
+	}
+	return statusMsgs(game.ApplySkillEffect(caster, target, effect))
+}
+
+// applyElements runs skill damage through the fight's surroundings and any
+// elemental reactions, recording the reactions in the metrics.
+func (e *Engine) applyElements(combat *CombatContext, damage int, element models.DamageType, target interface{}) (int, []GameMessage) {
+	location := ""
+	if combat.Location != nil {
+		location = combat.Location.Name
+	}
+	hit := game.ApplyElements(damage, element, target, location)
+	if e.metrics != nil {
+		for _, name := range hit.Reactions {
+			e.metrics.RecordReaction(name)
+		}
+	}
+	return hit.Damage, statusMsgs(hit.Messages)
+}
+
+// statusMsgs converts status effect output to combat log messages.
+func statusMsgs(lines []game.StatusMessage) []GameMessage {
+	msgs := make([]GameMessage, 0, len(lines))
+	for _, l := range lines {
+		msgs = append(msgs, Msg(l.Text, l.Kind))
+	}
+	return msgs
+}
+
 // processMonsterTurnMsgs generates messages for the monster's turn and applies state changes.
 func (e *Engine) processMonsterTurnMsgs(session *GameSession, playerDef int) []GameMessage {
 	combat := session.Combat
@@ -1517,117 +1644,192 @@ func (e *Engine) processMonsterTurnMsgs(session *GameSession, playerDef int) []G
 		return msgs
 	}
 
+	// A scripted boss plays its phases, adds and telegraphed attacks first
+	if boss := bossFightOf(combat); boss != nil {
+		bossMsgs, acted := boss.Turn(mob, player, combat.Turn, combat.IsDefending, game.IsStunnedMob(mob))
+		msgs = append(msgs, statusMsgs(bossMsgs)...)
+		if acted || player.HitpointsRemaining <= 0 {
+			return msgs
+		}
+	}
+
 	// Check if mob is stunned
 	if game.IsStunnedMob(mob) {
 		msgs = append(msgs, Msg(fmt.Sprintf("%s is STUNNED and cannot act!", mob.Name), "debuff"))
 		return msgs
 	}
 
-	// 40% chance to use skill if mob has skills and resources
-	usedSkill := false
-	if len(mob.LearnedSkills) > 0 && rand.Intn(100) < 40 {
-		skill := mob.LearnedSkills[rand.Intn(len(mob.LearnedSkills))]
-		if skill.ManaCost <= mob.ManaRemaining && skill.StaminaCost <= mob.StaminaRemaining {
-			mob.ManaRemaining -= skill.ManaCost
-			mob.StaminaRemaining -= skill.StaminaCost
-			usedSkill = true
+	// The monster's AI profile picks its move
+	var guards []models.Guard
+	if combat.HasGuards {
+		guards = combat.CombatGuards
+	}
+	act := game.DecideMonsterAction(mob, player, guards, &combat.MobSkills, combat.Turn)
+	msgs = append(msgs, statusMsgs(act.Messages)...)
+	if act.Kind == game.MonsterFlee {
+		if mobCanFlee(combat) {
+			combat.MobFled = true
+			msgs = append(msgs, Msg(fmt.Sprintf("%s turns tail and flees!", mob.Name), "combat"))
+			return msgs
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("%s is cornered and fights on!", mob.Name), "combat"))
+	}
 
-			msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s!", mob.Name, skill.Name), "combat"))
-			if e.metrics != nil {
-				e.metrics.RecordSkillUse(skill.Name)
-			}
+	if act.Kind == game.MonsterSkill {
+		skill := act.Skill
+		mob.ManaRemaining -= skill.ManaCost
+		mob.StaminaRemaining -= skill.StaminaCost
 
-			if skill.Damage < 0 {
-				// Healing
-				healAmount := -skill.Damage
-				mob.HitpointsRemaining += healAmount
-				if mob.HitpointsRemaining > mob.HitpointsTotal {
-					mob.HitpointsRemaining = mob.HitpointsTotal
-				}
-				msgs = append(msgs, Msg(fmt.Sprintf("%s heals for %d HP!", mob.Name, healAmount), "heal"))
-			} else if skill.Damage > 0 {
-				// Damage skill
-				finalDamage := game.ApplyDamage(skill.Damage, skill.DamageType, player)
-
-				// Guard defense if guards present
-				if combat.HasGuards && len(combat.CombatGuards) > 0 {
-					remainingDamage, _ := game.GuardDefense(combat.CombatGuards, finalDamage)
-					absorbedDamage := finalDamage - remainingDamage
-					if absorbedDamage > 0 {
-						msgs = append(msgs, Msg(fmt.Sprintf("Guards absorbed %d of %d incoming damage!", absorbedDamage, finalDamage), "system"))
-					}
-					finalDamage = remainingDamage
-				}
+		msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s!", mob.Name, skill.Name), "combat"))
+		if e.metrics != nil {
+			e.metrics.RecordSkillUse(skill.Name)
+		}
+		cast := combat.MobSkills.Cast(skill, skill.Damage, player, combat.Turn)
+		msgs = append(msgs, statusMsgs(cast.Messages)...)
 
+		if skill.Damage < 0 {
+			// Healing
+			healAmount := -cast.Power
+			mob.HitpointsRemaining += healAmount
+			if mob.HitpointsRemaining > mob.HitpointsTotal {
+				mob.HitpointsRemaining = mob.HitpointsTotal
+			}
+			msgs = append(msgs, Msg(fmt.Sprintf("%s heals for %d HP!", mob.Name, healAmount), "heal"))
+		} else if skill.Damage > 0 {
+			// Damage skill
+			if act.Guard >= 0 {
+				msgs = append(msgs, e.mobStrikeGuard(combat, act.Guard, cast.Power, skill.DamageType)...)
+			} else {
+				damage, reactionMsgs := e.applyElements(combat, cast.Power, skill.DamageType, player)
+				msgs = append(msgs, reactionMsgs...)
+				finalDamage := e.guardCover(combat, game.ApplyDamage(damage, skill.DamageType, player), &msgs)
 				player.HitpointsRemaining -= finalDamage
 				msgs = append(msgs, Msg(fmt.Sprintf("Deals %d damage to %s!", finalDamage, player.Name), "damage"))
 				if e.metrics != nil {
 					e.metrics.RecordDamage(finalDamage, string(skill.DamageType), false)
 				}
 			}
-
-			// Apply skill status effects
-			if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-				if e.metrics != nil {
-					e.metrics.RecordStatusEffect(skill.Effect.Type)
-				}
-				if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" {
-					// Buff on mob
-					mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-					if skill.Effect.Type == "buff_attack" {
-						mob.StatsMod.AttackMod += skill.Effect.Potency
-					} else if skill.Effect.Type == "buff_defense" {
-						mob.StatsMod.DefenseMod += skill.Effect.Potency
-					}
-					msgs = append(msgs, Msg(fmt.Sprintf("%s gains %s effect!", mob.Name, skill.Effect.Type), "buff"))
-				} else {
-					// Debuff/DoT on player
-					player.StatusEffects = append(player.StatusEffects, skill.Effect)
-					msgs = append(msgs, Msg(fmt.Sprintf("%s is afflicted with %s!", player.Name, skill.Effect.Type), "debuff"))
-				}
-			}
 		}
+
+		// Apply skill status effects
+		msgs = append(msgs, e.applySkillEffect(mob, player, skill.Effect)...)
+		return msgs
 	}
 
-	// Normal attack if no skill used
-	if !usedSkill {
-		mobAttack := game.MultiRoll(mob.AttackRolls) + mob.StatsMod.AttackMod
-		isCrit := rand.Intn(100) < 10
-		if isCrit {
-			mobAttack *= 2
-			msgs = append(msgs, Msg(fmt.Sprintf("*** %s CRITICAL HIT! ***", mob.Name), "combat"))
-			if e.metrics != nil {
-				e.metrics.RecordCrit(false)
-			}
+	// Normal attack
+	mobAttack := game.MultiRoll(mob.AttackRolls) + game.AttackMod(mob)
+	isCrit := game.RollMonsterCrit()
+	if isCrit {
+		mobAttack *= 2
+		msgs = append(msgs, Msg(fmt.Sprintf("*** %s CRITICAL HIT! ***", mob.Name), "combat"))
+		if e.metrics != nil {
+			e.metrics.RecordCrit(false)
 		}
+	}
 
-		if mobAttack > playerDef {
-			diff := mobAttack - playerDef
-			finalDamage := game.ApplyDamage(diff, models.Physical, player)
-
-			// Guard defense if guards present
-			if combat.HasGuards && len(combat.CombatGuards) > 0 {
-				remainingDamage, _ := game.GuardDefense(combat.CombatGuards, finalDamage)
-				absorbedDamage := finalDamage - remainingDamage
-				if absorbedDamage > 0 {
-					msgs = append(msgs, Msg(fmt.Sprintf("Guards absorbed %d of %d incoming damage!", absorbedDamage, finalDamage), "system"))
-				}
-				finalDamage = remainingDamage
-			}
+	if act.Guard >= 0 {
+		guard := &combat.CombatGuards[act.Guard]
+		guardDef := game.MultiRoll(guard.DefenseRolls) + game.DefenseMod(guard) + guard.DefenseBonus
+		if mobAttack > guardDef {
+			return append(msgs, e.mobStrikeGuard(combat, act.Guard, mobAttack-guardDef, models.Physical)...)
+		}
+		return append(msgs, Msg(fmt.Sprintf("%s lunges at %s but misses!", mob.Name, guard.Name), "combat"))
+	}
 
-			player.HitpointsRemaining -= finalDamage
-			msgs = append(msgs, Msg(fmt.Sprintf("%s attacks for %d damage!", mob.Name, finalDamage), "damage"))
-			if e.metrics != nil {
-				e.metrics.RecordDamage(finalDamage, "physical", false)
-			}
-		} else {
-			msgs = append(msgs, Msg(fmt.Sprintf("%s's attack missed!", mob.Name), "combat"))
+	if mobAttack > playerDef {
+		diff := mobAttack - playerDef
+		finalDamage := e.guardCover(combat, game.ApplyDamage(diff, models.Physical, player), &msgs)
+		player.HitpointsRemaining -= finalDamage
+		msgs = append(msgs, Msg(fmt.Sprintf("%s attacks for %d damage!", mob.Name, finalDamage), "damage"))
+		if e.metrics != nil {
+			e.metrics.RecordDamage(finalDamage, "physical", false)
 		}
+		for _, line := range game.ApplyThorns(player, mob, finalDamage) {
+			msgs = append(msgs, Msg(line, "damage"))
+		}
+	} else {
+		msgs = append(msgs, Msg(fmt.Sprintf("%s's attack missed!", mob.Name), "combat"))
+	}
+
+	return msgs
+}
+
+// bossFightOf returns the fight's scripted boss encounter, starting it the
+// first time, or nil when the monster is not a scripted boss.
+func bossFightOf(combat *CombatContext) *game.BossFight {
+	if combat.Boss == nil && combat.Mob.BossID != "" {
+		combat.Boss = game.NewBossFight(&combat.Mob)
+	}
+	return combat.Boss
+}
+
+// guardCover lets the player's guards absorb part of damage aimed at the
+// player, noting it in msgs, and returns what gets through.
+func (e *Engine) guardCover(combat *CombatContext, damage int, msgs *[]GameMessage) int {
+	if !combat.HasGuards || len(combat.CombatGuards) == 0 {
+		return damage
+	}
+	remaining, _ := game.GuardDefense(combat.CombatGuards, damage)
+	if absorbed := damage - remaining; absorbed > 0 {
+		*msgs = append(*msgs, Msg(fmt.Sprintf("Guards absorbed %d of %d incoming damage!", absorbed, damage), "system"))
 	}
+	return remaining
+}
 
+// mobStrikeGuard lands the monster's damage on one of the player's guards,
+// the target its AI profile picked.
+func (e *Engine) mobStrikeGuard(combat *CombatContext, idx, damage int, element models.DamageType) []GameMessage {
+	mob := &combat.Mob
+	guard := &combat.CombatGuards[idx]
+	damage, msgs := e.applyElements(combat, damage, element, guard)
+	final := game.ApplyDamage(damage, element, guard)
+	msgs = append(msgs, Msg(fmt.Sprintf("%s goes for %s and deals %d damage!", mob.Name, guard.Name, final), "damage"))
+	if game.WoundGuard(guard, final) {
+		msgs = append(msgs, Msg(fmt.Sprintf("%s is seriously injured and falls back!", guard.Name), "debuff"))
+	}
+	if e.metrics != nil {
+		e.metrics.RecordDamage(final, string(element), false)
+	}
 	return msgs
 }
 
+// mobCanFlee reports whether the monster may run from this fight. Only
+// ordinary hunts let it: dungeon, arena, town, defense and guardian fights
+// go to the end.
+func mobCanFlee(combat *CombatContext) bool {
+	return !combat.IsDungeon && !combat.IsPvP && !combat.IsArena && !combat.IsMayorChallenge &&
+		combat.WavesTotal == 0 && combat.GuardianLocationName == "" && !combat.Mob.IsSkillGuardian
+}
+
+// resolveMobFled ends a fight the monster ran away from: no rewards, and the
+// hunt carries on when it is continuous.
+func (e *Engine) resolveMobFled(session *GameSession, msgs []GameMessage) GameResponse {
+	combat := session.Combat
+	player := session.Player
+	msgs = append(msgs, Msg(fmt.Sprintf("%s got away. No rewards this time.", combat.Mob.Name), "system"))
+	if e.metrics != nil {
+		e.metrics.RecordMonsterFlee()
+	}
+
+	if session.GameState.Villages != nil {
+		if village, exists := session.GameState.Villages[player.VillageName]; exists {
+			game.ProcessGuardRecovery(&village)
+			session.GameState.Villages[player.VillageName] = village
+		}
+	}
+	if combat.ContinuousHunt {
+		return e.startNextHunt(session, msgs)
+	}
+
+	session.State = StateMainMenu
+	return GameResponse{
+		Type:     "narrative",
+		Messages: msgs,
+		State:    &StateData{Screen: "main_menu", Player: MakePlayerState(player)},
+		Options:  BuildMainMenuResponse(session).Options,
+	}
+}
+
 // startNextHunt begins the next hunt in a multi-hunt session.
 func (e *Engine) startNextHunt(session *GameSession, msgs []GameMessage) GameResponse {
 	combat := session.Combat
@@ -1730,21 +1932,23 @@ func (e *Engine) startNextHunt(session *GameSession, msgs []GameMessage) GameRes
 }
 
 // scaledXP calculates XP gained from defeating a monster based on level difference.
-// Monsters 10+ levels below the player grant 0 XP. Equal or higher level grants full XP.
-// In between, XP scales linearly.
+// Monsters KillXPCutoffLevels (default 10) or more levels below the player grant 0 XP.
+// Equal or higher level grants full XP. In between, XP scales linearly.
 func scaledXP(playerLevel, mobLevel int) int {
+	b := config.Current().Balance
 	diff := playerLevel - mobLevel // positive means player is higher
-	if diff >= 10 {
+	if diff >= b.KillXPCutoffLevels {
 		return 0
 	}
-	baseXP := mobLevel * 10
+	baseXP := mobLevel * b.KillXPPerMobLevel
 	if diff <= 0 {
 		// Monster is equal or higher level: full XP + bonus
-		bonus := (-diff) * 5
+		bonus := (-diff) * b.KillXPBonusPerLevel
 		return baseXP + bonus
 	}
-	// Monster is 1-9 levels below: scale down linearly (90% at -1, 80% at -2, ... 10% at -9)
-	pct := 100 - (diff * 10)
+	// Monster is below the player: scale down linearly toward the cutoff
+	// (90% at -1, 80% at -2, ... 10% at -9 with the default cutoff)
+	pct := 100 - (diff * 100 / b.KillXPCutoffLevels)
 	return baseXP * pct / 100
 }
 
@@ -1778,72 +1982,38 @@ func (e *Engine) autoResolveCombat(session *GameSession, msgs []GameMessage) Gam
 			break
 		}
 
-		// Process player status effects
-		for i := len(player.StatusEffects) - 1; i >= 0; i-- {
-			effect := &player.StatusEffects[i]
-			switch effect.Type {
-			case "poison":
-				player.HitpointsRemaining -= effect.Potency
-				msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d poison damage!", player.Name, effect.Potency), "damage"))
-			case "burn":
-				player.HitpointsRemaining -= effect.Potency
-				msgs = append(msgs, Msg(fmt.Sprintf("%s takes %d burn damage!", player.Name, effect.Potency), "damage"))
-			case "regen":
-				player.HitpointsRemaining += effect.Potency
-				if player.HitpointsRemaining > player.HitpointsTotal {
-					player.HitpointsRemaining = player.HitpointsTotal
-				}
-			}
-			effect.Duration--
-			if effect.Duration <= 0 {
-				switch effect.Type {
-				case "buff_attack":
-					player.StatsMod.AttackMod -= effect.Potency
-				case "buff_defense":
-					player.StatsMod.DefenseMod -= effect.Potency
-				}
-				player.StatusEffects = append(player.StatusEffects[:i], player.StatusEffects[i+1:]...)
-			}
-		}
-
-		// Process mob status effects
-		for i := len(mob.StatusEffects) - 1; i >= 0; i-- {
-			effect := &mob.StatusEffects[i]
-			switch effect.Type {
-			case "poison":
-				mob.HitpointsRemaining -= effect.Potency
-			case "burn":
-				mob.HitpointsRemaining -= effect.Potency
-			case "regen":
-				mob.HitpointsRemaining += effect.Potency
-				if mob.HitpointsRemaining > mob.HitpointsTotal {
-					mob.HitpointsRemaining = mob.HitpointsTotal
-				}
-			}
-			effect.Duration--
-			if effect.Duration <= 0 {
-				switch effect.Type {
-				case "buff_attack":
-					mob.StatsMod.AttackMod -= effect.Potency
-				case "buff_defense":
-					mob.StatsMod.DefenseMod -= effect.Potency
-				}
-				mob.StatusEffects = append(mob.StatusEffects[:i], mob.StatusEffects[i+1:]...)
-			}
-		}
+		// Status effects tick for the player, the monster and any guards
+		msgs = append(msgs, tickCombatStatuses(combat, player)...)
 
 		if player.HitpointsRemaining <= 0 || mob.HitpointsRemaining <= 0 {
 			break
 		}
 
-		// Player AI turn (skip if stunned)
-		if !game.IsStunned(player) {
-			decision := game.MakeAIDecision(player, mob, combat.Turn)
+		// Quicker foes act before the player's first move
+		if !combat.QueueStarted {
+			msgs = append(msgs, e.autoOthersTurns(combat, player)...)
+			if combat.MobFled || player.HitpointsRemaining <= 0 || mob.HitpointsRemaining <= 0 {
+				break
+			}
+		}
+
+		// Player AI turn (skip if stunned, keep charging if charging)
+		if game.IsStunned(player) {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s is STUNNED!", player.Name), "debuff"))
+			if name := combat.PlayerSkills.InterruptCharge(); name != "" {
+				msgs = append(msgs, Msg(fmt.Sprintf("%s's %s is interrupted!", player.Name, name), "debuff"))
+			}
+		} else if combat.PlayerSkills.Charging != nil {
+			if skill, ready := combat.PlayerSkills.ContinueCharge(combat.Turn); ready {
+				msgs = append(msgs, e.castPlayerSkill(combat, player, skill)...)
+			}
+		} else {
+			decision := game.MakeAIDecision(player, mob, combat.Turn, &combat.PlayerSkills)
 
 			switch decision {
 			case "attack":
-				playerAttack := game.MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-				isCrit := rand.Intn(100) < 15
+				playerAttack := game.MultiRoll(player.AttackRolls) + game.AttackMod(player)
+				isCrit := game.RollPlayerCrit(player)
 				if isCrit {
 					playerAttack *= 2
 					msgs = append(msgs, Msg("*** CRITICAL HIT! ***", "combat"))
@@ -1851,7 +2021,7 @@ func (e *Engine) autoResolveCombat(session *GameSession, msgs []GameMessage) Gam
 						e.metrics.RecordCrit(true)
 					}
 				}
-				mobDef := game.MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+				mobDef := game.MultiRoll(mob.DefenseRolls) + game.DefenseMod(mob)
 				if playerAttack > mobDef {
 					diff := game.ApplyDamage(playerAttack-mobDef, models.Physical, mob)
 					mob.HitpointsRemaining -= diff
@@ -1859,14 +2029,16 @@ func (e *Engine) autoResolveCombat(session *GameSession, msgs []GameMessage) Gam
 					if e.metrics != nil {
 						e.metrics.RecordDamage(diff, "physical", true)
 					}
+					for _, line := range game.ApplyOnHitEffects(player, mob, diff) {
+						msgs = append(msgs, Msg(line, "damage"))
+					}
 				} else {
 					msgs = append(msgs, Msg(fmt.Sprintf("%s's attack missed!", player.Name), "combat"))
 				}
 			case "item":
 				for idx, item := range player.Inventory {
 					if item.ItemType == "consumable" {
-						game.UseConsumableItem(item, player)
-						game.RemoveItemFromInventory(&player.Inventory, idx)
+						game.UseInventoryItem(player, idx, game.ReasonItemUsed, "combat")
 						msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s!", player.Name, item.Name), "heal"))
 						if e.metrics != nil {
 							e.metrics.RecordItemUse(item.Name)
@@ -1881,117 +2053,128 @@ func (e *Engine) autoResolveCombat(session *GameSession, msgs []GameMessage) Gam
 						if skill.Name == skillName && skill.ManaCost <= player.ManaRemaining && skill.StaminaCost <= player.StaminaRemaining {
 							player.ManaRemaining -= skill.ManaCost
 							player.StaminaRemaining -= skill.StaminaCost
-							if e.metrics != nil {
-								e.metrics.RecordSkillUse(skill.Name)
-							}
-							if skill.Damage < 0 {
-								player.HitpointsRemaining += -skill.Damage
-								if player.HitpointsRemaining > player.HitpointsTotal {
-									player.HitpointsRemaining = player.HitpointsTotal
-								}
-								msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s! Heals %d HP!", player.Name, skill.Name, -skill.Damage), "heal"))
-							} else if skill.Damage > 0 {
-								finalDamage := game.ApplyDamage(skill.Damage, skill.DamageType, mob)
-								mob.HitpointsRemaining -= finalDamage
-								msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s for %d damage!", player.Name, skill.Name, finalDamage), "damage"))
-								if e.metrics != nil {
-									e.metrics.RecordDamage(finalDamage, string(skill.DamageType), true)
-								}
-							}
-							if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-								if e.metrics != nil {
-									e.metrics.RecordStatusEffect(skill.Effect.Type)
-								}
-								if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" || skill.Effect.Type == "regen" {
-									player.StatusEffects = append(player.StatusEffects, skill.Effect)
-									if skill.Effect.Type == "buff_attack" {
-										player.StatsMod.AttackMod += skill.Effect.Potency
-									} else if skill.Effect.Type == "buff_defense" {
-										player.StatsMod.DefenseMod += skill.Effect.Potency
-									}
-								} else {
-									mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-								}
-							}
+							msgs = append(msgs, e.castPlayerSkill(combat, player, skill)...)
 							break
 						}
 					}
 				}
 			}
-		} else {
-			msgs = append(msgs, Msg(fmt.Sprintf("%s is STUNNED!", player.Name), "debuff"))
 		}
 
 		if mob.HitpointsRemaining <= 0 || player.HitpointsRemaining <= 0 {
 			break
 		}
 
-		// Monster turn (skip if stunned)
-		if !game.IsStunnedMob(mob) {
-			useSkill := len(mob.LearnedSkills) > 0 && rand.Intn(100) < 40
-			if useSkill {
-				skill := mob.LearnedSkills[rand.Intn(len(mob.LearnedSkills))]
-				if skill.ManaCost <= mob.ManaRemaining && skill.StaminaCost <= mob.StaminaRemaining {
-					mob.ManaRemaining -= skill.ManaCost
-					mob.StaminaRemaining -= skill.StaminaCost
-					if e.metrics != nil {
-						e.metrics.RecordSkillUse(skill.Name)
-					}
-					if skill.Damage < 0 {
-						mob.HitpointsRemaining += -skill.Damage
-						if mob.HitpointsRemaining > mob.HitpointsTotal {
-							mob.HitpointsRemaining = mob.HitpointsTotal
-						}
-					} else if skill.Damage > 0 {
-						playerDef := game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-						finalDamage := game.ApplyDamage(skill.Damage, skill.DamageType, player)
-						if finalDamage > playerDef {
-							player.HitpointsRemaining -= (finalDamage - playerDef)
-							if e.metrics != nil {
-								e.metrics.RecordDamage(finalDamage-playerDef, string(skill.DamageType), false)
-							}
-						}
-					}
-					if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-						if e.metrics != nil {
-							e.metrics.RecordStatusEffect(skill.Effect.Type)
-						}
-						if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" || skill.Effect.Type == "regen" {
-							mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-						} else {
-							player.StatusEffects = append(player.StatusEffects, skill.Effect)
-						}
-					}
-					msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s!", mob.Name, skill.Name), "combat"))
-					continue
-				}
-			}
-			// Normal attack
-			mobAttack := game.MultiRoll(mob.AttackRolls) + mob.StatsMod.AttackMod
-			isCrit := rand.Intn(100) < 10
-			if isCrit {
-				mobAttack *= 2
-				if e.metrics != nil {
-					e.metrics.RecordCrit(false)
-				}
-			}
-			playerDef := game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-			if mobAttack > playerDef {
-				diff := game.ApplyDamage(mobAttack-playerDef, models.Physical, player)
-				player.HitpointsRemaining -= diff
-				msgs = append(msgs, Msg(fmt.Sprintf("%s attacks for %d damage!", mob.Name, diff), "damage"))
-				if e.metrics != nil {
-					e.metrics.RecordDamage(diff, "physical", false)
-				}
-			}
+		msgs = append(msgs, e.autoOthersTurns(combat, player)...)
+		if combat.MobFled {
+			break
 		}
 	}
 
 	msgs = append(msgs, Msg(fmt.Sprintf("--- Auto fight ended (Turn %d) ---", combat.Turn), "system"))
 
 	// Resolve outcome
+	if combat.MobFled {
+		return e.resolveMobFled(session, msgs)
+	}
 	if mob.HitpointsRemaining <= 0 {
 		return e.resolveCombatWin(session, msgs)
 	}
 	return e.resolveCombatLoss(session, msgs)
 }
+
+// autoOthersTurns runs the turn queue during an auto fight until it is the
+// player's turn again. Guards sit out auto fights.
+func (e *Engine) autoOthersTurns(combat *CombatContext, player *models.Character) []GameMessage {
+	mob := &combat.Mob
+	msgs := []GameMessage{}
+	combat.QueueStarted = true
+	for n := 0; n < game.MaxActionsBetweenTurns; n++ {
+		if mob.HitpointsRemaining <= 0 || player.HitpointsRemaining <= 0 || combat.MobFled {
+			break
+		}
+		if combat.Queue.Next(turnOrder(combat, player, false)) == "player" {
+			break
+		}
+		msgs = append(msgs, e.autoMonsterTurn(combat, player)...)
+	}
+	return msgs
+}
+
+// autoMonsterTurn plays one monster turn during an auto fight.
+func (e *Engine) autoMonsterTurn(combat *CombatContext, player *models.Character) []GameMessage {
+	mob := &combat.Mob
+	msgs := []GameMessage{}
+
+	// A scripted boss plays its phases, adds and telegraphed attacks first
+	if boss := bossFightOf(combat); boss != nil {
+		bossMsgs, acted := boss.Turn(mob, player, combat.Turn, false, game.IsStunnedMob(mob))
+		msgs = append(msgs, statusMsgs(bossMsgs)...)
+		if acted || player.HitpointsRemaining <= 0 {
+			return msgs
+		}
+	}
+
+	// Monster turn (skip if stunned)
+	if !game.IsStunnedMob(mob) {
+		act := game.DecideMonsterAction(mob, player, nil, &combat.MobSkills, combat.Turn)
+		msgs = append(msgs, statusMsgs(act.Messages)...)
+		if act.Kind == game.MonsterFlee && mobCanFlee(combat) {
+			combat.MobFled = true
+			msgs = append(msgs, Msg(fmt.Sprintf("%s turns tail and flees!", mob.Name), "combat"))
+			return msgs
+		}
+		if act.Kind == game.MonsterSkill {
+			skill := act.Skill
+			mob.ManaRemaining -= skill.ManaCost
+			mob.StaminaRemaining -= skill.StaminaCost
+			if e.metrics != nil {
+				e.metrics.RecordSkillUse(skill.Name)
+			}
+			msgs = append(msgs, Msg(fmt.Sprintf("%s uses %s!", mob.Name, skill.Name), "combat"))
+			cast := combat.MobSkills.Cast(skill, skill.Damage, player, combat.Turn)
+			msgs = append(msgs, statusMsgs(cast.Messages)...)
+			if skill.Damage < 0 {
+				mob.HitpointsRemaining += -cast.Power
+				if mob.HitpointsRemaining > mob.HitpointsTotal {
+					mob.HitpointsRemaining = mob.HitpointsTotal
+				}
+			} else if skill.Damage > 0 {
+				playerDef := game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+				damage, reactionMsgs := e.applyElements(combat, cast.Power, skill.DamageType, player)
+				msgs = append(msgs, reactionMsgs...)
+				finalDamage := game.ApplyDamage(damage, skill.DamageType, player)
+				if finalDamage > playerDef {
+					player.HitpointsRemaining -= (finalDamage - playerDef)
+					if e.metrics != nil {
+						e.metrics.RecordDamage(finalDamage-playerDef, string(skill.DamageType), false)
+					}
+				}
+			}
+			msgs = append(msgs, e.applySkillEffect(mob, player, skill.Effect)...)
+			return msgs
+		}
+		// Normal attack
+		mobAttack := game.MultiRoll(mob.AttackRolls) + game.AttackMod(mob)
+		isCrit := game.RollMonsterCrit()
+		if isCrit {
+			mobAttack *= 2
+			if e.metrics != nil {
+				e.metrics.RecordCrit(false)
+			}
+		}
+		playerDef := game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
+		if mobAttack > playerDef {
+			diff := game.ApplyDamage(mobAttack-playerDef, models.Physical, player)
+			player.HitpointsRemaining -= diff
+			msgs = append(msgs, Msg(fmt.Sprintf("%s attacks for %d damage!", mob.Name, diff), "damage"))
+			if e.metrics != nil {
+				e.metrics.RecordDamage(diff, "physical", false)
+			}
+			for _, line := range game.ApplyThorns(player, mob, diff) {
+				msgs = append(msgs, Msg(line, "damage"))
+			}
+		}
+	}
+	return msgs
+}
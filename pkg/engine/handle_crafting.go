@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// craftScreen ties a recipe type to the state and screen that list it.
+type craftScreen struct {
+	state string
+	title string
+}
+
+// craftScreens lists the recipe menus reached from the crafting and defense
+// menus, keyed by recipe type.
+var craftScreens = map[string]craftScreen{
+	"potions":       {StateVillageCraftPotion, "POTION CRAFTING"},
+	"armor":         {StateVillageCraftArmor, "ARMOR CRAFTING"},
+	"weapons":       {StateVillageCraftWeapon, "WEAPON CRAFTING"},
+	"skill_scrolls": {StateVillageCraftScrolls, "SKILL SCROLL CRAFTING"},
+	"traps":         {StateVillageCraftTraps, "CRAFT TRAPS"},
+}
+
+// handleRecipeMenu queues the recipe picked from one of the recipe menus for
+// the village crafters and redraws it. Options are numbered in registry order.
+func (e *Engine) handleRecipeMenu(session *GameSession, cmd GameCommand, recipeType string) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	msgs := []GameMessage{}
+
+	recipes := game.KnownRecipesOfType(player, recipeType)
+	if cmd.Type != "init" {
+		idx, err := strconv.Atoi(cmd.Value)
+		if err == nil && idx >= 1 && idx <= len(recipes) {
+			job, err := game.QueueCraft(player, village, recipes[idx-1], -1)
+			if err != nil {
+				msgs = append(msgs, Msg(err.Error(), "error"))
+			} else {
+				msgs = append(msgs, jobQueuedMessages(village, job)...)
+				if e.metrics != nil {
+					e.metrics.RecordFeatureUse("craft")
+				}
+				e.saveVillage(session)
+			}
+		}
+	}
+
+	session.State = craftScreens[recipeType].state
+	resp := buildRecipeResponse(session, recipeType)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildRecipeResponse lists the known recipes of one type with their costs,
+// greying out those the village can't craft yet.
+func buildRecipeResponse(session *GameSession, recipeType string) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	screen := craftScreens[recipeType]
+
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg(screen.title, "system"),
+		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("Crafter skill: %d (better villagers make finer work)", game.CrafterSkill(village)), "system"),
+		Msg(fmt.Sprintf("Work queue: %d/%d jobs, crafting %d work/tick", len(village.Jobs), game.MaxVillageJobs,
+			game.TaskWork(village, game.TaskCrafting)), "system"),
+		Msg("", "system"),
+		Msg("Available Recipes:", "system"),
+	}
+
+	options := []MenuOption{}
+	for i, r := range game.KnownRecipesOfType(player, recipeType) {
+		label := fmt.Sprintf("%s (%s)", r.Name, game.RecipeCostText(r))
+		if r.Description != "" {
+			label += " -> " + r.Description
+		}
+		if reason := game.RecipeLocked(player, village, r); reason != "" {
+			options = append(options, OptDisabled(strconv.Itoa(i+1), label+" ["+reason+"]"))
+		} else {
+			options = append(options, Opt(strconv.Itoa(i+1), label))
+		}
+	}
+	options = append(options, Opt("0", "Back"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: screen.state, Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
+// recipeDiscoveryMessage announces a recipe learned from loot or a quest.
+func recipeDiscoveryMessage(r models.CraftingRecipe) GameMessage {
+	return Msg(fmt.Sprintf("You found a recipe: %s! Craft it in your village.", r.Name), "loot")
+}
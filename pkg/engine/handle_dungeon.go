@@ -376,7 +376,7 @@ func (e *Engine) handleDungeonTreasure(session *GameSession, room *models.Dungeo
 	}
 
 	for _, item := range room.Loot {
-		game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+		game.LootItem(player, item, game.ReasonDungeonLoot, dungeonRef(player))
 		msgs = append(msgs, Msg(fmt.Sprintf("Found: %s (Rarity %d, CP:%d)", item.Name, item.Rarity, item.CP), "loot"))
 	}
 
@@ -483,10 +483,13 @@ func (e *Engine) handleDungeonMerchant(session *GameSession, room *models.Dungeo
 	// Give all merchant items to the player
 	for _, item := range room.Loot {
 		if item.ItemType == "consumable" {
-			player.Inventory = append(player.Inventory, item)
+			if !game.LootItem(player, item, game.ReasonDungeonLoot, dungeonRef(player)) {
+				msgs = append(msgs, Msg(fmt.Sprintf("No room for %s, your inventory is full.", item.Name), "error"))
+				continue
+			}
 			msgs = append(msgs, Msg(fmt.Sprintf("Received: %s", item.Name), "loot"))
 		} else {
-			game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+			game.LootItem(player, item, game.ReasonDungeonLoot, dungeonRef(player))
 			msgs = append(msgs, Msg(fmt.Sprintf("Received: %s (CP:%d)", item.Name, item.CP), "loot"))
 		}
 	}
@@ -530,25 +533,19 @@ func (e *Engine) handleDungeonInvestigation(session *GameSession, room *models.D
 		if player.ResourceStorageMap == nil {
 			player.ResourceStorageMap = make(map[string]models.Resource)
 		}
-		goldRes := player.ResourceStorageMap["Gold"]
-		goldRes.Name = "Gold"
-		goldRes.Stock += goldAmount
-		player.ResourceStorageMap["Gold"] = goldRes
+		game.AdjustGold(player, goldAmount, game.ReasonDungeonTreasure, dungeonRef(player))
 		msgs = append(msgs, Msg(fmt.Sprintf("Found %d Gold!", goldAmount), "loot"))
 
 		// Village resources (random type)
 		resourceTypes := []string{"Lumber", "Iron", "Sand", "Stone"}
 		resName := resourceTypes[rand.Intn(len(resourceTypes))]
 		resAmount := 2 + rand.Intn(floorNum+1)
-		res := player.ResourceStorageMap[resName]
-		res.Name = resName
-		res.Stock += resAmount
-		player.ResourceStorageMap[resName] = res
+		game.AdjustResource(player, resName, resAmount, game.ReasonDungeonTreasure, dungeonRef(player))
 		msgs = append(msgs, Msg(fmt.Sprintf("Found %d %s!", resAmount, resName), "loot"))
 
 		// Equipment from pre-generated loot
 		for _, item := range room.Loot {
-			game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+			game.LootItem(player, item, game.ReasonDungeonLoot, dungeonRef(player))
 			msgs = append(msgs, Msg(fmt.Sprintf("Found: %s (Rarity %d, CP:%d)", item.Name, item.Rarity, item.CP), "loot"))
 		}
 
@@ -569,7 +566,7 @@ func (e *Engine) handleDungeonInvestigation(session *GameSession, room *models.D
 		// Still get partial loot even from trapped rooms
 		if len(room.Loot) > 0 {
 			item := room.Loot[0]
-			game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+			game.LootItem(player, item, game.ReasonDungeonLoot, dungeonRef(player))
 			msgs = append(msgs, Msg(fmt.Sprintf("Salvaged: %s (CP:%d)", item.Name, item.CP), "loot"))
 		}
 
@@ -584,10 +581,7 @@ func (e *Engine) handleDungeonInvestigation(session *GameSession, room *models.D
 		if player.ResourceStorageMap == nil {
 			player.ResourceStorageMap = make(map[string]models.Resource)
 		}
-		goldRes := player.ResourceStorageMap["Gold"]
-		goldRes.Name = "Gold"
-		goldRes.Stock += goldAmount
-		player.ResourceStorageMap["Gold"] = goldRes
+		game.AdjustGold(player, goldAmount, game.ReasonDungeonTreasure, dungeonRef(player))
 		msgs = append(msgs, Msg(fmt.Sprintf("Found %d Gold!", goldAmount), "loot"))
 
 		// Multiple village resources
@@ -595,10 +589,7 @@ func (e *Engine) handleDungeonInvestigation(session *GameSession, room *models.D
 		for i := 0; i < 2; i++ {
 			resName := resourceTypes[rand.Intn(len(resourceTypes))]
 			resAmount := 3 + rand.Intn(floorNum*2+1)
-			res := player.ResourceStorageMap[resName]
-			res.Name = resName
-			res.Stock += resAmount
-			player.ResourceStorageMap[resName] = res
+			game.AdjustResource(player, resName, resAmount, game.ReasonDungeonTreasure, dungeonRef(player))
 			msgs = append(msgs, Msg(fmt.Sprintf("Found %d %s!", resAmount, resName), "loot"))
 		}
 
@@ -617,7 +608,7 @@ func (e *Engine) handleDungeonInvestigation(session *GameSession, room *models.D
 
 		// All pre-generated loot
 		for _, item := range room.Loot {
-			game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+			game.LootItem(player, item, game.ReasonDungeonLoot, dungeonRef(player))
 			msgs = append(msgs, Msg(fmt.Sprintf("Found: %s (Rarity %d, CP:%d)", item.Name, item.Rarity, item.CP), "loot"))
 		}
 
@@ -725,6 +716,14 @@ func (e *Engine) handleDungeonDefeat(session *GameSession, msgs []GameMessage) G
 	}
 }
 
+// dungeonRef is the ledger source reference for the player's current dungeon floor.
+func dungeonRef(player *models.Character) string {
+	if player.ActiveDungeon == nil {
+		return "dungeon"
+	}
+	return fmt.Sprintf("dungeon:%s:%d", player.ActiveDungeon.Name, player.ActiveDungeon.CurrentFloor+1)
+}
+
 // makeDungeonView creates a DungeonView from a dungeon model.
 func makeDungeonView(dungeon *models.Dungeon) *DungeonView {
 	if dungeon == nil {
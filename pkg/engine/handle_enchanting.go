@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// enchantTarget is a piece of player or guard gear picked at the enchanting
+// station.
+type enchantTarget struct {
+	owner     string
+	equipment map[int]models.Item
+	slot      int
+	guard     *models.Guard // nil for the player's own gear
+}
+
+// resolveEnchantTarget parses "p:<slot>" or "g:<guard>:<slot>".
+func resolveEnchantTarget(player *models.Character, village *models.Village, key string) (enchantTarget, bool) {
+	parts := strings.Split(key, ":")
+	t := enchantTarget{owner: "your", equipment: player.EquipmentMap}
+	switch {
+	case len(parts) == 2 && parts[0] == "p":
+	case len(parts) == 3 && parts[0] == "g":
+		idx, err := strconv.Atoi(parts[1])
+		if err != nil || idx < 0 || idx >= len(village.ActiveGuards) {
+			return t, false
+		}
+		t.guard = &village.ActiveGuards[idx]
+		t.owner = t.guard.Name + "'s"
+		t.equipment = t.guard.EquipmentMap
+	default:
+		return t, false
+	}
+	slot, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return t, false
+	}
+	if _, ok := t.equipment[slot]; !ok {
+		return t, false
+	}
+	t.slot = slot
+	return t, true
+}
+
+// handleEnchanting sets runes and skill scroll infusions into the sockets of
+// player and guard gear at the village Enchanting Station.
+func (e *Engine) handleEnchanting(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	ref := "village:" + village.Name
+	msgs := []GameMessage{}
+
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.EnchantTarget = ""
+		session.State = StateVillageMain
+		return e.handleVillageMain(session, GameCommand{Type: "init"})
+
+	case cmd.Value == "build":
+		msgs = append(msgs, e.queueFirstBuilding(session, game.BuildingEnchanting)...)
+
+	case cmd.Value == "cancel":
+		session.EnchantTarget = ""
+
+	case action == "target":
+		if _, ok := resolveEnchantTarget(player, village, arg); ok {
+			session.EnchantTarget = arg
+		}
+
+	case (action == "apply" || action == "scroll") && game.HasBuilding(village, game.BuildingEnchanting):
+		target, ok := resolveEnchantTarget(player, village, session.EnchantTarget)
+		if !ok {
+			session.EnchantTarget = ""
+			break
+		}
+		item := target.equipment[target.slot]
+		var ench game.Enchantment
+		var held bool
+		var err error
+		if action == "apply" {
+			var found bool
+			if ench, found = game.FindEnchantment(arg); !found {
+				break
+			}
+			held, err = game.EnchantItem(player, village, &item, ench, ref)
+		} else {
+			idx, convErr := strconv.Atoi(arg)
+			if convErr != nil {
+				break
+			}
+			ench, held, err = game.InfuseScroll(player, village, &item, idx, ref)
+		}
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		if held {
+			target.equipment[target.slot] = item
+			if target.guard != nil {
+				target.guard.StatsMod = game.CalculateItemMods(target.guard.EquipmentMap)
+			} else {
+				game.RecalculatePlayerStats(player)
+			}
+			village.Experience += 5
+			msgs = append(msgs, Msg(fmt.Sprintf("%s takes hold in %s %s: %s! (+5 Village XP)",
+				ench.Name, target.owner, item.Name, game.EffectDescription(ench.Effect, ench.Value)), "loot"))
+		} else {
+			msgs = append(msgs, Msg(fmt.Sprintf("The %s fizzles out. The materials are lost.", ench.Name), "error"))
+		}
+		if e.metrics != nil {
+			e.metrics.RecordFeatureUse("enchant")
+		}
+		e.saveVillage(session)
+	}
+
+	session.State = StateEnchanting
+	resp := buildEnchantingResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildEnchantingResponse lists socketable gear, or, once a piece has been
+// picked, the runes and scrolls that can go into it.
+func buildEnchantingResponse(session *GameSession) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+
+	msgs := []GameMessage{Msg("============ Enchanting Station ============", "system")}
+	options := []MenuOption{}
+	resp := func() GameResponse {
+		return GameResponse{
+			Type:     "menu",
+			Messages: msgs,
+			State:    &StateData{Screen: "enchanting", Player: MakePlayerState(player), Village: MakeVillageView(village)},
+			Options:  options,
+		}
+	}
+
+	if !game.HasBuilding(village, game.BuildingEnchanting) {
+		gate, gateOptions := missingBuildingMenu(village, game.BuildingEnchanting)
+		msgs = append(msgs, gate...)
+		options = append(options, gateOptions...)
+		return resp()
+	}
+
+	msgs = append(msgs, Msg(fmt.Sprintf("Gold: %d | Beast materials: %d", game.GoldBalance(player), game.BeastMaterialStock(player)), "system"))
+
+	target, ok := resolveEnchantTarget(player, village, session.EnchantTarget)
+	if !ok {
+		msgs = append(msgs, Msg("Rarity 2+ gear has sockets: 1 at rarity 2, 2 at rarity 4, 3 at rarity 6.", "system"))
+		msgs = append(msgs, Msg("Pick a piece of gear to enchant:", "system"))
+		options = append(options, enchantTargetOptions("p", "", player.EquipmentMap)...)
+		for i, guard := range village.ActiveGuards {
+			options = append(options, enchantTargetOptions(fmt.Sprintf("g:%d", i), guard.Name+": ", guard.EquipmentMap)...)
+		}
+		if len(options) == 0 {
+			msgs = append(msgs, Msg("  No equipped gear has a free socket.", "system"))
+		}
+		options = append(options, Opt("back", "Back"))
+		return resp()
+	}
+
+	item := target.equipment[target.slot]
+	owner := "Your"
+	if target.guard != nil {
+		owner = target.owner
+	}
+	msgs = append(msgs, Msg(fmt.Sprintf("%s %s [%s] (Rarity %d, CP:%d) - %d/%d sockets filled",
+		owner, item.Name, SlotNames[target.slot], item.Rarity, item.CP,
+		len(item.Enchantments), game.SocketsForRarity(item.Rarity)), "system"))
+	for _, line := range game.AffixDescriptions(item) {
+		msgs = append(msgs, Msg("  "+line, "system"))
+	}
+	msgs = append(msgs, Msg(fmt.Sprintf("Each attempt costs %d gold and has a %d%% chance to hold. A failed attempt loses its materials.",
+		game.EnchantGoldCost(item), game.EnchantSuccessChance(village, item)), "system"))
+
+	if game.FreeSockets(item) > 0 {
+		for _, ench := range game.Enchantments {
+			options = append(options, Opt("apply:"+ench.ID, fmt.Sprintf("%s: %s (%s)",
+				ench.Name, game.EffectDescription(ench.Effect, ench.Value), game.MaterialsText(ench.Materials))))
+		}
+		for i, scroll := range player.Inventory {
+			ench, err := game.ScrollEnchantment(scroll)
+			if err != nil {
+				continue
+			}
+			options = append(options, Opt(fmt.Sprintf("scroll:%d", i), fmt.Sprintf("Infuse %s: %s (scroll + %d beast materials)",
+				scroll.Name, game.EffectDescription(ench.Effect, ench.Value), game.ScrollInfuseMaterial)))
+		}
+	} else {
+		msgs = append(msgs, Msg("Every socket is filled.", "system"))
+	}
+	options = append(options, Opt("cancel", "Choose Other Gear"))
+	options = append(options, Opt("back", "Back"))
+	return resp()
+}
+
+// enchantTargetOptions offers each equipped item with a free socket.
+func enchantTargetOptions(prefix, label string, equipment map[int]models.Item) []MenuOption {
+	options := []MenuOption{}
+	for _, slot := range game.EquipmentSlots(equipment) {
+		item := equipment[slot]
+		if game.FreeSockets(item) == 0 {
+			continue
+		}
+		options = append(options, Opt(fmt.Sprintf("target:%s:%d", prefix, slot), fmt.Sprintf("%s%s [%s] (%d free sockets)",
+			label, item.Name, SlotNames[slot], game.FreeSockets(item))))
+	}
+	return options
+}
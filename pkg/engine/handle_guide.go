@@ -1,5 +1,11 @@
 package engine
 
+import (
+	"fmt"
+
+	"rpg-game/pkg/config"
+)
+
 // handleGuideMain displays the player guide topic menu.
 func (e *Engine) handleGuideMain(session *GameSession, cmd GameCommand) GameResponse {
 	if cmd.Value == "0" || cmd.Value == "back" {
@@ -85,7 +91,7 @@ func guideContentCombat() []GameMessage {
 		Msg("Combat is turn-based. Each turn you choose an action, then the monster acts.", "narrative"),
 		Msg("", "system"),
 		Msg("ACTIONS:", "system"),
-		Msg("  1. Attack - Normal physical attack (15% crit chance for double damage)", "narrative"),
+		Msg(fmt.Sprintf("  1. Attack - Normal physical attack (%d%% crit chance for double damage)", config.Current().Balance.PlayerCritChance), "narrative"),
 		Msg("  2. Defend - +50% defense but only 50% attack power", "narrative"),
 		Msg("  3. Use Item - Consume a potion or other consumable from inventory", "narrative"),
 		Msg("  4. Use Skill - Cast a spell or ability (costs Mana or Stamina)", "narrative"),
@@ -0,0 +1,907 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// guildChatShown is how many chat lines the guild screens show.
+const guildChatShown = 10
+
+var errNoGuild = errors.New("you are not in a guild")
+
+// guildDay is the day guild bank allowances are counted against.
+func guildDay() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// handleGuild shows the player's guild, or their invitations when they have
+// none, and routes to the guild's other screens.
+func (e *Engine) handleGuild(session *GameSession, cmd GameCommand) GameResponse {
+	if cmd.Value == "back" {
+		session.State = StateMainMenu
+		return BuildMainMenuResponse(session)
+	}
+	if e.store == nil {
+		session.State = StateMainMenu
+		resp := BuildMainMenuResponse(session)
+		resp.Messages = append([]GameMessage{Msg("Guilds require a database connection.", "error")}, resp.Messages...)
+		return resp
+	}
+
+	player := session.Player
+	now := time.Now().Unix()
+	msgs := []GameMessage{}
+
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch action {
+	case "create":
+		session.State = StateGuildCreate
+		return GameResponse{
+			Type:     "menu",
+			Messages: []GameMessage{Msg(fmt.Sprintf("Founding a guild costs %d gold.", data.GuildCreateCost), "system")},
+			State:    &StateData{Screen: "guild_create", Player: MakePlayerState(player)},
+			Options:  []MenuOption{Opt("back", "Cancel")},
+			Prompt:   "Guild name: ",
+		}
+
+	case "join", "decline":
+		id, _ := strconv.ParseInt(arg, 10, 64)
+		g, err := e.changeGuild(func() (*models.Guild, error) { return e.store.LoadGuild(id) }, func(g *models.Guild) error {
+			if action == "decline" {
+				game.DeclineGuildInvite(g, session.AccountID, player.Name)
+				return nil
+			}
+			if current, err := e.store.LoadCharacterGuild(session.AccountID, player.Name); err != nil || current != nil {
+				return fmt.Errorf("leave your guild before joining another")
+			}
+			return game.JoinGuild(g, session.AccountID, player.Name, now)
+		})
+		switch {
+		case err != nil:
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		case action == "join":
+			msgs = append(msgs, Msg(fmt.Sprintf("You joined %s!", g.Name), "loot"))
+			e.broadcastToGuild(g, session.ID, guildNotice(fmt.Sprintf("%s joined the guild.", player.Name)))
+		default:
+			msgs = append(msgs, Msg(fmt.Sprintf("You declined the invitation to %s.", g.Name), "system"))
+		}
+
+	case "invite":
+		session.State = StateGuildInvite
+		return e.buildGuildInviteResponse(session, nil)
+
+	case "roster":
+		session.State = StateGuildRoster
+		return e.buildGuildRosterResponse(session, nil)
+
+	case "chat":
+		session.State = StateGuildChat
+		return e.buildGuildChatResponse(session, nil)
+
+	case "bank":
+		session.State = StateGuildBank
+		return e.buildGuildBankResponse(session, nil)
+
+	case "leave":
+		if arg != "yes" {
+			return GameResponse{
+				Type:     "menu",
+				Messages: []GameMessage{Msg("Leave your guild? A leader who leaves hands the guild to the next in rank.", "system")},
+				State:    &StateData{Screen: "guild", Player: MakePlayerState(player)},
+				Options:  []MenuOption{Opt("leave:yes", "Leave the Guild"), Opt("list", "Stay")},
+			}
+		}
+		g, err := e.updateGuild(session, func(g *models.Guild, me int) error {
+			game.LeaveGuild(g, me)
+			return nil
+		})
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("You left %s.", g.Name), "system"))
+		if len(g.Members) == 0 {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s has disbanded.", g.Name), "system"))
+		}
+		e.broadcastToGuild(g, session.ID, guildNotice(fmt.Sprintf("%s left the guild.", player.Name)))
+
+	case "top":
+		msgs = append(msgs, e.guildLeaderboardMessages()...)
+	}
+
+	session.State = StateGuild
+	resp := e.buildGuildResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// handleGuildCreate founds a guild with the name the player entered.
+func (e *Engine) handleGuildCreate(session *GameSession, cmd GameCommand) GameResponse {
+	session.State = StateGuild
+	if cmd.Value == "back" || strings.TrimSpace(cmd.Value) == "" {
+		return e.buildGuildResponse(session)
+	}
+
+	player := session.Player
+	msgs := []GameMessage{}
+	g, err := game.NewGuild(cmd.Value, session.AccountID, player, time.Now().Unix())
+	if err == nil {
+		var current *models.Guild
+		if current, err = e.store.LoadCharacterGuild(session.AccountID, player.Name); err == nil && current != nil {
+			err = fmt.Errorf("you are already in %s", current.Name)
+		}
+	}
+	if err == nil {
+		e.guildMu.Lock()
+		err = e.store.CreateGuild(&g)
+		e.guildMu.Unlock()
+	}
+	if err != nil {
+		msgs = append(msgs, Msg(err.Error(), "error"))
+	} else {
+		game.PayGuildFounding(&g, player)
+		e.saveSession(session)
+		msgs = append(msgs, Msg(fmt.Sprintf("You founded %s! (-%d gold)", g.Name, data.GuildCreateCost), "loot"))
+	}
+
+	resp := e.buildGuildResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// handleGuildInvite invites an online player picked from the list, or a
+// character named by the player, to the guild.
+func (e *Engine) handleGuildInvite(session *GameSession, cmd GameCommand) GameResponse {
+	if cmd.Value == "back" || cmd.Value == "list" {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+
+	var accountID int64
+	name := strings.TrimSpace(cmd.Value)
+	var err error
+	if rest, ok := strings.CutPrefix(cmd.Value, "invite:"); ok {
+		var idStr string
+		idStr, name, _ = strings.Cut(rest, ":")
+		accountID, _ = strconv.ParseInt(idStr, 10, 64)
+	} else {
+		var ids []int64
+		ids, err = e.store.FindCharacterAccounts(name)
+		switch {
+		case err != nil:
+		case len(ids) == 0:
+			err = fmt.Errorf("no character called %q", name)
+		case len(ids) > 1:
+			err = fmt.Errorf("several characters are called %q; invite them from the online list", name)
+		default:
+			accountID = ids[0]
+		}
+	}
+	if err == nil {
+		if other, loadErr := e.store.LoadCharacterGuild(accountID, name); loadErr != nil || other != nil {
+			err = fmt.Errorf("%s is already in a guild", name)
+		}
+	}
+
+	var g *models.Guild
+	if err == nil {
+		g, err = e.updateGuild(session, func(g *models.Guild, me int) error {
+			return game.InviteToGuild(g, me, accountID, name, time.Now().Unix())
+		})
+	}
+	msgs := []GameMessage{}
+	if err != nil {
+		msgs = append(msgs, Msg(err.Error(), "error"))
+	} else {
+		msgs = append(msgs, Msg(fmt.Sprintf("You invited %s to %s.", name, g.Name), "system"))
+		e.pushToCharacter(accountID, name, guildNotice(fmt.Sprintf("%s invited you to join the guild %s. Open the Guild menu to answer.", session.Player.Name, g.Name)))
+	}
+	return e.buildGuildInviteResponse(session, msgs)
+}
+
+// handleGuildRoster promotes, demotes and kicks guild members.
+func (e *Engine) handleGuildRoster(session *GameSession, cmd GameCommand) GameResponse {
+	if cmd.Value == "back" || cmd.Value == "list" {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+
+	action, rest, _ := strings.Cut(cmd.Value, ":")
+	idStr, name, _ := strings.Cut(rest, ":")
+	accountID, _ := strconv.ParseInt(idStr, 10, 64)
+
+	var rank string
+	g, err := e.updateGuild(session, func(g *models.Guild, me int) error {
+		target := game.GuildMemberIndex(g, accountID, name)
+		var err error
+		switch action {
+		case "promote":
+			err = game.PromoteGuildMember(g, me, target)
+		case "demote":
+			err = game.DemoteGuildMember(g, me, target)
+		case "kick":
+			err = game.KickFromGuild(g, me, target)
+		default:
+			return fmt.Errorf("unknown roster action")
+		}
+		if err == nil && action != "kick" {
+			rank = game.FindGuildRank(g.Members[target].Rank).Name
+		}
+		return err
+	})
+
+	msgs := []GameMessage{}
+	switch {
+	case err != nil:
+		msgs = append(msgs, Msg(err.Error(), "error"))
+	case action == "kick":
+		msgs = append(msgs, Msg(fmt.Sprintf("%s was removed from the guild.", name), "system"))
+		e.broadcastToGuild(g, session.ID, guildNotice(fmt.Sprintf("%s removed %s from the guild.", session.Player.Name, name)))
+		e.pushToCharacter(accountID, name, guildNotice(fmt.Sprintf("You were removed from %s.", g.Name)))
+	default:
+		msgs = append(msgs, Msg(fmt.Sprintf("%s is now %s.", name, rank), "system"))
+		e.broadcastToGuild(g, session.ID, guildNotice(fmt.Sprintf("%s is now %s.", name, rank)))
+	}
+	return e.buildGuildRosterResponse(session, msgs)
+}
+
+// handleGuildChat posts what the player typed to the guild chat and sends it
+// to every online member.
+func (e *Engine) handleGuildChat(session *GameSession, cmd GameCommand) GameResponse {
+	if cmd.Value == "back" || cmd.Value == "list" {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+
+	var line models.GuildChatLine
+	g, err := e.updateGuild(session, func(g *models.Guild, me int) error {
+		var err error
+		line, err = game.AddGuildChat(g, session.Player.Name, cmd.Value, time.Now().Unix())
+		return err
+	})
+	if err != nil {
+		return e.buildGuildChatResponse(session, []GameMessage{Msg(err.Error(), "error")})
+	}
+	e.broadcastToGuild(g, session.ID, GameResponse{
+		Type:     "guild_chat",
+		Messages: []GameMessage{Msg(game.GuildChatText(g, line), "broadcast")},
+		State:    &StateData{Screen: "guild_chat"},
+	})
+	return e.buildGuildChatResponse(session, nil)
+}
+
+// handleGuildBank deposits and withdraws guild bank items, and asks how much
+// of a resource to move.
+func (e *Engine) handleGuildBank(session *GameSession, cmd GameCommand) GameResponse {
+	if cmd.Value == "back" || cmd.Value == "list" {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+
+	player := session.Player
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch action {
+	case "deposit", "withdraw":
+		session.GuildBankAction = cmd.Value
+		session.State = StateGuildBankAmount
+		return GameResponse{
+			Type:     "menu",
+			Messages: []GameMessage{Msg(fmt.Sprintf("How much %s do you want to %s?", arg, action), "system")},
+			State:    &StateData{Screen: "guild_bank", Player: MakePlayerState(player)},
+			Options:  []MenuOption{Opt("back", "Cancel")},
+			Prompt:   "Amount: ",
+		}
+	}
+
+	// Items are picked as "<index>:<name>"; the name catches a list that
+	// shifted since the screen was drawn.
+	indexStr, name, _ := strings.Cut(arg, ":")
+	index, _ := strconv.Atoi(indexStr)
+	var item models.Item
+	_, err := e.updateGuildBank(session, func(g *models.Guild, me int, player *models.Character) error {
+		var err error
+		switch action {
+		case "ditem":
+			if err = checkBankPick(player.Inventory, index, name); err == nil {
+				item, err = game.DepositItemToGuildBank(g, player, index, game.MaxStackSize)
+			}
+		case "witem":
+			if err = checkBankPick(g.Bank.Items, index, name); err == nil {
+				item, err = game.WithdrawItemFromGuildBank(g, me, player, index, game.MaxStackSize)
+			}
+		default:
+			err = fmt.Errorf("unknown bank action")
+		}
+		return err
+	})
+	msgs := []GameMessage{}
+	if err != nil {
+		msgs = append(msgs, Msg(err.Error(), "error"))
+	} else {
+		e.saveSession(session)
+		verb := "deposited"
+		if action == "witem" {
+			verb = "withdrew"
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("You %s %s x%d.", verb, item.Name, game.StackSize(item)), "loot"))
+	}
+	return e.buildGuildBankResponse(session, msgs)
+}
+
+// handleGuildBankAmount moves the amount the player entered of the resource
+// chosen on the bank screen.
+func (e *Engine) handleGuildBankAmount(session *GameSession, cmd GameCommand) GameResponse {
+	session.State = StateGuildBank
+	action, resource, _ := strings.Cut(session.GuildBankAction, ":")
+	session.GuildBankAction = ""
+	if cmd.Value == "back" {
+		return e.buildGuildBankResponse(session, nil)
+	}
+
+	amount, err := strconv.Atoi(strings.TrimSpace(cmd.Value))
+	if err != nil || amount <= 0 {
+		return e.buildGuildBankResponse(session, []GameMessage{Msg("Enter a positive amount.", "error")})
+	}
+	_, err = e.updateGuildBank(session, func(g *models.Guild, me int, player *models.Character) error {
+		if action == "deposit" {
+			return game.DepositToGuildBank(g, player, resource, amount)
+		}
+		return game.WithdrawFromGuildBank(g, me, player, resource, amount, guildDay())
+	})
+	if err != nil {
+		return e.buildGuildBankResponse(session, []GameMessage{Msg(err.Error(), "error")})
+	}
+	e.saveSession(session)
+	verb := "deposited"
+	if action == "withdraw" {
+		verb = "withdrew"
+	}
+	return e.buildGuildBankResponse(session, []GameMessage{Msg(fmt.Sprintf("You %s %d %s.", verb, amount, resource), "loot")})
+}
+
+// changeGuild loads a guild, applies change and saves it, deleting it if it
+// has no members left. guildMu is held throughout so members' changes don't
+// overwrite each other.
+func (e *Engine) changeGuild(load func() (*models.Guild, error), change func(*models.Guild) error) (*models.Guild, error) {
+	e.guildMu.Lock()
+	defer e.guildMu.Unlock()
+	g, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, fmt.Errorf("that guild no longer exists")
+	}
+	if err := change(g); err != nil {
+		return g, err
+	}
+	if len(g.Members) == 0 {
+		return g, e.store.DeleteGuild(g.ID)
+	}
+	return g, e.store.SaveGuild(*g)
+}
+
+// updateGuild is changeGuild on the session character's guild; change is
+// given the character's roster index.
+func (e *Engine) updateGuild(session *GameSession, change func(g *models.Guild, me int) error) (*models.Guild, error) {
+	var me int
+	return e.changeGuild(func() (*models.Guild, error) {
+		g, err := e.store.LoadCharacterGuild(session.AccountID, session.Player.Name)
+		if err == nil && g == nil {
+			err = errNoGuild
+		}
+		if g != nil {
+			me = game.GuildMemberIndex(g, session.AccountID, session.Player.Name)
+		}
+		return g, err
+	}, func(g *models.Guild) error { return change(g, me) })
+}
+
+// updateGuildBank is updateGuild for a bank move. change works on a copy of
+// the character's storage, inventory and pending ledger rows, which replace
+// the live character's only once the guild is saved, so a failed save can't
+// duplicate or destroy anything.
+func (e *Engine) updateGuildBank(session *GameSession, change func(g *models.Guild, me int, player *models.Character) error) (*models.Guild, error) {
+	player := session.Player
+	draft := *player
+	draft.ResourceStorageMap = maps.Clone(player.ResourceStorageMap)
+	draft.Inventory = slices.Clone(player.Inventory)
+	draft.PendingLedger = slices.Clone(player.PendingLedger)
+	g, err := e.updateGuild(session, func(g *models.Guild, me int) error { return change(g, me, &draft) })
+	if err == nil {
+		player.ResourceStorageMap, player.Inventory, player.PendingLedger = draft.ResourceStorageMap, draft.Inventory, draft.PendingLedger
+	}
+	return g, err
+}
+
+// checkBankPick reports an error if the item at index is no longer the one
+// the player picked.
+func checkBankPick(items []models.Item, index int, name string) error {
+	if index < 0 || index >= len(items) || items[index].Name != name {
+		return fmt.Errorf("that item has moved; pick it again")
+	}
+	return nil
+}
+
+// creditGuildTideDamage adds damage a character's village dealt the tide
+// leader to the character's guild, if they have one. The caller saves the
+// leader.
+func (e *Engine) creditGuildTideDamage(leader *models.TideLeader, accountID int64, name string, damage int) {
+	if damage <= 0 {
+		return
+	}
+	e.guildMu.Lock()
+	defer e.guildMu.Unlock()
+	g, err := e.store.LoadCharacterGuild(accountID, name)
+	if err != nil {
+		fmt.Printf("[TideLeader] Failed to load guild of %s: %v\n", name, err)
+		return
+	}
+	if g == nil {
+		return
+	}
+	game.AddGuildTideDamage(leader, g, damage)
+	if err := e.store.SaveGuild(*g); err != nil {
+		fmt.Printf("[TideLeader] Failed to save guild %s: %v\n", g.Name, err)
+	}
+}
+
+// guildNotice is a push telling guild members what happened.
+func guildNotice(text string) GameResponse {
+	return GameResponse{
+		Type:     "broadcast",
+		Messages: []GameMessage{Msg(text, "broadcast")},
+		State:    &StateData{Screen: "guild_notice"},
+	}
+}
+
+// broadcastToGuild sends a response to the sessions of every online member
+// of the guild except the excluded one.
+func (e *Engine) broadcastToGuild(g *models.Guild, excludeSessionID string, resp GameResponse) {
+	e.mu.RLock()
+	var targetSessionIDs []string
+	for id, sess := range e.sessions {
+		if id != excludeSessionID && sess.Player != nil && game.GuildMemberIndex(g, sess.AccountID, sess.Player.Name) >= 0 {
+			targetSessionIDs = append(targetSessionIDs, id)
+		}
+	}
+	e.mu.RUnlock()
+	e.sendToSessions(targetSessionIDs, resp)
+}
+
+// pushToCharacter sends a response to the sessions playing a character.
+func (e *Engine) pushToCharacter(accountID int64, name string, resp GameResponse) {
+	e.mu.RLock()
+	var targetSessionIDs []string
+	for id, sess := range e.sessions {
+		if sess.AccountID == accountID && sess.Player != nil && sess.Player.Name == name {
+			targetSessionIDs = append(targetSessionIDs, id)
+		}
+	}
+	e.mu.RUnlock()
+	e.sendToSessions(targetSessionIDs, resp)
+}
+
+// sendToSessions sends a response to the subscribers of the given sessions.
+func (e *Engine) sendToSessions(sessionIDs []string, resp GameResponse) {
+	e.subMu.RLock()
+	defer e.subMu.RUnlock()
+	for _, sid := range sessionIDs {
+		if cb, ok := e.subscribers[sid]; ok {
+			go cb(resp)
+		}
+	}
+}
+
+// guildOnline maps the online characters, keyed by guildMemberKey, to what
+// they are doing.
+func (e *Engine) guildOnline() map[string]string {
+	online := map[string]string{}
+	for _, p := range e.GetOnlinePlayers("") {
+		online[guildMemberKey(p.AccountID, p.Name)] = p.Activity
+	}
+	return online
+}
+
+// guildMemberKey identifies a character across accounts.
+func guildMemberKey(accountID int64, name string) string {
+	return fmt.Sprintf("%d:%s", accountID, name)
+}
+
+// makeGuildView builds the guild as member me sees it.
+func makeGuildView(g *models.Guild, me int, online map[string]string) *GuildView {
+	gv := &GuildView{
+		Name:         g.Name,
+		Rank:         game.FindGuildRank(g.Members[me].Rank).Name,
+		TideDamage:   g.TideDamage,
+		Members:      make([]GuildMemberView, 0, len(g.Members)),
+		Resources:    g.Bank.Resources,
+		Items:        make([]string, 0, len(g.Bank.Items)),
+		Chat:         make([]string, 0, len(g.Chat)),
+		WithdrawLeft: game.GuildWithdrawLeft(g, me, guildDay()),
+	}
+	for _, i := range guildRosterOrder(g) {
+		m := g.Members[i]
+		activity, ok := online[guildMemberKey(m.AccountID, m.Name)]
+		gv.Members = append(gv.Members, GuildMemberView{Name: m.Name, Rank: game.FindGuildRank(m.Rank).Name, Online: ok, Activity: activity})
+	}
+	for _, item := range g.Bank.Items {
+		gv.Items = append(gv.Items, fmt.Sprintf("%s x%d", item.Name, game.StackSize(item)))
+	}
+	for _, line := range g.Chat {
+		gv.Chat = append(gv.Chat, game.GuildChatText(g, line))
+	}
+	return gv
+}
+
+// guildRosterOrder lists the guild's member indices by rank, then name.
+func guildRosterOrder(g *models.Guild) []int {
+	order := make([]int, len(g.Members))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ma, mb := g.Members[order[a]], g.Members[order[b]]
+		if la, lb := game.FindGuildRank(ma.Rank).Level, game.FindGuildRank(mb.Rank).Level; la != lb {
+			return la < lb
+		}
+		return ma.Name < mb.Name
+	})
+	return order
+}
+
+// guildLeaderboardMessages lists the top guilds.
+func (e *Engine) guildLeaderboardMessages() []GameMessage {
+	msgs := []GameMessage{Msg("=== TOP GUILDS ===", "system")}
+	entries, err := e.store.GetLeaderboard("guilds", 10)
+	if err != nil {
+		return append(msgs, Msg("Failed to load the guild leaderboard.", "error"))
+	}
+	if len(entries) == 0 {
+		msgs = append(msgs, Msg("  No guilds yet.", "system"))
+	}
+	for i, entry := range entries {
+		msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s - %d members, %d tide leader damage, %d kills",
+			i+1, entry.Guild, entry.Members, entry.TideDamage, entry.TotalKills), "system"))
+	}
+	return msgs
+}
+
+// buildGuildResponse shows the player's guild, or their invitations.
+func (e *Engine) buildGuildResponse(session *GameSession) GameResponse {
+	player := session.Player
+	g, err := e.store.LoadCharacterGuild(session.AccountID, player.Name)
+	if err != nil {
+		return GameResponse{
+			Type:     "menu",
+			Messages: []GameMessage{Msg("Failed to load your guild.", "error")},
+			State:    &StateData{Screen: "guild", Player: MakePlayerState(player)},
+			Options:  []MenuOption{Opt("back", "Back")},
+		}
+	}
+	if g == nil {
+		return e.buildNoGuildResponse(session)
+	}
+
+	me := game.GuildMemberIndex(g, session.AccountID, player.Name)
+	view := makeGuildView(g, me, e.guildOnline())
+	rank := game.FindGuildRank(g.Members[me].Rank)
+	online := 0
+	for _, m := range view.Members {
+		if m.Online {
+			online++
+		}
+	}
+
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("GUILD: %s", g.Name), "system"),
+		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("Your rank: %s", rank.Name), "system"),
+		Msg(fmt.Sprintf("Members: %d/%d (%d online)", len(g.Members), data.GuildMaxMembers, online), "system"),
+		Msg(fmt.Sprintf("Tide leader damage: %d", g.TideDamage), "system"),
+		Msg("", "system"),
+		Msg("ROSTER:", "system"),
+	}
+	for _, m := range view.Members {
+		status := "offline"
+		if m.Online {
+			status = "online - " + m.Activity
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("  %s [%s] (%s)", m.Name, m.Rank, status), "system"))
+	}
+	if len(g.Chat) > 0 {
+		msgs = append(msgs, Msg("", "system"), Msg("RECENT CHAT:", "system"))
+		for _, line := range view.Chat[max(len(view.Chat)-guildChatShown, 0):] {
+			msgs = append(msgs, Msg("  "+line, "broadcast"))
+		}
+	}
+	msgs = append(msgs, Msg("============================================================", "system"))
+
+	options := []MenuOption{
+		Opt("chat", "Guild Chat"),
+		Opt("bank", "Guild Bank"),
+		Opt("roster", "Manage Roster"),
+	}
+	if rank.CanInvite {
+		options = append(options, Opt("invite", "Invite a Player"))
+	} else {
+		options = append(options, OptDisabled("invite", "Invite a Player (rank too low)"))
+	}
+	options = append(options, Opt("top", "Top Guilds"), Opt("leave", "Leave Guild"), Opt("back", "Back"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "guild", Player: MakePlayerState(player), Guild: view},
+		Options:  options,
+	}
+}
+
+// buildNoGuildResponse offers a guildless player their invitations and the
+// chance to found a guild.
+func (e *Engine) buildNoGuildResponse(session *GameSession) GameResponse {
+	player := session.Player
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg("GUILDS", "system"),
+		Msg("============================================================", "system"),
+		Msg("You are not in a guild. Guild members share a bank and a chat channel, and their", "system"),
+		Msg("villages' damage to the tide leader counts toward the guild leaderboard.", "system"),
+		Msg("", "system"),
+	}
+
+	options := []MenuOption{}
+	if player.ResourceStorageMap["Gold"].Stock >= data.GuildCreateCost {
+		options = append(options, Opt("create", fmt.Sprintf("Found a Guild (%d gold)", data.GuildCreateCost)))
+	} else {
+		options = append(options, OptDisabled("create", fmt.Sprintf("Found a Guild (%d gold needed)", data.GuildCreateCost)))
+	}
+
+	guilds, err := e.store.LoadAllGuilds()
+	if err != nil {
+		msgs = append(msgs, Msg("Failed to load guild invitations.", "error"))
+	}
+	invited := 0
+	for _, g := range guilds {
+		i := game.GuildInviteIndex(&g, session.AccountID, player.Name)
+		if i < 0 {
+			continue
+		}
+		invited++
+		msgs = append(msgs, Msg(fmt.Sprintf("%s invited you to %s (%d members).", g.Invites[i].From, g.Name, len(g.Members)), "system"))
+		options = append(options,
+			Opt(fmt.Sprintf("join:%d", g.ID), "Join "+g.Name),
+			Opt(fmt.Sprintf("decline:%d", g.ID), "Decline "+g.Name))
+	}
+	if invited == 0 {
+		msgs = append(msgs, Msg("You have no guild invitations.", "system"))
+	}
+	msgs = append(msgs, Msg("============================================================", "system"))
+
+	options = append(options, Opt("top", "Top Guilds"), Opt("back", "Back"))
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "guild", Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
+// buildGuildInviteResponse offers the online players outside any guild as
+// invitees, and takes any character's name.
+func (e *Engine) buildGuildInviteResponse(session *GameSession, msgs []GameMessage) GameResponse {
+	options := []MenuOption{}
+	for _, p := range e.GetOnlinePlayers(session.ID) {
+		if p.AccountID == session.AccountID && p.Name == session.Player.Name {
+			continue
+		}
+		if g, err := e.store.LoadCharacterGuild(p.AccountID, p.Name); err != nil || g != nil {
+			continue
+		}
+		options = append(options, Opt(fmt.Sprintf("invite:%d:%s", p.AccountID, p.Name), fmt.Sprintf("Invite %s (Lv%d)", p.Name, p.Level)))
+	}
+	msgs = append(msgs, Msg("Pick an online player to invite, or enter a character's name.", "system"))
+	options = append(options, Opt("list", "Back to Guild"))
+
+	session.State = StateGuildInvite
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "guild_invite", Player: MakePlayerState(session.Player)},
+		Options:  options,
+		Prompt:   "Character name: ",
+	}
+}
+
+// buildGuildRosterResponse lists the guild's members with the rank changes
+// and kicks the player may make.
+func (e *Engine) buildGuildRosterResponse(session *GameSession, msgs []GameMessage) GameResponse {
+	session.State = StateGuildRoster
+	player := session.Player
+	g, err := e.store.LoadCharacterGuild(session.AccountID, player.Name)
+	if err != nil || g == nil {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+	me := game.GuildMemberIndex(g, session.AccountID, player.Name)
+	view := makeGuildView(g, me, e.guildOnline())
+
+	msgs = append(msgs, Msg("=== GUILD ROSTER ===", "system"))
+	for _, rank := range data.GuildRanks {
+		perms := []string{}
+		if rank.CanInvite {
+			perms = append(perms, "invite")
+		}
+		if rank.CanKick {
+			perms = append(perms, "kick")
+		}
+		if rank.CanPromote {
+			perms = append(perms, "promote")
+		}
+		switch {
+		case rank.DailyWithdraw < 0:
+			perms = append(perms, "withdraw resources")
+		case rank.DailyWithdraw > 0:
+			perms = append(perms, fmt.Sprintf("withdraw %d resources a day", rank.DailyWithdraw))
+		}
+		if rank.WithdrawItems {
+			perms = append(perms, "withdraw items")
+		}
+		if len(perms) == 0 {
+			perms = append(perms, "deposit only")
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("  %s: %s", rank.Name, strings.Join(perms, ", ")), "system"))
+	}
+	msgs = append(msgs, Msg("", "system"))
+
+	options := []MenuOption{}
+	for i, j := range guildRosterOrder(g) {
+		m := g.Members[j]
+		status := "offline"
+		if view.Members[i].Online {
+			status = "online"
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("  %s [%s] (%s)", m.Name, view.Members[i].Rank, status), "system"))
+		if j == me {
+			continue
+		}
+		// Try each action on a copy of the roster to see if it's allowed.
+		allowed := func(act func(*models.Guild, int, int) error) bool {
+			probe := *g
+			probe.Members = append([]models.GuildMember(nil), g.Members...)
+			return act(&probe, me, j) == nil
+		}
+		key := guildMemberKey(m.AccountID, m.Name)
+		if allowed(game.PromoteGuildMember) {
+			options = append(options, Opt("promote:"+key, "Promote "+m.Name))
+		}
+		if allowed(game.DemoteGuildMember) {
+			options = append(options, Opt("demote:"+key, "Demote "+m.Name))
+		}
+		if allowed(game.KickFromGuild) {
+			options = append(options, Opt("kick:"+key, "Kick "+m.Name))
+		}
+	}
+	options = append(options, Opt("list", "Back to Guild"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "guild_roster", Player: MakePlayerState(player), Guild: view},
+		Options:  options,
+	}
+}
+
+// buildGuildChatResponse shows the guild chat and asks for a message.
+func (e *Engine) buildGuildChatResponse(session *GameSession, msgs []GameMessage) GameResponse {
+	session.State = StateGuildChat
+	player := session.Player
+	g, err := e.store.LoadCharacterGuild(session.AccountID, player.Name)
+	if err != nil || g == nil {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+	view := makeGuildView(g, game.GuildMemberIndex(g, session.AccountID, player.Name), e.guildOnline())
+
+	msgs = append(msgs, Msg(fmt.Sprintf("=== %s CHAT ===", strings.ToUpper(g.Name)), "system"))
+	if len(view.Chat) == 0 {
+		msgs = append(msgs, Msg("  No messages yet. Say hello!", "system"))
+	}
+	for _, line := range view.Chat[max(len(view.Chat)-guildChatShown, 0):] {
+		msgs = append(msgs, Msg("  "+line, "broadcast"))
+	}
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "guild_chat", Player: MakePlayerState(player), Guild: view},
+		Options:  []MenuOption{Opt("list", "Back to Guild")},
+		Prompt:   "Say: ",
+	}
+}
+
+// buildGuildBankResponse shows the guild bank and what the player may move
+// in and out of it.
+func (e *Engine) buildGuildBankResponse(session *GameSession, msgs []GameMessage) GameResponse {
+	session.State = StateGuildBank
+	player := session.Player
+	g, err := e.store.LoadCharacterGuild(session.AccountID, player.Name)
+	if err != nil || g == nil {
+		session.State = StateGuild
+		return e.buildGuildResponse(session)
+	}
+	me := game.GuildMemberIndex(g, session.AccountID, player.Name)
+	rank := game.FindGuildRank(g.Members[me].Rank)
+	left := game.GuildWithdrawLeft(g, me, guildDay())
+
+	msgs = append(msgs, Msg("=== GUILD BANK ===", "system"))
+	switch {
+	case left < 0:
+		msgs = append(msgs, Msg(fmt.Sprintf("As %s you may withdraw freely.", rank.Name), "system"))
+	default:
+		msgs = append(msgs, Msg(fmt.Sprintf("As %s you may withdraw %d more resources today.", rank.Name, left), "system"))
+	}
+
+	options := []MenuOption{}
+	msgs = append(msgs, Msg("Resources:", "system"))
+	if len(g.Bank.Resources) == 0 {
+		msgs = append(msgs, Msg("  none", "system"))
+	}
+	for _, name := range sortedKeys(g.Bank.Resources) {
+		amount := g.Bank.Resources[name]
+		msgs = append(msgs, Msg(fmt.Sprintf("  %s: %d", name, amount), "system"))
+		if left != 0 {
+			options = append(options, Opt("withdraw:"+name, fmt.Sprintf("Withdraw %s (%d)", name, amount)))
+		}
+	}
+	for _, name := range sortedKeys(player.ResourceStorageMap) {
+		if have := player.ResourceStorageMap[name].Stock; have > 0 {
+			options = append(options, Opt("deposit:"+name, fmt.Sprintf("Deposit %s (you have %d)", name, have)))
+		}
+	}
+
+	msgs = append(msgs, Msg(fmt.Sprintf("Items (%d/%d slots):", len(g.Bank.Items), data.GuildBankMaxItems), "system"))
+	if len(g.Bank.Items) == 0 {
+		msgs = append(msgs, Msg("  none", "system"))
+	}
+	for i, item := range g.Bank.Items {
+		label := fmt.Sprintf("%s x%d", item.Name, game.StackSize(item))
+		msgs = append(msgs, Msg("  "+label, "system"))
+		if rank.WithdrawItems {
+			options = append(options, Opt(fmt.Sprintf("witem:%d:%s", i, item.Name), "Take "+label))
+		}
+	}
+	for i, item := range player.Inventory {
+		options = append(options, Opt(fmt.Sprintf("ditem:%d:%s", i, item.Name), fmt.Sprintf("Deposit %s x%d", item.Name, game.StackSize(item))))
+	}
+	options = append(options, Opt("list", "Back to Guild"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State: &StateData{Screen: "guild_bank", Player: MakePlayerState(player),
+			Guild: makeGuildView(g, me, e.guildOnline())},
+		Options: options,
+	}
+}
+
+// sortedKeys lists a map's keys in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// handleInventory shows the player's item stacks and lets them sort, filter,
+// use and discard them.
+func (e *Engine) handleInventory(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	msgs := []GameMessage{}
+
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.InventoryFilter = ""
+		session.State = StateMainMenu
+		return BuildMainMenuResponse(session)
+
+	case strings.HasPrefix(cmd.Value, "sort:"):
+		if err := game.SortInventory(player.Inventory, strings.TrimPrefix(cmd.Value, "sort:")); err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		} else {
+			e.saveSession(session)
+		}
+
+	case strings.HasPrefix(cmd.Value, "filter:"):
+		session.InventoryFilter = strings.TrimPrefix(cmd.Value, "filter:")
+
+	case strings.HasPrefix(cmd.Value, "use:"):
+		idx, err := strconv.Atoi(strings.TrimPrefix(cmd.Value, "use:"))
+		if err != nil || idx < 0 || idx >= len(player.Inventory) || player.Inventory[idx].ItemType != "consumable" {
+			msgs = append(msgs, Msg("That item can't be used here.", "error"))
+			break
+		}
+		item, _ := game.UseInventoryItem(player, idx, game.ReasonItemUsed, "inventory")
+		msgs = append(msgs, Msg(fmt.Sprintf("Used %s. HP %d/%d, MP %d/%d, SP %d/%d", item.Name,
+			player.HitpointsRemaining, player.HitpointsTotal, player.ManaRemaining, player.ManaTotal,
+			player.StaminaRemaining, player.StaminaTotal), "heal"))
+		if e.metrics != nil {
+			e.metrics.RecordItemUse(item.Name)
+		}
+		e.saveSession(session)
+
+	case strings.HasPrefix(cmd.Value, "drop:"):
+		idx, err := strconv.Atoi(strings.TrimPrefix(cmd.Value, "drop:"))
+		if err != nil || idx < 0 || idx >= len(player.Inventory) {
+			break
+		}
+		item := game.TakeFromInventory(&player.Inventory, idx, game.StackSize(player.Inventory[idx]))
+		game.RecordItemChange(player, item.Name, -game.StackSize(item), game.ReasonDiscard, "inventory")
+		msgs = append(msgs, Msg(fmt.Sprintf("Discarded %s%s.", item.Name, stackSuffix(item)), "system"))
+		e.saveSession(session)
+	}
+
+	session.State = StateInventory
+	resp := buildInventoryResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildInventoryResponse lists the inventory entries that match the
+// session's filter.
+func buildInventoryResponse(session *GameSession) GameResponse {
+	player := session.Player
+	filter := session.InventoryFilter
+	if filter == "" {
+		filter = "all"
+	}
+
+	msgs := []GameMessage{
+		Msg("============ Inventory ============", "system"),
+		Msg(fmt.Sprintf("Slots: %d/%d | Showing: %s", len(player.Inventory), game.InventoryCapacity(player), itemTypeLabel(filter)), "system"),
+	}
+	options := []MenuOption{}
+
+	indices := game.FilterInventory(player.Inventory, filter)
+	if len(indices) == 0 {
+		msgs = append(msgs, Msg("  Nothing here.", "system"))
+	}
+	for _, idx := range indices {
+		item := player.Inventory[idx]
+		msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s", idx+1, itemLine(item)), "system"))
+		if item.ItemType == "consumable" {
+			options = append(options, Opt(fmt.Sprintf("use:%d", idx), fmt.Sprintf("Use %s%s", item.Name, stackSuffix(item))))
+		}
+	}
+	for _, idx := range indices {
+		item := player.Inventory[idx]
+		options = append(options, Opt(fmt.Sprintf("drop:%d", idx), fmt.Sprintf("Discard %s%s", item.Name, stackSuffix(item))))
+	}
+
+	for _, key := range game.InventorySortKeys {
+		options = append(options, Opt("sort:"+key, "Sort by "+key))
+	}
+	for _, f := range game.InventoryFilters {
+		if f == filter {
+			options = append(options, OptDisabled("filter:"+f, "Show "+itemTypeLabel(f)))
+		} else {
+			options = append(options, Opt("filter:"+f, "Show "+itemTypeLabel(f)))
+		}
+	}
+	options = append(options, Opt("back", "Return to Main Menu"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "inventory", Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
+// handleVillageStash moves item stacks between the inventory and the
+// village stash. "deposit:N" and "withdraw:N" move a whole stack;
+// "deposit:N:Q" moves Q items of it.
+func (e *Engine) handleVillageStash(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	msgs := []GameMessage{}
+
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch action {
+	case "back", "0":
+		session.State = StateVillageMain
+		return e.handleVillageMain(session, GameCommand{Type: "init"})
+
+	case "deposit", "withdraw":
+		idxStr, qtyStr, _ := strings.Cut(arg, ":")
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil {
+			break
+		}
+		qty := 0
+		if qtyStr != "" {
+			qty, _ = strconv.Atoi(qtyStr)
+		}
+		move, verb := game.DepositToStash, "Stored"
+		source := player.Inventory
+		if action == "withdraw" {
+			move, verb = game.WithdrawFromStash, "Took"
+			source = village.Stash
+		}
+		if qty <= 0 && idx >= 0 && idx < len(source) {
+			qty = game.StackSize(source[idx])
+		}
+		item, err := move(player, village, idx, qty)
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("%s %s%s.", verb, item.Name, stackSuffix(item)), "system"))
+		e.saveVillage(session)
+	}
+
+	session.State = StateVillageStash
+	resp := buildVillageStashResponse(player, village)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildVillageStashResponse lists the stash and the inventory side by side.
+func buildVillageStashResponse(player *models.Character, village *models.Village) GameResponse {
+	msgs := []GameMessage{
+		Msg("============ Village Stash ============", "system"),
+		Msg(fmt.Sprintf("Stash: %d/%d slots (grows with village level) | Inventory: %d/%d slots",
+			len(village.Stash), game.StashCapacity(village), len(player.Inventory), game.InventoryCapacity(player)), "system"),
+	}
+	options := []MenuOption{}
+
+	msgs = append(msgs, Msg("", "system"), Msg("STASH:", "system"))
+	if len(village.Stash) == 0 {
+		msgs = append(msgs, Msg("  Empty.", "system"))
+	}
+	for i, item := range village.Stash {
+		msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s", i+1, itemLine(item)), "system"))
+		options = append(options, Opt(fmt.Sprintf("withdraw:%d", i), fmt.Sprintf("Take %s%s", item.Name, stackSuffix(item))))
+		if item.Quantity > 1 {
+			options = append(options, Opt(fmt.Sprintf("withdraw:%d:1", i), fmt.Sprintf("Take 1 %s", item.Name)))
+		}
+	}
+
+	msgs = append(msgs, Msg("", "system"), Msg("INVENTORY:", "system"))
+	if len(player.Inventory) == 0 {
+		msgs = append(msgs, Msg("  Empty.", "system"))
+	}
+	for i, item := range player.Inventory {
+		msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s", i+1, itemLine(item)), "system"))
+		options = append(options, Opt(fmt.Sprintf("deposit:%d", i), fmt.Sprintf("Store %s%s", item.Name, stackSuffix(item))))
+	}
+	options = append(options, Opt("back", "Back"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "village_stash", Player: MakePlayerState(player), Village: MakeVillageView(village)},
+		Options:  options,
+	}
+}
+
+// stackSuffix renders the size of a stack of more than one item.
+func stackSuffix(item models.Item) string {
+	if n := game.StackSize(item); n > 1 {
+		return fmt.Sprintf(" x%d", n)
+	}
+	return ""
+}
+
+// itemLine describes an inventory entry on one line.
+func itemLine(item models.Item) string {
+	switch item.ItemType {
+	case "consumable":
+		return fmt.Sprintf("%s%s (%s %d)", item.Name, stackSuffix(item), item.Consumable.EffectType, item.Consumable.Value)
+	case "skill_scroll":
+		return fmt.Sprintf("%s%s (Skill: %s)", item.Name, stackSuffix(item), item.SkillScroll.Skill.Name)
+	case "equipment":
+		line := fmt.Sprintf("%s [%s] (Rarity %d, CP:%d", item.Name, SlotNames[item.Slot], item.Rarity, item.CP)
+		if label := game.DurabilityLabel(item); label != "" {
+			line += ", " + label
+		}
+		return line + ")"
+	}
+	return item.Name + stackSuffix(item)
+}
+
+// itemTypeLabel names an inventory filter for display.
+func itemTypeLabel(filter string) string {
+	switch filter {
+	case "all":
+		return "everything"
+	case "consumable":
+		return "consumables"
+	case "skill_scroll":
+		return "skill scrolls"
+	case "repair_kit":
+		return "repair kits"
+	}
+	return filter
+}
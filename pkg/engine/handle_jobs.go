@@ -0,0 +1,233 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// handleVillageJobs shows the village work queue and cancels jobs.
+func (e *Engine) handleVillageJobs(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	village := session.SelectedVillage
+	msgs := []GameMessage{}
+
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.State = StateVillageMain
+		return e.handleVillageMain(session, GameCommand{Type: "init"})
+
+	case action == "cancel":
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			break
+		}
+		refund, err := game.CancelJob(player, village, id)
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		msgs = append(msgs, Msg("Job cancelled. Refunded: "+refundText(refund), "system"))
+		e.saveVillage(session)
+	}
+
+	session.State = StateVillageJobs
+	resp := buildVillageJobsResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildVillageJobsResponse lists queued jobs with their progress.
+func buildVillageJobsResponse(session *GameSession) GameResponse {
+	village := session.SelectedVillage
+	tick := config.Current().Tickers.VillageJobSeconds
+
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("WORK QUEUE (%d/%d)", len(village.Jobs), game.MaxVillageJobs), "system"),
+		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("Crafting: %d work/tick | Building: %d work/tick",
+			game.TaskWork(village, game.TaskCrafting), game.TaskWork(village, game.TaskBuilding)), "system"),
+		Msg("Assign crafters and builders from Assign Villager Tasks to speed things up.", "system"),
+		Msg(fmt.Sprintf("Cancelling refunds %d%% of a job's cost, less the work already done.",
+			config.Current().Balance.JobRefundPercent), "system"),
+		Msg("", "system"),
+	}
+
+	options := []MenuOption{}
+	if len(village.Jobs) == 0 {
+		msgs = append(msgs, Msg("Nothing queued. Crafting and building orders show up here.", "system"))
+	}
+	for i, job := range village.Jobs {
+		status := "waiting"
+		if game.ActiveJob(village, game.JobTask(job.Kind)) == i {
+			status = "in progress"
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("%d. %s [%s] %d/%d work, %s - ready in ~%ds",
+			i+1, job.Name, game.JobTask(job.Kind), job.Progress, job.Work, status, game.JobTicksLeft(village, i)*tick), "system"))
+		options = append(options, Opt(fmt.Sprintf("cancel:%d", job.ID), "Cancel "+job.Name))
+	}
+	options = append(options, Opt("back", "Back"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "village_jobs", Player: MakePlayerState(session.Player), Village: MakeVillageView(village)},
+		Options:  options,
+	}
+}
+
+// jobQueuedMessages confirms a newly queued job.
+func jobQueuedMessages(village *models.Village, job models.VillageJob) []GameMessage {
+	idx := len(village.Jobs) - 1
+	return []GameMessage{
+		Msg(fmt.Sprintf("Queued %s. Your villagers will have it ready in ~%ds.",
+			job.Name, game.JobTicksLeft(village, idx)*config.Current().Tickers.VillageJobSeconds), "system"),
+	}
+}
+
+// villagerTaskInfo describes what a villager is doing.
+func villagerTaskInfo(v models.Villager) string {
+	switch {
+	case v.InjuredTicks > 0:
+		return fmt.Sprintf("Injured (%d ticks to recover)", v.InjuredTicks)
+	case v.Role == "guard":
+		return "Guarding the village"
+	case v.HarvestType != "":
+		return fmt.Sprintf("Harvesting %s (+%d/visit)", v.HarvestType, game.VillagerOutput(v))
+	case v.AssignedTask == game.TaskCrafting || v.AssignedTask == game.TaskBuilding:
+		return fmt.Sprintf("On %s jobs (+%d work/tick)", v.AssignedTask, game.VillagerWork(v, v.AssignedTask))
+	}
+	return "Idle"
+}
+
+// villagerDetails sums up a villager's trait, morale and progress.
+func villagerDetails(v models.Villager) string {
+	trait := "no trait"
+	if t, ok := game.FindVillagerTrait(v.Trait); ok {
+		trait = t.Name + ": " + t.Description
+	}
+	return fmt.Sprintf("Efficiency %d, %s (%+d morale), XP %d/%d, %s",
+		v.Efficiency, game.MoraleText(v.Morale), v.Morale, v.XP, game.VillagerXPToLevel(v.Level), trait)
+}
+
+// refundText lists refunded resources for display.
+func refundText(refund map[string]int) string {
+	if len(refund) == 0 {
+		return "nothing"
+	}
+	return game.MaterialsText(refund)
+}
+
+// VillageJobTickResult holds the results of a village job tick.
+type VillageJobTickResult struct {
+	JobsCompleted int
+}
+
+// ProcessVillageJobTicks advances every village's work queue by a tick.
+// Villages of online players are worked through their session so the
+// in-memory copies stay current; the rest are loaded from the store. Owners
+// are told when a job finishes.
+func (e *Engine) ProcessVillageJobTicks() *VillageJobTickResult {
+	completed := 0
+	worked := map[string]bool{}
+
+	e.mu.RLock()
+	sessions := make([]*GameSession, 0, len(e.sessions))
+	for _, sess := range e.sessions {
+		sessions = append(sessions, sess)
+	}
+	e.mu.RUnlock()
+
+	for _, sess := range sessions {
+		if sess.Player == nil || sess.Player.VillageName == "" || sess.GameState == nil || sess.GameState.Villages == nil {
+			continue
+		}
+		key := fmt.Sprintf("%d/%s", sess.AccountID, sess.Player.Name)
+		if worked[key] {
+			continue
+		}
+		village := sess.SelectedVillage
+		if village == nil {
+			v, ok := sess.GameState.Villages[sess.Player.VillageName]
+			if !ok {
+				continue
+			}
+			village = &v
+		}
+		if len(village.Jobs) == 0 {
+			continue
+		}
+		worked[key] = true
+		lines := game.WorkVillageJobs(sess.Player, village)
+		sess.GameState.Villages[sess.Player.VillageName] = *village
+		e.saveSession(sess)
+		if len(lines) > 0 {
+			completed += len(lines)
+			e.broadcastToAccount(sess.AccountID, villageNoticeResponse("village_job", lines, sess.Player, village))
+		}
+	}
+
+	if e.store != nil {
+		villages, err := e.store.LoadAllVillages()
+		if err != nil {
+			fmt.Printf("[VillageJobs] Failed to load villages: %v\n", err)
+			villages = nil
+		}
+		for _, vwo := range villages {
+			if len(vwo.Village.Jobs) == 0 || worked[fmt.Sprintf("%d/%s", vwo.AccountID, vwo.CharacterName)] {
+				continue
+			}
+			char, err := e.store.LoadCharacter(vwo.AccountID, vwo.CharacterName)
+			if err != nil {
+				fmt.Printf("[VillageJobs] Failed to load character %s: %v\n", vwo.CharacterName, err)
+				continue
+			}
+			lines := game.WorkVillageJobs(&char, &vwo.Village)
+			if err := e.store.SaveVillage(vwo.CharacterID, vwo.Village); err != nil {
+				fmt.Printf("[VillageJobs] Failed to save village for %s: %v\n", vwo.CharacterName, err)
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			completed += len(lines)
+			e.flushLedger(vwo.AccountID, &char)
+			if err := e.store.SaveCharacter(vwo.AccountID, char); err != nil {
+				fmt.Printf("[VillageJobs] Failed to save character %s: %v\n", vwo.CharacterName, err)
+			}
+			e.broadcastToAccount(vwo.AccountID, villageNoticeResponse("village_job", lines, &char, &vwo.Village))
+		}
+	}
+
+	if completed == 0 {
+		return nil
+	}
+	return &VillageJobTickResult{JobsCompleted: completed}
+}
+
+// villageNoticeResponse pushes village news, such as finished jobs or
+// villager events, to its owner. kind is the response type.
+func villageNoticeResponse(kind string, lines []string, player *models.Character, village *models.Village) GameResponse {
+	category := "system"
+	if kind == "village_job" {
+		category = "loot"
+	}
+	msgs := []GameMessage{}
+	for _, line := range lines {
+		msgs = append(msgs, Msg(line, category))
+	}
+	return GameResponse{
+		Type:     kind,
+		Messages: msgs,
+		State: &StateData{
+			Screen:  kind,
+			Player:  MakePlayerState(player),
+			Village: MakeVillageView(village),
+		},
+	}
+}
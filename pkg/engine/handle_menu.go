@@ -43,8 +43,10 @@ func (e *Engine) handleInit(session *GameSession) GameResponse {
 			if c.LockedLocations == nil {
 				c.LockedLocations = []string{}
 			}
+			game.CompactInventory(&c.Inventory)
 			gs.CharactersMap[c.Name] = c
 			session.Player = &c
+			migrateBuiltBuildings(session)
 			break
 		}
 		// Ensure leaderboard entry exists for this character.
@@ -99,8 +101,10 @@ func (e *Engine) handleCharacterSelect(session *GameSession, cmd GameCommand) Ga
 	if char.LockedLocations == nil {
 		char.LockedLocations = []string{}
 	}
+	game.CompactInventory(&char.Inventory)
 	gs.CharactersMap[char.Name] = char
 	session.Player = &char
+	migrateBuiltBuildings(session)
 
 	// Ensure leaderboard entry exists for this character.
 	e.saveSession(session)
@@ -113,7 +117,8 @@ func (e *Engine) handleCharacterSelect(session *GameSession, cmd GameCommand) Ga
 	return BuildMainMenuResponse(session)
 }
 
-// handleCharacterCreate creates a new character with the given name.
+// handleCharacterCreate takes the new character's name and moves on to class
+// selection.
 func (e *Engine) handleCharacterCreate(session *GameSession, cmd GameCommand) GameResponse {
 	gs := session.GameState
 	name := cmd.Value
@@ -127,20 +132,47 @@ func (e *Engine) handleCharacterCreate(session *GameSession, cmd GameCommand) Ga
 		}
 	}
 
-	player := game.GenerateCharacter(name, 1, 1)
+	session.PendingCharacterName = name
+	session.State = StateCharacterClassSelect
+	return buildClassSelectResponse(name)
+}
+
+// handleCharacterClassSelect creates the pending character with the chosen class.
+func (e *Engine) handleCharacterClassSelect(session *GameSession, cmd GameCommand) GameResponse {
+	gs := session.GameState
+	name := session.PendingCharacterName
+
+	if cmd.Value == "back" || name == "" {
+		session.PendingCharacterName = ""
+		session.State = StateCharacterCreate
+		return GameResponse{
+			Type:     "menu",
+			Messages: []GameMessage{Msg("Create a new character", "system")},
+			State:    &StateData{Screen: "character_create"},
+			Prompt:   "Enter character name: ",
+		}
+	}
+
+	player, err := game.GenerateClassCharacter(name, cmd.Value)
+	if err != nil {
+		resp := buildClassSelectResponse(name)
+		resp.Messages = append([]GameMessage{Msg("Choose one of the listed classes.", "error")}, resp.Messages...)
+		return resp
+	}
 	player.EquipmentMap = map[int]models.Item{}
 	player.Inventory = []models.Item{
 		game.CreateHealthPotion("small"),
 		game.CreateHealthPotion("small"),
 		game.CreateHealthPotion("small"),
 	}
+	game.CompactInventory(&player.Inventory)
 	player.ResourceStorageMap = map[string]models.Resource{}
-	player.BuiltBuildings = []models.Building{}
 	player.LockedLocations = []string{}
 	game.GenerateLocationsForNewCharacter(&player)
 
 	gs.CharactersMap[player.Name] = player
 	session.Player = &player
+	session.PendingCharacterName = ""
 
 	// Save after creation (uses DB for DB sessions, file for local sessions).
 	e.saveSession(session)
@@ -148,11 +180,30 @@ func (e *Engine) handleCharacterCreate(session *GameSession, cmd GameCommand) Ga
 	session.State = StateMainMenu
 	resp := BuildMainMenuResponse(session)
 	resp.Messages = append([]GameMessage{
-		Msg(fmt.Sprintf("Character '%s' created!", name), "system"),
+		Msg(fmt.Sprintf("Character '%s' the %s created!", name, game.Classes[player.Class].Name), "system"),
 	}, resp.Messages...)
 	return resp
 }
 
+// buildClassSelectResponse lists the selectable classes for a new character.
+func buildClassSelectResponse(name string) GameResponse {
+	msgs := []GameMessage{Msg(fmt.Sprintf("Choose a class for %s:", name), "system")}
+	options := []MenuOption{}
+	for _, id := range game.ClassOrder {
+		class := game.Classes[id]
+		msgs = append(msgs, Msg(fmt.Sprintf("%s - %s", class.Name, class.Description), "system"))
+		msgs = append(msgs, Msg(fmt.Sprintf("   Starts with: %s", strings.Join(class.StartSkills, ", ")), "system"))
+		options = append(options, Opt(id, class.Name))
+	}
+	options = append(options, Opt("back", "Back"))
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "character_class_select"},
+		Options:  options,
+	}
+}
+
 // handleMainMenu processes the main menu selection.
 func (e *Engine) handleMainMenu(session *GameSession, cmd GameCommand) GameResponse {
 	gs := session.GameState
@@ -203,6 +254,9 @@ func (e *Engine) handleMainMenu(session *GameSession, cmd GameCommand) GameRespo
 			}
 			options = append(options, Opt(locName, fmt.Sprintf("%s (%s, Lv1-%d)", locName, loc.Type, loc.LevelMax)))
 		}
+		if game.Contains(player.KnownLocations, game.RespecLocation) {
+			options = append(options, Opt("visit:"+game.RespecLocation, fmt.Sprintf("Visit the %s masters (respec attributes)", game.RespecLocation)))
+		}
 		// Show locked locations
 		for _, locName := range player.LockedLocations {
 			loc, exists := gs.GameLocations[locName]
@@ -371,6 +425,10 @@ func (e *Engine) handleMainMenu(session *GameSession, cmd GameCommand) GameRespo
 			gs.Villages[player.VillageName] = village
 		}
 		session.SelectedVillage = &village
+		// A character that had no village when it loaded founds its home here.
+		if game.MigrateBuiltBuildings(player, &village) {
+			e.saveVillage(session)
+		}
 		session.State = StateVillageMain
 		// Return a "pass-through" that the village handler will process
 		return e.handleVillageMain(session, GameCommand{Type: "init", Value: ""})
@@ -404,6 +462,39 @@ func (e *Engine) handleMainMenu(session *GameSession, cmd GameCommand) GameRespo
 		session.State = StateArenaMain
 		return e.handleArenaMain(session, GameCommand{Type: "init"})
 
+	case "15":
+		// Talents
+		if e.metrics != nil {
+			e.metrics.RecordFeatureUse("talents")
+		}
+		session.State = StateTalents
+		return e.handleTalents(session, GameCommand{Type: "init"})
+
+	case "16":
+		// Attributes
+		if e.metrics != nil {
+			e.metrics.RecordFeatureUse("attributes")
+		}
+		session.State = StateAttributes
+		session.AtTrainingHall = false
+		return e.handleAttributes(session, GameCommand{Type: "init"})
+
+	case "17":
+		// Inventory
+		if e.metrics != nil {
+			e.metrics.RecordFeatureUse("inventory")
+		}
+		session.State = StateInventory
+		return e.handleInventory(session, GameCommand{Type: "init"})
+
+	case "18":
+		// Guild
+		if e.metrics != nil {
+			e.metrics.RecordFeatureUse("guild")
+		}
+		session.State = StateGuild
+		return e.handleGuild(session, GameCommand{Type: "init"})
+
 	case "exit":
 		gs.CharactersMap[player.Name] = *player
 		game.WriteGameStateToFile(*gs, session.SaveFile)
@@ -425,6 +516,13 @@ func (e *Engine) handleHarvestSelect(session *GameSession, cmd GameCommand) Game
 	resourceType := cmd.Value
 
 	amount := game.HarvestResource(resourceType, &player.ResourceStorageMap)
+	if bonus := game.HarvestYield(player, amount) - amount; bonus > 0 {
+		res := player.ResourceStorageMap[resourceType]
+		res.Stock += bonus
+		player.ResourceStorageMap[resourceType] = res
+		amount += bonus
+	}
+	game.RecordResourceChange(player, resourceType, amount, game.ReasonHarvest, "")
 	if e.metrics != nil {
 		e.metrics.RecordHarvest(resourceType, amount)
 	}
@@ -437,9 +535,7 @@ func (e *Engine) handleHarvestSelect(session *GameSession, cmd GameCommand) Game
 			netAmount, taxAmount := game.CalculateTax(amount, town.TaxRate)
 			if taxAmount > 0 {
 				// Reduce player's harvest by tax
-				res := player.ResourceStorageMap[resourceType]
-				res.Stock -= taxAmount
-				player.ResourceStorageMap[resourceType] = res
+				game.AdjustResource(player, resourceType, -taxAmount, game.ReasonTax, "town:"+town.Name)
 
 				// Add to treasury
 				if town.Treasury == nil {
@@ -490,6 +586,22 @@ func (e *Engine) handleHuntLocationSelect(session *GameSession, cmd GameCommand)
 	player := session.Player
 	locName := cmd.Value
 
+	// Visiting the Training Hall opens the attribute screen with respecs on
+	// offer.
+	if name, ok := strings.CutPrefix(locName, "visit:"); ok {
+		if name != game.RespecLocation || !game.Contains(player.KnownLocations, name) {
+			session.State = StateMainMenu
+			resp := BuildMainMenuResponse(session)
+			resp.Messages = append([]GameMessage{
+				Msg(fmt.Sprintf("There is nothing to visit at %s.", name), "error"),
+			}, resp.Messages...)
+			return resp
+		}
+		session.State = StateAttributes
+		session.AtTrainingHall = true
+		return e.handleAttributes(session, GameCommand{Type: "init"})
+	}
+
 	// Check if this is a locked location (guardian fight)
 	if strings.HasPrefix(locName, "locked:") {
 		actualName := strings.TrimPrefix(locName, "locked:")
@@ -689,6 +801,7 @@ func (e *Engine) startCombat(session *GameSession, location *models.Location, mo
 				if !guard.Injured && guard.HitpointsRemaining > 0 {
 					g := guard
 					g.HitpointsRemaining = g.HitPoints
+					g.AttackBonus += game.GuardTalentBonus(player)
 					availableGuards = append(availableGuards, g)
 				}
 			}
@@ -708,7 +821,7 @@ func (e *Engine) startCombat(session *GameSession, location *models.Location, mo
 				for _, guard := range availableGuards {
 					msgs = append(msgs, Msg(fmt.Sprintf("  %s (Lv%d, HP:%d)", guard.Name, guard.Level, guard.HitPoints), "system"))
 				}
-				msgs = append(msgs, Msg("Bring guards to this fight?", "system"))
+				msgs = append(msgs, Msg("Bring guards to this fight, and with what orders?", "system"))
 
 				return GameResponse{
 					Type:     "menu",
@@ -718,10 +831,7 @@ func (e *Engine) startCombat(session *GameSession, location *models.Location, mo
 						Player: MakePlayerState(player),
 						Combat: MakeCombatView(session),
 					},
-					Options: []MenuOption{
-						Opt("y", "Yes, bring guards"),
-						Opt("n", "No, fight alone"),
-					},
+					Options: guardPromptOptions(),
 				}
 			}
 		}
@@ -987,6 +1097,7 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 
 	session.Combat.AutoPlayFights++
 	turnCount := 0
+	timers, mobTimers := &game.SkillTimers{}, &game.SkillTimers{}
 
 	msgs = append(msgs, Msg(fmt.Sprintf("Fight: %s (Lv%d) vs %s (Lv%d)",
 		player.Name, player.Level, mob.Name, mob.Level), "combat"))
@@ -1004,91 +1115,39 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 		}
 
 		// Process status effects for player
-		for i := len(player.StatusEffects) - 1; i >= 0; i-- {
-			effect := &player.StatusEffects[i]
-			switch effect.Type {
-			case "poison":
-				player.HitpointsRemaining -= effect.Potency
-			case "burn":
-				player.HitpointsRemaining -= effect.Potency
-			case "regen":
-				player.HitpointsRemaining += effect.Potency
-				if player.HitpointsRemaining > player.HitpointsTotal {
-					player.HitpointsRemaining = player.HitpointsTotal
-				}
-			}
-			effect.Duration--
-			if effect.Duration <= 0 {
-				switch effect.Type {
-				case "buff_attack":
-					player.StatsMod.AttackMod -= effect.Potency
-				case "buff_defense":
-					player.StatsMod.DefenseMod -= effect.Potency
-				}
-				player.StatusEffects = append(player.StatusEffects[:i], player.StatusEffects[i+1:]...)
-			}
-		}
+		game.TickStatusEffects(player)
 
 		// Process status effects for monster
-		for i := len(mob.StatusEffects) - 1; i >= 0; i-- {
-			effect := &mob.StatusEffects[i]
-			switch effect.Type {
-			case "poison":
-				mob.HitpointsRemaining -= effect.Potency
-			case "burn":
-				mob.HitpointsRemaining -= effect.Potency
-			case "regen":
-				mob.HitpointsRemaining += effect.Potency
-				if mob.HitpointsRemaining > mob.HitpointsTotal {
-					mob.HitpointsRemaining = mob.HitpointsTotal
-				}
-			}
-			effect.Duration--
-			if effect.Duration <= 0 {
-				switch effect.Type {
-				case "buff_attack":
-					mob.StatsMod.AttackMod -= effect.Potency
-				case "buff_defense":
-					mob.StatsMod.DefenseMod -= effect.Potency
-				}
-				mob.StatusEffects = append(mob.StatusEffects[:i], mob.StatusEffects[i+1:]...)
-			}
-		}
+		game.TickStatusEffects(mob)
 
 		if player.HitpointsRemaining <= 0 || mob.HitpointsRemaining <= 0 {
 			break
 		}
 
 		// Check if player is stunned
-		playerStunned := false
-		for _, eff := range player.StatusEffects {
-			if eff.Type == "stun" {
-				playerStunned = true
-				break
-			}
-		}
+		playerStunned := game.IsStunned(player)
 
 		if !playerStunned {
 			// AI makes decision
-			decision := game.MakeAIDecision(player, mob, turnCount)
+			decision := game.MakeAIDecision(player, mob, turnCount, timers)
 
 			switch decision {
 			case "attack":
-				playerAttack := game.MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-				if rand.Intn(100) < 15 {
+				playerAttack := game.MultiRoll(player.AttackRolls) + game.AttackMod(player)
+				if game.RollPlayerCrit(player) {
 					playerAttack = playerAttack * 2
 				}
-				mobDef := game.MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+				mobDef := game.MultiRoll(mob.DefenseRolls) + game.DefenseMod(mob)
 				if playerAttack > mobDef {
 					diff := game.ApplyDamage(playerAttack-mobDef, models.Physical, mob)
 					mob.HitpointsRemaining -= diff
+					game.ApplyOnHitEffects(player, mob, diff)
 				}
 
 			case "item":
 				for idx, item := range player.Inventory {
 					if item.ItemType == "consumable" {
-						game.UseConsumableItem(item, player)
-						game.RemoveItemFromInventory(&player.Inventory, idx)
+						game.UseInventoryItem(player, idx, game.ReasonItemUsed, "combat")
 						break
 					}
 				}
@@ -1115,29 +1174,21 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 						if nameMatch && skill.ManaCost <= player.ManaRemaining && skill.StaminaCost <= player.StaminaRemaining {
 							player.ManaRemaining -= skill.ManaCost
 							player.StaminaRemaining -= skill.StaminaCost
+							cast := timers.Cast(skill, game.SkillPower(player, skill), mob, turnCount)
 
 							if skill.Damage < 0 {
-								player.HitpointsRemaining += -skill.Damage
+								player.HitpointsRemaining += -cast.Power
 								if player.HitpointsRemaining > player.HitpointsTotal {
 									player.HitpointsRemaining = player.HitpointsTotal
 								}
 							} else if skill.Damage > 0 {
-								finalDamage := game.ApplyDamage(skill.Damage, skill.DamageType, mob)
+								hit := game.ApplyElements(cast.Power, skill.DamageType, mob, locationName)
+								finalDamage := game.ApplyDamage(hit.Damage, skill.DamageType, mob)
 								mob.HitpointsRemaining -= finalDamage
 							}
 
-							if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-								if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" || skill.Effect.Type == "regen" {
-									player.StatusEffects = append(player.StatusEffects, skill.Effect)
-									if skill.Effect.Type == "buff_attack" {
-										player.StatsMod.AttackMod += skill.Effect.Potency
-									} else if skill.Effect.Type == "buff_defense" {
-										player.StatsMod.DefenseMod += skill.Effect.Potency
-									}
-								} else {
-									mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-								}
-							}
+							game.ApplySkillEffect(player, mob, skill.Effect)
+							game.TrainSkill(player, skill.Name, game.CastXP(cast))
 							break
 						}
 					}
@@ -1147,57 +1198,40 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 
 		// Monster's turn
 		if mob.HitpointsRemaining > 0 {
-			mobStunned := false
-			for _, eff := range mob.StatusEffects {
-				if eff.Type == "stun" {
-					mobStunned = true
-					break
-				}
-			}
+			mobStunned := game.IsStunnedMob(mob)
 
 			if !mobStunned {
 				useMonsterSkill := false
-				if len(mob.LearnedSkills) > 0 && rand.Intn(100) < 40 {
-					skill := mob.LearnedSkills[rand.Intn(len(mob.LearnedSkills))]
-					if skill.ManaCost <= mob.ManaRemaining && skill.StaminaCost <= mob.StaminaRemaining {
-						mob.ManaRemaining -= skill.ManaCost
-						mob.StaminaRemaining -= skill.StaminaCost
-						useMonsterSkill = true
-
-						if skill.Damage < 0 {
-							mob.HitpointsRemaining += -skill.Damage
-							if mob.HitpointsRemaining > mob.HitpointsTotal {
-								mob.HitpointsRemaining = mob.HitpointsTotal
-							}
-						} else if skill.Damage > 0 {
-							finalDamage := game.ApplyDamage(skill.Damage, skill.DamageType, player)
-							player.HitpointsRemaining -= finalDamage
-						}
-
-						if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-							if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" {
-								mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-								if skill.Effect.Type == "buff_attack" {
-									mob.StatsMod.AttackMod += skill.Effect.Potency
-								} else if skill.Effect.Type == "buff_defense" {
-									mob.StatsMod.DefenseMod += skill.Effect.Potency
-								}
-							} else {
-								player.StatusEffects = append(player.StatusEffects, skill.Effect)
-							}
+				if skill, ok := game.ChooseMonsterSkill(mob, player, mobTimers, turnCount); ok {
+					mob.ManaRemaining -= skill.ManaCost
+					mob.StaminaRemaining -= skill.StaminaCost
+					useMonsterSkill = true
+					cast := mobTimers.Cast(skill, skill.Damage, player, turnCount)
+
+					if skill.Damage < 0 {
+						mob.HitpointsRemaining += -cast.Power
+						if mob.HitpointsRemaining > mob.HitpointsTotal {
+							mob.HitpointsRemaining = mob.HitpointsTotal
 						}
+					} else if skill.Damage > 0 {
+						hit := game.ApplyElements(cast.Power, skill.DamageType, player, locationName)
+						finalDamage := game.ApplyDamage(hit.Damage, skill.DamageType, player)
+						player.HitpointsRemaining -= finalDamage
 					}
+
+					game.ApplySkillEffect(mob, player, skill.Effect)
 				}
 
 				if !useMonsterSkill {
-					mobAttack := game.MultiRoll(mob.AttackRolls) + mob.StatsMod.AttackMod
-					if rand.Intn(100) < 10 {
+					mobAttack := game.MultiRoll(mob.AttackRolls) + game.AttackMod(mob)
+					if game.RollMonsterCrit() {
 						mobAttack = mobAttack * 2
 					}
-					playerDef := game.MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+					playerDef := game.MultiRoll(player.DefenseRolls) + game.DefenseMod(player)
 					if mobAttack > playerDef {
 						diff := game.ApplyDamage(mobAttack-playerDef, models.Physical, player)
 						player.HitpointsRemaining -= diff
+						game.ApplyThorns(player, mob, diff)
 					}
 				}
 			}
@@ -1225,9 +1259,18 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 					break
 				}
 			}
-			if existingIdx >= 0 {
-				game.UpgradeSkill(&player.LearnedSkills[existingIdx])
-				msgs = append(msgs, Msg(fmt.Sprintf("  SKILL GUARDIAN DEFEATED! %s upgraded! (+5 dmg, -2 cost) [+%d]", mob.GuardedSkill.Name, player.LearnedSkills[existingIdx].UpgradeCount), "loot"))
+			if !game.CanLearnSkill(player, mob.GuardedSkill.Name) {
+				scroll := game.CreateSkillScroll(mob.GuardedSkill)
+				if game.LootItem(player, scroll, game.ReasonSkillScroll, "guardian:"+mob.GuardedSkill.Name) {
+					msgs = append(msgs, Msg(fmt.Sprintf("  SKILL GUARDIAN DEFEATED! Took a %s (not a %s skill)", scroll.Name, game.Classes[player.Class].Name), "loot"))
+				} else {
+					msgs = append(msgs, Msg(fmt.Sprintf("  SKILL GUARDIAN DEFEATED! Inventory full, left the %s behind", scroll.Name), "error"))
+				}
+			} else if existingIdx >= 0 {
+				msgs = append(msgs, Msg(fmt.Sprintf("  SKILL GUARDIAN DEFEATED! %s gains %d skill XP", mob.GuardedSkill.Name, data.SkillStudyXP), "loot"))
+				for _, line := range game.GainSkillXP(&player.LearnedSkills[existingIdx], data.SkillStudyXP) {
+					msgs = append(msgs, Msg("  "+line, "loot"))
+				}
 				if e.metrics != nil {
 					e.metrics.RecordSkillUpgraded(mob.GuardedSkill.Name)
 				}
@@ -1242,7 +1285,7 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 
 		// Loot equipment
 		for _, item := range mob.EquipmentMap {
-			game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+			game.LootItem(player, item, game.ReasonMonsterDrop, "monster:"+mob.Name)
 		}
 
 		// Drop beast materials
@@ -1250,14 +1293,17 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 		if matName != "" {
 			msgs = append(msgs, Msg(fmt.Sprintf("  Dropped: %d %s", matQty, matName), "loot"))
 		}
+		if r, ok := game.RollRecipeDrop(player); ok {
+			msgs = append(msgs, recipeDiscoveryMessage(r))
+		}
 
 		// Chance for potion
-		if rand.Intn(100) < 30 {
+		if rand.Intn(100) < game.LootChance(player, 30) {
 			potion := game.CreateHealthPotion("small")
 			if rand.Intn(100) < 30 {
 				potion = game.CreateHealthPotion("medium")
 			}
-			player.Inventory = append(player.Inventory, potion)
+			game.LootItem(player, potion, game.ReasonMonsterDrop, "monster:"+mob.Name)
 		}
 
 		// 15% chance to rescue a villager (only after elder quest completed) or get a hint
@@ -1326,8 +1372,7 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 			}
 		}
 
-		player.StatsMod = game.CalculateItemMods(player.EquipmentMap)
-		player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
+		game.RecalculatePlayerStats(player)
 
 		// Respawn monster at location
 		loc := gs.GameLocations[locationName]
@@ -1346,6 +1391,7 @@ func (e *Engine) autoPlayOneFight(session *GameSession, player *models.Character
 		loc.Monsters[mobLoc].Experience += player.Level * 100
 		gs.GameLocations[locationName] = loc
 	}
+	msgs = append(msgs, wearPlayerGear(player, player.HitpointsRemaining <= 0)...)
 
 	// Level up
 	game.LevelUp(player)
@@ -1639,9 +1685,11 @@ func (e *Engine) handleLoadSaveCharSelect(session *GameSession, cmd GameCommand)
 	if char.LockedLocations == nil {
 		char.LockedLocations = []string{}
 	}
+	game.CompactInventory(&char.Inventory)
 
 	gs.CharactersMap[char.Name] = char
 	session.Player = &char
+	migrateBuiltBuildings(session)
 
 	// Ensure leaderboard entry exists for this character.
 	e.saveSession(session)
@@ -1653,106 +1701,13 @@ func (e *Engine) handleLoadSaveCharSelect(session *GameSession, cmd GameCommand)
 	return resp
 }
 
-// handleBuildSelect processes the building construction.
-func (e *Engine) handleBuildSelect(session *GameSession, cmd GameCommand) GameResponse {
-	player := session.Player
-	gs := session.GameState
-	buildingName := cmd.Value
-
-	// Find the building in available buildings
-	var targetBuilding *models.Building
-	for _, b := range data.AvailableBuildings {
-		if b.Name == buildingName {
-			bCopy := b
-			targetBuilding = &bCopy
-			break
-		}
-	}
-
-	if targetBuilding == nil {
-		session.State = StateMainMenu
-		resp := BuildMainMenuResponse(session)
-		resp.Messages = append([]GameMessage{
-			Msg(fmt.Sprintf("Building '%s' not found!", buildingName), "error"),
-		}, resp.Messages...)
-		return resp
-	}
-
-	// Check if already built
-	for _, b := range player.BuiltBuildings {
-		if b.Name == buildingName {
-			session.State = StateMainMenu
-			resp := BuildMainMenuResponse(session)
-			resp.Messages = append([]GameMessage{
-				Msg(fmt.Sprintf("'%s' is already built!", buildingName), "error"),
-			}, resp.Messages...)
-			return resp
-		}
-	}
-
-	// Check resources
-	canBuild := true
-	missingMsgs := []GameMessage{}
-	for resName, required := range targetBuilding.RequiredResourceMap {
-		res, exists := player.ResourceStorageMap[resName]
-		if !exists || res.Stock < required {
-			canBuild = false
-			have := 0
-			if exists {
-				have = res.Stock
-			}
-			missingMsgs = append(missingMsgs, Msg(fmt.Sprintf("Need %d %s (have %d)", required, resName, have), "error"))
-		}
-	}
-
-	if !canBuild {
-		session.State = StateMainMenu
-		msgs := []GameMessage{Msg(fmt.Sprintf("Not enough resources to build %s!", buildingName), "error")}
-		msgs = append(msgs, missingMsgs...)
-		resp := BuildMainMenuResponse(session)
-		resp.Messages = append(msgs, resp.Messages...)
-		return resp
-	}
-
-	// Deduct resources
-	for resName, required := range targetBuilding.RequiredResourceMap {
-		res := player.ResourceStorageMap[resName]
-		res.Stock -= required
-		player.ResourceStorageMap[resName] = res
-	}
-
-	// Add to built buildings
-	if player.BuiltBuildings == nil {
-		player.BuiltBuildings = []models.Building{}
-	}
-	player.BuiltBuildings = append(player.BuiltBuildings, *targetBuilding)
-
-	// Recalculate player stats with building bonuses
-	player.StatsMod = game.CalculateItemMods(player.EquipmentMap)
-	for _, b := range player.BuiltBuildings {
-		player.StatsMod.AttackMod += b.StatsMod.AttackMod
-		player.StatsMod.DefenseMod += b.StatsMod.DefenseMod
-		player.StatsMod.HitPointMod += b.StatsMod.HitPointMod
-	}
-	player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
-
-	gs.CharactersMap[player.Name] = *player
-	game.WriteGameStateToFile(*gs, session.SaveFile)
-
-	session.State = StateMainMenu
-	resp := BuildMainMenuResponse(session)
-	resp.Messages = append([]GameMessage{
-		Msg(fmt.Sprintf("Built %s!", buildingName), "system"),
-	}, resp.Messages...)
-	return resp
-}
-
 // --- Helper functions for building display messages ---
 
 func buildPlayerStatsMessages(player *models.Character, gs *models.GameState) []GameMessage {
 	msgs := []GameMessage{
 		Msg("============ Player Stats ============", "system"),
 		Msg(fmt.Sprintf("Name: %s", player.Name), "system"),
+		Msg(fmt.Sprintf("Class: %s", className(player)), "system"),
 		Msg(fmt.Sprintf("Level: %d", player.Level), "system"),
 		Msg(fmt.Sprintf("Experience: %d / %d", player.Experience, player.Level*100), "system"),
 		Msg(fmt.Sprintf("HP: %d/%d (Natural: %d)", player.HitpointsRemaining, player.HitpointsTotal, player.HitpointsNatural), "system"),
@@ -1775,8 +1730,18 @@ func buildPlayerStatsMessages(player *models.Character, gs *models.GameState) []
 			if slotName == "" {
 				slotName = fmt.Sprintf("Slot %d", slot)
 			}
-			msgs = append(msgs, Msg(fmt.Sprintf("  [%s] %s (Rarity %d, CP:%d)",
-				slotName, item.Name, item.Rarity, item.CP), "system"))
+			durability := ""
+			if label := game.DurabilityLabel(item); label != "" {
+				durability = ", " + label
+			}
+			msgs = append(msgs, Msg(fmt.Sprintf("  [%s] %s (Rarity %d, CP:%d%s)",
+				slotName, item.Name, item.Rarity, item.CP, durability), "system"))
+			for _, line := range game.AffixDescriptions(item) {
+				msgs = append(msgs, Msg("      "+line, "system"))
+			}
+		}
+		for _, line := range game.ActiveSetBonuses(player) {
+			msgs = append(msgs, Msg("  Set bonus: "+line, "system"))
 		}
 	}
 
@@ -1825,15 +1790,6 @@ func buildPlayerStatsMessages(player *models.Character, gs *models.GameState) []
 		}
 	}
 
-	// Built Buildings
-	if len(player.BuiltBuildings) > 0 {
-		msgs = append(msgs, Msg("", "system"))
-		msgs = append(msgs, Msg("--- Built Buildings ---", "system"))
-		for _, b := range player.BuiltBuildings {
-			msgs = append(msgs, Msg(fmt.Sprintf("  %s", b.Name), "system"))
-		}
-	}
-
 	msgs = append(msgs, Msg("======================================", "system"))
 
 	return msgs
@@ -1968,7 +1924,7 @@ func buildInventoryMessages(player *models.Character) []GameMessage {
 			msgs = append(msgs, Msg("CONSUMABLES:", "system"))
 			potionCount := make(map[string]int)
 			for _, item := range consumables {
-				potionCount[item.Name]++
+				potionCount[item.Name] += game.StackSize(item)
 			}
 			for name, count := range potionCount {
 				msgs = append(msgs, Msg(fmt.Sprintf("  %s x%d", name, count), "system"))
@@ -2020,6 +1976,11 @@ func buildSkillsMessages(player *models.Character) []GameMessage {
 				skillLabel += fmt.Sprintf(" +%d", skill.UpgradeCount)
 			}
 			msgs = append(msgs, Msg(fmt.Sprintf("%d. %s", i+1, skillLabel), "system"))
+			if next := game.NextRankXP(skill); next > 0 {
+				msgs = append(msgs, Msg(fmt.Sprintf("   Rank %d (%d/%d skill XP)", game.SkillRank(skill), skill.RankXP, next), "system"))
+			} else {
+				msgs = append(msgs, Msg(fmt.Sprintf("   Rank %d (max)", game.SkillRank(skill)), "system"))
+			}
 
 			if skill.ManaCost > 0 {
 				msgs = append(msgs, Msg(fmt.Sprintf("   Cost: %d MP", skill.ManaCost), "system"))
@@ -2036,6 +1997,12 @@ func buildSkillsMessages(player *models.Character) []GameMessage {
 				msgs = append(msgs, Msg(fmt.Sprintf("   Effect: %s (%d turns, potency %d)",
 					skill.Effect.Type, skill.Effect.Duration, skill.Effect.Potency), "system"))
 			}
+			if cooldown := game.SkillCooldown(skill); cooldown > 0 {
+				msgs = append(msgs, Msg(fmt.Sprintf("   Cooldown: %d turns", cooldown), "system"))
+			}
+			if charge := game.SkillChargeTurns(skill); charge > 0 {
+				msgs = append(msgs, Msg(fmt.Sprintf("   Charges for %d turn(s) before it goes off", charge), "system"))
+			}
 			msgs = append(msgs, Msg(fmt.Sprintf("   %s", skill.Description), "system"))
 		}
 	}
@@ -0,0 +1,299 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/db"
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// raidLogShown is how many raid log entries the raids screen lists.
+const raidLogShown = 10
+
+// handleVillageRaids shows the village's shield and raid log, scouts other
+// players' villages and launches raids and revenge raids on them.
+func (e *Engine) handleVillageRaids(session *GameSession, cmd GameCommand) GameResponse {
+	village := session.SelectedVillage
+	msgs := []GameMessage{}
+
+	action, arg, _ := strings.Cut(cmd.Value, ":")
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.State = StateVillageMain
+		return e.handleVillageMain(session, GameCommand{Type: "init"})
+
+	case e.store == nil:
+		msgs = append(msgs, Msg("Raids require a database connection.", "error"))
+
+	case action == "scout":
+		return e.buildRaidScoutResponse(session)
+
+	case action == "raid":
+		id, _ := strconv.ParseInt(arg, 10, 64)
+		vwo, err := e.findRaidTarget(func(v db.VillageWithOwner) bool { return v.CharacterID == id })
+		if err == nil && vwo.AccountID == session.AccountID {
+			err = fmt.Errorf("you can't raid your own villages")
+		}
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		msgs = append(msgs, e.raidMessages(session, vwo, -1)...)
+
+	case action == "revenge":
+		i, _ := strconv.Atoi(arg)
+		if i < 0 || i >= len(village.RaidLog) || !game.CanRevenge(village.RaidLog[i]) {
+			msgs = append(msgs, Msg(game.ErrNoRevenge.Error(), "error"))
+			break
+		}
+		entry := village.RaidLog[i]
+		vwo, err := e.findRaidTarget(func(v db.VillageWithOwner) bool {
+			return v.AccountID == entry.AttackerAccount && v.CharacterName == entry.Attacker && v.Village.Name == entry.AttackerVillage
+		})
+		if err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+			break
+		}
+		msgs = append(msgs, e.raidMessages(session, vwo, i)...)
+	}
+
+	session.State = StateVillageRaids
+	resp := buildVillageRaidsResponse(session)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// findRaidTarget is the first stored village match accepts.
+func (e *Engine) findRaidTarget(match func(db.VillageWithOwner) bool) (db.VillageWithOwner, error) {
+	villages, err := e.store.LoadAllVillages()
+	if err != nil {
+		return db.VillageWithOwner{}, fmt.Errorf("failed to load villages")
+	}
+	for _, vwo := range villages {
+		if match(vwo) {
+			return vwo, nil
+		}
+	}
+	return db.VillageWithOwner{}, fmt.Errorf("that village can no longer be found")
+}
+
+// raidMessages raids vwo from the session's village and describes the raid;
+// avenge is the index of the raid log entry it answers, or -1. An online
+// defender is raided through their session so it stays current and is told
+// at once; an offline one is loaded from the store and saved back.
+func (e *Engine) raidMessages(session *GameSession, vwo db.VillageWithOwner, avenge int) []GameMessage {
+	attacker := game.RaidParty{AccountID: session.AccountID, Character: session.Player, Village: session.SelectedVillage}
+	now := time.Now().Unix()
+	raid := func(defender game.RaidParty) (game.RaidOutcome, error) {
+		if avenge >= 0 {
+			return game.Revenge(attacker, defender, avenge, now)
+		}
+		return game.Raid(attacker, defender, false, now)
+	}
+
+	var outcome game.RaidOutcome
+	var err error
+	if sess := e.characterSession(vwo.AccountID, vwo.CharacterName); sess != nil {
+		village := sess.SelectedVillage
+		if village == nil || village.Name != vwo.Village.Name {
+			v, ok := sess.GameState.Villages[vwo.Village.Name]
+			if !ok {
+				v = vwo.Village
+			}
+			village = &v
+		}
+		outcome, err = raid(game.RaidParty{AccountID: vwo.AccountID, Character: sess.Player, Village: village})
+		if err == nil {
+			sess.GameState.Villages[village.Name] = *village
+			e.saveSession(sess)
+			e.broadcastToAccount(vwo.AccountID, villageNoticeResponse("village_event", raidNotice(village), sess.Player, village))
+		}
+	} else {
+		var char models.Character
+		char, err = e.store.LoadCharacter(vwo.AccountID, vwo.CharacterName)
+		if err == nil {
+			outcome, err = raid(game.RaidParty{AccountID: vwo.AccountID, Character: &char, Village: &vwo.Village})
+		}
+		if err == nil {
+			if saveErr := e.store.SaveVillage(vwo.CharacterID, vwo.Village); saveErr != nil {
+				fmt.Printf("[Raid] Failed to save village for %s: %v\n", vwo.CharacterName, saveErr)
+			}
+			e.flushLedger(vwo.AccountID, &char)
+			if saveErr := e.store.SaveCharacter(vwo.AccountID, char); saveErr != nil {
+				fmt.Printf("[Raid] Failed to save character %s: %v\n", vwo.CharacterName, saveErr)
+			}
+		}
+	}
+	if err != nil {
+		return []GameMessage{Msg(err.Error(), "error")}
+	}
+
+	e.saveVillage(session)
+	e.saveSession(session)
+
+	msgs := []GameMessage{Msg(fmt.Sprintf("-- Your guards march on %s --", vwo.Village.Name), "system")}
+	for _, ev := range outcome.Wave.Events {
+		msgs = append(msgs, Msg(game.SiegeEventLine(ev), siegeEventCategory(ev)))
+	}
+	category := "damage"
+	if len(outcome.Entry.Loot) > 0 {
+		category = "loot"
+	}
+	return append(msgs, Msg(game.RaidLogLine(outcome.Entry), category))
+}
+
+// characterSession is the session playing a character, or nil when it's
+// offline.
+func (e *Engine) characterSession(accountID int64, name string) *GameSession {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, sess := range e.sessions {
+		if sess.AccountID == accountID && sess.Player != nil && sess.Player.Name == name && sess.GameState != nil {
+			return sess
+		}
+	}
+	return nil
+}
+
+// raidNotice tells a raided village's owner about the raid.
+func raidNotice(village *models.Village) []string {
+	entry := village.RaidLog[0]
+	return []string{
+		fmt.Sprintf("%s's guards raided %s!", entry.Attacker, village.Name),
+		game.RaidLogLine(entry),
+		fmt.Sprintf("%s is shielded from raids for %s.", village.Name, shieldText(village.ShieldUntil-entry.Time)),
+	}
+}
+
+func shieldText(seconds int64) string {
+	return fmt.Sprintf("%dh %dm", seconds/3600, (seconds%3600)/60)
+}
+
+// buildVillageRaidsResponse shows the village's shield, raiders and raid log.
+func buildVillageRaidsResponse(session *GameSession) GameResponse {
+	village := session.SelectedVillage
+	now := time.Now().Unix()
+
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg("VILLAGE RAIDS", "system"),
+		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("Send your hired guards against villages within %d levels of yours. Every raider that breaks in", data.RaidLevelBand), "system"),
+		Msg("carries off a share of the owner's stored resources. A raided village is shielded for a while;", "system"),
+		Msg("raiding drops your own shield. Answer a raid on you with revenge, whatever its level or shield.", "system"),
+		Msg("", "system"),
+	}
+	if game.RaidShielded(village, now) {
+		msgs = append(msgs, Msg("Shield: "+shieldText(village.ShieldUntil-now)+" left", "buff"))
+	} else {
+		msgs = append(msgs, Msg("Shield: none", "system"))
+	}
+	msgs = append(msgs, Msg(fmt.Sprintf("Raiders ready: %d of %d hired guards", len(game.Raiders(village)), len(village.ActiveGuards)), "system"))
+
+	options := []MenuOption{}
+	if len(game.Raiders(village)) > 0 {
+		options = append(options, Opt("scout", "Scout for Villages"))
+	} else {
+		options = append(options, OptDisabled("scout", "Scout for Villages (no hired guards fit to raid)"))
+	}
+
+	msgs = append(msgs, Msg("", "system"), Msg("RAID LOG:", "system"))
+	if len(village.RaidLog) == 0 {
+		msgs = append(msgs, Msg("  No raids yet.", "system"))
+	}
+	for i, entry := range village.RaidLog {
+		if i == raidLogShown {
+			break
+		}
+		category := "combat"
+		if !entry.Outgoing && len(entry.Loot) > 0 {
+			category = "damage"
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("  %s ago - %s", shieldText(now-entry.Time), game.RaidLogLine(entry)), category))
+		if game.CanRevenge(entry) {
+			options = append(options, Opt(fmt.Sprintf("revenge:%d", i), fmt.Sprintf("Revenge on %s (%s)", entry.Attacker, entry.AttackerVillage)))
+		}
+	}
+	msgs = append(msgs, Msg("============================================================", "system"))
+
+	options = append(options, Opt("back", "Back to Village"))
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State: &StateData{Screen: "village_raids", Player: MakePlayerState(session.Player),
+			Village: MakeVillageView(village)},
+		Options: options,
+	}
+}
+
+// buildRaidScoutResponse lists the unshielded villages of other players in
+// the raid level band, closest in level first.
+func (e *Engine) buildRaidScoutResponse(session *GameSession) GameResponse {
+	village := session.SelectedVillage
+	now := time.Now().Unix()
+
+	villages, err := e.store.LoadAllVillages()
+	if err != nil {
+		resp := buildVillageRaidsResponse(session)
+		resp.Messages = append([]GameMessage{Msg("Failed to load villages.", "error")}, resp.Messages...)
+		return resp
+	}
+	targets := []db.VillageWithOwner{}
+	for _, vwo := range villages {
+		if vwo.AccountID != session.AccountID && game.InRaidBand(village, &vwo.Village) && !game.RaidShielded(&vwo.Village, now) {
+			targets = append(targets, vwo)
+		}
+	}
+	gap := func(v *models.Village) int { return max(v.Level-village.Level, village.Level-v.Level) }
+	sort.Slice(targets, func(i, j int) bool {
+		if gi, gj := gap(&targets[i].Village), gap(&targets[j].Village); gi != gj {
+			return gi < gj
+		}
+		return targets[i].Village.Name < targets[j].Village.Name
+	})
+	if len(targets) > data.RaidTargets {
+		targets = targets[:data.RaidTargets]
+	}
+
+	msgs := []GameMessage{
+		Msg("============================================================", "system"),
+		Msg("SCOUTED VILLAGES", "system"),
+		Msg("============================================================", "system"),
+	}
+	if len(targets) == 0 {
+		msgs = append(msgs, Msg("Your scouts found no unshielded villages near your level.", "system"))
+	}
+	options := []MenuOption{}
+	for _, vwo := range targets {
+		v := &vwo.Village
+		walls, towers := 0, 0
+		for _, d := range v.Defenses {
+			if !d.Built {
+				continue
+			}
+			if d.Type == "wall" {
+				walls++
+			} else if d.AttackPower > 0 {
+				towers++
+			}
+		}
+		msgs = append(msgs, Msg(fmt.Sprintf("%s (Lv%d, %s) - %d walls, %d towers, %d traps, %d guards",
+			v.Name, v.Level, vwo.CharacterName, walls, towers, len(v.Traps), len(game.Raiders(v))), "system"))
+		options = append(options, Opt(fmt.Sprintf("raid:%d", vwo.CharacterID), fmt.Sprintf("Raid %s (Lv%d)", v.Name, v.Level)))
+	}
+	options = append(options, Opt("list", "Back to Raids"))
+
+	session.State = StateVillageRaids
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "village_raids", Player: MakePlayerState(session.Player)},
+		Options:  options,
+	}
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
+
+// handleTalents shows the character's talent tree and spends talent points.
+// Classless characters from before classes existed pick a class here first.
+func (e *Engine) handleTalents(session *GameSession, cmd GameCommand) GameResponse {
+	player := session.Player
+	msgs := []GameMessage{}
+
+	switch {
+	case cmd.Value == "back" || cmd.Value == "0":
+		session.State = StateMainMenu
+		return BuildMainMenuResponse(session)
+
+	case strings.HasPrefix(cmd.Value, "class:"):
+		if err := game.AdoptClass(player, strings.TrimPrefix(cmd.Value, "class:")); err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		} else {
+			msgs = append(msgs, Msg(fmt.Sprintf("%s is now a %s!", player.Name, className(player)), "levelup"))
+			e.saveSession(session)
+		}
+
+	case strings.HasPrefix(cmd.Value, "learn:"):
+		id := strings.TrimPrefix(cmd.Value, "learn:")
+		if err := game.LearnTalent(player, id); err != nil {
+			msgs = append(msgs, Msg(err.Error(), "error"))
+		} else {
+			talent, _ := game.FindTalent(player, id)
+			msgs = append(msgs, Msg(fmt.Sprintf("%s is now rank %d/%d.", talent.Name, player.Talents[id], talent.MaxRank), "levelup"))
+			e.saveSession(session)
+		}
+	}
+
+	session.State = StateTalents
+	resp := buildTalentsResponse(player)
+	resp.Messages = append(msgs, resp.Messages...)
+	return resp
+}
+
+// buildTalentsResponse renders the talent tree, or the class choice for a
+// classless character.
+func buildTalentsResponse(player *models.Character) GameResponse {
+	msgs := []GameMessage{Msg("============ Talents ============", "system")}
+	options := []MenuOption{}
+
+	class, ok := game.LookupClass(player)
+	if !ok {
+		msgs = append(msgs, Msg("You have not chosen a class yet. Once you have earned experience your stats and", "system"))
+		msgs = append(msgs, Msg("skills are kept; the class shapes future level-ups, learnable skills and talents.", "system"))
+		for _, id := range game.ClassOrder {
+			c := game.Classes[id]
+			msgs = append(msgs, Msg(fmt.Sprintf("%s - %s", c.Name, c.Description), "system"))
+			options = append(options, Opt("class:"+id, "Become a "+c.Name))
+		}
+	} else {
+		points := game.TalentPoints(player)
+		msgs = append(msgs, Msg(fmt.Sprintf("%s - talent points available: %d", class.Name, points), "system"))
+		for _, t := range class.Talents {
+			rank := player.Talents[t.ID]
+			line := fmt.Sprintf("%s [%d/%d] - %s", t.Name, rank, t.MaxRank, t.Description)
+			if t.RequiredLevel > player.Level {
+				line += fmt.Sprintf(" (requires level %d)", t.RequiredLevel)
+			}
+			msgs = append(msgs, Msg(line, "system"))
+
+			label := fmt.Sprintf("Learn %s (%d/%d)", t.Name, rank, t.MaxRank)
+			if points > 0 && rank < t.MaxRank && player.Level >= t.RequiredLevel {
+				options = append(options, Opt("learn:"+t.ID, label))
+			} else {
+				options = append(options, OptDisabled("learn:"+t.ID, label))
+			}
+		}
+	}
+	options = append(options, Opt("back", "Return to Main Menu"))
+
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "talents", Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
+// talentsMenuLabel is the main menu entry for the talents screen, flagging
+// unspent points.
+func talentsMenuLabel(player *models.Character) string {
+	if player.Class == "" {
+		return "Talents (choose a class)"
+	}
+	if points := game.TalentPoints(player); points > 0 {
+		return fmt.Sprintf("Talents (%d unspent)", points)
+	}
+	return "Talents"
+}
+
+// className returns the display name of the character's class.
+func className(player *models.Character) string {
+	if class, ok := game.LookupClass(player); ok {
+		return class.Name
+	}
+	return "None"
+}
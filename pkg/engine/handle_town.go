@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/data"
 	"rpg-game/pkg/game"
 	"rpg-game/pkg/models"
@@ -91,6 +92,9 @@ func (e *Engine) handleTownMain(session *GameSession, cmd GameCommand) GameRespo
 	case "6": // NPC Quest Board
 		session.State = StateTownNPCQuestBoard
 		return e.handleTownNPCQuestBoard(session, GameCommand{Type: "init"})
+	case "7": // Blacksmith
+		session.State = StateBlacksmith
+		return e.handleBlacksmith(session, GameCommand{Type: "init"})
 	case "0", "back":
 		session.SelectedTown = nil
 		session.State = StateMainMenu
@@ -129,6 +133,7 @@ func (e *Engine) handleTownMain(session *GameSession, cmd GameCommand) GameRespo
 		Opt("4", "Challenge Mayor"),
 		Opt("5", "Talk to Townsfolk"),
 		Opt("6", "NPC Quest Board"),
+		Opt("7", "Blacksmith (Repairs)"),
 		Opt("0", "Return to Main Menu"),
 	}
 
@@ -245,8 +250,7 @@ func (e *Engine) handleTownInnSleep(session *GameSession, cmd GameCommand) GameR
 	}
 
 	// Deduct gold
-	goldRes.Stock -= cost
-	player.ResourceStorageMap["Gold"] = goldRes
+	game.AdjustGold(player, -cost, game.ReasonInnSleep, "town:"+town.Name)
 
 	// Restore stats
 	player.HitpointsRemaining = player.HitpointsTotal
@@ -332,8 +336,7 @@ func (e *Engine) handleTownInnHireGuard(session *GameSession, cmd GameCommand) G
 		}
 
 		// Deduct gold and add guard
-		goldRes.Stock -= guard.Cost
-		player.ResourceStorageMap["Gold"] = goldRes
+		game.AdjustGold(player, -guard.Cost, game.ReasonInnGuardHire, "guard:"+guard.Name)
 		guard.Hired = true
 		town.InnGuests[guestIdx].HiredGuards = append(town.InnGuests[guestIdx].HiredGuards, guard)
 		e.saveTown(town)
@@ -469,12 +472,13 @@ func (e *Engine) startInnPvP(session *GameSession, town *models.Town, target *mo
 	// Build synthetic monster from guest snapshot
 	mob := game.InnGuestToMonster(target)
 
-	// Set up combat guards from the target's hired guards
-	var combatGuards []models.Guard
+	// The target's hired guards fight on the target's side
+	var enemyGuards []models.Guard
 	for _, g := range target.HiredGuards {
 		gc := g
 		gc.HitpointsRemaining = gc.HitPoints
-		combatGuards = append(combatGuards, gc)
+		gc.Injured = false
+		enemyGuards = append(enemyGuards, gc)
 	}
 
 	// Resurrect player if needed
@@ -493,8 +497,7 @@ func (e *Engine) startInnPvP(session *GameSession, town *models.Town, target *mo
 		IsDefending:    false,
 		IsPvP:          true,
 		PvPTargetGuest: target,
-		CombatGuards:   combatGuards,
-		HasGuards:      len(combatGuards) > 0,
+		EnemyGuards:    enemyGuards,
 	}
 
 	// Restore mana/stamina
@@ -507,9 +510,8 @@ func (e *Engine) startInnPvP(session *GameSession, town *models.Town, target *mo
 		Msg("============================================================", "system"),
 	}
 
-	if len(combatGuards) > 0 {
-		msgs = append(msgs, Msg(fmt.Sprintf("The target has %d guards defending them!", len(combatGuards)), "combat"))
-		// Guards fight alongside the target in PvP
+	if len(enemyGuards) > 0 {
+		msgs = append(msgs, Msg(fmt.Sprintf("The target has %d guards defending them!", len(enemyGuards)), "combat"))
 	}
 
 	session.State = StateCombat
@@ -651,8 +653,7 @@ func (e *Engine) handleTownInnGamblePlay(session *GameSession, cmd GameCommand)
 	}
 
 	// Deduct bet
-	goldRes.Stock -= bet
-	player.ResourceStorageMap["Gold"] = goldRes
+	game.AdjustGold(player, -bet, game.ReasonGamble, "town:"+town.Name)
 
 	won, narrative, payout := game.ResolveGamble(player.Level, bet)
 
@@ -664,8 +665,7 @@ func (e *Engine) handleTownInnGamblePlay(session *GameSession, cmd GameCommand)
 	}
 
 	if won {
-		goldRes.Stock += payout
-		player.ResourceStorageMap["Gold"] = goldRes
+		game.AdjustGold(player, payout, game.ReasonGambleWin, "town:"+town.Name)
 		msgs = append(msgs, Msg(fmt.Sprintf("You won %d gold! (net +%d)", payout, payout-bet), "loot"))
 	} else {
 		msgs = append(msgs, Msg(fmt.Sprintf("You lost %d gold!", bet), "damage"))
@@ -720,8 +720,7 @@ func (e *Engine) handleTownInnHireFighter(session *GameSession, cmd GameCommand)
 			}
 
 			// Deduct gold
-			goldRes.Stock -= fighter.HireCost
-			player.ResourceStorageMap["Gold"] = goldRes
+			game.AdjustGold(player, -fighter.HireCost, game.ReasonFighterHire, "fighter:"+fighter.Name)
 
 			// Create a guard from the fighter
 			guard := models.Guard{
@@ -954,31 +953,8 @@ func (e *Engine) startMayorChallengePhase(session *GameSession, town *models.Tow
 			// Skip to phase 1
 			return e.startMayorChallengePhase(session, town, 1)
 		}
-		// Create a combined guard monster
 		guard := town.Mayor.Guards[0]
-		mob := models.Monster{
-			Name:               guard.Name + " (Mayor's Guard)",
-			Level:              guard.Level,
-			Rank:               guard.Level/3 + 1,
-			HitpointsTotal:     guard.HitPoints,
-			HitpointsNatural:   guard.HitPoints,
-			HitpointsRemaining: guard.HitPoints,
-			ManaTotal:          30,
-			ManaNatural:        30,
-			ManaRemaining:      30,
-			StaminaTotal:       30,
-			StaminaNatural:     30,
-			StaminaRemaining:   30,
-			AttackRolls:        guard.AttackRolls,
-			DefenseRolls:       guard.DefenseRolls,
-			StatsMod:           guard.StatsMod,
-			EquipmentMap:       guard.EquipmentMap,
-			Inventory:          guard.Inventory,
-			LearnedSkills:      game.AssignMonsterSkills("humanoid", guard.Level),
-			StatusEffects:      []models.StatusEffect{},
-			Resistances:        guard.Resistances,
-			MonsterType:        "humanoid",
-		}
+		mob := game.GuardToMonster(guard, guard.Name+" (Mayor's Guard)")
 
 		session.Combat = &CombatContext{
 			Mob:                 mob,
@@ -1095,11 +1071,12 @@ func (e *Engine) handleTownMayorMenu(session *GameSession, cmd GameCommand) Game
 	switch cmd.Value {
 	case "1": // Set Tax
 		session.State = StateTownMayorSetTax
+		bal := config.Current().Balance
 		return GameResponse{
 			Type:     "menu",
-			Messages: []GameMessage{Msg(fmt.Sprintf("Current tax rate: %d%%. Enter new rate (0-50):", town.TaxRate), "system")},
+			Messages: []GameMessage{Msg(fmt.Sprintf("Current tax rate: %d%%. Enter new rate (%d-%d):", town.TaxRate, bal.TaxMin, bal.TaxMax), "system")},
 			State:    townStateData("town_mayor_set_tax", session, town),
-			Prompt:   "New tax rate (0-50): ",
+			Prompt:   fmt.Sprintf("New tax rate (%d-%d): ", bal.TaxMin, bal.TaxMax),
 		}
 	case "2": // Create Fetch Quest
 		session.State = StateTownMayorCreateQuest
@@ -1183,11 +1160,12 @@ func (e *Engine) handleTownMayorSetTax(session *GameSession, cmd GameCommand) Ga
 	}
 	session.SelectedTown = town
 
+	bal := config.Current().Balance
 	rate, parseErr := strconv.Atoi(cmd.Value)
-	if parseErr != nil || rate < 0 || rate > 50 {
+	if parseErr != nil || rate < bal.TaxMin || rate > bal.TaxMax {
 		session.State = StateTownMayorMenu
 		resp := e.handleTownMayorMenu(session, GameCommand{Type: "init"})
-		resp.Messages = append([]GameMessage{Msg("Invalid tax rate! Must be 0-50.", "error")}, resp.Messages...)
+		resp.Messages = append([]GameMessage{Msg(fmt.Sprintf("Invalid tax rate! Must be %d-%d.", bal.TaxMin, bal.TaxMax), "error")}, resp.Messages...)
 		return resp
 	}
 
@@ -1580,16 +1558,10 @@ func (e *Engine) completeFetchQuest(session *GameSession, town *models.Town, que
 			}
 
 			// Deduct resources
-			res.Stock -= fq.Amount
-			player.ResourceStorageMap[fq.Resource] = res
+			game.AdjustResource(player, fq.Resource, -fq.Amount, game.ReasonFetchQuest, "fetch_quest:"+fq.ID)
 
 			// Award gold
-			goldRes, hasGold := player.ResourceStorageMap["Gold"]
-			if !hasGold {
-				goldRes = models.Resource{Name: "Gold", Stock: 0}
-			}
-			goldRes.Stock += fq.RewardGold
-			player.ResourceStorageMap["Gold"] = goldRes
+			game.AdjustGold(player, fq.RewardGold, game.ReasonFetchQuest, "fetch_quest:"+fq.ID)
 
 			// Award XP
 			player.Experience += fq.RewardXP
@@ -1651,7 +1623,7 @@ func (e *Engine) resolvePvPWin(session *GameSession, msgs []GameMessage) int {
 		if transferred >= maxTransfer {
 			break
 		}
-		game.EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+		game.LootItem(player, item, game.ReasonPvPTheft, "inn_guest:"+target.CharacterName)
 		delete(target.EquipmentMap, slot)
 		transferred++
 	}
@@ -1660,12 +1632,7 @@ func (e *Engine) resolvePvPWin(session *GameSession, msgs []GameMessage) int {
 	goldLooted := 0
 	if target.AccountID == 0 && target.GoldCarried > 0 {
 		goldLooted = target.GoldCarried
-		goldRes, hasGold := player.ResourceStorageMap["Gold"]
-		if !hasGold {
-			goldRes = models.Resource{Name: "Gold", Stock: 0}
-		}
-		goldRes.Stock += goldLooted
-		player.ResourceStorageMap["Gold"] = goldRes
+		game.AdjustGold(player, goldLooted, game.ReasonPvPTheft, "inn_guest:"+target.CharacterName)
 	}
 
 	// Remove target from inn
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"rpg-game/pkg/data"
@@ -32,12 +33,18 @@ func buildVillageMainResponse(session *GameSession, extraMsgs []GameMessage) Gam
 		Msg(fmt.Sprintf("  %s - Level %d", village.Name, village.Level), "system"),
 		Msg("============================================================", "system"),
 		Msg(fmt.Sprintf("Experience: %d/%d", village.Experience, village.Level*100), "system"),
-		Msg(fmt.Sprintf("Villagers: %d (Harvesters: %d, Guards: %d)",
+		Msg(fmt.Sprintf("Villagers: %d (Harvesters: %d, Crafters: %d, Builders: %d, Guards: %d)",
 			len(village.Villagers),
 			game.CountVillagersByRole(village, "harvester"),
+			game.CountVillagersByRole(village, "crafter"),
+			game.CountVillagersByRole(village, "builder"),
 			game.CountVillagersByRole(village, "guard")), "system"),
-		Msg(fmt.Sprintf("Hired Guards: %d", len(village.ActiveGuards)), "system"),
+		Msg(fmt.Sprintf("Housing: %d/%d | Food: %d/%d (+%d/-%d per tick)",
+			len(village.Villagers), game.HousingCapacity(village), village.FoodStock, game.FoodStorage(village),
+			game.FoodProduction(village), game.FoodUpkeep(village)), "system"),
+		Msg(fmt.Sprintf("Hired Guards: %d/%d", len(village.ActiveGuards), game.GuardCapacity(village)), "system"),
 		Msg(fmt.Sprintf("Defenses Built: %d (Level %d)", len(village.Defenses), village.DefenseLevel), "system"),
+		Msg("Buildings: "+game.BuildingsText(village), "system"),
 	)
 
 	if len(village.UnlockedCrafting) > 0 {
@@ -53,13 +60,19 @@ func buildVillageMainResponse(session *GameSession, extraMsgs []GameMessage) Gam
 
 	options := []MenuOption{
 		Opt("1", "View Villagers"),
-		Opt("2", "Assign Harvester Tasks"),
+		Opt("2", "Assign Villager Tasks"),
 		Opt("3", "Hire Guards"),
 		Opt("4", "Crafting"),
 		Opt("5", "Build Defenses"),
 		Opt("6", "Check Next Monster Tide"),
 		Opt("7", "Defend Against Tide (if ready)"),
 		Opt("8", "Manage Guards (Equipment & Status)"),
+		Opt("9", "Blacksmith (Repairs & Repair Kits)"),
+		Opt("10", fmt.Sprintf("Stash (%d/%d)", len(village.Stash), game.StashCapacity(village))),
+		Opt("11", "Enchanting Station (Sockets & Runes)"),
+		Opt("12", fmt.Sprintf("Work Queue (%d jobs)", len(village.Jobs))),
+		Opt("13", fmt.Sprintf("Buildings (%d)", len(village.Buildings))),
+		Opt("14", "Raids (Scout, Raid & Revenge)"),
 		Opt("0", "Return to Main Menu"),
 	}
 
@@ -120,6 +133,25 @@ func (e *Engine) handleVillageMain(session *GameSession, cmd GameCommand) GameRe
 	case "8":
 		session.State = StateVillageManageGuards
 		return e.handleVillageManageGuards(session, GameCommand{Type: "init"})
+	case "9":
+		session.State = StateBlacksmith
+		return e.handleBlacksmith(session, GameCommand{Type: "init"})
+	case "10":
+		session.State = StateVillageStash
+		return e.handleVillageStash(session, GameCommand{Type: "init"})
+	case "11":
+		session.EnchantTarget = ""
+		session.State = StateEnchanting
+		return e.handleEnchanting(session, GameCommand{Type: "init"})
+	case "12":
+		session.State = StateVillageJobs
+		return e.handleVillageJobs(session, GameCommand{Type: "init"})
+	case "13":
+		session.State = StateVillageBuildings
+		return e.handleVillageBuildings(session, GameCommand{Type: "init"})
+	case "14":
+		session.State = StateVillageRaids
+		return e.handleVillageRaids(session, GameCommand{Type: "init"})
 	case "0":
 		e.saveVillage(session)
 		session.SelectedVillage = nil
@@ -153,12 +185,16 @@ func (e *Engine) handleVillageViewVillagers(session *GameSession, cmd GameComman
 		msgs = append(msgs, Msg("No villagers yet. Rescue them during hunts!", "narrative"))
 	} else {
 		harvesters := []models.Villager{}
+		workers := []models.Villager{}
 		guards := []models.Villager{}
 
 		for _, v := range village.Villagers {
-			if v.Role == "harvester" {
+			switch v.Role {
+			case "harvester":
 				harvesters = append(harvesters, v)
-			} else {
+			case "crafter", "builder":
+				workers = append(workers, v)
+			default:
 				guards = append(guards, v)
 			}
 		}
@@ -167,11 +203,17 @@ func (e *Engine) handleVillageViewVillagers(session *GameSession, cmd GameComman
 			msgs = append(msgs, Msg("", "system"))
 			msgs = append(msgs, Msg("HARVESTERS:", "system"))
 			for i, v := range harvesters {
-				taskInfo := "Idle"
-				if v.HarvestType != "" {
-					taskInfo = fmt.Sprintf("Harvesting %s (+%d/visit)", v.HarvestType, v.Efficiency+(v.Level/2))
-				}
-				msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s (Lv%d) - %s", i+1, v.Name, v.Level, taskInfo), "system"))
+				msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s (Lv%d) - %s", i+1, v.Name, v.Level, villagerTaskInfo(v)), "system"))
+				msgs = append(msgs, Msg("     "+villagerDetails(v), "system"))
+			}
+		}
+
+		if len(workers) > 0 {
+			msgs = append(msgs, Msg("", "system"))
+			msgs = append(msgs, Msg("CRAFTERS & BUILDERS:", "system"))
+			for i, v := range workers {
+				msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s (%s, Lv%d) - %s", i+1, v.Name, v.Role, v.Level, villagerTaskInfo(v)), "system"))
+				msgs = append(msgs, Msg("     "+villagerDetails(v), "system"))
 			}
 		}
 
@@ -179,7 +221,8 @@ func (e *Engine) handleVillageViewVillagers(session *GameSession, cmd GameComman
 			msgs = append(msgs, Msg("", "system"))
 			msgs = append(msgs, Msg("GUARDS:", "system"))
 			for i, v := range guards {
-				msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s (Lv%d) - Efficiency: %d", i+1, v.Name, v.Level, v.Efficiency), "system"))
+				msgs = append(msgs, Msg(fmt.Sprintf("  %d. %s (Lv%d) - %s", i+1, v.Name, v.Level, villagerTaskInfo(v)), "system"))
+				msgs = append(msgs, Msg("     "+villagerDetails(v), "system"))
 			}
 		}
 	}
@@ -203,50 +246,48 @@ func (e *Engine) handleVillageAssignTask(session *GameSession, cmd GameCommand)
 		return e.handleVillageMain(session, GameCommand{Type: "init"})
 	}
 
-	// Build list of harvesters
-	harvesters := []int{}
+	// Build list of villagers that can be given a task; guards only guard
+	workers := []int{}
 	for i, v := range village.Villagers {
-		if v.Role == "harvester" {
-			harvesters = append(harvesters, i)
+		if game.CanTakeTask(v, game.TaskCrafting) {
+			workers = append(workers, i)
 		}
 	}
 
-	if len(harvesters) == 0 {
+	if len(workers) == 0 {
 		session.State = StateVillageMain
 		resp := e.handleVillageMain(session, GameCommand{Type: "init"})
-		resp.Messages = append([]GameMessage{Msg("No harvesters available!", "error")}, resp.Messages...)
+		resp.Messages = append([]GameMessage{Msg("No villagers available for tasks!", "error")}, resp.Messages...)
 		return resp
 	}
 
 	// Count idle harvesters for batch assign option
 	idleCount := 0
-	for _, idx := range harvesters {
-		if village.Villagers[idx].HarvestType == "" {
+	for _, idx := range workers {
+		if game.IsIdleHarvester(village.Villagers[idx]) {
 			idleCount++
 		}
 	}
 
-	// If cmd.Type is "init", show the harvester selection
+	// If cmd.Type is "init", show the villager selection
 	if cmd.Type == "init" {
 		msgs := []GameMessage{
 			Msg("============================================================", "system"),
-			Msg("ASSIGN HARVESTER TASK", "system"),
+			Msg("ASSIGN VILLAGER TASK", "system"),
 			Msg("============================================================", "system"),
+			Msg("Harvesters gather resources or help out in the work queue.", "system"),
+			Msg("Crafters and builders work queued jobs at double speed.", "system"),
 			Msg("", "system"),
-			Msg("Available Harvesters:", "system"),
+			Msg("Available Villagers:", "system"),
 		}
 
 		options := []MenuOption{}
 		if idleCount > 0 {
 			options = append(options, Opt("all", fmt.Sprintf("Assign All Idle Harvesters (%d)", idleCount)))
 		}
-		for i, idx := range harvesters {
+		for i, idx := range workers {
 			v := village.Villagers[idx]
-			taskInfo := "Idle"
-			if v.HarvestType != "" {
-				taskInfo = "Currently: " + v.HarvestType
-			}
-			label := fmt.Sprintf("%s (Lv%d, Efficiency %d) - %s", v.Name, v.Level, v.Efficiency, taskInfo)
+			label := fmt.Sprintf("%s (%s, Lv%d, Efficiency %d) - %s", v.Name, v.Role, v.Level, v.Efficiency, villagerTaskInfo(v))
 			options = append(options, Opt(strconv.Itoa(i+1), label))
 		}
 		options = append(options, Opt("0", "Cancel"))
@@ -269,13 +310,13 @@ func (e *Engine) handleVillageAssignTask(session *GameSession, cmd GameCommand)
 		return e.handleVillageBatchAssign(session, GameCommand{Type: "init"})
 	}
 
-	// A harvester was selected - store index and move to resource selection
+	// A villager was selected - store index and move to task selection
 	idx, err := strconv.Atoi(cmd.Value)
-	if err != nil || idx < 1 || idx > len(harvesters) {
+	if err != nil || idx < 1 || idx > len(workers) {
 		return ErrorResponse("Invalid choice!")
 	}
 
-	session.SelectedVillagerIdx = harvesters[idx-1]
+	session.SelectedVillagerIdx = workers[idx-1]
 	session.State = StateVillageAssignResource
 	return e.handleVillageAssignResource(session, GameCommand{Type: "init"})
 }
@@ -288,15 +329,27 @@ func (e *Engine) handleVillageAssignResource(session *GameSession, cmd GameComma
 		return e.handleVillageAssignTask(session, GameCommand{Type: "init"})
 	}
 
+	villagerIdx := session.SelectedVillagerIdx
+	if villagerIdx < 0 || villagerIdx >= len(village.Villagers) {
+		session.State = StateVillageAssignTask
+		return e.handleVillageAssignTask(session, GameCommand{Type: "init"})
+	}
+	villager := &village.Villagers[villagerIdx]
+
 	if cmd.Type == "init" {
 		msgs := []GameMessage{
-			Msg("Assign to resource:", "system"),
+			Msg(fmt.Sprintf("Assign %s to:", villager.Name), "system"),
 		}
 		options := []MenuOption{}
-		for i, res := range data.ResourceTypes {
-			options = append(options, Opt(strconv.Itoa(i+1), res))
+		if game.CanTakeTask(*villager, game.TaskHarvesting) {
+			for i, res := range data.ResourceTypes {
+				options = append(options, Opt(strconv.Itoa(i+1), "Harvest "+res))
+			}
 		}
-		options = append(options, Opt("0", "Cancel"))
+		options = append(options,
+			Opt(game.TaskCrafting, fmt.Sprintf("Crafting jobs (+%d work/tick)", game.VillagerWork(*villager, game.TaskCrafting))),
+			Opt(game.TaskBuilding, fmt.Sprintf("Building jobs (+%d work/tick)", game.VillagerWork(*villager, game.TaskBuilding))),
+			Opt("0", "Cancel"))
 
 		session.State = StateVillageAssignResource
 		return GameResponse{
@@ -307,14 +360,27 @@ func (e *Engine) handleVillageAssignResource(session *GameSession, cmd GameComma
 		}
 	}
 
+	if cmd.Value == game.TaskCrafting || cmd.Value == game.TaskBuilding {
+		if err := game.AssignVillagerTask(villager, cmd.Value); err != nil {
+			return ErrorResponse(err.Error())
+		}
+		e.saveVillage(session)
+
+		session.State = StateVillageMain
+		resp := e.handleVillageMain(session, GameCommand{Type: "init"})
+		resp.Messages = append([]GameMessage{
+			Msg(fmt.Sprintf("%s is now working on %s jobs!", villager.Name, cmd.Value), "system"),
+		}, resp.Messages...)
+		return resp
+	}
+
 	resIdx, err := strconv.Atoi(cmd.Value)
-	if err != nil || resIdx < 1 || resIdx > len(data.ResourceTypes) {
+	if err != nil || resIdx < 1 || resIdx > len(data.ResourceTypes) || !game.CanTakeTask(*villager, game.TaskHarvesting) {
 		return ErrorResponse("Invalid choice!")
 	}
 
-	villagerIdx := session.SelectedVillagerIdx
 	village.Villagers[villagerIdx].HarvestType = data.ResourceTypes[resIdx-1]
-	village.Villagers[villagerIdx].AssignedTask = "harvesting"
+	village.Villagers[villagerIdx].AssignedTask = game.TaskHarvesting
 	village.Experience += 10
 
 	e.saveVillage(session)
@@ -367,9 +433,9 @@ func (e *Engine) handleVillageBatchAssign(session *GameSession, cmd GameCommand)
 	resourceName := data.ResourceTypes[resIdx-1]
 	assigned := 0
 	for i := range village.Villagers {
-		if village.Villagers[i].Role == "harvester" && village.Villagers[i].HarvestType == "" {
+		if game.IsIdleHarvester(village.Villagers[i]) {
 			village.Villagers[i].HarvestType = resourceName
-			village.Villagers[i].AssignedTask = "harvesting"
+			village.Villagers[i].AssignedTask = game.TaskHarvesting
 			village.Experience += 10
 			assigned++
 		}
@@ -395,6 +461,17 @@ func (e *Engine) handleVillageHireGuard(session *GameSession, cmd GameCommand) G
 		return e.handleVillageMain(session, GameCommand{Type: "init"})
 	}
 
+	if len(village.ActiveGuards) >= game.GuardCapacity(village) {
+		session.State = StateVillageHireGuard
+		return GameResponse{
+			Type: "menu",
+			Messages: []GameMessage{Msg(fmt.Sprintf("Your village can only keep %d guards. Build or upgrade a Barracks to house more.",
+				game.GuardCapacity(village)), "error")},
+			State:   &StateData{Screen: "village_hire_guard", Player: MakePlayerState(player)},
+			Options: []MenuOption{Opt("back", "Back")},
+		}
+	}
+
 	if cmd.Type == "init" {
 		goldResource := player.ResourceStorageMap["Gold"]
 
@@ -403,6 +480,7 @@ func (e *Engine) handleVillageHireGuard(session *GameSession, cmd GameCommand) G
 			Msg("GUARD RECRUITMENT", "system"),
 			Msg("============================================================", "system"),
 			Msg(fmt.Sprintf("Your Gold: %d", goldResource.Stock), "system"),
+			Msg(fmt.Sprintf("Guards: %d/%d", len(village.ActiveGuards), game.GuardCapacity(village)), "system"),
 			Msg("", "system"),
 			Msg("Available Guards for Hire:", "system"),
 		}
@@ -464,8 +542,7 @@ func (e *Engine) handleVillageHireGuard(session *GameSession, cmd GameCommand) G
 			}
 		}
 
-		goldResource.Stock -= selectedGuard.Cost
-		player.ResourceStorageMap["Gold"] = goldResource
+		game.AdjustGold(player, -selectedGuard.Cost, game.ReasonGuardHire, "guard:"+selectedGuard.Name)
 		selectedGuard.Hired = true
 		village.ActiveGuards = append(village.ActiveGuards, selectedGuard)
 		village.Experience += 50
@@ -510,8 +587,7 @@ func (e *Engine) handleVillageHireGuard(session *GameSession, cmd GameCommand) G
 					}
 				}
 
-				goldResource.Stock -= selectedGuard.Cost
-				player.ResourceStorageMap["Gold"] = goldResource
+				game.AdjustGold(player, -selectedGuard.Cost, game.ReasonGuardHire, "guard:"+selectedGuard.Name)
 				selectedGuard.Hired = true
 				village.ActiveGuards = append(village.ActiveGuards, selectedGuard)
 				village.Experience += 50
@@ -635,360 +711,27 @@ func (e *Engine) handleVillageCrafting(session *GameSession, cmd GameCommand) Ga
 }
 
 func (e *Engine) handleVillageCraftPotion(session *GameSession, cmd GameCommand) GameResponse {
-	village := session.SelectedVillage
-	player := session.Player
-
 	if cmd.Value == "0" || cmd.Value == "back" {
 		session.State = StateVillageCrafting
 		return e.handleVillageCrafting(session, GameCommand{Type: "init"})
 	}
-
-	type potionRecipe struct {
-		name     string
-		size     string
-		ironCost int
-		goldCost int
-	}
-	recipes := []potionRecipe{
-		{"Small Health Potion", "small", 5, 10},
-		{"Medium Health Potion", "medium", 10, 20},
-		{"Large Health Potion", "large", 20, 40},
-	}
-
-	if cmd.Type != "init" {
-		idx, err := strconv.Atoi(cmd.Value)
-		if err == nil && idx >= 1 && idx <= len(recipes) {
-			recipe := recipes[idx-1]
-			iron := player.ResourceStorageMap["Iron"]
-			gold := player.ResourceStorageMap["Gold"]
-
-			if iron.Stock < recipe.ironCost {
-				// Show error and re-display
-				session.State = StateVillageCraftPotion
-				return GameResponse{
-					Type:     "menu",
-					Messages: []GameMessage{Msg(fmt.Sprintf("Not enough Iron! Need %d, have %d", recipe.ironCost, iron.Stock), "error")},
-					State:    &StateData{Screen: "village_craft_potion", Player: MakePlayerState(player)},
-					Options:  []MenuOption{Opt("back", "Back")},
-				}
-			}
-			if gold.Stock < recipe.goldCost {
-				session.State = StateVillageCraftPotion
-				return GameResponse{
-					Type:     "menu",
-					Messages: []GameMessage{Msg(fmt.Sprintf("Not enough Gold! Need %d, have %d", recipe.goldCost, gold.Stock), "error")},
-					State:    &StateData{Screen: "village_craft_potion", Player: MakePlayerState(player)},
-					Options:  []MenuOption{Opt("back", "Back")},
-				}
-			}
-
-			iron.Stock -= recipe.ironCost
-			gold.Stock -= recipe.goldCost
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Gold"] = gold
-
-			potion := game.CreateHealthPotion(recipe.size)
-			player.Inventory = append(player.Inventory, potion)
-			village.Experience += 20
-
-			e.saveVillage(session)
-
-			session.State = StateVillageCraftPotion
-			msgs := []GameMessage{
-				Msg(fmt.Sprintf("Crafted %s!", recipe.name), "loot"),
-				Msg("+20 Village XP", "system"),
-			}
-			return GameResponse{
-				Type:     "menu",
-				Messages: msgs,
-				State:    &StateData{Screen: "village_craft_potion", Player: MakePlayerState(player)},
-				Options: []MenuOption{
-					Opt("1", fmt.Sprintf("Small Health Potion (Iron: %d, Gold: %d)", recipes[0].ironCost, recipes[0].goldCost)),
-					Opt("2", fmt.Sprintf("Medium Health Potion (Iron: %d, Gold: %d)", recipes[1].ironCost, recipes[1].goldCost)),
-					Opt("3", fmt.Sprintf("Large Health Potion (Iron: %d, Gold: %d)", recipes[2].ironCost, recipes[2].goldCost)),
-					Opt("0", "Back"),
-				},
-			}
-		}
-	}
-
-	// Show potion crafting menu
-	msgs := []GameMessage{
-		Msg("============================================================", "system"),
-		Msg("POTION CRAFTING", "system"),
-		Msg("============================================================", "system"),
-		Msg("", "system"),
-		Msg("Available Recipes:", "system"),
-	}
-
-	options := []MenuOption{}
-	for i, recipe := range recipes {
-		options = append(options, Opt(strconv.Itoa(i+1), fmt.Sprintf("%s (Iron: %d, Gold: %d)", recipe.name, recipe.ironCost, recipe.goldCost)))
-	}
-	options = append(options, Opt("0", "Back"))
-
-	session.State = StateVillageCraftPotion
-	return GameResponse{
-		Type:     "menu",
-		Messages: msgs,
-		State:    &StateData{Screen: "village_craft_potion", Player: MakePlayerState(player)},
-		Options:  options,
-	}
+	return e.handleRecipeMenu(session, cmd, "potions")
 }
 
 func (e *Engine) handleVillageCraftArmor(session *GameSession, cmd GameCommand) GameResponse {
-	village := session.SelectedVillage
-	player := session.Player
-
 	if cmd.Value == "0" || cmd.Value == "back" {
 		session.State = StateVillageCrafting
 		return e.handleVillageCrafting(session, GameCommand{Type: "init"})
 	}
-
-	type armorRecipe struct {
-		name      string
-		materials map[string]int
-		defBonus  func(int) int
-		hpBonus   func(int) int
-		atkBonus  func(int) int
-		rarityMin int
-		rarityMax int
-		xp        int
-		desc      string
-	}
-
-	recipes := []armorRecipe{
-		{"Enhanced Armor", map[string]int{"Iron": 30, "Stone": 20}, func(r int) int { return r * 2 }, func(r int) int { return r }, func(r int) int { return 0 }, 3, 5, 40, "Random armor, Rarity 3-5, Defense focus"},
-		{"Beast Skin Armor", map[string]int{"Iron": 20, "Beast Skin": 15}, func(r int) int { return r * 2 }, func(r int) int { return r + 3 }, func(r int) int { return 0 }, 4, 6, 50, "Light armor, Rarity 4-6, Fire resistance"},
-		{"Bone Plate Armor", map[string]int{"Iron": 25, "Beast Bone": 12, "Stone": 15}, func(r int) int { return r * 3 }, func(r int) int { return r * 2 }, func(r int) int { return 0 }, 5, 7, 60, "Heavy armor, Rarity 5-7, High defense"},
-		{"Tough Hide Vest", map[string]int{"Tough Hide": 10, "Beast Bone": 8}, func(r int) int { return r*2 + 2 }, func(r int) int { return r + 4 }, func(r int) int { return 0 }, 4, 6, 50, "Medium armor, Rarity 4-6, Physical resistance"},
-		{"Ore Fragment Mail", map[string]int{"Ore Fragment": 20, "Iron": 15}, func(r int) int { return r*2 + 3 }, func(r int) int { return r }, func(r int) int { return 0 }, 5, 7, 60, "Magic armor, Rarity 5-7, Lightning resistance"},
-		{"Fang-Studded Armor", map[string]int{"Sharp Fang": 15, "Beast Skin": 10, "Iron": 20}, func(r int) int { return r*2 + 5 }, func(r int) int { return r * 2 }, func(r int) int { return r }, 6, 8, 70, "Spiked armor, Rarity 6-8, Counter-damage bonus"},
-		{"Claw Guard Armor", map[string]int{"Monster Claw": 12, "Tough Hide": 8, "Iron": 15}, func(r int) int { return r*3 + 2 }, func(r int) int { return r*2 + 3 }, func(r int) int { return 0 }, 6, 8, 70, "Elite armor, Rarity 6-8, Ice resistance"},
-	}
-
-	if cmd.Type != "init" {
-		idx, err := strconv.Atoi(cmd.Value)
-		if err == nil && idx >= 1 && idx <= len(recipes) {
-			recipe := recipes[idx-1]
-
-			// Check resources
-			for mat, qty := range recipe.materials {
-				res := player.ResourceStorageMap[mat]
-				if res.Stock < qty {
-					session.State = StateVillageCraftArmor
-					return GameResponse{
-						Type:     "menu",
-						Messages: []GameMessage{Msg(fmt.Sprintf("Not enough %s! Need %d, have %d", mat, qty, res.Stock), "error")},
-						State:    &StateData{Screen: "village_craft_armor", Player: MakePlayerState(player)},
-						Options:  []MenuOption{Opt("back", "Back")},
-					}
-				}
-			}
-
-			// Deduct resources
-			for mat, qty := range recipe.materials {
-				res := player.ResourceStorageMap[mat]
-				res.Stock -= qty
-				player.ResourceStorageMap[mat] = res
-			}
-
-			rarity := recipe.rarityMin + rand.Intn(recipe.rarityMax-recipe.rarityMin+1)
-			armor := game.GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += recipe.defBonus(rarity)
-			armor.StatsMod.HitPointMod += recipe.hpBonus(rarity)
-			armor.StatsMod.AttackMod += recipe.atkBonus(rarity)
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			game.EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			player.StatsMod = game.CalculateItemMods(player.EquipmentMap)
-			player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
-
-			village.Experience += recipe.xp
-
-			e.saveVillage(session)
-
-			session.State = StateVillageCraftArmor
-			extraMsgs := []GameMessage{
-				Msg(fmt.Sprintf("Crafted %s (Rarity %d)!", recipe.name, rarity), "loot"),
-				Msg(fmt.Sprintf("   Defense: +%d | HP: +%d | CP: %d", armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod, armor.CP), "system"),
-				Msg(fmt.Sprintf("+%d Village XP", recipe.xp), "system"),
-			}
-			return buildArmorCraftingResponse(session, extraMsgs)
-		}
-	}
-
-	return buildArmorCraftingResponse(session, nil)
-}
-
-func buildArmorCraftingResponse(session *GameSession, extraMsgs []GameMessage) GameResponse {
-	msgs := append([]GameMessage{}, extraMsgs...)
-	msgs = append(msgs,
-		Msg("============================================================", "system"),
-		Msg("ARMOR CRAFTING", "system"),
-		Msg("============================================================", "system"),
-		Msg("", "system"),
-		Msg("Available Recipes:", "system"),
-		Msg("", "system"),
-		Msg("--- STANDARD ARMOR ---", "system"),
-		Msg("1. Enhanced Armor (Iron: 30, Stone: 20) -> Defense focus", "system"),
-		Msg("", "system"),
-		Msg("--- BEAST MATERIAL ARMOR ---", "system"),
-		Msg("2. Beast Skin Armor (Iron: 20, Beast Skin: 15) -> Fire resistance", "system"),
-		Msg("3. Bone Plate Armor (Iron: 25, Beast Bone: 12, Stone: 15) -> High defense", "system"),
-		Msg("4. Tough Hide Vest (Tough Hide: 10, Beast Bone: 8) -> Physical resistance", "system"),
-		Msg("5. Ore Fragment Mail (Ore Fragment: 20, Iron: 15) -> Lightning resistance", "system"),
-		Msg("6. Fang-Studded Armor (Sharp Fang: 15, Beast Skin: 10, Iron: 20) -> Counter-damage", "system"),
-		Msg("7. Claw Guard Armor (Monster Claw: 12, Tough Hide: 8, Iron: 15) -> Ice resistance", "system"),
-	)
-
-	options := []MenuOption{
-		Opt("1", "Enhanced Armor"),
-		Opt("2", "Beast Skin Armor"),
-		Opt("3", "Bone Plate Armor"),
-		Opt("4", "Tough Hide Vest"),
-		Opt("5", "Ore Fragment Mail"),
-		Opt("6", "Fang-Studded Armor"),
-		Opt("7", "Claw Guard Armor"),
-		Opt("0", "Back"),
-	}
-
-	return GameResponse{
-		Type:     "menu",
-		Messages: msgs,
-		State:    &StateData{Screen: "village_craft_armor", Player: MakePlayerState(session.Player)},
-		Options:  options,
-	}
-}
-
-func buildWeaponCraftingResponse(session *GameSession, extraMsgs []GameMessage) GameResponse {
-	msgs := append([]GameMessage{}, extraMsgs...)
-	msgs = append(msgs,
-		Msg("============================================================", "system"),
-		Msg("WEAPON CRAFTING", "system"),
-		Msg("============================================================", "system"),
-		Msg("", "system"),
-		Msg("Available Recipes:", "system"),
-		Msg("", "system"),
-		Msg("--- STANDARD WEAPONS ---", "system"),
-		Msg("1. Enhanced Weapon (Iron: 40, Gold: 30) -> Attack focus", "system"),
-		Msg("", "system"),
-		Msg("--- BEAST MATERIAL WEAPONS ---", "system"),
-		Msg("2. Beast Claw Blade (Iron: 25, Monster Claw: 15, Sharp Fang: 10) -> Bleed bonus", "system"),
-		Msg("3. Bone Crusher Mace (Iron: 30, Beast Bone: 20, Stone: 15) -> Stun chance", "system"),
-		Msg("4. Hide-Wrapped Axe (Iron: 20, Tough Hide: 12, Lumber: 25) -> Balanced", "system"),
-		Msg("5. Ore Fragment Sword (Iron: 35, Ore Fragment: 25, Gold: 20) -> Elemental damage", "system"),
-		Msg("6. Fang Spear (Sharp Fang: 18, Beast Bone: 15, Iron: 20) -> Critical bonus", "system"),
-		Msg("7. Composite War Hammer (Beast Skin: 10, Ore Fragment: 15, Iron: 25, Stone: 20) -> Elite", "system"),
-	)
-
-	options := []MenuOption{
-		Opt("1", "Enhanced Weapon"),
-		Opt("2", "Beast Claw Blade"),
-		Opt("3", "Bone Crusher Mace"),
-		Opt("4", "Hide-Wrapped Axe"),
-		Opt("5", "Ore Fragment Sword"),
-		Opt("6", "Fang Spear"),
-		Opt("7", "Composite War Hammer"),
-		Opt("0", "Back"),
-	}
-
-	return GameResponse{
-		Type:     "menu",
-		Messages: msgs,
-		State:    &StateData{Screen: "village_craft_weapon", Player: MakePlayerState(session.Player)},
-		Options:  options,
-	}
-}
-
-// checkAndDeductResources verifies the player has sufficient resources and deducts them.
-// Returns true on success, or false with an error message on failure.
-func checkAndDeductResources(player *models.Character, materials map[string]int) (bool, string) {
-	for mat, qty := range materials {
-		res := player.ResourceStorageMap[mat]
-		if res.Stock < qty {
-			return false, fmt.Sprintf("Not enough %s! Need %d, have %d", mat, qty, res.Stock)
-		}
-	}
-	for mat, qty := range materials {
-		res := player.ResourceStorageMap[mat]
-		res.Stock -= qty
-		player.ResourceStorageMap[mat] = res
-	}
-	return true, ""
+	return e.handleRecipeMenu(session, cmd, "armor")
 }
 
 func (e *Engine) handleVillageCraftWeapon(session *GameSession, cmd GameCommand) GameResponse {
-	village := session.SelectedVillage
-	player := session.Player
-
 	if cmd.Value == "0" || cmd.Value == "back" {
 		session.State = StateVillageCrafting
 		return e.handleVillageCrafting(session, GameCommand{Type: "init"})
 	}
-
-	type weaponDef struct {
-		materials map[string]int
-		atkBonus  func(int) int
-		defBonus  func(int) int
-		hpBonus   func(int) int
-		rarityMin int
-		rarityMax int
-		xp        int
-		name      string
-	}
-
-	weapons := map[string]weaponDef{
-		"1": {map[string]int{"Iron": 40, "Gold": 30}, func(r int) int { return r * 3 }, func(r int) int { return 0 }, func(r int) int { return r / 2 }, 4, 6, 50, "Enhanced Weapon"},
-		"2": {map[string]int{"Iron": 25, "Monster Claw": 15, "Sharp Fang": 10}, func(r int) int { return r * 4 }, func(r int) int { return 0 }, func(r int) int { return r }, 5, 7, 60, "Beast Claw Blade"},
-		"3": {map[string]int{"Iron": 30, "Beast Bone": 20, "Stone": 15}, func(r int) int { return r*3 + 5 }, func(r int) int { return r }, func(r int) int { return r + 2 }, 5, 7, 60, "Bone Crusher Mace"},
-		"4": {map[string]int{"Iron": 20, "Tough Hide": 12, "Lumber": 25}, func(r int) int { return r*3 + 3 }, func(r int) int { return 0 }, func(r int) int { return r*2 + 5 }, 4, 6, 55, "Hide-Wrapped Axe"},
-		"5": {map[string]int{"Iron": 35, "Ore Fragment": 25, "Gold": 20}, func(r int) int { return r*4 + 5 }, func(r int) int { return 0 }, func(r int) int { return r + 3 }, 6, 8, 70, "Ore Fragment Sword"},
-		"6": {map[string]int{"Sharp Fang": 18, "Beast Bone": 15, "Iron": 20}, func(r int) int { return r*3 + 7 }, func(r int) int { return 0 }, func(r int) int { return r }, 5, 7, 65, "Fang Spear"},
-		"7": {map[string]int{"Beast Skin": 10, "Ore Fragment": 15, "Iron": 25, "Stone": 20}, func(r int) int { return r*5 + 3 }, func(r int) int { return r + 2 }, func(r int) int { return r*2 + 5 }, 6, 8, 80, "Composite War Hammer"},
-	}
-
-	if cmd.Type != "init" {
-		if wep, ok := weapons[cmd.Value]; ok {
-			ok2, errMsg := checkAndDeductResources(player, wep.materials)
-			if !ok2 {
-				session.State = StateVillageCraftWeapon
-				return GameResponse{
-					Type:     "menu",
-					Messages: []GameMessage{Msg(errMsg, "error")},
-					State:    &StateData{Screen: "village_craft_weapon", Player: MakePlayerState(player)},
-					Options:  []MenuOption{Opt("back", "Back")},
-				}
-			}
-
-			rarity := wep.rarityMin + rand.Intn(wep.rarityMax-wep.rarityMin+1)
-			weapon := game.GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += wep.atkBonus(rarity)
-			weapon.StatsMod.DefenseMod += wep.defBonus(rarity)
-			weapon.StatsMod.HitPointMod += wep.hpBonus(rarity)
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			game.EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			player.StatsMod = game.CalculateItemMods(player.EquipmentMap)
-			player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
-
-			village.Experience += wep.xp
-			e.saveVillage(session)
-
-			extraMsgs := []GameMessage{
-				Msg(fmt.Sprintf("Crafted %s (Rarity %d)!", wep.name, rarity), "loot"),
-				Msg(fmt.Sprintf("   Attack: +%d | Defense: +%d | HP: +%d | CP: %d",
-					weapon.StatsMod.AttackMod, weapon.StatsMod.DefenseMod, weapon.StatsMod.HitPointMod, weapon.CP), "system"),
-				Msg(fmt.Sprintf("+%d Village XP", wep.xp), "system"),
-			}
-			session.State = StateVillageCraftWeapon
-			resp := buildWeaponCraftingResponse(session, extraMsgs)
-			return resp
-		}
-	}
-
-	session.State = StateVillageCraftWeapon
-	return buildWeaponCraftingResponse(session, nil)
+	return e.handleRecipeMenu(session, cmd, "weapons")
 }
 
 func (e *Engine) handleVillageUpgradeSkill(session *GameSession, cmd GameCommand) GameResponse {
@@ -1057,73 +800,47 @@ func (e *Engine) handleVillageUpgradeConfirm(session *GameSession, cmd GameComma
 		return e.handleVillageUpgradeSkill(session, GameCommand{Type: "init"})
 	}
 
+	recipes := game.KnownRecipesOfType(player, "skill_upgrades")
 	if cmd.Type == "init" {
 		msgs := []GameMessage{
 			Msg(fmt.Sprintf("Upgrade %s", skill.Name), "system"),
-			Msg("Cost: Gold: 50, Iron: 25", "system"),
-			Msg("Effect: +5 Damage (or +5 Healing), -2 Resource Cost", "system"),
 		}
+		options := []MenuOption{}
+		for i, r := range recipes {
+			label := fmt.Sprintf("%s (%s) -> %s", r.Name, game.RecipeCostText(r), r.SkillUpgrade.Description)
+			if reason := game.RecipeLocked(player, village, r); reason != "" {
+				options = append(options, OptDisabled(strconv.Itoa(i+1), label+" ["+reason+"]"))
+			} else {
+				options = append(options, Opt(strconv.Itoa(i+1), label))
+			}
+		}
+		options = append(options, Opt("n", "No, cancel"))
 
 		session.State = StateVillageUpgradeConfirm
 		return GameResponse{
 			Type:     "menu",
 			Messages: msgs,
 			State:    &StateData{Screen: "village_upgrade_confirm", Player: MakePlayerState(player)},
-			Options: []MenuOption{
-				Opt("y", "Yes, upgrade"),
-				Opt("n", "No, cancel"),
-			},
+			Options:  options,
 		}
 	}
 
+	// "y" confirms the basic upgrade, the first on the list.
+	idx, err := strconv.Atoi(cmd.Value)
 	if cmd.Value == "y" {
-		iron := player.ResourceStorageMap["Iron"]
-		gold := player.ResourceStorageMap["Gold"]
-
-		if iron.Stock < 25 {
-			session.State = StateVillageUpgradeSkill
-			resp := e.handleVillageUpgradeSkill(session, GameCommand{Type: "init"})
-			resp.Messages = append([]GameMessage{Msg(fmt.Sprintf("Not enough Iron! Need 25, have %d", iron.Stock), "error")}, resp.Messages...)
-			return resp
-		}
-		if gold.Stock < 50 {
-			session.State = StateVillageUpgradeSkill
-			resp := e.handleVillageUpgradeSkill(session, GameCommand{Type: "init"})
-			resp.Messages = append([]GameMessage{Msg(fmt.Sprintf("Not enough Gold! Need 50, have %d", gold.Stock), "error")}, resp.Messages...)
+		idx, err = 1, nil
+	}
+	if err == nil && idx >= 1 && idx <= len(recipes) {
+		job, err := game.QueueCraft(player, village, recipes[idx-1], skillIdx)
+		session.State = StateVillageUpgradeSkill
+		resp := e.handleVillageUpgradeSkill(session, GameCommand{Type: "init"})
+		if err != nil {
+			resp.Messages = append([]GameMessage{Msg(err.Error(), "error")}, resp.Messages...)
 			return resp
 		}
 
-		iron.Stock -= 25
-		gold.Stock -= 50
-		player.ResourceStorageMap["Iron"] = iron
-		player.ResourceStorageMap["Gold"] = gold
-
-		resultMsgs := []GameMessage{}
-		if skill.Damage > 0 {
-			skill.Damage += 5
-			resultMsgs = append(resultMsgs, Msg(fmt.Sprintf("%s damage increased by 5! (Now: %d)", skill.Name, skill.Damage), "system"))
-		} else if skill.Damage < 0 {
-			skill.Damage -= 5
-			resultMsgs = append(resultMsgs, Msg(fmt.Sprintf("%s healing increased by 5! (Now: %d)", skill.Name, -skill.Damage), "system"))
-		}
-
-		if skill.ManaCost > 2 {
-			skill.ManaCost -= 2
-			resultMsgs = append(resultMsgs, Msg(fmt.Sprintf("Mana cost reduced by 2! (Now: %d)", skill.ManaCost), "system"))
-		}
-		if skill.StaminaCost > 2 {
-			skill.StaminaCost -= 2
-			resultMsgs = append(resultMsgs, Msg(fmt.Sprintf("Stamina cost reduced by 2! (Now: %d)", skill.StaminaCost), "system"))
-		}
-
-		village.Experience += 60
-		resultMsgs = append(resultMsgs, Msg("+60 Village XP", "system"))
-
 		e.saveVillage(session)
-
-		session.State = StateVillageUpgradeSkill
-		resp := e.handleVillageUpgradeSkill(session, GameCommand{Type: "init"})
-		resp.Messages = append(resultMsgs, resp.Messages...)
+		resp.Messages = append(jobQueuedMessages(village, job), resp.Messages...)
 		return resp
 	}
 
@@ -1132,123 +849,11 @@ func (e *Engine) handleVillageUpgradeConfirm(session *GameSession, cmd GameComma
 }
 
 func (e *Engine) handleVillageCraftScrolls(session *GameSession, cmd GameCommand) GameResponse {
-	village := session.SelectedVillage
-	player := session.Player
-
 	if cmd.Value == "0" || cmd.Value == "back" {
 		session.State = StateVillageCrafting
 		return e.handleVillageCrafting(session, GameCommand{Type: "init"})
 	}
-
-	type scrollRecipe struct {
-		skillIdx  int
-		materials map[string]int
-		xp        int
-	}
-
-	recipes := map[string]scrollRecipe{
-		"1":  {0, map[string]int{"Ore Fragment": 15, "Sharp Fang": 10, "Gold": 30}, 100},
-		"2":  {1, map[string]int{"Beast Skin": 12, "Ore Fragment": 10, "Iron": 20}, 90},
-		"3":  {2, map[string]int{"Ore Fragment": 20, "Monster Claw": 15, "Gold": 40}, 120},
-		"4":  {4, map[string]int{"Beast Bone": 10, "Iron": 15}, 70},
-		"5":  {7, map[string]int{"Beast Skin": 10, "Sharp Fang": 12, "Iron": 15}, 85},
-		"6":  {3, map[string]int{"Beast Skin": 15, "Beast Bone": 10, "Gold": 25}, 95},
-		"7":  {8, map[string]int{"Ore Fragment": 12, "Beast Skin": 15, "Gold": 30}, 105},
-		"8":  {5, map[string]int{"Tough Hide": 15, "Beast Bone": 12, "Stone": 20}, 90},
-		"9":  {6, map[string]int{"Sharp Fang": 15, "Beast Bone": 10, "Iron": 20}, 85},
-		"10": {9, map[string]int{"Beast Bone": 8, "Beast Skin": 8}, 60},
-	}
-
-	if cmd.Type != "init" {
-		if recipe, ok := recipes[cmd.Value]; ok {
-			skillToLearn := data.AvailableSkills[recipe.skillIdx]
-
-			// Check if already known
-			for _, skill := range player.LearnedSkills {
-				if skill.Name == skillToLearn.Name {
-					session.State = StateVillageCraftScrolls
-					resp := buildScrollCraftingResponse(session, nil)
-					resp.Messages = append([]GameMessage{Msg(fmt.Sprintf("You already know %s!", skillToLearn.Name), "error")}, resp.Messages...)
-					return resp
-				}
-			}
-
-			// Check resources
-			ok2, errMsg := checkAndDeductResources(player, recipe.materials)
-			if !ok2 {
-				session.State = StateVillageCraftScrolls
-				resp := buildScrollCraftingResponse(session, nil)
-				resp.Messages = append([]GameMessage{Msg(errMsg, "error")}, resp.Messages...)
-				return resp
-			}
-
-			player.LearnedSkills = append(player.LearnedSkills, skillToLearn)
-			village.Experience += recipe.xp
-
-			e.saveVillage(session)
-
-			extraMsgs := []GameMessage{
-				Msg(fmt.Sprintf("Crafted %s Scroll!", skillToLearn.Name), "loot"),
-				Msg(fmt.Sprintf("You have learned %s!", skillToLearn.Name), "system"),
-				Msg(fmt.Sprintf("   %s", skillToLearn.Description), "system"),
-				Msg(fmt.Sprintf("+%d Village XP", recipe.xp), "system"),
-			}
-			session.State = StateVillageCraftScrolls
-			return buildScrollCraftingResponse(session, extraMsgs)
-		}
-	}
-
-	session.State = StateVillageCraftScrolls
-	return buildScrollCraftingResponse(session, nil)
-}
-
-func buildScrollCraftingResponse(session *GameSession, extraMsgs []GameMessage) GameResponse {
-	msgs := append([]GameMessage{}, extraMsgs...)
-	msgs = append(msgs,
-		Msg("============================================================", "system"),
-		Msg("SKILL SCROLL CRAFTING", "system"),
-		Msg("============================================================", "system"),
-		Msg("", "system"),
-		Msg("Craft skill scrolls using beast materials!", "system"),
-		Msg("Learn skills without defeating Skill Guardians", "system"),
-		Msg("", "system"),
-		Msg("--- OFFENSIVE SKILLS ---", "system"),
-		Msg("1. Fireball Scroll (Ore Fragment: 15, Sharp Fang: 10, Gold: 30)", "system"),
-		Msg("2. Ice Shard Scroll (Beast Skin: 12, Ore Fragment: 10, Iron: 20)", "system"),
-		Msg("3. Lightning Bolt Scroll (Ore Fragment: 20, Monster Claw: 15, Gold: 40)", "system"),
-		Msg("4. Power Strike Scroll (Beast Bone: 10, Iron: 15)", "system"),
-		Msg("5. Poison Blade Scroll (Beast Skin: 10, Sharp Fang: 12, Iron: 15)", "system"),
-		Msg("", "system"),
-		Msg("--- SUPPORT SKILLS ---", "system"),
-		Msg("6. Heal Scroll (Beast Skin: 15, Beast Bone: 10, Gold: 25)", "system"),
-		Msg("7. Regeneration Scroll (Ore Fragment: 12, Beast Skin: 15, Gold: 30)", "system"),
-		Msg("8. Shield Wall Scroll (Tough Hide: 15, Beast Bone: 12, Stone: 20)", "system"),
-		Msg("9. Battle Cry Scroll (Sharp Fang: 15, Beast Bone: 10, Iron: 20)", "system"),
-		Msg("", "system"),
-		Msg("--- UTILITY SKILLS ---", "system"),
-		Msg("10. Tracking Scroll (Beast Bone: 8, Beast Skin: 8)", "system"),
-	)
-
-	options := []MenuOption{
-		Opt("1", "Fireball Scroll"),
-		Opt("2", "Ice Shard Scroll"),
-		Opt("3", "Lightning Bolt Scroll"),
-		Opt("4", "Power Strike Scroll"),
-		Opt("5", "Poison Blade Scroll"),
-		Opt("6", "Heal Scroll"),
-		Opt("7", "Regeneration Scroll"),
-		Opt("8", "Shield Wall Scroll"),
-		Opt("9", "Battle Cry Scroll"),
-		Opt("10", "Tracking Scroll"),
-		Opt("0", "Back"),
-	}
-
-	return GameResponse{
-		Type:     "menu",
-		Messages: msgs,
-		State:    &StateData{Screen: "village_craft_scrolls", Player: MakePlayerState(session.Player)},
-		Options:  options,
-	}
+	return e.handleRecipeMenu(session, cmd, "skill_scrolls")
 }
 
 func (e *Engine) handleVillageBuildDefense(session *GameSession, cmd GameCommand) GameResponse {
@@ -1300,77 +905,23 @@ func (e *Engine) handleVillageBuildWalls(session *GameSession, cmd GameCommand)
 		return e.handleVillageBuildDefense(session, GameCommand{Type: "init"})
 	}
 
-	type wallDef struct {
-		name    string
-		lumber  int
-		stone   int
-		iron    int
-		defense int
-		attack  int
-		dtype   string
-	}
-
-	defenseOptions := []wallDef{
-		{"Wooden Wall", 50, 20, 0, 10, 0, "wall"},
-		{"Stone Wall", 30, 60, 10, 25, 0, "wall"},
-		{"Iron Wall", 20, 80, 40, 40, 0, "wall"},
-		{"Guard Tower", 40, 40, 30, 15, 20, "tower"},
-		{"Arrow Tower", 30, 50, 40, 10, 35, "tower"},
-		{"Iron Gate", 20, 50, 50, 30, 10, "wall"},
-	}
-
 	if cmd.Type != "init" {
-		idx, err := strconv.Atoi(cmd.Value)
-		if err == nil && idx >= 1 && idx <= len(defenseOptions) {
-			selected := defenseOptions[idx-1]
-			materials := map[string]int{}
-			if selected.lumber > 0 {
-				materials["Lumber"] = selected.lumber
-			}
-			if selected.stone > 0 {
-				materials["Stone"] = selected.stone
-			}
-			if selected.iron > 0 {
-				materials["Iron"] = selected.iron
-			}
-
-			ok, errMsg := checkAndDeductResources(player, materials)
-			if !ok {
-				session.State = StateVillageBuildWalls
-				return GameResponse{
-					Type:     "menu",
-					Messages: []GameMessage{Msg(errMsg, "error")},
-					State:    &StateData{Screen: "village_build_walls", Player: MakePlayerState(player)},
-					Options:  []MenuOption{Opt("back", "Back")},
-				}
-			}
-
-			newDefense := models.Defense{
-				Name:        selected.name,
-				Level:       1,
-				Defense:     selected.defense,
-				AttackPower: selected.attack,
-				Range:       10,
-				Built:       true,
-				Type:        selected.dtype,
-			}
-			village.Defenses = append(village.Defenses, newDefense)
-			village.DefenseLevel++
-			village.Experience += 30
-
-			e.saveVillage(session)
-
-			extraMsgs := []GameMessage{
-				Msg(fmt.Sprintf("Built %s!", selected.name), "system"),
-				Msg(fmt.Sprintf("Village Defense Level increased to %d", village.DefenseLevel), "system"),
-				Msg("+30 Village XP", "system"),
+		var job models.VillageJob
+		err := fmt.Errorf("invalid choice")
+		if arg, ok := strings.CutPrefix(cmd.Value, "u:"); ok {
+			if idx, convErr := strconv.Atoi(arg); convErr == nil {
+				job, err = game.QueueDefenseUpgrade(player, village, idx)
 			}
+		} else if idx, convErr := strconv.Atoi(cmd.Value); convErr == nil && idx >= 1 && idx <= len(game.DefenseBlueprints) {
+			job, err = game.QueueDefense(player, village, game.DefenseBlueprints[idx-1])
+		}
 
-			session.State = StateVillageBuildWalls
-			resp := buildWallsResponse(session, nil)
-			resp.Messages = append(extraMsgs, resp.Messages...)
-			return resp
+		session.State = StateVillageBuildWalls
+		if err != nil {
+			return buildWallsResponse(session, []GameMessage{Msg(err.Error(), "error")})
 		}
+		e.saveVillage(session)
+		return buildWallsResponse(session, jobQueuedMessages(village, job))
 	}
 
 	session.State = StateVillageBuildWalls
@@ -1378,39 +929,40 @@ func (e *Engine) handleVillageBuildWalls(session *GameSession, cmd GameCommand)
 }
 
 func buildWallsResponse(session *GameSession, extraMsgs []GameMessage) GameResponse {
+	village := session.SelectedVillage
+
 	msgs := append([]GameMessage{}, extraMsgs...)
 	msgs = append(msgs,
 		Msg("============================================================", "system"),
 		Msg("BUILD WALLS & TOWERS", "system"),
 		Msg("============================================================", "system"),
+		Msg(fmt.Sprintf("Work queue: %d/%d jobs, building %d work/tick", len(village.Jobs), game.MaxVillageJobs,
+			game.TaskWork(village, game.TaskBuilding)), "system"),
 		Msg("", "system"),
 		Msg("Available Structures:", "system"),
 	)
 
-	type wallInfo struct {
-		name    string
-		lumber  int
-		stone   int
-		iron    int
-		defense int
-		attack  int
-	}
-
-	walls := []wallInfo{
-		{"Wooden Wall", 50, 20, 0, 10, 0},
-		{"Stone Wall", 30, 60, 10, 25, 0},
-		{"Iron Wall", 20, 80, 40, 40, 0},
-		{"Guard Tower", 40, 40, 30, 15, 20},
-		{"Arrow Tower", 30, 50, 40, 10, 35},
-		{"Iron Gate", 20, 50, 50, 30, 10},
-	}
-
 	options := []MenuOption{}
-	for i, w := range walls {
-		label := fmt.Sprintf("%s (Lumber:%d Stone:%d Iron:%d) -> Defense:+%d Attack:+%d",
-			w.name, w.lumber, w.stone, w.iron, w.defense, w.attack)
+	for i, bp := range game.DefenseBlueprints {
+		label := fmt.Sprintf("%s (%s) -> Defense:+%d Attack:+%d",
+			bp.Name, game.MaterialsText(bp.Cost), bp.Defense, bp.Attack)
 		options = append(options, Opt(strconv.Itoa(i+1), label))
 	}
+	for i, d := range village.Defenses {
+		bp, ok := game.FindDefenseBlueprint(d.Name)
+		if !ok {
+			continue
+		}
+		value := fmt.Sprintf("u:%d", i)
+		level := game.PlannedDefenseLevel(village, i)
+		if level >= game.MaxDefenseLevel {
+			options = append(options, OptDisabled(value, fmt.Sprintf("Upgrade %s (max level)", d.Name)))
+			continue
+		}
+		label := fmt.Sprintf("Upgrade %s to Lv%d (%s) -> Defense:+%d Attack:+%d",
+			d.Name, level+1, game.MaterialsText(game.DefenseUpgradeCost(bp, level)), bp.Defense/2, bp.Attack/2)
+		options = append(options, Opt(value, label))
+	}
 	options = append(options, Opt("0", "Back"))
 
 	return GameResponse{
@@ -1422,107 +974,11 @@ func buildWallsResponse(session *GameSession, extraMsgs []GameMessage) GameRespo
 }
 
 func (e *Engine) handleVillageCraftTraps(session *GameSession, cmd GameCommand) GameResponse {
-	village := session.SelectedVillage
-	player := session.Player
-
 	if cmd.Value == "0" || cmd.Value == "back" {
 		session.State = StateVillageBuildDefense
 		return e.handleVillageBuildDefense(session, GameCommand{Type: "init"})
 	}
-
-	type trapDef struct {
-		name        string
-		trapType    string
-		materials   map[string]int
-		damage      int
-		duration    int
-		triggerRate int
-	}
-
-	trapOptions := []trapDef{
-		{"Spike Trap", "spike", map[string]int{"Iron": 10, "Beast Bone": 5}, 15, 3, 60},
-		{"Fire Trap", "fire", map[string]int{"Iron": 15, "Ore Fragment": 8, "Sharp Fang": 5}, 25, 2, 50},
-		{"Ice Trap", "ice", map[string]int{"Iron": 12, "Ore Fragment": 10, "Beast Skin": 8}, 20, 3, 55},
-		{"Poison Trap", "poison", map[string]int{"Beast Skin": 10, "Sharp Fang": 8, "Monster Claw": 5}, 18, 4, 65},
-		{"Barricade Trap", "spike", map[string]int{"Lumber": 30, "Tough Hide": 6, "Beast Bone": 8}, 30, 2, 70},
-	}
-
-	if cmd.Type != "init" {
-		idx, err := strconv.Atoi(cmd.Value)
-		if err == nil && idx >= 1 && idx <= len(trapOptions) {
-			selected := trapOptions[idx-1]
-
-			ok, errMsg := checkAndDeductResources(player, selected.materials)
-			if !ok {
-				session.State = StateVillageCraftTraps
-				return GameResponse{
-					Type:     "menu",
-					Messages: []GameMessage{Msg(errMsg, "error")},
-					State:    &StateData{Screen: "village_craft_traps", Player: MakePlayerState(player)},
-					Options:  []MenuOption{Opt("back", "Back")},
-				}
-			}
-
-			newTrap := models.Trap{
-				Name:        selected.name,
-				Type:        selected.trapType,
-				Damage:      selected.damage,
-				Duration:    selected.duration,
-				Remaining:   selected.duration,
-				TriggerRate: selected.triggerRate,
-			}
-			village.Traps = append(village.Traps, newTrap)
-			village.Experience += 35
-
-			e.saveVillage(session)
-
-			extraMsgs := []GameMessage{
-				Msg(fmt.Sprintf("Crafted %s!", selected.name), "loot"),
-				Msg(fmt.Sprintf("Will last for %d monster tides", selected.duration), "system"),
-				Msg("+35 Village XP", "system"),
-			}
-			session.State = StateVillageCraftTraps
-			resp := buildTrapCraftingResponse(session, nil)
-			resp.Messages = append(extraMsgs, resp.Messages...)
-			return resp
-		}
-	}
-
-	session.State = StateVillageCraftTraps
-	return buildTrapCraftingResponse(session, nil)
-}
-
-func buildTrapCraftingResponse(session *GameSession, extraMsgs []GameMessage) GameResponse {
-	msgs := append([]GameMessage{}, extraMsgs...)
-	msgs = append(msgs,
-		Msg("============================================================", "system"),
-		Msg("CRAFT TRAPS", "system"),
-		Msg("============================================================", "system"),
-		Msg("", "system"),
-		Msg("Available Traps:", "system"),
-		Msg("", "system"),
-		Msg("1. Spike Trap (Iron:10, Beast Bone:5) Damage:15, Duration:3, Trigger:60%", "system"),
-		Msg("2. Fire Trap (Iron:15, Ore Fragment:8, Sharp Fang:5) Damage:25, Duration:2, Trigger:50%", "system"),
-		Msg("3. Ice Trap (Iron:12, Ore Fragment:10, Beast Skin:8) Damage:20, Duration:3, Trigger:55%", "system"),
-		Msg("4. Poison Trap (Beast Skin:10, Sharp Fang:8, Monster Claw:5) Damage:18, Duration:4, Trigger:65%", "system"),
-		Msg("5. Barricade Trap (Lumber:30, Tough Hide:6, Beast Bone:8) Damage:30, Duration:2, Trigger:70%", "system"),
-	)
-
-	options := []MenuOption{
-		Opt("1", "Spike Trap"),
-		Opt("2", "Fire Trap"),
-		Opt("3", "Ice Trap"),
-		Opt("4", "Poison Trap"),
-		Opt("5", "Barricade Trap"),
-		Opt("0", "Back"),
-	}
-
-	return GameResponse{
-		Type:     "menu",
-		Messages: msgs,
-		State:    &StateData{Screen: "village_craft_traps", Player: MakePlayerState(session.Player)},
-		Options:  options,
-	}
+	return e.handleRecipeMenu(session, cmd, "traps")
 }
 
 func (e *Engine) handleVillageViewDefenses(session *GameSession, cmd GameCommand) GameResponse {
@@ -1612,6 +1068,7 @@ func (e *Engine) enrichVillageWithTideLeader(resp *GameResponse) {
 		Defeated:        leader.Defeated,
 		Participants:    len(leader.RaidParticipants),
 		RaidActive:      cal.IsRaidPhase(),
+		GuildDamage:     makeSiegeCounts(leader.GuildDamage),
 	}
 }
 
@@ -1655,6 +1112,12 @@ func (e *Engine) handleVillageCheckTide(session *GameSession, cmd GameCommand) G
 				}
 				msgs = append(msgs, Msg(fmt.Sprintf("Villages raiding: %d", len(leader.RaidParticipants)), "system"))
 			}
+			for i, c := range game.SiegeTally(leader.GuildDamage) {
+				if i == 3 {
+					break
+				}
+				msgs = append(msgs, Msg(fmt.Sprintf("  Guild %s: %d damage", c.Name, c.Count), "system"))
+			}
 		}
 	}
 
@@ -1673,6 +1136,21 @@ func (e *Engine) handleVillageCheckTide(session *GameSession, cmd GameCommand) G
 		msgs = append(msgs, Msg(fmt.Sprintf("Next Monster Tide in: %d hours, %d minutes", hours, minutes), "system"))
 	}
 
+	if report := village.LastSiege; report != nil {
+		msgs = append(msgs, Msg("", "system"), Msg("LAST SIEGE", "system"))
+		for _, line := range game.SiegeSummary(report) {
+			msgs = append(msgs, Msg(line, "narrative"))
+		}
+		for _, wave := range report.Waves {
+			msgs = append(msgs, Msg(fmt.Sprintf("-- Wave %d: %d/%d killed, %d breached in %d ticks --",
+				wave.Number, wave.Killed, wave.Monsters, wave.Breached, wave.Ticks), "system"))
+			for _, ev := range wave.Events {
+				msgs = append(msgs, Msg(game.SiegeEventLine(ev), siegeEventCategory(ev)))
+			}
+		}
+		msgs = append(msgs, Msg("", "system"))
+	}
+
 	msgs = append(msgs,
 		Msg(fmt.Sprintf("Village Defense Level: %d", village.DefenseLevel), "system"),
 		Msg(fmt.Sprintf("Defenses: %d built", len(village.Defenses)), "system"),
@@ -1698,6 +1176,21 @@ func (e *Engine) handleVillageCheckTide(session *GameSession, cmd GameCommand) G
 	}
 }
 
+// siegeEventCategory is the log category a siege event is shown with.
+func siegeEventCategory(ev models.SiegeEvent) string {
+	switch {
+	case ev.Killed:
+		return "loot"
+	case ev.Kind == "breach":
+		return "damage"
+	case ev.Kind == "wound" || ev.Kind == "wall":
+		return "debuff"
+	case ev.Kind == "retreat":
+		return "system"
+	}
+	return "combat"
+}
+
 func (e *Engine) handleVillageMonsterTide(session *GameSession, cmd GameCommand) GameResponse {
 	village := session.SelectedVillage
 	player := session.Player
@@ -1797,10 +1290,14 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 
 	msgs = append(msgs, Msg(fmt.Sprintf("%d monsters approach!", waveSize), "combat"))
 
+	// Guard skill cooldowns count monsters, one per monster of the wave
+	guardSkills := make([]game.SkillTimers, len(village.ActiveGuards))
+
 	waveDamageDealt := 0
 	waveDamageTaken := 0
 	monstersKilled := 0
 	trapsTriggered := 0
+	breaches := 0
 
 	for i := 0; i < waveSize; i++ {
 		monsterLevel := baseMonsterLevel + rand.Intn(5) - 2
@@ -1847,19 +1344,29 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 			}
 		}
 
-		// Phase 3: Guards
-		if totalGuards > 0 {
-			guardDamage := totalGuards * (5 + rand.Intn(8))
+		// Phase 3: Guards - villagers on guard duty fight as a group, hired
+		// guards each take their own turn
+		if villagerGuards > 0 {
+			guardDamage := villagerGuards * (5 + rand.Intn(8))
 			monster.HitpointsRemaining -= guardDamage
 			waveDamageDealt += guardDamage
-			msgs = append(msgs, Msg(fmt.Sprintf("    Guards attack! (%d damage)", guardDamage), "damage"))
-
+			msgs = append(msgs, Msg(fmt.Sprintf("    Villager guards attack! (%d damage)", guardDamage), "damage"))
+		}
+		for g := range village.ActiveGuards {
 			if monster.HitpointsRemaining <= 0 {
-				msgs = append(msgs, Msg(fmt.Sprintf("    %s killed by guards!", monster.Name), "combat"))
-				monstersKilled++
-				continue
+				break
+			}
+			act := game.GuardTurn(&village.ActiveGuards[g], []interface{}{&monster}, nil, &guardSkills[g], i)
+			waveDamageDealt += act.Damage
+			for _, m := range act.Messages {
+				msgs = append(msgs, Msg("    "+m.Text, m.Kind))
 			}
 		}
+		if totalGuards > 0 && monster.HitpointsRemaining <= 0 {
+			msgs = append(msgs, Msg(fmt.Sprintf("    %s killed by guards!", monster.Name), "combat"))
+			monstersKilled++
+			continue
+		}
 
 		// Phase 4: Monster attacks village
 		monsterAttack := monster.AttackRolls * 6
@@ -1868,8 +1375,12 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 			reducedDamage = 1
 		}
 		waveDamageTaken += reducedDamage
+		breaches++
 		msgs = append(msgs, Msg(fmt.Sprintf("    %s breaches defenses! (%d damage to village)", monster.Name, reducedDamage), "damage"))
 	}
+	for _, line := range game.TideCasualties(village, breaches) {
+		msgs = append(msgs, Msg("    "+line, "damage"))
+	}
 
 	// Update running totals
 	session.Combat.AutoPlayWins += monstersKilled
@@ -1894,6 +1405,13 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 		}
 	}
 
+	// Guard equipment wears down each wave
+	for i := range village.ActiveGuards {
+		for _, name := range game.WearGuardEquipment(&village.ActiveGuards[i], game.TideWear) {
+			msgs = append(msgs, Msg(fmt.Sprintf("  %s's %s has broken!", village.ActiveGuards[i].Name, name), "system"))
+		}
+	}
+
 	// Check if more waves remain
 	if currentWave < numWaves {
 		session.State = StateVillageTideWave
@@ -1916,7 +1434,8 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 		Msg("============================================================", "system"),
 	)
 
-	if totalDamageTaken < damageThreshold {
+	victory := totalDamageTaken < damageThreshold
+	if victory {
 		// Victory
 		xpReward := 100 * numWaves
 		village.Experience += xpReward
@@ -1938,9 +1457,7 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 
 		if totalDamageTaken < damageThreshold/2 {
 			bonusGold := 50 + (village.Level * 10)
-			goldResource := player.ResourceStorageMap["Gold"]
-			goldResource.Stock += bonusGold
-			player.ResourceStorageMap["Gold"] = goldResource
+			game.AdjustGold(player, bonusGold, game.ReasonTideVictory, "village:"+village.Name)
 			msgs = append(msgs, Msg(fmt.Sprintf("  Bonus Gold: +%d (minimal damage taken!)", bonusGold), "loot"))
 		}
 	} else {
@@ -1963,12 +1480,7 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 		)
 
 		for _, resourceType := range data.ResourceTypes {
-			resource := player.ResourceStorageMap[resourceType]
-			resource.Stock -= resourceLoss
-			if resource.Stock < 0 {
-				resource.Stock = 0
-			}
-			player.ResourceStorageMap[resourceType] = resource
+			game.AdjustResource(player, resourceType, -resourceLoss, game.ReasonTideLoss, "village:"+village.Name)
 		}
 
 		if len(village.ActiveGuards) > 0 {
@@ -1980,6 +1492,9 @@ func (e *Engine) handleVillageTideWave(session *GameSession, cmd GameCommand) Ga
 			msgs = append(msgs, Msg(fmt.Sprintf("  %d hired guards were lost", guardsLost), "system"))
 		}
 	}
+	for _, line := range game.VillagersAfterTide(village, victory) {
+		msgs = append(msgs, Msg(line, "system"))
+	}
 
 	// Update last tide time
 	village.LastTideTime = time.Now().Unix()
@@ -2110,6 +1625,9 @@ func (e *Engine) handleVillageManageGuard(session *GameSession, cmd GameCommand)
 	case "5":
 		session.State = StateVillageHealGuard
 		return e.handleVillageHealGuard(session, GameCommand{Type: "init"})
+	case "6":
+		session.State = StateVillageGuardSkill
+		return e.handleVillageGuardSkill(session, GameCommand{Type: "init"})
 	}
 
 	// Show guard details
@@ -2132,6 +1650,12 @@ func (e *Engine) handleVillageManageGuard(session *GameSession, cmd GameCommand)
 			guard.DefenseRolls, guard.DefenseBonus+guard.StatsMod.DefenseMod,
 			guard.DefenseRolls*6+guard.DefenseBonus+guard.StatsMod.DefenseMod), "system"),
 	}
+	msgs = append(msgs, Msg(fmt.Sprintf("Speed: %d | XP: %d/%d", game.Speed(guard), guard.Experience, game.GuardXPToLevel(guard)), "system"))
+	if guard.Skill != nil {
+		msgs = append(msgs, Msg(fmt.Sprintf("Skill: %s - %s", guard.Skill.Name, guard.Skill.Description), "system"))
+	} else {
+		msgs = append(msgs, Msg("Skill: none (teach one from a skill scroll)", "system"))
+	}
 
 	// Equipment
 	msgs = append(msgs, Msg("", "system"))
@@ -2184,6 +1708,7 @@ func (e *Engine) handleVillageManageGuard(session *GameSession, cmd GameCommand)
 		Opt("3", "Give Item from Player"),
 		Opt("4", "Take Item to Player"),
 		Opt("5", "Heal Guard (costs 1 health potion)"),
+		Opt("6", "Teach Skill from Scroll"),
 		Opt("0", "Back"),
 	}
 
@@ -2383,6 +1908,7 @@ func (e *Engine) handleVillageGiveItem(session *GameSession, cmd GameCommand) Ga
 
 			guard.Inventory = append(guard.Inventory, item)
 			game.RemoveItemFromInventory(&player.Inventory, originalIdx)
+			game.RecordItemChange(player, item.Name, -1, game.ReasonGuardGift, "guard:"+guard.Name)
 
 			e.saveVillage(session)
 
@@ -2439,9 +1965,16 @@ func (e *Engine) handleVillageTakeItem(session *GameSession, cmd GameCommand) Ga
 		idx, err := strconv.Atoi(cmd.Value)
 		if err == nil && idx >= 1 && idx <= len(guard.Inventory) {
 			item := guard.Inventory[idx-1]
+			if !game.HasRoomFor(player.Inventory, game.InventoryCapacity(player), item) {
+				session.State = StateVillageManageGuard
+				resp := e.handleVillageManageGuard(session, GameCommand{Type: "init"})
+				resp.Messages = append([]GameMessage{Msg("Your inventory is full!", "error")}, resp.Messages...)
+				return resp
+			}
 
 			player.Inventory = append(player.Inventory, item)
 			game.RemoveItemFromInventory(&guard.Inventory, idx-1)
+			game.RecordItemChange(player, item.Name, 1, game.ReasonGuardGift, "guard:"+guard.Name)
 
 			e.saveVillage(session)
 
@@ -2544,6 +2077,7 @@ func (e *Engine) handleVillageHealGuard(session *GameSession, cmd GameCommand) G
 			guard.RecoveryTime = 0
 		}
 
+		game.RecordItemChange(player, player.Inventory[potionIdx].Name, -1, game.ReasonItemUsed, "guard:"+guard.Name)
 		game.RemoveItemFromInventory(&player.Inventory, potionIdx)
 
 		e.saveVillage(session)
@@ -2566,6 +2100,75 @@ func (e *Engine) handleVillageHealGuard(session *GameSession, cmd GameCommand) G
 	return e.handleVillageManageGuard(session, GameCommand{Type: "init"})
 }
 
+// handleVillageGuardSkill teaches the selected guard the skill on one of the
+// player's skill scrolls, using up the scroll.
+func (e *Engine) handleVillageGuardSkill(session *GameSession, cmd GameCommand) GameResponse {
+	village := session.SelectedVillage
+	player := session.Player
+	guardIdx := session.SelectedGuardIdx
+
+	if guardIdx < 0 || guardIdx >= len(village.ActiveGuards) || cmd.Value == "0" || cmd.Value == "back" {
+		session.State = StateVillageManageGuard
+		return e.handleVillageManageGuard(session, GameCommand{Type: "init"})
+	}
+
+	guard := &village.ActiveGuards[guardIdx]
+
+	scrollIndices := []int{}
+	for i, item := range player.Inventory {
+		if item.ItemType == "skill_scroll" {
+			scrollIndices = append(scrollIndices, i)
+		}
+	}
+	if len(scrollIndices) == 0 {
+		session.State = StateVillageManageGuard
+		resp := e.handleVillageManageGuard(session, GameCommand{Type: "init"})
+		resp.Messages = append([]GameMessage{Msg("You don't have any skill scrolls!", "error")}, resp.Messages...)
+		return resp
+	}
+
+	if cmd.Type != "init" {
+		idx, err := strconv.Atoi(cmd.Value)
+		if err == nil && idx >= 1 && idx <= len(scrollIndices) {
+			invIdx := scrollIndices[idx-1]
+			scroll := player.Inventory[invIdx]
+			if err := game.GuardLearnSkill(guard, scroll); err != nil {
+				session.State = StateVillageManageGuard
+				resp := e.handleVillageManageGuard(session, GameCommand{Type: "init"})
+				resp.Messages = append([]GameMessage{Msg(err.Error(), "error")}, resp.Messages...)
+				return resp
+			}
+			game.RecordItemChange(player, scroll.Name, -1, game.ReasonItemUsed, "guard:"+guard.Name)
+			game.RemoveItemFromInventory(&player.Inventory, invIdx)
+			e.saveVillage(session)
+
+			session.State = StateVillageManageGuard
+			resp := e.handleVillageManageGuard(session, GameCommand{Type: "init"})
+			resp.Messages = append([]GameMessage{Msg(fmt.Sprintf("%s learned %s!", guard.Name, guard.Skill.Name), "levelup")}, resp.Messages...)
+			return resp
+		}
+	}
+
+	msgs := []GameMessage{Msg(fmt.Sprintf("Teach %s a skill (the scroll is used up):", guard.Name), "system")}
+	if guard.Skill != nil {
+		msgs = append(msgs, Msg(fmt.Sprintf("This replaces %s.", guard.Skill.Name), "system"))
+	}
+	options := []MenuOption{}
+	for i, invIdx := range scrollIndices {
+		skill := player.Inventory[invIdx].SkillScroll.Skill
+		options = append(options, Opt(strconv.Itoa(i+1), fmt.Sprintf("%s | %s", skill.Name, skill.Description)))
+	}
+	options = append(options, Opt("0", "Cancel"))
+
+	session.State = StateVillageGuardSkill
+	return GameResponse{
+		Type:     "menu",
+		Messages: msgs,
+		State:    &StateData{Screen: "village_guard_skill", Player: MakePlayerState(player)},
+		Options:  options,
+	}
+}
+
 // handleVillageFortifications lets the player craft village defense items using resources.
 func (e *Engine) handleVillageFortifications(session *GameSession, cmd GameCommand) GameResponse {
 	village := session.SelectedVillage
@@ -2610,9 +2213,7 @@ func (e *Engine) handleVillageFortifications(session *GameSession, cmd GameComma
 			}
 		}
 		for res, amount := range recipe.Cost {
-			r := player.ResourceStorageMap[res]
-			r.Stock -= amount
-			player.ResourceStorageMap[res] = r
+			game.AdjustResource(player, res, -amount, game.ReasonBuilding, "defense:"+recipe.Name)
 		}
 		village.Defenses = append(village.Defenses, models.Defense{
 			Name:    recipe.Name,
@@ -2692,12 +2293,10 @@ func (e *Engine) handleVillageTraining(session *GameSession, cmd GameCommand) Ga
 				Options:  []MenuOption{Opt("back", "Back")},
 			}
 		}
+		v := &village.Villagers[idx-1]
 		for res, amount := range trainingCost {
-			r := player.ResourceStorageMap[res]
-			r.Stock -= amount
-			player.ResourceStorageMap[res] = r
+			game.AdjustResource(player, res, -amount, game.ReasonTraining, "villager:"+v.Name)
 		}
-		v := &village.Villagers[idx-1]
 		v.Level++
 		v.Efficiency++
 		e.saveVillage(session)
@@ -2760,8 +2359,7 @@ func (e *Engine) handleVillageHealing(session *GameSession, cmd GameCommand) Gam
 				Options:  []MenuOption{Opt("back", "Back")},
 			}
 		}
-		r.Stock -= cost
-		player.ResourceStorageMap["Gold"] = r
+		game.AdjustGold(player, -cost, game.ReasonHealing, "village:"+village.Name)
 		player.HitpointsRemaining = player.HitpointsTotal
 		e.saveVillage(session)
 		msgs := []GameMessage{Msg(fmt.Sprintf("HP fully restored! (%d/%d)", player.HitpointsRemaining, player.HitpointsTotal), "heal")}
@@ -2783,8 +2381,7 @@ func (e *Engine) handleVillageHealing(session *GameSession, cmd GameCommand) Gam
 				Options:  []MenuOption{Opt("back", "Back")},
 			}
 		}
-		r.Stock -= cost
-		player.ResourceStorageMap["Gold"] = r
+		game.AdjustGold(player, -cost, game.ReasonHealing, "village:"+village.Name)
 		player.ManaRemaining = player.ManaTotal
 		e.saveVillage(session)
 		msgs := []GameMessage{Msg(fmt.Sprintf("MP fully restored! (%d/%d)", player.ManaRemaining, player.ManaTotal), "heal")}
@@ -2806,8 +2403,7 @@ func (e *Engine) handleVillageHealing(session *GameSession, cmd GameCommand) Gam
 				Options:  []MenuOption{Opt("back", "Back")},
 			}
 		}
-		r.Stock -= cost
-		player.ResourceStorageMap["Gold"] = r
+		game.AdjustGold(player, -cost, game.ReasonHealing, "village:"+village.Name)
 		player.StaminaRemaining = player.StaminaTotal
 		e.saveVillage(session)
 		msgs := []GameMessage{Msg(fmt.Sprintf("SP fully restored! (%d/%d)", player.StaminaRemaining, player.StaminaTotal), "heal")}
@@ -2829,8 +2425,7 @@ func (e *Engine) handleVillageHealing(session *GameSession, cmd GameCommand) Gam
 				Options:  []MenuOption{Opt("back", "Back")},
 			}
 		}
-		r.Stock -= cost
-		player.ResourceStorageMap["Gold"] = r
+		game.AdjustGold(player, -cost, game.ReasonHealing, "village:"+village.Name)
 		player.HitpointsRemaining = player.HitpointsTotal
 		player.ManaRemaining = player.ManaTotal
 		player.StaminaRemaining = player.StaminaTotal
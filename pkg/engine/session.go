@@ -1,6 +1,9 @@
 package engine
 
-import "rpg-game/pkg/models"
+import (
+	"rpg-game/pkg/game"
+	"rpg-game/pkg/models"
+)
 
 // Session states
 const (
@@ -12,6 +15,17 @@ const (
 	StateHuntLocationSelect = "hunt_location_select"
 	StateHuntTracking       = "hunt_tracking"
 
+	StateCharacterClassSelect = "character_class_select"
+	StateTalents              = "talents"
+	StateAttributes           = "attributes"
+	StateBlacksmith           = "blacksmith"
+	StateInventory            = "inventory"
+	StateVillageStash         = "village_stash"
+	StateEnchanting           = "enchanting"
+	StateVillageJobs          = "village_jobs"
+	StateVillageBuildings     = "village_buildings"
+	StateVillageRaids         = "village_raids"
+
 	StateCombat            = "combat"
 	StateCombatItemSelect  = "combat_item_select"
 	StateCombatSkillSelect = "combat_skill_select"
@@ -26,7 +40,6 @@ const (
 	StateDiscoveredLocations = "discovered_locations"
 	StateLoadSave            = "load_save"
 	StateLoadSaveCharSelect  = "load_save_char_select"
-	StateBuildSelect         = "build_select"
 
 	StateVillageMain             = "village_main"
 	StateVillageViewVillagers    = "village_view_villagers"
@@ -55,6 +68,7 @@ const (
 	StateVillageGiveItem         = "village_give_item"
 	StateVillageTakeItem         = "village_take_item"
 	StateVillageHealGuard        = "village_heal_guard"
+	StateVillageGuardSkill       = "village_guard_skill"
 	StateVillageFortifications   = "village_fortifications"
 	StateVillageTraining         = "village_training"
 	StateVillageHealing          = "village_healing"
@@ -104,6 +118,15 @@ const (
 	StateArenaChallenge = "arena_challenge"
 	StateArenaConfirm   = "arena_confirm"
 
+	// Guild states
+	StateGuild           = "guild"
+	StateGuildCreate     = "guild_create"
+	StateGuildInvite     = "guild_invite"
+	StateGuildRoster     = "guild_roster"
+	StateGuildChat       = "guild_chat"
+	StateGuildBank       = "guild_bank"
+	StateGuildBankAmount = "guild_bank_amount"
+
 	// Dungeon states
 	StateDungeonSelect    = "dungeon_select"
 	StateDungeonFloorMap  = "dungeon_floor_map"
@@ -124,10 +147,19 @@ type CombatContext struct {
 	Location       *models.Location
 	Turn           int
 	Fled           bool
+	MobFled        bool // the monster ran away
 	PlayerWon      bool
 	IsDefending    bool
+	PlayerSkills   game.SkillTimers // cooldowns, charging skill and combo chain
+	MobSkills      game.SkillTimers
+	Boss           *game.BossFight // scripted boss encounter, set on the boss's first turn
+	Queue          game.TurnQueue  // initiative gauges deciding who acts next
+	QueueStarted   bool            // the opening turns before the player's first action have been played
 	CombatGuards   []models.Guard
 	HasGuards      bool
+	GuardSkills    []game.SkillTimers // per CombatGuards entry
+	EnemyGuards    []models.Guard     // guards fighting for the monster (inn PvP)
+	EnemySkills    []game.SkillTimers // per EnemyGuards entry
 	GuardianLocationName string // non-empty = fighting a location guardian
 	ContinuousHunt       bool // true = keep hunting until player stops
 	WavesTotal           int  // village defense wave count
@@ -169,6 +201,12 @@ type GameSession struct {
 	SelectedGuardIdx    int
 	SelectedSkillIdx    int
 	SelectedVillagerIdx int
+	InventoryFilter     string // item type shown on the inventory screen
+	EnchantTarget       string // "p:<slot>" or "g:<guard>:<slot>" at the enchanting station
+	AtTrainingHall      bool   // the attribute screen was opened at the Training Hall, which sells respecs
+
+	// Character creation context
+	PendingCharacterName string
 
 	// Town context
 	SelectedTown         *models.Town
@@ -183,4 +221,7 @@ type GameSession struct {
 	// Arena context
 	ArenaTargetAccountID int64
 	ArenaTargetCharName  string
+
+	// Guild context
+	GuildBankAction string // "deposit:<resource>" or "withdraw:<resource>" awaiting an amount
 }
@@ -44,9 +44,10 @@ type MenuOption struct {
 
 // OnlinePlayer represents another player currently online.
 type OnlinePlayer struct {
-	Name     string `json:"name"`
-	Level    int    `json:"level"`
-	Activity string `json:"activity"`
+	AccountID int64  `json:"-"`
+	Name      string `json:"name"`
+	Level     int    `json:"level"`
+	Activity  string `json:"activity"`
 }
 
 // StateData contains current visible game state for UI rendering.
@@ -57,9 +58,30 @@ type StateData struct {
 	Village       *VillageView   `json:"village,omitempty"`
 	Town          *TownView      `json:"town,omitempty"`
 	Dungeon       *DungeonView   `json:"dungeon,omitempty"`
+	Guild         *GuildView     `json:"guild,omitempty"`
 	OnlinePlayers []OnlinePlayer `json:"online_players,omitempty"`
 }
 
+// GuildView represents the player's guild for the frontend.
+type GuildView struct {
+	Name         string            `json:"name"`
+	Rank         string            `json:"rank"` // the player's
+	TideDamage   int               `json:"tide_damage"`
+	Members      []GuildMemberView `json:"members"`
+	Resources    map[string]int    `json:"resources"`
+	Items        []string          `json:"items"`
+	Chat         []string          `json:"chat"`          // oldest first
+	WithdrawLeft int               `json:"withdraw_left"` // resources the player may still take today; -1 unlimited
+}
+
+// GuildMemberView is one row of a guild roster.
+type GuildMemberView struct {
+	Name     string `json:"name"`
+	Rank     string `json:"rank"`
+	Online   bool   `json:"online"`
+	Activity string `json:"activity,omitempty"`
+}
+
 // DungeonView represents dungeon state for the frontend.
 type DungeonView struct {
 	Name         string            `json:"name"`
@@ -116,18 +138,38 @@ type ItemView struct {
 	HitPoint  int    `json:"hitpoint"`
 	HealValue int    `json:"heal_value,omitempty"`
 	SkillName string `json:"skill_name,omitempty"`
+
+	Attributes *models.Attributes `json:"attributes,omitempty"`
+	Affixes    []string           `json:"affixes,omitempty"` // affix and set bonus descriptions
+	Unique     bool               `json:"unique,omitempty"`
+	Set        string             `json:"set,omitempty"` // set name
+
+	Durability    int  `json:"durability,omitempty"`
+	MaxDurability int  `json:"max_durability,omitempty"`
+	Broken        bool `json:"broken,omitempty"`
+
+	Sockets       int `json:"sockets,omitempty"`
+	FilledSockets int `json:"filled_sockets,omitempty"`
+
+	Quantity int `json:"quantity"` // stack size, at least 1
 }
 
 // SkillView represents a skill for the frontend.
 type SkillView struct {
-	Name        string `json:"name"`
-	ManaCost    int    `json:"mana_cost"`
-	StaminaCost int    `json:"stamina_cost"`
-	Damage      int    `json:"damage"`
-	DamageType  string `json:"damage_type"`
-	Effect      string `json:"effect"`
-	Duration    int    `json:"duration,omitempty"`
-	Description string `json:"description"`
+	Name         string `json:"name"`
+	ManaCost     int    `json:"mana_cost"`
+	StaminaCost  int    `json:"stamina_cost"`
+	Damage       int    `json:"damage"`
+	DamageType   string `json:"damage_type"`
+	Effect       string `json:"effect"`
+	Duration     int    `json:"duration,omitempty"`
+	Description  string `json:"description"`
+	Rank         int    `json:"rank"`
+	RankXP       int    `json:"rank_xp"`
+	NextRankXP   int    `json:"next_rank_xp,omitempty"` // 0 at the top rank
+	Cooldown     int    `json:"cooldown,omitempty"`
+	ChargeTurns  int    `json:"charge_turns,omitempty"`
+	CooldownLeft int    `json:"cooldown_left,omitempty"` // combat views only: turns until ready
 }
 
 // LocationView represents a location for the frontend.
@@ -160,6 +202,8 @@ type TideLeaderView struct {
 	Defeated        bool   `json:"defeated"`
 	Participants    int    `json:"participants"`
 	RaidActive      bool   `json:"raid_active"`
+
+	GuildDamage []SiegeCountView `json:"guild_damage,omitempty"` // this cycle's damage by guild, largest first
 }
 
 // VillageView represents village state for the frontend.
@@ -177,6 +221,59 @@ type VillageView struct {
 	ResourcePerTick  map[string]int      `json:"resource_per_tick"`
 	LastHarvestTime  int64               `json:"last_harvest_time"`
 	TideLeader       *TideLeaderView     `json:"tide_leader,omitempty"`
+
+	Stash         []ItemView `json:"stash"`
+	StashCapacity int        `json:"stash_capacity"`
+
+	FoodStock   int `json:"food_stock"`
+	FoodStorage int `json:"food_storage"`
+	FoodUpkeep  int `json:"food_upkeep"` // eaten per manager tick; production is resource_per_tick["Food"]
+	Housing     int `json:"housing"`
+
+	Buildings     []BuildingView `json:"buildings"`
+	GuardCapacity int            `json:"guard_capacity"`
+
+	LastSiege *SiegeView `json:"last_siege,omitempty"`
+
+	ShieldUntil int64    `json:"shield_until,omitempty"`
+	RaidLog     []string `json:"raid_log"` // newest first
+}
+
+// SiegeView is the report of a village's last auto-tide for the frontend.
+type SiegeView struct {
+	Time         int64            `json:"time"`
+	Victory      bool             `json:"victory"`
+	BreachDamage int              `json:"breach_damage"`
+	Threshold    int              `json:"threshold"`
+	Kills        []SiegeCountView `json:"kills"`
+	WallDamage   []SiegeCountView `json:"wall_damage"`
+	WallsBroken  []string         `json:"walls_broken"`
+	Waves        []SiegeWaveView  `json:"waves"`
+}
+
+// SiegeCountView is one entry of a siege tally.
+type SiegeCountView struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// SiegeWaveView is one wave of a siege. Timeline holds its first events.
+type SiegeWaveView struct {
+	Number   int      `json:"number"`
+	Monsters int      `json:"monsters"`
+	Killed   int      `json:"killed"`
+	Breached int      `json:"breached"`
+	Ticks    int      `json:"ticks"`
+	Timeline []string `json:"timeline"`
+	More     int      `json:"more,omitempty"` // events left out of Timeline
+}
+
+// BuildingView represents a village building for the frontend.
+type BuildingView struct {
+	Name   string         `json:"name"`
+	Level  int            `json:"level"`
+	Upkeep map[string]int `json:"upkeep"`
+	Idle   bool           `json:"idle,omitempty"` // upkeep unpaid
 }
 
 // VillagerView represents a villager for the frontend.
@@ -187,6 +284,13 @@ type VillagerView struct {
 	Efficiency   int    `json:"efficiency"`
 	AssignedTask string `json:"assigned_task"`
 	HarvestType  string `json:"harvest_type"`
+	Trait        string `json:"trait,omitempty"`
+	XP           int    `json:"xp"`
+	XPToLevel    int    `json:"xp_to_level"`
+	Morale       int    `json:"morale"`
+	Mood         string `json:"mood"`
+	InjuredTicks int    `json:"injured_ticks,omitempty"`
+	Output       int    `json:"output"` // harvest or job work per tick
 }
 
 // VillageGuardView represents a guard for the frontend.
@@ -201,6 +305,8 @@ type VillageGuardView struct {
 	Cost         int    `json:"cost"`
 	Injured      bool   `json:"injured"`
 	RecoveryTime int    `json:"recovery_time"`
+	Experience   int    `json:"experience"`
+	Skill        string `json:"skill,omitempty"`
 }
 
 // DefenseView represents a defense structure for the frontend.
@@ -258,7 +364,19 @@ type PlayerState struct {
 	HitPointMod   int    `json:"hitpoint_mod"`
 	Resurrections int    `json:"resurrections"`
 
+	Class        string         `json:"class,omitempty"`
+	Talents      map[string]int `json:"talents,omitempty"`
+	TalentPoints int            `json:"talent_points"`
+
+	Attributes      models.Attributes `json:"attributes"`       // allocated points
+	TotalAttributes models.Attributes `json:"total_attributes"` // including item bonuses
+	AttributePoints int               `json:"attribute_points"`
+
+	ItemEffects map[string]int `json:"item_effects,omitempty"` // lifesteal, thorns, elemental damage, ...
+	SetBonuses  []string       `json:"set_bonuses,omitempty"`
+
 	Inventory       []ItemView          `json:"inventory"`
+	InventorySlots  int                 `json:"inventory_slots"` // capacity
 	Equipment       map[string]ItemView `json:"equipment"`
 	Skills          []SkillView         `json:"skills"`
 	Resources       map[string]int      `json:"resources"`
@@ -267,7 +385,6 @@ type PlayerState struct {
 	ActiveQuests    []QuestView         `json:"active_quests"`
 	CompletedQuests []QuestView         `json:"completed_quests"`
 	VillageName     string              `json:"village_name"`
-	Buildings       []string            `json:"buildings"`
 	Stats           *StatsView          `json:"stats,omitempty"`
 	ActiveNPCQuests []NPCQuestView      `json:"active_npc_quests"`
 }
@@ -294,10 +411,23 @@ type CombatView struct {
 	MonsterEffects    []EffectView `json:"monster_effects"`
 	MonsterRarity     string       `json:"monster_rarity"`
 	MonsterIsBoss     bool         `json:"monster_is_boss"`
+	MonsterBehavior   string       `json:"monster_behavior"` // AI profile name
+	BossPhase         string       `json:"boss_phase,omitempty"`     // current phase of a scripted boss
+	BossPhaseNum      int          `json:"boss_phase_num,omitempty"` // 1-based
+	BossPhases        int          `json:"boss_phases,omitempty"`
+	BossWindUp        string       `json:"boss_wind_up,omitempty"`   // telegraphed attack landing next turn
+	BossAdds          int          `json:"boss_adds,omitempty"`      // summoned adds still standing
+	BossEnrageIn      int          `json:"boss_enrage_in,omitempty"` // turns until enrage
 	MonsterIsGuardian bool         `json:"monster_is_guardian"`
 	GuardedSkillName  string       `json:"guarded_skill_name,omitempty"`
 	Guards            []GuardView  `json:"guards,omitempty"`
+	EnemyGuards       []GuardView  `json:"enemy_guards,omitempty"` // guards fighting for the monster
 	ContinuousHunt    bool         `json:"continuous_hunt"`
+	Skills            []SkillView  `json:"skills,omitempty"`   // with cooldown state
+	Charging          string       `json:"charging,omitempty"` // skill the player is charging
+	PlayerSpeed       int          `json:"player_speed"`
+	MonsterSpeed      int          `json:"monster_speed"`
+	TurnOrder         []string     `json:"turn_order,omitempty"` // who acts next, soonest first
 }
 
 // EffectView shows a status effect for display.
@@ -305,14 +435,18 @@ type EffectView struct {
 	Name     string `json:"name"`
 	Duration int    `json:"duration"`
 	Type     string `json:"type"` // buff, debuff, dot
+	Stacks   int    `json:"stacks,omitempty"`
 }
 
 // GuardView shows guard status in combat.
 type GuardView struct {
 	Name    string `json:"name"`
+	Level   int    `json:"level"`
 	HP      int    `json:"hp"`
 	MaxHP   int    `json:"max_hp"`
 	Injured bool   `json:"injured"`
+	Stance  string `json:"stance"`
+	Skill   string `json:"skill,omitempty"`
 }
 
 // Helper constructors
@@ -362,9 +496,37 @@ func makeItemView(item models.Item) ItemView {
 	if item.ItemType == "skill_scroll" {
 		v.SkillName = item.SkillScroll.Skill.Name
 	}
+	if attrs := item.StatsMod.Attributes; game.AttributeSum(attrs) != 0 {
+		v.Attributes = &attrs
+	}
+	if lines := game.AffixDescriptions(item); len(lines) > 0 {
+		v.Affixes = lines
+	}
+	v.Unique = item.Unique
+	if set, ok := game.ItemSets[item.Set]; ok {
+		v.Set = set.Name
+	}
+	v.Durability = item.Durability
+	v.MaxDurability = item.MaxDurability
+	v.Broken = game.IsBroken(item)
+	if item.ItemType == "equipment" {
+		v.Sockets = game.SocketsForRarity(item.Rarity)
+		v.FilledSockets = len(item.Enchantments)
+	}
+	v.Quantity = game.StackSize(item)
 	return v
 }
 
+// makeEffectView converts an active status effect to an EffectView, using
+// the status registry to tell buffs from debuffs.
+func makeEffectView(eff models.StatusEffect) EffectView {
+	category := "debuff"
+	if def, _ := game.FindStatus(eff.Type); def.Beneficial {
+		category = "buff"
+	}
+	return EffectView{Name: eff.Type, Duration: eff.Duration, Type: category, Stacks: eff.Stacks}
+}
+
 // makeSkillView converts a models.Skill to a SkillView for the frontend.
 func makeSkillView(skill models.Skill) SkillView {
 	effectStr := ""
@@ -380,6 +542,11 @@ func makeSkillView(skill models.Skill) SkillView {
 		Effect:      effectStr,
 		Duration:    skill.Effect.Duration,
 		Description: skill.Description,
+		Rank:        game.SkillRank(skill),
+		RankXP:      skill.RankXP,
+		NextRankXP:  game.NextRankXP(skill),
+		Cooldown:    game.SkillCooldown(skill),
+		ChargeTurns: game.SkillChargeTurns(skill),
 	}
 }
 
@@ -405,9 +572,20 @@ func MakePlayerState(p *models.Character) *PlayerState {
 		HitPointMod:   p.StatsMod.HitPointMod,
 		Resurrections: p.Resurrections,
 		VillageName:   p.VillageName,
+		Class:         p.Class,
+		Talents:       p.Talents,
+		TalentPoints:  game.TalentPoints(p),
+
+		Attributes:      p.Attributes,
+		TotalAttributes: game.TotalAttributes(p),
+		AttributePoints: game.AttributePoints(p),
+
+		ItemEffects: p.StatsMod.Effects,
+		SetBonuses:  game.ActiveSetBonuses(p),
 	}
 
 	// Inventory
+	ps.InventorySlots = game.InventoryCapacity(p)
 	ps.Inventory = make([]ItemView, 0, len(p.Inventory))
 	for _, item := range p.Inventory {
 		ps.Inventory = append(ps.Inventory, makeItemView(item))
@@ -461,12 +639,6 @@ func MakePlayerState(p *models.Character) *PlayerState {
 	// Completed quests (populated by MakeCompletedQuestViews where GameState is available)
 	ps.CompletedQuests = []QuestView{}
 
-	// Buildings
-	ps.Buildings = make([]string, 0, len(p.BuiltBuildings))
-	for _, b := range p.BuiltBuildings {
-		ps.Buildings = append(ps.Buildings, b.Name)
-	}
-
 	// ActiveQuests will be populated by MakeQuestViews where GameState is available
 	ps.ActiveQuests = []QuestView{}
 
@@ -622,8 +794,13 @@ func MakeVillageView(village *models.Village) *VillageView {
 		ExpToLevel:       village.Level * 100,
 		DefenseLevel:     village.DefenseLevel,
 		UnlockedCrafting: village.UnlockedCrafting,
-		ResourcePerTick:  village.ResourcePerTick,
+		ResourcePerTick:  game.ProductionPerTick(village),
 		LastHarvestTime:  village.LastHarvestTime,
+		FoodStock:        village.FoodStock,
+		FoodStorage:      game.FoodStorage(village),
+		FoodUpkeep:       game.FoodUpkeep(village),
+		Housing:          game.HousingCapacity(village),
+		GuardCapacity:    game.GuardCapacity(village),
 	}
 
 	if vv.UnlockedCrafting == nil {
@@ -633,6 +810,13 @@ func MakeVillageView(village *models.Village) *VillageView {
 		vv.ResourcePerTick = make(map[string]int)
 	}
 
+	vv.Buildings = make([]BuildingView, 0, len(village.Buildings))
+	for _, b := range village.Buildings {
+		bp, _ := game.FindBuildingBlueprint(b.Name)
+		vv.Buildings = append(vv.Buildings, BuildingView{Name: b.Name, Level: b.Level,
+			Upkeep: game.BuildingUpkeep(bp, b.Level), Idle: b.Unpaid})
+	}
+
 	// Villagers
 	vv.Villagers = make([]VillagerView, 0, len(village.Villagers))
 	for _, v := range village.Villagers {
@@ -643,13 +827,20 @@ func MakeVillageView(village *models.Village) *VillageView {
 			Efficiency:   v.Efficiency,
 			AssignedTask: v.AssignedTask,
 			HarvestType:  v.HarvestType,
+			Trait:        v.Trait,
+			XP:           v.XP,
+			XPToLevel:    game.VillagerXPToLevel(v.Level),
+			Morale:       v.Morale,
+			Mood:         game.MoraleText(v.Morale),
+			InjuredTicks: v.InjuredTicks,
+			Output:       game.VillagerOutput(v),
 		})
 	}
 
 	// Guards
 	vv.Guards = make([]VillageGuardView, 0, len(village.ActiveGuards))
 	for _, g := range village.ActiveGuards {
-		vv.Guards = append(vv.Guards, VillageGuardView{
+		gv := VillageGuardView{
 			Name:         g.Name,
 			Level:        g.Level,
 			HP:           g.HitpointsRemaining,
@@ -660,7 +851,12 @@ func MakeVillageView(village *models.Village) *VillageView {
 			Cost:         g.Cost,
 			Injured:      g.Injured,
 			RecoveryTime: g.RecoveryTime,
-		})
+			Experience:   g.Experience,
+		}
+		if g.Skill != nil {
+			gv.Skill = g.Skill.Name
+		}
+		vv.Guards = append(vv.Guards, gv)
 	}
 
 	// Defenses
@@ -685,9 +881,66 @@ func MakeVillageView(village *models.Village) *VillageView {
 		})
 	}
 
+	// Stash
+	vv.Stash = make([]ItemView, 0, len(village.Stash))
+	for _, item := range village.Stash {
+		vv.Stash = append(vv.Stash, makeItemView(item))
+	}
+	vv.StashCapacity = game.StashCapacity(village)
+
+	if village.LastSiege != nil {
+		vv.LastSiege = makeSiegeView(village.LastSiege)
+	}
+
+	vv.ShieldUntil = village.ShieldUntil
+	vv.RaidLog = make([]string, 0, len(village.RaidLog))
+	for _, entry := range village.RaidLog {
+		vv.RaidLog = append(vv.RaidLog, game.RaidLogLine(entry))
+	}
+
 	return vv
 }
 
+// siegeTimelineView is how many events of each wave the village view carries;
+// the tide screen shows them all.
+const siegeTimelineView = 30
+
+func makeSiegeView(report *models.SiegeReport) *SiegeView {
+	sv := &SiegeView{
+		Time:         report.Time,
+		Victory:      report.Victory,
+		BreachDamage: report.BreachDamage,
+		Threshold:    report.Threshold,
+		Kills:        makeSiegeCounts(report.Kills),
+		WallDamage:   makeSiegeCounts(report.WallDamage),
+		WallsBroken:  report.WallsBroken,
+	}
+	if sv.WallsBroken == nil {
+		sv.WallsBroken = []string{}
+	}
+	for _, w := range report.Waves {
+		wv := SiegeWaveView{Number: w.Number, Monsters: w.Monsters, Killed: w.Killed,
+			Breached: w.Breached, Ticks: w.Ticks, Timeline: []string{}}
+		for i, ev := range w.Events {
+			if i == siegeTimelineView {
+				wv.More = len(w.Events) - i
+				break
+			}
+			wv.Timeline = append(wv.Timeline, fmt.Sprintf("T%d L%d: %s", ev.Tick, ev.Lane, ev.Text))
+		}
+		sv.Waves = append(sv.Waves, wv)
+	}
+	return sv
+}
+
+func makeSiegeCounts(tally map[string]int) []SiegeCountView {
+	counts := []SiegeCountView{}
+	for _, c := range game.SiegeTally(tally) {
+		counts = append(counts, SiegeCountView{Name: c.Name, Count: c.Count})
+	}
+	return counts
+}
+
 // --- Town view structs ---
 
 // NPCQuestView represents an NPC quest for the frontend.
@@ -915,6 +1168,21 @@ func MakeTownView(town *models.Town, accountID int64, charName string) *TownView
 	return tv
 }
 
+// makeGuardView shows a guard fighting in combat.
+func makeGuardView(g models.Guard) GuardView {
+	gv := GuardView{
+		Name: g.Name, Level: g.Level, HP: g.HitpointsRemaining, MaxHP: g.HitPoints, Injured: g.Injured,
+		Stance: game.FindGuardStance(g.Stance).Name,
+	}
+	if g.Skill != nil {
+		gv.Skill = g.Skill.Name
+	}
+	return gv
+}
+
+// turnOrderPreview is how many upcoming turns CombatView.TurnOrder shows.
+const turnOrderPreview = 6
+
 func MakeCombatView(session *GameSession) *CombatView {
 	if session.Combat == nil {
 		return nil
@@ -942,38 +1210,52 @@ func MakeCombatView(session *GameSession) *CombatView {
 		MonsterMaxSP:      m.StaminaTotal,
 		MonsterRarity:     string(game.NormalizeRarity(m.Rarity)),
 		MonsterIsBoss:     m.IsBoss,
+		MonsterBehavior:   game.MonsterProfile(m).Name,
 		MonsterIsGuardian: m.IsSkillGuardian,
 		ContinuousHunt:    c.ContinuousHunt,
+		PlayerSpeed:       game.Speed(p),
+		MonsterSpeed:      game.Speed(m),
 	}
+	view.TurnOrder = c.Queue.Preview(turnOrder(c, p, true), turnOrderPreview)
 
 	if m.IsSkillGuardian {
 		view.GuardedSkillName = m.GuardedSkill.Name
 	}
+	if boss := bossFightOf(c); boss != nil {
+		view.BossPhase = boss.CurrentPhase().Name
+		view.BossPhaseNum = boss.Phase + 1
+		view.BossPhases = len(boss.Def.Phases)
+		view.BossAdds = boss.LivingAdds()
+		if turns := boss.EnrageIn(c.Turn); turns > 0 {
+			view.BossEnrageIn = turns
+		}
+		if boss.WindUp {
+			view.BossWindUp = boss.CurrentPhase().Telegraph.Name
+		}
+	}
+
+	for _, skill := range p.LearnedSkills {
+		sv := makeSkillView(skill)
+		sv.CooldownLeft = c.PlayerSkills.CooldownLeft(skill.Name, c.Turn+1)
+		view.Skills = append(view.Skills, sv)
+	}
+	if c.PlayerSkills.Charging != nil {
+		view.Charging = c.PlayerSkills.Charging.Name
+	}
 
 	for _, eff := range p.StatusEffects {
-		category := "debuff"
-		if eff.Type == "buff_attack" || eff.Type == "buff_defense" || eff.Type == "regen" {
-			category = "buff"
-		}
-		view.PlayerEffects = append(view.PlayerEffects, EffectView{
-			Name: eff.Type, Duration: eff.Duration, Type: category,
-		})
+		view.PlayerEffects = append(view.PlayerEffects, makeEffectView(eff))
 	}
 
 	for _, eff := range m.StatusEffects {
-		category := "debuff"
-		if eff.Type == "buff_attack" || eff.Type == "buff_defense" || eff.Type == "regen" {
-			category = "buff"
-		}
-		view.MonsterEffects = append(view.MonsterEffects, EffectView{
-			Name: eff.Type, Duration: eff.Duration, Type: category,
-		})
+		view.MonsterEffects = append(view.MonsterEffects, makeEffectView(eff))
 	}
 
 	for _, g := range c.CombatGuards {
-		view.Guards = append(view.Guards, GuardView{
-			Name: g.Name, HP: g.HitpointsRemaining, MaxHP: g.HitPoints, Injured: g.Injured,
-		})
+		view.Guards = append(view.Guards, makeGuardView(g))
+	}
+	for _, g := range c.EnemyGuards {
+		view.EnemyGuards = append(view.EnemyGuards, makeGuardView(g))
 	}
 
 	return view
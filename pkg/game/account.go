@@ -0,0 +1,206 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"rpg-game/pkg/models"
+)
+
+// TownTraces collects everything a town's shared state remembers about one
+// account: inn snapshots, a player mayor, mayor-posted quests, attack log
+// lines, gossip and townsfolk memories. NPC data refers to players by
+// character name only, so callers should pass only names no other account
+// uses; a shared name can't be told apart.
+type TownTraces struct {
+	Town             string                        `json:"town"`
+	InnGuests        []models.InnGuest             `json:"inn_guests,omitempty"`
+	Mayor            *models.MayorData             `json:"mayor,omitempty"`
+	FetchQuests      []models.FetchQuest           `json:"fetch_quests,omitempty"`
+	AttackLog        []models.TownAttackLog        `json:"attack_log,omitempty"`
+	Gossip           []string                      `json:"gossip,omitempty"`
+	NPCMemories      map[string][]models.NPCMemory `json:"npc_memories,omitempty"`
+	NPCRelationships map[string]map[string]int     `json:"npc_relationships,omitempty"`
+	NPCQuests        []models.NPCQuest             `json:"npc_quests,omitempty"`
+}
+
+// Empty reports whether the town holds nothing about the account.
+func (t TownTraces) Empty() bool {
+	return len(t.InnGuests) == 0 && t.Mayor == nil && len(t.FetchQuests) == 0 &&
+		len(t.AttackLog) == 0 && len(t.Gossip) == 0 && len(t.NPCMemories) == 0 &&
+		len(t.NPCRelationships) == 0 && len(t.NPCQuests) == 0
+}
+
+// FindTownTraces returns the town's references to the account and its
+// characters.
+func FindTownTraces(town *models.Town, accountID int64, charNames []string) TownTraces {
+	owns := nameSet(charNames)
+	traces := TownTraces{Town: town.Name}
+
+	for _, g := range town.InnGuests {
+		if g.AccountID == accountID {
+			traces.InnGuests = append(traces.InnGuests, g)
+		}
+	}
+	if m := town.Mayor; m != nil && !m.IsNPC && m.AccountID == accountID {
+		mayor := *m
+		traces.Mayor = &mayor
+	}
+	for _, q := range town.FetchQuests {
+		if owns[q.CreatedBy] {
+			traces.FetchQuests = append(traces.FetchQuests, q)
+		}
+	}
+	for _, l := range town.AttackLog {
+		if owns[l.AttackerName] || owns[l.TargetName] {
+			traces.AttackLog = append(traces.AttackLog, l)
+		}
+	}
+	for _, g := range town.GossipBoard {
+		if mentionsAny(g, charNames) {
+			traces.Gossip = append(traces.Gossip, g)
+		}
+	}
+	for _, npc := range town.Townsfolk {
+		for _, m := range npc.Memory {
+			if owns[m.PlayerName] {
+				if traces.NPCMemories == nil {
+					traces.NPCMemories = make(map[string][]models.NPCMemory)
+				}
+				traces.NPCMemories[npc.Name] = append(traces.NPCMemories[npc.Name], m)
+			}
+		}
+		for name, rel := range npc.Relationships {
+			if owns[name] {
+				if traces.NPCRelationships == nil {
+					traces.NPCRelationships = make(map[string]map[string]int)
+				}
+				if traces.NPCRelationships[npc.Name] == nil {
+					traces.NPCRelationships[npc.Name] = make(map[string]int)
+				}
+				traces.NPCRelationships[npc.Name][name] = rel
+			}
+		}
+	}
+	for _, q := range town.NPCQuests {
+		if owns[q.AcceptedBy] {
+			traces.NPCQuests = append(traces.NPCQuests, q)
+		}
+	}
+	return traces
+}
+
+// ScrubTownTraces removes the account from the town's shared state. A player
+// mayor is replaced by an NPC as if they had abdicated, fetch quests they
+// posted stay up without an author, and open NPC quests they accepted go
+// back on the board. Returns true if the town changed.
+func ScrubTownTraces(town *models.Town, accountID int64, charNames []string) bool {
+	if FindTownTraces(town, accountID, charNames).Empty() {
+		return false
+	}
+	owns := nameSet(charNames)
+
+	guests := town.InnGuests[:0]
+	for _, g := range town.InnGuests {
+		if g.AccountID != accountID {
+			guests = append(guests, g)
+		}
+	}
+	town.InnGuests = guests
+
+	if m := town.Mayor; m != nil && !m.IsNPC && m.AccountID == accountID {
+		npcMayor := GenerateNPCMayor(10)
+		town.Mayor = &npcMayor
+	}
+	for i := range town.FetchQuests {
+		if owns[town.FetchQuests[i].CreatedBy] {
+			town.FetchQuests[i].CreatedBy = ""
+		}
+	}
+
+	log := town.AttackLog[:0]
+	for _, l := range town.AttackLog {
+		if !owns[l.AttackerName] && !owns[l.TargetName] {
+			log = append(log, l)
+		}
+	}
+	town.AttackLog = log
+
+	gossip := town.GossipBoard[:0]
+	for _, g := range town.GossipBoard {
+		if !mentionsAny(g, charNames) {
+			gossip = append(gossip, g)
+		}
+	}
+	town.GossipBoard = gossip
+
+	for i := range town.Townsfolk {
+		npc := &town.Townsfolk[i]
+		memory := npc.Memory[:0]
+		for _, m := range npc.Memory {
+			if !owns[m.PlayerName] {
+				memory = append(memory, m)
+			}
+		}
+		npc.Memory = memory
+		for name := range npc.Relationships {
+			if owns[name] {
+				delete(npc.Relationships, name)
+			}
+		}
+		npc.CurrentMood = ComputeNPCMood(npc)
+	}
+
+	quests := town.NPCQuests[:0]
+	for _, q := range town.NPCQuests {
+		if owns[q.AcceptedBy] {
+			if q.Completed || q.Failed {
+				continue
+			}
+			q.AcceptedBy = ""
+			q.Requirement.CurrentCount = 0
+		}
+		quests = append(quests, q)
+	}
+	town.NPCQuests = quests
+	return true
+}
+
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n != "" {
+			set[n] = true
+		}
+	}
+	return set
+}
+
+// mentionsAny reports whether s names any of names as a whole word, so "Al"
+// matches "Al's sword" but not "Alice" or "Valley".
+func mentionsAny(s string, names []string) bool {
+	for _, n := range names {
+		if n == "" {
+			continue
+		}
+		for i := 0; ; {
+			j := strings.Index(s[i:], n)
+			if j < 0 {
+				break
+			}
+			start, end := i+j, i+j+len(n)
+			before, _ := utf8.DecodeLastRuneInString(s[:start])
+			after, _ := utf8.DecodeRuneInString(s[end:])
+			if !isWordRune(before) && !isWordRune(after) {
+				return true
+			}
+			i = start + 1
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
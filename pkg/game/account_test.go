@@ -0,0 +1,53 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestScrubTownTraces(t *testing.T) {
+	town := GenerateDefaultTown(DefaultTownName)
+	town.Townsfolk = GenerateDefaultTownsfolk()
+	town.InnGuests = append(town.InnGuests, models.InnGuest{AccountID: 7, CharacterName: "Gone"})
+	town.Mayor = &models.MayorData{AccountID: 7, CharacterName: "Gone"}
+	town.FetchQuests = []models.FetchQuest{{ID: "fq1", CreatedBy: "Gone"}}
+	town.AttackLog = []models.TownAttackLog{
+		{AttackerName: "Gone", TargetName: "Stays"},
+		{AttackerName: "Stays", TargetName: "Somebody"},
+	}
+	town.GossipBoard = []string{"Gone was seen at the inn", "Wolves howl tonight", "Goneril sings in Bygone Valley"}
+	npc := &town.Townsfolk[0]
+	AddNPCMemory(npc, models.NPCMemory{PlayerName: "Gone", Sentiment: -5})
+	AddNPCMemory(npc, models.NPCMemory{PlayerName: "Stays", Sentiment: 3})
+	UpdateNPCRelationship(npc, "Gone", 20)
+	town.NPCQuests = []models.NPCQuest{
+		{ID: "open", AcceptedBy: "Gone", Requirement: models.NPCQuestReq{CurrentCount: 2, TargetCount: 5}},
+		{ID: "done", AcceptedBy: "Gone", Completed: true},
+	}
+
+	traces := FindTownTraces(&town, 7, []string{"Gone"})
+	if len(traces.InnGuests) != 1 || traces.Mayor == nil || len(traces.AttackLog) != 1 ||
+		len(traces.Gossip) != 1 || len(traces.NPCMemories[npc.Name]) != 1 || len(traces.NPCQuests) != 2 {
+		t.Fatalf("unexpected traces: %+v", traces)
+	}
+
+	if !ScrubTownTraces(&town, 7, []string{"Gone"}) {
+		t.Fatal("expected town to change")
+	}
+	if !FindTownTraces(&town, 7, []string{"Gone"}).Empty() {
+		t.Error("traces remain after scrub")
+	}
+	if town.Mayor == nil || !town.Mayor.IsNPC {
+		t.Error("player mayor should be replaced by an NPC")
+	}
+	if len(town.AttackLog) != 1 || len(town.GossipBoard) != 2 || len(town.Townsfolk[0].Memory) != 1 {
+		t.Error("unrelated town history should be kept")
+	}
+	if len(town.NPCQuests) != 1 || town.NPCQuests[0].AcceptedBy != "" || town.NPCQuests[0].Requirement.CurrentCount != 0 {
+		t.Errorf("open quest should return to the board, got %+v", town.NPCQuests)
+	}
+	if ScrubTownTraces(&town, 7, []string{"Gone"}) {
+		t.Error("second scrub should be a no-op")
+	}
+}
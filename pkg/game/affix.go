@@ -0,0 +1,582 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"rpg-game/pkg/models"
+)
+
+// Item effects granted by affixes, unique items and set bonuses. Attack,
+// defense and hit points fold into the flat StatMod fields; the rest are
+// collected in StatMod.Effects.
+const (
+	EffectAttack          = "attack"
+	EffectDefense         = "defense"
+	EffectHitPoints       = "hit_points"
+	EffectCritChance      = "crit_chance"      // +% crit chance
+	EffectLifesteal       = "lifesteal"        // % of attack damage healed
+	EffectThorns          = "thorns"           // damage returned to attackers
+	EffectFireDamage      = "fire_damage"      // bonus fire damage per hit
+	EffectIceDamage       = "ice_damage"       // bonus ice damage per hit
+	EffectLightningDamage = "lightning_damage" // bonus lightning damage per hit
+
+	EffectFireResist      = "fire_resist" // % less fire damage taken
+	EffectIceResist       = "ice_resist"
+	EffectLightningResist = "lightning_resist"
+	EffectPoisonResist    = "poison_resist"
+
+	EffectPoisonOnHit = "poison_on_hit" // % chance per hit to poison
+	EffectBurnOnHit   = "burn_on_hit"   // % chance per hit to burn
+	EffectStunOnHit   = "stun_on_hit"   // % chance per hit to stun
+)
+
+// effectWeights is how much one point of an effect counts toward an item's CP.
+var effectWeights = map[string]int{
+	EffectAttack:          1,
+	EffectDefense:         1,
+	EffectHitPoints:       1,
+	EffectCritChance:      3,
+	EffectLifesteal:       1,
+	EffectThorns:          1,
+	EffectFireDamage:      2,
+	EffectIceDamage:       2,
+	EffectLightningDamage: 2,
+	EffectFireResist:      1,
+	EffectIceResist:       1,
+	EffectLightningResist: 1,
+	EffectPoisonResist:    1,
+	EffectPoisonOnHit:     1,
+	EffectBurnOnHit:       1,
+	EffectStunOnHit:       2,
+}
+
+// elementalEffects maps the on-hit damage effects to their damage type.
+var elementalEffects = []struct {
+	Effect string
+	Type   models.DamageType
+}{
+	{EffectFireDamage, models.Fire},
+	{EffectIceDamage, models.Ice},
+	{EffectLightningDamage, models.Lightning},
+}
+
+// resistEffects maps each damage type to the item effect resisting it.
+var resistEffects = map[models.DamageType]string{
+	models.Fire:      EffectFireResist,
+	models.Ice:       EffectIceResist,
+	models.Lightning: EffectLightningResist,
+	models.Poison:    EffectPoisonResist,
+}
+
+// MaxItemResist caps the damage reduction gear can give against one type.
+const MaxItemResist = 75
+
+// onHitStatuses maps the status-on-hit effects to the status they inflict.
+var onHitStatuses = []struct {
+	Effect string
+	Status models.StatusEffect
+}{
+	{EffectPoisonOnHit, models.StatusEffect{Type: "poison", Duration: 3, Potency: 3}},
+	{EffectBurnOnHit, models.StatusEffect{Type: "burn", Duration: 2, Potency: 5}},
+	{EffectStunOnHit, models.StatusEffect{Type: "stun", Duration: 1}},
+}
+
+// affixTemplate describes an affix that can roll on items of at least
+// MinRarity, with a value between Min and Max.
+type affixTemplate struct {
+	Name      string
+	Prefix    bool
+	Effect    string
+	MinRarity int
+	Min, Max  int
+}
+
+var affixTemplates = []affixTemplate{
+	{"Sturdy", true, EffectDefense, 2, 2, 5},
+	{"Keen", true, EffectCritChance, 2, 1, 3},
+	{"Brutal", true, EffectAttack, 3, 2, 5},
+	{"Spiked", true, EffectThorns, 3, 2, 5},
+	{"Vampiric", true, EffectLifesteal, 4, 5, 10},
+	{"Tyrant's", true, EffectAttack, 6, 6, 10},
+
+	{"of the Bear", false, EffectHitPoints, 2, 5, 15},
+	{"of Flame", false, EffectFireDamage, 3, 2, 5},
+	{"of Frost", false, EffectIceDamage, 3, 2, 5},
+	{"of Storms", false, EffectLightningDamage, 4, 3, 6},
+	{"of Precision", false, EffectCritChance, 5, 3, 6},
+	{"of the Leech", false, EffectLifesteal, 6, 10, 20},
+}
+
+// Rarity tiers at which items start rolling affixes, uniques and set pieces.
+const (
+	AffixMinRarity       = 2 // one prefix or suffix
+	DoubleAffixMinRarity = 4 // a prefix and a suffix
+	SetMinRarity         = 4
+	UniqueMinRarity      = 5
+	SetDropChance        = 4 // % chance an eligible item is a set piece
+	UniqueDropChance     = 2 // % chance an eligible item is a unique
+)
+
+// UniqueItem is a named item with fixed stats and effects. Boss-only
+// uniques never drop at random; they come from boss loot tables.
+type UniqueItem struct {
+	Name      string
+	Slot      int
+	MinRarity int
+	StatsMod  models.StatMod
+	Affixes   []models.Affix
+	BossOnly  bool
+}
+
+var UniqueItems = []UniqueItem{
+	{
+		Name: "Emberheart", Slot: 5, MinRarity: 5,
+		StatsMod: models.StatMod{AttackMod: 8},
+		Affixes:  []models.Affix{{Name: "Emberheart", Effect: EffectFireDamage, Value: 8}},
+	},
+	{
+		Name: "Bloodthirst", Slot: 5, MinRarity: 6,
+		StatsMod: models.StatMod{AttackMod: 6},
+		Affixes:  []models.Affix{{Name: "Bloodthirst", Effect: EffectLifesteal, Value: 25}},
+	},
+	{
+		Name: "Frostward Aegis", Slot: 6, MinRarity: 5,
+		StatsMod: models.StatMod{DefenseMod: 10},
+		Affixes: []models.Affix{
+			{Name: "Frostward", Effect: EffectIceDamage, Value: 4},
+			{Name: "Aegis", Effect: EffectThorns, Value: 4},
+		},
+	},
+	{
+		Name: "Crown of the Tide King", Slot: 0, MinRarity: 7,
+		StatsMod: models.StatMod{DefenseMod: 6, HitPointMod: 20, Attributes: models.Attributes{Vitality: 5}},
+		Affixes:  []models.Affix{{Name: "Tide King", Effect: EffectThorns, Value: 8}},
+	},
+	{
+		Name: "Stormwalkers", Slot: 3, MinRarity: 6,
+		StatsMod: models.StatMod{DefenseMod: 3, Attributes: models.Attributes{Dexterity: 6}},
+		Affixes: []models.Affix{
+			{Name: "Storm", Effect: EffectLightningDamage, Value: 5},
+			{Name: "Walker", Effect: EffectCritChance, Value: 5},
+		},
+	},
+
+	// Boss loot (see data.BossDefinitions)
+	{
+		Name: "Warlord's Cleaver", Slot: 5, MinRarity: 6, BossOnly: true,
+		StatsMod: models.StatMod{AttackMod: 10},
+		Affixes:  []models.Affix{{Name: "Warlord", Effect: EffectLifesteal, Value: 10}},
+	},
+	{
+		Name: "Crown of the Abyss", Slot: 0, MinRarity: 7, BossOnly: true,
+		StatsMod: models.StatMod{DefenseMod: 8, HitPointMod: 30},
+		Affixes: []models.Affix{
+			{Name: "Abyss", Effect: EffectFireDamage, Value: 6},
+			{Name: "Crown", Effect: EffectCritChance, Value: 5},
+		},
+	},
+	{
+		Name: "Guardian's Bulwark", Slot: 6, MinRarity: 6, BossOnly: true,
+		StatsMod: models.StatMod{DefenseMod: 12, HitPointMod: 15},
+		Affixes:  []models.Affix{{Name: "Bulwark", Effect: EffectThorns, Value: 6}},
+	},
+	{
+		Name: "Scholar's Sigil", Slot: 7, MinRarity: 5, BossOnly: true,
+		StatsMod: models.StatMod{Attributes: models.Attributes{Intelligence: 6}},
+		Affixes:  []models.Affix{{Name: "Sigil", Effect: EffectLightningDamage, Value: 4}},
+	},
+	{
+		Name: "Tidebreaker", Slot: 5, MinRarity: 7, BossOnly: true,
+		StatsMod: models.StatMod{AttackMod: 9},
+		Affixes:  []models.Affix{{Name: "Tidebreaker", Effect: EffectIceDamage, Value: 6}},
+	},
+}
+
+// SetBonus is granted while at least Pieces items of the set are equipped.
+type SetBonus struct {
+	Pieces  int
+	Effects map[string]int
+}
+
+// ItemSet is a group of named items, one per slot, that grant bonuses when
+// worn together.
+type ItemSet struct {
+	ID      string
+	Name    string
+	Pieces  map[int]string // slot -> piece name
+	Bonuses []SetBonus
+}
+
+var ItemSets = map[string]ItemSet{
+	"wardens_bulwark": {
+		ID:   "wardens_bulwark",
+		Name: "Warden's Bulwark",
+		Pieces: map[int]string{
+			0: "Warden's Helm", 1: "Warden's Plate", 2: "Warden's Greaves",
+		},
+		Bonuses: []SetBonus{
+			{Pieces: 2, Effects: map[string]int{EffectDefense: 5}},
+			{Pieces: 3, Effects: map[string]int{EffectHitPoints: 20, EffectThorns: 5}},
+		},
+	},
+	"shadowstalker": {
+		ID:   "shadowstalker",
+		Name: "Shadowstalker",
+		Pieces: map[int]string{
+			3: "Shadowstalker Boots", 4: "Shadowstalker Grips", 7: "Shadowstalker Charm",
+		},
+		Bonuses: []SetBonus{
+			{Pieces: 2, Effects: map[string]int{EffectCritChance: 5}},
+			{Pieces: 3, Effects: map[string]int{EffectAttack: 4, EffectLifesteal: 10}},
+		},
+	},
+	"stormcallers_regalia": {
+		ID:   "stormcallers_regalia",
+		Name: "Stormcaller's Regalia",
+		Pieces: map[int]string{
+			5: "Stormcaller's Rod", 6: "Stormcaller's Tome", 1: "Stormcaller's Robe",
+		},
+		Bonuses: []SetBonus{
+			{Pieces: 2, Effects: map[string]int{EffectLightningDamage: 4}},
+			{Pieces: 3, Effects: map[string]int{EffectLightningDamage: 6, EffectCritChance: 4}},
+		},
+	},
+}
+
+// rollAffixes adds random affixes to a freshly generated item according to
+// its rarity tier and prepends/appends their names.
+func rollAffixes(item *models.Item) {
+	if item.Rarity < AffixMinRarity {
+		return
+	}
+	wantPrefix := rand.Intn(2) == 0
+	wantSuffix := !wantPrefix
+	if item.Rarity >= DoubleAffixMinRarity {
+		wantPrefix, wantSuffix = true, true
+	}
+	if wantPrefix {
+		if a, ok := rollAffix(item.Rarity, true); ok {
+			item.Affixes = append(item.Affixes, a)
+			item.Name = a.Name + " " + item.Name
+		}
+	}
+	if wantSuffix {
+		if a, ok := rollAffix(item.Rarity, false); ok {
+			item.Affixes = append(item.Affixes, a)
+			item.Name = item.Name + " " + a.Name
+		}
+	}
+}
+
+func rollAffix(rarity int, prefix bool) (models.Affix, bool) {
+	pool := []affixTemplate{}
+	for _, t := range affixTemplates {
+		if t.Prefix == prefix && rarity >= t.MinRarity {
+			pool = append(pool, t)
+		}
+	}
+	if len(pool) == 0 {
+		return models.Affix{}, false
+	}
+	t := pool[rand.Intn(len(pool))]
+	return models.Affix{Name: t.Name, Prefix: t.Prefix, Effect: t.Effect, Value: t.Min + rand.Intn(t.Max-t.Min+1)}, true
+}
+
+// rollSpecialItem occasionally turns a high-rarity drop into a unique or a
+// set piece. It reports whether the item was replaced.
+func rollSpecialItem(item *models.Item) bool {
+	if item.Rarity >= UniqueMinRarity && rand.Intn(100) < UniqueDropChance {
+		eligible := []UniqueItem{}
+		for _, u := range UniqueItems {
+			if item.Rarity >= u.MinRarity && !u.BossOnly {
+				eligible = append(eligible, u)
+			}
+		}
+		if len(eligible) > 0 {
+			*item = CreateUniqueItem(eligible[rand.Intn(len(eligible))], item.Rarity)
+			return true
+		}
+	}
+	if item.Rarity >= SetMinRarity && rand.Intn(100) < SetDropChance {
+		ids := make([]string, 0, len(ItemSets))
+		for id := range ItemSets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		set := ItemSets[ids[rand.Intn(len(ids))]]
+		slots := make([]int, 0, len(set.Pieces))
+		for slot := range set.Pieces {
+			slots = append(slots, slot)
+		}
+		sort.Ints(slots)
+		slot := slots[rand.Intn(len(slots))]
+		item.Slot = slot
+		item.Name = set.Pieces[slot]
+		item.Set = set.ID
+		item.Affixes = nil
+		return true
+	}
+	return false
+}
+
+// CreateUniqueItem builds the named unique at the given rarity.
+func CreateUniqueItem(u UniqueItem, rarity int) models.Item {
+	item := models.Item{
+		Name:     u.Name,
+		Rarity:   rarity,
+		Slot:     u.Slot,
+		StatsMod: u.StatsMod,
+		ItemType: "equipment",
+		Affixes:  append([]models.Affix(nil), u.Affixes...),
+		Unique:   true,
+	}
+	item.CP = ItemPower(item)
+	EnsureDurability(&item)
+	return item
+}
+
+// ItemPower is an item's CP: its flat stats and attributes plus the weighted
+// value of its affixes and enchantments.
+func ItemPower(item models.Item) int {
+	cp := item.StatsMod.AttackMod + item.StatsMod.DefenseMod + item.StatsMod.HitPointMod + AttributeSum(item.StatsMod.Attributes)
+	for _, a := range item.Affixes {
+		cp += effectWeights[a.Effect] * a.Value
+	}
+	for _, a := range item.Enchantments {
+		cp += effectWeights[a.Effect] * a.Value
+	}
+	return cp
+}
+
+// effectsPower weighs a set of effects the same way ItemPower weighs affixes.
+func effectsPower(effects map[string]int) int {
+	total := 0
+	for effect, v := range effects {
+		total += effectWeights[effect] * v
+	}
+	return total
+}
+
+// setPieceCounts counts equipped items per set.
+func setPieceCounts(equipment map[int]models.Item) map[string]int {
+	counts := map[string]int{}
+	for _, item := range equipment {
+		if item.Set != "" && !IsBroken(item) {
+			counts[item.Set]++
+		}
+	}
+	return counts
+}
+
+// activeSetEffects sums the bonuses of every set bonus the equipment meets.
+func activeSetEffects(equipment map[int]models.Item) map[string]int {
+	effects := map[string]int{}
+	for id, n := range setPieceCounts(equipment) {
+		for _, b := range ItemSets[id].Bonuses {
+			if n < b.Pieces {
+				continue
+			}
+			for effect, v := range b.Effects {
+				effects[effect] += v
+			}
+		}
+	}
+	return effects
+}
+
+// addEffect folds one effect into a StatMod.
+func addEffect(mod *models.StatMod, effect string, value int) {
+	switch effect {
+	case EffectAttack:
+		mod.AttackMod += value
+	case EffectDefense:
+		mod.DefenseMod += value
+	case EffectHitPoints:
+		mod.HitPointMod += value
+	default:
+		if mod.Effects == nil {
+			mod.Effects = map[string]int{}
+		}
+		mod.Effects[effect] += value
+	}
+}
+
+// EquipScore rates item for its slot given the rest of the equipment,
+// counting set bonuses it would complete. Broken items score zero.
+func EquipScore(item models.Item, equipment map[int]models.Item) int {
+	if IsBroken(item) {
+		return 0
+	}
+	score := ItemPower(item)
+	if item.Set == "" {
+		return score
+	}
+	without := map[int]models.Item{}
+	for slot, it := range equipment {
+		if slot != item.Slot {
+			without[slot] = it
+		}
+	}
+	with := map[int]models.Item{item.Slot: item}
+	for slot, it := range without {
+		with[slot] = it
+	}
+	return score + effectsPower(activeSetEffects(with)) - effectsPower(activeSetEffects(without))
+}
+
+// EffectDescription renders an effect value for display.
+func EffectDescription(effect string, value int) string {
+	switch effect {
+	case EffectAttack:
+		return fmt.Sprintf("+%d attack", value)
+	case EffectDefense:
+		return fmt.Sprintf("+%d defense", value)
+	case EffectHitPoints:
+		return fmt.Sprintf("+%d HP", value)
+	case EffectCritChance:
+		return fmt.Sprintf("+%d%% crit chance", value)
+	case EffectLifesteal:
+		return fmt.Sprintf("%d%% lifesteal", value)
+	case EffectThorns:
+		return fmt.Sprintf("%d thorns damage", value)
+	case EffectFireDamage:
+		return fmt.Sprintf("+%d fire damage", value)
+	case EffectIceDamage:
+		return fmt.Sprintf("+%d ice damage", value)
+	case EffectLightningDamage:
+		return fmt.Sprintf("+%d lightning damage", value)
+	case EffectFireResist, EffectIceResist, EffectLightningResist, EffectPoisonResist:
+		return fmt.Sprintf("%d%% %s resistance", value, strings.TrimSuffix(effect, "_resist"))
+	case EffectPoisonOnHit:
+		return fmt.Sprintf("%d%% chance to poison on hit", value)
+	case EffectBurnOnHit:
+		return fmt.Sprintf("%d%% chance to burn on hit", value)
+	case EffectStunOnHit:
+		return fmt.Sprintf("%d%% chance to stun on hit", value)
+	}
+	return fmt.Sprintf("%s %+d", effect, value)
+}
+
+// AffixDescriptions lists an item's affixes and set bonuses for display.
+func AffixDescriptions(item models.Item) []string {
+	lines := []string{}
+	for _, a := range item.Affixes {
+		lines = append(lines, fmt.Sprintf("%s: %s", a.Name, EffectDescription(a.Effect, a.Value)))
+	}
+	for _, a := range item.Enchantments {
+		lines = append(lines, fmt.Sprintf("[Socket] %s: %s", a.Name, EffectDescription(a.Effect, a.Value)))
+	}
+	if set, ok := ItemSets[item.Set]; ok {
+		for _, b := range set.Bonuses {
+			lines = append(lines, fmt.Sprintf("%s (%d): %s", set.Name, b.Pieces, effectsText(b.Effects)))
+		}
+	}
+	return lines
+}
+
+// ActiveSetBonuses describes the set bonuses the player currently has.
+func ActiveSetBonuses(player *models.Character) []string {
+	counts := setPieceCounts(player.EquipmentMap)
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	lines := []string{}
+	for _, id := range ids {
+		set := ItemSets[id]
+		for _, b := range set.Bonuses {
+			if counts[id] >= b.Pieces {
+				lines = append(lines, fmt.Sprintf("%s (%d/%d): %s", set.Name, counts[id], len(set.Pieces), effectsText(b.Effects)))
+			}
+		}
+	}
+	return lines
+}
+
+func effectsText(effects map[string]int) string {
+	keys := make([]string, 0, len(effects))
+	for k := range effects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	text := ""
+	for i, k := range keys {
+		if i > 0 {
+			text += ", "
+		}
+		text += EffectDescription(k, effects[k])
+	}
+	return text
+}
+
+// ApplyOnHitEffects applies the player's elemental item damage, status
+// effects and lifesteal after a weapon attack dealt damage to mob, returning
+// a line per effect.
+func ApplyOnHitEffects(player *models.Character, mob *models.Monster, dealt int) []string {
+	if dealt <= 0 {
+		return nil
+	}
+	bonus, lines := applyItemStrikes(player.StatsMod.Effects, mob)
+	total := dealt + bonus
+	if pct := player.StatsMod.Effects[EffectLifesteal]; pct > 0 && player.HitpointsRemaining < player.HitpointsTotal {
+		heal := max(total*pct/100, 1)
+		heal = min(heal, player.HitpointsTotal-player.HitpointsRemaining)
+		player.HitpointsRemaining += heal
+		lines = append(lines, fmt.Sprintf("%s drains %d HP!", player.Name, heal))
+	}
+	return lines
+}
+
+// applyItemStrikes deals the elemental damage and rolls the status-on-hit
+// effects of a hit from gear with the given effects, returning the extra
+// damage dealt and a line per effect.
+func applyItemStrikes(effects map[string]int, mob *models.Monster) (int, []string) {
+	total := 0
+	lines := []string{}
+	for _, el := range elementalEffects {
+		v := effects[el.Effect]
+		if v <= 0 {
+			continue
+		}
+		dmg := ApplyDamage(v, el.Type, mob)
+		if dmg <= 0 {
+			continue
+		}
+		mob.HitpointsRemaining -= dmg
+		total += dmg
+		lines = append(lines, fmt.Sprintf("%s takes %d %s damage!", mob.Name, dmg, el.Type))
+	}
+	for _, oh := range onHitStatuses {
+		if chance := effects[oh.Effect]; chance > 0 && mob.HitpointsRemaining > 0 && rand.Intn(100) < chance {
+			if msg, _ := ApplyStatus(mob, oh.Status); msg.Text != "" {
+				lines = append(lines, msg.Text)
+			}
+		}
+	}
+	return total, lines
+}
+
+// ElementalItemDamage totals the flat elemental damage per hit from gear with
+// the given effects, for fights resolved without a monster to resist it.
+func ElementalItemDamage(effects map[string]int) int {
+	total := 0
+	for _, el := range elementalEffects {
+		total += max(effects[el.Effect], 0)
+	}
+	return total
+}
+
+// ApplyThorns returns thorns damage to a monster that just hit the player.
+func ApplyThorns(player *models.Character, mob *models.Monster, taken int) []string {
+	v := player.StatsMod.Effects[EffectThorns]
+	if taken <= 0 || v <= 0 {
+		return nil
+	}
+	mob.HitpointsRemaining -= v
+	return []string{fmt.Sprintf("%s takes %d thorns damage!", mob.Name, v)}
+}
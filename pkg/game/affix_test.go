@@ -0,0 +1,71 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestGenerateItemAffixTiers(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		if item := GenerateItem(1); len(item.Affixes) != 0 {
+			t.Fatalf("rarity 1 should not roll affixes: %+v", item.Affixes)
+		}
+		item := GenerateItem(4)
+		if item.Unique || item.Set != "" {
+			continue
+		}
+		if len(item.Affixes) != 2 || !item.Affixes[0].Prefix || item.Affixes[1].Prefix {
+			t.Fatalf("rarity 4 should roll a prefix and a suffix: %+v", item.Affixes)
+		}
+		if item.CP != ItemPower(item) {
+			t.Fatalf("CP %d should include affixes (%d)", item.CP, ItemPower(item))
+		}
+	}
+}
+
+func TestSetBonusesAndEquipScore(t *testing.T) {
+	piece := func(slot int) models.Item {
+		return models.Item{Name: ItemSets["wardens_bulwark"].Pieces[slot], Slot: slot, ItemType: "equipment", Set: "wardens_bulwark", CP: 1,
+			StatsMod: models.StatMod{DefenseMod: 1}}
+	}
+	equipment := map[int]models.Item{0: piece(0), 1: piece(1)}
+	mods := CalculateItemMods(equipment)
+	if mods.DefenseMod != 2+5 {
+		t.Errorf("2-piece bonus should add 5 defense, got %d", mods.DefenseMod)
+	}
+
+	// A plain item with more raw CP loses to the piece completing the set.
+	plain := models.Item{Name: "Iron Greaves", Slot: 2, ItemType: "equipment", StatsMod: models.StatMod{DefenseMod: 10}}
+	plain.CP = ItemPower(plain)
+	inventory := []models.Item{}
+	EquipBestItem(plain, &equipment, &inventory)
+	EquipBestItem(piece(2), &equipment, &inventory)
+	if equipment[2].Set != "wardens_bulwark" {
+		t.Fatalf("expected set piece to replace %s", plain.Name)
+	}
+	mods = CalculateItemMods(equipment)
+	if mods.HitPointMod != 20 || mods.Effects[EffectThorns] != 5 {
+		t.Errorf("3-piece bonus missing: %+v", mods)
+	}
+}
+
+func TestOnHitEffects(t *testing.T) {
+	player := GenerateCharacter("Vlad", 1, 1)
+	player.EquipmentMap = map[int]models.Item{5: CreateUniqueItem(UniqueItems[0], 5)}
+	player.EquipmentMap[6] = models.Item{Name: "Vampiric Buckler", Slot: 6, ItemType: "equipment",
+		Affixes: []models.Affix{{Name: "Vampiric", Prefix: true, Effect: EffectLifesteal, Value: 50}}}
+	player.HitpointsNatural = 50
+	RecalculatePlayerStats(&player)
+	player.HitpointsRemaining = 1
+
+	mob := models.Monster{Name: "Slime", HitpointsRemaining: 100, HitpointsTotal: 100,
+		Resistances: map[models.DamageType]float64{models.Fire: 0.5}}
+	lines := ApplyOnHitEffects(&player, &mob, 10)
+	if mob.HitpointsRemaining != 100-4 {
+		t.Errorf("expected 4 resisted fire damage, mob at %d", mob.HitpointsRemaining)
+	}
+	if player.HitpointsRemaining != 1+7 || len(lines) != 2 {
+		t.Errorf("expected 7 HP drained, got %d (%v)", player.HitpointsRemaining, lines)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"math"
 	"time"
 
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/models"
 )
 
-// ArenaMaxBattlesPerDay is the daily battle limit per player.
-const ArenaMaxBattlesPerDay = 5
+// ArenaMaxBattlesPerDay returns the daily battle limit per player.
+func ArenaMaxBattlesPerDay() int {
+	return config.Current().Balance.ArenaMaxBattlesPerDay
+}
 
 // CalculateArenaPoints computes ELO-style rating changes.
 // Returns (winnerGain, loserLoss).
@@ -67,5 +70,6 @@ func CharacterToArenaMonster(char *models.Character) models.Monster {
 		StatusEffects:      []models.StatusEffect{},
 		Resistances:        copyResistances(char.Resistances),
 		MonsterType:        "humanoid",
+		Speed:              CharacterSpeed(char),
 	}
 }
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"fmt"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+// AttributeNames lists the primary attributes in display order.
+var AttributeNames = []string{"strength", "dexterity", "intelligence", "vitality", "luck"}
+
+// Per-point effects of the primary attributes.
+const (
+	StrengthPerAttackRoll  = 10 // strength for each extra attack roll
+	VitalityPerDefenseRoll = 10 // vitality for each extra defense roll
+	HPPerVitality          = 3
+	ManaPerIntelligence    = 3
+	StaminaPerDexterity    = 2
+	DexterityPerCrit       = 4  // dexterity for each +1% crit chance
+	LuckPerCrit            = 10 // luck for each +1% crit chance
+	LuckPerLootChance      = 2  // luck for each +1% drop chance
+	DexterityPerSpeed      = 5  // dexterity for each +1 speed
+	SkillPctPerPoint       = 2  // +% skill damage per strength (physical) or intelligence (elemental, heals)
+)
+
+// AttributeField returns a pointer to the named attribute, or nil if the name
+// is unknown.
+func AttributeField(a *models.Attributes, name string) *int {
+	switch name {
+	case "strength":
+		return &a.Strength
+	case "dexterity":
+		return &a.Dexterity
+	case "intelligence":
+		return &a.Intelligence
+	case "vitality":
+		return &a.Vitality
+	case "luck":
+		return &a.Luck
+	}
+	return nil
+}
+
+// AddAttributes returns the field-wise sum of two attribute sets.
+func AddAttributes(a, b models.Attributes) models.Attributes {
+	return models.Attributes{
+		Strength:     a.Strength + b.Strength,
+		Dexterity:    a.Dexterity + b.Dexterity,
+		Intelligence: a.Intelligence + b.Intelligence,
+		Vitality:     a.Vitality + b.Vitality,
+		Luck:         a.Luck + b.Luck,
+	}
+}
+
+// AttributeSum totals every attribute in the set.
+func AttributeSum(a models.Attributes) int {
+	return a.Strength + a.Dexterity + a.Intelligence + a.Vitality + a.Luck
+}
+
+// TotalAttributes is the character's allocated attributes plus bonuses from
+// equipped items.
+func TotalAttributes(player *models.Character) models.Attributes {
+	return AddAttributes(player.Attributes, player.StatsMod.Attributes)
+}
+
+// AttributePoints returns the unallocated attribute points: a fixed number
+// per level past the first, minus what has been allocated.
+func AttributePoints(player *models.Character) int {
+	points := (player.Level-1)*config.Current().Balance.AttributePointsPerLevel - AttributeSum(player.Attributes)
+	if points < 0 {
+		return 0
+	}
+	return points
+}
+
+// AllocateAttribute spends one attribute point on the named attribute.
+func AllocateAttribute(player *models.Character, name string) error {
+	field := AttributeField(&player.Attributes, name)
+	if field == nil {
+		return fmt.Errorf("unknown attribute %q", name)
+	}
+	if AttributePoints(player) <= 0 {
+		return fmt.Errorf("no attribute points available")
+	}
+	*field++
+	RecalculatePlayerStats(player)
+	return nil
+}
+
+// RespecLocation is where respecs are sold.
+const RespecLocation = "Training Hall"
+
+// RespecCost is the gold price of resetting the character's attributes.
+func RespecCost(player *models.Character) int {
+	b := config.Current().Balance
+	return b.RespecBaseCost + player.Level*b.RespecCostPerLevel
+}
+
+// RespecAttributes charges the respec cost in gold and returns every
+// allocated point to the pool.
+func RespecAttributes(player *models.Character) error {
+	if AttributeSum(player.Attributes) == 0 {
+		return fmt.Errorf("no attribute points have been allocated")
+	}
+	cost := RespecCost(player)
+	if GoldBalance(player) < cost {
+		return fmt.Errorf("a respec costs %d gold, you have %d", cost, GoldBalance(player))
+	}
+	AdjustResource(player, "Gold", -cost, ReasonRespec, "training_hall")
+	player.Attributes = models.Attributes{}
+	RecalculatePlayerStats(player)
+	return nil
+}
+
+// RecalculatePlayerStats rebuilds the character's item mods and everything
+// derived from attributes: attack and defense rolls and the HP, mana and
+// stamina pools. Remaining values are capped at the new totals.
+func RecalculatePlayerStats(player *models.Character) {
+	player.StatsMod = CalculateItemMods(player.EquipmentMap)
+	attrs := TotalAttributes(player)
+	player.StatsMod.HitPointMod += attrs.Vitality * HPPerVitality
+
+	baseRolls := player.Level/10 + 1
+	player.AttackRolls = baseRolls + attrs.Strength/StrengthPerAttackRoll
+	player.DefenseRolls = baseRolls + attrs.Vitality/VitalityPerDefenseRoll
+
+	player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
+	player.ManaTotal = player.ManaNatural + attrs.Intelligence*ManaPerIntelligence
+	player.StaminaTotal = player.StaminaNatural + attrs.Dexterity*StaminaPerDexterity
+	if player.HitpointsRemaining > player.HitpointsTotal {
+		player.HitpointsRemaining = player.HitpointsTotal
+	}
+	if player.ManaRemaining > player.ManaTotal {
+		player.ManaRemaining = player.ManaTotal
+	}
+	if player.StaminaRemaining > player.StaminaTotal {
+		player.StaminaRemaining = player.StaminaTotal
+	}
+}
+
+// AttributeCritBonus is the crit chance in percent granted by dexterity and
+// luck.
+func AttributeCritBonus(player *models.Character) int {
+	attrs := TotalAttributes(player)
+	return attrs.Dexterity/DexterityPerCrit + attrs.Luck/LuckPerCrit
+}
+
+// LootChance raises a base drop chance (percent) by the character's luck,
+// capped at 95.
+func LootChance(player *models.Character, base int) int {
+	chance := base + TotalAttributes(player).Luck/LuckPerLootChance
+	if chance > 95 {
+		return max(base, 95)
+	}
+	return chance
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+func TestAllocateAndRespecAttributes(t *testing.T) {
+	perLevel := config.Current().Balance.AttributePointsPerLevel
+	char := GenerateCharacter("Stat", 1, 1)
+	char.EquipmentMap = map[int]models.Item{}
+	char.ResourceStorageMap = map[string]models.Resource{}
+	if err := AllocateAttribute(&char, "strength"); err == nil {
+		t.Error("level 1 has no attribute points")
+	}
+
+	char.Level = 11
+	if AttributePoints(&char) != 10*perLevel {
+		t.Fatalf("expected %d points, got %d", 10*perLevel, AttributePoints(&char))
+	}
+	for i := 0; i < StrengthPerAttackRoll; i++ {
+		if err := AllocateAttribute(&char, "strength"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AllocateAttribute(&char, "vitality"); err != nil {
+		t.Fatal(err)
+	}
+	if err := AllocateAttribute(&char, "charisma"); err == nil {
+		t.Error("unknown attribute should be rejected")
+	}
+	if char.AttackRolls != 3 {
+		t.Errorf("level 11 with %d strength should have 3 attack rolls, got %d", StrengthPerAttackRoll, char.AttackRolls)
+	}
+	if char.HitpointsTotal != char.HitpointsNatural+HPPerVitality {
+		t.Errorf("vitality HP not applied: total %d natural %d", char.HitpointsTotal, char.HitpointsNatural)
+	}
+
+	if err := RespecAttributes(&char); err == nil {
+		t.Error("respec without gold should fail")
+	}
+	AdjustResource(&char, "Gold", RespecCost(&char), ReasonMonsterDrop, "")
+	if err := RespecAttributes(&char); err != nil {
+		t.Fatal(err)
+	}
+	if AttributeSum(char.Attributes) != 0 || GoldBalance(&char) != 0 || char.AttackRolls != 2 {
+		t.Errorf("respec did not reset: %+v gold %d rolls %d", char.Attributes, GoldBalance(&char), char.AttackRolls)
+	}
+}
+
+func TestItemAttributesFeedStats(t *testing.T) {
+	char := GenerateCharacter("Gear", 1, 1)
+	char.EquipmentMap = map[int]models.Item{
+		0: {Name: "Ring", ItemType: "equipment", StatsMod: models.StatMod{Attributes: models.Attributes{Luck: 20, Intelligence: 5}}},
+	}
+	RecalculatePlayerStats(&char)
+	if char.ManaTotal != char.ManaNatural+5*ManaPerIntelligence {
+		t.Errorf("item intelligence should raise mana, got %d", char.ManaTotal)
+	}
+	if got := LootChance(&char, 30); got != 30+20/LuckPerLootChance {
+		t.Errorf("unexpected loot chance %d", got)
+	}
+	if got := LootChance(&char, 90); got != 95 {
+		t.Errorf("loot chance should cap at 95, got %d", got)
+	}
+	if got := AttributeCritBonus(&char); got != 20/LuckPerCrit {
+		t.Errorf("unexpected crit bonus %d", got)
+	}
+
+	fireball := models.Skill{Damage: 20, DamageType: models.Fire}
+	if got := SkillPower(&char, fireball); got != 20*(100+5*SkillPctPerPoint)/100 {
+		t.Errorf("intelligence should boost elemental skills, got %d", got)
+	}
+
+	item := GenerateItem(6)
+	if AttributeSum(item.StatsMod.Attributes) < 3 {
+		t.Errorf("rarity 6 item should roll attribute bonuses, got %+v", item.StatsMod.Attributes)
+	}
+}
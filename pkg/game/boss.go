@@ -0,0 +1,266 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// AddInterceptPct is how much of the damage dealt to a boss its living adds
+// throw themselves in front of.
+const AddInterceptPct = 50
+
+// AddHPPct is the least health a summoned add has, as a share of its boss's.
+const AddHPPct = 10
+
+// FindBoss looks up a boss definition by ID.
+func FindBoss(id string) (models.BossDefinition, bool) {
+	def, ok := data.BossDefinitions[id]
+	return def, ok && len(def.Phases) > 0
+}
+
+// DungeonBossID is the boss definition for a dungeon boss on floor.
+func DungeonBossID(floor int) string {
+	if floor >= data.DeepDungeonBossFloor {
+		return "abyssal_overlord"
+	}
+	return "dungeon_warlord"
+}
+
+// bossText fills in a boss line's "{name}".
+func bossText(text, name string) string {
+	return strings.ReplaceAll(text, "{name}", name)
+}
+
+// BossFight tracks a scripted boss encounter from turn to turn: its phase,
+// a telegraphed attack being wound up, summoned adds and the enrage timer.
+type BossFight struct {
+	Def     models.BossDefinition
+	Phase   int
+	WindUp  bool // the phase's telegraphed attack lands on the boss's next turn
+	Adds    []models.Monster
+	Enraged bool
+	LastHP  int // the boss's health at the end of its last turn
+}
+
+// NewBossFight starts the encounter for mob, or returns nil when mob has no
+// boss definition.
+func NewBossFight(mob *models.Monster) *BossFight {
+	def, ok := FindBoss(mob.BossID)
+	if !ok {
+		return nil
+	}
+	return &BossFight{Def: def, LastHP: mob.HitpointsRemaining}
+}
+
+// CurrentPhase is the phase the fight is in.
+func (b *BossFight) CurrentPhase() models.BossPhase {
+	return b.Def.Phases[b.Phase]
+}
+
+// LivingAdds counts the summoned adds still standing.
+func (b *BossFight) LivingAdds() int {
+	n := 0
+	for _, a := range b.Adds {
+		if a.HitpointsRemaining > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// EnrageIn is how many turns are left before the boss enrages, or -1 when it
+// never will or already has.
+func (b *BossFight) EnrageIn(turn int) int {
+	if b.Def.EnrageTurn <= 0 || b.Enraged {
+		return -1
+	}
+	return max(b.Def.EnrageTurn-turn, 0)
+}
+
+// Turn plays the scripted part of the boss's turn against player, before its
+// ordinary move: adds soak up damage the boss took since its last turn, the
+// next phase begins once the boss is hurt enough, the enrage timer runs out,
+// a telegraphed attack is wound up or unleashed, and the adds attack.
+// defending is whether the player took the Defend action this turn and
+// stunned whether the boss is stunned, which interrupts a wind-up. It
+// reports whether the boss used its turn.
+func (b *BossFight) Turn(mob *models.Monster, player *models.Character, turn int, defending, stunned bool) ([]StatusMessage, bool) {
+	msgs := b.interceptDamage(mob)
+	msgs = append(msgs, b.advancePhase(mob)...)
+
+	if b.Def.EnrageTurn > 0 && turn >= b.Def.EnrageTurn && !b.Enraged {
+		b.Enraged = true
+		mob.StatsMod.AttackMod += b.Def.EnrageAttack
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("%s has run out of patience and becomes ENRAGED! (+%d attack)", mob.Name, b.Def.EnrageAttack), "boss"})
+	}
+
+	acted := false
+	tele := b.CurrentPhase().Telegraph
+	switch {
+	case stunned && b.WindUp:
+		b.WindUp = false
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("%s's %s is interrupted!", mob.Name, tele.Name), "buff"})
+	case stunned:
+	case b.WindUp:
+		b.WindUp = false
+		msgs = append(msgs, b.unleash(mob, player, tele, defending)...)
+		acted = true
+	case tele.Every > 0 && turn%tele.Every == 0:
+		b.WindUp = true
+		msgs = append(msgs, StatusMessage{bossText(tele.Warning, mob.Name), "boss"})
+		acted = true
+	}
+
+	msgs = append(msgs, b.addsAttack(player)...)
+	b.LastHP = mob.HitpointsRemaining
+	return msgs, acted
+}
+
+// interceptDamage has the living adds take their share of the damage dealt
+// to the boss since its last turn.
+func (b *BossFight) interceptDamage(mob *models.Monster) []StatusMessage {
+	taken := b.LastHP - mob.HitpointsRemaining
+	if taken <= 0 || mob.HitpointsRemaining <= 0 {
+		return nil
+	}
+	msgs := []StatusMessage{}
+	soak := taken * AddInterceptPct / 100
+	for i := range b.Adds {
+		add := &b.Adds[i]
+		if soak <= 0 || add.HitpointsRemaining <= 0 {
+			continue
+		}
+		dmg := min(soak, add.HitpointsRemaining)
+		soak -= dmg
+		add.HitpointsRemaining -= dmg
+		mob.HitpointsRemaining += dmg
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("%s throws itself in front of %s and takes %d damage!", add.Name, mob.Name, dmg), "combat"})
+		if add.HitpointsRemaining <= 0 {
+			msgs = append(msgs, StatusMessage{fmt.Sprintf("%s falls!", add.Name), "combat"})
+		}
+	}
+	return msgs
+}
+
+// advancePhase moves into every phase whose health threshold the boss has
+// dropped below, applying its bonuses and summoning its adds.
+func (b *BossFight) advancePhase(mob *models.Monster) []StatusMessage {
+	msgs := []StatusMessage{}
+	for b.Phase+1 < len(b.Def.Phases) && hpPercent(mob) < b.Def.Phases[b.Phase+1].BelowPct {
+		b.Phase++
+		b.WindUp = false
+		phase := b.CurrentPhase()
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("=== Phase %d: %s ===", b.Phase+1, phase.Name), "boss"})
+		msgs = append(msgs, StatusMessage{bossText(phase.Narrative, mob.Name), "boss"})
+		mob.StatsMod.AttackMod += phase.AttackBonus
+		mob.StatsMod.DefenseMod += phase.DefenseBonus
+		if phase.Summon != "" && phase.SummonCount > 0 {
+			for i := 0; i < phase.SummonCount; i++ {
+				add := GenerateMonster(phase.Summon, max(mob.Level/2, 1), 1)
+				add.Name = fmt.Sprintf("%s %d", add.Name, len(b.Adds)+1)
+				add.HitpointsTotal = max(add.HitpointsTotal, mob.HitpointsTotal*AddHPPct/100)
+				add.HitpointsRemaining = add.HitpointsTotal
+				b.Adds = append(b.Adds, add)
+			}
+			msgs = append(msgs, StatusMessage{fmt.Sprintf("%s summons %d %s to its side!", mob.Name, phase.SummonCount, phase.Summon), "boss"})
+		}
+	}
+	return msgs
+}
+
+// unleash lands a wound-up telegraphed attack on player.
+func (b *BossFight) unleash(mob *models.Monster, player *models.Character, tele models.BossTelegraph, defending bool) []StatusMessage {
+	element := tele.DamageType
+	if element == "" {
+		element = models.Physical
+	}
+	damage := (MultiRoll(mob.AttackRolls) + AttackMod(mob)) * tele.DamagePct / 100
+	msgs := []StatusMessage{{fmt.Sprintf("%s unleashes %s!", mob.Name, tele.Name), "boss"}}
+	if defending {
+		damage = damage * tele.DefendPct / 100
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("%s braced for it!", player.Name), "buff"})
+	}
+	final := ApplyDamage(max(damage, 1), element, player)
+	player.HitpointsRemaining -= final
+	msgs = append(msgs, StatusMessage{fmt.Sprintf("%s takes %d damage!", player.Name, final), "damage"})
+	if !defending && tele.Effect.Type != "" {
+		if msg, _ := ApplyStatus(player, tele.Effect); msg.Text != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// addsAttack has each living add take a swing at player.
+func (b *BossFight) addsAttack(player *models.Character) []StatusMessage {
+	msgs := []StatusMessage{}
+	for i := range b.Adds {
+		add := &b.Adds[i]
+		if add.HitpointsRemaining <= 0 || player.HitpointsRemaining <= 0 {
+			continue
+		}
+		attack := MultiRoll(add.AttackRolls) + AttackMod(add)
+		defense := MultiRoll(player.DefenseRolls) + DefenseMod(player)
+		if attack <= defense {
+			msgs = append(msgs, StatusMessage{fmt.Sprintf("%s misses %s.", add.Name, player.Name), "combat"})
+			continue
+		}
+		final := ApplyDamage(attack-defense, models.Physical, player)
+		player.HitpointsRemaining -= final
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("%s hits %s for %d damage!", add.Name, player.Name, final), "damage"})
+	}
+	return msgs
+}
+
+// RollBossLoot rolls a boss definition's loot table, returning the unique
+// items that dropped.
+func RollBossLoot(def models.BossDefinition) []models.Item {
+	items := []models.Item{}
+	for _, drop := range def.Loot {
+		if rand.Intn(100) >= drop.Chance {
+			continue
+		}
+		for _, u := range UniqueItems {
+			if u.Name == drop.Unique {
+				items = append(items, CreateUniqueItem(u, max(drop.Rarity, u.MinRarity)))
+				break
+			}
+		}
+	}
+	return items
+}
+
+// TideLeaderPhase moves the tide leader into every phase whose health
+// threshold it has dropped below, returning the narrative lines.
+func TideLeaderPhase(leader *models.TideLeader) []string {
+	def, ok := FindBoss("tide_leader")
+	if !ok || leader.HitpointsTotal <= 0 {
+		return nil
+	}
+	lines := []string{}
+	for leader.Phase+1 < len(def.Phases) && leader.HitpointsRemaining*100/leader.HitpointsTotal < def.Phases[leader.Phase+1].BelowPct {
+		leader.Phase++
+		phase := def.Phases[leader.Phase]
+		lines = append(lines, fmt.Sprintf("=== %s enters phase %d: %s ===", leader.Name, leader.Phase+1, phase.Name),
+			bossText(phase.Narrative, leader.Name))
+	}
+	return lines
+}
+
+// TideLeaderAttackBonus is the extra retaliation damage the tide leader's
+// phases so far give it.
+func TideLeaderAttackBonus(leader *models.TideLeader) int {
+	def, ok := FindBoss("tide_leader")
+	if !ok {
+		return 0
+	}
+	bonus := 0
+	for i := 0; i <= leader.Phase && i < len(def.Phases); i++ {
+		bonus += def.Phases[i].AttackBonus
+	}
+	return bonus
+}
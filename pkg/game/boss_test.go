@@ -0,0 +1,108 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func testBoss() models.Monster {
+	boss := GenerateDungeonBoss(5, 10, 2)
+	boss.HitpointsTotal, boss.HitpointsRemaining = 1000, 1000
+	return boss
+}
+
+func TestBossPhasesAndAdds(t *testing.T) {
+	boss := testBoss()
+	player := GenerateCharacter("Hero", 1, 1)
+	player.HitpointsTotal, player.HitpointsRemaining = 100000, 100000
+	fight := NewBossFight(&boss)
+	if fight == nil || boss.BossID != "dungeon_warlord" {
+		t.Fatalf("a floor 5 dungeon boss should be a scripted warlord, got %q", boss.BossID)
+	}
+
+	attack := AttackMod(&boss)
+	boss.HitpointsRemaining = 550
+	fight.Turn(&boss, &player, 1, false, false)
+	if fight.CurrentPhase().Name != "War Band" || fight.LivingAdds() != 2 {
+		t.Fatalf("at 55%% HP the warlord should call its war band, got phase %q with %d adds", fight.CurrentPhase().Name, fight.LivingAdds())
+	}
+	if AttackMod(&boss) != attack+3 {
+		t.Errorf("the war band phase should add 3 attack, %d -> %d", attack, AttackMod(&boss))
+	}
+
+	hp := boss.HitpointsRemaining
+	boss.HitpointsRemaining -= 40
+	fight.Turn(&boss, &player, 2, false, false)
+	if boss.HitpointsRemaining != hp-20 {
+		t.Errorf("adds should soak half of the 40 damage, HP %d -> %d", hp, boss.HitpointsRemaining)
+	}
+
+	boss.HitpointsRemaining, fight.LastHP = 100, 100
+	fight.Turn(&boss, &player, 3, false, false)
+	if fight.Phase != 2 {
+		t.Errorf("at 10%% HP the warlord should make its last stand, got phase %d", fight.Phase)
+	}
+}
+
+func TestBossTelegraphAndDefend(t *testing.T) {
+	boss := testBoss()
+	player := GenerateCharacter("Hero", 1, 1)
+	player.HitpointsTotal, player.HitpointsRemaining = 100000, 100000
+	fight := NewBossFight(&boss)
+
+	if _, acted := fight.Turn(&boss, &player, 4, false, false); !acted || !fight.WindUp {
+		t.Fatal("on turn 4 the warlord should wind up its Crushing Blow")
+	}
+	if _, acted := fight.Turn(&boss, &player, 5, true, false); !acted || fight.WindUp {
+		t.Fatal("the Crushing Blow should land on the next turn")
+	}
+	if IsStunned(&player) {
+		t.Error("defending should avoid the stun")
+	}
+
+	fight.Turn(&boss, &player, 8, false, false)
+	fight.Turn(&boss, &player, 9, false, false)
+	if !IsStunned(&player) {
+		t.Error("an undefended Crushing Blow should stun")
+	}
+
+	fight.Turn(&boss, &player, 12, false, false)
+	if _, acted := fight.Turn(&boss, &player, 13, false, true); acted || fight.WindUp {
+		t.Error("stunning the boss should interrupt its wind-up")
+	}
+}
+
+func TestBossEnrageTimer(t *testing.T) {
+	boss := testBoss()
+	player := GenerateCharacter("Hero", 1, 1)
+	player.HitpointsTotal, player.HitpointsRemaining = 100000, 100000
+	fight := NewBossFight(&boss)
+	attack := AttackMod(&boss)
+
+	if fight.EnrageIn(15) != 5 {
+		t.Fatalf("expected 5 turns to enrage, got %d", fight.EnrageIn(15))
+	}
+	fight.Turn(&boss, &player, 21, false, false)
+	if !fight.Enraged || AttackMod(&boss) != attack+10 || fight.EnrageIn(22) != -1 {
+		t.Errorf("the warlord should enrage after turn 20, attack %d -> %d", attack, AttackMod(&boss))
+	}
+}
+
+func TestBossLootAndTideLeaderPhases(t *testing.T) {
+	def := models.BossDefinition{Phases: []models.BossPhase{{Name: "Only"}},
+		Loot: []models.BossDrop{{Unique: "Warlord's Cleaver", Rarity: 6, Chance: 100}}}
+	items := RollBossLoot(def)
+	if len(items) != 1 || !items[0].Unique || items[0].Name != "Warlord's Cleaver" {
+		t.Fatalf("expected the Warlord's Cleaver, got %+v", items)
+	}
+
+	leader := GenerateTideLeader(1, 1, 0)
+	leader.HitpointsRemaining = leader.HitpointsTotal / 4
+	if lines := TideLeaderPhase(&leader); leader.Phase != 2 || len(lines) != 4 {
+		t.Errorf("a quartered tide leader should reach its Maelstrom, got phase %d: %v", leader.Phase, lines)
+	}
+	if TideLeaderAttackBonus(&leader) != 15 {
+		t.Errorf("expected +15 retaliation, got %d", TideLeaderAttackBonus(&leader))
+	}
+}
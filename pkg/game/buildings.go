@@ -0,0 +1,357 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"rpg-game/pkg/models"
+)
+
+// Village building names.
+const (
+	BuildingFarm       = "Farm"
+	BuildingHouses     = "Houses"
+	BuildingWarehouse  = "Warehouse"
+	BuildingBarracks   = "Barracks"
+	BuildingAlchemist  = "Alchemist"
+	BuildingLibrary    = "Library"
+	BuildingWatchtower = "Watchtower"
+	BuildingTraining   = "Training Grounds"
+	BuildingBlacksmith = "Blacksmith"
+	BuildingEnchanting = "Enchanting Station"
+)
+
+// Village building effects, per building level.
+const (
+	MaxBuildingLevel = 5
+
+	FarmFoodPerLevel           = 4
+	FarmHarvestPercent         = 10 // extra harvester yield
+	HousesPerLevel             = 3
+	WarehouseFoodPerLevel      = 30
+	WarehouseStashPerLevel     = 5
+	BarracksGuardsPerLevel     = 2
+	WorkshopQualityPerLevel    = 5   // quality roll for the crafts an alchemist or library unlocks
+	WatchtowerWarningPerLevel  = 300 // seconds of warning before a tide
+	WatchtowerShelterPerLevel  = 5   // taken off TideInjuryChance
+	TrainingXPPerLevel         = 10  // XP each hired guard gains per manager tick
+	BlacksmithDiscountPerLevel = 5   // % off repair gold, on top of the village level discount
+	EnchantingChancePerLevel   = 3   // % enchant success for each level past the first
+)
+
+// BuildingBlueprint is a building the village can raise and level up.
+type BuildingBlueprint struct {
+	Name            string
+	Description     string
+	Cost            map[string]int // for level 1; level n costs n times as much
+	Upkeep          map[string]int // per level, each village manager tick
+	MinVillageLevel int
+	Unlocks         []string // crafting types opened up once built
+}
+
+// BuildingBlueprints lists the buildings in the village build menu.
+var BuildingBlueprints = []BuildingBlueprint{
+	{BuildingFarm, fmt.Sprintf("+%d food per tick and +%d%% harvest per level", FarmFoodPerLevel, FarmHarvestPercent),
+		map[string]int{"Lumber": 40, "Stone": 10}, map[string]int{"Lumber": 2}, 1, nil},
+	{BuildingHouses, fmt.Sprintf("+%d housing per level", HousesPerLevel),
+		map[string]int{"Lumber": 60, "Stone": 30}, map[string]int{"Lumber": 2}, 1, nil},
+	{BuildingWarehouse, fmt.Sprintf("+%d food storage and +%d stash slots per level", WarehouseFoodPerLevel, WarehouseStashPerLevel),
+		map[string]int{"Lumber": 50, "Stone": 50}, map[string]int{"Lumber": 1, "Stone": 1}, 1, nil},
+	{BuildingWatchtower, fmt.Sprintf("warns of tides %d minutes early and shelters villagers (-%d%% injuries) per level",
+		WatchtowerWarningPerLevel/60, WatchtowerShelterPerLevel),
+		map[string]int{"Lumber": 60, "Stone": 30, "Iron": 10}, map[string]int{"Lumber": 2}, 2, nil},
+	{BuildingAlchemist, fmt.Sprintf("unlocks potions, +%d potion quality per level", WorkshopQualityPerLevel),
+		map[string]int{"Lumber": 30, "Stone": 40, "Sand": 40}, map[string]int{"Gold": 3, "Sand": 2}, 2, []string{"potions"}},
+	{BuildingBarracks, fmt.Sprintf("+%d guard capacity per level", BarracksGuardsPerLevel),
+		map[string]int{"Lumber": 40, "Stone": 60, "Iron": 30}, map[string]int{"Gold": 5}, 3, nil},
+	{BuildingLibrary, fmt.Sprintf("unlocks skill scrolls and upgrades, +%d quality per level", WorkshopQualityPerLevel),
+		map[string]int{"Lumber": 80, "Stone": 40, "Gold": 50}, map[string]int{"Gold": 3}, 4, []string{"skill_scrolls", "skill_upgrades"}},
+	{BuildingTraining, fmt.Sprintf("hired guards drill for +%d XP per tick per level", TrainingXPPerLevel),
+		map[string]int{"Lumber": 30, "Stone": 10}, map[string]int{"Gold": 2}, 1, nil},
+	{BuildingBlacksmith, fmt.Sprintf("guard gear repairs, repair kits and elite recipes, +%d%% repair discount per level", BlacksmithDiscountPerLevel),
+		map[string]int{"Lumber": 10, "Stone": 30}, map[string]int{"Iron": 1}, 1, nil},
+	{BuildingEnchanting, fmt.Sprintf("sets runes and scrolls into gear, +%d%% enchant success per level past the first", EnchantingChancePerLevel),
+		map[string]int{"Stone": 20, "Sand": 20, "Iron": 10}, map[string]int{"Sand": 1}, 1, nil},
+}
+
+// FindBuildingBlueprint looks up an entry of BuildingBlueprints by name.
+func FindBuildingBlueprint(name string) (BuildingBlueprint, bool) {
+	for _, bp := range BuildingBlueprints {
+		if bp.Name == name {
+			return bp, true
+		}
+	}
+	return BuildingBlueprint{}, false
+}
+
+// FindVillageBuilding is the village's building of that name, or nil.
+func FindVillageBuilding(village *models.Village, name string) *models.VillageBuilding {
+	for i := range village.Buildings {
+		if village.Buildings[i].Name == name {
+			return &village.Buildings[i]
+		}
+	}
+	return nil
+}
+
+// BuildingLevel is the level of a working building, 0 if it isn't built or
+// its upkeep is unpaid.
+func BuildingLevel(village *models.Village, name string) int {
+	b := FindVillageBuilding(village, name)
+	if b == nil || b.Unpaid {
+		return 0
+	}
+	return b.Level
+}
+
+// HasBuilding reports whether the village has a working building of that
+// name.
+func HasBuilding(village *models.Village, name string) bool {
+	return village != nil && BuildingLevel(village, name) > 0
+}
+
+// MigrateBuiltBuildings moves buildings from the character-level list older
+// saves kept into the character's home village, at level 1, and reports
+// whether anything moved. The list is cleared, so it runs once.
+func MigrateBuiltBuildings(player *models.Character, village *models.Village) bool {
+	if len(player.BuiltBuildings) == 0 {
+		return false
+	}
+	for _, old := range player.BuiltBuildings {
+		if _, ok := FindBuildingBlueprint(old.Name); ok && FindVillageBuilding(village, old.Name) == nil {
+			raiseBuilding(village, old.Name)
+		}
+	}
+	player.BuiltBuildings = nil
+	return true
+}
+
+// TrainGuards drills the village's hired guards at its training grounds.
+// Returns a line for each guard that levels up.
+func TrainGuards(village *models.Village) []string {
+	xp := BuildingLevel(village, BuildingTraining) * TrainingXPPerLevel
+	if xp == 0 {
+		return nil
+	}
+	var messages []string
+	for i := range village.ActiveGuards {
+		guard := &village.ActiveGuards[i]
+		if guard.Injured {
+			continue
+		}
+		for _, m := range GainGuardXP(guard, xp) {
+			messages = append(messages, m.Text)
+		}
+	}
+	return messages
+}
+
+// BuildingCost is what raising a building to level costs.
+func BuildingCost(bp BuildingBlueprint, level int) map[string]int {
+	cost := map[string]int{}
+	for name, n := range bp.Cost {
+		cost[name] = n * level
+	}
+	return cost
+}
+
+// BuildingUpkeep is what a building at level costs each manager tick.
+func BuildingUpkeep(bp BuildingBlueprint, level int) map[string]int {
+	upkeep := map[string]int{}
+	for name, n := range bp.Upkeep {
+		upkeep[name] = n * level
+	}
+	return upkeep
+}
+
+// PlannedBuildingLevel is the level a building reaches once its queued
+// construction is done.
+func PlannedBuildingLevel(village *models.Village, name string) int {
+	level := 0
+	if b := FindVillageBuilding(village, name); b != nil {
+		level = b.Level
+	}
+	for _, job := range village.Jobs {
+		if job.Kind == JobConstruct && job.Building == name {
+			level++
+		}
+	}
+	return level
+}
+
+// nextBuildingLevel checks the village can raise a building and returns
+// the level it would reach and what that costs.
+func nextBuildingLevel(player *models.Character, village *models.Village, bp BuildingBlueprint) (int, map[string]int, error) {
+	if village.Level < bp.MinVillageLevel {
+		return 0, nil, fmt.Errorf("the %s needs village level %d", bp.Name, bp.MinVillageLevel)
+	}
+	level := PlannedBuildingLevel(village, bp.Name) + 1
+	if level > MaxBuildingLevel {
+		return 0, nil, fmt.Errorf("the %s is already at its highest level", bp.Name)
+	}
+	cost := BuildingCost(bp, level)
+	return level, cost, checkCost(player, cost)
+}
+
+// BuildBuilding raises a building a level at once, for the terminal game.
+func BuildBuilding(player *models.Character, village *models.Village, bp BuildingBlueprint) (string, error) {
+	_, cost, err := nextBuildingLevel(player, village, bp)
+	if err != nil {
+		return "", err
+	}
+	payCost(player, cost, ReasonBuilding, "building:"+bp.Name)
+	return raiseBuilding(village, bp.Name), nil
+}
+
+// QueueBuilding pays to raise a building its next level, constructing it
+// if the village has none, and queues the work for the builders.
+func QueueBuilding(player *models.Character, village *models.Village, bp BuildingBlueprint) (models.VillageJob, error) {
+	if err := checkQueue(village); err != nil {
+		return models.VillageJob{}, err
+	}
+	level, cost, err := nextBuildingLevel(player, village, bp)
+	if err != nil {
+		return models.VillageJob{}, err
+	}
+	payCost(player, cost, ReasonBuilding, "building:"+bp.Name)
+	name := bp.Name
+	if level > 1 {
+		name = fmt.Sprintf("%s to level %d", bp.Name, level)
+	}
+	return addJob(village, models.VillageJob{Kind: JobConstruct, Name: name, Building: bp.Name,
+		Cost: cost, Work: costWork(cost)}), nil
+}
+
+// raiseBuilding finishes a construction job: the building gains a level
+// and unlocks its crafting types.
+func raiseBuilding(village *models.Village, name string) string {
+	bp, _ := FindBuildingBlueprint(name)
+	b := FindVillageBuilding(village, name)
+	if b == nil {
+		village.Buildings = append(village.Buildings, models.VillageBuilding{Name: name})
+		b = &village.Buildings[len(village.Buildings)-1]
+	}
+	b.Level++
+	xp := 25 * b.Level
+	village.Experience += xp
+	for _, craft := range bp.Unlocks {
+		if !Contains(village.UnlockedCrafting, craft) {
+			village.UnlockedCrafting = append(village.UnlockedCrafting, craft)
+		}
+	}
+	if b.Level == 1 {
+		return fmt.Sprintf("Your builders finished the %s! (+%d Village XP)", name, xp)
+	}
+	return fmt.Sprintf("Your builders raised the %s to level %d! (+%d Village XP)", name, b.Level, xp)
+}
+
+// PayBuildingUpkeep charges each building its upkeep for a manager tick. A
+// building the owner can't pay for stands idle until a later tick is paid.
+// Returns a line whenever a building stops or starts working.
+func PayBuildingUpkeep(village *models.Village, player *models.Character) []string {
+	var messages []string
+	for i := range village.Buildings {
+		b := &village.Buildings[i]
+		bp, ok := FindBuildingBlueprint(b.Name)
+		if !ok {
+			continue
+		}
+		upkeep := BuildingUpkeep(bp, b.Level)
+		if err := checkCost(player, upkeep); err != nil {
+			if !b.Unpaid {
+				messages = append(messages, fmt.Sprintf("The %s stands idle: %v for its upkeep.", b.Name, err))
+			}
+			b.Unpaid = true
+			continue
+		}
+		payCost(player, upkeep, ReasonUpkeep, "building:"+b.Name)
+		if b.Unpaid {
+			messages = append(messages, fmt.Sprintf("The %s is paid up and working again.", b.Name))
+		}
+		b.Unpaid = false
+	}
+	return messages
+}
+
+// TotalBuildingUpkeep sums the upkeep of every building in the village.
+func TotalBuildingUpkeep(village *models.Village) map[string]int {
+	total := map[string]int{}
+	for _, b := range village.Buildings {
+		bp, _ := FindBuildingBlueprint(b.Name)
+		for name, n := range BuildingUpkeep(bp, b.Level) {
+			total[name] += n
+		}
+	}
+	return total
+}
+
+// GuardCapacity is how many hired guards the village can keep: one per
+// village level, plus the barracks.
+func GuardCapacity(village *models.Village) int {
+	return max(village.Level, 1) + BuildingLevel(village, BuildingBarracks)*BarracksGuardsPerLevel
+}
+
+// HarvestBonus is the extra share of a harvest, in percent, the village's
+// farms add.
+func HarvestBonus(village *models.Village) int {
+	return BuildingLevel(village, BuildingFarm) * FarmHarvestPercent
+}
+
+// CraftingQualityBonus is what the village's workshops add to the quality
+// roll of a craft type.
+func CraftingQualityBonus(village *models.Village, craftType string) int {
+	bonus := 0
+	for _, bp := range BuildingBlueprints {
+		if Contains(bp.Unlocks, craftType) {
+			bonus += BuildingLevel(village, bp.Name) * WorkshopQualityPerLevel
+		}
+	}
+	return bonus
+}
+
+// tideInjuryChance is the chance a breach hits a villager, lowered by the
+// shelter a watchtower's warning buys.
+func tideInjuryChance(village *models.Village) int {
+	return max(TideInjuryChance-BuildingLevel(village, BuildingWatchtower)*WatchtowerShelterPerLevel, 5)
+}
+
+// nextTideTime is when the village's next tide is due.
+func nextTideTime(village *models.Village) int64 {
+	interval := int64(village.TideInterval)
+	if interval <= 0 {
+		interval = 3600
+	}
+	return village.LastTideTime + interval
+}
+
+// TideWarning returns the watchtower's warning of a tide due within its
+// warning window, once per tide, or "" if there is nothing to report.
+func TideWarning(village *models.Village, now int64) string {
+	level := BuildingLevel(village, BuildingWatchtower)
+	due := nextTideTime(village)
+	if level == 0 || now >= due || now < due-int64(level*WatchtowerWarningPerLevel) ||
+		village.TideWarnedFor == village.LastTideTime {
+		return ""
+	}
+	village.TideWarnedFor = village.LastTideTime
+	return fmt.Sprintf("Lookouts on the watchtower in %s spot a monster tide! It arrives in about %d minutes.",
+		village.Name, max((due-now)/60, 1))
+}
+
+// BuildingsText lists the village's buildings for display.
+func BuildingsText(village *models.Village) string {
+	if len(village.Buildings) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(village.Buildings))
+	for _, b := range village.Buildings {
+		text := fmt.Sprintf("%s Lv%d", b.Name, b.Level)
+		if b.Unpaid {
+			text += " (idle)"
+		}
+		names = append(names, text)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
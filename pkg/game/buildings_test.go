@@ -0,0 +1,157 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestQueueBuildingFinishesOnTicks(t *testing.T) {
+	player := GenerateCharacter("Farmer", 1, 1)
+	village := models.Village{Name: "Glen", Level: 2,
+		Villagers: []models.Villager{{Name: "Bo", Role: "builder", Level: 10, Efficiency: 20, AssignedTask: TaskBuilding}}}
+	bp, _ := FindBuildingBlueprint(BuildingFarm)
+	food := FoodProduction(&village)
+
+	for level := 1; level <= 2; level++ {
+		fundCost(&player, BuildingCost(bp, level))
+		if _, err := QueueBuilding(&player, &village, bp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if BuildingLevel(&village, BuildingFarm) != 0 || PlannedBuildingLevel(&village, BuildingFarm) != 2 {
+		t.Fatal("queued construction shouldn't count until it is finished")
+	}
+	for i := 0; i < 20 && len(village.Jobs) > 0; i++ {
+		WorkVillageJobs(&player, &village)
+	}
+	if BuildingLevel(&village, BuildingFarm) != 2 || FoodProduction(&village) != food+2*FarmFoodPerLevel {
+		t.Errorf("expected a level 2 farm feeding the village, got %+v", village.Buildings)
+	}
+	if HarvestBonus(&village) != 2*FarmHarvestPercent {
+		t.Errorf("expected a %d%% harvest bonus, got %d", 2*FarmHarvestPercent, HarvestBonus(&village))
+	}
+
+	library, _ := FindBuildingBlueprint(BuildingLibrary)
+	fundCost(&player, library.Cost)
+	if _, err := QueueBuilding(&player, &village, library); err == nil {
+		t.Error("the library should need a higher village level")
+	}
+}
+
+func TestBuildingEffects(t *testing.T) {
+	player := GenerateCharacter("Mason", 1, 1)
+	village := models.Village{Name: "Glen", Level: 4}
+	base := models.Village{Name: "Glen", Level: 4}
+	for _, name := range []string{BuildingHouses, BuildingWarehouse, BuildingBarracks, BuildingAlchemist} {
+		bp, _ := FindBuildingBlueprint(name)
+		fundCost(&player, bp.Cost)
+		if _, err := BuildBuilding(&player, &village, bp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if HousingCapacity(&village) != HousingCapacity(&base)+HousesPerLevel {
+		t.Error("houses should add housing")
+	}
+	if FoodStorage(&village) != FoodStorage(&base)+WarehouseFoodPerLevel ||
+		StashCapacity(&village) != StashCapacity(&base)+WarehouseStashPerLevel {
+		t.Error("a warehouse should raise food storage and the stash")
+	}
+	if GuardCapacity(&village) != GuardCapacity(&base)+BarracksGuardsPerLevel {
+		t.Error("a barracks should house more guards")
+	}
+	if !Contains(village.UnlockedCrafting, "potions") || CraftingQualityBonus(&village, "potions") != WorkshopQualityPerLevel ||
+		CraftingQualityBonus(&village, "armor") != 0 {
+		t.Errorf("an alchemist should unlock and improve potions only, got %v", village.UnlockedCrafting)
+	}
+}
+
+func TestBuildingUpkeep(t *testing.T) {
+	player := GenerateCharacter("Mason", 1, 1)
+	AdjustGold(&player, -ResourceBalance(&player, "Gold"), ReasonMonsterDrop, "test")
+	village := models.Village{Name: "Glen", Level: 3, Buildings: []models.VillageBuilding{{Name: BuildingBarracks, Level: 2}}}
+	capacity := GuardCapacity(&village)
+
+	if msgs := PayBuildingUpkeep(&village, &player); len(msgs) != 1 || BuildingLevel(&village, BuildingBarracks) != 0 {
+		t.Fatalf("an unpaid barracks should stand idle, got %v", msgs)
+	}
+	if GuardCapacity(&village) != capacity-2*BarracksGuardsPerLevel {
+		t.Error("an idle barracks shouldn't house guards")
+	}
+	if msgs := PayBuildingUpkeep(&village, &player); len(msgs) != 0 {
+		t.Errorf("an idle building should only be reported once, got %v", msgs)
+	}
+
+	bp, _ := FindBuildingBlueprint(BuildingBarracks)
+	upkeep := BuildingUpkeep(bp, 2)
+	AdjustGold(&player, upkeep["Gold"], ReasonMonsterDrop, "test")
+	if msgs := PayBuildingUpkeep(&village, &player); len(msgs) != 1 || GuardCapacity(&village) != capacity {
+		t.Errorf("a paid barracks should work again, got %v", msgs)
+	}
+	if ResourceBalance(&player, "Gold") != 0 {
+		t.Errorf("expected the upkeep spent, %d gold left", ResourceBalance(&player, "Gold"))
+	}
+}
+
+func TestTideWarning(t *testing.T) {
+	village := models.Village{Name: "Glen", Level: 2, LastTideTime: 1000, TideInterval: 3600}
+	due := int64(1000 + 3600)
+	if TideWarning(&village, due-60) != "" {
+		t.Error("no watchtower, no warning")
+	}
+
+	village.Buildings = []models.VillageBuilding{{Name: BuildingWatchtower, Level: 2}}
+	if TideWarning(&village, due-int64(2*WatchtowerWarningPerLevel)-1) != "" {
+		t.Error("the tide is still beyond the watchtower's sight")
+	}
+	if TideWarning(&village, due-60) == "" {
+		t.Fatal("expected a warning inside the watchtower's window")
+	}
+	if TideWarning(&village, due-30) != "" {
+		t.Error("each tide should only be warned about once")
+	}
+	if tideInjuryChance(&village) != TideInjuryChance-2*WatchtowerShelterPerLevel {
+		t.Error("a watchtower should shelter villagers from tides")
+	}
+}
+
+func TestOldBuildingsMoveIntoTheVillage(t *testing.T) {
+	player := GenerateCharacter("Mason", 1, 1)
+	player.BuiltBuildings = []models.Building{{Name: BuildingBlacksmith}, {Name: BuildingTraining}}
+	village := models.Village{Name: "Glen", Level: 1, Buildings: []models.VillageBuilding{{Name: BuildingTraining, Level: 3}}}
+
+	if !MigrateBuiltBuildings(&player, &village) || player.BuiltBuildings != nil {
+		t.Fatal("old buildings should move off the character")
+	}
+	if BuildingLevel(&village, BuildingBlacksmith) != 1 || BuildingLevel(&village, BuildingTraining) != 3 {
+		t.Errorf("expected a level 1 blacksmith and the training grounds kept, got %+v", village.Buildings)
+	}
+	if MigrateBuiltBuildings(&player, &village) {
+		t.Error("a second load should have nothing to move")
+	}
+}
+
+func TestWorkshopBuildingEffects(t *testing.T) {
+	village := models.Village{Name: "Glen", Level: 1,
+		ActiveGuards: []models.Guard{{Name: "Bram", Level: 1}, {Name: "Wren", Level: 1, Injured: true}}}
+	discount := RepairDiscount(&village)
+	TrainGuards(&village)
+	if village.ActiveGuards[0].Experience != 0 {
+		t.Error("guards shouldn't train without training grounds")
+	}
+
+	village.Buildings = []models.VillageBuilding{{Name: BuildingTraining, Level: 2}, {Name: BuildingBlacksmith, Level: 2}}
+	TrainGuards(&village)
+	if village.ActiveGuards[0].Experience != 2*TrainingXPPerLevel || village.ActiveGuards[1].Experience != 0 {
+		t.Errorf("only the fit guard should drill, got %+v", village.ActiveGuards)
+	}
+	if RepairDiscount(&village) != discount+2*BlacksmithDiscountPerLevel {
+		t.Errorf("a level 2 blacksmith should add to the repair discount, got %d%%", RepairDiscount(&village))
+	}
+
+	village.Buildings[1].Unpaid = true
+	if HasBuilding(&village, BuildingBlacksmith) || RepairDiscount(&village) != discount {
+		t.Error("an idle blacksmith should do nothing")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/data"
 	"rpg-game/pkg/models"
 )
@@ -52,25 +53,23 @@ func GenerateCharacter(name string, level int, rank int) models.Character {
 
 // PlayerExpToLevel returns the total XP needed to reach the next level.
 // Scaled so ~30 even-level kills at low levels, increasing with level.
+// The curve coefficients come from the balance config.
 func PlayerExpToLevel(level int) int {
-	return level * (300 + level*10)
+	b := config.Current().Balance
+	return level * (b.XPCurveBase + level*b.XPCurvePerLevel)
 }
 
 func LevelUp(player *models.Character) {
 	for player.Experience >= PlayerExpToLevel(player.Level) {
 		player.Level++
+		applyClassLevelGains(player)
 		player.HitpointsNatural += MultiRoll(1)
-		player.HitpointsRemaining = player.HitpointsNatural
 		player.ManaNatural += MultiRoll(1) + 5
-		player.ManaTotal = player.ManaNatural
-		player.ManaRemaining = player.ManaTotal
 		player.StaminaNatural += MultiRoll(1) + 5
-		player.StaminaTotal = player.StaminaNatural
+		RecalculatePlayerStats(player)
+		player.HitpointsRemaining = player.HitpointsTotal
+		player.ManaRemaining = player.ManaTotal
 		player.StaminaRemaining = player.StaminaTotal
-		player.AttackRolls = player.Level/10 + 1
-		player.DefenseRolls = player.Level/10 + 1
-		player.StatsMod = CalculateItemMods(player.EquipmentMap)
-		player.HitpointsTotal = player.HitpointsNatural + player.StatsMod.HitPointMod
 		// Enforce minimum HP of 1 per level
 		if player.HitpointsTotal < player.Level {
 			player.HitpointsTotal = player.Level
@@ -87,6 +86,10 @@ func LevelUp(player *models.Character) {
 		fmt.Printf("LEVEL UP!!! Now level %d!\n", player.Level)
 		fmt.Printf("HP: %d, MP: %d, SP: %d\n", player.HitpointsTotal, player.ManaTotal, player.StaminaTotal)
 
+		if TalentPoints(player) > 0 {
+			fmt.Printf("Talent points available: %d\n", TalentPoints(player))
+		}
+
 		// Skills are now learned from defeating Skill Guardians, not automatic
 	}
 }
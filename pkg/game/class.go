@@ -0,0 +1,335 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// Talent effect kinds. Each talent rank adds its PerRank value to one of these.
+const (
+	TalentCritChance      = "crit_chance"      // +% player crit chance
+	TalentElementalDamage = "elemental_damage" // +% damage on non-physical skills
+	TalentHealPower       = "heal_power"       // +% healing from skills
+	TalentGuardAttack     = "guard_attack"     // +attack bonus for guards fighting alongside
+	TalentHarvestYield    = "harvest_yield"    // +% harvested resources (minimum +1 per rank)
+)
+
+// Talent is one node of a class talent tree.
+type Talent struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Effect        string `json:"effect"`
+	PerRank       int    `json:"per_rank"`
+	MaxRank       int    `json:"max_rank"`
+	RequiredLevel int    `json:"required_level"`
+}
+
+// CharacterClass describes a selectable class: bonuses on top of the rolled
+// starting resources, extra resources per level, starting skills, the skills
+// the class may learn and its talent tree.
+type CharacterClass struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	BaseHP        int      `json:"base_hp"`
+	BaseMana      int      `json:"base_mana"`
+	BaseStamina   int      `json:"base_stamina"`
+	HPPerLevel    int      `json:"hp_per_level"`
+	ManaPerLevel  int      `json:"mana_per_level"`
+	StaminaPerLvl int      `json:"stamina_per_level"`
+	StartSkills   []string `json:"start_skills"`
+	AllowedSkills []string `json:"allowed_skills"`
+	Talents       []Talent `json:"talents"`
+}
+
+// ClassOrder lists class IDs in display order.
+var ClassOrder = []string{"warrior", "mage", "ranger", "cleric"}
+
+// Classes holds every selectable class keyed by ID.
+var Classes = map[string]CharacterClass{
+	"warrior": {
+		ID:            "warrior",
+		Name:          "Warrior",
+		Description:   "Heavy hitter with the most hit points and stamina; leads guards into battle.",
+		BaseHP:        8,
+		BaseStamina:   15,
+		HPPerLevel:    3,
+		StaminaPerLvl: 3,
+		StartSkills:   []string{"Power Strike"},
+		AllowedSkills: []string{"Power Strike", "Shield Wall", "Battle Cry", "Regeneration", "Rend", "Silencing Strike", "Tracking"},
+		Talents: []Talent{
+			{ID: "brutality", Name: "Brutality", Description: "+2% critical hit chance per rank", Effect: TalentCritChance, PerRank: 2, MaxRank: 5},
+			{ID: "warlord", Name: "Warlord", Description: "+2 guard attack per rank", Effect: TalentGuardAttack, PerRank: 2, MaxRank: 5},
+			{ID: "quarryman", Name: "Quarryman", Description: "+10% harvest yield per rank", Effect: TalentHarvestYield, PerRank: 10, MaxRank: 3},
+			{ID: "executioner", Name: "Executioner", Description: "+3% critical hit chance per rank", Effect: TalentCritChance, PerRank: 3, MaxRank: 3, RequiredLevel: 10},
+		},
+	},
+	"mage": {
+		ID:            "mage",
+		Name:          "Mage",
+		Description:   "Fragile caster with a deep mana pool and elemental spells.",
+		BaseMana:      25,
+		ManaPerLevel:  5,
+		StartSkills:   []string{"Fireball"},
+		AllowedSkills: []string{"Fireball", "Ice Shard", "Lightning Bolt", "Heal", "Regeneration", "Frost Nova", "Meteor", "Arcane Barrier", "Dispel Magic", "Haste", "Tracking"},
+		Talents: []Talent{
+			{ID: "pyromancy", Name: "Pyromancy", Description: "+8% elemental skill damage per rank", Effect: TalentElementalDamage, PerRank: 8, MaxRank: 5},
+			{ID: "arcane_focus", Name: "Arcane Focus", Description: "+2% critical hit chance per rank", Effect: TalentCritChance, PerRank: 2, MaxRank: 3},
+			{ID: "transmutation", Name: "Transmutation", Description: "+10% harvest yield per rank", Effect: TalentHarvestYield, PerRank: 10, MaxRank: 3},
+			{ID: "storm_caller", Name: "Storm Caller", Description: "+12% elemental skill damage per rank", Effect: TalentElementalDamage, PerRank: 12, MaxRank: 3, RequiredLevel: 10},
+		},
+	},
+	"ranger": {
+		ID:            "ranger",
+		Name:          "Ranger",
+		Description:   "Tracker and forager who strikes precisely and gathers more from the wild.",
+		BaseHP:        4,
+		BaseStamina:   10,
+		HPPerLevel:    1,
+		StaminaPerLvl: 2,
+		ManaPerLevel:  1,
+		StartSkills:   []string{"Power Strike", "Tracking"},
+		AllowedSkills: []string{"Power Strike", "Poison Blade", "Ice Shard", "Battle Cry", "Regeneration", "Rend", "Enfeeble", "Silencing Strike", "Haste", "Tracking"},
+		Talents: []Talent{
+			{ID: "marksman", Name: "Marksman", Description: "+3% critical hit chance per rank", Effect: TalentCritChance, PerRank: 3, MaxRank: 5},
+			{ID: "forager", Name: "Forager", Description: "+15% harvest yield per rank", Effect: TalentHarvestYield, PerRank: 15, MaxRank: 5},
+			{ID: "venomcraft", Name: "Venomcraft", Description: "+8% elemental skill damage per rank", Effect: TalentElementalDamage, PerRank: 8, MaxRank: 3},
+			{ID: "pack_leader", Name: "Pack Leader", Description: "+2 guard attack per rank", Effect: TalentGuardAttack, PerRank: 2, MaxRank: 3, RequiredLevel: 10},
+		},
+	},
+	"cleric": {
+		ID:            "cleric",
+		Name:          "Cleric",
+		Description:   "Healer and protector who keeps allies standing.",
+		BaseHP:        4,
+		BaseMana:      15,
+		HPPerLevel:    1,
+		ManaPerLevel:  3,
+		StartSkills:   []string{"Power Strike", "Heal"},
+		AllowedSkills: []string{"Power Strike", "Heal", "Regeneration", "Shield Wall", "Lightning Bolt", "Purify", "Dispel Magic", "Arcane Barrier", "Tracking"},
+		Talents: []Talent{
+			{ID: "devotion", Name: "Devotion", Description: "+10% skill healing per rank", Effect: TalentHealPower, PerRank: 10, MaxRank: 5},
+			{ID: "sanctuary", Name: "Sanctuary", Description: "+2 guard attack per rank", Effect: TalentGuardAttack, PerRank: 2, MaxRank: 5},
+			{ID: "smite", Name: "Smite", Description: "+8% elemental skill damage per rank", Effect: TalentElementalDamage, PerRank: 8, MaxRank: 3},
+			{ID: "blessed_hands", Name: "Blessed Hands", Description: "+2% critical hit chance per rank", Effect: TalentCritChance, PerRank: 2, MaxRank: 3, RequiredLevel: 10},
+		},
+	},
+}
+
+// LookupClass returns the class for a character, or false for legacy
+// characters created before classes existed.
+func LookupClass(player *models.Character) (CharacterClass, bool) {
+	c, ok := Classes[player.Class]
+	return c, ok
+}
+
+// FindSkill returns the skill definition with the given name.
+func FindSkill(name string) (models.Skill, bool) {
+	for _, s := range data.AvailableSkills {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return models.Skill{}, false
+}
+
+// GenerateClassCharacter rolls a new character of the given class. An unknown
+// class ID is an error.
+func GenerateClassCharacter(name, classID string) (models.Character, error) {
+	if _, ok := Classes[classID]; !ok {
+		return models.Character{}, fmt.Errorf("unknown class %q", classID)
+	}
+	char := GenerateCharacter(name, 1, 1)
+	applyClassStart(&char, classID)
+	return char, nil
+}
+
+// AdoptClass assigns a class to a classless character. A character that has
+// not earned any experience yet gets the full class start (resource bonuses
+// and starting skills). Otherwise stats and skills already earned are kept;
+// the class only affects future level-ups, skill learning and talents, and
+// the character gets the talent points its level entitles it to.
+func AdoptClass(player *models.Character, classID string) error {
+	if player.Class != "" {
+		return fmt.Errorf("%s is already a %s", player.Name, Classes[player.Class].Name)
+	}
+	if _, ok := Classes[classID]; !ok {
+		return fmt.Errorf("unknown class %q", classID)
+	}
+	if player.Level <= 1 && player.Experience == 0 {
+		applyClassStart(player, classID)
+		return nil
+	}
+	player.Class = classID
+	return nil
+}
+
+// applyClassStart adds the class's starting resource bonuses and replaces
+// the learned skills with the class's starting skills.
+func applyClassStart(char *models.Character, classID string) {
+	class := Classes[classID]
+	char.Class = class.ID
+	char.HitpointsNatural += class.BaseHP
+	char.HitpointsTotal = char.HitpointsNatural
+	char.HitpointsRemaining = char.HitpointsNatural
+	char.ManaNatural += class.BaseMana
+	char.ManaTotal = char.ManaNatural
+	char.ManaRemaining = char.ManaNatural
+	char.StaminaNatural += class.BaseStamina
+	char.StaminaTotal = char.StaminaNatural
+	char.StaminaRemaining = char.StaminaNatural
+
+	char.LearnedSkills = []models.Skill{}
+	for _, name := range class.StartSkills {
+		if skill, ok := FindSkill(name); ok {
+			char.LearnedSkills = append(char.LearnedSkills, skill)
+		}
+	}
+}
+
+// CanLearnSkill reports whether the character's class may learn the skill.
+// Classless characters may learn anything.
+func CanLearnSkill(player *models.Character, skillName string) bool {
+	class, ok := LookupClass(player)
+	if !ok {
+		return true
+	}
+	return Contains(class.AllowedSkills, skillName)
+}
+
+// applyClassLevelGains adds the class's per-level resource growth.
+func applyClassLevelGains(player *models.Character) {
+	class, ok := LookupClass(player)
+	if !ok {
+		return
+	}
+	player.HitpointsNatural += class.HPPerLevel
+	player.ManaNatural += class.ManaPerLevel
+	player.StaminaNatural += class.StaminaPerLvl
+}
+
+// FindTalent returns a talent from the character's class tree.
+func FindTalent(player *models.Character, talentID string) (Talent, bool) {
+	class, ok := LookupClass(player)
+	if !ok {
+		return Talent{}, false
+	}
+	for _, t := range class.Talents {
+		if t.ID == talentID {
+			return t, true
+		}
+	}
+	return Talent{}, false
+}
+
+// SpentTalentPoints counts the ranks the character has bought.
+func SpentTalentPoints(player *models.Character) int {
+	spent := 0
+	for _, rank := range player.Talents {
+		spent += rank
+	}
+	return spent
+}
+
+// TalentPoints returns the unspent talent points: one per level past the
+// first. Classless characters have none until they pick a class.
+func TalentPoints(player *models.Character) int {
+	if _, ok := LookupClass(player); !ok {
+		return 0
+	}
+	points := player.Level - 1 - SpentTalentPoints(player)
+	if points < 0 {
+		return 0
+	}
+	return points
+}
+
+// LearnTalent spends one talent point on a rank of the given talent.
+func LearnTalent(player *models.Character, talentID string) error {
+	talent, ok := FindTalent(player, talentID)
+	if !ok {
+		return fmt.Errorf("no talent %q for this class", talentID)
+	}
+	if TalentPoints(player) <= 0 {
+		return fmt.Errorf("no talent points available")
+	}
+	if player.Level < talent.RequiredLevel {
+		return fmt.Errorf("%s requires level %d", talent.Name, talent.RequiredLevel)
+	}
+	if player.Talents[talentID] >= talent.MaxRank {
+		return fmt.Errorf("%s is already at max rank", talent.Name)
+	}
+	if player.Talents == nil {
+		player.Talents = make(map[string]int)
+	}
+	player.Talents[talentID]++
+	return nil
+}
+
+// TalentBonus sums PerRank * rank over the character's talents with the
+// given effect.
+func TalentBonus(player *models.Character, effect string) int {
+	class, ok := LookupClass(player)
+	if !ok {
+		return 0
+	}
+	total := 0
+	for _, t := range class.Talents {
+		if t.Effect == effect {
+			total += t.PerRank * player.Talents[t.ID]
+		}
+	}
+	return total
+}
+
+// SortedTalentIDs returns the character's learned talent IDs in stable order.
+func SortedTalentIDs(player *models.Character) []string {
+	ids := make([]string, 0, len(player.Talents))
+	for id, rank := range player.Talents {
+		if rank > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SkillPower returns the skill's damage (negative for heals) after the
+// character's talents and attributes: strength boosts physical skills,
+// intelligence boosts elemental skills and heals.
+func SkillPower(player *models.Character, skill models.Skill) int {
+	attrs := TotalAttributes(player)
+	switch {
+	case skill.Damage < 0:
+		return skill.Damage * (100 + TalentBonus(player, TalentHealPower) + attrs.Intelligence*SkillPctPerPoint) / 100
+	case skill.Damage > 0 && skill.DamageType != models.Physical:
+		return skill.Damage * (100 + TalentBonus(player, TalentElementalDamage) + attrs.Intelligence*SkillPctPerPoint) / 100
+	case skill.Damage > 0:
+		return skill.Damage * (100 + attrs.Strength*SkillPctPerPoint) / 100
+	}
+	return skill.Damage
+}
+
+// GuardTalentBonus is the extra attack bonus the character's guards get when
+// fighting alongside them.
+func GuardTalentBonus(player *models.Character) int {
+	return TalentBonus(player, TalentGuardAttack)
+}
+
+// HarvestYield applies harvest talents to a harvested amount. Any invested
+// rank adds at least one unit to a non-empty harvest.
+func HarvestYield(player *models.Character, amount int) int {
+	pct := TalentBonus(player, TalentHarvestYield)
+	if pct <= 0 || amount <= 0 {
+		return amount
+	}
+	bonus := amount * pct / 100
+	if bonus < 1 {
+		bonus = 1
+	}
+	return amount + bonus
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestGenerateClassCharacter(t *testing.T) {
+	mage, err := GenerateClassCharacter("Merla", "mage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mage.Class != "mage" || mage.ManaNatural < 25+21 {
+		t.Errorf("expected mage with bonus mana, got class %q mana %d", mage.Class, mage.ManaNatural)
+	}
+	if len(mage.LearnedSkills) != 1 || mage.LearnedSkills[0].Name != "Fireball" {
+		t.Errorf("unexpected starting skills: %+v", mage.LearnedSkills)
+	}
+	if CanLearnSkill(&mage, "Power Strike") || !CanLearnSkill(&mage, "Ice Shard") {
+		t.Error("mage skill restrictions not applied")
+	}
+	if _, err := GenerateClassCharacter("Nobody", "bard"); err == nil {
+		t.Error("expected error for unknown class")
+	}
+
+	legacy := GenerateCharacter("Old", 1, 1)
+	if !CanLearnSkill(&legacy, "Power Strike") || !CanLearnSkill(&legacy, "Fireball") {
+		t.Error("classless characters should learn anything")
+	}
+}
+
+func TestAdoptClassKeepsEarnedProgress(t *testing.T) {
+	fresh := GenerateCharacter("Fresh", 1, 1)
+	if err := AdoptClass(&fresh, "cleric"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fresh.LearnedSkills) != 2 {
+		t.Errorf("fresh character should get cleric starting skills, got %+v", fresh.LearnedSkills)
+	}
+
+	vet := GenerateCharacter("Vet", 1, 1)
+	vet.Level, vet.Experience = 6, 1000
+	hp := vet.HitpointsNatural
+	if err := AdoptClass(&vet, "ranger"); err != nil {
+		t.Fatal(err)
+	}
+	if vet.HitpointsNatural != hp || len(vet.LearnedSkills) != 1 {
+		t.Error("experienced character should keep stats and skills")
+	}
+	if TalentPoints(&vet) != 5 {
+		t.Errorf("expected 5 talent points at level 6, got %d", TalentPoints(&vet))
+	}
+	if err := AdoptClass(&vet, "mage"); err == nil {
+		t.Error("class should only be chosen once")
+	}
+}
+
+func TestLearnTalentLimits(t *testing.T) {
+	char, _ := GenerateClassCharacter("Rook", "ranger")
+	if err := LearnTalent(&char, "forager"); err == nil {
+		t.Error("level 1 has no talent points")
+	}
+	char.Level = 12
+	for i := 0; i < 5; i++ {
+		if err := LearnTalent(&char, "forager"); err != nil {
+			t.Fatalf("rank %d: %v", i+1, err)
+		}
+	}
+	if err := LearnTalent(&char, "forager"); err == nil {
+		t.Error("expected max rank error")
+	}
+	if err := LearnTalent(&char, "brutality"); err == nil {
+		t.Error("talents from other classes should be rejected")
+	}
+	if err := LearnTalent(&char, "pack_leader"); err != nil {
+		t.Errorf("level 12 should unlock pack_leader: %v", err)
+	}
+	if TalentPoints(&char) != 5 || SpentTalentPoints(&char) != 6 {
+		t.Errorf("points: %d left, %d spent", TalentPoints(&char), SpentTalentPoints(&char))
+	}
+
+	if got := HarvestYield(&char, 10); got != 17 {
+		t.Errorf("forager 5/5 on 10 should yield 17, got %d", got)
+	}
+	if got := HarvestYield(&char, 1); got != 2 {
+		t.Errorf("any rank should add at least 1, got %d", got)
+	}
+	if got := GuardTalentBonus(&char); got != 2 {
+		t.Errorf("expected guard bonus 2, got %d", got)
+	}
+}
+
+func TestSkillPower(t *testing.T) {
+	cleric, _ := GenerateClassCharacter("Aria", "cleric")
+	cleric.Level = 10
+	cleric.Talents = map[string]int{"devotion": 5, "smite": 2}
+
+	heal := models.Skill{Name: "Heal", Damage: -20}
+	bolt := models.Skill{Name: "Lightning Bolt", Damage: 22, DamageType: models.Lightning}
+	strike := models.Skill{Name: "Power Strike", Damage: 25, DamageType: models.Physical}
+
+	if got := SkillPower(&cleric, heal); got != -30 {
+		t.Errorf("heal with devotion 5 should be -30, got %d", got)
+	}
+	if got := SkillPower(&cleric, bolt); got != 22*116/100 {
+		t.Errorf("unexpected bolt damage %d", got)
+	}
+	if got := SkillPower(&cleric, strike); got != 25 {
+		t.Errorf("physical skills are not boosted, got %d", got)
+	}
+}
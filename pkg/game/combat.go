@@ -18,6 +18,7 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 	mob.StaminaRemaining = mob.StaminaTotal
 
 	turnCount := 0
+	timers := &SkillTimers{}
 	fmt.Printf("Fight #%d: %s (Lv%d) vs %s (Lv%d)\n",
 		rand.Intn(10000), player.Name, player.Level, mob.Name, mob.Level)
 
@@ -38,22 +39,25 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 		}
 
 		// AI makes decision
-		decision = MakeAIDecision(player, mob, turnCount)
+		decision = MakeAIDecision(player, mob, turnCount, timers)
 
 		// Execute decision
 		switch decision {
 		case "attack":
-			playerAttack := MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-			if rand.Intn(100) < 15 {
+			playerAttack := MultiRoll(player.AttackRolls) + AttackMod(player)
+			if RollPlayerCrit(player) {
 				playerAttack = playerAttack * 2
 				fmt.Printf("  [T%d] %s CRITICAL HIT!\n", turnCount, player.Name)
 			}
-			mobDef := MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+			mobDef := MultiRoll(mob.DefenseRolls) + DefenseMod(mob)
 			if playerAttack > mobDef {
 				diff := ApplyDamage(playerAttack-mobDef, models.Physical, mob)
 				mob.HitpointsRemaining -= diff
 				fmt.Printf("  [T%d] %s attacks for %d dmg (Mob HP: %d/%d)\n",
 					turnCount, player.Name, diff, mob.HitpointsRemaining, mob.HitpointsTotal)
+				for _, line := range ApplyOnHitEffects(player, mob, diff) {
+					fmt.Printf("  [T%d] %s\n", turnCount, line)
+				}
 			}
 
 		case "item":
@@ -80,17 +84,26 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 						if skill.ManaCost <= player.ManaRemaining && skill.StaminaCost <= player.StaminaRemaining {
 							player.ManaRemaining -= skill.ManaCost
 							player.StaminaRemaining -= skill.StaminaCost
+							cast := timers.Cast(skill, SkillPower(player, skill), mob, turnCount)
+							for _, m := range cast.Messages {
+								fmt.Printf("  [T%d] %s\n", turnCount, m.Text)
+							}
 
 							if skill.Damage < 0 {
 								// Healing
-								player.HitpointsRemaining += -skill.Damage
+								healAmount := -cast.Power
+								player.HitpointsRemaining += healAmount
 								if player.HitpointsRemaining > player.HitpointsTotal {
 									player.HitpointsRemaining = player.HitpointsTotal
 								}
-								fmt.Printf("  [T%d] %s used %s (+%d HP)\n", turnCount, player.Name, skill.Name, -skill.Damage)
+								fmt.Printf("  [T%d] %s used %s (+%d HP)\n", turnCount, player.Name, skill.Name, healAmount)
 							} else if skill.Damage > 0 {
 								// Damage skill
-								finalDamage := ApplyDamage(skill.Damage, skill.DamageType, mob)
+								hit := ApplyElements(cast.Power, skill.DamageType, mob, location.Name)
+								for _, m := range hit.Messages {
+									fmt.Printf("  [T%d] %s\n", turnCount, m.Text)
+								}
+								finalDamage := ApplyDamage(hit.Damage, skill.DamageType, mob)
 								mob.HitpointsRemaining -= finalDamage
 								fmt.Printf("  [T%d] %s used %s (%d %s dmg)\n",
 									turnCount, player.Name, skill.Name, finalDamage, skill.DamageType)
@@ -100,17 +113,9 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 							}
 
 							// Apply effects
-							if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-								if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" || skill.Effect.Type == "regen" {
-									player.StatusEffects = append(player.StatusEffects, skill.Effect)
-									if skill.Effect.Type == "buff_attack" {
-										player.StatsMod.AttackMod += skill.Effect.Potency
-									} else if skill.Effect.Type == "buff_defense" {
-										player.StatsMod.DefenseMod += skill.Effect.Potency
-									}
-								} else {
-									mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-								}
+							ApplySkillEffect(player, mob, skill.Effect)
+							for _, line := range TrainSkill(player, skill.Name, CastXP(cast)) {
+								fmt.Printf("  [T%d] %s\n", turnCount, line)
 							}
 						}
 						break
@@ -126,14 +131,15 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 				// Monster stunned, skip turn
 			} else {
 				// Simple monster attack
-				mobAttack := MultiRoll(mob.AttackRolls) + mob.StatsMod.AttackMod
-				if rand.Intn(100) < 10 {
+				mobAttack := MultiRoll(mob.AttackRolls) + AttackMod(mob)
+				if RollMonsterCrit() {
 					mobAttack = mobAttack * 2
 				}
-				playerDef := MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+				playerDef := MultiRoll(player.DefenseRolls) + DefenseMod(player)
 				if mobAttack > playerDef {
 					diff := ApplyDamage(mobAttack-playerDef, models.Physical, player)
 					player.HitpointsRemaining -= diff
+					ApplyThorns(player, mob, diff)
 				}
 			}
 		}
@@ -151,6 +157,9 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 
 		// Drop beast materials based on monster type
 		DropBeastMaterial(mob.MonsterType, player)
+		if r, ok := RollRecipeDrop(player); ok {
+			fmt.Printf("You found a recipe: %s! Craft it in your village.\n", r.Name)
+		}
 
 		// Chance for potion
 		if rand.Intn(100) < 30 {
@@ -158,7 +167,7 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 			if rand.Intn(100) < 30 {
 				potion = CreateHealthPotion("medium")
 			}
-			player.Inventory = append(player.Inventory, potion)
+			AddItemToInventory(&player.Inventory, potion)
 		}
 
 		// 15% chance to rescue a villager after victory
@@ -181,7 +190,7 @@ func AutoFightToTheDeath(player *models.Character, game *models.GameState, mob *
 			game.Villages[player.VillageName] = village
 		}
 
-		player.StatsMod = CalculateItemMods(player.EquipmentMap)
+		RecalculatePlayerStats(player)
 		location.Monsters[mobLoc] = GenerateBestMonster(game, location.LevelMax, location.RarityMax)
 	} else {
 		fmt.Printf("  DEFEAT!\n")
@@ -259,6 +268,7 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 
 	playerFled := false
 	turnCount := 0
+	timers, mobTimers := &SkillTimers{}, &SkillTimers{}
 
 	for player.HitpointsRemaining > 0 && mob.HitpointsRemaining > 0 && !playerFled {
 		turnCount++
@@ -304,34 +314,50 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 
 		if IsStunned(player) {
 			fmt.Printf("%s is STUNNED and cannot act!\n", player.Name)
-			playerDef = MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+			if name := timers.InterruptCharge(); name != "" {
+				fmt.Printf("%s's %s is interrupted!\n", player.Name, name)
+			}
+			playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
 			goto MonsterTurn
 		}
 
-		fmt.Println("\n--- Your Action ---")
-		fmt.Println("1 = Attack (physical)")
-		fmt.Println("2 = Defend (+50% defense, 50% attack)")
-		fmt.Println("3 = Use Item")
-		fmt.Println("4 = Use Skill")
-		fmt.Println("5 = Flee")
-		fmt.Print("Choice: ")
+		if timers.Charging != nil {
+			action = "charge"
+		} else {
+			fmt.Println("\n--- Your Action ---")
+			fmt.Println("1 = Attack (physical)")
+			fmt.Println("2 = Defend (+50% defense, 50% attack)")
+			fmt.Println("3 = Use Item")
+			fmt.Println("4 = Use Skill")
+			fmt.Println("5 = Flee")
+			fmt.Print("Choice: ")
 
-		scanner.Scan()
-		action = scanner.Text()
+			scanner.Scan()
+			action = scanner.Text()
+		}
 
 		switch action {
+		case "charge": // Keep charging a skill
+			if skill, ready := timers.ContinueCharge(turnCount); ready {
+				fmt.Printf("%s unleashes %s!\n", player.Name, skill.Name)
+				usedSkillDamage, usedSkillType = castSkill(player, mob, skill, timers, turnCount)
+			} else {
+				fmt.Printf("%s keeps charging %s...\n", player.Name, timers.Charging.Name)
+			}
+			playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
+
 		case "1": // Attack
-			playerAttack = MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-			playerDef = MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
-			if rand.Intn(100) < 15 {
+			playerAttack = MultiRoll(player.AttackRolls) + AttackMod(player)
+			playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
+			if RollPlayerCrit(player) {
 				playerAttack = playerAttack * 2
 				fmt.Printf("*** CRITICAL HIT! ***\n")
 			}
 
 		case "2": // Defend
 			defending = true
-			playerAttack = (MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod) / 2
-			playerDef = int(float64(MultiRoll(player.DefenseRolls)+player.StatsMod.DefenseMod) * 1.5)
+			playerAttack = (MultiRoll(player.AttackRolls) + AttackMod(player)) / 2
+			playerDef = int(float64(MultiRoll(player.DefenseRolls)+DefenseMod(player)) * 1.5)
 			fmt.Printf("%s takes a defensive stance!\n", player.Name)
 
 		case "3": // Use Item
@@ -370,7 +396,7 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 					RemoveItemFromInventory(&player.Inventory, originalIdx)
 				}
 			}
-			playerDef = MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+			playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
 
 		case "4": // Use Skill
 			if len(player.LearnedSkills) == 0 {
@@ -383,7 +409,10 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 					if skill.ManaCost > player.ManaRemaining || skill.StaminaCost > player.StaminaRemaining {
 						canAfford = "N"
 					}
-					fmt.Printf("%d [%s] %s - ", idx+1, canAfford, skill.Name)
+					if left := timers.CooldownLeft(skill.Name, turnCount); left > 0 {
+						canAfford = fmt.Sprintf("CD %d", left)
+					}
+					fmt.Printf("%d [%s] %s R%d - ", idx+1, canAfford, skill.Name, SkillRank(skill))
 					if skill.ManaCost > 0 {
 						fmt.Printf("%dMP ", skill.ManaCost)
 					}
@@ -406,47 +435,32 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 				} else {
 					skill := player.LearnedSkills[skillIdx-1]
 
-					if skill.ManaCost > player.ManaRemaining {
+					if IsSilenced(player.StatusEffects) {
+						fmt.Printf("%s is SILENCED and cannot use skills!\n", player.Name)
+						skipPlayerTurn = true
+					} else if skill.ManaCost > player.ManaRemaining {
 						fmt.Println("Not enough mana!")
 						skipPlayerTurn = true
 					} else if skill.StaminaCost > player.StaminaRemaining {
 						fmt.Println("Not enough stamina!")
 						skipPlayerTurn = true
+					} else if left := timers.CooldownLeft(skill.Name, turnCount); left > 0 {
+						fmt.Printf("%s is on cooldown for %d more turn(s)!\n", skill.Name, left)
+						skipPlayerTurn = true
+					} else if SkillChargeTurns(skill) > 0 {
+						player.ManaRemaining -= skill.ManaCost
+						player.StaminaRemaining -= skill.StaminaCost
+						timers.BeginCharge(skill, turnCount)
+						fmt.Printf("%s begins charging %s!\n", player.Name, skill.Name)
 					} else {
 						player.ManaRemaining -= skill.ManaCost
 						player.StaminaRemaining -= skill.StaminaCost
 						fmt.Printf("%s uses %s!\n", player.Name, skill.Name)
-
-						if skill.Damage < 0 {
-							healAmount := -skill.Damage
-							player.HitpointsRemaining += healAmount
-							if player.HitpointsRemaining > player.HitpointsTotal {
-								player.HitpointsRemaining = player.HitpointsTotal
-							}
-							fmt.Printf("%s heals for %d HP!\n", player.Name, healAmount)
-						} else if skill.Damage > 0 {
-							usedSkillDamage = skill.Damage
-							usedSkillType = skill.DamageType
-						}
-
-						if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-							if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" || skill.Effect.Type == "regen" {
-								player.StatusEffects = append(player.StatusEffects, skill.Effect)
-								if skill.Effect.Type == "buff_attack" {
-									player.StatsMod.AttackMod += skill.Effect.Potency
-								} else if skill.Effect.Type == "buff_defense" {
-									player.StatsMod.DefenseMod += skill.Effect.Potency
-								}
-								fmt.Printf("%s gains %s effect!\n", player.Name, skill.Effect.Type)
-							} else {
-								mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-								fmt.Printf("%s is afflicted with %s!\n", mob.Name, skill.Effect.Type)
-							}
-						}
+						usedSkillDamage, usedSkillType = castSkill(player, mob, skill, timers, turnCount)
 					}
 				}
 			}
-			playerDef = MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+			playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
 
 		case "5": // Flee
 			fleeChance := 50 + (player.Level-mob.Level)*5
@@ -465,21 +479,23 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 			} else {
 				fmt.Printf("%s tried to flee but failed!\n", player.Name)
 				skipPlayerTurn = true
-				playerDef = MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+				playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
 			}
 
 		default:
 			fmt.Println("Invalid action! Defaulting to Attack.")
-			playerAttack = MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
-			playerDef = MultiRoll(player.DefenseRolls) + player.StatsMod.DefenseMod
+			playerAttack = MultiRoll(player.AttackRolls) + AttackMod(player)
+			playerDef = MultiRoll(player.DefenseRolls) + DefenseMod(player)
 		}
 
 		// Player attacks (if not skipped)
 		if !skipPlayerTurn && (playerAttack > 0 || usedSkillDamage > 0) {
-			mobDef := MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+			mobDef := MultiRoll(mob.DefenseRolls) + DefenseMod(mob)
 
 			if usedSkillDamage > 0 {
-				finalDamage := ApplyDamage(usedSkillDamage, usedSkillType, mob)
+				hit := ApplyElements(usedSkillDamage, usedSkillType, mob, location.Name)
+				printStatusMessages(hit.Messages)
+				finalDamage := ApplyDamage(hit.Damage, usedSkillType, mob)
 				mob.HitpointsRemaining -= finalDamage
 
 				if usedSkillType != models.Physical {
@@ -504,6 +520,9 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 				} else {
 					fmt.Printf("%s attacks for %d damage!\n", player.Name, finalDamage)
 				}
+				for _, line := range ApplyOnHitEffects(player, mob, finalDamage) {
+					fmt.Println(line)
+				}
 			} else {
 				fmt.Printf("%s's attack missed!\n", player.Name)
 			}
@@ -525,52 +544,41 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 				fmt.Printf("%s is STUNNED and cannot act!\n", mob.Name)
 			} else {
 				useMonsterSkill := false
-				if len(mob.LearnedSkills) > 0 && rand.Intn(100) < 40 {
-					skill := mob.LearnedSkills[rand.Intn(len(mob.LearnedSkills))]
-					if skill.ManaCost <= mob.ManaRemaining && skill.StaminaCost <= mob.StaminaRemaining {
-						mob.ManaRemaining -= skill.ManaCost
-						mob.StaminaRemaining -= skill.StaminaCost
-						useMonsterSkill = true
-
-						fmt.Printf("%s uses %s!\n", mob.Name, skill.Name)
-
-						if skill.Damage < 0 {
-							healAmount := -skill.Damage
-							mob.HitpointsRemaining += healAmount
-							if mob.HitpointsRemaining > mob.HitpointsTotal {
-								mob.HitpointsRemaining = mob.HitpointsTotal
-							}
-							fmt.Printf("%s heals for %d HP!\n", mob.Name, healAmount)
-						} else if skill.Damage > 0 {
-							finalDamage := ApplyDamage(skill.Damage, skill.DamageType, player)
-
-							if len(combatGuards) > 0 {
-								finalDamage, _ = GuardDefense(combatGuards, finalDamage)
-							}
-
-							player.HitpointsRemaining -= finalDamage
-							fmt.Printf("Deals %d damage to %s!\n", finalDamage, player.Name)
+				if skill, ok := ChooseMonsterSkill(mob, player, mobTimers, turnCount); ok {
+					mob.ManaRemaining -= skill.ManaCost
+					mob.StaminaRemaining -= skill.StaminaCost
+					useMonsterSkill = true
+
+					fmt.Printf("%s uses %s!\n", mob.Name, skill.Name)
+					cast := mobTimers.Cast(skill, skill.Damage, player, turnCount)
+					printStatusMessages(cast.Messages)
+
+					if skill.Damage < 0 {
+						healAmount := -cast.Power
+						mob.HitpointsRemaining += healAmount
+						if mob.HitpointsRemaining > mob.HitpointsTotal {
+							mob.HitpointsRemaining = mob.HitpointsTotal
 						}
+						fmt.Printf("%s heals for %d HP!\n", mob.Name, healAmount)
+					} else if skill.Damage > 0 {
+						hit := ApplyElements(cast.Power, skill.DamageType, player, location.Name)
+						printStatusMessages(hit.Messages)
+						finalDamage := ApplyDamage(hit.Damage, skill.DamageType, player)
 
-						if skill.Effect.Type != "none" && skill.Effect.Duration > 0 {
-							if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" {
-								mob.StatusEffects = append(mob.StatusEffects, skill.Effect)
-								if skill.Effect.Type == "buff_attack" {
-									mob.StatsMod.AttackMod += skill.Effect.Potency
-								} else if skill.Effect.Type == "buff_defense" {
-									mob.StatsMod.DefenseMod += skill.Effect.Potency
-								}
-							} else {
-								player.StatusEffects = append(player.StatusEffects, skill.Effect)
-								fmt.Printf("%s is afflicted with %s!\n", player.Name, skill.Effect.Type)
-							}
+						if len(combatGuards) > 0 {
+							finalDamage, _ = GuardDefense(combatGuards, finalDamage)
 						}
+
+						player.HitpointsRemaining -= finalDamage
+						fmt.Printf("Deals %d damage to %s!\n", finalDamage, player.Name)
 					}
+
+					printStatusMessages(ApplySkillEffect(mob, player, skill.Effect))
 				}
 
 				if !useMonsterSkill {
-					mobAttack := MultiRoll(mob.AttackRolls) + mob.StatsMod.AttackMod
-					if rand.Intn(100) < 10 {
+					mobAttack := MultiRoll(mob.AttackRolls) + AttackMod(mob)
+					if RollMonsterCrit() {
 						mobAttack = mobAttack * 2
 						fmt.Printf("*** %s CRITICAL HIT! ***\n", mob.Name)
 					}
@@ -585,6 +593,9 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 
 						player.HitpointsRemaining -= finalDamage
 						fmt.Printf("%s attacks for %d damage!\n", mob.Name, finalDamage)
+						for _, line := range ApplyThorns(player, mob, finalDamage) {
+							fmt.Println(line)
+						}
 					} else {
 						fmt.Printf("%s's attack missed!\n", mob.Name)
 					}
@@ -617,6 +628,10 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 			scanner := bufio.NewScanner(os.Stdin)
 			scanner.Scan()
 			choice := scanner.Text()
+			if choice == "1" && !CanLearnSkill(player, mob.GuardedSkill.Name) {
+				fmt.Printf("\nA %s cannot learn %s. You take the scroll instead.\n", Classes[player.Class].Name, mob.GuardedSkill.Name)
+				choice = "2"
+			}
 
 			switch choice {
 			case "1":
@@ -625,13 +640,13 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 				fmt.Printf("You can now use this skill in combat.\n\n")
 			case "2":
 				scroll := CreateSkillScroll(mob.GuardedSkill)
-				player.Inventory = append(player.Inventory, scroll)
+				AddItemToInventory(&player.Inventory, scroll)
 				fmt.Printf("\nYou received a %s!\n", scroll.Name)
 				fmt.Printf("You can use it later to learn the skill or craft it into equipment.\n")
 				fmt.Printf("Crafting Value: %d\n\n", scroll.SkillScroll.CraftingValue)
 			default:
 				scroll := CreateSkillScroll(mob.GuardedSkill)
-				player.Inventory = append(player.Inventory, scroll)
+				AddItemToInventory(&player.Inventory, scroll)
 				fmt.Printf("\nYou received a %s!\n", scroll.Name)
 			}
 		}
@@ -643,6 +658,9 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 
 		// Drop beast materials
 		DropBeastMaterial(mob.MonsterType, player)
+		if r, ok := RollRecipeDrop(player); ok {
+			fmt.Printf("You found a recipe: %s! Craft it in your village.\n", r.Name)
+		}
 
 		// 30% chance to get a health potion
 		if rand.Intn(100) < 30 {
@@ -656,7 +674,7 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 				potionSize = "large"
 			}
 			potion := CreateHealthPotion(potionSize)
-			player.Inventory = append(player.Inventory, potion)
+			AddItemToInventory(&player.Inventory, potion)
 			fmt.Printf("Found a %s!\n", potion.Name)
 		}
 
@@ -678,7 +696,7 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 			game.Villages[player.VillageName] = village
 		}
 
-		player.StatsMod = CalculateItemMods(player.EquipmentMap)
+		RecalculatePlayerStats(player)
 		location.Monsters[mobLoc] = GenerateBestMonster(game, location.LevelMax, location.RarityMax)
 
 		// Update guard states in village after combat
@@ -736,3 +754,24 @@ func FightToTheDeath(player *models.Character, game *models.GameState, mob *mode
 		}
 	}
 }
+
+// castSkill resolves a skill the player has paid for in the terminal game:
+// combo, healing, status effect and skill experience. It returns the damage
+// still to be dealt to the monster and its type.
+func castSkill(player *models.Character, mob *models.Monster, skill models.Skill, timers *SkillTimers, turn int) (int, models.DamageType) {
+	cast := timers.Cast(skill, SkillPower(player, skill), mob, turn)
+	printStatusMessages(cast.Messages)
+	damage := 0
+	if cast.Power < 0 {
+		healAmount := -cast.Power
+		player.HitpointsRemaining = min(player.HitpointsRemaining+healAmount, player.HitpointsTotal)
+		fmt.Printf("%s heals for %d HP!\n", player.Name, healAmount)
+	} else {
+		damage = cast.Power
+	}
+	printStatusMessages(ApplySkillEffect(player, mob, skill.Effect))
+	for _, line := range TrainSkill(player, skill.Name, CastXP(cast)) {
+		fmt.Println(line)
+	}
+	return damage, skill.DamageType
+}
@@ -8,22 +8,29 @@ import (
 )
 
 // MakeAIDecision determines the best combat action for a player character
-// based on current health, turn count, and available resources.
+// based on current health, turn count, available resources and, when timers
+// is not nil, which skills are off cooldown. The AI doesn't plan ahead, so it
+// never picks skills that need charging.
 // Returns a string representing the chosen action.
-func MakeAIDecision(player *models.Character, mob *models.Monster, turnCount int) string {
+func MakeAIDecision(player *models.Character, mob *models.Monster, turnCount int, timers *SkillTimers) string {
 	hpPercent := float64(player.HitpointsRemaining) / float64(player.HitpointsTotal)
+	canCast := !IsSilenced(player.StatusEffects)
+	usable := func(skill models.Skill) bool {
+		return canCast && timers.Ready(skill, turnCount) && SkillChargeTurns(skill) == 0 &&
+			player.ManaRemaining >= skill.ManaCost && player.StaminaRemaining >= skill.StaminaCost
+	}
 
 	// Priority 1: Heal if HP < 40%
 	if hpPercent < 0.4 {
 		// Check for Heal skill
 		for _, skill := range player.LearnedSkills {
-			if strings.EqualFold(skill.Name, "Heal") && player.ManaRemaining >= skill.ManaCost {
+			if strings.EqualFold(skill.Name, "Heal") && usable(skill) {
 				return "skill_heal"
 			}
 		}
 		// Check for Regeneration skill
 		for _, skill := range player.LearnedSkills {
-			if strings.EqualFold(skill.Name, "Regeneration") && player.ManaRemaining >= skill.ManaCost {
+			if strings.EqualFold(skill.Name, "Regeneration") && usable(skill) {
 				return "skill_regeneration"
 			}
 		}
@@ -38,21 +45,29 @@ func MakeAIDecision(player *models.Character, mob *models.Monster, turnCount int
 	// Priority 2: Use buff skills at the start of combat (turns 1-2)
 	if turnCount <= 2 {
 		for _, skill := range player.LearnedSkills {
-			if (strings.EqualFold(skill.Name, "Battle Cry") || strings.EqualFold(skill.Name, "Shield Wall")) &&
-				player.StaminaRemaining >= skill.StaminaCost {
+			if (strings.EqualFold(skill.Name, "Battle Cry") || strings.EqualFold(skill.Name, "Shield Wall")) && usable(skill) {
 				return "skill_" + skill.Name
 			}
 		}
 	}
 
-	// Priority 3: Use offensive skills if resources available (50% chance)
-	if rand.Intn(100) < 50 {
+	// Priority 3: Use offensive skills if resources available (50% chance),
+	// finishing a combo when one is open, else the hardest hitting skill
+	if canCast && rand.Intn(100) < 50 {
+		best := models.Skill{}
 		for _, skill := range player.LearnedSkills {
-			if skill.Damage > 0 &&
-				player.ManaRemaining >= skill.ManaCost &&
-				player.StaminaRemaining >= skill.StaminaCost {
+			if skill.Damage <= 0 || !usable(skill) {
+				continue
+			}
+			if _, ok := FindCombo(timers.previous(turnCount), skill, mob); ok {
 				return "skill_" + skill.Name
 			}
+			if skill.Damage > best.Damage {
+				best = skill
+			}
+		}
+		if best.Name != "" {
+			return "skill_" + best.Name
 		}
 	}
 
@@ -2,92 +2,334 @@ package game
 
 import (
 	"fmt"
+	"strings"
 
+	"rpg-game/pkg/data"
 	"rpg-game/pkg/models"
 )
 
-// ProcessStatusEffects iterates through a character's active status effects,
-// applies per-turn damage/healing, decrements durations, and removes expired effects.
-func ProcessStatusEffects(character *models.Character) {
-	for i := len(character.StatusEffects) - 1; i >= 0; i-- {
-		effect := &character.StatusEffects[i]
-
-		switch effect.Type {
-		case "poison":
-			character.HitpointsRemaining -= effect.Potency
-			fmt.Printf("%s takes %d poison damage!\n", character.Name, effect.Potency)
-		case "burn":
-			character.HitpointsRemaining -= effect.Potency
-			fmt.Printf("%s takes %d burn damage!\n", character.Name, effect.Potency)
-		case "regen":
-			character.HitpointsRemaining += effect.Potency
-			if character.HitpointsRemaining > character.HitpointsTotal {
-				character.HitpointsRemaining = character.HitpointsTotal
-			}
-			fmt.Printf("%s regenerates %d HP!\n", character.Name, effect.Potency)
-		case "buff_attack":
-			// already applied when effect was first added
-		case "buff_defense":
-			// already applied when effect was first added
+// Stacking policies and tick kinds of a models.StatusDefinition.
+const (
+	StackRefresh   = "refresh"   // reapplying renews the duration and keeps the stronger potency
+	StackIntensity = "intensity" // each application adds a stack, up to MaxStacks
+	StackIgnore    = "ignore"    // reapplying while active does nothing
+
+	TickDamage = "damage"
+	TickHeal   = "heal"
+)
+
+// Skill effect types that act once when the skill is used instead of
+// lingering as a status.
+const (
+	StatusCleanse = "cleanse" // removes the caster's harmful effects
+	StatusDispel  = "dispel"  // strips the target's beneficial magic effects
+)
+
+// StatusMessage is one line of status-effect output. Kind is the combat log
+// category: "damage", "heal", "buff", "debuff" or "combat".
+type StatusMessage struct {
+	Text string
+	Kind string
+}
+
+// FindStatus looks up a status effect in the registry by type. Unknown types
+// behave as inert, refreshing effects.
+func FindStatus(statusType string) (models.StatusDefinition, bool) {
+	for _, def := range data.StatusDefinitions {
+		if def.Type == statusType {
+			return def, true
 		}
+	}
+	return models.StatusDefinition{Type: statusType, Name: statusType, Stacking: StackRefresh}, false
+}
 
-		effect.Duration--
+// statusTarget is the part of a character, monster or guard that status
+// effects work on.
+type statusTarget struct {
+	name     string
+	category string
+	effects  *[]models.StatusEffect
+	hp       *int
+	maxHP    int
+	statsMod models.StatMod
+	speed    int
+}
 
+// statusTargetOf returns the status view of a *models.Character,
+// *models.Monster or *models.Guard, or nil for anything else.
+func statusTargetOf(target interface{}) *statusTarget {
+	switch t := target.(type) {
+	case *models.Character:
+		return &statusTarget{name: t.Name, effects: &t.StatusEffects,
+			hp: &t.HitpointsRemaining, maxHP: t.HitpointsTotal, statsMod: t.StatsMod, speed: CharacterSpeed(t)}
+	case *models.Monster:
+		return &statusTarget{name: t.Name, category: data.MonsterCategory[t.MonsterType], effects: &t.StatusEffects,
+			hp: &t.HitpointsRemaining, maxHP: t.HitpointsTotal, statsMod: t.StatsMod, speed: t.Speed}
+	case *models.Guard:
+		return &statusTarget{name: t.Name, effects: &t.StatusEffects,
+			hp: &t.HitpointsRemaining, maxHP: t.HitPoints, statsMod: t.StatsMod, speed: t.Speed}
+	}
+	return nil
+}
+
+// stacks is how many applications an active effect holds.
+func stacks(effect models.StatusEffect) int {
+	return max(effect.Stacks, 1)
+}
+
+// IsImmune reports whether the target's monster category shrugs off the
+// status. Characters and guards have no innate immunities.
+func IsImmune(target interface{}, statusType string) bool {
+	t := statusTargetOf(target)
+	return t != nil && Contains(data.StatusImmunities[t.category], statusType)
+}
+
+// ApplyStatus puts effect on target following its definition's stacking
+// policy. It reports whether the effect landed, with a log line either way
+// (empty when an "ignore" effect is already active).
+func ApplyStatus(target interface{}, effect models.StatusEffect) (StatusMessage, bool) {
+	t := statusTargetOf(target)
+	if t == nil || effect.Duration <= 0 {
+		return StatusMessage{}, false
+	}
+	def, _ := FindStatus(effect.Type)
+	if Contains(data.StatusImmunities[t.category], effect.Type) {
+		return StatusMessage{fmt.Sprintf("%s is immune to %s!", t.name, def.Name), "combat"}, false
+	}
+	effect.Stacks = 1
+	for i := range *t.effects {
+		active := &(*t.effects)[i]
+		if active.Type != effect.Type {
+			continue
+		}
+		switch def.Stacking {
+		case StackIgnore:
+			return StatusMessage{}, false
+		case StackIntensity:
+			active.Stacks = min(stacks(*active)+1, max(def.MaxStacks, 1))
+			active.Duration = max(active.Duration, effect.Duration)
+			active.Potency = max(active.Potency, effect.Potency)
+			return StatusMessage{fmt.Sprintf("%s's %s intensifies (x%d)!", t.name, def.Name, active.Stacks), statusKind(def)}, true
+		default:
+			active.Duration = max(active.Duration, effect.Duration)
+			active.Potency = max(active.Potency, effect.Potency)
+			return StatusMessage{fmt.Sprintf("%s's %s is renewed!", t.name, def.Name), statusKind(def)}, true
+		}
+	}
+	*t.effects = append(*t.effects, effect)
+	if def.Beneficial {
+		return StatusMessage{fmt.Sprintf("%s gains %s!", t.name, def.Name), "buff"}, true
+	}
+	return StatusMessage{fmt.Sprintf("%s is afflicted with %s!", t.name, def.Name), "debuff"}, true
+}
+
+// statusKind is the log category of an effect being applied.
+func statusKind(def models.StatusDefinition) string {
+	if def.Beneficial {
+		return "buff"
+	}
+	return "debuff"
+}
+
+// ApplySkillEffect applies a skill's effect: cleanse and dispel act at once,
+// beneficial statuses land on the caster and harmful ones on the target.
+func ApplySkillEffect(caster, target interface{}, effect models.StatusEffect) []StatusMessage {
+	switch effect.Type {
+	case "", "none":
+		return nil
+	case StatusCleanse:
+		return Cleanse(caster)
+	case StatusDispel:
+		return Dispel(target)
+	}
+	def, _ := FindStatus(effect.Type)
+	recipient := target
+	if def.Beneficial {
+		recipient = caster
+	}
+	if msg, _ := ApplyStatus(recipient, effect); msg.Text != "" {
+		return []StatusMessage{msg}
+	}
+	return nil
+}
+
+// TickStatusEffects runs one turn of the target's status effects: damage and
+// healing over time, then duration countdown and expiry.
+func TickStatusEffects(target interface{}) []StatusMessage {
+	t := statusTargetOf(target)
+	if t == nil {
+		return nil
+	}
+	msgs := []StatusMessage{}
+	kept := (*t.effects)[:0]
+	for _, effect := range *t.effects {
+		def, _ := FindStatus(effect.Type)
+		amount := effect.Potency * stacks(effect)
+		switch def.Tick {
+		case TickDamage:
+			*t.hp -= amount
+			msgs = append(msgs, StatusMessage{fmt.Sprintf("%s takes %d %s damage!", t.name, amount, strings.ToLower(def.Name)), "damage"})
+		case TickHeal:
+			*t.hp = min(*t.hp+amount, t.maxHP)
+			msgs = append(msgs, StatusMessage{fmt.Sprintf("%s regenerates %d HP!", t.name, amount), "heal"})
+		}
+		effect.Duration--
 		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				character.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				character.StatsMod.DefenseMod -= effect.Potency
-			}
-			fmt.Printf("%s's %s effect has worn off.\n", character.Name, effect.Type)
-			character.StatusEffects = append(character.StatusEffects[:i], character.StatusEffects[i+1:]...)
+			msgs = append(msgs, StatusMessage{fmt.Sprintf("%s's %s has worn off.", t.name, def.Name), "debuff"})
+			continue
 		}
+		kept = append(kept, effect)
 	}
+	*t.effects = kept
+	return msgs
+}
+
+// ProcessStatusEffects ticks a character's status effects for the terminal
+// game, printing what happened.
+func ProcessStatusEffects(character *models.Character) {
+	printStatusMessages(TickStatusEffects(character))
 }
 
-// ProcessStatusEffectsMob iterates through a monster's active status effects,
-// applies per-turn damage/healing, decrements durations, and removes expired effects.
+// ProcessStatusEffectsMob ticks a monster's status effects for the terminal
+// game, printing what happened.
 func ProcessStatusEffectsMob(mob *models.Monster) {
-	for i := len(mob.StatusEffects) - 1; i >= 0; i-- {
-		effect := &mob.StatusEffects[i]
-
-		switch effect.Type {
-		case "poison":
-			mob.HitpointsRemaining -= effect.Potency
-			fmt.Printf("%s takes %d poison damage!\n", mob.Name, effect.Potency)
-		case "burn":
-			mob.HitpointsRemaining -= effect.Potency
-			fmt.Printf("%s takes %d burn damage!\n", mob.Name, effect.Potency)
-		case "regen":
-			mob.HitpointsRemaining += effect.Potency
-			if mob.HitpointsRemaining > mob.HitpointsTotal {
-				mob.HitpointsRemaining = mob.HitpointsTotal
-			}
-			fmt.Printf("%s regenerates %d HP!\n", mob.Name, effect.Potency)
-		case "buff_attack":
-			// already applied when effect was first added
-		case "buff_defense":
-			// already applied when effect was first added
+	printStatusMessages(TickStatusEffects(mob))
+}
+
+func printStatusMessages(msgs []StatusMessage) {
+	for _, m := range msgs {
+		fmt.Println(m.Text)
+	}
+}
+
+// Cleanse removes the target's harmful effects in the given dispel
+// categories, or every removable harmful effect when none are given.
+func Cleanse(target interface{}, categories ...string) []StatusMessage {
+	return removeStatuses(target, func(def models.StatusDefinition) bool {
+		return !def.Beneficial && def.Dispel != "" && (len(categories) == 0 || Contains(categories, def.Dispel))
+	}, "%s is cleansed of %s.", "buff")
+}
+
+// Dispel strips the target's beneficial magic effects.
+func Dispel(target interface{}) []StatusMessage {
+	return removeStatuses(target, func(def models.StatusDefinition) bool {
+		return def.Beneficial && def.Dispel == "magic"
+	}, "%s's %s is dispelled!", "debuff")
+}
+
+// removeStatuses drops the target's effects whose definition matches,
+// logging each with format (target name, effect name).
+func removeStatuses(target interface{}, match func(models.StatusDefinition) bool, format, kind string) []StatusMessage {
+	t := statusTargetOf(target)
+	if t == nil {
+		return nil
+	}
+	msgs := []StatusMessage{}
+	kept := (*t.effects)[:0]
+	for _, effect := range *t.effects {
+		if def, _ := FindStatus(effect.Type); match(def) {
+			msgs = append(msgs, StatusMessage{fmt.Sprintf(format, t.name, def.Name), kind})
+			continue
 		}
+		kept = append(kept, effect)
+	}
+	*t.effects = kept
+	if len(msgs) == 0 {
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("Nothing to remove from %s.", t.name), "combat"})
+	}
+	return msgs
+}
 
-		effect.Duration--
+// StatusAttackMod totals the attack change from active effects.
+func StatusAttackMod(effects []models.StatusEffect) int {
+	total := 0
+	for _, e := range effects {
+		def, _ := FindStatus(e.Type)
+		total += def.AttackScale * e.Potency * stacks(e)
+	}
+	return total
+}
 
-		if effect.Duration <= 0 {
-			switch effect.Type {
-			case "buff_attack":
-				mob.StatsMod.AttackMod -= effect.Potency
-			case "buff_defense":
-				mob.StatsMod.DefenseMod -= effect.Potency
+// StatusDefenseMod totals the defense change from active effects.
+func StatusDefenseMod(effects []models.StatusEffect) int {
+	total := 0
+	for _, e := range effects {
+		def, _ := FindStatus(e.Type)
+		total += def.DefenseScale * e.Potency * stacks(e)
+	}
+	return total
+}
+
+// StatusSpeedMod totals the speed change from active effects.
+func StatusSpeedMod(effects []models.StatusEffect) int {
+	total := 0
+	for _, e := range effects {
+		def, _ := FindStatus(e.Type)
+		total += def.SpeedScale * e.Potency * stacks(e)
+	}
+	return total
+}
+
+// AttackMod is a combatant's attack modifier: gear plus active effects.
+func AttackMod(target interface{}) int {
+	t := statusTargetOf(target)
+	if t == nil {
+		return 0
+	}
+	return t.statsMod.AttackMod + StatusAttackMod(*t.effects)
+}
+
+// DefenseMod is a combatant's defense modifier: gear plus active effects.
+func DefenseMod(target interface{}) int {
+	t := statusTargetOf(target)
+	if t == nil {
+		return 0
+	}
+	return t.statsMod.DefenseMod + StatusDefenseMod(*t.effects)
+}
+
+// SkipsTurn reports whether an active effect (stun, freeze) costs the turn.
+func SkipsTurn(effects []models.StatusEffect) bool {
+	for _, e := range effects {
+		if def, _ := FindStatus(e.Type); def.SkipsTurn {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSilenced reports whether an active effect blocks skill use.
+func IsSilenced(effects []models.StatusEffect) bool {
+	for _, e := range effects {
+		if def, _ := FindStatus(e.Type); def.Silences {
+			return true
+		}
+	}
+	return false
+}
+
+// absorbDamage lets the target's shields soak up damage, using them up, and
+// returns what gets through.
+func absorbDamage(t *statusTarget, damage int) int {
+	kept := (*t.effects)[:0]
+	for _, effect := range *t.effects {
+		if def, _ := FindStatus(effect.Type); def.Absorbs && damage > 0 {
+			soaked := min(effect.Potency, damage)
+			damage -= soaked
+			effect.Potency -= soaked
+			if effect.Potency <= 0 {
+				continue
 			}
-			fmt.Printf("%s's %s effect has worn off.\n", mob.Name, effect.Type)
-			mob.StatusEffects = append(mob.StatusEffects[:i], mob.StatusEffects[i+1:]...)
 		}
+		kept = append(kept, effect)
 	}
+	*t.effects = kept
+	return damage
 }
 
-// ApplyDamage calculates final damage after applying elemental resistance modifiers.
-// The target can be either a *models.Character or a *models.Monster.
+// ApplyDamage calculates final damage after elemental resistance modifiers
+// and the target's shields, which it uses up. The target can be a
+// *models.Character, *models.Monster or *models.Guard.
 func ApplyDamage(damage int, damageType models.DamageType, target interface{}) int {
 	resistance := 1.0
 
@@ -96,31 +338,34 @@ func ApplyDamage(damage int, damageType models.DamageType, target interface{}) i
 		if res, ok := t.Resistances[damageType]; ok {
 			resistance = res
 		}
+		if pct := t.StatsMod.Effects[resistEffects[damageType]]; pct > 0 {
+			resistance *= float64(100-min(pct, MaxItemResist)) / 100
+		}
 	case *models.Monster:
 		if res, ok := t.Resistances[damageType]; ok {
 			resistance = res
 		}
+	case *models.Guard:
+		if res, ok := t.Resistances[damageType]; ok {
+			resistance = res
+		}
 	}
 
-	return int(float64(damage) * resistance)
+	final := int(float64(damage) * resistance)
+	if t := statusTargetOf(target); t != nil {
+		final = absorbDamage(t, final)
+	}
+	return final
 }
 
-// IsStunned checks whether a character has an active stun status effect.
+// IsStunned checks whether a character has an active stun, freeze or other
+// turn-skipping effect.
 func IsStunned(character *models.Character) bool {
-	for _, effect := range character.StatusEffects {
-		if effect.Type == "stun" {
-			return true
-		}
-	}
-	return false
+	return SkipsTurn(character.StatusEffects)
 }
 
-// IsStunnedMob checks whether a monster has an active stun status effect.
+// IsStunnedMob checks whether a monster has an active stun, freeze or other
+// turn-skipping effect.
 func IsStunnedMob(mob *models.Monster) bool {
-	for _, effect := range mob.StatusEffects {
-		if effect.Type == "stun" {
-			return true
-		}
-	}
-	return false
+	return SkipsTurn(mob.StatusEffects)
 }
@@ -0,0 +1,119 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestStatusStackingPolicies(t *testing.T) {
+	mob := GenerateMonster("wolf", 5, 2)
+
+	for i := 0; i < 7; i++ {
+		ApplyStatus(&mob, models.StatusEffect{Type: "bleed", Duration: 3, Potency: 2})
+	}
+	if len(mob.StatusEffects) != 1 || mob.StatusEffects[0].Stacks != 5 {
+		t.Fatalf("bleed should stack up to 5, got %+v", mob.StatusEffects)
+	}
+
+	ApplyStatus(&mob, models.StatusEffect{Type: "burn", Duration: 2, Potency: 3})
+	ApplyStatus(&mob, models.StatusEffect{Type: "burn", Duration: 4, Potency: 1})
+	burn := mob.StatusEffects[1]
+	if burn.Duration != 4 || burn.Potency != 3 || stacks(burn) != 1 {
+		t.Errorf("burn should refresh to the longer duration and stronger potency, got %+v", burn)
+	}
+
+	ApplyStatus(&mob, models.StatusEffect{Type: "stun", Duration: 1})
+	if _, ok := ApplyStatus(&mob, models.StatusEffect{Type: "stun", Duration: 3}); ok {
+		t.Error("reapplying an active stun should be ignored")
+	}
+
+	hp := mob.HitpointsRemaining
+	TickStatusEffects(&mob)
+	if want := hp - 2*5 - 3; mob.HitpointsRemaining != want {
+		t.Errorf("expected bleed x5 and burn to deal 13, HP %d -> %d", hp, mob.HitpointsRemaining)
+	}
+	if IsStunnedMob(&mob) {
+		t.Error("a one-turn stun should wear off after a tick")
+	}
+}
+
+func TestStatusImmunities(t *testing.T) {
+	skeleton := GenerateMonster("skeleton", 5, 2)
+	if msg, ok := ApplyStatus(&skeleton, models.StatusEffect{Type: "poison", Duration: 3, Potency: 4}); ok || len(skeleton.StatusEffects) != 0 {
+		t.Fatalf("undead should be immune to poison, got %q", msg.Text)
+	}
+	if _, ok := ApplyStatus(&skeleton, models.StatusEffect{Type: "burn", Duration: 3, Potency: 4}); !ok {
+		t.Error("undead should still burn")
+	}
+	player := GenerateCharacter("Hero", 1, 1)
+	if IsImmune(&player, "poison") {
+		t.Error("characters have no innate immunities")
+	}
+}
+
+func TestStatusModifiersLeaveStatsModAlone(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	player.StatsMod.AttackMod = 4
+	mob := GenerateMonster("wolf", 5, 2)
+
+	ApplySkillEffect(&player, &mob, models.StatusEffect{Type: "buff_attack", Duration: 1, Potency: 5})
+	ApplySkillEffect(&mob, &player, models.StatusEffect{Type: "weaken", Duration: 2, Potency: 2})
+	if AttackMod(&player) != 7 || player.StatsMod.AttackMod != 4 {
+		t.Fatalf("expected attack 4+5-2 with StatsMod untouched, got %d (StatsMod %d)", AttackMod(&player), player.StatsMod.AttackMod)
+	}
+	TickStatusEffects(&player)
+	if AttackMod(&player) != 2 {
+		t.Errorf("expected the buff to expire leaving 4-2, got %d", AttackMod(&player))
+	}
+
+	ApplyStatus(&mob, models.StatusEffect{Type: "freeze", Duration: 1, Potency: 3})
+	if DefenseMod(&mob) != mob.StatsMod.DefenseMod-3 || !IsStunnedMob(&mob) {
+		t.Error("a frozen monster should lose its turn and 3 defense")
+	}
+}
+
+func TestShieldAbsorbsDamage(t *testing.T) {
+	guard := GenerateGuard(3)
+	ApplyStatus(&guard, models.StatusEffect{Type: "shield", Duration: 3, Potency: 10})
+	if got := ApplyDamage(6, models.Physical, &guard); got != 0 {
+		t.Errorf("shield should absorb the whole hit, %d got through", got)
+	}
+	if got := ApplyDamage(6, models.Physical, &guard); got != 2 {
+		t.Errorf("shield should absorb its last 4 points, %d got through", got)
+	}
+	if len(guard.StatusEffects) != 0 {
+		t.Error("a used up shield should be removed")
+	}
+}
+
+func TestCleanseAndDispel(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	mob := GenerateMonster("wolf", 5, 2)
+	for _, e := range []models.StatusEffect{
+		{Type: "poison", Duration: 3, Potency: 2},
+		{Type: "silence", Duration: 2},
+		{Type: "regen", Duration: 3, Potency: 2},
+	} {
+		ApplyStatus(&player, e)
+	}
+	if !IsSilenced(player.StatusEffects) {
+		t.Fatal("player should be silenced")
+	}
+
+	Cleanse(&player, "poison")
+	if len(player.StatusEffects) != 2 || !IsSilenced(player.StatusEffects) {
+		t.Fatalf("cleansing poison should leave silence and regen, got %+v", player.StatusEffects)
+	}
+	ApplySkillEffect(&player, &mob, models.StatusEffect{Type: StatusCleanse})
+	if len(player.StatusEffects) != 1 || player.StatusEffects[0].Type != "regen" {
+		t.Fatalf("a full cleanse should keep only buffs, got %+v", player.StatusEffects)
+	}
+
+	ApplyStatus(&mob, models.StatusEffect{Type: "buff_defense", Duration: 3, Potency: 8})
+	ApplyStatus(&mob, models.StatusEffect{Type: "bleed", Duration: 3, Potency: 1})
+	ApplySkillEffect(&player, &mob, models.StatusEffect{Type: StatusDispel})
+	if len(mob.StatusEffects) != 1 || mob.StatusEffects[0].Type != "bleed" {
+		t.Errorf("dispel should strip only the monster's buffs, got %+v", mob.StatusEffects)
+	}
+}
@@ -0,0 +1,309 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// QualityTier is the grade of a crafted item. Bonus is the % added to the
+// recipe's stat bonus, potion healing or trap damage.
+type QualityTier struct {
+	Name  string
+	Bonus int
+}
+
+// QualityTiers lists the grades from worst to best with the minimum quality
+// roll each needs.
+var QualityTiers = []struct {
+	MinRoll int
+	Tier    QualityTier
+}{
+	{0, QualityTier{"Crude", -15}},
+	{15, QualityTier{"Standard", 0}},
+	{75, QualityTier{"Fine", 15}},
+	{100, QualityTier{"Masterwork", 30}},
+}
+
+// QualityPerSkill is how far each point of crafter skill raises the quality
+// roll.
+const QualityPerSkill = 4
+
+// CraftResult describes what a recipe produced.
+type CraftResult struct {
+	Recipe  models.CraftingRecipe
+	Quality QualityTier
+	Item    *models.Item  // potions and equipment
+	Trap    *models.Trap  // traps
+	Skill   *models.Skill // learned from a scroll
+	Stored  bool          // false when Item didn't fit anywhere
+	Stashed bool          // Item went to the village stash
+}
+
+// FindRecipe looks up a recipe in the registry by ID.
+func FindRecipe(id string) (models.CraftingRecipe, bool) {
+	for _, r := range data.CraftingRecipes {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return models.CraftingRecipe{}, false
+}
+
+// KnowsRecipe reports whether the player can see the recipe: ordinary
+// recipes are always known, discoverable ones once found.
+func KnowsRecipe(player *models.Character, r models.CraftingRecipe) bool {
+	return !r.Discoverable || Contains(player.KnownRecipes, r.ID)
+}
+
+// KnownRecipesOfType lists the recipes of one type the player knows, in
+// registry order.
+func KnownRecipesOfType(player *models.Character, recipeType string) []models.CraftingRecipe {
+	recipes := []models.CraftingRecipe{}
+	for _, r := range data.CraftingRecipes {
+		if r.Type == recipeType && KnowsRecipe(player, r) {
+			recipes = append(recipes, r)
+		}
+	}
+	return recipes
+}
+
+// RecipeLocked reports why the village can't craft r yet, or "" if it can.
+func RecipeLocked(player *models.Character, village *models.Village, r models.CraftingRecipe) string {
+	switch {
+	case !KnowsRecipe(player, r):
+		return "not yet discovered"
+	case village.Level < r.RequiredLevel:
+		return fmt.Sprintf("needs village level %d", r.RequiredLevel)
+	case r.RequiredCrafting != "" && !Contains(village.UnlockedCrafting, r.RequiredCrafting):
+		return fmt.Sprintf("needs %s crafting unlocked", strings.ReplaceAll(r.RequiredCrafting, "_", " "))
+	case r.RequiredBuilding != "" && !HasBuilding(village, r.RequiredBuilding):
+		return "needs a " + r.RequiredBuilding
+	}
+	return ""
+}
+
+// RecipeCostText lists a recipe's resources for display.
+func RecipeCostText(r models.CraftingRecipe) string {
+	names := make([]string, 0, len(r.RequiredResources))
+	for name := range r.RequiredResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := []string{}
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, r.RequiredResources[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CrafterSkill is the skill of the village's best villager, which raises
+// the quality of everything crafted there.
+func CrafterSkill(village *models.Village) int {
+	best := 0
+	for _, v := range village.Villagers {
+		best = max(best, v.Efficiency+v.Level/2)
+	}
+	return best
+}
+
+// RollQuality picks the quality tier of one craft of a type in the village.
+func RollQuality(village *models.Village, craftType string) QualityTier {
+	roll := rand.Intn(100) + CrafterSkill(village)*QualityPerSkill + CraftingQualityBonus(village, craftType)
+	tier := QualityTiers[0].Tier
+	for _, q := range QualityTiers {
+		if roll >= q.MinRoll {
+			tier = q.Tier
+		}
+	}
+	return tier
+}
+
+// withQuality scales a stat bonus by a quality tier.
+func withQuality(v int, q QualityTier) int {
+	return v * (100 + q.Bonus) / 100
+}
+
+// checkRecipe reports why the player can't craft r right now, if they
+// can't.
+func checkRecipe(player *models.Character, village *models.Village, r models.CraftingRecipe) error {
+	if reason := RecipeLocked(player, village, r); reason != "" {
+		return fmt.Errorf("%s %s", r.Name, reason)
+	}
+	return checkCost(player, r.RequiredResources)
+}
+
+// payRecipe deducts a recipe's resources.
+func payRecipe(player *models.Character, r models.CraftingRecipe) {
+	reason := ReasonCrafting
+	if r.Type == "traps" {
+		reason = ReasonBuilding
+	}
+	payCost(player, r.RequiredResources, reason, "craft:"+r.ID)
+}
+
+// CraftRecipe crafts a potion, equipment, trap or skill scroll recipe in the
+// village at once, charging its resources and granting its village XP.
+// Skill upgrades go through CraftSkillUpgrade instead.
+func CraftRecipe(player *models.Character, village *models.Village, r models.CraftingRecipe) (CraftResult, error) {
+	result := CraftResult{Recipe: r, Quality: QualityTiers[1].Tier}
+	if err := checkCraft(player, village, r); err != nil {
+		return result, err
+	}
+	if r.Output.ItemType != "" && r.Output.ItemType != "equipment" && !HasRoomFor(player.Inventory, InventoryCapacity(player), r.Output) {
+		return result, fmt.Errorf("your inventory is full; store something in the stash first")
+	}
+	payRecipe(player, r)
+	return finishRecipe(player, village, r), nil
+}
+
+// checkCraft reports why the player can't craft r right now, including
+// scrolls of skills they can't learn.
+func checkCraft(player *models.Character, village *models.Village, r models.CraftingRecipe) error {
+	if r.Type == "skill_upgrades" {
+		return fmt.Errorf("choose a skill to upgrade")
+	}
+	if r.SkillName != "" {
+		skill, ok := findSkill(r.SkillName)
+		if !ok {
+			return fmt.Errorf("unknown skill %s", r.SkillName)
+		}
+		if findLearnedSkill(player, skill.Name) >= 0 {
+			return fmt.Errorf("you already know %s", skill.Name)
+		}
+		if !CanLearnSkill(player, skill.Name) {
+			return fmt.Errorf("a %s cannot learn %s", Classes[player.Class].Name, skill.Name)
+		}
+	}
+	return checkRecipe(player, village, r)
+}
+
+// finishRecipe produces a paid-for recipe and grants its village XP. A
+// scroll of a skill learned in the meantime gives it data.SkillStudyXP
+// instead, and a potion that doesn't fit in the inventory goes to the
+// village stash if it can.
+func finishRecipe(player *models.Character, village *models.Village, r models.CraftingRecipe) CraftResult {
+	result := CraftResult{Recipe: r, Quality: QualityTiers[1].Tier}
+	switch {
+	case r.SkillName != "":
+		skill, _ := findSkill(r.SkillName)
+		if idx := findLearnedSkill(player, skill.Name); idx >= 0 {
+			GainSkillXP(&player.LearnedSkills[idx], data.SkillStudyXP)
+			skill = player.LearnedSkills[idx]
+		} else {
+			player.LearnedSkills = append(player.LearnedSkills, skill)
+		}
+		result.Skill = &skill
+
+	case r.Trap.Name != "":
+		result.Quality = RollQuality(village, r.Type)
+		trap := r.Trap
+		trap.Damage = withQuality(trap.Damage, result.Quality)
+		trap.Remaining = trap.Duration
+		village.Traps = append(village.Traps, trap)
+		result.Trap = &trap
+
+	case r.Output.ItemType == "equipment":
+		result.Quality = RollQuality(village, r.Type)
+		rarity := r.RarityMin + rand.Intn(max(r.RarityMax-r.RarityMin, 0)+1)
+		item := GenerateItem(rarity)
+		item.StatsMod.AttackMod += withQuality(r.Output.StatsMod.AttackMod+r.PerRarity.AttackMod*rarity, result.Quality)
+		item.StatsMod.DefenseMod += withQuality(r.Output.StatsMod.DefenseMod+r.PerRarity.DefenseMod*rarity, result.Quality)
+		item.StatsMod.HitPointMod += withQuality(r.Output.StatsMod.HitPointMod+r.PerRarity.HitPointMod*rarity, result.Quality)
+		item.CP = ItemPower(item)
+		result.Stored = LootItem(player, item, ReasonCrafting, "craft:"+r.ID)
+		RecalculatePlayerStats(player)
+		result.Item = &item
+
+	default:
+		item := r.Output
+		result.Quality = RollQuality(village, r.Type)
+		if result.Quality.Bonus != 0 {
+			item.Name = result.Quality.Name + " " + item.Name
+			item.Consumable.Value = withQuality(item.Consumable.Value, result.Quality)
+		}
+		result.Stored = LootItem(player, item, ReasonCrafting, "craft:"+r.ID)
+		if !result.Stored && HasRoomFor(village.Stash, StashCapacity(village), item) {
+			AddItemToInventory(&village.Stash, item)
+			result.Stored, result.Stashed = true, true
+		}
+		result.Item = &item
+	}
+
+	village.Experience += r.VillageXP
+	return result
+}
+
+// CraftSkillUpgrade applies a skill upgrade recipe to the learned skill at
+// skillIdx and returns a line per change.
+func CraftSkillUpgrade(player *models.Character, village *models.Village, r models.CraftingRecipe, skillIdx int) ([]string, error) {
+	if r.Type != "skill_upgrades" {
+		return nil, fmt.Errorf("%s is not a skill upgrade", r.Name)
+	}
+	if skillIdx < 0 || skillIdx >= len(player.LearnedSkills) {
+		return nil, fmt.Errorf("no such skill")
+	}
+	if err := checkRecipe(player, village, r); err != nil {
+		return nil, err
+	}
+	payRecipe(player, r)
+	lines := ApplySkillUpgrade(&player.LearnedSkills[skillIdx], r.SkillUpgrade)
+	village.Experience += r.VillageXP
+	return lines, nil
+}
+
+// findLearnedSkill is the index of the player's learned skill with the given
+// name, or -1.
+func findLearnedSkill(player *models.Character, name string) int {
+	for i, s := range player.LearnedSkills {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// findSkill looks up one of data.AvailableSkills by name.
+func findSkill(name string) (models.Skill, bool) {
+	for _, s := range data.AvailableSkills {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return models.Skill{}, false
+}
+
+// DiscoverRecipe teaches the player a random discoverable recipe they don't
+// know yet. It reports false once every recipe has been found.
+func DiscoverRecipe(player *models.Character) (models.CraftingRecipe, bool) {
+	unknown := []models.CraftingRecipe{}
+	for _, r := range data.CraftingRecipes {
+		if !KnowsRecipe(player, r) {
+			unknown = append(unknown, r)
+		}
+	}
+	if len(unknown) == 0 {
+		return models.CraftingRecipe{}, false
+	}
+	r := unknown[rand.Intn(len(unknown))]
+	player.KnownRecipes = append(player.KnownRecipes, r.ID)
+	return r, true
+}
+
+// Recipe discovery chances, in %.
+const (
+	RecipeDropChance  = 3  // a defeated monster carries a recipe
+	QuestRecipeChance = 25 // an NPC shares one on completing their quest
+)
+
+// RollRecipeDrop may teach the player a recipe found on a defeated monster.
+func RollRecipeDrop(player *models.Character) (models.CraftingRecipe, bool) {
+	if rand.Intn(100) >= RecipeDropChance {
+		return models.CraftingRecipe{}, false
+	}
+	return DiscoverRecipe(player)
+}
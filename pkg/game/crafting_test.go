@@ -0,0 +1,135 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// craftingVillage is a village with every crafting type unlocked.
+func craftingVillage(level int) models.Village {
+	return models.Village{Name: "Forge", Level: level,
+		UnlockedCrafting: []string{"potions", "armor", "weapons", "skill_upgrades", "skill_scrolls"}}
+}
+
+// fund gives the player what recipe r costs.
+func fund(player *models.Character, r models.CraftingRecipe) {
+	for name, n := range r.RequiredResources {
+		AdjustResource(player, name, n, ReasonMonsterDrop, "test")
+	}
+}
+
+func TestRecipeRegistry(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range data.CraftingRecipes {
+		if r.ID == "" || seen[r.ID] {
+			t.Errorf("recipe %q has a missing or duplicate ID", r.Name)
+		}
+		seen[r.ID] = true
+		if len(r.RequiredResources) == 0 {
+			t.Errorf("recipe %s costs nothing", r.ID)
+		}
+		if r.SkillName != "" {
+			if _, ok := findSkill(r.SkillName); !ok {
+				t.Errorf("recipe %s teaches unknown skill %s", r.ID, r.SkillName)
+			}
+		}
+	}
+}
+
+func TestRecipeLocked(t *testing.T) {
+	player := GenerateCharacter("Smith", 1, 1)
+	village := craftingVillage(10)
+	hammer, _ := FindRecipe("composite_war_hammer")
+	if RecipeLocked(&player, &village, hammer) == "" {
+		t.Error("war hammer should need a Blacksmith")
+	}
+	village.Buildings = []models.VillageBuilding{{Name: BuildingBlacksmith, Level: 1}}
+	if reason := RecipeLocked(&player, &village, hammer); reason != "" {
+		t.Errorf("war hammer should be craftable, got %q", reason)
+	}
+	village.Level = 5
+	if RecipeLocked(&player, &village, hammer) == "" {
+		t.Error("war hammer should need village level 7")
+	}
+
+	dagger, _ := FindRecipe("serpent_fang_dagger")
+	village.Level = 10
+	if KnowsRecipe(&player, dagger) || RecipeLocked(&player, &village, dagger) == "" {
+		t.Error("discoverable recipes start unknown")
+	}
+	for _, r := range KnownRecipesOfType(&player, "weapons") {
+		if r.ID == dagger.ID {
+			t.Error("unknown recipes should not be listed")
+		}
+	}
+}
+
+func TestCraftRecipeCharges(t *testing.T) {
+	player := GenerateCharacter("Brewer", 1, 1)
+	village := craftingVillage(3)
+	r, _ := FindRecipe("small_potion")
+	if _, err := CraftRecipe(&player, &village, r); err == nil {
+		t.Fatal("crafting should fail without resources")
+	}
+	fund(&player, r)
+	iron := ResourceBalance(&player, "Iron")
+	result, err := CraftRecipe(&player, &village, r)
+	if err != nil || result.Item == nil || !result.Stored {
+		t.Fatalf("expected a stored potion, got %+v err=%v", result, err)
+	}
+	if ResourceBalance(&player, "Iron") != iron-r.RequiredResources["Iron"] || village.Experience != r.VillageXP {
+		t.Error("crafting should charge the recipe and grant its village XP")
+	}
+}
+
+func TestQualityScalesWithCrafterSkill(t *testing.T) {
+	village := craftingVillage(5)
+	for i := 0; i < 20; i++ {
+		if q := RollQuality(&village, "armor"); q.Name == "Masterwork" {
+			t.Fatal("an unskilled village should not roll masterwork")
+		}
+	}
+	village.Villagers = []models.Villager{{Name: "Master", Efficiency: 25, Level: 10}}
+	for i := 0; i < 20; i++ {
+		if q := RollQuality(&village, "armor"); q.Name != "Masterwork" {
+			t.Fatalf("a master crafter should always roll masterwork, got %s", q.Name)
+		}
+	}
+	if withQuality(100, QualityTier{"Fine", 15}) != 115 {
+		t.Error("fine quality should add 15%")
+	}
+}
+
+func TestCraftSkillUpgrade(t *testing.T) {
+	player := GenerateCharacter("Mage", 1, 1)
+	player.LearnedSkills = []models.Skill{{Name: "Bolt", Damage: 10, ManaCost: 8}}
+	village := craftingVillage(10)
+	r, _ := FindRecipe("skill_upgrade")
+	fund(&player, r)
+	if _, err := CraftSkillUpgrade(&player, &village, r, 0); err != nil {
+		t.Fatal(err)
+	}
+	if s := player.LearnedSkills[0]; s.Damage != 15 || s.ManaCost != 6 || s.UpgradeCount != 1 {
+		t.Errorf("unexpected upgraded skill %+v", s)
+	}
+}
+
+func TestDiscoverRecipe(t *testing.T) {
+	player := GenerateCharacter("Finder", 1, 1)
+	found := 0
+	for {
+		r, ok := DiscoverRecipe(&player)
+		if !ok {
+			break
+		}
+		if !r.Discoverable || !KnowsRecipe(&player, r) {
+			t.Fatalf("unexpected discovery %+v", r)
+		}
+		found++
+	}
+	if found == 0 || len(player.KnownRecipes) != found {
+		t.Errorf("expected every discoverable recipe learned once, got %v", player.KnownRecipes)
+	}
+}
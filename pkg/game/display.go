@@ -73,7 +73,7 @@ func ShowInventory(player *models.Character) {
 			fmt.Println("\nCONSUMABLES:")
 			potionCount := make(map[string]int)
 			for _, item := range consumables {
-				potionCount[item.Name]++
+				potionCount[item.Name] += StackSize(item)
 			}
 			for name, count := range potionCount {
 				fmt.Printf("  %s x%d\n", name, count)
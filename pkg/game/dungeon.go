@@ -137,6 +137,7 @@ func GenerateDungeon(template data.DungeonTemplate, seed int64) models.Dungeon {
 // GenerateDungeonBoss creates a boss monster with amplified stats.
 // The boss is generated using GenerateMonster, then receives IsBoss=true,
 // Rarity=Legendary, HP multiplied by 5x, and attack/defense multiplied by 3x.
+// Its BossID scripts the fight: a warlord, or an overlord on deep floors.
 func GenerateDungeonBoss(floor int, baseLevel int, baseRank int) models.Monster {
 	// Pick a random monster name for the boss
 	name := data.MonsterNames[rand.Intn(len(data.MonsterNames))]
@@ -153,6 +154,7 @@ func GenerateDungeonBoss(floor int, baseLevel int, baseRank int) models.Monster
 	boss := GenerateMonster(name, level, rank)
 
 	boss.IsBoss = true
+	boss.BossID = DungeonBossID(floor)
 	boss.Rarity = models.RarityLegendary
 
 	// Multiply HP by 5x
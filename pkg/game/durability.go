@@ -0,0 +1,284 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// Durability tuning.
+const (
+	BaseDurability      = 30
+	DurabilityPerRarity = 10
+	CombatWear          = 1  // per fight, on every equipped item
+	DeathWearPct        = 10 // % of max durability lost on death
+	TideWear            = 2  // per tide wave, on every hired guard's item
+
+	RepairKitName         = "Repair Kit"
+	RepairKitPoints       = 40 // durability points one kit covers
+	RepairKitIronCost     = 5
+	RepairKitMaterialCost = 2 // beast materials
+
+	RepairDiscountPerVillageLevel = 3  // % off gold at a village blacksmith
+	MaxRepairDiscount             = 45 // %
+)
+
+// MaxDurabilityFor is the durability of a new item of the given rarity.
+func MaxDurabilityFor(rarity int) int {
+	return BaseDurability + DurabilityPerRarity*max(rarity, 1)
+}
+
+// EnsureDurability gives equipment from before durability existed a full
+// durability bar.
+func EnsureDurability(item *models.Item) {
+	if item.ItemType == "equipment" && item.MaxDurability == 0 {
+		item.MaxDurability = MaxDurabilityFor(item.Rarity)
+		item.Durability = item.MaxDurability
+	}
+}
+
+// IsBroken reports whether an item has worn down to zero durability.
+func IsBroken(item models.Item) bool {
+	return item.MaxDurability > 0 && item.Durability <= 0
+}
+
+// MissingDurability is how many points a repair would restore.
+func MissingDurability(item models.Item) int {
+	if item.MaxDurability == 0 {
+		return 0
+	}
+	return item.MaxDurability - item.Durability
+}
+
+// wearItem reduces an item's durability and reports whether it just broke.
+func wearItem(item *models.Item, amount int) bool {
+	EnsureDurability(item)
+	if item.Durability <= 0 {
+		return false
+	}
+	item.Durability = max(item.Durability-amount, 0)
+	return item.Durability == 0
+}
+
+// WearEquipment reduces every equipped item's durability by amount and
+// returns the names of items that broke.
+func WearEquipment(equipment map[int]models.Item, amount int) []string {
+	broken := []string{}
+	for slot, item := range equipment {
+		if item.ItemType != "equipment" {
+			continue
+		}
+		if wearItem(&item, amount) {
+			broken = append(broken, item.Name)
+		}
+		equipment[slot] = item
+	}
+	sort.Strings(broken)
+	return broken
+}
+
+// WearOnDeath applies the death penalty of DeathWearPct of each item's max
+// durability to a character's gear.
+func WearOnDeath(player *models.Character) []string {
+	broken := []string{}
+	for slot, item := range player.EquipmentMap {
+		if item.ItemType != "equipment" {
+			continue
+		}
+		EnsureDurability(&item) // MaxDurability sets the penalty
+		if wearItem(&item, max(item.MaxDurability*DeathWearPct/100, 1)) {
+			broken = append(broken, item.Name)
+		}
+		player.EquipmentMap[slot] = item
+	}
+	sort.Strings(broken)
+	return broken
+}
+
+// WearGuardEquipment wears a guard's gear and refreshes its stat mods.
+func WearGuardEquipment(guard *models.Guard, amount int) []string {
+	if guard.EquipmentMap == nil {
+		return nil
+	}
+	broken := WearEquipment(guard.EquipmentMap, amount)
+	guard.StatsMod = CalculateItemMods(guard.EquipmentMap)
+	return broken
+}
+
+// RepairDiscount is the gold discount at a village's blacksmith, which grows
+// with the village and the blacksmith's level.
+func RepairDiscount(village *models.Village) int {
+	if village == nil {
+		return 0
+	}
+	discount := village.Level*RepairDiscountPerVillageLevel + BuildingLevel(village, BuildingBlacksmith)*BlacksmithDiscountPerLevel
+	return min(discount, MaxRepairDiscount)
+}
+
+// RepairCost is the price of restoring durability on a set of items.
+type RepairCost struct {
+	Points    int // durability restored
+	KitPoints int // points covered by repair kits
+	Kits      int // repair kits consumed
+	Gold      int
+	Materials int // beast materials
+}
+
+// CountRepairKits counts the repair kits in an inventory.
+func CountRepairKits(inventory []models.Item) int {
+	n := 0
+	for _, item := range inventory {
+		if item.ItemType == "repair_kit" {
+			n += StackSize(item)
+		}
+	}
+	return n
+}
+
+// EquipmentSlots lists the occupied slots of an equipment map in order.
+func EquipmentSlots(equipment map[int]models.Item) []int {
+	slots := make([]int, 0, len(equipment))
+	for slot := range equipment {
+		slots = append(slots, slot)
+	}
+	sort.Ints(slots)
+	return slots
+}
+
+// QuoteRepair prices repairing the items in the given slots of equipment
+// (the player's own or a guard's) with discountPct off the gold. The
+// player's repair kits cover points first.
+func QuoteRepair(player *models.Character, equipment map[int]models.Item, slots []int, discountPct int) RepairCost {
+	balance := config.Current().Balance
+	cost := RepairCost{}
+	kitCapacity := CountRepairKits(player.Inventory) * RepairKitPoints
+
+	paidPoints := 0
+	for _, slot := range slots {
+		item, ok := equipment[slot]
+		if !ok {
+			continue
+		}
+		missing := MissingDurability(item)
+		covered := min(missing, kitCapacity)
+		kitCapacity -= covered
+		cost.Points += missing
+		cost.KitPoints += covered
+		paid := missing - covered
+		paidPoints += paid
+		cost.Gold += paid * balance.RepairGoldPerPoint * (1 + item.Rarity/3)
+	}
+	cost.Kits = (cost.KitPoints + RepairKitPoints - 1) / RepairKitPoints
+	cost.Gold = cost.Gold * (100 - discountPct) / 100
+	cost.Materials = (paidPoints + balance.RepairPointsPerMaterial - 1) / balance.RepairPointsPerMaterial
+	return cost
+}
+
+// BeastMaterialStock totals the player's beast materials.
+func BeastMaterialStock(player *models.Character) int {
+	total := 0
+	for _, name := range data.BeastMaterials {
+		total += ResourceBalance(player, name)
+	}
+	return total
+}
+
+// RepairEquipment restores the items in the given slots of equipment to full
+// durability, charging the player gold, beast materials and repair kits as
+// quoted. Callers refresh the owner's stats afterwards.
+func RepairEquipment(player *models.Character, equipment map[int]models.Item, slots []int, discountPct int, ref string) (RepairCost, error) {
+	cost := QuoteRepair(player, equipment, slots, discountPct)
+	if cost.Points == 0 {
+		return cost, fmt.Errorf("nothing needs repair")
+	}
+	if GoldBalance(player) < cost.Gold {
+		return cost, fmt.Errorf("repair costs %d gold, you have %d", cost.Gold, GoldBalance(player))
+	}
+	if BeastMaterialStock(player) < cost.Materials {
+		return cost, fmt.Errorf("repair needs %d beast materials, you have %d", cost.Materials, BeastMaterialStock(player))
+	}
+
+	if cost.Gold > 0 {
+		AdjustGold(player, -cost.Gold, ReasonRepair, ref)
+	}
+	spendBeastMaterials(player, cost.Materials, ReasonRepair, ref)
+	for used := 0; used < cost.Kits; {
+		for i, item := range player.Inventory {
+			if item.ItemType == "repair_kit" {
+				RemoveItemFromInventory(&player.Inventory, i)
+				RecordItemChange(player, item.Name, -1, ReasonRepair, ref)
+				used++
+				break
+			}
+		}
+	}
+
+	for _, slot := range slots {
+		if item, ok := equipment[slot]; ok && item.MaxDurability > 0 {
+			item.Durability = item.MaxDurability
+			equipment[slot] = item
+		}
+	}
+	return cost, nil
+}
+
+// spendBeastMaterials takes n beast materials, drawing from the largest
+// stocks first.
+func spendBeastMaterials(player *models.Character, n int, reason, ref string) {
+	for n > 0 {
+		best, stock := "", 0
+		for _, name := range data.BeastMaterials {
+			if s := ResourceBalance(player, name); s > stock {
+				best, stock = name, s
+			}
+		}
+		if best == "" {
+			return
+		}
+		take := min(n, stock)
+		AdjustResource(player, best, -take, reason, ref)
+		n -= take
+	}
+}
+
+// CreateRepairKit returns a repair kit, crafted at a village blacksmith.
+func CreateRepairKit() models.Item {
+	return models.Item{
+		Name:     RepairKitName,
+		ItemType: "repair_kit",
+		Rarity:   1,
+		Slot:     -1,
+	}
+}
+
+// CraftRepairKit turns iron and beast materials into a repair kit.
+func CraftRepairKit(player *models.Character, ref string) error {
+	if ResourceBalance(player, "Iron") < RepairKitIronCost {
+		return fmt.Errorf("a repair kit needs %d Iron, you have %d", RepairKitIronCost, ResourceBalance(player, "Iron"))
+	}
+	if BeastMaterialStock(player) < RepairKitMaterialCost {
+		return fmt.Errorf("a repair kit needs %d beast materials, you have %d", RepairKitMaterialCost, BeastMaterialStock(player))
+	}
+	kit := CreateRepairKit()
+	if !HasRoomFor(player.Inventory, InventoryCapacity(player), kit) {
+		return fmt.Errorf("your inventory is full")
+	}
+	AdjustResource(player, "Iron", -RepairKitIronCost, ReasonCrafting, ref)
+	spendBeastMaterials(player, RepairKitMaterialCost, ReasonCrafting, ref)
+	LootItem(player, kit, ReasonCrafting, ref)
+	return nil
+}
+
+// DurabilityLabel renders an item's durability for display.
+func DurabilityLabel(item models.Item) string {
+	if item.MaxDurability == 0 {
+		return ""
+	}
+	if IsBroken(item) {
+		return fmt.Sprintf("BROKEN 0/%d", item.MaxDurability)
+	}
+	return fmt.Sprintf("%d/%d", item.Durability, item.MaxDurability)
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestWearBreaksItemAndRemovesStats(t *testing.T) {
+	sword := models.Item{Name: "Longsword", Slot: 5, ItemType: "equipment", Rarity: 3,
+		StatsMod: models.StatMod{AttackMod: 7}}
+	EnsureDurability(&sword)
+	if sword.MaxDurability != MaxDurabilityFor(3) || sword.Durability != sword.MaxDurability {
+		t.Fatalf("expected full durability, got %d/%d", sword.Durability, sword.MaxDurability)
+	}
+	equipment := map[int]models.Item{5: sword}
+	if broken := WearEquipment(equipment, sword.MaxDurability-1); len(broken) != 0 {
+		t.Fatalf("item broke early: %v", broken)
+	}
+	if CalculateItemMods(equipment).AttackMod != 7 {
+		t.Error("worn item should keep its stats")
+	}
+	if broken := WearEquipment(equipment, CombatWear); len(broken) != 1 || !IsBroken(equipment[5]) {
+		t.Fatalf("expected the sword to break, got %v", broken)
+	}
+	if CalculateItemMods(equipment).AttackMod != 0 {
+		t.Error("broken item should grant no stats")
+	}
+	if EquipScore(equipment[5], equipment) != 0 {
+		t.Error("broken item should score 0 for auto-equip")
+	}
+}
+
+func TestRepairUsesKitsAndDiscount(t *testing.T) {
+	player := GenerateCharacter("Smith", 1, 1)
+	helm := models.Item{Name: "Iron Helm", Slot: 0, ItemType: "equipment", Rarity: 3}
+	EnsureDurability(&helm)
+	helm.Durability = 0
+	player.EquipmentMap = map[int]models.Item{0: helm}
+	player.Inventory = append(player.Inventory, CreateRepairKit())
+
+	village := &models.Village{Level: 10}
+	cost := QuoteRepair(&player, player.EquipmentMap, []int{0}, RepairDiscount(village))
+	// 60 missing: the kit covers 40, 20 are paid at 2 gold each less 30%.
+	if cost.Points != 60 || cost.Kits != 1 || cost.Gold != 28 || cost.Materials != 1 {
+		t.Fatalf("unexpected quote %+v", cost)
+	}
+
+	if _, err := RepairEquipment(&player, player.EquipmentMap, []int{0}, RepairDiscount(village), "test"); err == nil {
+		t.Fatal("repair should fail without gold")
+	}
+	AdjustGold(&player, cost.Gold-GoldBalance(&player), ReasonMonsterDrop, "test")
+	AdjustResource(&player, "Beast Bone", 1, ReasonMonsterDrop, "test")
+	if _, err := RepairEquipment(&player, player.EquipmentMap, []int{0}, RepairDiscount(village), "test"); err != nil {
+		t.Fatal(err)
+	}
+	if player.EquipmentMap[0].Durability != helm.MaxDurability || CountRepairKits(player.Inventory) != 0 ||
+		GoldBalance(&player) != 0 || ResourceBalance(&player, "Beast Bone") != 0 {
+		t.Errorf("repair did not charge as quoted: %+v", player.EquipmentMap[0])
+	}
+}
+
+func TestGuardWearRefreshesStats(t *testing.T) {
+	shield := models.Item{Name: "Tower Shield", Slot: 6, ItemType: "equipment", Rarity: 1,
+		StatsMod: models.StatMod{DefenseMod: 4}}
+	guard := models.Guard{Name: "Bram", EquipmentMap: map[int]models.Item{6: shield}}
+	guard.StatsMod = CalculateItemMods(guard.EquipmentMap)
+	if broken := WearGuardEquipment(&guard, MaxDurabilityFor(1)); len(broken) != 1 {
+		t.Fatalf("expected the shield to break, got %v", broken)
+	}
+	if guard.StatsMod.DefenseMod != 0 {
+		t.Errorf("broken guard gear should grant no defense, got %d", guard.StatsMod.DefenseMod)
+	}
+}
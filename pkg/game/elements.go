@@ -0,0 +1,75 @@
+package game
+
+import (
+	"fmt"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// ElementModifier is how much the surroundings at location scale element
+// damage.
+func ElementModifier(location string, element models.DamageType) float64 {
+	if mod, ok := data.LocationElements[location][element]; ok {
+		return mod
+	}
+	return 1.0
+}
+
+// ElementalHit is elemental damage after the surroundings and reactions,
+// before the target's resistances.
+type ElementalHit struct {
+	Damage    int
+	Messages  []StatusMessage // reaction lines use the reaction's category
+	Reactions []string        // names of the reactions set off
+}
+
+// ApplyElements scales element damage by the fight's location and sets off
+// reactions with the target's status effects, using up their triggers and
+// applying their effects. Physical damage passes through unchanged.
+func ApplyElements(damage int, element models.DamageType, target interface{}, location string) ElementalHit {
+	hit := ElementalHit{Damage: damage}
+	if element == models.Physical || damage <= 0 {
+		return hit
+	}
+	if mod := ElementModifier(location, element); mod != 1.0 {
+		hit.Damage = int(float64(hit.Damage) * mod)
+		verb := "empowers"
+		if mod < 1.0 {
+			verb = "dampens"
+		}
+		hit.Messages = append(hit.Messages, StatusMessage{fmt.Sprintf("The %s %s %s.", location, verb, element), "combat"})
+	}
+
+	bonus := 0
+	for _, r := range data.ElementalReactions {
+		if r.Element != element {
+			continue
+		}
+		trigger := ""
+		for _, status := range r.Trigger {
+			if hasStatus(target, status) {
+				trigger = status
+				break
+			}
+		}
+		if trigger == "" {
+			continue
+		}
+		bonus += r.BonusPct
+		hit.Reactions = append(hit.Reactions, r.Name)
+		hit.Messages = append(hit.Messages, StatusMessage{fmt.Sprintf("%s! %s", r.Name, r.Description), r.Category})
+		if r.Consumes {
+			hit.Messages = append(hit.Messages, removeStatuses(target, func(def models.StatusDefinition) bool {
+				return def.Type == trigger
+			}, "%s's %s is gone.", r.Category)...)
+		}
+		if r.Effect.Type != "" {
+			if msg, _ := ApplyStatus(target, r.Effect); msg.Text != "" {
+				hit.Messages = append(hit.Messages, StatusMessage{msg.Text, r.Category})
+			}
+		}
+	}
+	hit.Damage = hit.Damage * (100 + bonus) / 100
+	return hit
+}
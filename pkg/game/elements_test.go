@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestMeltConsumesFreeze(t *testing.T) {
+	mob := GenerateMonster("wolf", 5, 2)
+	ApplyStatus(&mob, models.StatusEffect{Type: "freeze", Duration: 2, Potency: 1})
+
+	hit := ApplyElements(10, models.Fire, &mob, "Plains")
+	if hit.Damage != 20 || len(hit.Reactions) != 1 || hit.Reactions[0] != "Melt" {
+		t.Fatalf("expected Melt for double damage, got %+v", hit)
+	}
+	if hasStatus(&mob, "freeze") {
+		t.Error("Melt should use up the freeze")
+	}
+}
+
+func TestSteamAndChainLightning(t *testing.T) {
+	mob := GenerateMonster("wolf", 5, 2)
+	ApplyStatus(&mob, models.StatusEffect{Type: "burn", Duration: 3, Potency: 2})
+
+	ApplyElements(10, models.Ice, &mob, "Plains")
+	if hasStatus(&mob, "burn") || !hasStatus(&mob, "wet") {
+		t.Fatalf("Steam should put out the burn and soak the target, got %+v", mob.StatusEffects)
+	}
+	if hit := ApplyElements(10, models.Lightning, &mob, "Plains"); hit.Damage != 15 {
+		t.Errorf("Chain Lightning should add 50%% on a wet target, got %d", hit.Damage)
+	}
+	if !hasStatus(&mob, "wet") {
+		t.Error("Chain Lightning should not dry the target")
+	}
+}
+
+func TestToxicFumesRespectsImmunity(t *testing.T) {
+	wolf := GenerateMonster("wolf", 5, 2)
+	ApplyStatus(&wolf, models.StatusEffect{Type: "burn", Duration: 3, Potency: 2})
+	if hit := ApplyElements(8, models.Poison, &wolf, "Plains"); len(hit.Reactions) != 1 || !hasStatus(&wolf, "toxic_fumes") {
+		t.Fatalf("poison on a burning wolf should give off toxic fumes, got %+v", hit)
+	}
+
+	skeleton := GenerateMonster("skeleton", 5, 2)
+	ApplyStatus(&skeleton, models.StatusEffect{Type: "burn", Duration: 3, Potency: 2})
+	ApplyElements(8, models.Poison, &skeleton, "Plains")
+	if hasStatus(&skeleton, "toxic_fumes") {
+		t.Error("undead should be immune to toxic fumes")
+	}
+}
+
+func TestLocationElementModifiers(t *testing.T) {
+	mob := GenerateMonster("wolf", 5, 2)
+	if hit := ApplyElements(20, models.Lightning, &mob, "Lake"); hit.Damage != 25 {
+		t.Errorf("the lake should empower lightning, got %d", hit.Damage)
+	}
+	if hit := ApplyElements(20, models.Fire, &mob, "Lake"); hit.Damage != 16 {
+		t.Errorf("the lake should dampen fire, got %d", hit.Damage)
+	}
+	if hit := ApplyElements(20, models.Physical, &mob, "Lake"); hit.Damage != 20 || len(hit.Messages) != 0 {
+		t.Errorf("physical damage should pass through, got %+v", hit)
+	}
+}
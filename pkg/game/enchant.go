@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+// Enchanting tuning.
+const (
+	EnchantGoldPerRarity = 20 // gold per point of item rarity, per attempt
+	MinEnchantChance     = 10 // % floor filled sockets can lower the chance to
+	ScrollInfuseMaterial = 2  // beast materials consumed with a skill scroll
+)
+
+// Enchantment is a rune the village Enchanting Station can set into a free
+// socket, paid for with beast materials.
+type Enchantment struct {
+	ID        string
+	Name      string
+	Effect    string
+	Value     int
+	Materials map[string]int
+}
+
+// Enchantments lists the runes available at the Enchanting Station.
+var Enchantments = []Enchantment{
+	{ID: "ember", Name: "Ember Rune", Effect: EffectFireDamage, Value: 4, Materials: map[string]int{"Sharp Fang": 2, "Monster Claw": 1}},
+	{ID: "frost", Name: "Frost Rune", Effect: EffectIceDamage, Value: 4, Materials: map[string]int{"Beast Bone": 2, "Ore Fragment": 1}},
+	{ID: "storm", Name: "Storm Rune", Effect: EffectLightningDamage, Value: 5, Materials: map[string]int{"Ore Fragment": 2, "Monster Claw": 1}},
+	{ID: "fire_ward", Name: "Fire Ward", Effect: EffectFireResist, Value: 10, Materials: map[string]int{"Tough Hide": 2, "Beast Skin": 1}},
+	{ID: "frost_ward", Name: "Frost Ward", Effect: EffectIceResist, Value: 10, Materials: map[string]int{"Beast Skin": 2, "Beast Bone": 1}},
+	{ID: "storm_ward", Name: "Storm Ward", Effect: EffectLightningResist, Value: 10, Materials: map[string]int{"Tough Hide": 2, "Ore Fragment": 1}},
+	{ID: "venom_ward", Name: "Venom Ward", Effect: EffectPoisonResist, Value: 10, Materials: map[string]int{"Beast Skin": 2, "Sharp Fang": 1}},
+	{ID: "venom", Name: "Venom Barb", Effect: EffectPoisonOnHit, Value: 15, Materials: map[string]int{"Sharp Fang": 3}},
+	{ID: "cinder", Name: "Cinder Brand", Effect: EffectBurnOnHit, Value: 15, Materials: map[string]int{"Monster Claw": 2, "Beast Bone": 1}},
+	{ID: "thunder", Name: "Thunder Seal", Effect: EffectStunOnHit, Value: 8, Materials: map[string]int{"Monster Claw": 2, "Ore Fragment": 2}},
+}
+
+// FindEnchantment looks up an entry of Enchantments by ID.
+func FindEnchantment(id string) (Enchantment, bool) {
+	for _, e := range Enchantments {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Enchantment{}, false
+}
+
+// scrollEffects maps a skill's damage type to the effect a scroll of it
+// infuses.
+var scrollEffects = map[models.DamageType]string{
+	models.Physical:  EffectAttack,
+	models.Fire:      EffectFireDamage,
+	models.Ice:       EffectIceDamage,
+	models.Lightning: EffectLightningDamage,
+	models.Poison:    EffectPoisonOnHit,
+}
+
+// ScrollEnchantment is the enchantment infusing a skill scroll gives: the
+// skill's element, stronger for more valuable scrolls. Materials lists only
+// the beast materials; the scroll itself is consumed too.
+func ScrollEnchantment(scroll models.Item) (Enchantment, error) {
+	if scroll.ItemType != "skill_scroll" {
+		return Enchantment{}, fmt.Errorf("%s is not a skill scroll", scroll.Name)
+	}
+	skill := scroll.SkillScroll.Skill
+	effect, ok := scrollEffects[skill.DamageType]
+	if !ok {
+		effect = EffectAttack
+	}
+	value := 3 + scroll.SkillScroll.CraftingValue/10
+	if effect == EffectPoisonOnHit {
+		value *= 3
+	}
+	return Enchantment{
+		ID:        "scroll",
+		Name:      skill.Name + " Infusion",
+		Effect:    effect,
+		Value:     value,
+		Materials: map[string]int{},
+	}, nil
+}
+
+// SocketsForRarity is how many enchantments an item of the given rarity
+// holds.
+func SocketsForRarity(rarity int) int {
+	switch {
+	case rarity >= 6:
+		return 3
+	case rarity >= 4:
+		return 2
+	case rarity >= 2:
+		return 1
+	}
+	return 0
+}
+
+// FreeSockets is how many more enchantments an item can take.
+func FreeSockets(item models.Item) int {
+	if item.ItemType != "equipment" {
+		return 0
+	}
+	return max(SocketsForRarity(item.Rarity)-len(item.Enchantments), 0)
+}
+
+// EnchantGoldCost is the gold charged for one attempt on item.
+func EnchantGoldCost(item models.Item) int {
+	return EnchantGoldPerRarity * max(item.Rarity, 1)
+}
+
+// EnchantSuccessChance is the % chance the next enchant on item holds at the
+// village's enchanting station. Filled sockets lower it, but not below
+// MinEnchantChance; each station level past the first raises it.
+func EnchantSuccessChance(village *models.Village, item models.Item) int {
+	balance := config.Current().Balance
+	chance := balance.EnchantSuccessChance - len(item.Enchantments)*balance.EnchantChancePerSocket
+	chance = max(chance, min(balance.EnchantSuccessChance, MinEnchantChance))
+	if village != nil {
+		chance += max(BuildingLevel(village, BuildingEnchanting)-1, 0) * EnchantingChancePerLevel
+	}
+	return min(chance, 100)
+}
+
+// MaterialsText lists an enchantment's material cost for display.
+func MaterialsText(materials map[string]int) string {
+	names := make([]string, 0, len(materials))
+	for name := range materials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := []string{}
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%d %s", materials[name], name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkEnchant reports why the player can't attempt ench on item, if they
+// can't.
+func checkEnchant(player *models.Character, item models.Item, ench Enchantment) error {
+	if FreeSockets(item) == 0 {
+		if SocketsForRarity(item.Rarity) == 0 {
+			return fmt.Errorf("%s has no sockets", item.Name)
+		}
+		return fmt.Errorf("every socket of %s is filled", item.Name)
+	}
+	if IsBroken(item) {
+		return fmt.Errorf("%s is broken; repair it first", item.Name)
+	}
+	if cost := EnchantGoldCost(item); GoldBalance(player) < cost {
+		return fmt.Errorf("enchanting costs %d gold, you have %d", cost, GoldBalance(player))
+	}
+	for name, n := range ench.Materials {
+		if ResourceBalance(player, name) < n {
+			return fmt.Errorf("%s needs %d %s, you have %d", ench.Name, n, name, ResourceBalance(player, name))
+		}
+	}
+	return nil
+}
+
+// attemptEnchant charges for and rolls one enchant of item, which is
+// updated in place on success. A failed attempt still uses up its cost.
+func attemptEnchant(player *models.Character, village *models.Village, item *models.Item, ench Enchantment, ref string) bool {
+	AdjustGold(player, -EnchantGoldCost(*item), ReasonEnchant, ref)
+	for name, n := range ench.Materials {
+		AdjustResource(player, name, -n, ReasonEnchant, ref)
+	}
+	if rand.Intn(100) >= EnchantSuccessChance(village, *item) {
+		return false
+	}
+	item.Enchantments = append(item.Enchantments, models.Affix{Name: ench.Name, Effect: ench.Effect, Value: ench.Value})
+	item.CP = ItemPower(*item)
+	return true
+}
+
+// EnchantItem tries to set ench into a free socket of item and reports
+// whether it held. Callers refresh the owner's stats afterwards.
+func EnchantItem(player *models.Character, village *models.Village, item *models.Item, ench Enchantment, ref string) (bool, error) {
+	if err := checkEnchant(player, *item, ench); err != nil {
+		return false, err
+	}
+	return attemptEnchant(player, village, item, ench, ref), nil
+}
+
+// InfuseScroll consumes the skill scroll at inventory index scrollIdx, along
+// with beast materials, to enchant item with the scroll's element. It
+// returns the enchantment attempted and whether it held.
+func InfuseScroll(player *models.Character, village *models.Village, item *models.Item, scrollIdx int, ref string) (Enchantment, bool, error) {
+	if scrollIdx < 0 || scrollIdx >= len(player.Inventory) {
+		return Enchantment{}, false, fmt.Errorf("no such scroll")
+	}
+	scroll := player.Inventory[scrollIdx]
+	ench, err := ScrollEnchantment(scroll)
+	if err != nil {
+		return ench, false, err
+	}
+	if BeastMaterialStock(player) < ScrollInfuseMaterial {
+		return ench, false, fmt.Errorf("infusing a scroll needs %d beast materials, you have %d", ScrollInfuseMaterial, BeastMaterialStock(player))
+	}
+	if err := checkEnchant(player, *item, ench); err != nil {
+		return ench, false, err
+	}
+	RemoveItemFromInventory(&player.Inventory, scrollIdx)
+	RecordItemChange(player, scroll.Name, -1, ReasonEnchant, ref)
+	spendBeastMaterials(player, ScrollInfuseMaterial, ReasonEnchant, ref)
+	return ench, attemptEnchant(player, village, item, ench, ref), nil
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+// withEnchantChance pins the enchant success chance for a test.
+func withEnchantChance(t *testing.T, chance int) {
+	prev := config.Current()
+	cfg := *prev
+	cfg.Balance.EnchantSuccessChance = chance
+	cfg.Balance.EnchantChancePerSocket = 0
+	config.Set(&cfg)
+	t.Cleanup(func() { config.Set(prev) })
+}
+
+func TestSocketsForRarity(t *testing.T) {
+	for rarity, want := range map[int]int{1: 0, 2: 1, 3: 1, 4: 2, 5: 2, 6: 3, 9: 3} {
+		if got := SocketsForRarity(rarity); got != want {
+			t.Errorf("rarity %d: expected %d sockets, got %d", rarity, want, got)
+		}
+	}
+	item := models.Item{Name: "Ring", ItemType: "equipment", Rarity: 4,
+		Enchantments: []models.Affix{{Name: "Ember Rune", Effect: EffectFireDamage, Value: 4}}}
+	if FreeSockets(item) != 1 {
+		t.Errorf("expected 1 free socket, got %d", FreeSockets(item))
+	}
+}
+
+func TestEnchantSuccessAndFailure(t *testing.T) {
+	player := GenerateCharacter("Enchanter", 1, 1)
+	ember, _ := FindEnchantment("ember")
+	sword := models.Item{Name: "Longsword", Slot: 5, ItemType: "equipment", Rarity: 2}
+	EnsureDurability(&sword)
+
+	if _, err := EnchantItem(&player, nil, &sword, ember, "test"); err == nil {
+		t.Fatal("enchant should fail without materials")
+	}
+	AdjustGold(&player, 2*EnchantGoldCost(sword)-GoldBalance(&player), ReasonMonsterDrop, "test")
+	AdjustResource(&player, "Sharp Fang", 4, ReasonMonsterDrop, "test")
+	AdjustResource(&player, "Monster Claw", 2, ReasonMonsterDrop, "test")
+
+	withEnchantChance(t, 0)
+	if held, err := EnchantItem(&player, nil, &sword, ember, "test"); err != nil || held {
+		t.Fatalf("expected a failed attempt, got held=%v err=%v", held, err)
+	}
+	if len(sword.Enchantments) != 0 || ResourceBalance(&player, "Sharp Fang") != 2 || GoldBalance(&player) != EnchantGoldCost(sword) {
+		t.Fatal("a failed attempt should consume its cost and leave the item alone")
+	}
+
+	withEnchantChance(t, 100)
+	if held, err := EnchantItem(&player, nil, &sword, ember, "test"); err != nil || !held {
+		t.Fatalf("expected the enchant to hold, got held=%v err=%v", held, err)
+	}
+	if len(sword.Enchantments) != 1 || sword.CP != ItemPower(sword) {
+		t.Fatalf("unexpected item after enchant: %+v", sword)
+	}
+	if CalculateItemMods(map[int]models.Item{5: sword}).Effects[EffectFireDamage] != ember.Value {
+		t.Error("enchantment should add its effect to the item's stats")
+	}
+	if _, err := EnchantItem(&player, nil, &sword, ember, "test"); err == nil {
+		t.Error("enchant should fail with every socket filled")
+	}
+}
+
+func TestInfuseScrollConsumesScroll(t *testing.T) {
+	withEnchantChance(t, 100)
+	player := GenerateCharacter("Infuser", 1, 1)
+	player.Inventory = []models.Item{{Name: "Fireball Scroll", ItemType: "skill_scroll",
+		SkillScroll: models.SkillScrollData{Skill: models.Skill{Name: "Fireball", DamageType: models.Fire}, CraftingValue: 20}}}
+	AdjustGold(&player, 100, ReasonMonsterDrop, "test")
+	AdjustResource(&player, "Beast Bone", ScrollInfuseMaterial, ReasonMonsterDrop, "test")
+	helm := models.Item{Name: "Helm", Slot: 0, ItemType: "equipment", Rarity: 3}
+
+	ench, held, err := InfuseScroll(&player, nil, &helm, 0, "test")
+	if err != nil || !held {
+		t.Fatalf("expected infusion to hold, got held=%v err=%v", held, err)
+	}
+	if ench.Effect != EffectFireDamage || ench.Value != 5 {
+		t.Errorf("unexpected infusion %+v", ench)
+	}
+	if len(player.Inventory) != 0 || ResourceBalance(&player, "Beast Bone") != 0 {
+		t.Error("infusion should consume the scroll and materials")
+	}
+}
+
+func TestResistEnchantReducesDamage(t *testing.T) {
+	player := GenerateCharacter("Warded", 1, 1)
+	player.Resistances = map[models.DamageType]float64{}
+	player.StatsMod.Effects = map[string]int{EffectFireResist: 50}
+	if got := ApplyDamage(20, models.Fire, &player); got != 10 {
+		t.Errorf("expected 50%% fire resist to halve damage, got %d", got)
+	}
+	player.StatsMod.Effects[EffectFireResist] = 200
+	if got := ApplyDamage(20, models.Fire, &player); got != 5 {
+		t.Errorf("expected resist capped at %d%%, got %d damage", MaxItemResist, got)
+	}
+}
+
+func TestStatusOnHitEnchant(t *testing.T) {
+	mob := models.Monster{Name: "Wolf", HitpointsRemaining: 100}
+	_, lines := applyItemStrikes(map[string]int{EffectPoisonOnHit: 100}, &mob)
+	if len(lines) != 1 || len(mob.StatusEffects) != 1 || mob.StatusEffects[0].Type != "poison" {
+		t.Fatalf("expected the mob to be poisoned, got %+v", mob.StatusEffects)
+	}
+	applyItemStrikes(map[string]int{EffectPoisonOnHit: 100}, &mob)
+	if len(mob.StatusEffects) != 1 {
+		t.Error("a second poison should refresh, not stack")
+	}
+}
@@ -16,7 +16,8 @@ type EvolutionEvent struct {
 	Details      string
 }
 
-// MonsterVsMonsterCombat runs a simplified auto-fight between two monsters.
+// MonsterVsMonsterCombat runs a simplified auto-fight between two monsters,
+// each fighting by its AI profile. A monster that flees loses.
 // Returns a pointer to the winner (a or b). Both are modified in place.
 func MonsterVsMonsterCombat(a, b *models.Monster) *models.Monster {
 	// Restore resources and clear status effects
@@ -28,11 +29,12 @@ func MonsterVsMonsterCombat(a, b *models.Monster) *models.Monster {
 	b.ManaRemaining = b.ManaTotal
 	b.StaminaRemaining = b.StaminaTotal
 	b.StatusEffects = []models.StatusEffect{}
+	var aTimers, bTimers SkillTimers
 
 	for turn := 0; turn < 200; turn++ {
 		// Process status effects for both
-		ProcessStatusEffectsMob(a)
-		ProcessStatusEffectsMob(b)
+		TickStatusEffects(a)
+		TickStatusEffects(b)
 
 		if a.HitpointsRemaining <= 0 {
 			return b
@@ -42,16 +44,16 @@ func MonsterVsMonsterCombat(a, b *models.Monster) *models.Monster {
 		}
 
 		// Monster A attacks B
-		if !IsStunnedMob(a) {
-			monsterAttack(a, b)
+		if !IsStunnedMob(a) && monsterAttack(a, b, &aTimers, turn) {
+			return b
 		}
 		if b.HitpointsRemaining <= 0 {
 			return a
 		}
 
 		// Monster B attacks A
-		if !IsStunnedMob(b) {
-			monsterAttack(b, a)
+		if !IsStunnedMob(b) && monsterAttack(b, a, &bTimers, turn) {
+			return a
 		}
 		if a.HitpointsRemaining <= 0 {
 			return b
@@ -67,70 +69,46 @@ func MonsterVsMonsterCombat(a, b *models.Monster) *models.Monster {
 	return b
 }
 
-// monsterAttack executes a single monster's attack on a target monster.
-// 40% chance to use a skill (matching existing monster AI).
-func monsterAttack(attacker, target *models.Monster) {
-	// 40% chance to use a skill if available
-	if rand.Intn(100) < 40 && len(attacker.LearnedSkills) > 0 {
-		skill := attacker.LearnedSkills[rand.Intn(len(attacker.LearnedSkills))]
-		canUse := true
-		if skill.ManaCost > 0 && attacker.ManaRemaining < skill.ManaCost {
-			canUse = false
+// monsterAttack plays one turn of attacker against target as its AI profile
+// decides. It reports whether the attacker fled.
+func monsterAttack(attacker, target *models.Monster, timers *SkillTimers, turn int) bool {
+	act := DecideMonsterAction(attacker, target, nil, timers, turn)
+	switch act.Kind {
+	case MonsterFlee:
+		return true
+	case MonsterSkill:
+		skill := act.Skill
+		attacker.ManaRemaining -= skill.ManaCost
+		attacker.StaminaRemaining -= skill.StaminaCost
+		cast := timers.Cast(skill, skill.Damage, target, turn)
+
+		if skill.Damage > 0 {
+			hit := ApplyElements(cast.Power, skill.DamageType, target, attacker.LocationName)
+			target.HitpointsRemaining -= ApplyDamage(hit.Damage, skill.DamageType, target)
+		} else if skill.Damage < 0 {
+			// Healing skill
+			attacker.HitpointsRemaining = min(attacker.HitpointsRemaining-cast.Power, attacker.HitpointsTotal)
 		}
-		if skill.StaminaCost > 0 && attacker.StaminaRemaining < skill.StaminaCost {
-			canUse = false
-		}
-		if canUse {
-			attacker.ManaRemaining -= skill.ManaCost
-			attacker.StaminaRemaining -= skill.StaminaCost
-
-			if skill.Damage > 0 {
-				finalDmg := ApplyDamage(skill.Damage, skill.DamageType, target)
-				target.HitpointsRemaining -= finalDmg
-			} else if skill.Damage < 0 {
-				// Healing skill
-				attacker.HitpointsRemaining -= skill.Damage // subtracting negative = adding
-				if attacker.HitpointsRemaining > attacker.HitpointsTotal {
-					attacker.HitpointsRemaining = attacker.HitpointsTotal
-				}
-			}
 
-			// Apply status effect
-			if skill.Effect.Type != "" && skill.Effect.Type != "none" {
-				effect := models.StatusEffect{
-					Type:     skill.Effect.Type,
-					Duration: skill.Effect.Duration,
-					Potency:  skill.Effect.Potency,
-				}
-				if skill.Effect.Type == "buff_attack" || skill.Effect.Type == "buff_defense" ||
-					skill.Effect.Type == "regen" {
-					attacker.StatusEffects = append(attacker.StatusEffects, effect)
-					if skill.Effect.Type == "buff_attack" {
-						attacker.StatsMod.AttackMod += effect.Potency
-					} else if skill.Effect.Type == "buff_defense" {
-						attacker.StatsMod.DefenseMod += effect.Potency
-					}
-				} else {
-					target.StatusEffects = append(target.StatusEffects, effect)
-				}
-			}
-			return
-		}
+		// Apply status effect
+		ApplySkillEffect(attacker, target, skill.Effect)
+		return false
 	}
 
 	// Normal attack
-	atkRoll := MultiRoll(attacker.AttackRolls) + attacker.StatsMod.AttackMod
-	defRoll := MultiRoll(target.DefenseRolls) + target.StatsMod.DefenseMod
+	atkRoll := MultiRoll(attacker.AttackRolls) + AttackMod(attacker)
+	defRoll := MultiRoll(target.DefenseRolls) + DefenseMod(target)
 	damage := atkRoll - defRoll
 	if damage < 1 {
 		damage = 1
 	}
-	// 10% crit chance for monsters
-	if rand.Intn(100) < 10 {
+	// Monster crit chance comes from the balance config
+	if RollMonsterCrit() {
 		damage = int(float64(damage) * 1.5)
 	}
 	finalDmg := ApplyDamage(damage, models.Physical, target)
 	target.HitpointsRemaining -= finalDmg
+	return false
 }
 
 // TryUpgradeRarity attempts to upgrade a monster's rarity based on its monster kills.
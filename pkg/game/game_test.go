@@ -263,7 +263,7 @@ func TestAIDecisionMaking(t *testing.T) {
 
 	// Test low HP decision (should try to heal)
 	player.HitpointsRemaining = player.HitpointsTotal / 4 // 25% HP
-	decision := MakeAIDecision(&player, &mob, 5)
+	decision := MakeAIDecision(&player, &mob, 5, nil)
 
 	if decision != "skill_heal" && decision != "skill_regeneration" && decision != "item" {
 		t.Logf("Low HP AI decision: %s (expected healing action)", decision)
@@ -273,7 +273,7 @@ func TestAIDecisionMaking(t *testing.T) {
 
 	// Test early combat decision (should try to buff)
 	player.HitpointsRemaining = player.HitpointsTotal // Full HP
-	decision = MakeAIDecision(&player, &mob, 1)       // Turn 1
+	decision = MakeAIDecision(&player, &mob, 1, nil)  // Turn 1
 
 	t.Logf("Turn 1 AI decision: %s", decision)
 }
@@ -414,7 +414,7 @@ func TestCombatSimulation(t *testing.T) {
 
 		// Player turn - use AI decision
 		if !IsStunned(&player) {
-			decision := MakeAIDecision(&player, &mob, turnCount)
+			decision := MakeAIDecision(&player, &mob, turnCount, nil)
 
 			if decision == "attack" {
 				playerAttack := MultiRoll(player.AttackRolls) + player.StatsMod.AttackMod
@@ -44,6 +44,7 @@ func GenerateGuard(level int) models.Guard {
 		RecoveryTime:       0,
 		StatusEffects:      []models.StatusEffect{},
 		Resistances:        resistances,
+		Speed:              BaseSpeed - 1 + level/5,
 	}
 
 	// Generate starting equipment
@@ -70,7 +71,8 @@ func GenerateGuard(level int) models.Guard {
 }
 
 // GuardAttack processes attacks from all healthy guards against a monster,
-// applying critical hits and elemental resistance. Returns total damage dealt.
+// applying critical hits, elemental resistance and their gear's enchantments.
+// Returns total damage dealt.
 func GuardAttack(guards []models.Guard, mob *models.Monster) int {
 	totalDamage := 0
 
@@ -80,8 +82,12 @@ func GuardAttack(guards []models.Guard, mob *models.Monster) int {
 		if guard.Injured || guard.HitpointsRemaining <= 0 {
 			continue
 		}
+		if SkipsTurn(guard.StatusEffects) {
+			fmt.Printf("💫  %s is unable to act!\n", guard.Name)
+			continue
+		}
 
-		guardAttack := MultiRoll(guard.AttackRolls) + guard.StatsMod.AttackMod + guard.AttackBonus
+		guardAttack := (MultiRoll(guard.AttackRolls) + AttackMod(guard) + guard.AttackBonus) * FindGuardStance(guard.Stance).AttackPct / 100
 
 		// 10% critical hit chance
 		if rand.Intn(100) < 10 {
@@ -91,7 +97,7 @@ func GuardAttack(guards []models.Guard, mob *models.Monster) int {
 			fmt.Printf("🗡️  %s attacks %s.\n", guard.Name, mob.Name)
 		}
 
-		mobDef := MultiRoll(mob.DefenseRolls) + mob.StatsMod.DefenseMod
+		mobDef := MultiRoll(mob.DefenseRolls) + DefenseMod(mob)
 
 		if guardAttack > mobDef {
 			damage := guardAttack - mobDef
@@ -99,6 +105,11 @@ func GuardAttack(guards []models.Guard, mob *models.Monster) int {
 			mob.HitpointsRemaining -= finalDamage
 			totalDamage += finalDamage
 			fmt.Printf("🛡️  %s deals %d damage to %s!\n", guard.Name, finalDamage, mob.Name)
+			bonus, lines := applyItemStrikes(guard.StatsMod.Effects, mob)
+			totalDamage += bonus
+			for _, line := range lines {
+				fmt.Printf("✨ %s\n", line)
+			}
 		} else {
 			fmt.Printf("🛡️  %s's attack was blocked by %s!\n", guard.Name, mob.Name)
 		}
@@ -108,16 +119,18 @@ func GuardAttack(guards []models.Guard, mob *models.Monster) int {
 }
 
 // GuardDefense distributes incoming damage among healthy guards, absorbing a
-// percentage based on the number of active guards. Returns the remaining damage
+// percentage set by the active guards' stances. Returns the remaining damage
 // that passes through to the player and the indices of guards that absorbed damage.
 func GuardDefense(guards []models.Guard, incomingDamage int) (int, []int) {
 	healthyGuards := 0
 	healthyIndices := []int{}
+	absorbPercent := 0
 
 	for i := range guards {
 		if !guards[i].Injured && guards[i].HitpointsRemaining > 0 {
 			healthyGuards++
 			healthyIndices = append(healthyIndices, i)
+			absorbPercent += FindGuardStance(guards[i].Stance).CoverPct
 		}
 	}
 
@@ -125,9 +138,8 @@ func GuardDefense(guards []models.Guard, incomingDamage int) (int, []int) {
 		return incomingDamage, nil
 	}
 
-	absorbPercent := healthyGuards * 20
-	if absorbPercent > 60 {
-		absorbPercent = 60
+	if absorbPercent > data.GuardMaxCoverPct {
+		absorbPercent = data.GuardMaxCoverPct
 	}
 
 	absorbedDamage := (incomingDamage * absorbPercent) / 100
@@ -147,15 +159,9 @@ func GuardDefense(guards []models.Guard, incomingDamage int) (int, []int) {
 		}
 
 		if dmg > 0 {
-			guard.HitpointsRemaining -= dmg
 			damagedIndices = append(damagedIndices, guardIndex)
 			fmt.Printf("🛡️  %s absorbs %d damage!\n", guard.Name, dmg)
-
-			// Check if guard HP dropped below 30%
-			hpThreshold := (guard.HitPoints * 30) / 100
-			if guard.HitpointsRemaining <= hpThreshold {
-				guard.Injured = true
-				guard.RecoveryTime = 3
+			if WoundGuard(guard, dmg) {
 				fmt.Printf("🚑  %s has been seriously injured and needs recovery!\n", guard.Name)
 			}
 		}
@@ -166,6 +172,18 @@ func GuardDefense(guards []models.Guard, incomingDamage int) (int, []int) {
 	return remainingDamage, damagedIndices
 }
 
+// WoundGuard deals damage to guard, injuring it once its health drops to 30%
+// or less. It reports whether the guard was injured by this hit.
+func WoundGuard(guard *models.Guard, damage int) bool {
+	guard.HitpointsRemaining -= damage
+	if guard.Injured || guard.HitpointsRemaining > (guard.HitPoints*30)/100 {
+		return false
+	}
+	guard.Injured = true
+	guard.RecoveryTime = 3
+	return true
+}
+
 // ProcessGuardRecovery handles recovery for injured guards in a village,
 // decrementing recovery timers and restoring guards to full health when ready.
 func ProcessGuardRecovery(village *models.Village) {
@@ -188,3 +206,248 @@ func ProcessGuardRecovery(village *models.Village) {
 		}
 	}
 }
+
+// FindGuardStance returns the stance with the given ID, or the default stance
+// for an unknown or empty ID.
+func FindGuardStance(id string) models.GuardStance {
+	for _, stance := range data.GuardStances {
+		if stance.ID == id {
+			return stance
+		}
+	}
+	return data.GuardStances[0]
+}
+
+// SetGuardStance orders every guard into the stance with the given ID and
+// returns that stance.
+func SetGuardStance(guards []models.Guard, id string) models.GuardStance {
+	stance := FindGuardStance(id)
+	for i := range guards {
+		guards[i].Stance = stance.ID
+	}
+	return stance
+}
+
+// GuardTarget picks the foe a guard's stance goes after: the first one still
+// standing, or for "weakest" the one with the least health. It returns -1 when
+// every foe is down.
+func GuardTarget(guard *models.Guard, foes []interface{}) int {
+	weakest := FindGuardStance(guard.Stance).Target == "weakest"
+	best, bestHP := -1, 0
+	for i, foe := range foes {
+		t := statusTargetOf(foe)
+		if t == nil || *t.hp <= 0 {
+			continue
+		}
+		if best < 0 || (weakest && *t.hp < bestHP) {
+			best, bestHP = i, *t.hp
+		}
+		if !weakest {
+			break
+		}
+	}
+	return best
+}
+
+// GuardAction is what a guard did on its turn.
+type GuardAction struct {
+	Target   int // index into the foes, -1 when the guard did nothing
+	Damage   int
+	Skill    string // skill used, "" for a plain attack
+	Messages []StatusMessage
+}
+
+// GuardTurn plays one turn for guard against foes (characters, monsters or
+// guards). A guard uses its learned skill whenever timers says it is ready,
+// and otherwise attacks the foe its stance picks. Healing and beneficial
+// skills go to ally, or to the guard itself when ally is nil. Nil timers keep
+// the guard to plain attacks. The guard earns XP for the damage it deals.
+func GuardTurn(guard *models.Guard, foes []interface{}, ally interface{}, timers *SkillTimers, turn int) GuardAction {
+	act := GuardAction{Target: -1}
+	if guard.Injured || guard.HitpointsRemaining <= 0 {
+		return act
+	}
+	if SkipsTurn(guard.StatusEffects) {
+		act.Messages = append(act.Messages, StatusMessage{fmt.Sprintf("%s is unable to act!", guard.Name), "debuff"})
+		return act
+	}
+	act.Target = GuardTarget(guard, foes)
+	if act.Target < 0 {
+		return act
+	}
+	foe := foes[act.Target]
+	ft := statusTargetOf(foe)
+	stance := FindGuardStance(guard.Stance)
+
+	if skill := guard.Skill; skill != nil && timers != nil && timers.Ready(*skill, turn) {
+		act.Skill = skill.Name
+		act.Messages = append(act.Messages, StatusMessage{fmt.Sprintf("%s uses %s!", guard.Name, skill.Name), "combat"})
+		power := skill.Damage
+		if power > 0 {
+			power = power * stance.AttackPct / 100
+		}
+		cast := timers.Cast(*skill, power, foe, turn)
+		if timers.ReadyOn == nil {
+			timers.ReadyOn = map[string]int{}
+		}
+		timers.ReadyOn[skill.Name] = turn + max(SkillCooldown(*skill), data.GuardSkillCooldown) + 1
+		act.Messages = append(act.Messages, cast.Messages...)
+		switch {
+		case skill.Damage < 0:
+			if ally == nil {
+				ally = guard
+			}
+			if at := statusTargetOf(ally); at != nil {
+				heal := min(-cast.Power, max(at.maxHP-*at.hp, 0))
+				*at.hp += heal
+				act.Messages = append(act.Messages, StatusMessage{fmt.Sprintf("%s heals %s for %d HP!", guard.Name, at.name, heal), "heal"})
+			}
+		case skill.Damage > 0:
+			act.Damage = ApplyDamage(cast.Power, skill.DamageType, foe)
+			*ft.hp -= act.Damage
+			act.Messages = append(act.Messages, StatusMessage{fmt.Sprintf("%s's %s hits %s for %d damage!", guard.Name, skill.Name, ft.name, act.Damage), "damage"})
+		}
+		act.Messages = append(act.Messages, ApplySkillEffect(guard, foe, skill.Effect)...)
+	} else {
+		attack := (MultiRoll(guard.AttackRolls) + AttackMod(guard) + guard.AttackBonus) * stance.AttackPct / 100
+		crit := rand.Intn(100) < 10
+		if crit {
+			attack *= 2
+		}
+		if defense := defenseRoll(foe); attack > defense {
+			act.Damage = ApplyDamage(attack-defense, models.Physical, foe)
+			*ft.hp -= act.Damage
+			text := fmt.Sprintf("%s strikes %s for %d damage!", guard.Name, ft.name, act.Damage)
+			if crit {
+				text = fmt.Sprintf("%s lands a CRITICAL HIT on %s for %d damage!", guard.Name, ft.name, act.Damage)
+			}
+			act.Messages = append(act.Messages, StatusMessage{text, "damage"})
+			if mob, ok := foe.(*models.Monster); ok {
+				bonus, lines := applyItemStrikes(guard.StatsMod.Effects, mob)
+				act.Damage += bonus
+				for _, line := range lines {
+					act.Messages = append(act.Messages, StatusMessage{line, "damage"})
+				}
+			}
+		} else {
+			act.Messages = append(act.Messages, StatusMessage{fmt.Sprintf("%s's attack is blocked by %s!", guard.Name, ft.name), "combat"})
+		}
+	}
+
+	xp := max(act.Damage, 0)
+	if *ft.hp <= 0 && act.Damage > 0 {
+		xp += levelOf(foe) * data.GuardKillXP
+	}
+	act.Messages = append(act.Messages, GainGuardXP(guard, xp)...)
+	return act
+}
+
+// defenseRoll rolls a combatant's defense.
+func defenseRoll(target interface{}) int {
+	switch t := target.(type) {
+	case *models.Character:
+		return MultiRoll(t.DefenseRolls) + DefenseMod(t)
+	case *models.Monster:
+		return MultiRoll(t.DefenseRolls) + DefenseMod(t)
+	case *models.Guard:
+		return MultiRoll(t.DefenseRolls) + DefenseMod(t) + t.DefenseBonus
+	}
+	return 0
+}
+
+// levelOf is a combatant's level.
+func levelOf(target interface{}) int {
+	switch t := target.(type) {
+	case *models.Character:
+		return t.Level
+	case *models.Monster:
+		return t.Level
+	case *models.Guard:
+		return t.Level
+	}
+	return 0
+}
+
+// GuardXPToLevel is the XP a guard needs to reach its next level.
+func GuardXPToLevel(guard *models.Guard) int {
+	return max(guard.Level, 1) * data.GuardXPPerLevel
+}
+
+// GainGuardXP adds XP to a guard, levelling it up as often as the XP allows.
+func GainGuardXP(guard *models.Guard, xp int) []StatusMessage {
+	var msgs []StatusMessage
+	guard.Experience += xp
+	for guard.Experience >= GuardXPToLevel(guard) {
+		guard.Experience -= GuardXPToLevel(guard)
+		LevelUpGuard(guard)
+		msgs = append(msgs, StatusMessage{fmt.Sprintf("%s reaches level %d!", guard.Name, guard.Level), "levelup"})
+	}
+	return msgs
+}
+
+// LevelUpGuard raises a guard one level, growing its stats the way
+// GenerateGuard scales them.
+func LevelUpGuard(guard *models.Guard) {
+	guard.Level++
+	guard.HitpointsNatural += 5
+	guard.HitPoints = guard.HitpointsNatural + guard.StatsMod.HitPointMod
+	guard.HitpointsRemaining = min(guard.HitpointsRemaining+5, guard.HitPoints)
+	guard.AttackBonus++
+	guard.DefenseBonus++
+	guard.AttackRolls = max(guard.AttackRolls, guard.Level/5+1)
+	guard.DefenseRolls = max(guard.DefenseRolls, guard.Level/5+1)
+	guard.Speed = max(guard.Speed, BaseSpeed-1+guard.Level/5)
+}
+
+// CarryGuardProgress copies the levels and XP a guard earned in a fight back
+// to the guard it was copied from.
+func CarryGuardProgress(home *models.Guard, fought models.Guard) {
+	for home.Level < fought.Level {
+		LevelUpGuard(home)
+	}
+	home.Experience = fought.Experience
+}
+
+// GuardLearnSkill teaches a guard the skill on a scroll, replacing any skill
+// it knew.
+func GuardLearnSkill(guard *models.Guard, scroll models.Item) error {
+	if scroll.ItemType != "skill_scroll" || scroll.SkillScroll.Skill.Name == "" {
+		return fmt.Errorf("%s is not a skill scroll", scroll.Name)
+	}
+	skill := scroll.SkillScroll.Skill
+	guard.Skill = &skill
+	return nil
+}
+
+// GuardToMonster turns a guard into a standalone opponent, keeping its
+// learned skill and speed.
+func GuardToMonster(guard models.Guard, name string) models.Monster {
+	skills := AssignMonsterSkills("humanoid", guard.Level)
+	if guard.Skill != nil {
+		skills = append(skills, *guard.Skill)
+	}
+	return models.Monster{
+		Name:               name,
+		Level:              guard.Level,
+		Rank:               guard.Level/3 + 1,
+		HitpointsTotal:     guard.HitPoints,
+		HitpointsNatural:   guard.HitPoints,
+		HitpointsRemaining: guard.HitPoints,
+		ManaTotal:          30,
+		ManaNatural:        30,
+		ManaRemaining:      30,
+		StaminaTotal:       30,
+		StaminaNatural:     30,
+		StaminaRemaining:   30,
+		AttackRolls:        guard.AttackRolls,
+		DefenseRolls:       guard.DefenseRolls,
+		StatsMod:           guard.StatsMod,
+		EquipmentMap:       guard.EquipmentMap,
+		Inventory:          guard.Inventory,
+		LearnedSkills:      skills,
+		StatusEffects:      []models.StatusEffect{},
+		Resistances:        guard.Resistances,
+		MonsterType:        "humanoid",
+		Speed:              guard.Speed,
+	}
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+func TestGuardTargetFollowsStance(t *testing.T) {
+	guard := GenerateGuard(5)
+	strong := GenerateMonster("wolf", 5, 2)
+	weak := GenerateMonster("wolf", 5, 2)
+	strong.HitpointsRemaining, weak.HitpointsRemaining = 50, 5
+	foes := []interface{}{&strong, &weak}
+
+	if got := GuardTarget(&guard, foes); got != 0 {
+		t.Errorf("the default stance should go after the main foe, got %d", got)
+	}
+	guard.Stance = "focus"
+	if got := GuardTarget(&guard, foes); got != 1 {
+		t.Errorf("focus weakest should pick the 5 HP foe, got %d", got)
+	}
+	weak.HitpointsRemaining = 0
+	if got := GuardTarget(&guard, foes); got != 0 {
+		t.Errorf("fallen foes should be skipped, got %d", got)
+	}
+}
+
+func TestGuardDefenseCoverFollowsStance(t *testing.T) {
+	guards := []models.Guard{GenerateGuard(5), GenerateGuard(5)}
+	for i := range guards {
+		guards[i].HitPoints, guards[i].HitpointsRemaining = 1000, 1000
+	}
+	if left, _ := GuardDefense(guards, 100); left != 60 {
+		t.Errorf("two defending guards should take 40%%, got %d through", left)
+	}
+	SetGuardStance(guards, "aggressive")
+	if left, _ := GuardDefense(guards, 100); left != 90 {
+		t.Errorf("two aggressive guards should take 10%%, got %d through", left)
+	}
+}
+
+func TestGuardTurnUsesSkillThenRests(t *testing.T) {
+	guard := GenerateGuard(5)
+	scroll := CreateSkillScroll(models.Skill{Name: "Smite", Damage: 20, DamageType: models.Fire, Effect: models.StatusEffect{Type: "none"}})
+	if err := GuardLearnSkill(&guard, scroll); err != nil {
+		t.Fatal(err)
+	}
+	mob := GenerateMonster("wolf", 5, 2)
+	mob.HitpointsTotal, mob.HitpointsRemaining = 1000, 1000
+	var timers SkillTimers
+
+	if act := GuardTurn(&guard, []interface{}{&mob}, nil, &timers, 1); act.Skill != "Smite" {
+		t.Fatalf("a guard should open with its skill, got %+v", act)
+	}
+	for turn := 2; turn <= 1+data.GuardSkillCooldown; turn++ {
+		if act := GuardTurn(&guard, []interface{}{&mob}, nil, &timers, turn); act.Skill != "" {
+			t.Fatalf("turn %d: skill used during its cooldown", turn)
+		}
+	}
+	if act := GuardTurn(&guard, []interface{}{&mob}, nil, &timers, 2+data.GuardSkillCooldown); act.Skill != "Smite" {
+		t.Errorf("the skill should be ready again after %d turns", data.GuardSkillCooldown)
+	}
+}
+
+func TestGuardLearnSkillRejectsOtherItems(t *testing.T) {
+	guard := GenerateGuard(5)
+	if err := GuardLearnSkill(&guard, models.Item{Name: "Sword", ItemType: "weapon"}); err == nil {
+		t.Error("only skill scrolls should teach a guard")
+	}
+}
+
+func TestGuardLevelsFromXPAndCarriesProgress(t *testing.T) {
+	home := GenerateGuard(3)
+	fought := home
+	msgs := GainGuardXP(&fought, GuardXPToLevel(&fought)+5)
+	if fought.Level != 4 || fought.Experience != 5 || len(msgs) != 1 {
+		t.Fatalf("level %d, xp %d, %d messages after one level of XP", fought.Level, fought.Experience, len(msgs))
+	}
+	if fought.AttackBonus != home.AttackBonus+1 || fought.HitpointsNatural != home.HitpointsNatural+5 {
+		t.Error("a level up should raise attack and health")
+	}
+
+	CarryGuardProgress(&home, fought)
+	if home.Level != 4 || home.Experience != 5 || home.AttackBonus != fought.AttackBonus {
+		t.Errorf("progress not carried home: %+v", home)
+	}
+}
+
+func TestGuardTurnEarnsKillXP(t *testing.T) {
+	guard := GenerateGuard(5)
+	guard.AttackBonus = 1000
+	mob := GenerateMonster("wolf", 3, 1)
+	mob.HitpointsRemaining = 1
+	mob.Resistances = nil
+	level, xp := guard.Level, guard.Experience
+	GuardTurn(&guard, []interface{}{&mob}, nil, nil, 1)
+	if mob.HitpointsRemaining > 0 {
+		t.Fatal("the guard should have finished the wolf")
+	}
+	if guard.Level == level && guard.Experience <= xp {
+		t.Error("finishing a foe should earn XP")
+	}
+}
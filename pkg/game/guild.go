@@ -0,0 +1,358 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// Guild errors.
+var (
+	ErrGuildName       = fmt.Errorf("a guild name needs %d-%d letters, digits, spaces, dashes or apostrophes", data.GuildNameMinLen, data.GuildNameMaxLen)
+	ErrGuildFull       = fmt.Errorf("the guild is full (%d members)", data.GuildMaxMembers)
+	ErrGuildRank       = errors.New("your guild rank doesn't allow that")
+	ErrGuildMember     = errors.New("they are already in the guild")
+	ErrGuildInvited    = errors.New("they have already been invited")
+	ErrGuildNotInvited = errors.New("there is no invitation to that guild")
+	ErrGuildNoMember   = errors.New("no such guild member")
+	ErrGuildBank       = errors.New("the guild bank doesn't hold that much")
+	ErrGuildChat       = fmt.Errorf("a chat message needs 1-%d characters", data.GuildChatMaxLen)
+)
+
+// FindGuildRank is the guild rank with the given ID, or the lowest rank.
+func FindGuildRank(id string) models.GuildRank {
+	for _, rank := range data.GuildRanks {
+		if rank.ID == id {
+			return rank
+		}
+	}
+	return data.GuildRanks[len(data.GuildRanks)-1]
+}
+
+// GuildName tidies a proposed guild name and checks it's allowed.
+func GuildName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if len(name) < data.GuildNameMinLen || len(name) > data.GuildNameMaxLen {
+		return "", ErrGuildName
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '\'' {
+			return "", ErrGuildName
+		}
+	}
+	return name, nil
+}
+
+// NewGuild drafts a guild led by founder, checking they can afford
+// GuildCreateCost gold. Charge them with PayGuildFounding once the guild's
+// name is secured.
+func NewGuild(name string, accountID int64, founder *models.Character, now int64) (models.Guild, error) {
+	name, err := GuildName(name)
+	if err != nil {
+		return models.Guild{}, err
+	}
+	if gold := founder.ResourceStorageMap["Gold"].Stock; gold < data.GuildCreateCost {
+		return models.Guild{}, fmt.Errorf("founding a guild costs %d gold (you have %d)", data.GuildCreateCost, gold)
+	}
+	return models.Guild{
+		Name:      name,
+		Members:   []models.GuildMember{{AccountID: accountID, Name: founder.Name, Rank: data.GuildRanks[0].ID, Joined: now}},
+		Bank:      models.GuildBank{Resources: map[string]int{}, Items: []models.Item{}},
+		CreatedAt: now,
+	}, nil
+}
+
+// PayGuildFounding charges the founder of g for founding it.
+func PayGuildFounding(g *models.Guild, founder *models.Character) {
+	AdjustGold(founder, -data.GuildCreateCost, ReasonGuildFound, "guild:"+g.Name)
+}
+
+// GuildMemberIndex is the index of a character in the guild's roster, or -1.
+func GuildMemberIndex(g *models.Guild, accountID int64, name string) int {
+	for i, m := range g.Members {
+		if m.AccountID == accountID && m.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// GuildInviteIndex is the index of a character's invitation to the guild, or
+// -1.
+func GuildInviteIndex(g *models.Guild, accountID int64, name string) int {
+	for i, inv := range g.Invites {
+		if inv.AccountID == accountID && inv.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// outranks reports whether member i of the guild ranks above member j.
+func outranks(g *models.Guild, i, j int) bool {
+	return FindGuildRank(g.Members[i].Rank).Level < FindGuildRank(g.Members[j].Rank).Level
+}
+
+// InviteToGuild has member by invite a character to join the guild.
+func InviteToGuild(g *models.Guild, by int, accountID int64, name string, now int64) error {
+	if !FindGuildRank(g.Members[by].Rank).CanInvite {
+		return ErrGuildRank
+	}
+	if GuildMemberIndex(g, accountID, name) >= 0 {
+		return ErrGuildMember
+	}
+	if GuildInviteIndex(g, accountID, name) >= 0 {
+		return ErrGuildInvited
+	}
+	if len(g.Members)+len(g.Invites) >= data.GuildMaxMembers {
+		return ErrGuildFull
+	}
+	g.Invites = append(g.Invites, models.GuildInvite{AccountID: accountID, Name: name, From: g.Members[by].Name, Time: now})
+	return nil
+}
+
+// JoinGuild accepts a character's invitation, adding them at the lowest rank.
+func JoinGuild(g *models.Guild, accountID int64, name string, now int64) error {
+	i := GuildInviteIndex(g, accountID, name)
+	if i < 0 {
+		return ErrGuildNotInvited
+	}
+	g.Invites = append(g.Invites[:i], g.Invites[i+1:]...)
+	if len(g.Members) >= data.GuildMaxMembers {
+		return ErrGuildFull
+	}
+	g.Members = append(g.Members, models.GuildMember{
+		AccountID: accountID, Name: name, Rank: data.GuildRanks[len(data.GuildRanks)-1].ID, Joined: now,
+	})
+	return nil
+}
+
+// DeclineGuildInvite withdraws a character's invitation to the guild.
+func DeclineGuildInvite(g *models.Guild, accountID int64, name string) {
+	if i := GuildInviteIndex(g, accountID, name); i >= 0 {
+		g.Invites = append(g.Invites[:i], g.Invites[i+1:]...)
+	}
+}
+
+// KickFromGuild has member by remove member target, who must rank below them.
+func KickFromGuild(g *models.Guild, by, target int) error {
+	if target < 0 || target >= len(g.Members) {
+		return ErrGuildNoMember
+	}
+	if !FindGuildRank(g.Members[by].Rank).CanKick || !outranks(g, by, target) {
+		return ErrGuildRank
+	}
+	g.Members = append(g.Members[:target], g.Members[target+1:]...)
+	return nil
+}
+
+// PromoteGuildMember has member by raise member target one rank, no higher
+// than the rank below their own.
+func PromoteGuildMember(g *models.Guild, by, target int) error {
+	if target < 0 || target >= len(g.Members) {
+		return ErrGuildNoMember
+	}
+	level := FindGuildRank(g.Members[target].Rank).Level
+	if !FindGuildRank(g.Members[by].Rank).CanPromote || level-1 <= FindGuildRank(g.Members[by].Rank).Level {
+		return ErrGuildRank
+	}
+	g.Members[target].Rank = data.GuildRanks[level-1].ID
+	return nil
+}
+
+// DemoteGuildMember has member by lower member target, who must rank below
+// them, one rank.
+func DemoteGuildMember(g *models.Guild, by, target int) error {
+	if target < 0 || target >= len(g.Members) {
+		return ErrGuildNoMember
+	}
+	level := FindGuildRank(g.Members[target].Rank).Level
+	if !FindGuildRank(g.Members[by].Rank).CanPromote || !outranks(g, by, target) || level == len(data.GuildRanks)-1 {
+		return ErrGuildRank
+	}
+	g.Members[target].Rank = data.GuildRanks[level+1].ID
+	return nil
+}
+
+// LeaveGuild takes member i out of the guild. A departing leader hands the
+// guild to the highest-ranked, longest-serving member left. It reports
+// whether the guild is now empty and should be disbanded.
+func LeaveGuild(g *models.Guild, i int) bool {
+	leader := g.Members[i].Rank == data.GuildRanks[0].ID
+	g.Members = append(g.Members[:i], g.Members[i+1:]...)
+	if len(g.Members) == 0 {
+		return true
+	}
+	if leader {
+		heir := 0
+		for j := range g.Members {
+			if outranks(g, j, heir) || (!outranks(g, heir, j) && g.Members[j].Joined < g.Members[heir].Joined) {
+				heir = j
+			}
+		}
+		g.Members[heir].Rank = data.GuildRanks[0].ID
+	}
+	return false
+}
+
+// ScrubGuildTraces takes an account's characters out of the guild, along
+// with their invitations and chat lines, and reports whether anything
+// changed.
+func ScrubGuildTraces(g *models.Guild, accountID int64, names []string) bool {
+	changed := false
+	for _, name := range names {
+		if i := GuildMemberIndex(g, accountID, name); i >= 0 {
+			LeaveGuild(g, i)
+			changed = true
+			chat := g.Chat[:0]
+			for _, line := range g.Chat {
+				if line.From != name {
+					chat = append(chat, line)
+				}
+			}
+			g.Chat = chat
+		}
+		if GuildInviteIndex(g, accountID, name) >= 0 {
+			DeclineGuildInvite(g, accountID, name)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// DepositToGuildBank moves amount of a resource from the player's storage into
+// the guild bank.
+func DepositToGuildBank(g *models.Guild, player *models.Character, resource string, amount int) error {
+	if amount <= 0 {
+		return fmt.Errorf("deposit at least 1 %s", resource)
+	}
+	if have := player.ResourceStorageMap[resource].Stock; have < amount {
+		return fmt.Errorf("you only have %d %s", have, resource)
+	}
+	AdjustResource(player, resource, -amount, ReasonGuildBank, "guild:"+g.Name)
+	if g.Bank.Resources == nil {
+		g.Bank.Resources = map[string]int{}
+	}
+	g.Bank.Resources[resource] += amount
+	return nil
+}
+
+// GuildWithdrawLeft is how many more resources member i may take from the
+// bank on day, or -1 for no limit.
+func GuildWithdrawLeft(g *models.Guild, i int, day string) int {
+	limit := FindGuildRank(g.Members[i].Rank).DailyWithdraw
+	if limit < 0 {
+		return -1
+	}
+	if g.Members[i].WithdrawDay != day {
+		return limit
+	}
+	return max(limit-g.Members[i].Withdrawn, 0)
+}
+
+// WithdrawFromGuildBank moves amount of a resource from the guild bank into
+// member i's storage, within their rank's daily allowance.
+func WithdrawFromGuildBank(g *models.Guild, i int, player *models.Character, resource string, amount int, day string) error {
+	if amount <= 0 {
+		return fmt.Errorf("withdraw at least 1 %s", resource)
+	}
+	if g.Bank.Resources[resource] < amount {
+		return ErrGuildBank
+	}
+	left := GuildWithdrawLeft(g, i, day)
+	if left == 0 {
+		return ErrGuildRank
+	}
+	if left > 0 && amount > left {
+		return fmt.Errorf("your rank may withdraw %d more resources today", left)
+	}
+	member := &g.Members[i]
+	if member.WithdrawDay != day {
+		member.WithdrawDay, member.Withdrawn = day, 0
+	}
+	member.Withdrawn += amount
+	g.Bank.Resources[resource] -= amount
+	if g.Bank.Resources[resource] == 0 {
+		delete(g.Bank.Resources, resource)
+	}
+	AdjustResource(player, resource, amount, ReasonGuildBank, "guild:"+g.Name)
+	return nil
+}
+
+// DepositItemToGuildBank moves up to n items from the player's inventory
+// entry at index into the guild bank.
+func DepositItemToGuildBank(g *models.Guild, player *models.Character, index, n int) (models.Item, error) {
+	if index < 0 || index >= len(player.Inventory) {
+		return models.Item{}, fmt.Errorf("no such item")
+	}
+	probe := player.Inventory[index]
+	if IsStackable(probe) {
+		probe.Quantity = min(max(n, 1), StackSize(probe))
+	}
+	if !HasRoomFor(g.Bank.Items, data.GuildBankMaxItems, probe) {
+		return models.Item{}, fmt.Errorf("the guild bank is full (%d slots)", data.GuildBankMaxItems)
+	}
+	item := TakeFromInventory(&player.Inventory, index, n)
+	AddItemToInventory(&g.Bank.Items, item)
+	RecordItemChange(player, item.Name, -StackSize(item), ReasonGuildBank, "guild:"+g.Name)
+	return item, nil
+}
+
+// WithdrawItemFromGuildBank moves up to n items from the guild bank entry at
+// index into member i's inventory, if their rank may take items.
+func WithdrawItemFromGuildBank(g *models.Guild, i int, player *models.Character, index, n int) (models.Item, error) {
+	if !FindGuildRank(g.Members[i].Rank).WithdrawItems {
+		return models.Item{}, ErrGuildRank
+	}
+	if index < 0 || index >= len(g.Bank.Items) {
+		return models.Item{}, fmt.Errorf("no such item")
+	}
+	probe := g.Bank.Items[index]
+	if IsStackable(probe) {
+		probe.Quantity = min(max(n, 1), StackSize(probe))
+	}
+	if !HasRoomFor(player.Inventory, InventoryCapacity(player), probe) {
+		return models.Item{}, fmt.Errorf("your inventory is full (%d slots)", InventoryCapacity(player))
+	}
+	item := TakeFromInventory(&g.Bank.Items, index, n)
+	AddItemToInventory(&player.Inventory, item)
+	RecordItemChange(player, item.Name, StackSize(item), ReasonGuildBank, "guild:"+g.Name)
+	return item, nil
+}
+
+// AddGuildChat posts a line to the guild's chat, dropping the oldest past
+// GuildChatSize.
+func AddGuildChat(g *models.Guild, from, text string, now int64) (models.GuildChatLine, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > data.GuildChatMaxLen {
+		return models.GuildChatLine{}, ErrGuildChat
+	}
+	line := models.GuildChatLine{Time: now, From: from, Text: text}
+	g.Chat = append(g.Chat, line)
+	if len(g.Chat) > data.GuildChatSize {
+		g.Chat = g.Chat[len(g.Chat)-data.GuildChatSize:]
+	}
+	return line, nil
+}
+
+// GuildChatText is how a chat line reads.
+func GuildChatText(g *models.Guild, line models.GuildChatLine) string {
+	return fmt.Sprintf("[%s] %s: %s", g.Name, line.From, line.Text)
+}
+
+// AddGuildTideDamage credits damage a member's village dealt the tide leader
+// to their guild, on the leader's tally for this cycle and the guild's own.
+func AddGuildTideDamage(leader *models.TideLeader, g *models.Guild, damage int) {
+	if damage <= 0 {
+		return
+	}
+	if leader.GuildDamage == nil {
+		leader.GuildDamage = map[string]int{}
+	}
+	leader.GuildDamage[g.Name] += damage
+	g.TideDamage += damage
+}
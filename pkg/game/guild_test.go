@@ -0,0 +1,255 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+func guildFounder(gold int) *models.Character {
+	return &models.Character{Name: "Ada", ResourceStorageMap: map[string]models.Resource{
+		"Gold": {Name: "Gold", Stock: gold},
+		"Wood": {Name: "Wood", Stock: 300},
+	}}
+}
+
+// testGuild is a guild led by Ada (account 1) with Bo (2) and Cy (3) as
+// recruits.
+func testGuild(t *testing.T) models.Guild {
+	t.Helper()
+	g, err := NewGuild("  Iron   Wolves ", 1, guildFounder(data.GuildCreateCost), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"Bo", "Cy"} {
+		if err := InviteToGuild(&g, 0, int64(i+2), name, 100); err != nil {
+			t.Fatal(err)
+		}
+		if err := JoinGuild(&g, int64(i+2), name, int64(200+i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestGuildNames(t *testing.T) {
+	for _, bad := range []string{"ab", strings.Repeat("x", data.GuildNameMaxLen+1), "<b>Guild</b>"} {
+		if _, err := GuildName(bad); err != ErrGuildName {
+			t.Errorf("GuildName(%q): got %v", bad, err)
+		}
+	}
+	if name, err := GuildName("  The  Night's   Watch "); err != nil || name != "The Night's Watch" {
+		t.Errorf("GuildName: got %q, %v", name, err)
+	}
+}
+
+func TestFoundingAGuild(t *testing.T) {
+	if _, err := NewGuild("Iron Wolves", 1, guildFounder(data.GuildCreateCost-1), 100); err == nil {
+		t.Error("founded a guild without enough gold")
+	}
+	founder := guildFounder(data.GuildCreateCost)
+	g, err := NewGuild("Iron Wolves", 1, founder, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if founder.ResourceStorageMap["Gold"].Stock != data.GuildCreateCost {
+		t.Error("drafting a guild should not charge the founder")
+	}
+	PayGuildFounding(&g, founder)
+	if founder.ResourceStorageMap["Gold"].Stock != 0 {
+		t.Errorf("founder has %d gold left", founder.ResourceStorageMap["Gold"].Stock)
+	}
+	if len(g.Members) != 1 || g.Members[0].Rank != "leader" {
+		t.Errorf("founder joined as %+v", g.Members)
+	}
+}
+
+func TestGuildInvitations(t *testing.T) {
+	g := testGuild(t)
+	if g.Name != "Iron Wolves" || len(g.Members) != 3 || g.Members[2].Rank != "recruit" {
+		t.Fatalf("guild: %q with %+v", g.Name, g.Members)
+	}
+	if err := InviteToGuild(&g, 1, 4, "Di", 300); err != ErrGuildRank {
+		t.Errorf("recruit inviting: got %v", err)
+	}
+	if err := InviteToGuild(&g, 0, 2, "Bo", 300); err != ErrGuildMember {
+		t.Errorf("inviting a member: got %v", err)
+	}
+	if err := InviteToGuild(&g, 0, 4, "Di", 300); err != nil {
+		t.Fatal(err)
+	}
+	if err := InviteToGuild(&g, 0, 4, "Di", 300); err != ErrGuildInvited {
+		t.Errorf("inviting twice: got %v", err)
+	}
+	DeclineGuildInvite(&g, 4, "Di")
+	if err := JoinGuild(&g, 4, "Di", 300); err != ErrGuildNotInvited {
+		t.Errorf("joining after declining: got %v", err)
+	}
+
+	for len(g.Members)+len(g.Invites) < data.GuildMaxMembers {
+		InviteToGuild(&g, 0, int64(100+len(g.Invites)), "Extra", 300)
+	}
+	if err := InviteToGuild(&g, 0, 5, "Ed", 300); err != ErrGuildFull {
+		t.Errorf("inviting to a full guild: got %v", err)
+	}
+}
+
+func TestGuildRankPermissions(t *testing.T) {
+	g := testGuild(t)
+	if err := KickFromGuild(&g, 1, 2); err != ErrGuildRank {
+		t.Errorf("recruit kicking: got %v", err)
+	}
+	if err := PromoteGuildMember(&g, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := PromoteGuildMember(&g, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if g.Members[1].Rank != "officer" {
+		t.Fatalf("Bo is %s after two promotions", g.Members[1].Rank)
+	}
+	if err := PromoteGuildMember(&g, 0, 1); err != ErrGuildRank {
+		t.Errorf("promoting to leader: got %v", err)
+	}
+	if err := KickFromGuild(&g, 1, 0); err != ErrGuildRank {
+		t.Errorf("officer kicking the leader: got %v", err)
+	}
+	if err := PromoteGuildMember(&g, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := PromoteGuildMember(&g, 1, 2); err != ErrGuildRank {
+		t.Errorf("officer promoting to officer: got %v", err)
+	}
+	if err := DemoteGuildMember(&g, 1, 2); err != nil || g.Members[2].Rank != "recruit" {
+		t.Errorf("officer demoting a member: %v, now %s", err, g.Members[2].Rank)
+	}
+	if err := DemoteGuildMember(&g, 1, 2); err != ErrGuildRank {
+		t.Errorf("demoting a recruit: got %v", err)
+	}
+	if err := KickFromGuild(&g, 1, 2); err != nil || len(g.Members) != 2 {
+		t.Errorf("officer kicking a recruit: %v, %d members left", err, len(g.Members))
+	}
+}
+
+func TestLeaderLeavingHandsOverTheGuild(t *testing.T) {
+	g := testGuild(t)
+	if LeaveGuild(&g, 0) {
+		t.Fatal("guild with members left should not disband")
+	}
+	if g.Members[0].Name != "Bo" || g.Members[0].Rank != "leader" {
+		t.Errorf("the longest-serving member should lead, got %+v", g.Members)
+	}
+	LeaveGuild(&g, 0)
+	if !LeaveGuild(&g, 0) {
+		t.Error("the last member leaving should disband the guild")
+	}
+}
+
+func TestGuildBankAllowances(t *testing.T) {
+	g := testGuild(t)
+	leader := guildFounder(0)
+	if err := DepositToGuildBank(&g, leader, "Wood", 300); err != nil {
+		t.Fatal(err)
+	}
+	bo := guildFounder(0)
+	if err := WithdrawFromGuildBank(&g, 1, bo, "Wood", 10, "day1"); err != ErrGuildRank {
+		t.Errorf("recruit withdrawing: got %v", err)
+	}
+	PromoteGuildMember(&g, 0, 1)
+	if err := WithdrawFromGuildBank(&g, 1, bo, "Wood", 80, "day1"); err != nil {
+		t.Fatal(err)
+	}
+	if left := GuildWithdrawLeft(&g, 1, "day1"); left != 20 {
+		t.Errorf("member has %d left today", left)
+	}
+	if err := WithdrawFromGuildBank(&g, 1, bo, "Wood", 30, "day1"); err == nil {
+		t.Error("member withdrew past their daily allowance")
+	}
+	if err := WithdrawFromGuildBank(&g, 1, bo, "Wood", 30, "day2"); err != nil {
+		t.Errorf("allowance should reset the next day: %v", err)
+	}
+	if err := WithdrawFromGuildBank(&g, 0, leader, "Wood", 200, "day2"); err != ErrGuildBank {
+		t.Errorf("overdrawing the bank: got %v", err)
+	}
+	if err := WithdrawFromGuildBank(&g, 0, leader, "Wood", 190, "day2"); err != nil {
+		t.Errorf("leader withdrawal: %v", err)
+	}
+	if _, ok := g.Bank.Resources["Wood"]; ok {
+		t.Error("an emptied resource should leave the bank")
+	}
+	if bo.ResourceStorageMap["Wood"].Stock != 300+110 {
+		t.Errorf("Bo has %d wood", bo.ResourceStorageMap["Wood"].Stock)
+	}
+}
+
+func TestGuildBankItemsNeedRank(t *testing.T) {
+	g := testGuild(t)
+	leader := guildFounder(0)
+	leader.Inventory = []models.Item{{Name: "Sword", ItemType: "weapon"}}
+	if _, err := DepositItemToGuildBank(&g, leader, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(leader.Inventory) != 0 || len(g.Bank.Items) != 1 {
+		t.Fatalf("deposit left %d in inventory, %d in bank", len(leader.Inventory), len(g.Bank.Items))
+	}
+	PromoteGuildMember(&g, 0, 1)
+	if _, err := WithdrawItemFromGuildBank(&g, 1, guildFounder(0), 0, 1); err != ErrGuildRank {
+		t.Errorf("member taking an item: got %v", err)
+	}
+	PromoteGuildMember(&g, 0, 1)
+	bo := guildFounder(0)
+	if _, err := WithdrawItemFromGuildBank(&g, 1, bo, 0, 1); err != nil || len(bo.Inventory) != 1 {
+		t.Errorf("officer taking an item: %v", err)
+	}
+}
+
+func TestGuildChatIsCapped(t *testing.T) {
+	g := testGuild(t)
+	if _, err := AddGuildChat(&g, "Ada", "   ", 1); err != ErrGuildChat {
+		t.Errorf("blank chat: got %v", err)
+	}
+	if _, err := AddGuildChat(&g, "Ada", strings.Repeat("a", data.GuildChatMaxLen+1), 1); err != ErrGuildChat {
+		t.Errorf("long chat: got %v", err)
+	}
+	for i := 0; i < data.GuildChatSize+5; i++ {
+		AddGuildChat(&g, "Ada", "hello", int64(i))
+	}
+	if len(g.Chat) != data.GuildChatSize || g.Chat[0].Time != 5 {
+		t.Errorf("chat kept %d lines from %d", len(g.Chat), g.Chat[0].Time)
+	}
+	if got := GuildChatText(&g, g.Chat[0]); got != "[Iron Wolves] Ada: hello" {
+		t.Errorf("chat text %q", got)
+	}
+}
+
+func TestScrubGuildTraces(t *testing.T) {
+	g := testGuild(t)
+	AddGuildChat(&g, "Bo", "hi", 1)
+	AddGuildChat(&g, "Ada", "welcome", 2)
+	InviteToGuild(&g, 0, 2, "Bo2", 3)
+	if !ScrubGuildTraces(&g, 2, []string{"Bo", "Bo2"}) {
+		t.Fatal("scrub reported no change")
+	}
+	if GuildMemberIndex(&g, 2, "Bo") >= 0 || len(g.Invites) != 0 {
+		t.Error("scrubbed account is still in the guild")
+	}
+	if len(g.Chat) != 1 || g.Chat[0].From != "Ada" {
+		t.Errorf("chat after scrub: %+v", g.Chat)
+	}
+	if ScrubGuildTraces(&g, 9, []string{"Zed"}) {
+		t.Error("scrubbing a stranger changed the guild")
+	}
+}
+
+func TestGuildTideDamage(t *testing.T) {
+	g := testGuild(t)
+	leader := &models.TideLeader{}
+	AddGuildTideDamage(leader, &g, 40)
+	AddGuildTideDamage(leader, &g, 0)
+	AddGuildTideDamage(leader, &g, 15)
+	if leader.GuildDamage["Iron Wolves"] != 55 || g.TideDamage != 55 {
+		t.Errorf("leader tally %d, guild total %d", leader.GuildDamage["Iron Wolves"], g.TideDamage)
+	}
+}
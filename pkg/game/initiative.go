@@ -0,0 +1,98 @@
+package game
+
+import "rpg-game/pkg/models"
+
+// Initiative: every combatant fills a gauge by its speed each tick and acts
+// when the gauge reaches TurnCost, so a combatant twice as fast as its foe
+// acts twice for each of the foe's turns.
+const (
+	BaseSpeed = 10  // speed of an unmodified character, guard or monster
+	TurnCost  = 100 // gauge spent per action
+	MinSpeed  = 1
+
+	// MaxActionsBetweenTurns caps how many other actions can happen before
+	// the player acts again, however slow the player is.
+	MaxActionsBetweenTurns = 8
+)
+
+// CharacterSpeed is a character's base speed: BaseSpeed plus one per
+// DexterityPerSpeed points of dexterity, counting gear.
+func CharacterSpeed(player *models.Character) int {
+	return BaseSpeed + TotalAttributes(player).Dexterity/DexterityPerSpeed
+}
+
+// Speed is a combatant's current speed: its base speed plus haste and slow
+// effects, never below MinSpeed. Monsters and guards without a speed use
+// BaseSpeed.
+func Speed(target interface{}) int {
+	t := statusTargetOf(target)
+	if t == nil {
+		return BaseSpeed
+	}
+	base := t.speed
+	if base <= 0 {
+		base = BaseSpeed
+	}
+	return max(base+StatusSpeedMod(*t.effects), MinSpeed)
+}
+
+// Combatant is one entry in a TurnQueue.
+type Combatant struct {
+	ID    string // "player", "mob" or "guard:<index>"
+	Name  string
+	Speed int
+}
+
+// TurnQueue decides who acts next from each combatant's speed.
+type TurnQueue struct {
+	Gauges map[string]int `json:"gauges"`
+}
+
+// Next returns the ID of the next combatant to act and spends its turn.
+// Gauges fill by speed until one reaches TurnCost; the fullest gauge acts,
+// earlier entries in cs winning ties. It returns "" if cs is empty.
+func (q *TurnQueue) Next(cs []Combatant) string {
+	if len(cs) == 0 {
+		return ""
+	}
+	if q.Gauges == nil {
+		q.Gauges = map[string]int{}
+	}
+	for {
+		best := -1
+		for i, c := range cs {
+			if q.Gauges[c.ID] >= TurnCost && (best < 0 || q.Gauges[c.ID] > q.Gauges[cs[best].ID]) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			q.Gauges[cs[best].ID] -= TurnCost
+			return cs[best].ID
+		}
+		for _, c := range cs {
+			q.Gauges[c.ID] += max(c.Speed, MinSpeed)
+		}
+	}
+}
+
+// Preview returns the names of the next n combatants to act, without
+// changing the queue.
+func (q *TurnQueue) Preview(cs []Combatant, n int) []string {
+	names := make(map[string]string, len(cs))
+	for _, c := range cs {
+		names[c.ID] = c.Name
+	}
+	sim := TurnQueue{Gauges: make(map[string]int, len(q.Gauges))}
+	for id, g := range q.Gauges {
+		sim.Gauges[id] = g
+	}
+	var order []string
+	for len(order) < n {
+		id := sim.Next(cs)
+		if id == "" {
+			break
+		}
+		order = append(order, names[id])
+	}
+	return order
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestTurnQueueGivesFastCombatantsExtraTurns(t *testing.T) {
+	var q TurnQueue
+	cs := []Combatant{{ID: "player", Speed: 10}, {ID: "mob", Speed: 20}}
+	counts := map[string]int{}
+	for i := 0; i < 30; i++ {
+		counts[q.Next(cs)]++
+	}
+	if counts["mob"] != 2*counts["player"] {
+		t.Errorf("a monster twice as fast should act twice as often, got %v", counts)
+	}
+}
+
+func TestTurnQueueTiesFavourEarlierEntries(t *testing.T) {
+	var q TurnQueue
+	cs := []Combatant{{ID: "player", Speed: 10}, {ID: "mob", Speed: 10}}
+	for i := 0; i < 6; i++ {
+		want := "player"
+		if i%2 == 1 {
+			want = "mob"
+		}
+		if got := q.Next(cs); got != want {
+			t.Fatalf("turn %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestTurnQueuePreviewLeavesQueueUntouched(t *testing.T) {
+	var q TurnQueue
+	cs := []Combatant{{ID: "player", Name: "Hero", Speed: 10}, {ID: "mob", Name: "Wolf", Speed: 15}}
+	order := q.Preview(cs, 5)
+	if len(order) != 5 {
+		t.Fatalf("preview length = %d, want 5", len(order))
+	}
+	for i, name := range order {
+		id := "player"
+		if name == "Wolf" {
+			id = "mob"
+		}
+		if got := q.Next(cs); got != id {
+			t.Fatalf("turn %d: preview said %s, queue gave %s", i, name, got)
+		}
+	}
+}
+
+func TestSpeedFromDexterityAndEffects(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	player.Attributes.Dexterity = 10
+	base := Speed(&player)
+	if base != BaseSpeed+10/DexterityPerSpeed {
+		t.Fatalf("speed = %d, want %d", base, BaseSpeed+10/DexterityPerSpeed)
+	}
+	player.StatusEffects = []models.StatusEffect{{Type: "haste", Duration: 3, Potency: 5}}
+	if got := Speed(&player); got != base+5 {
+		t.Errorf("hasted speed = %d, want %d", got, base+5)
+	}
+	player.StatusEffects = []models.StatusEffect{{Type: "slow", Duration: 3, Potency: 100}}
+	if got := Speed(&player); got != MinSpeed {
+		t.Errorf("slowed speed = %d, want the floor of %d", got, MinSpeed)
+	}
+}
+
+func TestMonsterAndGuardSpeed(t *testing.T) {
+	if pixie, ooze := GenerateMonster("pixie", 5, 2), GenerateMonster("ooze", 5, 2); Speed(&pixie) <= Speed(&ooze) {
+		t.Errorf("fey (%d) should be faster than plants (%d)", Speed(&pixie), Speed(&ooze))
+	}
+	var unset models.Monster
+	if got := Speed(&unset); got != BaseSpeed {
+		t.Errorf("a monster without a speed should use the base speed, got %d", got)
+	}
+	guard := GenerateGuard(10)
+	if got := Speed(&guard); got != BaseSpeed+1 {
+		t.Errorf("level 10 guard speed = %d, want %d", got, BaseSpeed+1)
+	}
+}
+
+func TestPvPSnapshotsCarrySpeed(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	player.Attributes.Dexterity = 25
+	want := CharacterSpeed(&player)
+	if m := CharacterToArenaMonster(&player); Speed(&m) != want {
+		t.Errorf("arena monster speed = %d, want %d", Speed(&m), want)
+	}
+	guest := InnGuestFromCharacter(&player, 1, 0)
+	if m := InnGuestToMonster(&guest); Speed(&m) != want {
+		t.Errorf("inn guest monster speed = %d, want %d", Speed(&m), want)
+	}
+}
@@ -0,0 +1,246 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+// MaxStackSize is the most items one inventory or stash slot holds.
+const MaxStackSize = 99
+
+// Inventory sort keys and filters accepted by SortInventory and
+// FilterInventory.
+var (
+	InventorySortKeys = []string{"type", "name", "rarity", "cp"}
+	InventoryFilters  = []string{"all", "consumable", "equipment", "skill_scroll", "repair_kit"}
+)
+
+// IsStackable reports whether copies of an item share one slot. Equipment
+// rolls unique stats and durability, so it never stacks.
+func IsStackable(item models.Item) bool {
+	switch item.ItemType {
+	case "consumable", "skill_scroll", "repair_kit":
+		return true
+	}
+	return false
+}
+
+// StackSize is the number of items in an inventory entry.
+func StackSize(item models.Item) int {
+	return max(item.Quantity, 1)
+}
+
+// sameStack reports whether b can be merged into the stack a.
+func sameStack(a, b models.Item) bool {
+	return IsStackable(a) && a.ItemType == b.ItemType && a.Name == b.Name && a.Rarity == b.Rarity
+}
+
+// InventoryCapacity is the number of slots a character can fill.
+func InventoryCapacity(player *models.Character) int {
+	return config.Current().Balance.InventorySlots
+}
+
+// StashCapacity is the number of slots in a village stash, warehouse
+// included.
+func StashCapacity(village *models.Village) int {
+	balance := config.Current().Balance
+	return balance.StashBaseSlots + village.Level*balance.StashSlotsPerVillageLevel +
+		BuildingLevel(village, BuildingWarehouse)*WarehouseStashPerLevel
+}
+
+// slotsNeeded is how many new slots adding item to inventory would take
+// after topping up existing stacks.
+func slotsNeeded(inventory []models.Item, item models.Item) int {
+	n := StackSize(item)
+	if IsStackable(item) {
+		for _, existing := range inventory {
+			if sameStack(existing, item) {
+				n -= MaxStackSize - StackSize(existing)
+			}
+		}
+		if n <= 0 {
+			return 0
+		}
+		return (n + MaxStackSize - 1) / MaxStackSize
+	}
+	return n
+}
+
+// HasRoomFor reports whether item fits in inventory without exceeding
+// capacity slots.
+func HasRoomFor(inventory []models.Item, capacity int, item models.Item) bool {
+	return len(inventory)+slotsNeeded(inventory, item) <= capacity
+}
+
+// AddItemToInventory adds item, merging it into existing stacks, without
+// checking capacity.
+func AddItemToInventory(inventory *[]models.Item, item models.Item) {
+	if !IsStackable(item) {
+		*inventory = append(*inventory, item)
+		return
+	}
+	n := StackSize(item)
+	for i := range *inventory {
+		existing := &(*inventory)[i]
+		if n == 0 || !sameStack(*existing, item) {
+			continue
+		}
+		take := min(MaxStackSize-StackSize(*existing), n)
+		if take > 0 {
+			existing.Quantity = StackSize(*existing) + take
+			n -= take
+		}
+	}
+	for n > 0 {
+		stack := item
+		stack.Quantity = min(n, MaxStackSize)
+		*inventory = append(*inventory, stack)
+		n -= stack.Quantity
+	}
+}
+
+// StoreItem adds item to the player's inventory if there is room.
+func StoreItem(player *models.Character, item models.Item) bool {
+	if !HasRoomFor(player.Inventory, InventoryCapacity(player), item) {
+		return false
+	}
+	AddItemToInventory(&player.Inventory, item)
+	return true
+}
+
+// RemoveItemFromInventory takes one item from the entry at index, removing
+// the entry once its stack is empty.
+func RemoveItemFromInventory(inventory *[]models.Item, index int) {
+	if (*inventory)[index].Quantity > 1 {
+		(*inventory)[index].Quantity--
+		return
+	}
+	*inventory = append((*inventory)[:index], (*inventory)[index+1:]...)
+}
+
+// TakeFromInventory removes up to n items from the entry at index and
+// returns them as one stack.
+func TakeFromInventory(inventory *[]models.Item, index, n int) models.Item {
+	item := (*inventory)[index]
+	n = min(max(n, 1), StackSize(item))
+	if n < StackSize(item) {
+		(*inventory)[index].Quantity = StackSize(item) - n
+	} else {
+		*inventory = append((*inventory)[:index], (*inventory)[index+1:]...)
+	}
+	if IsStackable(item) {
+		item.Quantity = n
+	}
+	return item
+}
+
+// UseInventoryItem uses one consumable from the stack at index and reports
+// whether it had an effect. The stack shrinks either way, as in combat.
+func UseInventoryItem(character *models.Character, index int, reason, ref string) (models.Item, bool) {
+	item := character.Inventory[index]
+	used := UseConsumableItem(item, character)
+	RemoveItemFromInventory(&character.Inventory, index)
+	RecordItemChange(character, item.Name, -1, reason, ref)
+	return item, used
+}
+
+// CompactInventory merges loose copies of stackable items, as saved before
+// items stacked.
+func CompactInventory(inventory *[]models.Item) {
+	merged := make([]models.Item, 0, len(*inventory))
+	for _, item := range *inventory {
+		AddItemToInventory(&merged, item)
+	}
+	*inventory = merged
+}
+
+// CountItem totals the stacked quantity of items with the given name.
+func CountItem(inventory []models.Item, name string) int {
+	n := 0
+	for _, item := range inventory {
+		if item.Name == name {
+			n += StackSize(item)
+		}
+	}
+	return n
+}
+
+var itemTypeOrder = map[string]int{"consumable": 0, "repair_kit": 1, "skill_scroll": 2, "equipment": 3}
+
+// SortInventory orders the inventory in place by one of InventorySortKeys.
+func SortInventory(inventory []models.Item, key string) error {
+	var less func(a, b models.Item) bool
+	switch key {
+	case "type":
+		less = func(a, b models.Item) bool {
+			if itemTypeOrder[a.ItemType] != itemTypeOrder[b.ItemType] {
+				return itemTypeOrder[a.ItemType] < itemTypeOrder[b.ItemType]
+			}
+			return a.Name < b.Name
+		}
+	case "name":
+		less = func(a, b models.Item) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "rarity":
+		less = func(a, b models.Item) bool { return a.Rarity > b.Rarity }
+	case "cp":
+		less = func(a, b models.Item) bool { return a.CP > b.CP }
+	default:
+		return fmt.Errorf("unknown sort %q", key)
+	}
+	sort.SliceStable(inventory, func(i, j int) bool { return less(inventory[i], inventory[j]) })
+	return nil
+}
+
+// FilterInventory returns the indices of the entries matching one of
+// InventoryFilters.
+func FilterInventory(inventory []models.Item, filter string) []int {
+	indices := []int{}
+	for i, item := range inventory {
+		if filter == "" || filter == "all" || item.ItemType == filter {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// DepositToStash moves up to n items from the inventory entry at index into
+// the village stash.
+func DepositToStash(player *models.Character, village *models.Village, index, n int) (models.Item, error) {
+	if index < 0 || index >= len(player.Inventory) {
+		return models.Item{}, fmt.Errorf("no such item")
+	}
+	probe := player.Inventory[index]
+	if IsStackable(probe) {
+		probe.Quantity = min(max(n, 1), StackSize(probe))
+	}
+	if !HasRoomFor(village.Stash, StashCapacity(village), probe) {
+		return models.Item{}, fmt.Errorf("the stash is full (%d slots)", StashCapacity(village))
+	}
+	item := TakeFromInventory(&player.Inventory, index, n)
+	AddItemToInventory(&village.Stash, item)
+	RecordItemChange(player, item.Name, -StackSize(item), ReasonStash, "village:"+village.Name)
+	return item, nil
+}
+
+// WithdrawFromStash moves up to n items from the stash entry at index into
+// the player's inventory.
+func WithdrawFromStash(player *models.Character, village *models.Village, index, n int) (models.Item, error) {
+	if index < 0 || index >= len(village.Stash) {
+		return models.Item{}, fmt.Errorf("no such item")
+	}
+	probe := village.Stash[index]
+	if IsStackable(probe) {
+		probe.Quantity = min(max(n, 1), StackSize(probe))
+	}
+	if !HasRoomFor(player.Inventory, InventoryCapacity(player), probe) {
+		return models.Item{}, fmt.Errorf("your inventory is full (%d slots)", InventoryCapacity(player))
+	}
+	item := TakeFromInventory(&village.Stash, index, n)
+	AddItemToInventory(&player.Inventory, item)
+	RecordItemChange(player, item.Name, StackSize(item), ReasonStash, "village:"+village.Name)
+	return item, nil
+}
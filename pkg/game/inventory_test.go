@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+func TestStackingAndCapacity(t *testing.T) {
+	inventory := []models.Item{}
+	for i := 0; i < MaxStackSize+1; i++ {
+		AddItemToInventory(&inventory, CreateHealthPotion("small"))
+	}
+	AddItemToInventory(&inventory, CreateManaPotion("small"))
+	if len(inventory) != 3 || StackSize(inventory[0]) != MaxStackSize || StackSize(inventory[1]) != 1 {
+		t.Fatalf("expected a full stack, an overflow stack and a mana potion, got %d entries", len(inventory))
+	}
+	if CountItem(inventory, "Small Health Potion") != MaxStackSize+1 {
+		t.Errorf("expected %d potions, got %d", MaxStackSize+1, CountItem(inventory, "Small Health Potion"))
+	}
+
+	RemoveItemFromInventory(&inventory, 1)
+	if len(inventory) != 2 {
+		t.Fatalf("removing the last item of a stack should drop the entry, got %d entries", len(inventory))
+	}
+	RemoveItemFromInventory(&inventory, 0)
+	if StackSize(inventory[0]) != MaxStackSize-1 {
+		t.Errorf("expected the stack to shrink by one, got %d", StackSize(inventory[0]))
+	}
+
+	sword := models.Item{Name: "Sword", ItemType: "equipment", Slot: 5}
+	if !HasRoomFor(inventory, 3, sword) || HasRoomFor(inventory, 2, sword) {
+		t.Error("equipment needs its own slot")
+	}
+	if !HasRoomFor(inventory, 2, CreateHealthPotion("small")) {
+		t.Error("a potion should top up the existing stack without a new slot")
+	}
+
+	player := GenerateCharacter("Packrat", 1, 1)
+	player.Inventory = nil
+	for i := 0; i < config.Current().Balance.InventorySlots; i++ {
+		player.Inventory = append(player.Inventory, sword)
+	}
+	if LootItem(&player, CreateManaPotion("small"), ReasonMonsterDrop, "test") {
+		t.Error("a full inventory should leave new stacks behind")
+	}
+}
+
+func TestCompactAndSortInventory(t *testing.T) {
+	inventory := []models.Item{
+		{Name: "Axe", ItemType: "equipment", Rarity: 2, CP: 9},
+		CreateHealthPotion("small"),
+		CreateHealthPotion("small"),
+		CreateHealthPotion("small"),
+	}
+	CompactInventory(&inventory)
+	if len(inventory) != 2 || StackSize(inventory[1]) != 3 {
+		t.Fatalf("expected loose potions merged into one stack, got %+v", inventory)
+	}
+	if err := SortInventory(inventory, "type"); err != nil || inventory[0].ItemType != "consumable" {
+		t.Errorf("sort by type should put consumables first, got %+v", inventory)
+	}
+	if got := FilterInventory(inventory, "equipment"); len(got) != 1 || inventory[got[0]].Name != "Axe" {
+		t.Errorf("filter should find the axe, got %v", got)
+	}
+	if err := SortInventory(inventory, "weight"); err == nil {
+		t.Error("unknown sort key should fail")
+	}
+}
+
+func TestVillageStash(t *testing.T) {
+	player := GenerateCharacter("Keeper", 1, 1)
+	potions := CreateHealthPotion("small")
+	potions.Quantity = 5
+	player.Inventory = []models.Item{potions}
+	village := &models.Village{Name: "Oakvale", Level: 2}
+	if StashCapacity(village) != config.Current().Balance.StashBaseSlots+2*config.Current().Balance.StashSlotsPerVillageLevel {
+		t.Errorf("stash should grow with village level, got %d", StashCapacity(village))
+	}
+
+	if _, err := DepositToStash(&player, village, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if StackSize(player.Inventory[0]) != 3 || StackSize(village.Stash[0]) != 2 {
+		t.Fatalf("expected 3 carried and 2 stored, got %+v / %+v", player.Inventory, village.Stash)
+	}
+	if _, err := WithdrawFromStash(&player, village, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(village.Stash) != 0 || CountItem(player.Inventory, potions.Name) != 5 {
+		t.Errorf("withdraw should merge the stack back, got %+v", player.Inventory)
+	}
+}
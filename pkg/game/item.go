@@ -25,7 +25,16 @@ func GenerateItem(rarity int) models.Item {
 		}
 	}
 
-	item.CP = item.StatsMod.AttackMod + item.StatsMod.DefenseMod + item.StatsMod.HitPointMod
+	// Every second rarity tier rolls a bonus to a random primary attribute.
+	for i := 0; i < rarity/2; i++ {
+		*AttributeField(&item.StatsMod.Attributes, AttributeNames[rand.Intn(len(AttributeNames))]) += rand.Intn(3) + 1
+	}
+
+	if !rollSpecialItem(&item) {
+		rollAffixes(&item)
+	}
+	item.CP = ItemPower(item)
+	EnsureDurability(&item)
 	return item
 }
 
@@ -158,14 +167,14 @@ func CreateSkillScroll(skill models.Skill) models.Item {
 }
 
 func EquipBestItem(newItem models.Item, equipment *map[int]models.Item, inventory *[]models.Item) {
-	if newItem.ItemType == "consumable" || newItem.ItemType == "skill_scroll" {
-		(*inventory) = append((*inventory), newItem)
+	if newItem.ItemType != "equipment" {
+		AddItemToInventory(inventory, newItem)
 		return
 	}
 
 	currentItem, ok := (*equipment)[newItem.Slot]
 	if ok {
-		if newItem.CP > currentItem.CP {
+		if EquipScore(newItem, *equipment) > EquipScore(currentItem, *equipment) {
 			(*equipment)[newItem.Slot] = newItem
 			(*inventory) = append((*inventory), currentItem)
 		}
@@ -177,9 +186,22 @@ func EquipBestItem(newItem models.Item, equipment *map[int]models.Item, inventor
 func CalculateItemMods(equipment map[int]models.Item) models.StatMod {
 	statMod := models.StatMod{AttackMod: 0, DefenseMod: 0, HitPointMod: 0}
 	for _, item := range equipment {
+		if IsBroken(item) {
+			continue
+		}
 		statMod.AttackMod += item.StatsMod.AttackMod
 		statMod.DefenseMod += item.StatsMod.DefenseMod
 		statMod.HitPointMod += item.StatsMod.HitPointMod
+		statMod.Attributes = AddAttributes(statMod.Attributes, item.StatsMod.Attributes)
+		for _, a := range item.Affixes {
+			addEffect(&statMod, a.Effect, a.Value)
+		}
+		for _, a := range item.Enchantments {
+			addEffect(&statMod, a.Effect, a.Value)
+		}
+	}
+	for effect, v := range activeSetEffects(equipment) {
+		addEffect(&statMod, effect, v)
 	}
 	return statMod
 }
@@ -216,10 +238,6 @@ func UseConsumableItem(item models.Item, character *models.Character) bool {
 	}
 }
 
-func RemoveItemFromInventory(inventory *[]models.Item, index int) {
-	*inventory = append((*inventory)[:index], (*inventory)[index+1:]...)
-}
-
 func DropBeastMaterial(monsterType string, player *models.Character) (string, int) {
 	var materials []string
 	var dropChance int
@@ -243,16 +261,11 @@ func DropBeastMaterial(monsterType string, player *models.Character) (string, in
 		}
 	}
 
-	if rand.Intn(100) < dropChance {
+	if rand.Intn(100) < LootChance(player, dropChance) {
 		material := materials[rand.Intn(len(materials))]
 		quantity := rand.Intn(3) + 1
 
-		resource, exists := player.ResourceStorageMap[material]
-		if !exists {
-			resource = models.Resource{Name: material, Stock: 0, RollModifier: 0}
-		}
-		resource.Stock += quantity
-		player.ResourceStorageMap[material] = resource
+		AdjustResource(player, material, quantity, ReasonBeastMaterial, "monster:"+monsterType)
 
 		return material, quantity
 	}
@@ -260,14 +273,14 @@ func DropBeastMaterial(monsterType string, player *models.Character) (string, in
 }
 
 func EquipGuardItem(newItem models.Item, equipment *map[int]models.Item, inventory *[]models.Item) {
-	if newItem.ItemType == "consumable" || newItem.ItemType == "skill_scroll" {
+	if newItem.ItemType != "equipment" {
 		(*inventory) = append((*inventory), newItem)
 		return
 	}
 
 	currentItem, ok := (*equipment)[newItem.Slot]
 	if ok {
-		if newItem.CP > currentItem.CP {
+		if EquipScore(newItem, *equipment) > EquipScore(currentItem, *equipment) {
 			(*equipment)[newItem.Slot] = newItem
 			(*inventory) = append((*inventory), currentItem)
 		} else {
@@ -0,0 +1,420 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
+)
+
+// Village job kinds.
+const (
+	JobCraft   = "craft"
+	JobBuild   = "build"
+	JobUpgrade = "upgrade"
+
+	JobConstruct = "construct" // raises a village building a level
+)
+
+// Villager tasks. Harvesters gather resources; crafters and builders work
+// through the village job queue, as can harvesters taken off their resource.
+const (
+	TaskHarvesting = "harvesting"
+	TaskCrafting   = "crafting"
+	TaskBuilding   = "building"
+)
+
+// Job queue tuning.
+const (
+	MaxVillageJobs   = 5 // queued jobs per village
+	MaxDefenseLevel  = 5
+	UnstaffedJobWork = 1 // work the owner manages alone when nobody is assigned
+	MinJobWork       = 10
+)
+
+// DefenseBlueprint is a wall or tower the village can build.
+type DefenseBlueprint struct {
+	Name    string
+	Type    string
+	Cost    map[string]int
+	Defense int
+	Attack  int
+}
+
+// DefenseBlueprints lists the walls and towers in the build menu.
+var DefenseBlueprints = []DefenseBlueprint{
+	{"Wooden Wall", "wall", map[string]int{"Lumber": 50, "Stone": 20}, 10, 0},
+	{"Stone Wall", "wall", map[string]int{"Lumber": 30, "Stone": 60, "Iron": 10}, 25, 0},
+	{"Iron Wall", "wall", map[string]int{"Lumber": 20, "Stone": 80, "Iron": 40}, 40, 0},
+	{"Guard Tower", "tower", map[string]int{"Lumber": 40, "Stone": 40, "Iron": 30}, 15, 20},
+	{"Arrow Tower", "tower", map[string]int{"Lumber": 30, "Stone": 50, "Iron": 40}, 10, 35},
+	{"Iron Gate", "wall", map[string]int{"Lumber": 20, "Stone": 50, "Iron": 50}, 30, 10},
+}
+
+// FindDefenseBlueprint looks up an entry of DefenseBlueprints by name.
+func FindDefenseBlueprint(name string) (DefenseBlueprint, bool) {
+	for _, bp := range DefenseBlueprints {
+		if bp.Name == name {
+			return bp, true
+		}
+	}
+	return DefenseBlueprint{}, false
+}
+
+// NewDefense is a freshly built level 1 defense.
+func NewDefense(bp DefenseBlueprint) models.Defense {
+	return models.Defense{Name: bp.Name, Level: 1, Defense: bp.Defense, AttackPower: bp.Attack,
+		Range: 10, Built: true, Type: bp.Type}
+}
+
+// BuildDefense builds a defense at once, for the terminal game.
+func BuildDefense(player *models.Character, village *models.Village, bp DefenseBlueprint) error {
+	if err := checkCost(player, bp.Cost); err != nil {
+		return err
+	}
+	payCost(player, bp.Cost, ReasonBuilding, "defense:"+bp.Name)
+	village.Defenses = append(village.Defenses, NewDefense(bp))
+	village.DefenseLevel++
+	village.Experience += 30
+	return nil
+}
+
+// DefenseUpgradeCost is what raising a defense to its next level costs: the
+// blueprint's cost times its current level, halved.
+func DefenseUpgradeCost(bp DefenseBlueprint, level int) map[string]int {
+	cost := map[string]int{}
+	for name, n := range bp.Cost {
+		cost[name] = n * level / 2
+	}
+	return cost
+}
+
+// UpgradeDefense raises a defense a level, adding half its blueprint's
+// defense and attack.
+func UpgradeDefense(d *models.Defense, bp DefenseBlueprint) {
+	d.Level++
+	d.Defense += bp.Defense / 2
+	d.AttackPower += bp.Attack / 2
+}
+
+// JobTask is the villager task that works on jobs of a kind.
+func JobTask(kind string) string {
+	if kind == JobCraft {
+		return TaskCrafting
+	}
+	return TaskBuilding
+}
+
+// taskRole is the villager role that works twice as fast on a task.
+func taskRole(task string) string {
+	if task == TaskCrafting {
+		return "crafter"
+	}
+	return "builder"
+}
+
+// CanTakeTask reports whether a villager can be assigned the task. Guards
+// only guard; crafters and builders don't harvest.
+func CanTakeTask(v models.Villager, task string) bool {
+	switch v.Role {
+	case "harvester":
+		return true
+	case "crafter", "builder":
+		return task != TaskHarvesting
+	}
+	return false
+}
+
+// AssignVillagerTask puts a villager on crafting or building, taking them
+// off any resource.
+func AssignVillagerTask(v *models.Villager, task string) error {
+	if !CanTakeTask(*v, task) {
+		return fmt.Errorf("a %s can't work on %s", v.Role, task)
+	}
+	v.AssignedTask = task
+	v.HarvestType = ""
+	return nil
+}
+
+// IsIdleHarvester reports whether a harvester has neither a resource nor a
+// job task.
+func IsIdleHarvester(v models.Villager) bool {
+	return v.Role == "harvester" && v.HarvestType == "" &&
+		v.AssignedTask != TaskCrafting && v.AssignedTask != TaskBuilding
+}
+
+// VillagerWork is how much work a villager puts into a job each tick.
+func VillagerWork(v models.Villager, task string) int {
+	work := VillagerOutput(v)
+	if v.Role == taskRole(task) {
+		work *= 2
+	}
+	return work
+}
+
+// TaskWork is the work all villagers on a task put in each tick.
+func TaskWork(village *models.Village, task string) int {
+	work := 0
+	for _, v := range village.Villagers {
+		if v.AssignedTask == task {
+			work += VillagerWork(v, task)
+		}
+	}
+	if work == 0 {
+		return UnstaffedJobWork
+	}
+	return work
+}
+
+// checkCost reports the first resource the player is short of, if any.
+func checkCost(player *models.Character, cost map[string]int) error {
+	names := make([]string, 0, len(cost))
+	for name := range cost {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if have := ResourceBalance(player, name); have < cost[name] {
+			return fmt.Errorf("not enough %s: need %d, have %d", name, cost[name], have)
+		}
+	}
+	return nil
+}
+
+// payCost deducts a cost from the player's resources.
+func payCost(player *models.Character, cost map[string]int, reason, ref string) {
+	for name, n := range cost {
+		AdjustResource(player, name, -n, reason, ref)
+	}
+}
+
+// costWork is the work a building job takes: half its total cost.
+func costWork(cost map[string]int) int {
+	total := 0
+	for _, n := range cost {
+		total += n
+	}
+	return max(total/2, MinJobWork)
+}
+
+// addJob appends a paid-for job to the village queue.
+func addJob(village *models.Village, job models.VillageJob) models.VillageJob {
+	village.NextJobID++
+	job.ID = village.NextJobID
+	village.Jobs = append(village.Jobs, job)
+	return job
+}
+
+// checkQueue reports whether the village queue has room for another job.
+func checkQueue(village *models.Village) error {
+	if len(village.Jobs) >= MaxVillageJobs {
+		return fmt.Errorf("the village can only queue %d jobs at a time", MaxVillageJobs)
+	}
+	return nil
+}
+
+// QueueCraft pays for a recipe and queues it for the village crafters.
+// skillIdx picks the learned skill a skill upgrade recipe improves and is
+// ignored otherwise.
+func QueueCraft(player *models.Character, village *models.Village, r models.CraftingRecipe, skillIdx int) (models.VillageJob, error) {
+	if err := checkQueue(village); err != nil {
+		return models.VillageJob{}, err
+	}
+	job := models.VillageJob{Kind: JobCraft, Name: r.Name, RecipeID: r.ID, Cost: r.RequiredResources,
+		Work: max(r.VillageXP, MinJobWork)}
+	if r.Type == "skill_upgrades" {
+		if skillIdx < 0 || skillIdx >= len(player.LearnedSkills) {
+			return job, fmt.Errorf("no such skill")
+		}
+		if err := checkRecipe(player, village, r); err != nil {
+			return job, err
+		}
+		job.SkillName = player.LearnedSkills[skillIdx].Name
+		job.Name = fmt.Sprintf("%s (%s)", r.Name, job.SkillName)
+	} else if err := checkCraft(player, village, r); err != nil {
+		return job, err
+	}
+	payRecipe(player, r)
+	return addJob(village, job), nil
+}
+
+// QueueDefense pays for a wall or tower and queues it for the builders.
+func QueueDefense(player *models.Character, village *models.Village, bp DefenseBlueprint) (models.VillageJob, error) {
+	if err := checkQueue(village); err != nil {
+		return models.VillageJob{}, err
+	}
+	if err := checkCost(player, bp.Cost); err != nil {
+		return models.VillageJob{}, err
+	}
+	payCost(player, bp.Cost, ReasonBuilding, "defense:"+bp.Name)
+	return addJob(village, models.VillageJob{Kind: JobBuild, Name: bp.Name, Defense: bp.Name,
+		Cost: bp.Cost, Work: costWork(bp.Cost)}), nil
+}
+
+// PlannedDefenseLevel is the level the defense at defenseIdx reaches once
+// its queued upgrades are done.
+func PlannedDefenseLevel(village *models.Village, defenseIdx int) int {
+	level := village.Defenses[defenseIdx].Level
+	for _, job := range village.Jobs {
+		if job.Kind == JobUpgrade && job.DefenseIdx == defenseIdx {
+			level++
+		}
+	}
+	return level
+}
+
+// QueueDefenseUpgrade pays to raise the defense at defenseIdx a level and
+// queues the work for the builders.
+func QueueDefenseUpgrade(player *models.Character, village *models.Village, defenseIdx int) (models.VillageJob, error) {
+	if err := checkQueue(village); err != nil {
+		return models.VillageJob{}, err
+	}
+	if defenseIdx < 0 || defenseIdx >= len(village.Defenses) {
+		return models.VillageJob{}, fmt.Errorf("no such defense")
+	}
+	d := village.Defenses[defenseIdx]
+	bp, ok := FindDefenseBlueprint(d.Name)
+	if !ok {
+		return models.VillageJob{}, fmt.Errorf("%s can't be upgraded", d.Name)
+	}
+	level := PlannedDefenseLevel(village, defenseIdx)
+	if level >= MaxDefenseLevel {
+		return models.VillageJob{}, fmt.Errorf("%s is already at its highest level", d.Name)
+	}
+	cost := DefenseUpgradeCost(bp, level)
+	if err := checkCost(player, cost); err != nil {
+		return models.VillageJob{}, err
+	}
+	payCost(player, cost, ReasonUpgrade, "defense:"+d.Name)
+	return addJob(village, models.VillageJob{Kind: JobUpgrade, Name: fmt.Sprintf("%s to level %d", d.Name, level+1),
+		Defense: d.Name, DefenseIdx: defenseIdx, Cost: cost, Work: costWork(cost)}), nil
+}
+
+// CancelJob removes a queued job and refunds JobRefundPercent of what it
+// cost, less the share of work already done. It returns the refund.
+func CancelJob(player *models.Character, village *models.Village, jobID int) (map[string]int, error) {
+	for i, job := range village.Jobs {
+		if job.ID != jobID {
+			continue
+		}
+		percent := config.Current().Balance.JobRefundPercent
+		left := max(job.Work-job.Progress, 0)
+		refund := map[string]int{}
+		for name, n := range job.Cost {
+			if back := n * percent * left / (100 * max(job.Work, 1)); back > 0 {
+				refund[name] = back
+				AdjustResource(player, name, back, ReasonJobRefund, fmt.Sprintf("job:%d", job.ID))
+			}
+		}
+		village.Jobs = append(village.Jobs[:i], village.Jobs[i+1:]...)
+		return refund, nil
+	}
+	return nil, fmt.Errorf("no such job")
+}
+
+// ActiveJob is the index of the oldest queued job of a task, which its
+// villagers are working on, or -1.
+func ActiveJob(village *models.Village, task string) int {
+	for i, job := range village.Jobs {
+		if JobTask(job.Kind) == task {
+			return i
+		}
+	}
+	return -1
+}
+
+// JobTicksLeft estimates how many more ticks a queued job needs, counting
+// the jobs ahead of it on the same task.
+func JobTicksLeft(village *models.Village, jobIdx int) int {
+	job := village.Jobs[jobIdx]
+	task := JobTask(job.Kind)
+	remaining := 0
+	for _, j := range village.Jobs[:jobIdx+1] {
+		if JobTask(j.Kind) == task {
+			remaining += max(j.Work-j.Progress, 0)
+		}
+	}
+	work := TaskWork(village, task)
+	return (remaining + work - 1) / work
+}
+
+// WorkVillageJobs advances the active crafting and building jobs by a tick
+// of villager work, finishing any that are done, and returns a line per
+// finished job.
+func WorkVillageJobs(player *models.Character, village *models.Village) []string {
+	lines := []string{}
+	for _, task := range []string{TaskCrafting, TaskBuilding} {
+		idx := ActiveJob(village, task)
+		if idx < 0 {
+			continue
+		}
+		village.Jobs[idx].Progress += TaskWork(village, task)
+		for i := range village.Villagers {
+			if v := &village.Villagers[i]; v.AssignedTask == task && v.InjuredTicks == 0 {
+				GainVillagerXP(v, JobXP)
+			}
+		}
+		if village.Jobs[idx].Progress < village.Jobs[idx].Work {
+			continue
+		}
+		job := village.Jobs[idx]
+		village.Jobs = append(village.Jobs[:idx], village.Jobs[idx+1:]...)
+		lines = append(lines, finishJob(player, village, job))
+	}
+	return lines
+}
+
+// finishJob completes a job and describes the result.
+func finishJob(player *models.Character, village *models.Village, job models.VillageJob) string {
+	switch job.Kind {
+	case JobBuild:
+		bp, _ := FindDefenseBlueprint(job.Defense)
+		village.Defenses = append(village.Defenses, NewDefense(bp))
+		village.DefenseLevel++
+		village.Experience += 30
+		return fmt.Sprintf("Your builders finished the %s! (+30 Village XP)", bp.Name)
+
+	case JobUpgrade:
+		if job.DefenseIdx >= len(village.Defenses) || village.Defenses[job.DefenseIdx].Name != job.Defense {
+			return fmt.Sprintf("The %s was lost before its upgrade was finished.", job.Defense)
+		}
+		bp, _ := FindDefenseBlueprint(job.Defense)
+		d := &village.Defenses[job.DefenseIdx]
+		UpgradeDefense(d, bp)
+		village.Experience += 20
+		return fmt.Sprintf("Your builders raised the %s to level %d! (+20 Village XP)", d.Name, d.Level)
+
+	case JobConstruct:
+		return raiseBuilding(village, job.Building)
+	}
+
+	r, ok := FindRecipe(job.RecipeID)
+	if !ok {
+		return fmt.Sprintf("The recipe for %s has been lost.", job.Name)
+	}
+	if job.SkillName != "" {
+		idx := findLearnedSkill(player, job.SkillName)
+		if idx < 0 {
+			return fmt.Sprintf("You no longer know %s, so the upgrade was wasted.", job.SkillName)
+		}
+		ApplySkillUpgrade(&player.LearnedSkills[idx], r.SkillUpgrade)
+		village.Experience += r.VillageXP
+		return fmt.Sprintf("Your crafters finished %s! (+%d Village XP)", job.Name, r.VillageXP)
+	}
+
+	result := finishRecipe(player, village, r)
+	switch {
+	case result.Skill != nil:
+		return fmt.Sprintf("Your crafters finished a %s: you know %s! (+%d Village XP)", r.Name, result.Skill.Name, r.VillageXP)
+	case result.Trap != nil:
+		return fmt.Sprintf("Your crafters set a %s %s (damage %d). (+%d Village XP)", result.Quality.Name, r.Name, result.Trap.Damage, r.VillageXP)
+	case result.Stashed:
+		return fmt.Sprintf("Your crafters finished %s; your pack was full, so it is in the stash. (+%d Village XP)", result.Item.Name, r.VillageXP)
+	case !result.Stored:
+		return fmt.Sprintf("Your crafters finished %s, but there was nowhere to keep it. (+%d Village XP)", result.Item.Name, r.VillageXP)
+	case result.Item.ItemType != "equipment":
+		return fmt.Sprintf("Your crafters finished %s! (+%d Village XP)", result.Item.Name, r.VillageXP)
+	}
+	return fmt.Sprintf("Your crafters finished a %s %s: %s! (+%d Village XP)", result.Quality.Name, r.Name, result.Item.Name, r.VillageXP)
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+// fundCost gives the player a resource cost.
+func fundCost(player *models.Character, cost map[string]int) {
+	for name, n := range cost {
+		AdjustResource(player, name, n, ReasonMonsterDrop, "test")
+	}
+}
+
+func TestVillagerWork(t *testing.T) {
+	harvester := models.Villager{Name: "Hal", Role: "harvester", Level: 2, Efficiency: 4, HarvestType: "Iron"}
+	crafter := models.Villager{Name: "Cora", Role: "crafter", Level: 2, Efficiency: 4}
+	guard := models.Villager{Name: "Gus", Role: "guard", Level: 2, Efficiency: 4}
+
+	if VillagerWork(harvester, TaskCrafting) != 5 || VillagerWork(crafter, TaskCrafting) != 10 || VillagerWork(crafter, TaskBuilding) != 5 {
+		t.Error("specialists should work twice as fast on their own task")
+	}
+	if CanTakeTask(guard, TaskBuilding) || CanTakeTask(crafter, TaskHarvesting) || !CanTakeTask(harvester, TaskBuilding) {
+		t.Error("guards take no tasks and crafters don't harvest")
+	}
+	if err := AssignVillagerTask(&harvester, TaskCrafting); err != nil || harvester.HarvestType != "" {
+		t.Errorf("assigning a job task should stop harvesting, got %+v err=%v", harvester, err)
+	}
+
+	village := models.Village{}
+	if TaskWork(&village, TaskCrafting) != UnstaffedJobWork {
+		t.Error("an unstaffed task should still creep along")
+	}
+	village.Villagers = []models.Villager{harvester, crafter}
+	crafter.AssignedTask = TaskCrafting
+	village.Villagers[1] = crafter
+	if TaskWork(&village, TaskCrafting) != 15 {
+		t.Errorf("expected 15 crafting work, got %d", TaskWork(&village, TaskCrafting))
+	}
+}
+
+func TestQueueCraftFinishesOnTicks(t *testing.T) {
+	player := GenerateCharacter("Brewer", 1, 1)
+	village := craftingVillage(3)
+	village.Villagers = []models.Villager{{Name: "Cora", Role: "crafter", Level: 1, Efficiency: 5, AssignedTask: TaskCrafting}}
+	r, _ := FindRecipe("small_potion")
+	fund(&player, r)
+	iron := ResourceBalance(&player, "Iron")
+	items := len(player.Inventory)
+
+	job, err := QueueCraft(&player, &village, r, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ResourceBalance(&player, "Iron") != iron-r.RequiredResources["Iron"] || village.Experience != 0 || job.Work != r.VillageXP {
+		t.Errorf("queueing should charge up front and grant nothing yet, got job %+v", job)
+	}
+
+	if lines := WorkVillageJobs(&player, &village); len(lines) != 0 || village.Jobs[0].Progress != 10 {
+		t.Fatalf("expected 10 work done, got %+v", village.Jobs)
+	}
+	if lines := WorkVillageJobs(&player, &village); len(lines) != 1 {
+		t.Fatalf("expected the potion finished, got %v", lines)
+	}
+	if len(village.Jobs) != 0 || village.Experience != r.VillageXP || len(player.Inventory) <= items {
+		t.Error("a finished job should deliver the potion and its village XP")
+	}
+}
+
+func TestCancelJobRefund(t *testing.T) {
+	player := GenerateCharacter("Mason", 1, 1)
+	village := craftingVillage(3)
+	bp, _ := FindDefenseBlueprint("Wooden Wall")
+	fundCost(&player, bp.Cost)
+	lumber := ResourceBalance(&player, "Lumber")
+
+	job, err := QueueDefense(&player, &village, bp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	village.Jobs[0].Progress = job.Work / 2
+	refund, err := CancelJob(&player, &village, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Half the work is left and half of that is refunded.
+	want := bp.Cost["Lumber"] * 50 * (job.Work - job.Work/2) / (100 * job.Work)
+	if refund["Lumber"] != want || ResourceBalance(&player, "Lumber") != lumber-bp.Cost["Lumber"]+want {
+		t.Errorf("expected %d lumber back, got %v", want, refund)
+	}
+	if len(village.Jobs) != 0 || len(village.Defenses) != 0 {
+		t.Error("a cancelled job should be gone without building anything")
+	}
+	if _, err := CancelJob(&player, &village, job.ID); err == nil {
+		t.Error("cancelling twice should fail")
+	}
+}
+
+func TestQueueLimit(t *testing.T) {
+	player := GenerateCharacter("Mason", 1, 1)
+	village := craftingVillage(3)
+	bp, _ := FindDefenseBlueprint("Wooden Wall")
+	for i := 0; i < MaxVillageJobs; i++ {
+		fundCost(&player, bp.Cost)
+		if _, err := QueueDefense(&player, &village, bp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fundCost(&player, bp.Cost)
+	if _, err := QueueDefense(&player, &village, bp); err == nil {
+		t.Error("a full queue should refuse more jobs")
+	}
+}
+
+func TestDefenseUpgradeJob(t *testing.T) {
+	player := GenerateCharacter("Mason", 1, 1)
+	village := craftingVillage(3)
+	village.Villagers = []models.Villager{{Name: "Bo", Role: "builder", Level: 10, Efficiency: 20, AssignedTask: TaskBuilding}}
+	bp, _ := FindDefenseBlueprint("Guard Tower")
+	fundCost(&player, bp.Cost)
+	if err := BuildDefense(&player, &village, bp); err != nil {
+		t.Fatal(err)
+	}
+
+	for level := 1; level < 3; level++ {
+		fundCost(&player, DefenseUpgradeCost(bp, level))
+		if _, err := QueueDefenseUpgrade(&player, &village, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if PlannedDefenseLevel(&village, 0) != 3 {
+		t.Errorf("expected level 3 planned, got %d", PlannedDefenseLevel(&village, 0))
+	}
+	for i := 0; i < 10 && len(village.Jobs) > 0; i++ {
+		WorkVillageJobs(&player, &village)
+	}
+	d := village.Defenses[0]
+	if len(village.Jobs) != 0 || d.Level != 3 || d.Defense != bp.Defense+2*(bp.Defense/2) || d.AttackPower != bp.Attack+2*(bp.Attack/2) {
+		t.Errorf("expected a level 3 tower, got %+v", d)
+	}
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"time"
+
+	"rpg-game/pkg/models"
+)
+
+// Ledger entry kinds.
+const (
+	LedgerKindResource = "resource"
+	LedgerKindItem     = "item"
+)
+
+// Ledger reasons. Positive gold deltas are economy sources and negative
+// deltas are sinks; the metrics economy view groups them by reason.
+const (
+	ReasonBeastMaterial    = "beast_material"
+	ReasonBounty           = "bounty"
+	ReasonBuilding         = "building"
+	ReasonCrafting         = "crafting"
+	ReasonDiscard          = "discard"
+	ReasonDungeonLoot      = "dungeon_loot"
+	ReasonDungeonTreasure  = "dungeon_treasure"
+	ReasonEnchant          = "enchant"
+	ReasonFetchQuest       = "fetch_quest"
+	ReasonFighterHire      = "fighter_hire"
+	ReasonGamble           = "gamble"
+	ReasonGambleWin        = "gamble_win"
+	ReasonGuardGift        = "guard_gift"
+	ReasonGuardHire        = "guard_hire"
+	ReasonGuildBank        = "guild_bank"
+	ReasonGuildFound       = "guild_found"
+	ReasonHarvest          = "harvest"
+	ReasonHealing          = "healing"
+	ReasonInnGuardHire     = "inn_guard_hire"
+	ReasonInnSleep         = "inn_sleep"
+	ReasonItemUsed         = "item_used"
+	ReasonJobRefund        = "job_refund"
+	ReasonMonsterDrop      = "monster_drop"
+	ReasonNPCQuest         = "npc_quest"
+	ReasonPvPTheft         = "pvp_theft"
+	ReasonRaidLoot         = "raid_loot"
+	ReasonRaidLoss         = "raid_loss"
+	ReasonRepair           = "repair"
+	ReasonRespec           = "respec"
+	ReasonSkillScroll      = "skill_scroll"
+	ReasonStash            = "stash"
+	ReasonTax              = "tax"
+	ReasonTideLeaderReward = "tide_leader_reward"
+	ReasonTideLoss         = "tide_loss"
+	ReasonTideVictory      = "tide_victory"
+	ReasonTraining         = "training"
+	ReasonUpgrade          = "upgrade"
+	ReasonUpkeep           = "upkeep"
+)
+
+// ResourceBalance returns the current stock of a resource, 0 if absent.
+func ResourceBalance(player *models.Character, name string) int {
+	if player.ResourceStorageMap == nil {
+		return 0
+	}
+	return player.ResourceStorageMap[name].Stock
+}
+
+// GoldBalance returns the player's current gold.
+func GoldBalance(player *models.Character) int {
+	return ResourceBalance(player, "Gold")
+}
+
+// AdjustResource changes a resource stock by delta and records a ledger
+// entry. The stock never drops below zero; the recorded delta is the amount
+// actually applied. Returns the new balance.
+func AdjustResource(player *models.Character, name string, delta int, reason, ref string) int {
+	if player.ResourceStorageMap == nil {
+		player.ResourceStorageMap = make(map[string]models.Resource)
+	}
+	res := player.ResourceStorageMap[name]
+	res.Name = name
+	if res.Stock+delta < 0 {
+		delta = -res.Stock
+	}
+	res.Stock += delta
+	player.ResourceStorageMap[name] = res
+	if delta != 0 {
+		recordLedger(player, LedgerKindResource, name, delta, res.Stock, reason, ref)
+	}
+	return res.Stock
+}
+
+// AdjustGold is AdjustResource for gold.
+func AdjustGold(player *models.Character, delta int, reason, ref string) int {
+	return AdjustResource(player, "Gold", delta, reason, ref)
+}
+
+// RecordItemChange records an item entering (delta > 0) or leaving
+// (delta < 0) a character's possession. The caller moves the item itself.
+func RecordItemChange(player *models.Character, itemName string, delta int, reason, ref string) {
+	if delta == 0 {
+		return
+	}
+	recordLedger(player, LedgerKindItem, itemName, delta, 0, reason, ref)
+}
+
+// DrainLedger returns and clears the character's buffered ledger entries.
+func DrainLedger(player *models.Character) []models.LedgerEntry {
+	entries := player.PendingLedger
+	player.PendingLedger = nil
+	return entries
+}
+
+func recordLedger(player *models.Character, kind, asset string, delta, balance int, reason, ref string) {
+	player.PendingLedger = append(player.PendingLedger, models.LedgerEntry{
+		Character: player.Name,
+		Kind:      kind,
+		Asset:     asset,
+		Delta:     delta,
+		Balance:   balance,
+		Reason:    reason,
+		SourceRef: ref,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// RecordResourceChange records a resource change that the caller has already
+// applied to ResourceStorageMap.
+func RecordResourceChange(player *models.Character, name string, delta int, reason, ref string) {
+	if delta == 0 {
+		return
+	}
+	recordLedger(player, LedgerKindResource, name, delta, ResourceBalance(player, name), reason, ref)
+}
+
+// LootItem gives an item to the player, equipping it if it beats the current
+// gear, and records it in the ledger. It returns false, leaving the item
+// behind, when a non-equipment item does not fit in the inventory. Gear
+// displaced by an upgrade is always kept.
+func LootItem(player *models.Character, item models.Item, reason, ref string) bool {
+	if item.ItemType != "equipment" && !HasRoomFor(player.Inventory, InventoryCapacity(player), item) {
+		return false
+	}
+	EquipBestItem(item, &player.EquipmentMap, &player.Inventory)
+	RecordItemChange(player, item.Name, StackSize(item), reason, ref)
+	return true
+}
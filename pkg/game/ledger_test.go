@@ -0,0 +1,68 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestAdjustGoldRecordsLedger(t *testing.T) {
+	player := &models.Character{Name: "Hero"}
+
+	if got := AdjustGold(player, 50, ReasonDungeonTreasure, "dungeon:Crypt:1"); got != 50 {
+		t.Fatalf("balance after credit = %d, want 50", got)
+	}
+	if got := AdjustGold(player, -20, ReasonInnSleep, "town:Ravenhold"); got != 30 {
+		t.Fatalf("balance after debit = %d, want 30", got)
+	}
+	if GoldBalance(player) != 30 {
+		t.Errorf("GoldBalance = %d, want 30", GoldBalance(player))
+	}
+
+	entries := DrainLedger(player)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 ledger entries, got %d", len(entries))
+	}
+	if entries[1].Delta != -20 || entries[1].Balance != 30 || entries[1].Reason != ReasonInnSleep {
+		t.Errorf("unexpected debit entry: %+v", entries[1])
+	}
+	if entries[0].Character != "Hero" || entries[0].Kind != LedgerKindResource {
+		t.Errorf("unexpected credit entry: %+v", entries[0])
+	}
+	if len(player.PendingLedger) != 0 {
+		t.Error("DrainLedger should clear pending entries")
+	}
+}
+
+func TestAdjustResourceClampsAtZero(t *testing.T) {
+	player := &models.Character{
+		Name:               "Hero",
+		ResourceStorageMap: map[string]models.Resource{"Iron": {Name: "Iron", Stock: 3}},
+	}
+
+	if got := AdjustResource(player, "Iron", -10, ReasonTideLoss, ""); got != 0 {
+		t.Fatalf("balance = %d, want 0", got)
+	}
+	entries := DrainLedger(player)
+	if len(entries) != 1 || entries[0].Delta != -3 {
+		t.Fatalf("expected a single -3 entry, got %+v", entries)
+	}
+
+	// A no-op change records nothing.
+	AdjustResource(player, "Iron", -5, ReasonTideLoss, "")
+	if len(player.PendingLedger) != 0 {
+		t.Errorf("expected no entry for a zero delta, got %+v", player.PendingLedger)
+	}
+}
+
+func TestLootItemRecordsLedger(t *testing.T) {
+	player := &models.Character{Name: "Hero", EquipmentMap: map[int]models.Item{}}
+	item := GenerateItem(2)
+
+	LootItem(player, item, ReasonMonsterDrop, "monster:Goblin")
+
+	entries := DrainLedger(player)
+	if len(entries) != 1 || entries[0].Kind != LedgerKindItem || entries[0].Asset != item.Name || entries[0].Delta != 1 {
+		t.Errorf("unexpected item entry: %+v", entries)
+	}
+}
@@ -178,6 +178,7 @@ func GenerateLocationGuardian(locationName string, loc models.Location, gs *mode
 	}
 	guardian := GenerateBestMonster(gs, levelMax, rarityMax)
 	guardian.IsBoss = true
+	guardian.BossID = "location_guardian"
 	guardian.Name = "Guardian of " + locationName
 	guardian.HitpointsNatural = int(float64(guardian.HitpointsNatural) * 1.5)
 	guardian.HitpointsTotal = int(float64(guardian.HitpointsTotal) * 1.5)
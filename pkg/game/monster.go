@@ -146,13 +146,13 @@ var categorySkillPools = map[string][]levelSkill{
 		{2, models.Skill{Name: "Pounce", StaminaCost: 10, Damage: 14, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "none"}, Description: "Leaps onto prey with savage force"}},
 		{4, models.Skill{Name: "Frenzy", StaminaCost: 18, Damage: 20, DamageType: models.Physical,
-			Effect: models.StatusEffect{Type: "none"}, Description: "A whirlwind of claws and fangs"}},
+			Effect: models.StatusEffect{Type: "bleed", Duration: 3, Potency: 3}, Description: "A whirlwind of claws and fangs"}},
 		{6, models.Skill{Name: "Venomous Bite", StaminaCost: 12, Damage: 10, DamageType: models.Poison,
 			Effect: models.StatusEffect{Type: "poison", Duration: 3, Potency: 4}, Description: "Sinks envenomed fangs deep"}},
 	},
 	"undead": {
 		{2, models.Skill{Name: "Life Drain", ManaCost: 10, Damage: 12, DamageType: models.Lightning,
-			Effect: models.StatusEffect{Type: "none"}, Description: "Siphons the essence of the living"}},
+			Effect: models.StatusEffect{Type: "weaken", Duration: 3, Potency: 4}, Description: "Siphons the essence of the living"}},
 		{4, models.Skill{Name: "Bone Shards", ManaCost: 8, Damage: 16, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "none"}, Description: "Launches jagged bone fragments"}},
 		{7, models.Skill{Name: "Terrify", ManaCost: 15, Damage: 5, DamageType: models.Lightning,
@@ -162,7 +162,7 @@ var categorySkillPools = map[string][]levelSkill{
 		{2, models.Skill{Name: "Elemental Blast", ManaCost: 8, Damage: 14, DamageType: models.Fire,
 			Effect: models.StatusEffect{Type: "burn", Duration: 2, Potency: 3}, Description: "Hurls a bolt of raw elemental energy"}},
 		{4, models.Skill{Name: "Elemental Shield", ManaCost: 12, Damage: 0, DamageType: models.Physical,
-			Effect: models.StatusEffect{Type: "buff_defense", Duration: 3, Potency: 12}, Description: "Wraps in a shell of elemental force"}},
+			Effect: models.StatusEffect{Type: "shield", Duration: 3, Potency: 15}, Description: "Wraps in a shell of elemental force"}},
 		{7, models.Skill{Name: "Eruption", ManaCost: 18, Damage: 22, DamageType: models.Fire,
 			Effect: models.StatusEffect{Type: "burn", Duration: 2, Potency: 4}, Description: "The ground splits with elemental fury"}},
 	},
@@ -199,12 +199,14 @@ var categorySkillPools = map[string][]levelSkill{
 			Effect: models.StatusEffect{Type: "stun", Duration: 1, Potency: 1}, Description: "Beguiles the mind with fey glamour"}},
 		{4, models.Skill{Name: "Nature's Wrath", ManaCost: 12, Damage: 12, DamageType: models.Poison,
 			Effect: models.StatusEffect{Type: "poison", Duration: 3, Potency: 3}, Description: "The wild strikes back"}},
+		{3, models.Skill{Name: "Quicken", ManaCost: 10, Damage: 0, DamageType: models.Physical,
+			Effect: models.StatusEffect{Type: "haste", Duration: 3, Potency: 5}, Description: "Moves with the swiftness of the wind"}},
 		{6, models.Skill{Name: "Healing Light", ManaCost: 14, Damage: -20, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "regen", Duration: 3, Potency: 4}, Description: "Bathes in restorative moonlight"}},
 	},
 	"aberration": {
 		{2, models.Skill{Name: "Psychic Blast", ManaCost: 12, Damage: 16, DamageType: models.Lightning,
-			Effect: models.StatusEffect{Type: "none"}, Description: "A searing wave of psychic energy"}},
+			Effect: models.StatusEffect{Type: "silence", Duration: 2, Potency: 1}, Description: "A searing wave of psychic energy"}},
 		{4, models.Skill{Name: "Tentacle Slam", StaminaCost: 10, Damage: 14, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "none"}, Description: "Lashes out with writhing appendages"}},
 		{7, models.Skill{Name: "Mind Shatter", ManaCost: 18, Damage: 12, DamageType: models.Lightning,
@@ -212,7 +214,7 @@ var categorySkillPools = map[string][]levelSkill{
 	},
 	"humanoid": {
 		{2, models.Skill{Name: "Cleave", StaminaCost: 10, Damage: 14, DamageType: models.Physical,
-			Effect: models.StatusEffect{Type: "none"}, Description: "A broad sweeping strike"}},
+			Effect: models.StatusEffect{Type: "bleed", Duration: 3, Potency: 2}, Description: "A broad sweeping strike"}},
 		{4, models.Skill{Name: "Shield Bash", StaminaCost: 12, Damage: 8, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "stun", Duration: 1, Potency: 1}, Description: "Rams with a heavy shield"}},
 		{6, models.Skill{Name: "War Cry", StaminaCost: 15, Damage: 0, DamageType: models.Physical,
@@ -225,6 +227,8 @@ var categorySkillPools = map[string][]levelSkill{
 			Effect: models.StatusEffect{Type: "stun", Duration: 1, Potency: 1}, Description: "Vines and tendrils bind the target"}},
 		{6, models.Skill{Name: "Spore Cloud", ManaCost: 14, Damage: 8, DamageType: models.Poison,
 			Effect: models.StatusEffect{Type: "poison", Duration: 4, Potency: 5}, Description: "Releases a choking cloud of toxic spores"}},
+		{5, models.Skill{Name: "Grasping Roots", ManaCost: 8, Damage: 6, DamageType: models.Physical,
+			Effect: models.StatusEffect{Type: "slow", Duration: 3, Potency: 4}, Description: "Roots coil around the legs, dragging at every step"}},
 		{3, models.Skill{Name: "Regenerate", ManaCost: 10, Damage: 0, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "regen", Duration: 4, Potency: 5}, Description: "Regrows damaged tissue rapidly"}},
 	},
@@ -284,7 +288,7 @@ var monsterSkillOverrides = map[string][]levelSkill{
 	},
 	"frost wyrm": {
 		{2, models.Skill{Name: "Frost Breath", ManaCost: 14, Damage: 20, DamageType: models.Ice,
-			Effect: models.StatusEffect{Type: "stun", Duration: 1, Potency: 1}, Description: "Exhales a blast of killing cold"}},
+			Effect: models.StatusEffect{Type: "freeze", Duration: 1, Potency: 4}, Description: "Exhales a blast of killing cold"}},
 		{5, models.Skill{Name: "Ice Armor", ManaCost: 12, Damage: 0, DamageType: models.Physical,
 			Effect: models.StatusEffect{Type: "buff_defense", Duration: 4, Potency: 14}, Description: "Encases in a shell of solid ice"}},
 	},
@@ -407,6 +411,7 @@ func GenerateMonster(name string, level int, rank int) models.Monster {
 		StatusEffects:      []models.StatusEffect{},
 		Resistances:        resistances,
 		MonsterType:        name,
+		Speed:              data.CategorySpeed[data.MonsterCategory[name]],
 	}
 	monster.EquipmentMap = map[int]models.Item{}
 	monster.Inventory = []models.Item{}
@@ -497,6 +502,7 @@ func GenerateSkillGuardian(skill models.Skill, level int, rank int) models.Monst
 	}
 
 	baseMob.IsSkillGuardian = true
+	baseMob.BossID = "skill_guardian"
 	baseMob.GuardedSkill = skill
 	baseMob.MonsterType = "Guardian"
 	baseMob.Rarity = models.RarityLegendary
@@ -0,0 +1,194 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// Monster moves decided by DecideMonsterAction.
+const (
+	MonsterAttack = "attack"
+	MonsterSkill  = "skill"
+	MonsterFlee   = "flee"
+)
+
+// MonsterAction is a monster's move for one turn.
+type MonsterAction struct {
+	Kind     string
+	Skill    models.Skill    // the skill to use when Kind is MonsterSkill
+	Guard    int             // guard to strike instead of the foe, -1 for none
+	Messages []StatusMessage // lines to show before the move, such as enraging
+}
+
+// MonsterProfile is the AI profile mob fights with: its archetype's profile
+// with the rarity skill bonus and, for bosses, the boss overrides.
+func MonsterProfile(mob *models.Monster) models.AIProfile {
+	p, ok := data.MonsterAIProfiles[data.MonsterCategory[mob.MonsterType]]
+	if !ok {
+		p = data.DefaultAIProfile
+	}
+	p.SkillChance += data.RarityAISkillBonus[mob.Rarity]
+	switch mob.Rarity {
+	case models.RarityEpic, models.RarityLegendary, models.RarityMythic:
+		p.FleePct = 0
+	}
+	if mob.IsBoss {
+		p.FleePct = 0
+		p.EnragePct = max(p.EnragePct, data.BossAIProfile.EnragePct)
+		p.EnrageAttack = max(p.EnrageAttack, data.BossAIProfile.EnrageAttack)
+		p.HealPct = max(p.HealPct, data.BossAIProfile.HealPct)
+	}
+	return p
+}
+
+// hpPercent is how much of its health target has left, 0-100.
+func hpPercent(target interface{}) int {
+	t := statusTargetOf(target)
+	if t == nil || t.maxHP <= 0 {
+		return 100
+	}
+	return *t.hp * 100 / t.maxHP
+}
+
+// skillKind sorts a skill for the AI: "damage", "heal", "buff" (helps the
+// caster) or "debuff" (hinders the target).
+func skillKind(skill models.Skill) string {
+	switch {
+	case skill.Damage > 0:
+		return "damage"
+	case skill.Damage < 0:
+		return "heal"
+	case skill.Effect.Type == StatusCleanse:
+		return "buff"
+	case skill.Effect.Type == StatusDispel:
+		return "debuff"
+	}
+	if def, _ := FindStatus(skill.Effect.Type); def.Beneficial {
+		return "buff"
+	}
+	return "debuff"
+}
+
+// finisher is mob's hardest hitting skill.
+func finisher(mob *models.Monster) (models.Skill, bool) {
+	best, found := models.Skill{}, false
+	for _, s := range mob.LearnedSkills {
+		if s.Damage > best.Damage {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// ChooseMonsterSkill picks the skill mob uses against foe on turn, if any,
+// following its AI profile: a hurt monster heals itself, a monster saving
+// for its finisher spends it once foe is low enough, a boss follows
+// data.BossRotation and anyone else rolls its skill chance, trying the kind of
+// skill it prefers first.
+func ChooseMonsterSkill(mob *models.Monster, foe interface{}, timers *SkillTimers, turn int) (models.Skill, bool) {
+	if len(mob.LearnedSkills) == 0 || IsSilenced(mob.StatusEffects) {
+		return models.Skill{}, false
+	}
+	p := MonsterProfile(mob)
+	finish, hasFinisher := finisher(mob)
+	saving := hasFinisher && p.FinisherPct > 0 && hpPercent(foe) >= p.FinisherPct
+
+	byKind := map[string][]models.Skill{}
+	for _, s := range mob.LearnedSkills {
+		if s.ManaCost > mob.ManaRemaining || s.StaminaCost > mob.StaminaRemaining || !timers.Ready(s, turn) {
+			continue
+		}
+		if saving && (s.Name == finish.Name || mob.ManaRemaining-s.ManaCost < finish.ManaCost) {
+			continue
+		}
+		byKind[skillKind(s)] = append(byKind[skillKind(s)], s)
+	}
+	pick := func(kinds ...string) (models.Skill, bool) {
+		for _, kind := range kinds {
+			if skills := byKind[kind]; len(skills) > 0 {
+				return skills[rand.Intn(len(skills))], true
+			}
+		}
+		return models.Skill{}, false
+	}
+	hurt := p.HealPct > 0 && hpPercent(mob) < p.HealPct
+
+	if hurt {
+		if s, ok := pick("heal"); ok {
+			return s, true
+		}
+	}
+	if hasFinisher && p.FinisherPct > 0 && !saving && finish.ManaCost <= mob.ManaRemaining &&
+		finish.StaminaCost <= mob.StaminaRemaining && timers.Ready(finish, turn) {
+		return finish, true
+	}
+
+	if mob.IsBoss && len(data.BossRotation) > 0 {
+		switch step := data.BossRotation[turn%len(data.BossRotation)]; step {
+		case "finisher":
+			return pick("damage")
+		case "heal":
+			if hurt {
+				return pick("heal", "buff")
+			}
+			return models.Skill{}, false
+		case "attack":
+			return models.Skill{}, false
+		default:
+			return pick(step)
+		}
+	}
+
+	if rand.Intn(100) >= p.SkillChance {
+		return models.Skill{}, false
+	}
+	if s, ok := pick(p.Prefers); ok {
+		return s, true
+	}
+	return pick("damage", "debuff", "buff")
+}
+
+// DecideMonsterAction works out mob's move against foe on turn from its AI
+// profile. Enraging happens here, on the first turn the monster drops below
+// its threshold. guards are the foe's guards, which some archetypes strike
+// instead of the foe.
+func DecideMonsterAction(mob *models.Monster, foe interface{}, guards []models.Guard, timers *SkillTimers, turn int) MonsterAction {
+	act := MonsterAction{Kind: MonsterAttack, Guard: -1}
+	p := MonsterProfile(mob)
+	hp := hpPercent(mob)
+
+	if p.EnragePct > 0 && hp < p.EnragePct && !hasStatus(mob, "enrage") {
+		if _, ok := ApplyStatus(mob, models.StatusEffect{Type: "enrage", Duration: 999, Potency: p.EnrageAttack}); ok {
+			act.Messages = append(act.Messages, StatusMessage{fmt.Sprintf("%s flies into a rage! (+%d attack)", mob.Name, p.EnrageAttack), "buff"})
+		}
+	}
+	if p.FleePct > 0 && hp < p.FleePct && rand.Intn(100) < data.MonsterFleeChance {
+		act.Kind = MonsterFlee
+		return act
+	}
+	if skill, ok := ChooseMonsterSkill(mob, foe, timers, turn); ok {
+		act.Kind, act.Skill = MonsterSkill, skill
+	}
+	if p.FocusGuards > 0 && (act.Kind == MonsterAttack || act.Skill.Damage > 0) && rand.Intn(100) < p.FocusGuards {
+		act.Guard = weakestGuard(guards)
+	}
+	return act
+}
+
+// weakestGuard is the index of the standing guard with the least health, or
+// -1 when none is standing.
+func weakestGuard(guards []models.Guard) int {
+	weakest := -1
+	for i, g := range guards {
+		if g.Injured || g.HitpointsRemaining <= 0 {
+			continue
+		}
+		if weakest < 0 || g.HitpointsRemaining < guards[weakest].HitpointsRemaining {
+			weakest = i
+		}
+	}
+	return weakest
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+var (
+	testBolt   = models.Skill{Name: "Bolt", ManaCost: 5, Damage: 10, DamageType: models.Lightning}
+	testStorm  = models.Skill{Name: "Storm", ManaCost: 20, Damage: 40, DamageType: models.Lightning}
+	testMend   = models.Skill{Name: "Mend", ManaCost: 5, Damage: -15}
+	testWarcry = models.Skill{Name: "Warcry", ManaCost: 5, Effect: models.StatusEffect{Type: "buff_attack", Duration: 3, Potency: 2}}
+)
+
+func TestMonsterProfileByArchetypeAndRarity(t *testing.T) {
+	wolf := GenerateMonster("wolf", 5, 2)
+	wolf.Rarity = models.RarityCommon
+	if p := MonsterProfile(&wolf); p.Name != "Predator" || p.FleePct == 0 {
+		t.Fatalf("a common beast should be a predator that flees, got %+v", p)
+	}
+	wolf.Rarity = models.RarityEpic
+	if p := MonsterProfile(&wolf); p.FleePct != 0 || p.SkillChance <= 30 {
+		t.Errorf("an epic beast should use more skills and never flee, got %+v", p)
+	}
+	wolf.IsBoss = true
+	if p := MonsterProfile(&wolf); p.EnragePct == 0 {
+		t.Error("bosses should enrage")
+	}
+}
+
+func TestBeastFleesOnlyWhenHurt(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	wolf := GenerateMonster("wolf", 5, 2)
+	wolf.Rarity = models.RarityCommon
+	for i := 0; i < 50; i++ {
+		if act := DecideMonsterAction(&wolf, &player, nil, nil, i); act.Kind == MonsterFlee {
+			t.Fatal("a healthy wolf should not flee")
+		}
+	}
+	wolf.HitpointsTotal, wolf.HitpointsRemaining = 100, 5
+	fled := false
+	for i := 0; i < 50 && !fled; i++ {
+		fled = DecideMonsterAction(&wolf, &player, nil, nil, i).Kind == MonsterFlee
+	}
+	if !fled {
+		t.Error("a badly hurt wolf should try to flee")
+	}
+}
+
+func TestDemonEnragesOnce(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	imp := GenerateMonster("imp", 5, 2)
+	imp.LearnedSkills = nil
+	attack := AttackMod(&imp)
+	imp.HitpointsRemaining = imp.HitpointsTotal / 4
+
+	if act := DecideMonsterAction(&imp, &player, nil, nil, 1); len(act.Messages) != 1 {
+		t.Fatalf("the imp should enrage below 40%% HP, got %+v", act.Messages)
+	}
+	if AttackMod(&imp) != attack+4 {
+		t.Errorf("enrage should add 4 attack, %d -> %d", attack, AttackMod(&imp))
+	}
+	if act := DecideMonsterAction(&imp, &player, nil, nil, 2); len(act.Messages) != 0 {
+		t.Error("a monster should only enrage once")
+	}
+}
+
+func TestElementalSavesManaForFinisher(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	mob := GenerateMonster("storm elemental", 5, 2)
+	mob.Rarity = models.RarityCommon
+	mob.LearnedSkills = []models.Skill{testBolt, testStorm}
+	mob.ManaRemaining = 22
+
+	for i := 0; i < 30; i++ {
+		if skill, ok := ChooseMonsterSkill(&mob, &player, nil, i); ok {
+			t.Fatalf("with the foe healthy it should hold its mana for Storm, used %s", skill.Name)
+		}
+	}
+	player.HitpointsRemaining = player.HitpointsTotal / 5
+	if skill, ok := ChooseMonsterSkill(&mob, &player, nil, 1); !ok || skill.Name != "Storm" {
+		t.Errorf("with the foe low it should finish with Storm, got %q", skill.Name)
+	}
+}
+
+func TestHurtMonsterHealsItself(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	mob := GenerateMonster("imp", 5, 2)
+	mob.IsBoss = true
+	mob.LearnedSkills = []models.Skill{testBolt, testMend}
+	mob.ManaRemaining = 50
+	mob.HitpointsRemaining = mob.HitpointsTotal / 5
+
+	if skill, ok := ChooseMonsterSkill(&mob, &player, nil, 2); !ok || skill.Name != "Mend" {
+		t.Errorf("a badly hurt boss should heal, got %q", skill.Name)
+	}
+}
+
+func TestBossFollowsRotation(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	boss := GenerateMonster("wolf", 5, 2)
+	boss.IsBoss = true
+	boss.LearnedSkills = []models.Skill{testBolt, testWarcry}
+	boss.ManaRemaining = 50
+
+	if skill, ok := ChooseMonsterSkill(&boss, &player, nil, 0); !ok || skill.Name != "Warcry" {
+		t.Errorf("the rotation opens with a buff, got %q", skill.Name)
+	}
+	if skill, ok := ChooseMonsterSkill(&boss, &player, nil, 1); !ok || skill.Name != "Bolt" {
+		t.Errorf("the rotation follows with a damage skill, got %q", skill.Name)
+	}
+	if _, ok := ChooseMonsterSkill(&boss, &player, nil, 2); ok {
+		t.Error("the third step of the rotation is a plain attack")
+	}
+}
+
+func TestConstructFocusesWeakestGuard(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	golem := GenerateMonster("iron golem", 5, 2)
+	golem.LearnedSkills = nil
+	guards := []models.Guard{GenerateGuard(3), GenerateGuard(3), GenerateGuard(3)}
+	guards[0].HitpointsRemaining = 2
+	guards[0].Injured = true
+	guards[2].HitpointsRemaining = guards[1].HitpointsRemaining - 1
+
+	focused := false
+	for i := 0; i < 50 && !focused; i++ {
+		act := DecideMonsterAction(&golem, &player, guards, nil, i)
+		if act.Guard >= 0 {
+			focused = true
+			if act.Guard != 2 {
+				t.Fatalf("expected the weakest standing guard, got %d", act.Guard)
+			}
+		}
+	}
+	if !focused {
+		t.Error("a construct should go for the guards")
+	}
+}
@@ -212,14 +212,8 @@ func CompleteNPCQuest(player *models.Character, town *models.Town, questID strin
 	// Grant XP.
 	player.Experience += xp
 
-	// Grant Gold via the resource storage map.
-	if player.ResourceStorageMap == nil {
-		player.ResourceStorageMap = make(map[string]models.Resource)
-	}
-	goldRes := player.ResourceStorageMap["Gold"]
-	goldRes.Name = "Gold"
-	goldRes.Stock += gold
-	player.ResourceStorageMap["Gold"] = goldRes
+	// Grant Gold via the ledger.
+	AdjustGold(player, gold, ReasonNPCQuest, "npc_quest:"+questID)
 
 	// Track the quest on the player.
 	if player.CompletedNPCQuests == nil {
@@ -248,6 +242,13 @@ func CompleteNPCQuest(player *models.Character, town *models.Town, questID strin
 	msgs = append(msgs, fmt.Sprintf("Quest '%s' completed!", quest.Name))
 	msgs = append(msgs, fmt.Sprintf("  Reward: +%d XP, +%d Gold, +%d Reputation with %s", xp, gold, rep, quest.NPCName))
 
+	// Grateful townsfolk sometimes share a crafting recipe.
+	if rand.Intn(100) < QuestRecipeChance {
+		if r, ok := DiscoverRecipe(player); ok {
+			msgs = append(msgs, fmt.Sprintf("  %s also teaches you a recipe: %s!", quest.NPCName, r.Name))
+		}
+	}
+
 	return msgs, xp, gold, rep
 }
 
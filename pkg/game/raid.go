@@ -0,0 +1,223 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// Raid errors.
+var (
+	ErrNoRaiders    = errors.New("you have no hired guards fit to raid")
+	ErrRaidShielded = errors.New("that village is shielded from raids")
+	ErrRaidBand     = errors.New("that village is too far from your level to raid")
+	ErrNoRevenge    = errors.New("there is no raid there to avenge")
+)
+
+// RaidParty is one side of a raid: a player and their village.
+type RaidParty struct {
+	AccountID int64
+	Character *models.Character
+	Village   *models.Village
+}
+
+// RaidOutcome is what a raid did.
+type RaidOutcome struct {
+	Wave  models.SiegeWave    // the raid as fought on the defender's lanes
+	Entry models.RaidLogEntry // as logged by the attacker
+}
+
+// InRaidBand reports whether target is close enough to village's level to be
+// raided from it.
+func InRaidBand(village, target *models.Village) bool {
+	diff := village.Level - target.Level
+	return diff >= -data.RaidLevelBand && diff <= data.RaidLevelBand
+}
+
+// RaidShielded reports whether village is still shielded from raids at now.
+func RaidShielded(village *models.Village, now int64) bool {
+	return village.ShieldUntil > now
+}
+
+// Raiders are the hired guards of village fit to go raiding.
+func Raiders(village *models.Village) []*models.Guard {
+	raiders := []*models.Guard{}
+	for i := range village.ActiveGuards {
+		if siegeGuardReady(&village.ActiveGuards[i]) {
+			raiders = append(raiders, &village.ActiveGuards[i])
+		}
+	}
+	return raiders
+}
+
+// RaidLootPct is the share of each stored resource a raid takes when breached
+// raiders break in.
+func RaidLootPct(breached int, revenge bool) int {
+	if breached == 0 {
+		return 0
+	}
+	pct := breached * data.RaidLootPctPerBreach
+	if revenge {
+		pct += data.RaidRevengeLootPct
+	}
+	return min(pct, data.RaidMaxLootPct)
+}
+
+// Raid sends the attacker's ready hired guards against the defender's
+// village. The defender is fought as it stands, from a snapshot: its towers,
+// traps, walls and guards take no lasting harm, but every raider that breaks
+// in carries off a share of the defender's stored resources. Both villages
+// log the raid and the defender is shielded for a while; raiding drops the
+// attacker's own shield. A revenge raid ignores the level band and shield.
+func Raid(attacker, defender RaidParty, revenge bool, now int64) (RaidOutcome, error) {
+	raiders := Raiders(attacker.Village)
+	if len(raiders) == 0 {
+		return RaidOutcome{}, ErrNoRaiders
+	}
+	if !revenge && RaidShielded(defender.Village, now) {
+		return RaidOutcome{}, ErrRaidShielded
+	}
+	if !revenge && !InRaidBand(attacker.Village, defender.Village) {
+		return RaidOutcome{}, ErrRaidBand
+	}
+
+	snapshot := *defender.Village
+	snapshot.Traps = append([]models.Trap(nil), defender.Village.Traps...)
+	snapshot.ActiveGuards = append([]models.Guard(nil), defender.Village.ActiveGuards...)
+	wave := NewSiege(&snapshot).RunRaid(raiders)
+
+	entry := models.RaidLogEntry{
+		Time:            now,
+		Attacker:        attacker.Character.Name,
+		AttackerAccount: attacker.AccountID,
+		AttackerVillage: attacker.Village.Name,
+		Defender:        defender.Character.Name,
+		DefenderAccount: defender.AccountID,
+		DefenderVillage: defender.Village.Name,
+		Raiders:         len(raiders),
+		Breached:        wave.Breached,
+		Revenge:         revenge,
+	}
+
+	pct := RaidLootPct(wave.Breached, revenge)
+	if pct > 0 {
+		entry.Loot = map[string]int{}
+		for _, name := range sortedResourceNames(defender.Character) {
+			take := defender.Character.ResourceStorageMap[name].Stock * pct / 100
+			if take <= 0 {
+				continue
+			}
+			AdjustResource(defender.Character, name, -take, ReasonRaidLoss, "raid:"+attacker.Character.Name)
+			AdjustResource(attacker.Character, name, take, ReasonRaidLoot, "raid:"+defender.Character.Name)
+			entry.Loot[name] = take
+		}
+	}
+
+	defender.Village.ShieldUntil = now + data.RaidShieldSeconds
+	attacker.Village.ShieldUntil = 0
+
+	out := entry
+	out.Outgoing = true
+	AddRaidLog(attacker.Village, out)
+	AddRaidLog(defender.Village, entry)
+
+	return RaidOutcome{Wave: wave, Entry: out}, nil
+}
+
+// sortedResourceNames lists a character's stored resources by name, so a raid
+// loots them in a stable order.
+func sortedResourceNames(char *models.Character) []string {
+	names := make([]string, 0, len(char.ResourceStorageMap))
+	for name := range char.ResourceStorageMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddRaidLog puts entry at the top of village's raid log, dropping the oldest
+// past RaidLogSize.
+func AddRaidLog(village *models.Village, entry models.RaidLogEntry) {
+	village.RaidLog = append([]models.RaidLogEntry{entry}, village.RaidLog...)
+	if len(village.RaidLog) > data.RaidLogSize {
+		village.RaidLog = village.RaidLog[:data.RaidLogSize]
+	}
+}
+
+// CanRevenge reports whether entry is a raid on the village that hasn't been
+// answered yet. A revenge raid can't itself be avenged, so two villages
+// can't trade raids past each other's shields.
+func CanRevenge(entry models.RaidLogEntry) bool {
+	return !entry.Outgoing && !entry.Avenged && !entry.Revenge
+}
+
+// Revenge answers the raid at index i of the attacker's raid log with a
+// revenge raid on the defender, who made it.
+func Revenge(attacker, defender RaidParty, i int, now int64) (RaidOutcome, error) {
+	log := attacker.Village.RaidLog
+	if i < 0 || i >= len(log) || !CanRevenge(log[i]) {
+		return RaidOutcome{}, ErrNoRevenge
+	}
+	outcome, err := Raid(attacker, defender, true, now)
+	if err == nil && i+1 < len(attacker.Village.RaidLog) {
+		attacker.Village.RaidLog[i+1].Avenged = true // the new entry went on top
+	}
+	return outcome, err
+}
+
+// RaidTraces is what one village's raid log records about another account.
+type RaidTraces struct {
+	Village string                `json:"village"`
+	Entries []models.RaidLogEntry `json:"entries"`
+}
+
+// FindRaidTraces returns the raids in village's log the account took part
+// in.
+func FindRaidTraces(village *models.Village, accountID int64) []models.RaidLogEntry {
+	var entries []models.RaidLogEntry
+	for _, entry := range village.RaidLog {
+		if entry.AttackerAccount == accountID || entry.DefenderAccount == accountID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// ScrubRaidTraces removes the raids the account took part in from village's
+// raid log. Returns true if the log changed.
+func ScrubRaidTraces(village *models.Village, accountID int64) bool {
+	log := village.RaidLog[:0]
+	for _, entry := range village.RaidLog {
+		if entry.AttackerAccount != accountID && entry.DefenderAccount != accountID {
+			log = append(log, entry)
+		}
+	}
+	changed := len(log) != len(village.RaidLog)
+	village.RaidLog = log
+	return changed
+}
+
+// RaidLogLine describes a raid log entry from its village's side.
+func RaidLogLine(entry models.RaidLogEntry) string {
+	kind := "Raid"
+	if entry.Revenge {
+		kind = "Revenge raid"
+	}
+	loot := "nothing"
+	if len(entry.Loot) > 0 {
+		loot = MaterialsText(entry.Loot)
+	}
+	if entry.Outgoing {
+		return fmt.Sprintf("%s on %s's %s: %d/%d raiders broke in, took %s",
+			kind, entry.Defender, entry.DefenderVillage, entry.Breached, entry.Raiders, loot)
+	}
+	line := fmt.Sprintf("%s by %s of %s: %d/%d raiders broke in, lost %s",
+		kind, entry.Attacker, entry.AttackerVillage, entry.Breached, entry.Raiders, loot)
+	if entry.Avenged {
+		line += " (avenged)"
+	}
+	return line
+}
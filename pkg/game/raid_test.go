@@ -0,0 +1,159 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+func raidParty(account int64, name, village string, level int) RaidParty {
+	return RaidParty{
+		AccountID: account,
+		Character: &models.Character{Name: name, ResourceStorageMap: map[string]models.Resource{
+			"Wood": {Name: "Wood", Stock: 1000},
+			"Iron": {Name: "Iron", Stock: 200},
+		}},
+		Village: &models.Village{Name: village, Level: level},
+	}
+}
+
+func TestRaidLootsBreachedVillage(t *testing.T) {
+	attacker := raidParty(1, "Ada", "Adaburg", 5)
+	defender := raidParty(2, "Bo", "Boville", 5)
+	attacker.Village.ActiveGuards = []models.Guard{strongGuard("Brom"), strongGuard("Cade")}
+
+	outcome, err := Raid(attacker, defender, false, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.Wave.Breached != 2 {
+		t.Fatalf("%d of 2 raiders broke into an undefended village", outcome.Wave.Breached)
+	}
+	pct := RaidLootPct(2, false)
+	if got := defender.Character.ResourceStorageMap["Wood"].Stock; got != 1000-1000*pct/100 {
+		t.Errorf("defender has %d wood left", got)
+	}
+	if got := attacker.Character.ResourceStorageMap["Wood"].Stock; got != 1000+1000*pct/100 {
+		t.Errorf("attacker has %d wood", got)
+	}
+	if outcome.Entry.Loot["Iron"] != 200*pct/100 {
+		t.Errorf("entry records %d iron taken", outcome.Entry.Loot["Iron"])
+	}
+}
+
+func TestRaidShieldsAndLogsBothSides(t *testing.T) {
+	attacker := raidParty(1, "Ada", "Adaburg", 5)
+	defender := raidParty(2, "Bo", "Boville", 5)
+	attacker.Village.ActiveGuards = []models.Guard{strongGuard("Brom")}
+	attacker.Village.ShieldUntil = 5000
+
+	if _, err := Raid(attacker, defender, false, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if defender.Village.ShieldUntil != 1000+data.RaidShieldSeconds {
+		t.Errorf("defender shielded until %d", defender.Village.ShieldUntil)
+	}
+	if attacker.Village.ShieldUntil != 0 {
+		t.Error("raiding should drop the attacker's shield")
+	}
+	if len(attacker.Village.RaidLog) != 1 || !attacker.Village.RaidLog[0].Outgoing {
+		t.Error("attacker should log an outgoing raid")
+	}
+	if len(defender.Village.RaidLog) != 1 || !CanRevenge(defender.Village.RaidLog[0]) {
+		t.Error("defender should log a raid it can avenge")
+	}
+
+	if _, err := Raid(attacker, defender, false, 2000); err != ErrRaidShielded {
+		t.Errorf("raid on a shielded village: got %v", err)
+	}
+}
+
+func TestRaidRequiresBandAndRaiders(t *testing.T) {
+	attacker := raidParty(1, "Ada", "Adaburg", 5)
+	defender := raidParty(2, "Bo", "Boville", 5+data.RaidLevelBand+1)
+	if _, err := Raid(attacker, defender, false, 1000); err != ErrNoRaiders {
+		t.Errorf("raid without guards: got %v", err)
+	}
+	attacker.Village.ActiveGuards = []models.Guard{strongGuard("Brom")}
+	if _, err := Raid(attacker, defender, false, 1000); err != ErrRaidBand {
+		t.Errorf("raid out of band: got %v", err)
+	}
+}
+
+func TestRaidLootIsCapped(t *testing.T) {
+	if got := RaidLootPct(100, true); got != data.RaidMaxLootPct {
+		t.Errorf("loot share %d%%, want capped at %d%%", got, data.RaidMaxLootPct)
+	}
+	if got := RaidLootPct(0, true); got != 0 {
+		t.Errorf("a raid with no breaches took %d%%", got)
+	}
+}
+
+func TestRevengeBypassesShieldAndBand(t *testing.T) {
+	attacker := raidParty(1, "Ada", "Adaburg", 5)
+	defender := raidParty(2, "Bo", "Boville", 15)
+	defender.Village.ActiveGuards = []models.Guard{strongGuard("Brom")}
+	defender.Village.ShieldUntil = 9999
+	AddRaidLog(defender.Village, models.RaidLogEntry{Attacker: "Ada", AttackerAccount: 1, AttackerVillage: "Adaburg"})
+	attacker.Village.ShieldUntil = 9999
+
+	outcome, err := Revenge(defender, attacker, 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outcome.Entry.Revenge {
+		t.Error("entry should be marked as revenge")
+	}
+	if !defender.Village.RaidLog[1].Avenged || CanRevenge(defender.Village.RaidLog[1]) {
+		t.Error("the answered raid should be avenged")
+	}
+	if _, err := Revenge(defender, attacker, 1, 1000); err != ErrNoRevenge {
+		t.Errorf("second revenge: got %v", err)
+	}
+	if CanRevenge(attacker.Village.RaidLog[0]) {
+		t.Error("a revenge raid should not be avengeable")
+	}
+	if _, err := Revenge(attacker, defender, 0, 1000); err != ErrNoRevenge {
+		t.Errorf("revenge on a revenge raid: got %v", err)
+	}
+}
+
+func TestBeatenRaidersAreInjured(t *testing.T) {
+	attacker := raidParty(1, "Ada", "Adaburg", 5)
+	defender := raidParty(2, "Bo", "Boville", 5)
+	attacker.Village.ActiveGuards = []models.Guard{{Name: "Pip", Level: 1, HitPoints: 5, HitpointsRemaining: 5, AttackRolls: 1}}
+	defender.Village.ActiveGuards = []models.Guard{strongGuard("Brom"), strongGuard("Cade"), strongGuard("Dara")}
+
+	outcome, err := Raid(attacker, defender, false, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.Wave.Breached != 0 || len(outcome.Entry.Loot) != 0 {
+		t.Fatal("a weak raider got past three strong guards")
+	}
+	pip := attacker.Village.ActiveGuards[0]
+	if !pip.Injured || pip.HitpointsRemaining != 1 || pip.RecoveryTime != data.RaidRecoveryTime {
+		t.Errorf("beaten raider: injured=%v hp=%d recovery=%d", pip.Injured, pip.HitpointsRemaining, pip.RecoveryTime)
+	}
+	if defender.Village.ActiveGuards[0].HitpointsRemaining != 500 {
+		t.Error("a raid should not harm the defender's guards")
+	}
+}
+
+func TestScrubRaidTraces(t *testing.T) {
+	village := &models.Village{Name: "Boville", RaidLog: []models.RaidLogEntry{
+		{Attacker: "Ada", AttackerAccount: 1, Defender: "Bo", DefenderAccount: 2},
+		{Attacker: "Cy", AttackerAccount: 3, Defender: "Bo", DefenderAccount: 2},
+		{Attacker: "Bo", AttackerAccount: 2, Defender: "Ada", DefenderAccount: 1, Outgoing: true},
+	}}
+	if got := FindRaidTraces(village, 1); len(got) != 2 {
+		t.Fatalf("expected both of Ada's raids, got %+v", got)
+	}
+	if !ScrubRaidTraces(village, 1) || len(village.RaidLog) != 1 || village.RaidLog[0].Attacker != "Cy" {
+		t.Errorf("only Cy's raid should be left, got %+v", village.RaidLog)
+	}
+	if ScrubRaidTraces(village, 1) {
+		t.Error("second scrub should be a no-op")
+	}
+}
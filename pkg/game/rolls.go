@@ -3,6 +3,9 @@ package game
 import (
 	"math/rand"
 	"time"
+
+	"rpg-game/pkg/config"
+	"rpg-game/pkg/models"
 )
 
 func RollDice() int {
@@ -36,3 +39,16 @@ func RollUntilSix(minRolls int, minMod int) int {
 	}
 	return resultTotal
 }
+
+// RollPlayerCrit reports whether a player attack lands a critical hit,
+// including the player's crit talents, attributes and item effects.
+func RollPlayerCrit(player *models.Character) bool {
+	chance := config.Current().Balance.PlayerCritChance + TalentBonus(player, TalentCritChance) + AttributeCritBonus(player) +
+		player.StatsMod.Effects[EffectCritChance]
+	return rand.Intn(100) < chance
+}
+
+// RollMonsterCrit reports whether a monster attack lands a critical hit.
+func RollMonsterCrit() bool {
+	return rand.Intn(100) < config.Current().Balance.MonsterCritChance
+}
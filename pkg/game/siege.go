@@ -0,0 +1,488 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// Siege fights a monster tide out on lanes leading to a village. Monsters
+// advance tile by tile, towers shoot those in range, traps fire as monsters
+// cross them, hired guards hold their post and walls take the blows of
+// whatever reaches them. Nothing is rolled, so the same village always fights
+// the same battle.
+type Siege struct {
+	Village *models.Village
+	Report  models.SiegeReport
+
+	walls      []siegeWall
+	trapCharge []int
+	defended   [data.SiegeLanes]bool // lane had a wall or guard when the tide began
+}
+
+type siegeWall struct {
+	name string
+	lane int
+	hp   int
+}
+
+// siegeFoe is a monster on a lane.
+type siegeFoe struct {
+	name   string
+	lane   int
+	pos    int
+	hp     int
+	attack int
+	speed  int
+	spawn  int  // tick it enters the lane
+	guard  int  // index of the hired guard holding it, -1 when free
+	atWall bool // stopped by a standing wall
+	done   bool // killed, breached or withdrawn
+
+	raider *models.Guard // the guard this foe is in a raid, nil for monsters
+}
+
+// NewSiege lines up village's walls, traps and guards on the lanes. Walls,
+// traps, hired guards and guard villagers are dealt out to the lanes in turn.
+func NewSiege(village *models.Village) *Siege {
+	s := &Siege{
+		Village: village,
+		Report: models.SiegeReport{
+			Lanes:      data.SiegeLanes,
+			Kills:      map[string]int{},
+			WallDamage: map[string]int{},
+		},
+	}
+	for _, d := range village.Defenses {
+		if d.Built && d.Type == "wall" {
+			lane := len(s.walls) % data.SiegeLanes
+			s.walls = append(s.walls, siegeWall{name: d.Name, lane: lane, hp: d.Defense * data.SiegeWallHPPerDefense})
+			s.defended[lane] = true
+		}
+	}
+	for i, g := range village.ActiveGuards {
+		if siegeGuardReady(&g) {
+			s.defended[i%data.SiegeLanes] = true
+		}
+	}
+	s.trapCharge = make([]int, len(village.Traps))
+	for i := range s.trapCharge {
+		s.trapCharge[i] = data.SiegeTrapChargeStart
+	}
+	return s
+}
+
+// trapTile is the lane tile trap i is laid on.
+func trapTile(i int) int {
+	return data.SiegeTrapTiles[(i/data.SiegeLanes)%len(data.SiegeTrapTiles)]
+}
+
+func siegeGuardReady(g *models.Guard) bool {
+	return !g.Injured && g.HitpointsRemaining > 0
+}
+
+// siegeGuardDamage is a guard's blow in a siege: its average attack, scaled by
+// its stance, plus its weapons' elemental damage.
+func siegeGuardDamage(g *models.Guard) int {
+	atk := (g.AttackRolls*3 + g.AttackBonus + g.StatsMod.AttackMod) * FindGuardStance(g.Stance).AttackPct / 100
+	return max(atk, 1) + ElementalItemDamage(g.StatsMod.Effects)
+}
+
+func siegeGuardDefense(g *models.Guard) int {
+	return g.DefenseRolls*2 + g.DefenseBonus + g.StatsMod.DefenseMod
+}
+
+// siegeFoes lines up a wave of count monsters from the roster. Monster m of
+// the wave takes lane m%SiegeLanes and enters it on tick m/SiegeLanes+1.
+func (s *Siege) siegeFoes(number, count int) []*siegeFoe {
+	level := s.Village.Level
+	baseHP := 8 + level*4
+	baseAttack := 2 + level*3/2
+	foes := make([]*siegeFoe, count)
+	for m := range foes {
+		kind := data.SiegeRoster[(level+(number-1)*count+m)%len(data.SiegeRoster)]
+		foes[m] = &siegeFoe{
+			name:   fmt.Sprintf("Lv%d %s", level, kind.Name),
+			lane:   m % data.SiegeLanes,
+			pos:    data.SiegeLaneLength,
+			hp:     max(baseHP*kind.HPPct/100, 1),
+			attack: max(baseAttack*kind.AttackPct/100, 1),
+			speed:  kind.Speed,
+			spawn:  m/data.SiegeLanes + 1,
+			guard:  -1,
+		}
+	}
+	return foes
+}
+
+// RunWave fights wave number of the tide with count monsters, adds it to the
+// report and returns it.
+func (s *Siege) RunWave(number, count int) models.SiegeWave {
+	wave := models.SiegeWave{Number: number, Monsters: count}
+	s.fight(&wave, s.siegeFoes(number, count))
+	s.Report.Waves = append(s.Report.Waves, wave)
+	return wave
+}
+
+// RunRaid sends another player's guards down the lanes in place of monsters.
+// Raiders march at a fifth of their speed and strike with their stance's
+// blow; when the raid ends each keeps the health it has left, and one beaten
+// down is carried home injured.
+func (s *Siege) RunRaid(raiders []*models.Guard) models.SiegeWave {
+	wave := models.SiegeWave{Number: 1, Monsters: len(raiders)}
+	foes := make([]*siegeFoe, len(raiders))
+	for i, g := range raiders {
+		foes[i] = &siegeFoe{
+			name:   g.Name,
+			lane:   i % data.SiegeLanes,
+			pos:    data.SiegeLaneLength,
+			hp:     g.HitpointsRemaining,
+			attack: siegeGuardDamage(g),
+			speed:  max(Speed(g)/5, 1),
+			spawn:  i/data.SiegeLanes + 1,
+			guard:  -1,
+			raider: g,
+		}
+	}
+	s.fight(&wave, foes)
+	for _, f := range foes {
+		if f.hp > 0 {
+			f.raider.HitpointsRemaining = f.hp
+			continue
+		}
+		f.raider.HitpointsRemaining = 1
+		f.raider.Injured = true
+		f.raider.RecoveryTime = data.RaidRecoveryTime
+	}
+	s.Report.Waves = append(s.Report.Waves, wave)
+	return wave
+}
+
+// fight plays foes out tick by tick until none is left on the lanes.
+func (s *Siege) fight(wave *models.SiegeWave, foes []*siegeFoe) {
+	tick := 0
+	for tick < data.SiegeMaxTicks && !siegeOver(foes) {
+		tick++
+		s.towersFire(wave, tick, foes)
+		s.advance(wave, tick, foes)
+		s.guardsFight(wave, tick, foes)
+		s.wallsHold(wave, tick, foes)
+	}
+	for _, f := range foes {
+		if !f.done {
+			f.done = true
+			wave.Events = append(wave.Events, models.SiegeEvent{Tick: tick, Lane: f.lane + 1, Kind: "retreat",
+				Source: f.name, Text: fmt.Sprintf("%s withdraws from the walls", f.name)})
+		}
+	}
+	wave.Ticks = tick
+}
+
+func siegeOver(foes []*siegeFoe) bool {
+	for _, f := range foes {
+		if !f.done {
+			return false
+		}
+	}
+	return true
+}
+
+// strike deals dmg to f, logging text and crediting source with the kill.
+func (s *Siege) strike(wave *models.SiegeWave, tick int, f *siegeFoe, kind, source string, dmg int, text string) {
+	f.hp -= dmg
+	wave.DamageDealt += dmg
+	ev := models.SiegeEvent{Tick: tick, Lane: f.lane + 1, Kind: kind, Source: source, Target: f.name, Amount: dmg}
+	if f.hp <= 0 {
+		f.done = true
+		ev.Killed = true
+		wave.Killed++
+		s.Report.Kills[source]++
+		ev.Text = text + " - killed!"
+		if f.raider != nil {
+			ev.Text = text + " - beaten back!"
+		}
+	} else {
+		ev.Text = fmt.Sprintf("%s (%d HP left)", text, f.hp)
+	}
+	wave.Events = append(wave.Events, ev)
+}
+
+// towersFire has every defense with an attack shoot the nearest monster
+// within its range of the walls.
+func (s *Siege) towersFire(wave *models.SiegeWave, tick int, foes []*siegeFoe) {
+	for _, d := range s.Village.Defenses {
+		if !d.Built || d.AttackPower <= 0 {
+			continue
+		}
+		var target *siegeFoe
+		for _, f := range foes {
+			if f.done || tick < f.spawn || f.pos > d.Range {
+				continue
+			}
+			if target == nil || f.pos < target.pos {
+				target = f
+			}
+		}
+		if target == nil {
+			continue
+		}
+		dmg := d.AttackPower + d.Level
+		s.strike(wave, tick, target, "tower", d.Name, dmg,
+			fmt.Sprintf("%s shoots %s for %d dmg", d.Name, target.name, dmg))
+	}
+}
+
+// advance moves every free monster down its lane, springing the traps it
+// crosses and stopping it at a guard post or a standing wall. A monster
+// reaching the end of a lane with no wall left breaches the village.
+func (s *Siege) advance(wave *models.SiegeWave, tick int, foes []*siegeFoe) {
+	for _, f := range foes {
+		if f.done || tick < f.spawn || f.guard >= 0 || f.atWall {
+			continue
+		}
+		from, to := f.pos, f.pos-f.speed
+
+		for i := range s.Village.Traps {
+			trap := &s.Village.Traps[i]
+			tile := trapTile(i)
+			if i%data.SiegeLanes != f.lane || trap.Remaining <= 0 || tile >= from || tile < to {
+				continue
+			}
+			s.trapCharge[i] += trap.TriggerRate
+			if s.trapCharge[i] < 100 {
+				continue
+			}
+			s.trapCharge[i] -= 100
+			trap.Remaining--
+			s.strike(wave, tick, f, "trap", trap.Name, trap.Damage,
+				fmt.Sprintf("%s triggers %s for %d dmg", f.name, trap.Name, trap.Damage))
+			if f.done {
+				break
+			}
+		}
+		if f.done {
+			continue
+		}
+
+		if from >= data.SiegeGuardPost && to <= data.SiegeGuardPost {
+			if gi := s.laneGuard(f.lane); gi >= 0 {
+				f.pos, f.guard = data.SiegeGuardPost, gi
+				name := s.Village.ActiveGuards[gi].Name
+				wave.Events = append(wave.Events, models.SiegeEvent{Tick: tick, Lane: f.lane + 1, Kind: "guard",
+					Source: name, Target: f.name, Text: fmt.Sprintf("%s intercepts %s", name, f.name)})
+				continue
+			}
+		}
+
+		if to > 0 {
+			f.pos = to
+			continue
+		}
+		f.pos = 0
+		if s.laneWall(f.lane) >= 0 {
+			f.atWall = true
+			continue
+		}
+		f.done = true
+		wave.Breached++
+		wave.DamageTaken += f.attack
+		s.Report.BreachDamage += f.attack
+		text := fmt.Sprintf("%s breaks through the defenses! (%d breach dmg)", f.name, f.attack)
+		if !s.defended[f.lane] {
+			text = fmt.Sprintf("%s breaches undefended village! (%d breach dmg)", f.name, f.attack)
+		}
+		wave.Events = append(wave.Events, models.SiegeEvent{Tick: tick, Lane: f.lane + 1, Kind: "breach",
+			Source: f.name, Amount: f.attack, Text: text})
+	}
+}
+
+// laneGuard is the first hired guard still fighting on a lane, or -1.
+func (s *Siege) laneGuard(lane int) int {
+	for i := range s.Village.ActiveGuards {
+		if i%data.SiegeLanes == lane && siegeGuardReady(&s.Village.ActiveGuards[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// laneWall is the first wall still standing on a lane, or -1.
+func (s *Siege) laneWall(lane int) int {
+	for i, w := range s.walls {
+		if w.lane == lane && w.hp > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// guardsFight has each guard strike a monster it holds, picked by its stance,
+// and take the blows of all of them. A guard that falls lets them go.
+func (s *Siege) guardsFight(wave *models.SiegeWave, tick int, foes []*siegeFoe) {
+	for gi := range s.Village.ActiveGuards {
+		g := &s.Village.ActiveGuards[gi]
+		var held []*siegeFoe
+		for _, f := range foes {
+			if !f.done && f.guard == gi {
+				held = append(held, f)
+			}
+		}
+		if len(held) == 0 {
+			continue
+		}
+
+		target := held[0]
+		if FindGuardStance(g.Stance).Target == "weakest" {
+			for _, f := range held {
+				if f.hp < target.hp {
+					target = f
+				}
+			}
+		}
+		dmg := siegeGuardDamage(g)
+		s.strike(wave, tick, target, "guard", g.Name, dmg,
+			fmt.Sprintf("%s strikes %s for %d dmg", g.Name, target.name, dmg))
+
+		for _, f := range held {
+			if f.done {
+				continue
+			}
+			taken := max(f.attack-siegeGuardDefense(g), 1)
+			g.HitpointsRemaining -= taken
+			wave.DamageTaken += taken
+			ev := models.SiegeEvent{Tick: tick, Lane: f.lane + 1, Kind: "wound", Source: f.name, Target: g.Name, Amount: taken,
+				Text: fmt.Sprintf("%s hits %s for %d dmg (%d/%d HP)", f.name, g.Name, taken, max(g.HitpointsRemaining, 0), g.HitPoints)}
+			if g.HitpointsRemaining <= 0 {
+				g.Injured = true
+				ev.Text = fmt.Sprintf("%s hits %s for %d dmg - %s falls!", f.name, g.Name, taken, g.Name)
+			}
+			wave.Events = append(wave.Events, ev)
+			if g.Injured {
+				break
+			}
+		}
+		if g.Injured {
+			for _, f := range held {
+				f.guard = -1
+			}
+		}
+	}
+}
+
+// wallsHold has the guard villagers on each lane strike the first monster at
+// its wall, then every monster there batter the wall. A broken wall lets them
+// through on their next move unless another stands behind it.
+func (s *Siege) wallsHold(wave *models.SiegeWave, tick int, foes []*siegeFoe) {
+	militia := [data.SiegeLanes]int{}
+	n := 0
+	for _, v := range s.Village.Villagers {
+		if v.Role == "guard" && v.InjuredTicks == 0 {
+			militia[n%data.SiegeLanes] += data.SiegeMilitiaDamage + v.Level
+			n++
+		}
+	}
+
+	for lane := 0; lane < data.SiegeLanes; lane++ {
+		var atWall []*siegeFoe
+		for _, f := range foes {
+			if !f.done && f.atWall && f.lane == lane {
+				atWall = append(atWall, f)
+			}
+		}
+		if len(atWall) == 0 {
+			continue
+		}
+		if dmg := militia[lane]; dmg > 0 {
+			s.strike(wave, tick, atWall[0], "militia", "Militia", dmg,
+				fmt.Sprintf("Militia on the walls strike %s for %d dmg", atWall[0].name, dmg))
+		}
+
+		for _, f := range atWall {
+			wi := s.laneWall(lane)
+			if f.done || wi < 0 {
+				continue
+			}
+			w := &s.walls[wi]
+			dmg := min(f.attack, w.hp)
+			w.hp -= dmg
+			s.Report.WallDamage[w.name] += dmg
+			ev := models.SiegeEvent{Tick: tick, Lane: lane + 1, Kind: "wall", Source: f.name, Target: w.name, Amount: dmg,
+				Text: fmt.Sprintf("%s batters the %s for %d dmg (%d HP left)", f.name, w.name, dmg, w.hp)}
+			if w.hp <= 0 {
+				s.Report.WallsBroken = append(s.Report.WallsBroken, w.name)
+				ev.Text = fmt.Sprintf("%s batters the %s for %d dmg - the %s breaks!", f.name, w.name, dmg, w.name)
+			}
+			wave.Events = append(wave.Events, ev)
+		}
+		if s.laneWall(lane) < 0 {
+			for _, f := range atWall {
+				f.atWall = false
+			}
+		}
+	}
+}
+
+// SiegeEventLine is an event as a line of a tide log.
+func SiegeEventLine(ev models.SiegeEvent) string {
+	return fmt.Sprintf("  [T%d L%d] %s", ev.Tick, ev.Lane, ev.Text)
+}
+
+// SiegeCount is one entry of a siege report tally.
+type SiegeCount struct {
+	Name  string
+	Count int
+}
+
+// SiegeTally sorts a siege report tally, largest first.
+func SiegeTally(tally map[string]int) []SiegeCount {
+	counts := make([]SiegeCount, 0, len(tally))
+	for name, n := range tally {
+		counts = append(counts, SiegeCount{name, n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// SiegeSummary describes a siege report in a few lines: the outcome, what
+// killed the monsters and what the walls took.
+func SiegeSummary(report *models.SiegeReport) []string {
+	monsters, killed, breached := 0, 0, 0
+	for _, w := range report.Waves {
+		monsters += w.Monsters
+		killed += w.Killed
+		breached += w.Breached
+	}
+	outcome := "held"
+	if !report.Victory {
+		outcome = "fell"
+	}
+	lines := []string{fmt.Sprintf("Siege report: the village %s. %d/%d monsters killed, %d breached over %d waves (%d/%d breach dmg)",
+		outcome, killed, monsters, breached, len(report.Waves), report.BreachDamage, report.Threshold)}
+
+	if kills := SiegeTally(report.Kills); len(kills) > 0 {
+		parts := make([]string, len(kills))
+		for i, c := range kills {
+			parts[i] = fmt.Sprintf("%s %d", c.Name, c.Count)
+		}
+		lines = append(lines, "Siege kills: "+strings.Join(parts, ", "))
+	}
+	if walls := SiegeTally(report.WallDamage); len(walls) > 0 {
+		parts := make([]string, len(walls))
+		for i, c := range walls {
+			parts[i] = fmt.Sprintf("%s %d", c.Name, c.Count)
+			if Contains(report.WallsBroken, c.Name) {
+				parts[i] += " (broken)"
+			}
+		}
+		lines = append(lines, "Siege wall damage: "+strings.Join(parts, ", "))
+	}
+	return lines
+}
@@ -0,0 +1,174 @@
+package game
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func siegeVillage() models.Village {
+	return models.Village{Name: "Siegeton", Level: 5, DefenseLevel: 1, TideInterval: 3600}
+}
+
+func strongGuard(name string) models.Guard {
+	return models.Guard{Name: name, Level: 20, HitPoints: 500, HitpointsNatural: 500, HitpointsRemaining: 500,
+		AttackRolls: 5, AttackBonus: 80, DefenseRolls: 5, DefenseBonus: 50}
+}
+
+func TestSiegeIsDeterministic(t *testing.T) {
+	build := func() models.Village {
+		v := siegeVillage()
+		v.Defenses = []models.Defense{NewDefense(DefenseBlueprints[4]), NewDefense(DefenseBlueprints[0])}
+		v.Traps = []models.Trap{{Name: "Spike Trap", Damage: 15, Remaining: 3, TriggerRate: 60}}
+		v.ActiveGuards = []models.Guard{strongGuard("Brom")}
+		return v
+	}
+	a, b := build(), build()
+	sa, sb := NewSiege(&a), NewSiege(&b)
+	for wave := 1; wave <= 2; wave++ {
+		sa.RunWave(wave, 6)
+		sb.RunWave(wave, 6)
+	}
+	if !reflect.DeepEqual(sa.Report, sb.Report) {
+		t.Error("the same village fought two different sieges")
+	}
+}
+
+func TestSiegeUndefendedLanesBreach(t *testing.T) {
+	v := siegeVillage()
+	s := NewSiege(&v)
+	wave := s.RunWave(1, 4)
+
+	if wave.Breached != 4 || wave.Killed != 0 {
+		t.Fatalf("breached %d, killed %d; want every monster through", wave.Breached, wave.Killed)
+	}
+	if s.Report.BreachDamage == 0 {
+		t.Error("breaches dealt no damage")
+	}
+}
+
+func TestSiegeTowersFireWithinRange(t *testing.T) {
+	v := siegeVillage()
+	tower := NewDefense(DefenseBlueprints[4])
+	tower.Range = 0 // only reaches monsters at the walls, and there are none
+	v.Defenses = []models.Defense{tower}
+	s := NewSiege(&v)
+	s.RunWave(1, 3)
+	if s.Report.Kills[tower.Name] != 0 {
+		t.Fatalf("a tower with no range killed %d monsters", s.Report.Kills[tower.Name])
+	}
+
+	v = siegeVillage()
+	tower.Range = 10
+	v.Defenses = []models.Defense{tower}
+	s = NewSiege(&v)
+	s.RunWave(1, 3)
+	if s.Report.Kills[tower.Name] == 0 {
+		t.Error("a tower in range killed nothing")
+	}
+	for _, ev := range s.Report.Waves[0].Events {
+		if ev.Kind == "tower" && ev.Tick == 1 {
+			t.Errorf("tower fired at spawning monsters out of range: %s", ev.Text)
+		}
+	}
+}
+
+func TestSiegeTrapsTriggerOnTheirLane(t *testing.T) {
+	v := siegeVillage()
+	v.Traps = []models.Trap{{Name: "Spike Trap", Damage: 5, Remaining: 9, TriggerRate: 50}}
+	s := NewSiege(&v)
+	wave := s.RunWave(1, 6)
+
+	fired := 0
+	for _, ev := range wave.Events {
+		if ev.Kind != "trap" {
+			continue
+		}
+		fired++
+		if ev.Lane != 1 {
+			t.Errorf("trap on lane 1 hit a monster on lane %d", ev.Lane)
+		}
+	}
+	// Two monsters cross lane 1; at 50% the trap fires for the first and
+	// recharges on the second.
+	if fired != 1 {
+		t.Errorf("trap fired %d times, want 1", fired)
+	}
+	if v.Traps[0].Remaining != 8 {
+		t.Errorf("trap has %d charges left, want 8", v.Traps[0].Remaining)
+	}
+}
+
+func TestSiegeWallsAbsorbThenBreak(t *testing.T) {
+	v := siegeVillage()
+	wall := NewDefense(DefenseBlueprints[0])
+	v.Defenses = []models.Defense{wall}
+	s := NewSiege(&v)
+	s.RunWave(1, 9)
+
+	hp := wall.Defense * 4
+	if got := s.Report.WallDamage[wall.Name]; got != hp {
+		t.Errorf("wall absorbed %d damage, want all %d of its HP", got, hp)
+	}
+	if !Contains(s.Report.WallsBroken, wall.Name) {
+		t.Error("wall should have broken")
+	}
+	broken := false
+	for _, ev := range s.Report.Waves[0].Events {
+		if ev.Kind == "wall" && strings.HasSuffix(ev.Text, "breaks!") {
+			broken = true
+		}
+		if ev.Kind == "breach" && ev.Lane == 1 && !broken {
+			t.Errorf("monster breached the walled lane before its wall broke: %s", ev.Text)
+		}
+	}
+}
+
+func TestSiegeGuardsIntercept(t *testing.T) {
+	v := siegeVillage()
+	v.ActiveGuards = []models.Guard{strongGuard("Brom"), strongGuard("Cade"), strongGuard("Dara")}
+	s := NewSiege(&v)
+	wave := s.RunWave(1, 6)
+
+	if wave.Breached != 0 {
+		t.Errorf("%d monsters got past three strong guards", wave.Breached)
+	}
+	kills := s.Report.Kills["Brom"] + s.Report.Kills["Cade"] + s.Report.Kills["Dara"]
+	if kills != 6 {
+		t.Errorf("guards killed %d of 6 monsters", kills)
+	}
+}
+
+func TestSiegeFallenGuardReleasesMonsters(t *testing.T) {
+	v := siegeVillage()
+	weak := models.Guard{Name: "Pip", Level: 1, HitPoints: 1, HitpointsRemaining: 1, AttackRolls: 1}
+	v.ActiveGuards = []models.Guard{weak}
+	s := NewSiege(&v)
+	wave := s.RunWave(1, 1)
+
+	if !v.ActiveGuards[0].Injured {
+		t.Error("guard at 0 HP should be injured")
+	}
+	if wave.Breached != 1 {
+		t.Errorf("monster should breach once its guard falls, breached %d", wave.Breached)
+	}
+}
+
+func TestAutoTideRecordsSiege(t *testing.T) {
+	v := siegeVillage()
+	v.ActiveGuards = []models.Guard{strongGuard("Brom")}
+	player := models.Character{Name: "TestPlayer", ResourceStorageMap: map[string]models.Resource{}}
+
+	result := ProcessAutoTide(&v, &player)
+	if v.LastSiege == nil || result.Report != v.LastSiege {
+		t.Fatal("auto-tide should keep its siege report on the village")
+	}
+	if len(v.LastSiege.Waves) != result.WavesProcessed {
+		t.Errorf("report has %d waves, tide fought %d", len(v.LastSiege.Waves), result.WavesProcessed)
+	}
+	if v.LastSiege.Victory != result.Victory {
+		t.Error("report and result disagree on the outcome")
+	}
+}
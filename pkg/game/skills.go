@@ -1,20 +1,250 @@
 package game
 
-import "rpg-game/pkg/models"
+import (
+	"fmt"
 
-// UpgradeSkill applies a single upgrade to a skill: +5 damage (or +5 healing),
-// -2 mana cost, -2 stamina cost. This matches the village crafting upgrade logic.
-func UpgradeSkill(skill *models.Skill) {
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+// ApplySkillUpgrade raises a skill's damage (or healing) and lowers its costs
+// as up describes, returning a line per change.
+func ApplySkillUpgrade(skill *models.Skill, up models.SkillUpgrade) []string {
+	lines := improveSkill(skill, up)
+	skill.UpgradeCount += max(up.UpgradeLevel, 1)
+	return lines
+}
+
+// improveSkill applies up's damage and cost changes to skill.
+func improveSkill(skill *models.Skill, up models.SkillUpgrade) []string {
+	lines := []string{}
 	if skill.Damage > 0 {
-		skill.Damage += 5
+		skill.Damage += up.DamageIncrease
+		lines = append(lines, fmt.Sprintf("%s damage increased by %d! (Now: %d)", skill.Name, up.DamageIncrease, skill.Damage))
 	} else if skill.Damage < 0 {
-		skill.Damage -= 5 // more negative = more healing
+		skill.Damage -= up.DamageIncrease // more negative = more healing
+		lines = append(lines, fmt.Sprintf("%s healing increased by %d! (Now: %d)", skill.Name, up.DamageIncrease, -skill.Damage))
+	}
+	if skill.ManaCost > up.CostReduction {
+		skill.ManaCost -= up.CostReduction
+		lines = append(lines, fmt.Sprintf("Mana cost reduced by %d! (Now: %d)", up.CostReduction, skill.ManaCost))
+	}
+	if skill.StaminaCost > up.CostReduction {
+		skill.StaminaCost -= up.CostReduction
+		lines = append(lines, fmt.Sprintf("Stamina cost reduced by %d! (Now: %d)", up.CostReduction, skill.StaminaCost))
+	}
+	return lines
+}
+
+// SkillRank is a skill's rank, starting at 1.
+func SkillRank(skill models.Skill) int {
+	return max(skill.Rank, 1)
+}
+
+// NextRankXP is the skill experience the skill needs for its next rank, or 0
+// at the top rank.
+func NextRankXP(skill models.Skill) int {
+	if SkillRank(skill) >= len(data.SkillRankXP) {
+		return 0
+	}
+	return data.SkillRankXP[SkillRank(skill)]
+}
+
+// GainSkillXP adds skill experience, improving the skill by
+// data.SkillRankUpgrade for every rank reached. It returns a line per change.
+func GainSkillXP(skill *models.Skill, xp int) []string {
+	lines := []string{}
+	skill.Rank = SkillRank(*skill)
+	skill.RankXP += xp
+	for next := NextRankXP(*skill); next > 0 && skill.RankXP >= next; next = NextRankXP(*skill) {
+		skill.Rank++
+		lines = append(lines, fmt.Sprintf("%s reached rank %d!", skill.Name, skill.Rank))
+		lines = append(lines, improveSkill(skill, data.SkillRankUpgrade)...)
 	}
-	if skill.ManaCost > 2 {
-		skill.ManaCost -= 2
+	return lines
+}
+
+// TrainSkill gives the character's learned skill of that name experience.
+func TrainSkill(player *models.Character, name string, xp int) []string {
+	idx := findLearnedSkill(player, name)
+	if idx < 0 {
+		return nil
 	}
-	if skill.StaminaCost > 2 {
-		skill.StaminaCost -= 2
+	return GainSkillXP(&player.LearnedSkills[idx], xp)
+}
+
+// skillTiming returns the skill's cooldown and charge time. Copies learned
+// before skills had them fall back to the catalogue entry, and monster skills
+// outside the catalogue rest data.DefaultSkillCooldown turns.
+func skillTiming(skill models.Skill) (cooldown, charge int) {
+	if skill.Cooldown > 0 || skill.ChargeTurns > 0 {
+		return skill.Cooldown, skill.ChargeTurns
+	}
+	if def, ok := findSkill(skill.Name); ok {
+		return def.Cooldown, def.ChargeTurns
+	}
+	return data.DefaultSkillCooldown, 0
+}
+
+// SkillCooldown is how many turns a skill rests after use.
+func SkillCooldown(skill models.Skill) int {
+	cooldown, _ := skillTiming(skill)
+	return cooldown
+}
+
+// SkillChargeTurns is how many turns a skill charges before it goes off.
+func SkillChargeTurns(skill models.Skill) int {
+	_, charge := skillTiming(skill)
+	return charge
+}
+
+// SkillTimers tracks one combatant's skill cooldowns, the skill it is
+// charging and its combo chain for the length of a fight. Turns are the
+// fight's turn numbers. The zero value is ready to use, and a nil
+// *SkillTimers treats every skill as ready.
+type SkillTimers struct {
+	ReadyOn       map[string]int // turn each resting skill can be used again
+	Charging      *models.Skill  // skill being charged, already paid for
+	ChargeLeft    int
+	LastSkill     string // last skill used, for combo chains
+	LastSkillTurn int
+}
+
+// CooldownLeft is how many turns from turn the named skill keeps resting.
+func (t *SkillTimers) CooldownLeft(name string, turn int) int {
+	if t == nil {
+		return 0
+	}
+	return max(t.ReadyOn[name]-turn, 0)
+}
+
+// Ready reports whether the skill can be used on turn.
+func (t *SkillTimers) Ready(skill models.Skill, turn int) bool {
+	return t.CooldownLeft(skill.Name, turn) == 0
+}
+
+// previous is the skill used on the turn before turn, which a skill used on
+// turn can finish a combo from.
+func (t *SkillTimers) previous(turn int) string {
+	if t == nil || t.LastSkillTurn != turn-1 {
+		return ""
+	}
+	return t.LastSkill
+}
+
+// BeginCharge starts charging a skill whose costs have been paid. The combo
+// chain carries through the charge.
+func (t *SkillTimers) BeginCharge(skill models.Skill, turn int) {
+	t.Charging = &skill
+	t.ChargeLeft = SkillChargeTurns(skill)
+	if t.previous(turn) != "" {
+		t.LastSkillTurn = turn
+	}
+}
+
+// ContinueCharge spends turn charging and returns the skill once it is
+// ready to go off.
+func (t *SkillTimers) ContinueCharge(turn int) (models.Skill, bool) {
+	if t == nil || t.Charging == nil {
+		return models.Skill{}, false
+	}
+	t.ChargeLeft--
+	if t.ChargeLeft > 0 {
+		if t.previous(turn) != "" {
+			t.LastSkillTurn = turn
+		}
+		return models.Skill{}, false
+	}
+	skill := *t.Charging
+	t.Charging = nil
+	return skill, true
+}
+
+// InterruptCharge drops the skill being charged, returning its name.
+func (t *SkillTimers) InterruptCharge() string {
+	if t == nil || t.Charging == nil {
+		return ""
+	}
+	name := t.Charging.Name
+	t.Charging, t.ChargeLeft = nil, 0
+	return name
+}
+
+// FindCombo returns the combo that skill completes against target, given the
+// skill used the turn before.
+func FindCombo(previous string, skill models.Skill, target interface{}) (models.SkillCombo, bool) {
+	if previous == "" {
+		return models.SkillCombo{}, false
+	}
+	for _, c := range data.SkillCombos {
+		if c.First != previous || c.Then != skill.Name {
+			continue
+		}
+		if c.RequiresStatus != "" && !hasStatus(target, c.RequiresStatus) {
+			continue
+		}
+		return c, true
+	}
+	return models.SkillCombo{}, false
+}
+
+// hasStatus reports whether target is under an effect of the given type.
+func hasStatus(target interface{}, statusType string) bool {
+	if t := statusTargetOf(target); t != nil {
+		for _, e := range *t.effects {
+			if e.Type == statusType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SkillCast is the outcome of a combatant using a skill.
+type SkillCast struct {
+	Power    int                // damage (or negative healing) after any combo bonus
+	Combo    *models.SkillCombo // the combo the skill completed, if any
+	Messages []StatusMessage
+}
+
+// Cast records the use of skill against target on turn: it starts the
+// cooldown, completes any combo with the skill used the turn before and
+// scales power, the skill's damage (or healing) before resistances.
+func (t *SkillTimers) Cast(skill models.Skill, power int, target interface{}, turn int) SkillCast {
+	cast := SkillCast{Power: power}
+	if t == nil {
+		return cast
+	}
+	if cooldown := SkillCooldown(skill); cooldown > 0 {
+		if t.ReadyOn == nil {
+			t.ReadyOn = map[string]int{}
+		}
+		t.ReadyOn[skill.Name] = turn + cooldown + 1
+	}
+	combo, ok := FindCombo(t.previous(turn), skill, target)
+	t.LastSkill, t.LastSkillTurn = skill.Name, turn
+	if !ok {
+		return cast
+	}
+	cast.Combo = &combo
+	cast.Power = power * (100 + combo.BonusPct) / 100
+	cast.Messages = append(cast.Messages, StatusMessage{fmt.Sprintf("COMBO! %s: %s", combo.Name, combo.Description), "combat"})
+	if combo.ConsumesStatus {
+		removeStatuses(target, func(def models.StatusDefinition) bool { return def.Type == combo.RequiresStatus },
+			"%s's %s shatters!", "debuff")
+	}
+	if combo.Effect.Type != "" {
+		if msg, _ := ApplyStatus(target, combo.Effect); msg.Text != "" {
+			cast.Messages = append(cast.Messages, msg)
+		}
+	}
+	return cast
+}
+
+// CastXP is the skill experience a cast earns.
+func CastXP(cast SkillCast) int {
+	if cast.Combo != nil {
+		return data.SkillComboXP
 	}
-	skill.UpgradeCount++
+	return data.SkillUseXP
 }
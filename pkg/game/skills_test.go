@@ -0,0 +1,127 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/data"
+	"rpg-game/pkg/models"
+)
+
+func TestSkillCooldowns(t *testing.T) {
+	fireball, _ := findSkill("Fireball")
+	mob := GenerateMonster("wolf", 5, 2)
+	timers := &SkillTimers{}
+
+	timers.Cast(fireball, 18, &mob, 1)
+	for turn := 2; turn <= 1+fireball.Cooldown; turn++ {
+		if timers.Ready(fireball, turn) {
+			t.Fatalf("Fireball should rest on turn %d", turn)
+		}
+	}
+	if !timers.Ready(fireball, 2+fireball.Cooldown) {
+		t.Error("Fireball should be ready once its cooldown has passed")
+	}
+
+	// Older saves have no cooldown on the learned copy
+	fireball.Cooldown = 0
+	if SkillCooldown(fireball) != 2 {
+		t.Errorf("expected the catalogue cooldown, got %d", SkillCooldown(fireball))
+	}
+	if SkillCooldown(models.Skill{Name: "Pounce"}) != data.DefaultSkillCooldown {
+		t.Error("monster skills should use the default cooldown")
+	}
+}
+
+func TestSkillCombo(t *testing.T) {
+	shard, _ := findSkill("Ice Shard")
+	bolt, _ := findSkill("Lightning Bolt")
+	mob := GenerateMonster("wolf", 5, 2)
+	timers := &SkillTimers{}
+
+	timers.Cast(shard, 15, &mob, 1)
+	cast := timers.Cast(bolt, 22, &mob, 2)
+	if cast.Combo == nil || cast.Combo.Name != "Shatter" || cast.Power != 44 {
+		t.Fatalf("expected Shatter to double Lightning Bolt, got %+v", cast)
+	}
+
+	timers = &SkillTimers{}
+	timers.Cast(shard, 15, &mob, 1)
+	if cast := timers.Cast(bolt, 22, &mob, 3); cast.Combo != nil {
+		t.Error("a turn in between should break the chain")
+	}
+
+	nova, _ := findSkill("Frost Nova")
+	timers = &SkillTimers{}
+	timers.Cast(nova, 12, &mob, 1)
+	if cast := timers.Cast(bolt, 22, &mob, 2); cast.Combo != nil {
+		t.Error("Deep Shatter needs a frozen target")
+	}
+	ApplyStatus(&mob, models.StatusEffect{Type: "freeze", Duration: 1, Potency: 5})
+	timers.Cast(nova, 12, &mob, 5)
+	cast = timers.Cast(bolt, 20, &mob, 6)
+	if cast.Combo == nil || cast.Combo.Name != "Deep Shatter" || hasStatus(&mob, "freeze") {
+		t.Errorf("Deep Shatter should break the ice, got %+v with %+v", cast, mob.StatusEffects)
+	}
+}
+
+func TestSkillCharging(t *testing.T) {
+	fireball, _ := findSkill("Fireball")
+	meteor, _ := findSkill("Meteor")
+	mob := GenerateMonster("wolf", 5, 2)
+	ApplyStatus(&mob, models.StatusEffect{Type: "burn", Duration: 3, Potency: 3})
+	timers := &SkillTimers{}
+
+	timers.Cast(fireball, 18, &mob, 1)
+	timers.BeginCharge(meteor, 2)
+	skill, ready := timers.ContinueCharge(3)
+	if !ready || skill.Name != "Meteor" || timers.Charging != nil {
+		t.Fatal("Meteor should go off after one turn of charging")
+	}
+	if cast := timers.Cast(skill, 40, &mob, 3); cast.Combo == nil || cast.Combo.Name != "Cataclysm" {
+		t.Error("the Fireball chain should carry through the charge")
+	}
+
+	timers.BeginCharge(meteor, 10)
+	if timers.InterruptCharge() != "Meteor" || timers.Charging != nil {
+		t.Error("interrupting should drop the charge")
+	}
+}
+
+func TestSkillRanks(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	fireball, _ := findSkill("Fireball")
+	player.LearnedSkills = []models.Skill{fireball}
+
+	if lines := TrainSkill(&player, "Fireball", data.SkillRankXP[1]-1); len(lines) != 0 {
+		t.Fatalf("no rank up expected yet, got %v", lines)
+	}
+	TrainSkill(&player, "Fireball", 1)
+	got := player.LearnedSkills[0]
+	if SkillRank(got) != 2 || got.Damage != fireball.Damage+data.SkillRankUpgrade.DamageIncrease {
+		t.Fatalf("expected rank 2 with more damage, got rank %d damage %d", SkillRank(got), got.Damage)
+	}
+	if got.UpgradeCount != 0 {
+		t.Error("ranks should not count as village upgrades")
+	}
+
+	TrainSkill(&player, "Fireball", 1000)
+	if SkillRank(player.LearnedSkills[0]) != len(data.SkillRankXP) || NextRankXP(player.LearnedSkills[0]) != 0 {
+		t.Error("skill experience should stop at the top rank")
+	}
+}
+
+func TestAIRespectsCooldowns(t *testing.T) {
+	player := GenerateCharacter("Hero", 1, 1)
+	fireball, _ := findSkill("Fireball")
+	player.LearnedSkills = []models.Skill{fireball}
+	player.ManaRemaining = 100
+	mob := GenerateMonster("wolf", 5, 2)
+	timers := &SkillTimers{}
+	timers.Cast(fireball, 18, &mob, 1)
+
+	for i := 0; i < 50; i++ {
+		if MakeAIDecision(&player, &mob, 2, timers) != "attack" {
+			t.Fatal("the AI should not pick a skill on cooldown")
+		}
+	}
+}
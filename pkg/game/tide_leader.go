@@ -90,9 +90,12 @@ func ProcessTideLeaderRaid(leader *models.TideLeader, village *models.Village, p
 
 	result.Messages = append(result.Messages,
 		fmt.Sprintf("%s contributes %d damage to %s!", village.Name, villageDamage, leader.Name))
+	if leader.HitpointsRemaining > 0 {
+		result.Messages = append(result.Messages, TideLeaderPhase(leader)...)
+	}
 
-	// Leader retaliates — damages village defenses
-	leaderDamage := leader.Level*2 + leader.AttackRolls*3
+	// Leader retaliates — damages village defenses, harder in later phases
+	leaderDamage := leader.Level*2 + leader.AttackRolls*3 + TideLeaderAttackBonus(leader)
 	leaderDamage = leaderDamage * (80 + rand.Intn(41)) / 100
 
 	// Defenses absorb damage first
@@ -137,21 +140,24 @@ func ProcessTideLeaderRaid(leader *models.TideLeader, village *models.Village, p
 	return result
 }
 
-// TideLeaderDefeatReward grants rewards to a player whose village participated.
-func TideLeaderDefeatReward(player *models.Character, leader *models.TideLeader) (int, int) {
+// TideLeaderDefeatReward grants rewards to a player whose village
+// participated, including a roll on the tide leader's loot table.
+func TideLeaderDefeatReward(player *models.Character, leader *models.TideLeader) (int, int, []models.Item) {
 	xp := leader.Level*50 + leader.TimesUndefeated*100
 	gold := leader.Level*10 + leader.TimesUndefeated*20
 
 	player.Experience += xp
-	if player.ResourceStorageMap == nil {
-		player.ResourceStorageMap = make(map[string]models.Resource)
+	AdjustGold(player, gold, ReasonTideLeaderReward, "tide_leader:"+leader.Name)
+
+	looted := []models.Item{}
+	if def, ok := FindBoss("tide_leader"); ok {
+		for _, item := range RollBossLoot(def) {
+			if LootItem(player, item, ReasonTideLeaderReward, "tide_leader:"+leader.Name) {
+				looted = append(looted, item)
+			}
+		}
 	}
-	goldRes := player.ResourceStorageMap["Gold"]
-	goldRes.Name = "Gold"
-	goldRes.Stock += gold
-	player.ResourceStorageMap["Gold"] = goldRes
-
-	return xp, gold
+	return xp, gold, looted
 }
 
 // ScaleTidesForUndefeated reduces the tide interval by 10% per undefeated
@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"time"
 
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/data"
 	"rpg-game/pkg/models"
 )
@@ -133,6 +134,7 @@ func InnGuestFromCharacter(char *models.Character, accountID int64, goldPaid int
 		EquipmentMap:  copyEquipmentMap(char.EquipmentMap),
 		LearnedSkills: append([]models.Skill{}, char.LearnedSkills...),
 		Resistances:   copyResistances(char.Resistances),
+		Speed:         CharacterSpeed(char),
 	}
 }
 
@@ -160,6 +162,7 @@ func InnGuestToMonster(guest *models.InnGuest) models.Monster {
 		StatusEffects:      []models.StatusEffect{},
 		Resistances:        copyResistances(guest.Resistances),
 		MonsterType:        "humanoid",
+		Speed:              guest.Speed,
 	}
 }
 
@@ -200,8 +203,8 @@ func CalculateTax(amount int, taxRate int) (int, int) {
 	if taxRate <= 0 || amount <= 0 {
 		return amount, 0
 	}
-	if taxRate > 50 {
-		taxRate = 50
+	if max := config.Current().Balance.TaxMax; taxRate > max {
+		taxRate = max
 	}
 	taxAmount := (amount * taxRate) / 100
 	if taxAmount < 1 && amount > 0 && taxRate > 0 {
@@ -276,6 +279,7 @@ func GenerateNPCGuest(name string, level int) models.InnGuest {
 		EquipmentMap:  equipMap,
 		LearnedSkills: skills,
 		Resistances:   resistances,
+		Speed:         BaseSpeed + level/5,
 	}
 }
 
@@ -313,7 +317,8 @@ func CleanExpiredGuests(town *models.Town, maxAge int64) {
 
 // InnSleepCost returns the gold cost for sleeping at the inn.
 func InnSleepCost(level int) int {
-	return 10 + level*5
+	b := config.Current().Balance
+	return b.InnSleepBaseCost + level*b.InnSleepCostPerLevel
 }
 
 func copyEquipmentMap(m map[int]models.Item) map[int]models.Item {
@@ -39,15 +39,12 @@ func GenerateVillager(role string) models.Villager {
 		Efficiency:   efficiency,
 		AssignedTask: "",
 		HarvestType:  "",
+		Trait:        RollVillagerTrait(),
 	}
 }
 
 func RescueVillager(village *models.Village) models.Villager {
-	role := "harvester"
-	if rand.Intn(100) < 30 {
-		role = "guard"
-	}
-	villager := GenerateVillager(role)
+	villager := GenerateVillager(RollVillagerRole())
 	village.Villagers = append(village.Villagers, villager)
 	fmt.Printf("🎉 You rescued %s!\n", villager.Name)
 	fmt.Printf("🏘️ %s has joined your village as a %s (Efficiency: %d)\n", villager.Name, villager.Role, villager.Efficiency)
@@ -59,30 +56,27 @@ type HarvestResult struct {
 	VillagerName string
 	Amount       int
 	ResourceType string
+	LeveledUp    bool // the villager reached NewLevel with this harvest
+	NewLevel     int
 }
 
 // ProcessVillageResourceCollection collects resources from active harvesters,
 // updates the player's resource storage, and returns what was collected.
 func ProcessVillageResourceCollection(village *models.Village, player *models.Character) []HarvestResult {
 	var results []HarvestResult
-	for _, villager := range village.Villagers {
-		if villager.Role == "harvester" && villager.HarvestType != "" {
-			amount := villager.Efficiency + villager.Level/2
-			resource, exists := player.ResourceStorageMap[villager.HarvestType]
-			if exists {
-				resource.Stock += amount
-				player.ResourceStorageMap[villager.HarvestType] = resource
-			} else {
-				player.ResourceStorageMap[villager.HarvestType] = models.Resource{
-					Name:         villager.HarvestType,
-					Stock:        amount,
-					RollModifier: 0,
-				}
-			}
+	for i := range village.Villagers {
+		villager := &village.Villagers[i]
+		if villager.Role == "harvester" && villager.HarvestType != "" && villager.InjuredTicks == 0 {
+			amount := HarvestYield(player, VillagerOutput(*villager))
+			amount += amount * HarvestBonus(village) / 100
+			AdjustResource(player, villager.HarvestType, amount, ReasonHarvest, "villager:"+villager.Name)
+			leveled := GainVillagerXP(villager, HarvestXP)
 			results = append(results, HarvestResult{
 				VillagerName: villager.Name,
 				Amount:       amount,
 				ResourceType: villager.HarvestType,
+				LeveledUp:    leveled,
+				NewLevel:     villager.Level,
 			})
 		}
 	}
@@ -192,6 +186,7 @@ type AutoTideResult struct {
 	VillagersLost     int
 	DefensesDestroyed int
 	Messages          []string
+	Report            *models.SiegeReport // the lane-by-lane battle
 }
 
 // ProcessAutoTide runs a full non-interactive monster tide against a village.
@@ -231,130 +226,34 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 	}
 	result.Messages = append(result.Messages,
 		fmt.Sprintf("Defenders: %d guards, %d traps, %d defenses", guardCount, trapCount, defenseCount))
+	if BuildingLevel(village, BuildingWatchtower) > 0 {
+		result.Messages = append(result.Messages,
+			"Defenders: warned by the watchtower, the villagers have taken shelter.")
+	}
 
-	monsterTypes := []string{"Goblin", "Orc", "Kobold", "Slime", "Skeleton", "Wolf", "Bandit"}
+	villagersBefore := len(village.Villagers)
+	siege := NewSiege(village)
 
 	for wave := 1; wave <= totalWaves; wave++ {
 		result.WavesProcessed++
 		result.Messages = append(result.Messages,
 			fmt.Sprintf("-- Wave %d/%d: %d monsters charge! --", wave, totalWaves, monstersPerWave))
 
-		waveKills := 0
-		waveBreaches := 0
-
-		for m := 0; m < monstersPerWave; m++ {
-			monsterHP := 8 + level*3 + rand.Intn(level*2+1)
-			monsterAtk := 2 + level + rand.Intn(level+1)
-			monsterName := monsterTypes[rand.Intn(len(monsterTypes))]
-			monsterLabel := fmt.Sprintf("Lv%d %s", level+rand.Intn(3), monsterName)
-
-			// Phase 1: Traps
-			for i := range village.Traps {
-				if village.Traps[i].Remaining > 0 && rand.Intn(100) < village.Traps[i].TriggerRate {
-					dmg := village.Traps[i].Damage
-					monsterHP -= dmg
-					result.DamageDealt += dmg
-					village.Traps[i].Remaining--
-					if monsterHP <= 0 {
-						result.Messages = append(result.Messages,
-							fmt.Sprintf("  %s triggers %s for %d dmg - killed!", monsterLabel, village.Traps[i].Name, dmg))
-					} else {
-						result.Messages = append(result.Messages,
-							fmt.Sprintf("  %s triggers %s for %d dmg (%d HP left)", monsterLabel, village.Traps[i].Name, dmg, monsterHP))
-					}
-				}
-				if monsterHP <= 0 {
-					break
-				}
-			}
-			if monsterHP <= 0 {
-				result.MonstersKilled++
-				waveKills++
-				continue
-			}
-
-			// Phase 2: Towers/Defenses
-			for _, d := range village.Defenses {
-				if d.Built && d.AttackPower > 0 {
-					dmg := d.AttackPower + rand.Intn(d.Level*2+1)
-					monsterHP -= dmg
-					result.DamageDealt += dmg
-					if monsterHP <= 0 {
-						result.Messages = append(result.Messages,
-							fmt.Sprintf("  %s hit by %s for %d dmg - killed!", monsterLabel, d.Name, dmg))
-					} else {
-						result.Messages = append(result.Messages,
-							fmt.Sprintf("  %s hit by %s for %d dmg (%d HP left)", monsterLabel, d.Name, dmg, monsterHP))
-					}
-				}
-				if monsterHP <= 0 {
-					break
-				}
-			}
-			if monsterHP <= 0 {
-				result.MonstersKilled++
-				waveKills++
-				continue
-			}
-
-			// Phase 3: Guards
-			guardEngaged := false
-			for i := range village.ActiveGuards {
-				if village.ActiveGuards[i].HitpointsRemaining <= 0 {
-					continue
-				}
-				guardEngaged = true
-				guard := &village.ActiveGuards[i]
-				guardAtk := guard.AttackRolls*3 + guard.AttackBonus + guard.StatsMod.AttackMod
-				guardDef := guard.DefenseRolls*2 + guard.DefenseBonus + guard.StatsMod.DefenseMod
-				// Guard attacks monster
-				dmg := guardAtk + rand.Intn(guardAtk/2+1)
-				monsterHP -= dmg
-				result.DamageDealt += dmg
-				if monsterHP <= 0 {
-					result.MonstersKilled++
-					waveKills++
-					result.Messages = append(result.Messages,
-						fmt.Sprintf("  %s engages %s - deals %d dmg - killed!", guard.Name, monsterLabel, dmg))
-					break
-				}
-				// Monster attacks guard
-				mDmg := monsterAtk - guardDef
-				if mDmg < 1 {
-					mDmg = 1
-				}
-				guard.HitpointsRemaining -= mDmg
-				result.DamageTaken += mDmg
-				if guard.HitpointsRemaining <= 0 {
-					guard.Injured = true
-					result.Messages = append(result.Messages,
-						fmt.Sprintf("  %s fights %s (%d dmg) but takes %d dmg and falls!", guard.Name, monsterLabel, dmg, mDmg))
-				} else {
-					result.Messages = append(result.Messages,
-						fmt.Sprintf("  %s fights %s (%d dmg) and takes %d dmg (%d/%d HP)", guard.Name, monsterLabel, dmg, mDmg, guard.HitpointsRemaining, guard.HitPoints))
-				}
-				break // one guard engages per monster
-			}
-			if monsterHP <= 0 {
-				continue
-			}
-
-			// Phase 4: Breach — monster got through
-			breachDmg := monsterAtk
-			result.DamageTaken += breachDmg
-			waveBreaches++
-			if !guardEngaged {
-				result.Messages = append(result.Messages,
-					fmt.Sprintf("  %s breaches undefended village! (%d breach dmg)", monsterLabel, breachDmg))
-			} else {
-				result.Messages = append(result.Messages,
-					fmt.Sprintf("  %s breaks through defenses! (%d breach dmg)", monsterLabel, breachDmg))
-			}
+		report := siege.RunWave(wave, monstersPerWave)
+		for _, ev := range report.Events {
+			result.Messages = append(result.Messages, SiegeEventLine(ev))
 		}
+		result.DamageDealt += report.DamageDealt
+		result.DamageTaken += report.DamageTaken
+		result.MonstersKilled += report.Killed
+		waveKills, waveBreaches := report.Killed, report.Breached
 
 		// Wave summary
 		result.Messages = append(result.Messages,
 			fmt.Sprintf("  Wave %d result: %d killed, %d breached", wave, waveKills, waveBreaches))
+		for _, line := range TideCasualties(village, waveBreaches) {
+			result.Messages = append(result.Messages, "  "+line)
+		}
 
 		// Decrement trap durability at end of wave
 		for i := range village.Traps {
@@ -362,6 +261,14 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 				village.Traps[i].Remaining--
 			}
 		}
+
+		// Guard equipment wears down each wave
+		for i := range village.ActiveGuards {
+			for _, name := range WearGuardEquipment(&village.ActiveGuards[i], TideWear) {
+				result.Messages = append(result.Messages,
+					fmt.Sprintf("  %s's %s has broken!", village.ActiveGuards[i].Name, name))
+			}
+		}
 	}
 
 	// Determine victory/defeat
@@ -369,22 +276,23 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 	// before losing, which is enough to survive 1 wave of 2 weak monsters.
 	defenseThreshold := 30 + defenseLevel*50
 
+	report := &siege.Report
+	report.Threshold = defenseThreshold
+	report.Victory = report.BreachDamage < defenseThreshold
+	report.Time = time.Now().Unix()
+
 	result.Messages = append(result.Messages,
 		fmt.Sprintf("-- Battle Over -- Total damage dealt: %d | Breach damage taken: %d/%d threshold",
-			result.DamageDealt, result.DamageTaken, defenseThreshold))
+			result.DamageDealt, report.BreachDamage, defenseThreshold))
+	result.Messages = append(result.Messages, SiegeSummary(report)...)
 
-	if result.DamageTaken < defenseThreshold {
+	if report.Victory {
 		// Victory
 		result.Victory = true
 		result.XPReward = level*20 + result.MonstersKilled*5
-		if result.DamageTaken < defenseThreshold/2 {
+		if report.BreachDamage < defenseThreshold/2 {
 			result.BonusGold = level * 10
-			if res, ok := player.ResourceStorageMap["Gold"]; ok {
-				res.Stock += result.BonusGold
-				player.ResourceStorageMap["Gold"] = res
-			} else {
-				player.ResourceStorageMap["Gold"] = models.Resource{Name: "Gold", Stock: result.BonusGold}
-			}
+			AdjustGold(player, result.BonusGold, ReasonTideVictory, "village:"+village.Name)
 		}
 		village.Experience += result.XPReward
 		result.Messages = append(result.Messages,
@@ -394,6 +302,11 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 			result.Messages = append(result.Messages,
 				fmt.Sprintf("Strong defense bonus: +%d Gold", result.BonusGold))
 		}
+		result.Messages = append(result.Messages, VillagersAfterTide(village, true)...)
+		if result.VillagersLost = villagersBefore - len(village.Villagers); result.VillagersLost > 0 {
+			result.Messages = append(result.Messages,
+				fmt.Sprintf("Villagers killed in the fighting: %d", result.VillagersLost))
+		}
 
 		// Report surviving guard status
 		injured := 0
@@ -419,7 +332,7 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 
 		// Count losses for reporting
 		result.GuardsLost = len(village.ActiveGuards)
-		result.VillagersLost = len(village.Villagers)
+		result.VillagersLost = villagersBefore
 		result.DefensesDestroyed = len(village.Defenses)
 
 		// Steal resources
@@ -431,8 +344,7 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 				if loss > res.Stock {
 					loss = res.Stock
 				}
-				res.Stock -= loss
-				player.ResourceStorageMap[rt] = res
+				AdjustResource(player, rt, -loss, ReasonTideLoss, "village:"+village.Name)
 				result.ResourcesLost += loss
 			}
 		}
@@ -490,15 +402,17 @@ func ProcessAutoTide(village *models.Village, player *models.Character) AutoTide
 	}
 	village.Traps = activeTraps
 
-	village.LastTideTime = time.Now().Unix()
+	village.LastTideTime = report.Time
+	village.LastSiege = report
 	UpgradeVillage(village)
 
+	result.Report = report
 	return result
 }
 
 // ProcessVillageManagerTick performs automated village upkeep: assigning idle
-// harvesters, hiring guards, building defenses/traps, recovering guards, and
-// upgrading the village. Returns a list of action messages (empty if nothing done).
+// harvesters, hiring guards, building defenses/traps, recovering guards,
+// paying building upkeep, and upgrading the village. Returns a list of action messages (empty if nothing done).
 func ProcessVillageManagerTick(village *models.Village, player *models.Character) []string {
 	var messages []string
 	resourceTypes := []string{"Lumber", "Gold", "Iron", "Sand", "Stone"}
@@ -506,7 +420,7 @@ func ProcessVillageManagerTick(village *models.Village, player *models.Character
 	// 1. Assign idle harvesters
 	for i := range village.Villagers {
 		v := &village.Villagers[i]
-		if v.Role == "harvester" && v.HarvestType == "" {
+		if IsIdleHarvester(*v) {
 			v.HarvestType = resourceTypes[rand.Intn(len(resourceTypes))]
 			v.AssignedTask = "harvesting"
 			village.Experience += 10
@@ -515,14 +429,13 @@ func ProcessVillageManagerTick(village *models.Village, player *models.Character
 	}
 
 	// 2. Hire a guard (one per tick)
-	if len(village.ActiveGuards) < village.Level {
+	if len(village.ActiveGuards) < GuardCapacity(village) {
 		cost := 50 + village.Level*25
 		if goldRes, ok := player.ResourceStorageMap["Gold"]; ok && goldRes.Stock >= cost {
 			guard := GenerateGuard(village.Level)
 			guard.Hired = true
 			village.ActiveGuards = append(village.ActiveGuards, guard)
-			goldRes.Stock -= cost
-			player.ResourceStorageMap["Gold"] = goldRes
+			AdjustGold(player, -cost, ReasonGuardHire, "guard:"+guard.Name)
 			village.Experience += 50
 			messages = append(messages, fmt.Sprintf("Hired guard %s for %d Gold", guard.Name, cost))
 		}
@@ -542,10 +455,8 @@ func ProcessVillageManagerTick(village *models.Village, player *models.Character
 			}
 			village.Defenses = append(village.Defenses, wall)
 			village.DefenseLevel++
-			lumberRes.Stock -= 50
-			stoneRes.Stock -= 20
-			player.ResourceStorageMap["Lumber"] = lumberRes
-			player.ResourceStorageMap["Stone"] = stoneRes
+			AdjustResource(player, "Lumber", -50, ReasonBuilding, "defense:"+wall.Name)
+			AdjustResource(player, "Stone", -20, ReasonBuilding, "defense:"+wall.Name)
 			village.Experience += 30
 			messages = append(messages, "Built a Wooden Wall")
 		}
@@ -566,10 +477,8 @@ func ProcessVillageManagerTick(village *models.Village, player *models.Character
 				TriggerRate: 60,
 			}
 			village.Traps = append(village.Traps, trap)
-			ironRes.Stock -= 10
-			boneRes.Stock -= 5
-			player.ResourceStorageMap["Iron"] = ironRes
-			player.ResourceStorageMap["Beast Bone"] = boneRes
+			AdjustResource(player, "Iron", -10, ReasonBuilding, "trap:"+trap.Name)
+			AdjustResource(player, "Beast Bone", -5, ReasonBuilding, "trap:"+trap.Name)
 			village.Experience += 35
 			messages = append(messages, "Built a Spike Trap")
 		}
@@ -578,7 +487,16 @@ func ProcessVillageManagerTick(village *models.Village, player *models.Character
 	// 5. Recover injured guards
 	ProcessGuardRecovery(village)
 
-	// 6. Upgrade village if enough XP
+	// 6. Pay building upkeep
+	messages = append(messages, PayBuildingUpkeep(village, player)...)
+
+	// 7. Drill guards at the training grounds
+	messages = append(messages, TrainGuards(village)...)
+
+	// 8. Feed villagers, heal injuries and grow the population
+	messages = append(messages, SimulateVillagers(village)...)
+
+	// 9. Upgrade village if enough XP
 	UpgradeVillage(village)
 
 	return messages
@@ -25,12 +25,15 @@ func ShowVillageMenu(gameState *models.GameState, player *models.Character, vill
 		fmt.Printf("  %s - Level %d\n", village.Name, village.Level)
 		fmt.Println("============================================================")
 		fmt.Printf("Experience: %d/%d\n", village.Experience, village.Level*100)
-		fmt.Printf("Villagers: %d (Harvesters: %d, Guards: %d)\n",
+		fmt.Printf("Villagers: %d (Harvesters: %d, Crafters: %d, Builders: %d, Guards: %d)\n",
 			len(village.Villagers),
 			CountVillagersByRole(village, "harvester"),
+			CountVillagersByRole(village, "crafter"),
+			CountVillagersByRole(village, "builder"),
 			CountVillagersByRole(village, "guard"))
-		fmt.Printf("Hired Guards: %d\n", len(village.ActiveGuards))
+		fmt.Printf("Hired Guards: %d/%d\n", len(village.ActiveGuards), GuardCapacity(village))
 		fmt.Printf("Defenses Built: %d (Level %d)\n", len(village.Defenses), village.DefenseLevel)
+		fmt.Printf("Buildings: %s\n", BuildingsText(village))
 
 		// Show unlocked crafting
 		if len(village.UnlockedCrafting) > 0 {
@@ -53,6 +56,7 @@ func ShowVillageMenu(gameState *models.GameState, player *models.Character, vill
 		fmt.Println("6 = Check Next Monster Tide")
 		fmt.Println("7 = Defend Against Tide (if ready)")
 		fmt.Println("8 = Manage Guards (Equipment & Status)")
+		fmt.Println("9 = Village Buildings")
 		fmt.Println("0 = Return to Main Menu")
 		fmt.Print("Choice: ")
 
@@ -91,6 +95,8 @@ func ShowVillageMenu(gameState *models.GameState, player *models.Character, vill
 			}
 		case "8":
 			ManageGuardsMenu(gameState, player, village)
+		case "9":
+			villageBuildingsMenu(village, player)
 		case "0":
 			// Save and return
 			gameState.CharactersMap[player.Name] = *player
@@ -502,12 +508,16 @@ func viewVillagers(village *models.Village) {
 	}
 
 	harvesters := []models.Villager{}
+	workers := []models.Villager{}
 	guards := []models.Villager{}
 
 	for _, v := range village.Villagers {
-		if v.Role == "harvester" {
+		switch v.Role {
+		case "harvester":
 			harvesters = append(harvesters, v)
-		} else {
+		case "crafter", "builder":
+			workers = append(workers, v)
+		default:
 			guards = append(guards, v)
 		}
 	}
@@ -523,6 +533,13 @@ func viewVillagers(village *models.Village) {
 		}
 	}
 
+	if len(workers) > 0 {
+		fmt.Println("\nCRAFTERS & BUILDERS:")
+		for i, v := range workers {
+			fmt.Printf("  %d. %s (%s, Lv%d) - Efficiency: %d\n", i+1, v.Name, v.Role, v.Level, v.Efficiency)
+		}
+	}
+
 	if len(guards) > 0 {
 		fmt.Println("\nGUARDS:")
 		for i, v := range guards {
@@ -609,6 +626,11 @@ func hireGuardMenu(village *models.Village, player *models.Character) {
 	fmt.Println("GUARD RECRUITMENT")
 	fmt.Println("============================================================")
 
+	if len(village.ActiveGuards) >= GuardCapacity(village) {
+		fmt.Printf("Your village can only keep %d guards. Build or upgrade a Barracks to house more.\n", GuardCapacity(village))
+		return
+	}
+
 	// Get player's gold
 	goldResource, hasGold := player.ResourceStorageMap["Gold"]
 	if !hasGold {
@@ -754,616 +776,80 @@ func craftingMenu(village *models.Village, player *models.Character) {
 }
 
 func craftPotion(village *models.Village, player *models.Character) {
-	fmt.Println("\n============================================================")
-	fmt.Println("POTION CRAFTING")
-	fmt.Println("============================================================")
-
-	potionRecipes := []struct {
-		name     string
-		size     string
-		ironCost int
-		goldCost int
-	}{
-		{"Small Health Potion", "small", 5, 10},
-		{"Medium Health Potion", "medium", 10, 20},
-		{"Large Health Potion", "large", 20, 40},
-	}
-
-	fmt.Println("\nAvailable Recipes:")
-	for i, recipe := range potionRecipes {
-		fmt.Printf("%d. %s (Iron: %d, Gold: %d)\n",
-			i+1, recipe.name, recipe.ironCost, recipe.goldCost)
-	}
-
-	fmt.Print("\nCraft (0=cancel): ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	choice := scanner.Text()
-	idx, err := strconv.Atoi(choice)
-
-	if err != nil || idx < 0 || idx > len(potionRecipes) {
-		fmt.Println("Invalid choice!")
-		return
-	}
-	if idx == 0 {
-		return
-	}
-
-	recipe := potionRecipes[idx-1]
-
-	// Check resources
-	iron := player.ResourceStorageMap["Iron"]
-	gold := player.ResourceStorageMap["Gold"]
-
-	if iron.Stock < recipe.ironCost {
-		fmt.Printf("Not enough Iron! Need %d, have %d\n", recipe.ironCost, iron.Stock)
-		return
-	}
-	if gold.Stock < recipe.goldCost {
-		fmt.Printf("Not enough Gold! Need %d, have %d\n", recipe.goldCost, gold.Stock)
-		return
-	}
-
-	// Deduct resources
-	iron.Stock -= recipe.ironCost
-	gold.Stock -= recipe.goldCost
-	player.ResourceStorageMap["Iron"] = iron
-	player.ResourceStorageMap["Gold"] = gold
-
-	// Create potion
-	potion := CreateHealthPotion(recipe.size)
-	player.Inventory = append(player.Inventory, potion)
-
-	fmt.Printf("\nCrafted %s!\n", recipe.name)
-
-	// Grant village XP
-	village.Experience += 20
-	fmt.Println("+20 Village XP")
+	recipeMenu(village, player, "POTION CRAFTING", "potions")
 }
 
 func craftArmor(village *models.Village, player *models.Character) {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Println("\n============================================================")
-		fmt.Println("ARMOR CRAFTING")
-		fmt.Println("============================================================")
-
-		fmt.Println("\nAvailable Recipes:")
-		fmt.Println("\n--- STANDARD ARMOR ---")
-		fmt.Println("1. Enhanced Armor (Iron: 30, Stone: 20)")
-		fmt.Println("   -> Random armor, Rarity 3-5, Defense focus")
-
-		fmt.Println("\n--- BEAST MATERIAL ARMOR ---")
-		fmt.Println("2. Beast Skin Armor (Iron: 20, Beast Skin: 15)")
-		fmt.Println("   -> Light armor, Rarity 4-6, Fire resistance")
-
-		fmt.Println("3. Bone Plate Armor (Iron: 25, Beast Bone: 12, Stone: 15)")
-		fmt.Println("   -> Heavy armor, Rarity 5-7, High defense")
-
-		fmt.Println("4. Tough Hide Vest (Tough Hide: 10, Beast Bone: 8)")
-		fmt.Println("   -> Medium armor, Rarity 4-6, Physical resistance")
-
-		fmt.Println("5. Ore Fragment Mail (Ore Fragment: 20, Iron: 15)")
-		fmt.Println("   -> Magic armor, Rarity 5-7, Lightning resistance")
-
-		fmt.Println("6. Fang-Studded Armor (Sharp Fang: 15, Beast Skin: 10, Iron: 20)")
-		fmt.Println("   -> Spiked armor, Rarity 6-8, Counter-damage bonus")
-
-		fmt.Println("7. Claw Guard Armor (Monster Claw: 12, Tough Hide: 8, Iron: 15)")
-		fmt.Println("   -> Elite armor, Rarity 6-8, Ice resistance")
-
-		fmt.Println("\n0 = Back")
-		fmt.Print("Choice: ")
-
-		scanner.Scan()
-		choice := scanner.Text()
-
-		switch choice {
-		case "1":
-			// Standard armor
-			iron := player.ResourceStorageMap["Iron"]
-			stone := player.ResourceStorageMap["Stone"]
-
-			if iron.Stock < 30 {
-				fmt.Printf("Not enough Iron! Need 30, have %d\n", iron.Stock)
-				continue
-			}
-			if stone.Stock < 20 {
-				fmt.Printf("Not enough Stone! Need 20, have %d\n", stone.Stock)
-				continue
-			}
-
-			iron.Stock -= 30
-			stone.Stock -= 20
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Stone"] = stone
-
-			rarity := 3 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity * 2
-			armor.StatsMod.HitPointMod += rarity
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted %s (Rarity %d)!\n", armor.Name, armor.Rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | CP: %d\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod, armor.CP)
-
-			village.Experience += 40
-			fmt.Println("+40 Village XP")
-
-		case "2":
-			// Beast Skin Armor
-			iron := player.ResourceStorageMap["Iron"]
-			beastSkin := player.ResourceStorageMap["Beast Skin"]
-
-			if iron.Stock < 20 || beastSkin.Stock < 15 {
-				fmt.Printf("Insufficient materials!\n")
-				fmt.Printf("   Need: Iron 20 (have %d), Beast Skin 15 (have %d)\n", iron.Stock, beastSkin.Stock)
-				continue
-			}
-
-			iron.Stock -= 20
-			beastSkin.Stock -= 15
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Beast Skin"] = beastSkin
-
-			rarity := 4 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity * 2
-			armor.StatsMod.HitPointMod += rarity + 3
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Beast Skin Armor (Rarity %d)!\n", rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | Fire Resistance\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod)
-
-			village.Experience += 50
-			fmt.Println("+50 Village XP")
-
-		case "3":
-			// Bone Plate Armor
-			iron := player.ResourceStorageMap["Iron"]
-			beastBone := player.ResourceStorageMap["Beast Bone"]
-			stone := player.ResourceStorageMap["Stone"]
-
-			if iron.Stock < 25 || beastBone.Stock < 12 || stone.Stock < 15 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			iron.Stock -= 25
-			beastBone.Stock -= 12
-			stone.Stock -= 15
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Beast Bone"] = beastBone
-			player.ResourceStorageMap["Stone"] = stone
-
-			rarity := 5 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity * 3
-			armor.StatsMod.HitPointMod += rarity * 2
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Bone Plate Armor (Rarity %d)!\n", rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | Heavy Armor\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod)
-
-			village.Experience += 60
-			fmt.Println("+60 Village XP")
-
-		case "4":
-			// Tough Hide Vest
-			toughHide := player.ResourceStorageMap["Tough Hide"]
-			beastBone := player.ResourceStorageMap["Beast Bone"]
-
-			if toughHide.Stock < 10 || beastBone.Stock < 8 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			toughHide.Stock -= 10
-			beastBone.Stock -= 8
-			player.ResourceStorageMap["Tough Hide"] = toughHide
-			player.ResourceStorageMap["Beast Bone"] = beastBone
-
-			rarity := 4 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity*2 + 2
-			armor.StatsMod.HitPointMod += rarity + 4
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Tough Hide Vest (Rarity %d)!\n", rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | Physical Resistance\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod)
-
-			village.Experience += 50
-			fmt.Println("+50 Village XP")
-
-		case "5":
-			// Ore Fragment Mail
-			oreFragment := player.ResourceStorageMap["Ore Fragment"]
-			iron := player.ResourceStorageMap["Iron"]
-
-			if oreFragment.Stock < 20 || iron.Stock < 15 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			oreFragment.Stock -= 20
-			iron.Stock -= 15
-			player.ResourceStorageMap["Ore Fragment"] = oreFragment
-			player.ResourceStorageMap["Iron"] = iron
-
-			rarity := 5 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity*2 + 3
-			armor.StatsMod.HitPointMod += rarity
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Ore Fragment Mail (Rarity %d)!\n", rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | Lightning Resistance\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod)
-
-			village.Experience += 60
-			fmt.Println("+60 Village XP")
-
-		case "6":
-			// Fang-Studded Armor
-			sharpFang := player.ResourceStorageMap["Sharp Fang"]
-			beastSkin := player.ResourceStorageMap["Beast Skin"]
-			iron := player.ResourceStorageMap["Iron"]
-
-			if sharpFang.Stock < 15 || beastSkin.Stock < 10 || iron.Stock < 20 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			sharpFang.Stock -= 15
-			beastSkin.Stock -= 10
-			iron.Stock -= 20
-			player.ResourceStorageMap["Sharp Fang"] = sharpFang
-			player.ResourceStorageMap["Beast Skin"] = beastSkin
-			player.ResourceStorageMap["Iron"] = iron
-
-			rarity := 6 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity*2 + 5
-			armor.StatsMod.HitPointMod += rarity * 2
-			armor.StatsMod.AttackMod += rarity // Spike bonus
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Fang-Studded Armor (Rarity %d)!\n", rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | Attack: +%d (Counter-damage)\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod, armor.StatsMod.AttackMod)
-
-			village.Experience += 70
-			fmt.Println("+70 Village XP")
-
-		case "7":
-			// Claw Guard Armor
-			monsterClaw := player.ResourceStorageMap["Monster Claw"]
-			toughHide := player.ResourceStorageMap["Tough Hide"]
-			iron := player.ResourceStorageMap["Iron"]
-
-			if monsterClaw.Stock < 12 || toughHide.Stock < 8 || iron.Stock < 15 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			monsterClaw.Stock -= 12
-			toughHide.Stock -= 8
-			iron.Stock -= 15
-			player.ResourceStorageMap["Monster Claw"] = monsterClaw
-			player.ResourceStorageMap["Tough Hide"] = toughHide
-			player.ResourceStorageMap["Iron"] = iron
-
-			rarity := 6 + rand.Intn(3)
-			armor := GenerateItem(rarity)
-			armor.StatsMod.DefenseMod += rarity*3 + 2
-			armor.StatsMod.HitPointMod += rarity*2 + 3
-			armor.CP = armor.StatsMod.AttackMod + armor.StatsMod.DefenseMod + armor.StatsMod.HitPointMod
-
-			EquipBestItem(armor, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Claw Guard Armor (Rarity %d)!\n", rarity)
-			fmt.Printf("   Defense: +%d | HP: +%d | Ice Resistance (Elite)\n",
-				armor.StatsMod.DefenseMod, armor.StatsMod.HitPointMod)
-
-			village.Experience += 70
-			fmt.Println("+70 Village XP")
+	recipeMenu(village, player, "ARMOR CRAFTING", "armor")
+}
 
-		case "0":
-			return
+func craftWeapon(village *models.Village, player *models.Character) {
+	recipeMenu(village, player, "WEAPON CRAFTING", "weapons")
+}
 
-		default:
-			fmt.Println("Invalid choice")
-		}
-	}
+func craftSkillScrolls(village *models.Village, player *models.Character) {
+	recipeMenu(village, player, "SKILL SCROLL CRAFTING", "skill_scrolls")
 }
 
-func craftWeapon(village *models.Village, player *models.Character) {
+// recipeMenu lists the known recipes of one type and crafts the ones picked
+// until the player backs out.
+func recipeMenu(village *models.Village, player *models.Character, title, recipeType string) {
 	scanner := bufio.NewScanner(os.Stdin)
 
 	for {
 		fmt.Println("\n============================================================")
-		fmt.Println("WEAPON CRAFTING")
+		fmt.Println(title)
 		fmt.Println("============================================================")
+		fmt.Printf("Crafter skill: %d (better villagers make finer work)\n", CrafterSkill(village))
 
+		recipes := KnownRecipesOfType(player, recipeType)
 		fmt.Println("\nAvailable Recipes:")
-		fmt.Println("\n--- STANDARD WEAPONS ---")
-		fmt.Println("1. Enhanced Weapon (Iron: 40, Gold: 30)")
-		fmt.Println("   -> Random weapon, Rarity 4-6, Attack focus")
-
-		fmt.Println("\n--- BEAST MATERIAL WEAPONS ---")
-		fmt.Println("2. Beast Claw Blade (Iron: 25, Monster Claw: 15, Sharp Fang: 10)")
-		fmt.Println("   -> Slashing weapon, Rarity 5-7, High attack + bleed bonus")
-
-		fmt.Println("3. Bone Crusher Mace (Iron: 30, Beast Bone: 20, Stone: 15)")
-		fmt.Println("   -> Crushing weapon, Rarity 5-7, Attack + stun chance")
-
-		fmt.Println("4. Hide-Wrapped Axe (Iron: 20, Tough Hide: 12, Lumber: 25)")
-		fmt.Println("   -> Balanced weapon, Rarity 4-6, Attack + HP bonus")
-
-		fmt.Println("5. Ore Fragment Sword (Iron: 35, Ore Fragment: 25, Gold: 20)")
-		fmt.Println("   -> Magical weapon, Rarity 6-8, High attack + elemental damage")
-
-		fmt.Println("6. Fang Spear (Sharp Fang: 18, Beast Bone: 15, Iron: 20)")
-		fmt.Println("   -> Piercing weapon, Rarity 5-7, Attack + critical bonus")
-
-		fmt.Println("7. Composite War Hammer (Beast Skin: 10, Ore Fragment: 15, Iron: 25, Stone: 20)")
-		fmt.Println("   -> Elite weapon, Rarity 6-8, Massive attack + durability")
-
-		fmt.Println("\n0 = Back")
-		fmt.Print("Choice: ")
-
-		scanner.Scan()
-		choice := scanner.Text()
-
-		switch choice {
-		case "1":
-			// Standard Enhanced Weapon
-			iron := player.ResourceStorageMap["Iron"]
-			gold := player.ResourceStorageMap["Gold"]
-
-			if iron.Stock < 40 {
-				fmt.Printf("Not enough Iron! Need 40, have %d\n", iron.Stock)
-				continue
+		for i, r := range recipes {
+			fmt.Printf("%d. %s (%s)", i+1, r.Name, RecipeCostText(r))
+			if r.Description != "" {
+				fmt.Printf(" -> %s", r.Description)
 			}
-			if gold.Stock < 30 {
-				fmt.Printf("Not enough Gold! Need 30, have %d\n", gold.Stock)
-				continue
+			if reason := RecipeLocked(player, village, r); reason != "" {
+				fmt.Printf(" [%s]", reason)
 			}
+			fmt.Println()
+		}
 
-			iron.Stock -= 40
-			gold.Stock -= 30
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Gold"] = gold
-
-			rarity := 4 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity * 3
-			weapon.StatsMod.HitPointMod += rarity / 2
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Enhanced %s (Rarity %d)!\n", weapon.Name, rarity)
-			fmt.Printf("   Attack: +%d | HP: +%d | CP: %d\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.HitPointMod, weapon.CP)
-
-			village.Experience += 50
-			fmt.Println("+50 Village XP")
-
-		case "2":
-			// Beast Claw Blade
-			iron := player.ResourceStorageMap["Iron"]
-			monsterClaw := player.ResourceStorageMap["Monster Claw"]
-			sharpFang := player.ResourceStorageMap["Sharp Fang"]
-
-			if iron.Stock < 25 || monsterClaw.Stock < 15 || sharpFang.Stock < 10 {
-				fmt.Printf("Insufficient materials!\n")
-				fmt.Printf("   Need: Iron 25 (have %d), Monster Claw 15 (have %d), Sharp Fang 10 (have %d)\n",
-					iron.Stock, monsterClaw.Stock, sharpFang.Stock)
-				continue
-			}
-
-			iron.Stock -= 25
-			monsterClaw.Stock -= 15
-			sharpFang.Stock -= 10
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Monster Claw"] = monsterClaw
-			player.ResourceStorageMap["Sharp Fang"] = sharpFang
-
-			rarity := 5 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity * 4
-			weapon.StatsMod.HitPointMod += rarity
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Beast Claw Blade (Rarity %d)!\n", rarity)
-			fmt.Printf("   Attack: +%d | HP: +%d | Bleed Bonus\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.HitPointMod)
-
-			village.Experience += 60
-			fmt.Println("+60 Village XP")
-
-		case "3":
-			// Bone Crusher Mace
-			iron := player.ResourceStorageMap["Iron"]
-			beastBone := player.ResourceStorageMap["Beast Bone"]
-			stone := player.ResourceStorageMap["Stone"]
-
-			if iron.Stock < 30 || beastBone.Stock < 20 || stone.Stock < 15 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			iron.Stock -= 30
-			beastBone.Stock -= 20
-			stone.Stock -= 15
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Beast Bone"] = beastBone
-			player.ResourceStorageMap["Stone"] = stone
-
-			rarity := 5 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity*3 + 5
-			weapon.StatsMod.DefenseMod += rarity
-			weapon.StatsMod.HitPointMod += rarity + 2
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Bone Crusher Mace (Rarity %d)!\n", rarity)
-			fmt.Printf("   Attack: +%d | Defense: +%d | HP: +%d | Stun Chance\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.DefenseMod, weapon.StatsMod.HitPointMod)
-
-			village.Experience += 60
-			fmt.Println("+60 Village XP")
-
-		case "4":
-			// Hide-Wrapped Axe
-			iron := player.ResourceStorageMap["Iron"]
-			toughHide := player.ResourceStorageMap["Tough Hide"]
-			lumber := player.ResourceStorageMap["Lumber"]
-
-			if iron.Stock < 20 || toughHide.Stock < 12 || lumber.Stock < 25 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			iron.Stock -= 20
-			toughHide.Stock -= 12
-			lumber.Stock -= 25
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Tough Hide"] = toughHide
-			player.ResourceStorageMap["Lumber"] = lumber
-
-			rarity := 4 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity*3 + 3
-			weapon.StatsMod.HitPointMod += rarity*2 + 5
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Hide-Wrapped Axe (Rarity %d)!\n", rarity)
-			fmt.Printf("   Attack: +%d | HP: +%d | Balanced\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.HitPointMod)
-
-			village.Experience += 55
-			fmt.Println("+55 Village XP")
-
-		case "5":
-			// Ore Fragment Sword
-			iron := player.ResourceStorageMap["Iron"]
-			oreFragment := player.ResourceStorageMap["Ore Fragment"]
-			gold := player.ResourceStorageMap["Gold"]
-
-			if iron.Stock < 35 || oreFragment.Stock < 25 || gold.Stock < 20 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			iron.Stock -= 35
-			oreFragment.Stock -= 25
-			gold.Stock -= 20
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Ore Fragment"] = oreFragment
-			player.ResourceStorageMap["Gold"] = gold
-
-			rarity := 6 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity*4 + 5
-			weapon.StatsMod.HitPointMod += rarity + 3
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Ore Fragment Sword (Rarity %d)!\n", rarity)
-			fmt.Printf("   Attack: +%d | HP: +%d | Elemental Damage (Magical)\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.HitPointMod)
-
-			village.Experience += 70
-			fmt.Println("+70 Village XP")
-
-		case "6":
-			// Fang Spear
-			sharpFang := player.ResourceStorageMap["Sharp Fang"]
-			beastBone := player.ResourceStorageMap["Beast Bone"]
-			iron := player.ResourceStorageMap["Iron"]
-
-			if sharpFang.Stock < 18 || beastBone.Stock < 15 || iron.Stock < 20 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			sharpFang.Stock -= 18
-			beastBone.Stock -= 15
-			iron.Stock -= 20
-			player.ResourceStorageMap["Sharp Fang"] = sharpFang
-			player.ResourceStorageMap["Beast Bone"] = beastBone
-			player.ResourceStorageMap["Iron"] = iron
-
-			rarity := 5 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity*3 + 7
-			weapon.StatsMod.HitPointMod += rarity
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Fang Spear (Rarity %d)!\n", rarity)
-			fmt.Printf("   Attack: +%d | HP: +%d | Critical Bonus (Piercing)\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.HitPointMod)
-
-			village.Experience += 65
-			fmt.Println("+65 Village XP")
-
-		case "7":
-			// Composite War Hammer
-			beastSkin := player.ResourceStorageMap["Beast Skin"]
-			oreFragment := player.ResourceStorageMap["Ore Fragment"]
-			iron := player.ResourceStorageMap["Iron"]
-			stone := player.ResourceStorageMap["Stone"]
-
-			if beastSkin.Stock < 10 || oreFragment.Stock < 15 || iron.Stock < 25 || stone.Stock < 20 {
-				fmt.Printf("Insufficient materials!\n")
-				continue
-			}
-
-			beastSkin.Stock -= 10
-			oreFragment.Stock -= 15
-			iron.Stock -= 25
-			stone.Stock -= 20
-			player.ResourceStorageMap["Beast Skin"] = beastSkin
-			player.ResourceStorageMap["Ore Fragment"] = oreFragment
-			player.ResourceStorageMap["Iron"] = iron
-			player.ResourceStorageMap["Stone"] = stone
-
-			rarity := 6 + rand.Intn(3)
-			weapon := GenerateItem(rarity)
-			weapon.StatsMod.AttackMod += rarity*5 + 3
-			weapon.StatsMod.DefenseMod += rarity + 2
-			weapon.StatsMod.HitPointMod += rarity*2 + 5
-			weapon.CP = weapon.StatsMod.AttackMod + weapon.StatsMod.DefenseMod + weapon.StatsMod.HitPointMod
-
-			EquipBestItem(weapon, &player.EquipmentMap, &player.Inventory)
-			fmt.Printf("\nCrafted Composite War Hammer (Rarity %d)!\n", rarity)
-			fmt.Printf("   Attack: +%d | Defense: +%d | HP: +%d | Elite (Durability)\n",
-				weapon.StatsMod.AttackMod, weapon.StatsMod.DefenseMod, weapon.StatsMod.HitPointMod)
-
-			village.Experience += 80
-			fmt.Println("+80 Village XP")
-
-		case "0":
+		fmt.Print("\nCraft (0=back): ")
+		scanner.Scan()
+		idx, err := strconv.Atoi(scanner.Text())
+		if err != nil || idx < 0 || idx > len(recipes) {
+			fmt.Println("Invalid choice!")
+			continue
+		}
+		if idx == 0 {
 			return
+		}
 
-		default:
-			fmt.Println("Invalid choice")
+		result, err := CraftRecipe(player, village, recipes[idx-1])
+		if err != nil {
+			fmt.Printf("Can't craft: %v\n", err)
+			continue
+		}
+		switch {
+		case result.Skill != nil:
+			fmt.Printf("\nCrafted %s!\n", result.Recipe.Name)
+			fmt.Printf("You have learned %s!\n", result.Skill.Name)
+		case result.Trap != nil:
+			fmt.Printf("\nCrafted %s %s! Damage: %d\n", result.Quality.Name, result.Recipe.Name, result.Trap.Damage)
+			fmt.Printf("Will last for %d monster tides\n", result.Trap.Duration)
+		case result.Item != nil && result.Item.ItemType == "equipment":
+			item := result.Item
+			fmt.Printf("\nCrafted %s %s: %s (Rarity %d)!\n", result.Quality.Name, result.Recipe.Name, item.Name, item.Rarity)
+			fmt.Printf("   Attack: +%d | Defense: +%d | HP: +%d | CP: %d\n",
+				item.StatsMod.AttackMod, item.StatsMod.DefenseMod, item.StatsMod.HitPointMod, item.CP)
+		case result.Item != nil:
+			fmt.Printf("\nCrafted %s! (heals %d)\n", result.Item.Name, result.Item.Consumable.Value)
 		}
+		if result.Item != nil && !result.Stored {
+			fmt.Println("Your inventory is full, so it was left behind.")
+		}
+		fmt.Printf("+%d Village XP\n", result.Recipe.VillageXP)
 	}
 }
 
@@ -1398,274 +884,33 @@ func upgradeSkillMenu(village *models.Village, player *models.Character) {
 	}
 
 	skillIdx := idx - 1
-	skill := &player.LearnedSkills[skillIdx]
-
-	fmt.Printf("\nUpgrade %s\n", skill.Name)
-	fmt.Println("Cost: Gold: 50, Iron: 25")
-	fmt.Println("Effect: +5 Damage (or +5 Healing), -2 Resource Cost")
+	fmt.Printf("\nUpgrade %s\n", player.LearnedSkills[skillIdx].Name)
+	recipes := KnownRecipesOfType(player, "skill_upgrades")
+	for i, r := range recipes {
+		fmt.Printf("%d. %s (%s) -> %s", i+1, r.Name, RecipeCostText(r), r.SkillUpgrade.Description)
+		if reason := RecipeLocked(player, village, r); reason != "" {
+			fmt.Printf(" [%s]", reason)
+		}
+		fmt.Println()
+	}
 
-	fmt.Print("\nUpgrade? (y/n): ")
+	fmt.Print("\nUpgrade with (0=cancel): ")
 	scanner.Scan()
-	confirm := scanner.Text()
-
-	if confirm != "y" && confirm != "Y" {
+	idx, err = strconv.Atoi(scanner.Text())
+	if err != nil || idx < 1 || idx > len(recipes) {
 		return
 	}
 
-	// Check resources
-	iron := player.ResourceStorageMap["Iron"]
-	gold := player.ResourceStorageMap["Gold"]
-
-	if iron.Stock < 25 {
-		fmt.Printf("Not enough Iron! Need 25, have %d\n", iron.Stock)
-		return
-	}
-	if gold.Stock < 50 {
-		fmt.Printf("Not enough Gold! Need 50, have %d\n", gold.Stock)
+	r := recipes[idx-1]
+	lines, err := CraftSkillUpgrade(player, village, r, skillIdx)
+	if err != nil {
+		fmt.Printf("Can't upgrade: %v\n", err)
 		return
 	}
-
-	// Deduct resources
-	iron.Stock -= 25
-	gold.Stock -= 50
-	player.ResourceStorageMap["Iron"] = iron
-	player.ResourceStorageMap["Gold"] = gold
-
-	// Upgrade skill
-	if skill.Damage > 0 {
-		skill.Damage += 5
-		fmt.Printf("%s damage increased by 5! (Now: %d)\n", skill.Name, skill.Damage)
-	} else if skill.Damage < 0 {
-		skill.Damage -= 5
-		fmt.Printf("%s healing increased by 5! (Now: %d)\n", skill.Name, -skill.Damage)
-	}
-
-	if skill.ManaCost > 2 {
-		skill.ManaCost -= 2
-		fmt.Printf("Mana cost reduced by 2! (Now: %d)\n", skill.ManaCost)
-	}
-	if skill.StaminaCost > 2 {
-		skill.StaminaCost -= 2
-		fmt.Printf("Stamina cost reduced by 2! (Now: %d)\n", skill.StaminaCost)
-	}
-
-	// Grant village XP
-	village.Experience += 60
-	fmt.Println("+60 Village XP")
-}
-
-func craftSkillScrolls(village *models.Village, player *models.Character) {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Println("\n============================================================")
-		fmt.Println("SKILL SCROLL CRAFTING")
-		fmt.Println("============================================================")
-
-		fmt.Println("\nCraft skill scrolls using beast materials!")
-		fmt.Println("Learn skills without defeating Skill Guardians")
-
-		fmt.Println("Available Skill Scrolls:")
-		fmt.Println("\n--- OFFENSIVE SKILLS ---")
-		fmt.Println("1. Fireball Scroll (Ore Fragment: 15, Sharp Fang: 10, Gold: 30)")
-		fmt.Println("   -> Fire damage + burn effect")
-
-		fmt.Println("2. Ice Shard Scroll (Beast Skin: 12, Ore Fragment: 10, Iron: 20)")
-		fmt.Println("   -> Ice damage")
-
-		fmt.Println("3. Lightning Bolt Scroll (Ore Fragment: 20, Monster Claw: 15, Gold: 40)")
-		fmt.Println("   -> Lightning damage + stun (EXPENSIVE)")
-
-		fmt.Println("4. Power Strike Scroll (Beast Bone: 10, Iron: 15)")
-		fmt.Println("   -> Physical stamina attack")
-
-		fmt.Println("5. Poison Blade Scroll (Beast Skin: 10, Sharp Fang: 12, Iron: 15)")
-		fmt.Println("   -> Poison damage over time")
-
-		fmt.Println("\n--- SUPPORT SKILLS ---")
-		fmt.Println("6. Heal Scroll (Beast Skin: 15, Beast Bone: 10, Gold: 25)")
-		fmt.Println("   -> Restore HP")
-
-		fmt.Println("7. Regeneration Scroll (Ore Fragment: 12, Beast Skin: 15, Gold: 30)")
-		fmt.Println("   -> Heal over time")
-
-		fmt.Println("8. Shield Wall Scroll (Tough Hide: 15, Beast Bone: 12, Stone: 20)")
-		fmt.Println("   -> Defense buff")
-
-		fmt.Println("9. Battle Cry Scroll (Sharp Fang: 15, Beast Bone: 10, Iron: 20)")
-		fmt.Println("   -> Attack buff")
-
-		fmt.Println("\n--- UTILITY SKILLS ---")
-		fmt.Println("10. Tracking Scroll (Beast Bone: 8, Beast Skin: 8)")
-		fmt.Println("    -> Choose targets in combat")
-
-		fmt.Println("\n0 = Back")
-		fmt.Print("Choice: ")
-
-		scanner.Scan()
-		choice := scanner.Text()
-
-		var skillToLearn models.Skill
-		var materialsNeeded map[string]int
-		var villageXP int
-
-		switch choice {
-		case "1":
-			// Fireball
-			skillToLearn = data.AvailableSkills[0]
-			materialsNeeded = map[string]int{
-				"Ore Fragment": 15,
-				"Sharp Fang":   10,
-				"Gold":         30,
-			}
-			villageXP = 100
-
-		case "2":
-			// Ice Shard
-			skillToLearn = data.AvailableSkills[1]
-			materialsNeeded = map[string]int{
-				"Beast Skin":   12,
-				"Ore Fragment": 10,
-				"Iron":         20,
-			}
-			villageXP = 90
-
-		case "3":
-			// Lightning Bolt
-			skillToLearn = data.AvailableSkills[2]
-			materialsNeeded = map[string]int{
-				"Ore Fragment": 20,
-				"Monster Claw": 15,
-				"Gold":         40,
-			}
-			villageXP = 120
-
-		case "4":
-			// Power Strike
-			skillToLearn = data.AvailableSkills[4]
-			materialsNeeded = map[string]int{
-				"Beast Bone": 10,
-				"Iron":       15,
-			}
-			villageXP = 70
-
-		case "5":
-			// Poison Blade
-			skillToLearn = data.AvailableSkills[7]
-			materialsNeeded = map[string]int{
-				"Beast Skin": 10,
-				"Sharp Fang": 12,
-				"Iron":       15,
-			}
-			villageXP = 85
-
-		case "6":
-			// Heal
-			skillToLearn = data.AvailableSkills[3]
-			materialsNeeded = map[string]int{
-				"Beast Skin": 15,
-				"Beast Bone": 10,
-				"Gold":       25,
-			}
-			villageXP = 95
-
-		case "7":
-			// Regeneration
-			skillToLearn = data.AvailableSkills[8]
-			materialsNeeded = map[string]int{
-				"Ore Fragment": 12,
-				"Beast Skin":   15,
-				"Gold":         30,
-			}
-			villageXP = 105
-
-		case "8":
-			// Shield Wall
-			skillToLearn = data.AvailableSkills[5]
-			materialsNeeded = map[string]int{
-				"Tough Hide": 15,
-				"Beast Bone": 12,
-				"Stone":      20,
-			}
-			villageXP = 90
-
-		case "9":
-			// Battle Cry
-			skillToLearn = data.AvailableSkills[6]
-			materialsNeeded = map[string]int{
-				"Sharp Fang": 15,
-				"Beast Bone": 10,
-				"Iron":       20,
-			}
-			villageXP = 85
-
-		case "10":
-			// Tracking
-			skillToLearn = data.AvailableSkills[9]
-			materialsNeeded = map[string]int{
-				"Beast Bone": 8,
-				"Beast Skin": 8,
-			}
-			villageXP = 60
-
-		case "0":
-			return
-
-		default:
-			fmt.Println("Invalid choice")
-			continue
-		}
-
-		// Check if player already has this skill
-		hasSkill := false
-		for _, skill := range player.LearnedSkills {
-			if skill.Name == skillToLearn.Name {
-				hasSkill = true
-				break
-			}
-		}
-
-		if hasSkill {
-			fmt.Printf("\nYou already know %s!\n", skillToLearn.Name)
-			continue
-		}
-
-		// Check if player has all required materials
-		canCraft := true
-		for material, qty := range materialsNeeded {
-			resource, exists := player.ResourceStorageMap[material]
-			if !exists || resource.Stock < qty {
-				canCraft = false
-				have := 0
-				if exists {
-					have = resource.Stock
-				}
-				fmt.Printf("Not enough %s! Need %d, have %d\n", material, qty, have)
-			}
-		}
-
-		if !canCraft {
-			continue
-		}
-
-		// Deduct materials
-		for material, qty := range materialsNeeded {
-			resource := player.ResourceStorageMap[material]
-			resource.Stock -= qty
-			player.ResourceStorageMap[material] = resource
-		}
-
-		// Learn the skill
-		player.LearnedSkills = append(player.LearnedSkills, skillToLearn)
-
-		fmt.Printf("\nCrafted %s Scroll!\n", skillToLearn.Name)
-		fmt.Printf("You have learned %s!\n", skillToLearn.Name)
-		fmt.Printf("   %s\n", skillToLearn.Description)
-
-		// Grant village XP
-		village.Experience += villageXP
-		fmt.Printf("+%d Village XP\n", villageXP)
+	for _, line := range lines {
+		fmt.Println(line)
 	}
+	fmt.Printf("+%d Village XP\n", r.VillageXP)
 }
 
 func buildDefenseMenu(village *models.Village, player *models.Character) {
@@ -1704,27 +949,10 @@ func buildWallsMenu(village *models.Village, player *models.Character) {
 	fmt.Println("BUILD WALLS & TOWERS")
 	fmt.Println("============================================================")
 
-	defenseOptions := []struct {
-		name    string
-		lumber  int
-		stone   int
-		iron    int
-		defense int
-		attack  int
-		dtype   string
-	}{
-		{"Wooden Wall", 50, 20, 0, 10, 0, "wall"},
-		{"Stone Wall", 30, 60, 10, 25, 0, "wall"},
-		{"Iron Wall", 20, 80, 40, 40, 0, "wall"},
-		{"Guard Tower", 40, 40, 30, 15, 20, "tower"},
-		{"Arrow Tower", 30, 50, 40, 10, 35, "tower"},
-		{"Iron Gate", 20, 50, 50, 30, 10, "wall"},
-	}
-
 	fmt.Println("\nAvailable Structures:")
-	for i, def := range defenseOptions {
-		fmt.Printf("%d. %s (Lumber:%d Stone:%d Iron:%d) -> Defense:+%d Attack:+%d\n",
-			i+1, def.name, def.lumber, def.stone, def.iron, def.defense, def.attack)
+	for i, bp := range DefenseBlueprints {
+		fmt.Printf("%d. %s (%s) -> Defense:+%d Attack:+%d\n",
+			i+1, bp.Name, MaterialsText(bp.Cost), bp.Defense, bp.Attack)
 	}
 
 	fmt.Print("\nBuild (0=cancel): ")
@@ -1733,7 +961,7 @@ func buildWallsMenu(village *models.Village, player *models.Character) {
 	choice := scanner.Text()
 	idx, err := strconv.Atoi(choice)
 
-	if err != nil || idx < 0 || idx > len(defenseOptions) {
+	if err != nil || idx < 0 || idx > len(DefenseBlueprints) {
 		fmt.Println("Invalid choice!")
 		return
 	}
@@ -1741,152 +969,39 @@ func buildWallsMenu(village *models.Village, player *models.Character) {
 		return
 	}
 
-	selected := defenseOptions[idx-1]
-
-	// Check resources
-	lumber := player.ResourceStorageMap["Lumber"]
-	stone := player.ResourceStorageMap["Stone"]
-	iron := player.ResourceStorageMap["Iron"]
-
-	if lumber.Stock < selected.lumber {
-		fmt.Printf("Not enough Lumber! Need %d, have %d\n", selected.lumber, lumber.Stock)
-		return
-	}
-	if stone.Stock < selected.stone {
-		fmt.Printf("Not enough Stone! Need %d, have %d\n", selected.stone, stone.Stock)
-		return
-	}
-	if iron.Stock < selected.iron {
-		fmt.Printf("Not enough Iron! Need %d, have %d\n", selected.iron, iron.Stock)
+	selected := DefenseBlueprints[idx-1]
+	if err := BuildDefense(player, village, selected); err != nil {
+		fmt.Println(err)
 		return
 	}
 
-	// Deduct resources
-	lumber.Stock -= selected.lumber
-	stone.Stock -= selected.stone
-	iron.Stock -= selected.iron
-	player.ResourceStorageMap["Lumber"] = lumber
-	player.ResourceStorageMap["Stone"] = stone
-	player.ResourceStorageMap["Iron"] = iron
-
-	// Add defense
-	newDefense := models.Defense{
-		Name:        selected.name,
-		Level:       1,
-		Defense:     selected.defense,
-		AttackPower: selected.attack,
-		Range:       10,
-		Built:       true,
-		Type:        selected.dtype,
-	}
-	village.Defenses = append(village.Defenses, newDefense)
-	village.DefenseLevel += 1
-
-	fmt.Printf("\nBuilt %s!\n", selected.name)
+	fmt.Printf("\nBuilt %s!\n", selected.Name)
 	fmt.Printf("Village Defense Level increased to %d\n", village.DefenseLevel)
-
-	// Grant village XP
-	village.Experience += 30
 	fmt.Println("+30 Village XP")
 }
 
-func craftTrapsMenu(village *models.Village, player *models.Character) {
+func villageBuildingsMenu(village *models.Village, player *models.Character) {
 	fmt.Println("\n============================================================")
-	fmt.Println("CRAFT TRAPS")
+	fmt.Println("VILLAGE BUILDINGS")
 	fmt.Println("============================================================")
 
-	trapOptions := []struct {
-		name        string
-		trapType    string
-		materials   map[string]int
-		damage      int
-		duration    int
-		triggerRate int
-	}{
-		{
-			name:     "Spike Trap",
-			trapType: "spike",
-			materials: map[string]int{
-				"Iron":       10,
-				"Beast Bone": 5,
-			},
-			damage:      15,
-			duration:    3,
-			triggerRate: 60,
-		},
-		{
-			name:     "Fire Trap",
-			trapType: "fire",
-			materials: map[string]int{
-				"Iron":         15,
-				"Ore Fragment": 8,
-				"Sharp Fang":   5,
-			},
-			damage:      25,
-			duration:    2,
-			triggerRate: 50,
-		},
-		{
-			name:     "Ice Trap",
-			trapType: "ice",
-			materials: map[string]int{
-				"Iron":         12,
-				"Ore Fragment": 10,
-				"Beast Skin":   8,
-			},
-			damage:      20,
-			duration:    3,
-			triggerRate: 55,
-		},
-		{
-			name:     "Poison Trap",
-			trapType: "poison",
-			materials: map[string]int{
-				"Beast Skin":   10,
-				"Sharp Fang":   8,
-				"Monster Claw": 5,
-			},
-			damage:      18,
-			duration:    4,
-			triggerRate: 65,
-		},
-		{
-			name:     "Barricade Trap",
-			trapType: "spike",
-			materials: map[string]int{
-				"Lumber":     30,
-				"Tough Hide": 6,
-				"Beast Bone": 8,
-			},
-			damage:      30,
-			duration:    2,
-			triggerRate: 70,
-		},
-	}
-
-	fmt.Println("\nAvailable Traps:")
-	for i, trap := range trapOptions {
-		fmt.Printf("\n%d. %s (Damage: %d, Duration: %d waves, Trigger: %d%%)\n",
-			i+1, trap.name, trap.damage, trap.duration, trap.triggerRate)
-		fmt.Print("   Costs: ")
-		first := true
-		for mat, qty := range trap.materials {
-			if !first {
-				fmt.Print(", ")
-			}
-			fmt.Printf("%s:%d", mat, qty)
-			first = false
+	for i, bp := range BuildingBlueprints {
+		level := PlannedBuildingLevel(village, bp.Name)
+		fmt.Printf("%d. %s (Level %d/%d) - %s\n", i+1, bp.Name, level, MaxBuildingLevel, bp.Description)
+		switch {
+		case village.Level < bp.MinVillageLevel:
+			fmt.Printf("   Needs village level %d\n", bp.MinVillageLevel)
+		case level < MaxBuildingLevel:
+			fmt.Printf("   Next level: %s | Upkeep: %s per tick\n",
+				MaterialsText(BuildingCost(bp, level+1)), MaterialsText(BuildingUpkeep(bp, level+1)))
 		}
-		fmt.Println()
 	}
 
-	fmt.Print("\nCraft (0=cancel): ")
+	fmt.Print("\nBuild or upgrade (0=cancel): ")
 	scanner := bufio.NewScanner(os.Stdin)
 	scanner.Scan()
-	choice := scanner.Text()
-	idx, err := strconv.Atoi(choice)
-
-	if err != nil || idx < 0 || idx > len(trapOptions) {
+	idx, err := strconv.Atoi(scanner.Text())
+	if err != nil || idx < 0 || idx > len(BuildingBlueprints) {
 		fmt.Println("Invalid choice!")
 		return
 	}
@@ -1894,45 +1009,16 @@ func craftTrapsMenu(village *models.Village, player *models.Character) {
 		return
 	}
 
-	selected := trapOptions[idx-1]
-
-	// Check if player has all required materials
-	for material, qty := range selected.materials {
-		resource, exists := player.ResourceStorageMap[material]
-		if !exists || resource.Stock < qty {
-			have := 0
-			if exists {
-				have = resource.Stock
-			}
-			fmt.Printf("Not enough %s! Need %d, have %d\n", material, qty, have)
-			return
-		}
-	}
-
-	// Deduct materials
-	for material, qty := range selected.materials {
-		resource := player.ResourceStorageMap[material]
-		resource.Stock -= qty
-		player.ResourceStorageMap[material] = resource
-	}
-
-	// Create trap
-	newTrap := models.Trap{
-		Name:        selected.name,
-		Type:        selected.trapType,
-		Damage:      selected.damage,
-		Duration:    selected.duration,
-		Remaining:   selected.duration,
-		TriggerRate: selected.triggerRate,
+	line, err := BuildBuilding(player, village, BuildingBlueprints[idx-1])
+	if err != nil {
+		fmt.Println(err)
+		return
 	}
-	village.Traps = append(village.Traps, newTrap)
-
-	fmt.Printf("\nCrafted %s!\n", selected.name)
-	fmt.Printf("Will last for %d monster tides\n", selected.duration)
+	fmt.Println("\n" + line)
+}
 
-	// Grant village XP
-	village.Experience += 35
-	fmt.Println("+35 Village XP")
+func craftTrapsMenu(village *models.Village, player *models.Character) {
+	recipeMenu(village, player, "CRAFT TRAPS", "traps")
 }
 
 func viewDefenses(village *models.Village) {
@@ -2016,6 +1102,13 @@ func checkMonsterTide(village *models.Village) {
 			len(village.ActiveGuards))
 	}
 
+	if village.LastSiege != nil {
+		fmt.Println("\nLAST SIEGE")
+		for _, line := range SiegeSummary(village.LastSiege) {
+			fmt.Println(line)
+		}
+	}
+
 	fmt.Println("\nPrepare your defenses by:")
 	fmt.Println("  - Building more defenses")
 	fmt.Println("  - Crafting traps")
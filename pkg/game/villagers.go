@@ -0,0 +1,306 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"rpg-game/pkg/models"
+)
+
+// Villager traits.
+const (
+	TraitDiligent = "diligent"
+	TraitLazy     = "lazy"
+	TraitBrave    = "brave"
+)
+
+// VillagerTrait changes how a villager works, learns and weathers tides.
+type VillagerTrait struct {
+	Name          string
+	Description   string
+	WorkPercent   int // added to the villager's output
+	XPPercent     int // added to XP the villager earns
+	InjuryPercent int // chance a blow that lands actually injures them
+}
+
+// VillagerTraits lists the traits a villager can be born with.
+var VillagerTraits = []VillagerTrait{
+	{TraitDiligent, "works harder and learns faster", 25, 25, 100},
+	{TraitLazy, "does as little as they can get away with", -25, -25, 100},
+	{TraitBrave, "shrugs off half the blows a tide lands", 0, 0, 50},
+}
+
+// Villager simulation tuning. Food and morale change once per village
+// manager tick.
+const (
+	MinMorale        = -50
+	MaxMorale        = 50
+	MoraleFedGain    = 2  // per tick with everyone fed
+	MoraleHungerLoss = 10 // per tick the village runs out of food
+	MoraleCrowdLoss  = 3  // per tick with more villagers than housing
+	MoraleGriefLoss  = 10 // when a villager dies
+	MoraleTideWin    = 5
+	MoraleTideLoss   = 10
+
+	FoodPerVillager     = 1
+	BaseFoodPerTick     = 4 // plus FoodPerVillageLevel per village level
+	FoodPerVillageLevel = 2
+	BaseFoodStorage     = 20 // plus FoodStoragePerLevel per village level
+	FoodStoragePerLevel = 10
+
+	BaseHousing            = 3 // plus HousingPerVillageLevel per village level
+	HousingPerVillageLevel = 2
+	GrowthChance           = 25 // % per tick while fed, housed and content
+	GrowthFoodCost         = 10
+
+	MaxVillagerLevel   = 20
+	VillagerXPPerLevel = 50
+	HarvestXP          = 5 // per harvest collected
+	JobXP              = 3 // per tick spent on a job
+	TideGuardXP        = 20
+
+	TideInjuryChance = 30 // % per breach that a villager is hit
+	InjuryTicks      = 3
+)
+
+// FindVillagerTrait looks up a trait by name.
+func FindVillagerTrait(name string) (VillagerTrait, bool) {
+	for _, t := range VillagerTraits {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return VillagerTrait{Name: name, InjuryPercent: 100}, false
+}
+
+// RollVillagerTrait picks a trait for a new villager. One in four has none.
+func RollVillagerTrait() string {
+	roll := rand.Intn(len(VillagerTraits) + 1)
+	if roll == len(VillagerTraits) {
+		return ""
+	}
+	return VillagerTraits[roll].Name
+}
+
+// RollVillagerRole picks the role of a newly arrived villager.
+func RollVillagerRole() string {
+	switch roll := rand.Intn(100); {
+	case roll < 30:
+		return "guard"
+	case roll < 40:
+		return "crafter"
+	case roll < 50:
+		return "builder"
+	}
+	return "harvester"
+}
+
+// VillagerOutput is how much a villager gets done each harvest or job tick:
+// efficiency plus half their level, scaled by morale (up to ±25%) and trait.
+// Injured villagers do nothing.
+func VillagerOutput(v models.Villager) int {
+	if v.InjuredTicks > 0 {
+		return 0
+	}
+	trait, _ := FindVillagerTrait(v.Trait)
+	percent := 100 + v.Morale/2 + trait.WorkPercent
+	return max((v.Efficiency+v.Level/2)*percent/100, 1)
+}
+
+// VillagerXPToLevel is the XP a villager needs to leave their level.
+func VillagerXPToLevel(level int) int {
+	return level * VillagerXPPerLevel
+}
+
+// GainVillagerXP adds task XP, scaled by trait, and levels the villager up.
+// Every second level also raises their efficiency. Reports a level-up.
+func GainVillagerXP(v *models.Villager, amount int) bool {
+	if v.Level >= MaxVillagerLevel {
+		return false
+	}
+	trait, _ := FindVillagerTrait(v.Trait)
+	v.XP += max(amount*(100+trait.XPPercent)/100, 1)
+	leveled := false
+	for v.Level < MaxVillagerLevel && v.XP >= VillagerXPToLevel(v.Level) {
+		v.XP -= VillagerXPToLevel(v.Level)
+		v.Level++
+		if v.Level%2 == 0 {
+			v.Efficiency++
+		}
+		leveled = true
+	}
+	if v.Level >= MaxVillagerLevel {
+		v.XP = 0
+	}
+	return leveled
+}
+
+// adjustMorale shifts a villager's morale within its bounds.
+func adjustMorale(v *models.Villager, delta int) {
+	v.Morale = min(max(v.Morale+delta, MinMorale), MaxMorale)
+}
+
+// FoodProduction is the food the village and its farm grow each manager
+// tick.
+func FoodProduction(village *models.Village) int {
+	return BaseFoodPerTick + village.Level*FoodPerVillageLevel + BuildingLevel(village, BuildingFarm)*FarmFoodPerLevel
+}
+
+// FoodUpkeep is the food the villagers eat each manager tick.
+func FoodUpkeep(village *models.Village) int {
+	return len(village.Villagers) * FoodPerVillager
+}
+
+// FoodStorage is the most food the village and its warehouse can keep.
+func FoodStorage(village *models.Village) int {
+	return BaseFoodStorage + village.Level*FoodStoragePerLevel + BuildingLevel(village, BuildingWarehouse)*WarehouseFoodPerLevel
+}
+
+// HousingCapacity is how many villagers the village houses. Past it,
+// villagers are unhappy and no newcomers settle.
+func HousingCapacity(village *models.Village) int {
+	return BaseHousing + village.Level*HousingPerVillageLevel + BuildingLevel(village, BuildingHouses)*HousesPerLevel
+}
+
+// ProductionPerTick is the village's production per tick: food, and what
+// its working harvesters bring in before talents.
+func ProductionPerTick(village *models.Village) map[string]int {
+	production := map[string]int{"Food": FoodProduction(village)}
+	for _, v := range village.Villagers {
+		if v.Role == "harvester" && v.HarvestType != "" && v.InjuredTicks == 0 {
+			production[v.HarvestType] += VillagerOutput(v)
+		}
+	}
+	return production
+}
+
+// SimulateVillagers runs a manager tick of village life. The village grows
+// food and the villagers eat it. Their morale rises when fed and falls with
+// hunger or crowding, and a villager at rock bottom leaves. Injuries heal, and
+// a content, fed and housed village may attract a newcomer. Returns a line per
+// notable event.
+func SimulateVillagers(village *models.Village) []string {
+	var messages []string
+	village.ResourcePerTick = ProductionPerTick(village)
+
+	village.FoodStock = min(village.FoodStock+FoodProduction(village), FoodStorage(village))
+	fed := village.FoodStock >= FoodUpkeep(village)
+	if fed {
+		village.FoodStock -= FoodUpkeep(village)
+	} else {
+		village.FoodStock = 0
+		if len(village.Villagers) > 0 {
+			messages = append(messages, fmt.Sprintf("%s has run out of food! Villagers go hungry.", village.Name))
+		}
+	}
+	crowded := len(village.Villagers) > HousingCapacity(village)
+
+	stayed := village.Villagers[:0]
+	for _, v := range village.Villagers {
+		if fed {
+			adjustMorale(&v, MoraleFedGain)
+		} else {
+			adjustMorale(&v, -MoraleHungerLoss)
+		}
+		if crowded {
+			adjustMorale(&v, -MoraleCrowdLoss)
+		}
+		if v.InjuredTicks > 0 {
+			v.InjuredTicks--
+			if v.InjuredTicks == 0 {
+				messages = append(messages, fmt.Sprintf("%s has recovered from their injuries.", v.Name))
+			}
+		}
+		if v.Morale <= MinMorale {
+			messages = append(messages, fmt.Sprintf("%s has had enough and left the village.", v.Name))
+			continue
+		}
+		stayed = append(stayed, v)
+	}
+	village.Villagers = stayed
+
+	if fed && !crowded && len(village.Villagers) < HousingCapacity(village) &&
+		village.FoodStock >= GrowthFoodCost && averageMorale(village) >= 0 && rand.Intn(100) < GrowthChance {
+		village.FoodStock -= GrowthFoodCost
+		newcomer := GenerateVillager(RollVillagerRole())
+		village.Villagers = append(village.Villagers, newcomer)
+		messages = append(messages, fmt.Sprintf("%s settled in %s as a %s.", newcomer.Name, village.Name, newcomer.Role))
+	}
+	return messages
+}
+
+// averageMorale is the mean morale of the villagers, 0 for an empty village.
+func averageMorale(village *models.Village) int {
+	if len(village.Villagers) == 0 {
+		return 0
+	}
+	total := 0
+	for _, v := range village.Villagers {
+		total += v.Morale
+	}
+	return total / len(village.Villagers)
+}
+
+// TideCasualties rolls for villagers hurt by monsters that breached the
+// village. A hit villager is injured, or killed if already injured; brave
+// villagers shrug off half the hits, and a watchtower's warning spares some.
+// Returns a line per casualty.
+func TideCasualties(village *models.Village, breaches int) []string {
+	var messages []string
+	for i := 0; i < breaches && len(village.Villagers) > 0; i++ {
+		if rand.Intn(100) >= tideInjuryChance(village) {
+			continue
+		}
+		idx := rand.Intn(len(village.Villagers))
+		v := &village.Villagers[idx]
+		trait, _ := FindVillagerTrait(v.Trait)
+		if rand.Intn(100) >= trait.InjuryPercent {
+			messages = append(messages, fmt.Sprintf("%s stands firm against the monsters!", v.Name))
+			continue
+		}
+		if v.InjuredTicks == 0 {
+			v.InjuredTicks = InjuryTicks
+			messages = append(messages, fmt.Sprintf("%s was injured in the attack!", v.Name))
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s was killed in the attack!", v.Name))
+		village.Villagers = append(village.Villagers[:idx], village.Villagers[idx+1:]...)
+		for j := range village.Villagers {
+			adjustMorale(&village.Villagers[j], -MoraleGriefLoss)
+		}
+	}
+	return messages
+}
+
+// VillagersAfterTide lifts or sinks morale after a tide and gives villager
+// guards XP for a victory. Returns a line per level-up.
+func VillagersAfterTide(village *models.Village, victory bool) []string {
+	var messages []string
+	for i := range village.Villagers {
+		v := &village.Villagers[i]
+		if !victory {
+			adjustMorale(v, -MoraleTideLoss)
+			continue
+		}
+		adjustMorale(v, MoraleTideWin)
+		if v.Role == "guard" && v.InjuredTicks == 0 && GainVillagerXP(v, TideGuardXP) {
+			messages = append(messages, fmt.Sprintf("%s reached level %d!", v.Name, v.Level))
+		}
+	}
+	return messages
+}
+
+// MoraleText describes a morale value.
+func MoraleText(morale int) string {
+	switch {
+	case morale >= 30:
+		return "elated"
+	case morale >= 10:
+		return "happy"
+	case morale > -10:
+		return "content"
+	case morale > -30:
+		return "unhappy"
+	}
+	return "miserable"
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"testing"
+
+	"rpg-game/pkg/models"
+)
+
+func TestVillagerOutput(t *testing.T) {
+	v := models.Villager{Name: "Ida", Role: "harvester", Level: 4, Efficiency: 6}
+	if VillagerOutput(v) != 8 {
+		t.Errorf("a content villager should put in efficiency plus half their level, got %d", VillagerOutput(v))
+	}
+	v.Trait = TraitDiligent
+	v.Morale = MaxMorale
+	if VillagerOutput(v) != 12 {
+		t.Errorf("an elated diligent villager should put in 150%%, got %d", VillagerOutput(v))
+	}
+	v.Trait = TraitLazy
+	v.Morale = MinMorale
+	if VillagerOutput(v) != 4 {
+		t.Errorf("a miserable lazy villager should put in 50%%, got %d", VillagerOutput(v))
+	}
+	v.InjuredTicks = 1
+	if VillagerOutput(v) != 0 {
+		t.Error("injured villagers shouldn't work")
+	}
+}
+
+func TestGainVillagerXP(t *testing.T) {
+	v := models.Villager{Name: "Ida", Level: 1, Efficiency: 2}
+	if GainVillagerXP(&v, VillagerXPToLevel(1)-1) || v.Level != 1 {
+		t.Fatal("leveled up too early")
+	}
+	if !GainVillagerXP(&v, 1) || v.Level != 2 || v.Efficiency != 3 || v.XP != 0 {
+		t.Errorf("expected level 2 with +1 efficiency, got %+v", v)
+	}
+
+	diligent := models.Villager{Name: "Dot", Level: 1, Trait: TraitDiligent}
+	GainVillagerXP(&diligent, 20)
+	if diligent.XP != 25 {
+		t.Errorf("diligent villagers should earn 25%% more XP, got %d", diligent.XP)
+	}
+}
+
+func TestSimulateVillagersFood(t *testing.T) {
+	village := models.Village{Name: "Mill", Level: 1,
+		Villagers: []models.Villager{{Name: "A", Role: "harvester"}, {Name: "B", Role: "guard", InjuredTicks: 1}}}
+	SimulateVillagers(&village)
+	if village.FoodStock != FoodProduction(&village)-2 {
+		t.Errorf("expected production less upkeep stored, got %d", village.FoodStock)
+	}
+	if village.Villagers[0].Morale != MoraleFedGain || village.Villagers[1].InjuredTicks != 0 {
+		t.Errorf("fed villagers should cheer up and heal, got %+v", village.Villagers)
+	}
+	if village.ResourcePerTick["Food"] != FoodProduction(&village) {
+		t.Error("the tick should expose food production")
+	}
+
+	// More mouths than the fields can feed: morale falls until people leave.
+	hungry := models.Village{Name: "Dust", Level: 1}
+	for i := 0; i < FoodProduction(&hungry)+5; i++ {
+		hungry.Villagers = append(hungry.Villagers, models.Villager{Name: "V", Role: "harvester"})
+	}
+	for i := 0; i < 10 && len(hungry.Villagers) > 0; i++ {
+		SimulateVillagers(&hungry)
+	}
+	if len(hungry.Villagers) != 0 {
+		t.Errorf("starving villagers should eventually leave, %d stayed", len(hungry.Villagers))
+	}
+}
+
+func TestPopulationGrowsToHousing(t *testing.T) {
+	village := models.Village{Name: "Glen", Level: 1, Villagers: []models.Villager{{Name: "A", Role: "harvester"}}}
+	for i := 0; i < 500; i++ {
+		SimulateVillagers(&village)
+		if len(village.Villagers) > HousingCapacity(&village) {
+			t.Fatalf("population %d grew past housing %d", len(village.Villagers), HousingCapacity(&village))
+		}
+	}
+	if len(village.Villagers) != HousingCapacity(&village) {
+		t.Errorf("expected the village to fill its housing, got %d/%d", len(village.Villagers), HousingCapacity(&village))
+	}
+}
+
+func TestTideCasualties(t *testing.T) {
+	village := models.Village{Villagers: []models.Villager{{Name: "A", Role: "harvester"}, {Name: "B", Role: "crafter"}}}
+	TideCasualties(&village, 1000)
+	if len(village.Villagers) != 0 {
+		t.Errorf("a thousand breaches should kill everyone, %d survived", len(village.Villagers))
+	}
+
+	village.Villagers = []models.Villager{{Name: "G", Role: "guard", Level: 1}}
+	msgs := VillagersAfterTide(&village, true)
+	if g := village.Villagers[0]; g.Morale != MoraleTideWin || g.XP != TideGuardXP || len(msgs) != 0 {
+		t.Errorf("a victory should cheer and train guards, got %+v", g)
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+)
+
+// goldHoursKept is how many hourly gold-flow buckets the collector retains.
+const goldHoursKept = 24
+
+// GoldHour aggregates gold sources and sinks for one wall-clock hour.
+type GoldHour struct {
+	Hour    int64            `json:"hour"` // unix seconds at the start of the hour
+	Sources map[string]int64 `json:"sources"`
+	Sinks   map[string]int64 `json:"sinks"`
+	Minted  int64            `json:"minted"`
+	Burned  int64            `json:"burned"`
+	Net     int64            `json:"net"`
+}
+
+// RecordLedger feeds one ledger entry into the economy view. Only gold is
+// tracked; positive deltas are sources and negative deltas are sinks.
+func (mc *MetricsCollector) RecordLedger(asset, reason string, delta int, timestamp int64) {
+	if asset != "Gold" || delta == 0 {
+		return
+	}
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	hour := timestamp - timestamp%3600
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	bucket, ok := mc.goldHours[hour]
+	if !ok {
+		bucket = &GoldHour{Hour: hour, Sources: make(map[string]int64), Sinks: make(map[string]int64)}
+		mc.goldHours[hour] = bucket
+		for h := range mc.goldHours {
+			if h <= hour-goldHoursKept*3600 {
+				delete(mc.goldHours, h)
+			}
+		}
+	}
+	amount := int64(delta)
+	if amount > 0 {
+		mc.GoldSources[reason] += amount
+		bucket.Sources[reason] += amount
+		bucket.Minted += amount
+	} else {
+		mc.GoldSinks[reason] -= amount
+		bucket.Sinks[reason] -= amount
+		bucket.Burned -= amount
+	}
+	bucket.Net += amount
+}
+
+// goldHoursLocked copies the hourly buckets oldest first. Callers hold mc.mu.
+func (mc *MetricsCollector) goldHoursLocked() []GoldHour {
+	hours := make([]GoldHour, 0, len(mc.goldHours))
+	for _, b := range mc.goldHours {
+		hours = append(hours, GoldHour{
+			Hour:    b.Hour,
+			Sources: copyMap(b.Sources),
+			Sinks:   copyMap(b.Sinks),
+			Minted:  b.Minted,
+			Burned:  b.Burned,
+			Net:     b.Net,
+		})
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Hour < hours[j].Hour })
+	return hours
+}
@@ -19,6 +19,7 @@ type MetricsCollector struct {
 	PlayerDeaths      atomic.Int64
 	Flees             atomic.Int64
 	FleeFails         atomic.Int64
+	MonsterFlees      atomic.Int64
 	PlayerCrits       atomic.Int64
 	MonsterCrits      atomic.Int64
 	CombatTurns       atomic.Int64
@@ -61,6 +62,7 @@ type MetricsCollector struct {
 	SkillUseCounts    map[string]int64
 	StatusEffects     map[string]int64
 	DamageByType      map[string]int64
+	Reactions         map[string]int64 // elemental reactions, by name
 	LevelUpsByLevel   map[string]int64
 	HarvestsByResource map[string]int64
 	PotionsUsed       map[string]int64
@@ -75,6 +77,15 @@ type MetricsCollector struct {
 	GuardianDefeatsBySkill map[string]int64
 	SkillsLearnedByName    map[string]int64
 	SkillsUpgradedByName   map[string]int64
+	GoldSources            map[string]int64 // gold credited, by ledger reason
+	GoldSinks              map[string]int64 // gold debited, by ledger reason
+	goldHours              map[int64]*GoldHour
+
+	// Latency and size histograms (each guarded by its own mutex)
+	CommandLatency *HistogramVec // seconds, by session state
+	DBQueryLatency *HistogramVec // seconds, by Store method
+	WSMessageSize  *HistogramVec // bytes, by direction
+	TickerDuration *HistogramVec // seconds, by ticker name
 }
 
 // NewMetricsCollector creates a new MetricsCollector with initialized maps.
@@ -90,6 +101,7 @@ func NewMetricsCollector() *MetricsCollector {
 		SkillUseCounts:      make(map[string]int64),
 		StatusEffects:       make(map[string]int64),
 		DamageByType:        make(map[string]int64),
+		Reactions:           make(map[string]int64),
 		LevelUpsByLevel:     make(map[string]int64),
 		HarvestsByResource:  make(map[string]int64),
 		PotionsUsed:         make(map[string]int64),
@@ -104,6 +116,13 @@ func NewMetricsCollector() *MetricsCollector {
 		GuardianDefeatsBySkill: make(map[string]int64),
 		SkillsLearnedByName:    make(map[string]int64),
 		SkillsUpgradedByName:   make(map[string]int64),
+		GoldSources:            make(map[string]int64),
+		GoldSinks:              make(map[string]int64),
+		goldHours:              make(map[int64]*GoldHour),
+		CommandLatency:         NewHistogramVec(latencyBuckets),
+		DBQueryLatency:         NewHistogramVec(latencyBuckets),
+		WSMessageSize:          NewHistogramVec(sizeBuckets),
+		TickerDuration:         NewHistogramVec(latencyBuckets),
 	}
 }
 
@@ -158,6 +177,11 @@ func (mc *MetricsCollector) RecordFlee(success bool) {
 	}
 }
 
+// RecordMonsterFlee records a monster running away from a fight.
+func (mc *MetricsCollector) RecordMonsterFlee() {
+	mc.MonsterFlees.Add(1)
+}
+
 // RecordCrit records a critical hit.
 func (mc *MetricsCollector) RecordCrit(isPlayer bool) {
 	if isPlayer {
@@ -194,6 +218,13 @@ func (mc *MetricsCollector) RecordStatusEffect(effectType string) {
 	mc.mu.Unlock()
 }
 
+// RecordReaction records an elemental reaction in combat.
+func (mc *MetricsCollector) RecordReaction(name string) {
+	mc.mu.Lock()
+	mc.Reactions[name]++
+	mc.mu.Unlock()
+}
+
 // RecordItemUse records item consumption.
 func (mc *MetricsCollector) RecordItemUse(name string) {
 	mc.ItemUses.Add(1)
@@ -392,6 +423,7 @@ type CombatMetrics struct {
 	Losses           int64              `json:"losses"`
 	Flees            int64              `json:"flees"`
 	FleeFails        int64              `json:"flee_fails"`
+	MonsterFlees     int64              `json:"monster_flees"`
 	WinRate          float64            `json:"win_rate"`
 	FleeSuccessRate  float64            `json:"flee_success_rate"`
 	AvgTurns         float64            `json:"avg_turns"`
@@ -407,6 +439,7 @@ type CombatMetrics struct {
 	SkillUsage       map[string]int64   `json:"skill_usage"`
 	StatusEffects    map[string]int64   `json:"status_effects"`
 	DamageByType     map[string]int64   `json:"damage_by_type"`
+	Reactions        map[string]int64   `json:"elemental_reactions"`
 }
 
 // WinLoss holds win/loss counts for a category.
@@ -428,6 +461,9 @@ type EconomyMetrics struct {
 	PotionsUsed        map[string]int64 `json:"potions_used"`
 	ItemsByRarity      map[string]int64 `json:"items_by_rarity"`
 	TotalItemsLooted   int64            `json:"total_items_looted"`
+	GoldSources        map[string]int64 `json:"gold_sources"`
+	GoldSinks          map[string]int64 `json:"gold_sinks"`
+	GoldPerHour        []GoldHour       `json:"gold_per_hour"`
 }
 
 // ArenaMetrics holds PvP arena data.
@@ -511,6 +547,7 @@ func (mc *MetricsCollector) Snapshot() MetricsSnapshot {
 	skillUsage := copyMap(mc.SkillUseCounts)
 	statusEffects := copyMap(mc.StatusEffects)
 	damageByType := copyMap(mc.DamageByType)
+	reactions := copyMap(mc.Reactions)
 	levelDist := copyMap(mc.LevelUpsByLevel)
 	harvestsByRes := copyMap(mc.HarvestsByResource)
 	potionsUsed := copyMap(mc.PotionsUsed)
@@ -523,6 +560,9 @@ func (mc *MetricsCollector) Snapshot() MetricsSnapshot {
 	guardianDefeatsBySkill := copyMap(mc.GuardianDefeatsBySkill)
 	skillsLearnedByName := copyMap(mc.SkillsLearnedByName)
 	skillsUpgradedByName := copyMap(mc.SkillsUpgradedByName)
+	goldSources := copyMap(mc.GoldSources)
+	goldSinks := copyMap(mc.GoldSinks)
+	goldPerHour := mc.goldHoursLocked()
 
 	arenaByGap := make(map[string]ArenaGapStats)
 	for bucket, wins := range mc.ArenaWinsByGap {
@@ -542,6 +582,7 @@ func (mc *MetricsCollector) Snapshot() MetricsSnapshot {
 			Losses:          losses,
 			Flees:           flees,
 			FleeFails:       fleeFails,
+			MonsterFlees:    mc.MonsterFlees.Load(),
 			WinRate:         winRate,
 			FleeSuccessRate: fleeSuccessRate,
 			AvgTurns:        avgTurns,
@@ -557,6 +598,7 @@ func (mc *MetricsCollector) Snapshot() MetricsSnapshot {
 			SkillUsage:      skillUsage,
 			StatusEffects:   statusEffects,
 			DamageByType:    damageByType,
+			Reactions:       reactions,
 		},
 		Progression: ProgressionMetrics{
 			TotalLevelUps:     mc.LevelUps.Load(),
@@ -568,6 +610,9 @@ func (mc *MetricsCollector) Snapshot() MetricsSnapshot {
 			PotionsUsed:        potionsUsed,
 			ItemsByRarity:      itemsByRarity,
 			TotalItemsLooted:   mc.ItemsLooted.Load(),
+			GoldSources:        goldSources,
+			GoldSinks:          goldSinks,
+			GoldPerHour:        goldPerHour,
 		},
 		Arena: ArenaMetrics{
 			TotalFights: mc.ArenaFights.Load(),
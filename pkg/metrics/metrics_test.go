@@ -1,9 +1,12 @@
 package metrics
 
 import (
+	"bufio"
 	"encoding/json"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestRecordCombatWin(t *testing.T) {
@@ -335,3 +338,103 @@ func TestZeroDivisionSafety(t *testing.T) {
 		t.Errorf("ClearRate = %f, want 0", snap.Dungeons.ClearRate)
 	}
 }
+
+func TestHistogramVecBuckets(t *testing.T) {
+	h := NewHistogramVec([]float64{1, 5, 10})
+	h.Observe("a", 0.5)
+	h.Observe("a", 3)
+	h.Observe("a", 50)
+	h.Observe("b", 7)
+
+	if got := h.Count("a"); got != 3 {
+		t.Errorf("Count(a) = %d, want 3", got)
+	}
+	if got := h.Count("missing"); got != 0 {
+		t.Errorf("Count(missing) = %d, want 0", got)
+	}
+
+	var buf strings.Builder
+	w := bufio.NewWriter(&buf)
+	writeHistogram(w, "test_hist", "help", "label", h)
+	w.Flush()
+	out := buf.String()
+
+	for _, want := range []string{
+		`test_hist_bucket{label="a",le="1"} 1`,
+		`test_hist_bucket{label="a",le="5"} 2`,
+		`test_hist_bucket{label="a",le="10"} 2`,
+		`test_hist_bucket{label="a",le="+Inf"} 3`,
+		`test_hist_sum{label="a"} 53.5`,
+		`test_hist_count{label="b"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("histogram output missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	mc := NewMetricsCollector()
+	mc.RecordCombatWin("Forest", "Goblin", "common", 3)
+	mc.RecordCombatLoss("Forest", "Orc", "rare", 4)
+	mc.RecordFlee(false)
+	mc.RecordHarvest("Lumber", 7)
+	mc.RecordFeatureUse(`weird"name`)
+	mc.ObserveCommand("combat", 2*time.Millisecond)
+	mc.ObserveWSMessage("in", 120)
+
+	var buf strings.Builder
+	if err := mc.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE rpg_fights_total counter",
+		"rpg_fights_total 2",
+		`rpg_flees_total{result="fail"} 1`,
+		`rpg_combat_results_by_location_total{location="Forest",result="win"} 1`,
+		`rpg_combat_results_by_location_total{location="Forest",result="loss"} 1`,
+		`rpg_harvested_units_by_resource_total{resource="Lumber"} 7`,
+		`rpg_feature_usage_total{feature="weird\"name"} 1`,
+		"# TYPE rpg_command_duration_seconds histogram",
+		`rpg_command_duration_seconds_count{state="combat"} 1`,
+		`rpg_websocket_message_bytes_bucket{direction="in",le="256"} 1`,
+		"# TYPE rpg_online_players gauge",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("prometheus output missing %q", want)
+		}
+	}
+}
+
+func TestRecordLedgerEconomyView(t *testing.T) {
+	mc := NewMetricsCollector()
+	hour := int64(1_700_000_000) - int64(1_700_000_000)%3600
+
+	mc.RecordLedger("Gold", "dungeon_treasure", 100, hour+10)
+	mc.RecordLedger("Gold", "inn_sleep", -30, hour+20)
+	mc.RecordLedger("Gold", "inn_sleep", -10, hour+3600)
+	mc.RecordLedger("Iron", "harvest", 5, hour+30) // not gold, ignored
+
+	snap := mc.Snapshot()
+	if snap.Economy.GoldSources["dungeon_treasure"] != 100 {
+		t.Errorf("sources = %v", snap.Economy.GoldSources)
+	}
+	if snap.Economy.GoldSinks["inn_sleep"] != 40 {
+		t.Errorf("sinks = %v", snap.Economy.GoldSinks)
+	}
+	if len(snap.Economy.GoldPerHour) != 2 {
+		t.Fatalf("expected 2 hourly buckets, got %d", len(snap.Economy.GoldPerHour))
+	}
+	first := snap.Economy.GoldPerHour[0]
+	if first.Hour != hour || first.Minted != 100 || first.Burned != 30 || first.Net != 70 {
+		t.Errorf("unexpected first bucket: %+v", first)
+	}
+
+	// Buckets older than the retention window are pruned.
+	mc.RecordLedger("Gold", "gamble_win", 1, hour+goldHoursKept*3600+5)
+	if n := len(mc.Snapshot().Economy.GoldPerHour); n != 2 {
+		t.Errorf("expected old bucket pruned, got %d buckets", n)
+	}
+}
@@ -0,0 +1,291 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Histograms
+// ---------------------------------------------------------------------------
+
+// Default bucket layouts for the built-in histograms.
+var (
+	latencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+	sizeBuckets    = []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576}
+)
+
+// HistogramVec is a set of histograms sharing bucket bounds, keyed by a
+// single label value.
+type HistogramVec struct {
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 // non-cumulative, one per bucket
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec creates a histogram vector with the given upper bounds,
+// which must be sorted ascending. A +Inf bucket is implied.
+func NewHistogramVec(buckets []float64) *HistogramVec {
+	return &HistogramVec{
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+}
+
+// Observe records a value for the given label.
+func (h *HistogramVec) Observe(label string, v float64) {
+	h.mu.Lock()
+	s, ok := h.series[label]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[label] = s
+	}
+	for i, ub := range h.buckets {
+		if v <= ub {
+			s.counts[i]++
+			break
+		}
+	}
+	s.sum += v
+	s.count++
+	h.mu.Unlock()
+}
+
+// Count returns how many observations were recorded for label.
+func (h *HistogramVec) Count(label string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[label]; ok {
+		return s.count
+	}
+	return 0
+}
+
+// ObserveCommand records how long ProcessCommand took for a session state.
+func (mc *MetricsCollector) ObserveCommand(state string, d time.Duration) {
+	mc.CommandLatency.Observe(state, d.Seconds())
+}
+
+// ObserveDBQuery records how long a Store method took.
+func (mc *MetricsCollector) ObserveDBQuery(method string, d time.Duration) {
+	mc.DBQueryLatency.Observe(method, d.Seconds())
+}
+
+// ObserveWSMessage records the size of a WebSocket message. direction is
+// "in" for client-to-server and "out" for server-to-client.
+func (mc *MetricsCollector) ObserveWSMessage(direction string, bytes int) {
+	mc.WSMessageSize.Observe(direction, float64(bytes))
+}
+
+// ObserveTicker records how long one run of a background ticker took.
+func (mc *MetricsCollector) ObserveTicker(name string, d time.Duration) {
+	mc.TickerDuration.Observe(name, d.Seconds())
+}
+
+// ---------------------------------------------------------------------------
+// Prometheus text exposition
+// ---------------------------------------------------------------------------
+
+// PrometheusContentType is the Content-Type for the text exposition format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes every metric in the Prometheus text exposition format.
+func (mc *MetricsCollector) WritePrometheus(out io.Writer) error {
+	w := bufio.NewWriter(out)
+
+	writeGauge(w, "rpg_uptime_seconds", "Seconds since the server started.", float64(time.Since(mc.startTime).Seconds()))
+	writeGauge(w, "rpg_online_players", "Players currently connected over WebSocket.", float64(mc.OnlinePlayers.Load()))
+
+	// Combat
+	writeCounter(w, "rpg_fights_total", "Fights finished (wins plus deaths).", mc.TotalFights.Load())
+	writeCounter(w, "rpg_player_wins_total", "Fights won by players.", mc.PlayerWins.Load())
+	writeCounter(w, "rpg_player_deaths_total", "Fights lost by players.", mc.PlayerDeaths.Load())
+	writeCounter(w, "rpg_combat_turns_total", "Turns taken across all finished fights.", mc.CombatTurns.Load())
+	writeCounter(w, "rpg_skill_uses_total", "Skills used in combat.", mc.SkillUses.Load())
+	writeCounter(w, "rpg_item_uses_total", "Items used in combat.", mc.ItemUses.Load())
+	writeCounter(w, "rpg_defend_actions_total", "Defend actions taken.", mc.DefendActions.Load())
+	writeCounter(w, "rpg_auto_fights_total", "Auto-fights started.", mc.AutoFights.Load())
+	writeHeader(w, "rpg_flees_total", "Flee attempts by result.", "counter")
+	writeSample(w, "rpg_flees_total", [][2]string{{"result", "success"}}, float64(mc.Flees.Load()))
+	writeSample(w, "rpg_flees_total", [][2]string{{"result", "fail"}}, float64(mc.FleeFails.Load()))
+	writeCounter(w, "rpg_monster_flees_total", "Monsters that ran away from a fight.", mc.MonsterFlees.Load())
+	writeHeader(w, "rpg_crits_total", "Critical hits by side.", "counter")
+	writeSample(w, "rpg_crits_total", [][2]string{{"side", "player"}}, float64(mc.PlayerCrits.Load()))
+	writeSample(w, "rpg_crits_total", [][2]string{{"side", "monster"}}, float64(mc.MonsterCrits.Load()))
+	writeHeader(w, "rpg_damage_dealt_total", "Damage dealt by side.", "counter")
+	writeSample(w, "rpg_damage_dealt_total", [][2]string{{"side", "player"}}, float64(mc.PlayerDamageDealt.Load()))
+	writeSample(w, "rpg_damage_dealt_total", [][2]string{{"side", "monster"}}, float64(mc.MonsterDamageDealt.Load()))
+
+	// Progression
+	writeCounter(w, "rpg_level_ups_total", "Player level-ups.", mc.LevelUps.Load())
+	writeCounter(w, "rpg_xp_awarded_total", "Experience points awarded.", mc.XPAwarded.Load())
+
+	// Economy
+	writeCounter(w, "rpg_harvests_total", "Harvest actions.", mc.Harvests.Load())
+	writeCounter(w, "rpg_resource_units_total", "Resource units harvested.", mc.ResourceUnits.Load())
+	writeCounter(w, "rpg_items_looted_total", "Items looted.", mc.ItemsLooted.Load())
+
+	// Arena
+	writeCounter(w, "rpg_arena_fights_total", "Arena fights.", mc.ArenaFights.Load())
+
+	// Dungeons
+	writeCounter(w, "rpg_dungeon_enters_total", "Dungeon entries.", mc.DungeonEnters.Load())
+	writeCounter(w, "rpg_dungeon_clears_total", "Dungeons cleared.", mc.DungeonClears.Load())
+	writeCounter(w, "rpg_dungeon_deaths_total", "Deaths inside dungeons.", mc.DungeonDeaths.Load())
+
+	// Village
+	writeCounter(w, "rpg_tide_ticks_total", "Auto-tide ticks that processed at least one tide.", mc.TideTicks.Load())
+	writeCounter(w, "rpg_tides_processed_total", "Auto-tides resolved.", mc.TidesProcessed.Load())
+	writeHeader(w, "rpg_tide_outcomes_total", "Auto-tide outcomes.", "counter")
+	writeSample(w, "rpg_tide_outcomes_total", [][2]string{{"result", "victory"}}, float64(mc.TideVictories.Load()))
+	writeSample(w, "rpg_tide_outcomes_total", [][2]string{{"result", "defeat"}}, float64(mc.TideDefeats.Load()))
+	writeCounter(w, "rpg_village_manager_ticks_total", "Village manager ticks that managed at least one village.", mc.VillageManagerTicks.Load())
+	writeCounter(w, "rpg_villages_managed_total", "Villages processed by the village manager.", mc.VillagesManaged.Load())
+
+	// Quests and guardians
+	writeCounter(w, "rpg_quest_completions_total", "Quests completed.", mc.QuestCompletions.Load())
+	writeCounter(w, "rpg_guardian_spawns_total", "Skill guardians spawned.", mc.GuardianSpawns.Load())
+	writeCounter(w, "rpg_guardian_defeats_total", "Skill guardians defeated.", mc.GuardianDefeats.Load())
+	writeCounter(w, "rpg_skills_learned_total", "Skills learned from guardians.", mc.SkillsLearned.Load())
+	writeCounter(w, "rpg_skills_upgraded_total", "Skills upgraded from duplicate guardians.", mc.SkillsUpgraded.Load())
+
+	// Labeled counters from the distribution maps
+	mc.mu.RLock()
+	writeWinLoss(w, "rpg_combat_results_by_location_total", "Fight results by location.", "location", mc.WinsByLocation, mc.LossesByLocation)
+	writeWinLoss(w, "rpg_combat_results_by_monster_type_total", "Fight results by monster type.", "monster_type", mc.WinsByMonsterType, mc.LossesByMonsterType)
+	writeWinLoss(w, "rpg_combat_results_by_rarity_total", "Fight results by monster rarity.", "rarity", mc.WinsByRarity, mc.LossesByRarity)
+	writeLabeled(w, "rpg_skill_uses_by_skill_total", "Skill uses by skill name.", "skill", mc.SkillUseCounts)
+	writeLabeled(w, "rpg_status_effects_total", "Status effects applied by type.", "effect", mc.StatusEffects)
+	writeLabeled(w, "rpg_damage_by_type_total", "Damage dealt by damage type.", "damage_type", mc.DamageByType)
+	writeLabeled(w, "rpg_elemental_reactions_total", "Elemental reactions by reaction.", "reaction", mc.Reactions)
+	writeLabeled(w, "rpg_level_ups_by_level_total", "Level-ups by level reached.", "level", mc.LevelUpsByLevel)
+	writeLabeled(w, "rpg_harvested_units_by_resource_total", "Resource units harvested by resource.", "resource", mc.HarvestsByResource)
+	writeLabeled(w, "rpg_items_used_by_name_total", "Items used by item name.", "item", mc.PotionsUsed)
+	writeLabeled(w, "rpg_items_looted_by_rarity_total", "Items looted by rarity tier.", "rarity", mc.ItemsByRarity)
+	writeLabeled(w, "rpg_arena_fights_by_gap_total", "Arena fights by rating gap bucket.", "gap", mc.ArenaTotalByGap)
+	writeLabeled(w, "rpg_arena_higher_wins_by_gap_total", "Arena wins by the higher-rated player by rating gap bucket.", "gap", mc.ArenaWinsByGap)
+	writeLabeled(w, "rpg_dungeon_deaths_by_floor_total", "Dungeon deaths by floor.", "floor", mc.FloorDeaths)
+	writeLabeled(w, "rpg_dungeon_floor_clears_total", "Dungeon floors cleared by floor.", "floor", mc.FloorClears)
+	writeLabeled(w, "rpg_feature_usage_total", "Feature usage by feature.", "feature", mc.FeatureUsage)
+	writeLabeled(w, "rpg_quest_completions_by_quest_total", "Quest completions by quest ID.", "quest", mc.QuestCompletionsByID)
+	writeLabeled(w, "rpg_guardian_spawns_by_skill_total", "Guardian spawns by guarded skill.", "skill", mc.GuardianSpawnsBySkill)
+	writeLabeled(w, "rpg_guardian_defeats_by_skill_total", "Guardian defeats by guarded skill.", "skill", mc.GuardianDefeatsBySkill)
+	writeLabeled(w, "rpg_skills_learned_by_skill_total", "Skills learned by skill name.", "skill", mc.SkillsLearnedByName)
+	writeLabeled(w, "rpg_skills_upgraded_by_skill_total", "Skills upgraded by skill name.", "skill", mc.SkillsUpgradedByName)
+	writeLabeled(w, "rpg_gold_sources_total", "Gold credited to characters by ledger reason.", "reason", mc.GoldSources)
+	writeLabeled(w, "rpg_gold_sinks_total", "Gold debited from characters by ledger reason.", "reason", mc.GoldSinks)
+	mc.mu.RUnlock()
+
+	// Histograms
+	writeHistogram(w, "rpg_command_duration_seconds", "ProcessCommand latency by session state.", "state", mc.CommandLatency)
+	writeHistogram(w, "rpg_db_query_duration_seconds", "Store method latency.", "method", mc.DBQueryLatency)
+	writeHistogram(w, "rpg_websocket_message_bytes", "WebSocket message size by direction.", "direction", mc.WSMessageSize)
+	writeHistogram(w, "rpg_ticker_duration_seconds", "Background ticker run time.", "ticker", mc.TickerDuration)
+
+	return w.Flush()
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeSample(w *bufio.Writer, name string, labels [][2]string, v float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, l[0], escapeLabel(l[1]))
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func writeCounter(w *bufio.Writer, name, help string, v int64) {
+	writeHeader(w, name, help, "counter")
+	writeSample(w, name, nil, float64(v))
+}
+
+func writeGauge(w *bufio.Writer, name, help string, v float64) {
+	writeHeader(w, name, help, "gauge")
+	writeSample(w, name, nil, v)
+}
+
+func writeLabeled(w *bufio.Writer, name, help, label string, m map[string]int64) {
+	writeHeader(w, name, help, "counter")
+	for _, k := range sortedKeys(m) {
+		writeSample(w, name, [][2]string{{label, k}}, float64(m[k]))
+	}
+}
+
+func writeWinLoss(w *bufio.Writer, name, help, label string, wins, losses map[string]int64) {
+	writeHeader(w, name, help, "counter")
+	merged := mergeWinLoss(wins, losses)
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeSample(w, name, [][2]string{{label, k}, {"result", "win"}}, float64(merged[k].W))
+		writeSample(w, name, [][2]string{{label, k}, {"result", "loss"}}, float64(merged[k].L))
+	}
+}
+
+func writeHistogram(w *bufio.Writer, name, help, label string, h *HistogramVec) {
+	writeHeader(w, name, help, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.series[k]
+		var cumulative uint64
+		for i, ub := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, name+"_bucket", [][2]string{{label, k}, {"le", formatFloat(ub)}}, float64(cumulative))
+		}
+		writeSample(w, name+"_bucket", [][2]string{{label, k}, {"le", "+Inf"}}, float64(s.count))
+		writeSample(w, name+"_sum", [][2]string{{label, k}}, s.sum)
+		writeSample(w, name+"_count", [][2]string{{label, k}}, float64(s.count))
+	}
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
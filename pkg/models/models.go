@@ -44,6 +44,63 @@ type Village struct {
 	TideInterval     int            `json:"tide_interval"`
 	ActiveGuards     []Guard        `json:"active_guards"`
 	LastHarvestTime  int64          `json:"last_harvest_time"`
+
+	Stash []Item `json:"stash,omitempty"` // items stored by the owner
+
+	FoodStock int `json:"food_stock,omitempty"` // food stored to feed villagers
+
+	Jobs      []VillageJob `json:"jobs,omitempty"` // crafting and building queue, oldest first
+	NextJobID int          `json:"next_job_id,omitempty"`
+
+	Buildings     []VillageBuilding `json:"buildings,omitempty"`
+	TideWarnedFor int64             `json:"tide_warned_for,omitempty"` // LastTideTime a watchtower warning was sent for
+
+	LastSiege *SiegeReport `json:"last_siege,omitempty"` // report of the last auto-tide
+
+	ShieldUntil int64          `json:"shield_until,omitempty"` // other players can't raid the village before then
+	RaidLog     []RaidLogEntry `json:"raid_log,omitempty"`     // newest first
+}
+
+// RaidLogEntry records a raid by one player's guards on another's village.
+// Both villages keep a copy; Outgoing marks the attacker's.
+type RaidLogEntry struct {
+	Time            int64          `json:"time"`
+	Attacker        string         `json:"attacker"` // character name
+	AttackerAccount int64          `json:"attacker_account"`
+	AttackerVillage string         `json:"attacker_village"`
+	Defender        string         `json:"defender"`
+	DefenderAccount int64          `json:"defender_account"`
+	DefenderVillage string         `json:"defender_village"`
+	Outgoing        bool           `json:"outgoing"`
+	Raiders         int            `json:"raiders"`
+	Breached        int            `json:"breached"` // raiders that broke into the village
+	Loot            map[string]int `json:"loot,omitempty"`
+	Revenge         bool           `json:"revenge,omitempty"` // answered an earlier raid
+	Avenged         bool           `json:"avenged,omitempty"` // an incoming raid already answered with revenge
+}
+
+// VillageBuilding is a farm, house or other building raised in a village.
+// A building whose upkeep went unpaid stands idle until it is paid again.
+type VillageBuilding struct {
+	Name   string `json:"name"`
+	Level  int    `json:"level"`
+	Unpaid bool   `json:"unpaid,omitempty"`
+}
+
+// VillageJob is a paid-for crafting, building or defense upgrade job that
+// villagers work through over time.
+type VillageJob struct {
+	ID         int            `json:"id"`
+	Kind       string         `json:"kind"` // craft, build, upgrade or construct
+	Name       string         `json:"name"`
+	RecipeID   string         `json:"recipe_id,omitempty"`
+	SkillName  string         `json:"skill_name,omitempty"` // skill upgrade target
+	Defense    string         `json:"defense,omitempty"`    // blueprint to build or upgrade
+	Building   string         `json:"building,omitempty"`   // village building to raise a level
+	DefenseIdx int            `json:"defense_idx,omitempty"`
+	Cost       map[string]int `json:"cost"`
+	Work       int            `json:"work"`
+	Progress   int            `json:"progress"`
 }
 
 type Villager struct {
@@ -53,6 +110,11 @@ type Villager struct {
 	Efficiency   int    `json:"efficiency"`
 	AssignedTask string `json:"assigned_task"`
 	HarvestType  string `json:"harvest_type"`
+
+	Trait        string `json:"trait,omitempty"`         // diligent, lazy or brave
+	XP           int    `json:"xp,omitempty"`            // toward the next level
+	Morale       int    `json:"morale,omitempty"`        // -50 (miserable) to 50 (elated), 0 is content
+	InjuredTicks int    `json:"injured_ticks,omitempty"` // manager ticks until recovered
 }
 
 type Guard struct {
@@ -74,6 +136,20 @@ type Guard struct {
 	RecoveryTime       int                    `json:"recovery_time"`
 	StatusEffects      []StatusEffect         `json:"status_effects"`
 	Resistances        map[DamageType]float64 `json:"resistances"`
+	Speed              int                    `json:"speed,omitempty"`      // initiative; 0 means the base speed
+	Experience         int                    `json:"experience,omitempty"` // toward the next level
+	Skill              *Skill                 `json:"skill,omitempty"`      // learned from a scroll
+	Stance             string                 `json:"stance,omitempty"`     // GuardStance ID ordered for the current fight
+}
+
+// GuardStance is an order the player gives their guards for a fight.
+type GuardStance struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	AttackPct   int    `json:"attack_pct"` // % of the guard's normal attack
+	CoverPct    int    `json:"cover_pct"`  // % of the damage aimed at their charge each guard takes instead
+	Target      string `json:"target"`     // "threat" (the main foe) or "weakest" (lowest HP foe)
 }
 
 type Defense struct {
@@ -95,13 +171,78 @@ type Trap struct {
 	TriggerRate int    `json:"trigger_rate"`
 }
 
+// SiegeMonster is a kind of monster that marches on villages in a tide. HP
+// and attack are percentages of the tide's base for the village level; Speed
+// is the lane tiles it advances a tick.
+type SiegeMonster struct {
+	Name      string `json:"name"`
+	HPPct     int    `json:"hp_pct"`
+	AttackPct int    `json:"attack_pct"`
+	Speed     int    `json:"speed"`
+}
+
+// SiegeEvent is one entry of a siege wave's timeline.
+type SiegeEvent struct {
+	Tick   int    `json:"tick"`
+	Lane   int    `json:"lane"`             // 1-based
+	Kind   string `json:"kind"`             // "tower", "trap", "guard", "militia", "wound" (a guard hit), "wall", "breach" or "retreat"
+	Source string `json:"source,omitempty"` // defense, trap or guard acting; the monster for wounds, walls and breaches
+	Target string `json:"target,omitempty"`
+	Amount int    `json:"amount,omitempty"`
+	Killed bool   `json:"killed,omitempty"`
+	Text   string `json:"text"`
+}
+
+// SiegeWave is the battle report of one tide wave.
+type SiegeWave struct {
+	Number   int          `json:"number"`
+	Monsters int          `json:"monsters"`
+	Killed   int          `json:"killed"`
+	Breached int          `json:"breached"`
+	Ticks    int          `json:"ticks"`
+	Events   []SiegeEvent `json:"events"`
+
+	DamageDealt int `json:"damage_dealt"` // to monsters
+	DamageTaken int `json:"damage_taken"` // by guards and from breaches
+}
+
+// SiegeReport is the battle report of a monster tide fought out on lanes.
+type SiegeReport struct {
+	Time         int64          `json:"time"`
+	Lanes        int            `json:"lanes"`
+	Waves        []SiegeWave    `json:"waves"`
+	Kills        map[string]int `json:"kills"`       // by the defense, trap or guard that finished the monster
+	WallDamage   map[string]int `json:"wall_damage"` // damage each wall absorbed
+	WallsBroken  []string       `json:"walls_broken,omitempty"`
+	BreachDamage int            `json:"breach_damage"`
+	Threshold    int            `json:"threshold"` // breach damage the village could take
+	Victory      bool           `json:"victory"`
+}
+
+// CraftingRecipe is one entry of the crafting registry. Type is the crafting
+// screen it appears on; RequiredLevel is the village level it needs.
 type CraftingRecipe struct {
+	ID                string         `json:"id"`
 	Name              string         `json:"name"`
 	Type              string         `json:"type"`
+	Description       string         `json:"description,omitempty"`
 	RequiredResources map[string]int `json:"required_resources"`
 	RequiredLevel     int            `json:"required_level"`
-	Output            Item           `json:"output"`
-	SkillUpgrade      SkillUpgrade   `json:"skill_upgrade"`
+	RequiredCrafting  string         `json:"required_crafting,omitempty"` // entry of Village.UnlockedCrafting
+	RequiredBuilding  string         `json:"required_building,omitempty"`
+	Discoverable      bool           `json:"discoverable,omitempty"` // must be found in loot or quests first
+	VillageXP         int            `json:"village_xp"`
+
+	// Output is the crafted item. Equipment rolls a rarity between RarityMin
+	// and RarityMax and gains Output.StatsMod plus PerRarity per rarity point.
+	Output    Item    `json:"output"`
+	RarityMin int     `json:"rarity_min,omitempty"`
+	RarityMax int     `json:"rarity_max,omitempty"`
+	PerRarity StatMod `json:"per_rarity,omitempty"`
+
+	Trap         Trap         `json:"trap,omitempty"`       // placed in the village
+	SkillName    string       `json:"skill_name,omitempty"` // learned from a scroll
+	SkillUpgrade SkillUpgrade `json:"skill_upgrade"`
 }
 
 type SkillUpgrade struct {
@@ -114,6 +255,8 @@ type SkillUpgrade struct {
 
 type Character struct {
 	Name               string                 `json:"name"`
+	Class              string                 `json:"class,omitempty"`
+	Talents            map[string]int         `json:"talents,omitempty"`
 	Level              int                    `json:"level"`
 	Experience         int                    `json:"experience"`
 	ExpSinceLevel      int                    `json:"exp_since_level"`
@@ -129,13 +272,14 @@ type Character struct {
 	AttackRolls        int                    `json:"attack_rolls"`
 	DefenseRolls       int                    `json:"defense_rolls"`
 	StatsMod           StatMod                `json:"stats_mod"`
+	Attributes         Attributes             `json:"attributes"`
 	Resurrections      int                    `json:"resurrections"`
 	Inventory          []Item                 `json:"inventory"`
 	EquipmentMap       map[int]Item           `json:"equipment_map"`
 	ResourceStorageMap map[string]Resource    `json:"resource_storage_map"`
 	KnownLocations     []string               `json:"known_locations"`
 	LockedLocations    []string               `json:"locked_locations"`
-	BuiltBuildings     []Building             `json:"built_buildings"`
+	BuiltBuildings     []Building             `json:"built_buildings,omitempty"` // older saves only; moved into the home village when the character loads
 	LearnedSkills      []Skill                `json:"learned_skills"`
 	StatusEffects      []StatusEffect         `json:"status_effects"`
 	Resistances        map[DamageType]float64 `json:"resistances"`
@@ -146,6 +290,10 @@ type Character struct {
 	ActiveDungeon      *Dungeon               `json:"active_dungeon,omitempty"`
 	ActiveNPCQuests    []string               `json:"active_npc_quests"`
 	CompletedNPCQuests []string               `json:"completed_npc_quests"`
+	KnownRecipes       []string               `json:"known_recipes,omitempty"` // discovered CraftingRecipe IDs
+
+	// PendingLedger buffers ledger entries until the session is saved.
+	PendingLedger []LedgerEntry `json:"-"`
 }
 
 type Quest struct {
@@ -194,15 +342,49 @@ type Resource struct {
 }
 
 type StatMod struct {
-	AttackMod   int `json:"attack_mod"`
-	DefenseMod  int `json:"defense_mod"`
-	HitPointMod int `json:"hit_point_mod"`
+	AttackMod   int        `json:"attack_mod"`
+	DefenseMod  int        `json:"defense_mod"`
+	HitPointMod int        `json:"hit_point_mod"`
+	Attributes  Attributes `json:"attributes"`
+
+	// Effects holds special item effects (lifesteal, elemental damage, ...)
+	// keyed by effect name. Only set on a character's aggregated StatsMod.
+	Effects map[string]int `json:"effects,omitempty"`
+}
+
+// Attributes are the primary character attributes. On a Character they are
+// the allocated points; on an item's StatMod they are the item's bonuses.
+type Attributes struct {
+	Strength     int `json:"strength"`
+	Dexterity    int `json:"dexterity"`
+	Intelligence int `json:"intelligence"`
+	Vitality     int `json:"vitality"`
+	Luck         int `json:"luck"`
 }
 
 type StatusEffect struct {
 	Type     string `json:"type"`
 	Duration int    `json:"duration"`
 	Potency  int    `json:"potency"`
+	Stacks   int    `json:"stacks,omitempty"` // applications of a stacking effect; 0 counts as 1
+}
+
+// StatusDefinition is one entry of the status-effect registry and describes
+// how every StatusEffect of its Type behaves.
+type StatusDefinition struct {
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Beneficial   bool   `json:"beneficial"`       // skills grant it to the caster instead of the target
+	Tick         string `json:"tick,omitempty"`   // "damage" or "heal": Potency per stack each turn
+	AttackScale  int    `json:"attack_scale"`     // attack change per point of Potency per stack
+	DefenseScale int    `json:"defense_scale"`    // defense change per point of Potency per stack
+	SpeedScale   int    `json:"speed_scale"`      // speed change per point of Potency per stack (haste, slow)
+	SkipsTurn    bool   `json:"skips_turn"`       // stun, freeze
+	Silences     bool   `json:"silences"`         // no skills while active
+	Absorbs      bool   `json:"absorbs"`          // Potency soaks incoming damage until used up
+	Stacking     string `json:"stacking"`         // "refresh", "intensity" or "ignore"
+	MaxStacks    int    `json:"max_stacks"`       // for "intensity" stacking
+	Dispel       string `json:"dispel,omitempty"` // "magic", "poison" or "physical"; "" cannot be removed
 }
 
 type Skill struct {
@@ -214,6 +396,94 @@ type Skill struct {
 	Effect       StatusEffect `json:"effect"`
 	Description  string       `json:"description"`
 	UpgradeCount int          `json:"upgrade_count"`
+	Cooldown     int          `json:"cooldown,omitempty"`     // turns before it can be used again
+	ChargeTurns  int          `json:"charge_turns,omitempty"` // turns spent charging before it goes off
+	Rank         int          `json:"rank,omitempty"`
+	RankXP       int          `json:"rank_xp,omitempty"`
+}
+
+// ElementalReaction is what happens when Element damage hits a target under
+// one of the Trigger statuses: bonus damage, the trigger used up when
+// Consumes is set, and Effect applied. Category is its combat log category.
+type ElementalReaction struct {
+	Name        string       `json:"name"`
+	Element     DamageType   `json:"element"`
+	Trigger     []string     `json:"trigger"`
+	Consumes    bool         `json:"consumes,omitempty"`
+	BonusPct    int          `json:"bonus_pct,omitempty"`
+	Effect      StatusEffect `json:"effect,omitempty"`
+	Category    string       `json:"category"`
+	Description string       `json:"description"`
+}
+
+// BossTelegraph is a big attack a boss winds up a turn ahead. Defending
+// when it lands cuts the damage to DefendPct percent and avoids Effect.
+type BossTelegraph struct {
+	Name       string       `json:"name"`
+	Warning    string       `json:"warning"`     // shown on the wind-up turn
+	Every      int          `json:"every"`       // turns between wind-ups
+	DamagePct  int          `json:"damage_pct"`  // of an ordinary attack roll
+	DamageType DamageType   `json:"damage_type"` // physical when empty
+	DefendPct  int          `json:"defend_pct"`
+	Effect     StatusEffect `json:"effect,omitempty"`
+}
+
+// BossPhase is a stage of a boss fight, entered once the boss drops below
+// BelowPct of its health. "{name}" in Narrative is replaced by the boss's name.
+type BossPhase struct {
+	Name         string        `json:"name"`
+	BelowPct     int           `json:"below_pct"` // 100 for the opening phase
+	Narrative    string        `json:"narrative"`
+	AttackBonus  int           `json:"attack_bonus"`
+	DefenseBonus int           `json:"defense_bonus"`
+	Summon       string        `json:"summon,omitempty"` // monster type called in on entering the phase
+	SummonCount  int           `json:"summon_count,omitempty"`
+	Telegraph    BossTelegraph `json:"telegraph,omitempty"`
+}
+
+// BossDrop is one line of a boss's loot table: a named unique item at the
+// given rarity, dropped Chance percent of the time.
+type BossDrop struct {
+	Unique string `json:"unique"`
+	Rarity int    `json:"rarity"`
+	Chance int    `json:"chance"`
+}
+
+// BossDefinition scripts a boss encounter: its phases, the turn it enrages
+// on and its own loot table.
+type BossDefinition struct {
+	ID           string      `json:"id"`
+	Phases       []BossPhase `json:"phases"`
+	EnrageTurn   int         `json:"enrage_turn"` // 0 never
+	EnrageAttack int         `json:"enrage_attack"`
+	Loot         []BossDrop  `json:"loot"`
+}
+
+// AIProfile is how a monster archetype fights. Percentages of HP are of the
+// monster's own maximum unless noted.
+type AIProfile struct {
+	Name         string `json:"name"`
+	SkillChance  int    `json:"skill_chance"`  // % chance to use a skill on an ordinary turn
+	FleePct      int    `json:"flee_pct"`      // tries to run away below this HP %, 0 never
+	HealPct      int    `json:"heal_pct"`      // heals or shields itself below this HP %, 0 never
+	EnragePct    int    `json:"enrage_pct"`    // enrages once below this HP %, 0 never
+	EnrageAttack int    `json:"enrage_attack"` // attack bonus while enraged
+	FocusGuards  int    `json:"focus_guards"`  // % chance to strike a guard instead of the player
+	FinisherPct  int    `json:"finisher_pct"`  // saves mana for its strongest skill until the foe is below this HP %, 0 never
+	Prefers      string `json:"prefers"`       // skill kind tried first: "damage", "debuff" or "buff"
+}
+
+// SkillCombo rewards using Then right after First. When RequiresStatus is
+// set the target must be suffering from it, and ConsumesStatus removes it.
+type SkillCombo struct {
+	Name           string       `json:"name"`
+	First          string       `json:"first"`
+	Then           string       `json:"then"`
+	RequiresStatus string       `json:"requires_status,omitempty"`
+	ConsumesStatus bool         `json:"consumes_status,omitempty"`
+	BonusPct       int          `json:"bonus_pct"`
+	Effect         StatusEffect `json:"effect,omitempty"`
+	Description    string       `json:"description"`
 }
 
 type Monster struct {
@@ -244,10 +514,12 @@ type Monster struct {
 	IsSkillGuardian    bool                   `json:"is_skill_guardian"`
 	GuardedSkill       Skill                  `json:"guarded_skill"`
 	IsBoss             bool                   `json:"is_boss"`
+	BossID             string                 `json:"boss_id,omitempty"` // scripted encounter in data.BossDefinitions
 	ID                 string                 `json:"id,omitempty"`
 	LocationName       string                 `json:"location_name,omitempty"`
 	PlayerKills        int                    `json:"player_kills,omitempty"`
 	MonsterKills       int                    `json:"monster_kills,omitempty"`
+	Speed              int                    `json:"speed,omitempty"` // initiative; 0 means the base speed
 }
 
 type Item struct {
@@ -259,6 +531,30 @@ type Item struct {
 	ItemType    string           `json:"item_type"`
 	Consumable  ConsumableEffect `json:"consumable"`
 	SkillScroll SkillScrollData  `json:"skill_scroll"`
+
+	Affixes []Affix `json:"affixes,omitempty"`
+	Unique  bool    `json:"unique,omitempty"`
+	Set     string  `json:"set,omitempty"` // item set ID
+
+	// Enchantments fill the item's sockets, whose number depends on rarity.
+	Enchantments []Affix `json:"enchantments,omitempty"`
+
+	// Equipment wears down in combat; a broken item (Durability 0) grants
+	// no stats. MaxDurability 0 marks items from before durability existed.
+	Durability    int `json:"durability,omitempty"`
+	MaxDurability int `json:"max_durability,omitempty"`
+
+	// Quantity is the size of a stack of identical stackable items; 0 means
+	// a single item.
+	Quantity int `json:"quantity,omitempty"`
+}
+
+// Affix is a named prefix or suffix rolled onto an item, granting one effect.
+type Affix struct {
+	Name   string `json:"name"`
+	Prefix bool   `json:"prefix"`
+	Effect string `json:"effect"`
+	Value  int    `json:"value"`
 }
 
 type ConsumableEffect struct {
@@ -309,6 +605,7 @@ type InnGuest struct {
 	EquipmentMap  map[int]Item           `json:"equipment_map"`
 	LearnedSkills []Skill                `json:"learned_skills"`
 	Resistances   map[DamageType]float64 `json:"resistances"`
+	Speed         int                    `json:"speed,omitempty"`
 }
 
 // MayorData represents the town mayor (NPC or player).
@@ -533,6 +830,65 @@ type TideLeader struct {
 	TotalDamageDealt   int      `json:"total_damage_dealt"`
 	RaidParticipants   []string `json:"raid_participants"`
 	LastRaidDay        int      `json:"last_raid_day"`
+	Phase              int      `json:"phase"` // index into its boss definition's phases
+
+	GuildDamage map[string]int `json:"guild_damage,omitempty"` // this cycle's damage by guild name
+}
+
+// GuildRank is a rank within a guild and what it lets its members do. Ranks
+// are ordered: a lower Level outranks a higher one.
+type GuildRank struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Level         int    `json:"level"`
+	CanInvite     bool   `json:"can_invite"`
+	CanKick       bool   `json:"can_kick"`       // members of lower rank
+	CanPromote    bool   `json:"can_promote"`    // members of lower rank, up to the rank below their own
+	DailyWithdraw int    `json:"daily_withdraw"` // resources a day from the bank; -1 unlimited
+	WithdrawItems bool   `json:"withdraw_items"`
+}
+
+// Guild is a band of players with a shared bank and chat channel.
+type Guild struct {
+	ID         int64           `json:"id"`
+	Name       string          `json:"name"`
+	Members    []GuildMember   `json:"members"`
+	Invites    []GuildInvite   `json:"invites,omitempty"`
+	Bank       GuildBank       `json:"bank"`
+	Chat       []GuildChatLine `json:"chat,omitempty"` // oldest first
+	TideDamage int             `json:"tide_damage"`    // dealt to tide leaders by members' villages
+	CreatedAt  int64           `json:"created_at"`
+}
+
+// GuildMember is a character in a guild.
+type GuildMember struct {
+	AccountID   int64  `json:"account_id"`
+	Name        string `json:"name"`
+	Rank        string `json:"rank"` // GuildRank ID
+	Joined      int64  `json:"joined"`
+	Withdrawn   int    `json:"withdrawn,omitempty"` // resources taken from the bank on WithdrawDay
+	WithdrawDay string `json:"withdraw_day,omitempty"`
+}
+
+// GuildInvite is a standing invitation for a character to join a guild.
+type GuildInvite struct {
+	AccountID int64  `json:"account_id"`
+	Name      string `json:"name"`
+	From      string `json:"from"`
+	Time      int64  `json:"time"`
+}
+
+// GuildBank holds the resources and items guild members have deposited.
+type GuildBank struct {
+	Resources map[string]int `json:"resources"`
+	Items     []Item         `json:"items"`
+}
+
+// GuildChatLine is one message in a guild's chat.
+type GuildChatLine struct {
+	Time int64  `json:"time"`
+	From string `json:"from"`
+	Text string `json:"text"`
 }
 
 // MostWantedEntry represents a monster on the Most Wanted board.
@@ -549,3 +905,18 @@ type MostWantedEntry struct {
 	IsBoss       bool          `json:"is_boss"`
 	HP           int           `json:"hp"`
 }
+
+// LedgerEntry records one change to a character's gold, resources or items.
+// Balance is the resource stock after the change (0 for item entries).
+type LedgerEntry struct {
+	ID        int64  `json:"id"`
+	AccountID int64  `json:"account_id"`
+	Character string `json:"character"`
+	Kind      string `json:"kind"`
+	Asset     string `json:"asset"`
+	Delta     int    `json:"delta"`
+	Balance   int    `json:"balance"`
+	Reason    string `json:"reason"`
+	SourceRef string `json:"source_ref,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
@@ -16,7 +16,9 @@ import (
 
 	"github.com/gorilla/websocket"
 	"rpg-game/pkg/agent"
+	"rpg-game/pkg/anticheat"
 	"rpg-game/pkg/auth"
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/db"
 	"rpg-game/pkg/engine"
 	"rpg-game/pkg/game"
@@ -37,6 +39,7 @@ type Server struct {
 	auth     *auth.AuthService
 	metrics  *metrics.MetricsCollector
 	agentMgr *agent.Manager
+	detector *anticheat.Detector
 	version  string
 	upgrader websocket.Upgrader
 	mux      *http.ServeMux
@@ -47,6 +50,9 @@ type Server struct {
 // it defaults to ../../web/static relative to this source file.
 // maxAgents controls the maximum number of AI agents (0 disables agents).
 func NewServer(store *db.Store, authService *auth.AuthService, staticDir string, version string, mc *metrics.MetricsCollector, maxAgents int) *Server {
+	if mc != nil {
+		store.SetQueryObserver(mc.ObserveDBQuery)
+	}
 	eng := engine.NewEngineWithStore(store, mc)
 	s := &Server{
 		engine:  eng,
@@ -63,6 +69,9 @@ func NewServer(store *db.Store, authService *auth.AuthService, staticDir string,
 		},
 		mux: http.NewServeMux(),
 	}
+	if ac := config.Current().AntiCheat; ac.Enabled {
+		s.detector = anticheat.NewDetector(ac)
+	}
 
 	// REST endpoints
 	s.mux.HandleFunc("/api/register", s.corsWrapper(s.handleRegister))
@@ -73,6 +82,16 @@ func NewServer(store *db.Store, authService *auth.AuthService, staticDir string,
 	s.mux.HandleFunc("/api/mostwanted", s.corsWrapper(s.handleMostWanted))
 	s.mux.HandleFunc("/api/arena", s.corsWrapper(s.handleArena))
 	s.mux.HandleFunc("/api/metrics", s.corsWrapper(s.authMiddleware(s.handleMetrics)))
+	s.mux.HandleFunc("/api/ledger", s.corsWrapper(s.authMiddleware(s.handleLedger)))
+	s.mux.HandleFunc("/api/account/export", s.corsWrapper(s.authMiddleware(s.handleAccountExport)))
+	s.mux.HandleFunc("/api/account/delete", s.corsWrapper(s.authMiddleware(s.handleAccountDelete)))
+	s.mux.HandleFunc("/metrics", s.handlePrometheusMetrics)
+
+	// Admin API endpoints
+	s.mux.HandleFunc("/api/admin/config", s.corsWrapper(s.authMiddleware(s.adminMiddleware(s.handleAdminConfig))))
+	s.mux.HandleFunc("/api/admin/ledger", s.corsWrapper(s.authMiddleware(s.adminMiddleware(s.handleAdminLedger))))
+	s.mux.HandleFunc("/api/admin/flags", s.corsWrapper(s.authMiddleware(s.adminMiddleware(s.handleAdminFlags))))
+	s.mux.HandleFunc("/api/admin/flags/", s.corsWrapper(s.authMiddleware(s.adminMiddleware(s.handleAdminFlagByID))))
 
 	// Agent API endpoints
 	s.mux.HandleFunc("/api/agents", s.corsWrapper(s.authMiddleware(s.handleAgents)))
@@ -89,16 +108,20 @@ func NewServer(store *db.Store, authService *auth.AuthService, staticDir string,
 	fs := http.FileServer(http.Dir(staticDir))
 	s.mux.Handle("/", noCacheStaticHandler(fs))
 
-	// Evolution ticker — monsters fight each other every 60 seconds.
+	tickers := config.Current().Tickers
+
+	// Evolution ticker — monsters fight each other every tick (60s by default).
 	// Also resets arena daily battles when the date changes.
-	// Flushes metrics snapshot to DB every 60 ticks (hourly).
+	// Flushes metrics snapshot to DB every MetricsSnapshotTicks ticks (hourly by default).
 	go func() {
-		ticker := time.NewTicker(60 * time.Second)
+		ticker := time.NewTicker(time.Duration(tickers.EvolutionSeconds) * time.Second)
 		defer ticker.Stop()
 		lastArenaReset := game.GetArenaResetDate()
 		snapshotCounter := 0
 		for range ticker.C {
+			start := time.Now()
 			result := s.engine.ProcessEvolutionTick()
+			s.observeTicker("evolution", start)
 			if result != nil {
 				for _, evt := range result.Events {
 					log.Printf("[Evolution] %s at %s: %s", evt.EventType, evt.LocationName, evt.Details)
@@ -116,7 +139,7 @@ func NewServer(store *db.Store, authService *auth.AuthService, staticDir string,
 			}
 			// Hourly metrics snapshot
 			snapshotCounter++
-			if snapshotCounter >= 60 {
+			if snapshotCounter >= tickers.MetricsSnapshotTicks {
 				snapshotCounter = 0
 				if s.metrics != nil {
 					jsonData, err := s.metrics.SnapshotJSON()
@@ -139,36 +162,56 @@ func NewServer(store *db.Store, authService *auth.AuthService, staticDir string,
 		log.Printf("[Startup] Deduplicated villages table: removed %d duplicate rows", removed)
 	}
 
-	// Auto-tide ticker — process automatic monster tides every 60 seconds.
+	// Auto-tide ticker — process automatic monster tides.
 	go func() {
-		ticker := time.NewTicker(60 * time.Second)
+		ticker := time.NewTicker(time.Duration(tickers.AutoTideSeconds) * time.Second)
 		defer ticker.Stop()
 		for range ticker.C {
+			start := time.Now()
 			result := s.engine.ProcessAutoTideTick()
+			s.observeTicker("auto_tide", start)
 			if result != nil {
 				log.Printf("[AutoTide] Processed %d tides", result.TidesProcessed)
 			}
 		}
 	}()
 
-	// Village manager ticker — automated village upkeep every 60 seconds.
+	// Village manager ticker — automated village upkeep.
 	go func() {
-		ticker := time.NewTicker(60 * time.Second)
+		ticker := time.NewTicker(time.Duration(tickers.VillageManagerSeconds) * time.Second)
 		defer ticker.Stop()
 		for range ticker.C {
+			start := time.Now()
 			result := s.engine.ProcessVillageManagerTicks()
+			s.observeTicker("village_manager", start)
 			if result != nil {
 				log.Printf("[VillageManager] Managed %d villages", result.VillagesManaged)
 			}
 		}
 	}()
 
-	// Tide leader ticker — global raid processing every 60 seconds.
+	// Village job ticker — advance crafting and building queues.
+	go func() {
+		ticker := time.NewTicker(time.Duration(tickers.VillageJobSeconds) * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			start := time.Now()
+			result := s.engine.ProcessVillageJobTicks()
+			s.observeTicker("village_job", start)
+			if result != nil {
+				log.Printf("[VillageJobs] Completed %d jobs", result.JobsCompleted)
+			}
+		}
+	}()
+
+	// Tide leader ticker — global raid processing.
 	go func() {
-		ticker := time.NewTicker(60 * time.Second)
+		ticker := time.NewTicker(time.Duration(tickers.TideLeaderSeconds) * time.Second)
 		defer ticker.Stop()
 		for range ticker.C {
+			start := time.Now()
 			result := s.engine.ProcessTideLeaderTick()
+			s.observeTicker("tide_leader", start)
 			if result != nil && (result.LeaderSpawned || result.RaidProcessed) {
 				if result.LeaderSpawned {
 					log.Printf("[TideLeader] New leader spawned")
@@ -181,7 +224,9 @@ func NewServer(store *db.Store, authService *auth.AuthService, staticDir string,
 	}()
 
 	// Auto-spawn default AI agents after a brief startup delay.
-	go s.agentMgr.SpawnDefaultAgents()
+	if config.Current().Agents.SpawnDefaults {
+		go s.agentMgr.SpawnDefaultAgents()
+	}
 
 	return s
 }
@@ -200,6 +245,13 @@ func noCacheStaticHandler(next http.Handler) http.Handler {
 	})
 }
 
+// observeTicker records how long a background ticker run took.
+func (s *Server) observeTicker(name string, start time.Time) {
+	if s.metrics != nil {
+		s.metrics.ObserveTicker(name, time.Since(start))
+	}
+}
+
 // ServeHTTP delegates to the internal mux so Server satisfies http.Handler.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.mux.ServeHTTP(w, r)
@@ -264,6 +316,11 @@ func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			jsonError(w, http.StatusUnauthorized, "invalid or expired token")
 			return
 		}
+		// Tokens outlive deleted accounts; reject them once the account is gone.
+		if acct, err := s.store.GetAccountByID(accountID); err != nil || acct == nil {
+			jsonError(w, http.StatusUnauthorized, "account no longer exists")
+			return
+		}
 
 		ctx := context.WithValue(r.Context(), ctxAccountID, accountID)
 		ctx = context.WithValue(ctx, ctxUsername, username)
@@ -271,6 +328,19 @@ func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// adminMiddleware rejects requests from accounts not listed in the config's
+// server.admin_users. It must be wrapped by authMiddleware.
+func (s *Server) adminMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, _ := r.Context().Value(ctxUsername).(string)
+		if !config.Current().IsAdmin(username) {
+			jsonError(w, http.StatusForbidden, "admin access required")
+			return
+		}
+		next(w, r)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // REST handlers
 // ---------------------------------------------------------------------------
@@ -504,6 +574,199 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, http.StatusOK, result)
 }
 
+// handlePrometheusMetrics handles GET /metrics in the Prometheus text
+// exposition format. When server.metrics_token is configured the scraper must
+// send it as a bearer token.
+func (s *Server) handlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if token := config.Current().Server.MetricsToken; token != "" {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			jsonError(w, http.StatusUnauthorized, "invalid metrics token")
+			return
+		}
+	}
+	if s.metrics == nil {
+		jsonError(w, http.StatusServiceUnavailable, "metrics not available")
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.PrometheusContentType)
+	if err := s.metrics.WritePrometheus(w); err != nil {
+		log.Printf("prometheus metrics write error: %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Admin API handlers
+// ---------------------------------------------------------------------------
+
+// handleAdminConfig handles GET /api/admin/config, returning the active
+// configuration with secrets redacted.
+func (s *Server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"config":     config.Current().Redacted(),
+		"reloadable": []string{"balance"},
+	})
+}
+
+// handleLedger handles GET /api/ledger, returning the caller's own gold,
+// resource and item history. Filters: character, asset, reason, since (unix
+// seconds) and limit. When character and asset are both given the response
+// also carries the reconstructed balance history.
+func (s *Server) handleLedger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	accountID := r.Context().Value(ctxAccountID).(int64)
+	s.writeLedger(w, r, accountID)
+}
+
+// handleAdminLedger handles GET /api/admin/ledger. It accepts the same
+// filters as /api/ledger plus account_id; without account_id it searches
+// every account.
+func (s *Server) handleAdminLedger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var accountID int64
+	if v := r.URL.Query().Get("account_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			jsonError(w, http.StatusBadRequest, "invalid account_id")
+			return
+		}
+		accountID = id
+	}
+	s.writeLedger(w, r, accountID)
+}
+
+// handleAdminFlags lists accounts flagged by cheat detection. The optional
+// status query parameter filters by review status (default "open").
+func (s *Server) handleAdminFlags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = db.FlagStatusOpen
+	case "all":
+		status = ""
+	}
+	flags, err := s.store.ListAccountFlags(status, 500)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, "failed to load flags")
+		return
+	}
+	if flags == nil {
+		flags = []db.AccountFlag{}
+	}
+	jsonResponse(w, http.StatusOK, flags)
+}
+
+// handleAdminFlagByID records an admin's review of a flag:
+// POST /api/admin/flags/{id} with {"status": "cleared"|"confirmed"|"open"}.
+// Clearing a flag also resets the account's detection profile.
+func (s *Server) handleAdminFlagByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/admin/flags/"), 10, 64)
+	if err != nil || id <= 0 {
+		jsonError(w, http.StatusBadRequest, "invalid flag ID")
+		return
+	}
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	switch req.Status {
+	case db.FlagStatusOpen, db.FlagStatusConfirmed, db.FlagStatusCleared:
+	default:
+		jsonError(w, http.StatusBadRequest, "status must be open, confirmed or cleared")
+		return
+	}
+	reviewer, _ := r.Context().Value(ctxUsername).(string)
+	accountID, err := s.store.ReviewAccountFlag(id, req.Status, reviewer)
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if req.Status == db.FlagStatusCleared && s.detector != nil {
+		s.detector.Forget(accountID)
+	}
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"id": id, "account_id": accountID, "status": req.Status})
+}
+
+func (s *Server) writeLedger(w http.ResponseWriter, r *http.Request, accountID int64) {
+	q := r.URL.Query()
+	filter := db.LedgerFilter{
+		AccountID: accountID,
+		Character: q.Get("character"),
+		Asset:     q.Get("asset"),
+		Reason:    q.Get("reason"),
+		Limit:     100,
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, "invalid since")
+			return
+		}
+		filter.Since = since
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			jsonError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		if limit > 1000 {
+			limit = 1000
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := s.store.GetLedger(filter)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, "failed to load ledger")
+		return
+	}
+	if entries == nil {
+		entries = []models.LedgerEntry{}
+	}
+	result := map[string]interface{}{"entries": entries}
+
+	if accountID > 0 && filter.Character != "" && filter.Asset != "" {
+		history, err := s.store.GetBalanceHistory(accountID, filter.Character, filter.Asset, filter.Since)
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, "failed to load balance history")
+			return
+		}
+		if history == nil {
+			history = []db.BalancePoint{}
+		}
+		result["history"] = history
+	}
+
+	jsonResponse(w, http.StatusOK, result)
+}
+
 // ---------------------------------------------------------------------------
 // Agent API handlers
 // ---------------------------------------------------------------------------
@@ -561,6 +824,30 @@ func (s *Server) handleAgentByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// observeCommand feeds a processed WebSocket command to the cheat detector
+// and records any new flags for admin review.
+func (s *Server) observeCommand(accountID int64, username, sessionID string, cmd engine.GameCommand) {
+	if s.detector == nil {
+		return
+	}
+	xp, gold, ok := s.engine.SessionProgress(sessionID)
+	if !ok {
+		return
+	}
+	flags := s.detector.Observe(accountID, anticheat.Observation{
+		Time:    time.Now(),
+		Command: cmd.Type + ":" + cmd.Value,
+		TotalXP: xp,
+		Gold:    gold,
+	})
+	for _, f := range flags {
+		log.Printf("[AntiCheat] flagged %s (account %d): %s: %s", username, accountID, f.Rule, f.Detail)
+		if err := s.store.FlagAccount(accountID, f.Rule, f.Detail); err != nil {
+			log.Printf("[AntiCheat] failed to record flag for %s: %v", username, err)
+		}
+	}
+}
+
 // StopAllAgents gracefully stops all running agents. Called during server shutdown.
 func (s *Server) StopAllAgents() {
 	if s.agentMgr != nil {
@@ -580,6 +867,68 @@ func (s *Server) handleCharacters(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleAccountExport handles GET /api/account/export. It returns a JSON
+// archive of everything stored about the caller's account.
+func (s *Server) handleAccountExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	accountID := r.Context().Value(ctxAccountID).(int64)
+	archive, err := s.engine.ExportAccount(accountID)
+	if err != nil {
+		log.Printf("export account %d error: %v", accountID, err)
+		jsonError(w, http.StatusInternalServerError, "failed to export account")
+		return
+	}
+	if archive == nil {
+		jsonError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%d.json"`, accountID))
+	jsonResponse(w, http.StatusOK, archive)
+}
+
+// handleAccountDelete handles POST /api/account/delete. The caller must
+// confirm with their password. Deletion cascades to every row tied to the
+// account and scrubs its characters from shared town state.
+func (s *Server) handleAccountDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		jsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	accountID := r.Context().Value(ctxAccountID).(int64)
+	username, _ := r.Context().Value(ctxUsername).(string)
+
+	var body struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	acct, err := s.store.GetAccountByID(accountID)
+	if err != nil || acct == nil {
+		jsonError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	if !auth.CheckPassword(acct.PasswordHash, body.Password) {
+		jsonError(w, http.StatusForbidden, "password does not match")
+		return
+	}
+
+	if err := s.engine.DeleteAccount(accountID); err != nil {
+		log.Printf("delete account %d error: %v", accountID, err)
+		jsonError(w, http.StatusInternalServerError, "failed to delete account")
+		return
+	}
+	if s.detector != nil {
+		s.detector.Forget(accountID)
+	}
+	log.Printf("Account deleted: %s (account %d)", username, accountID)
+	jsonResponse(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
 // handleListCharacters handles GET /api/characters.
 func (s *Server) handleListCharacters(w http.ResponseWriter, r *http.Request) {
 	accountID := r.Context().Value(ctxAccountID).(int64)
@@ -604,7 +953,8 @@ func (s *Server) handleCreateCharacter(w http.ResponseWriter, r *http.Request) {
 	accountID := r.Context().Value(ctxAccountID).(int64)
 
 	var body struct {
-		Name string `json:"name"`
+		Name  string `json:"name"`
+		Class string `json:"class"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		jsonError(w, http.StatusBadRequest, "invalid request body")
@@ -615,14 +965,23 @@ func (s *Server) handleCreateCharacter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate a fresh level-1 character.
+	// Generate a fresh level-1 character. The class is optional; without
+	// one the character picks it later from the talents screen.
 	char := game.GenerateCharacter(body.Name, 1, 1)
+	if body.Class != "" {
+		var err error
+		if char, err = game.GenerateClassCharacter(body.Name, body.Class); err != nil {
+			jsonError(w, http.StatusBadRequest, fmt.Sprintf("unknown class %q (valid: %s)", body.Class, strings.Join(game.ClassOrder, ", ")))
+			return
+		}
+	}
 	char.EquipmentMap = map[int]models.Item{}
 	char.Inventory = []models.Item{
 		game.CreateHealthPotion("small"),
 		game.CreateHealthPotion("small"),
 		game.CreateHealthPotion("small"),
 	}
+	game.CompactInventory(&char.Inventory)
 	char.ResourceStorageMap = map[string]models.Resource{}
 	game.GenerateLocationsForNewCharacter(&char)
 
@@ -635,6 +994,7 @@ func (s *Server) handleCreateCharacter(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, http.StatusCreated, map[string]interface{}{
 		"name":  char.Name,
 		"level": char.Level,
+		"class": char.Class,
 	})
 }
 
@@ -669,6 +1029,10 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, http.StatusUnauthorized, "invalid or expired token")
 		return
 	}
+	if acct, err := s.store.GetAccountByID(accountID); err != nil || acct == nil {
+		jsonError(w, http.StatusUnauthorized, "account no longer exists")
+		return
+	}
 
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -698,10 +1062,17 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// writeJSON marshals data and writes it to the WebSocket connection safely.
 	writeJSON := func(v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if s.metrics != nil {
+			s.metrics.ObserveWSMessage("out", len(data))
+		}
 		writeMu.Lock()
 		defer writeMu.Unlock()
 		conn.SetWriteDeadline(time.Now().Add(writeWait))
-		return conn.WriteJSON(v)
+		return conn.WriteMessage(websocket.TextMessage, data)
 	}
 
 	// Register broadcast subscriber so this client receives server-wide events.
@@ -740,14 +1111,18 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	// Start harvest ticker goroutine — checks every 15 seconds.
+	tickers := config.Current().Tickers
+
+	// Start harvest ticker goroutine — checks every 15 seconds by default.
 	go func() {
-		harvestTicker := time.NewTicker(15 * time.Second)
+		harvestTicker := time.NewTicker(time.Duration(tickers.HarvestSeconds) * time.Second)
 		defer harvestTicker.Stop()
 		for {
 			select {
 			case <-harvestTicker.C:
+				start := time.Now()
 				result := s.engine.ProcessHarvestTick(sessionID)
+				s.observeTicker("harvest", start)
 				if result == nil {
 					continue
 				}
@@ -770,9 +1145,9 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	// Online players ticker — push every 15 seconds.
+	// Online players ticker — push every 15 seconds by default.
 	go func() {
-		presenceTicker := time.NewTicker(15 * time.Second)
+		presenceTicker := time.NewTicker(time.Duration(tickers.PresenceSeconds) * time.Second)
 		defer presenceTicker.Stop()
 		for {
 			select {
@@ -813,6 +1188,9 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			}
 			break
 		}
+		if s.metrics != nil {
+			s.metrics.ObserveWSMessage("in", len(message))
+		}
 
 		var cmd engine.GameCommand
 		if err := json.Unmarshal(message, &cmd); err != nil {
@@ -825,6 +1203,7 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 
 		resp := s.engine.ProcessCommand(sessionID, cmd)
+		s.observeCommand(accountID, username, sessionID, cmd)
 
 		if err := writeJSON(resp); err != nil {
 			log.Printf("failed to write response to %s: %v", username, err)
@@ -2,6 +2,8 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
@@ -11,7 +13,10 @@ import (
 
 	"github.com/gorilla/websocket"
 	"rpg-game/pkg/auth"
+	"rpg-game/pkg/config"
 	"rpg-game/pkg/db"
+	"rpg-game/pkg/metrics"
+	"rpg-game/pkg/models"
 )
 
 func setupTestServer(t *testing.T) (*Server, *httptest.Server) {
@@ -320,3 +325,222 @@ func screenOf(resp gameResponse) string {
 	}
 	return ""
 }
+
+func TestPrometheusMetricsEndpoint(t *testing.T) {
+	_, ts := setupTestServer(t)
+
+	// Without a collector the endpoint reports unavailable.
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without collector, got %d", resp.StatusCode)
+	}
+
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "metrics.db"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	srv := NewServer(store, auth.NewAuthService(store, "test-secret-key"), "", "test", metrics.NewMetricsCollector(), 0)
+	mts := httptest.NewServer(srv)
+	t.Cleanup(mts.Close)
+
+	resp2, err := http.Get(mts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp2.StatusCode)
+	}
+	if ct := resp2.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(resp2.Body)
+	if !strings.Contains(string(body), "rpg_db_query_duration_seconds_bucket") {
+		t.Errorf("expected DB query histogram in output:\n%s", body)
+	}
+}
+
+func TestLedgerEndpoint(t *testing.T) {
+	srv, ts := setupTestServer(t)
+	token := registerAndLogin(t, ts, "ledger_user", "password1")
+
+	acct, err := srv.store.GetAccountByUsername("ledger_user")
+	if err != nil {
+		t.Fatalf("GetAccountByUsername: %v", err)
+	}
+	if err := srv.store.AppendLedger(acct.ID, []models.LedgerEntry{
+		{Character: "Hero", Kind: "resource", Asset: "Gold", Delta: 25, Balance: 25, Reason: "fetch_quest"},
+	}); err != nil {
+		t.Fatalf("AppendLedger: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/ledger?character=Hero&asset=Gold", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get ledger: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Entries []models.LedgerEntry `json:"entries"`
+		History []db.BalancePoint    `json:"history"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if len(body.Entries) != 1 || len(body.History) != 1 || body.History[0].Balance != 25 {
+		t.Errorf("unexpected ledger response: %+v", body)
+	}
+
+	// Non-admins cannot use the cross-account audit endpoint.
+	req2, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/admin/ledger", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	resp2, err := http.DefaultClient.Do(req2)
+	if err != nil {
+		t.Fatalf("get admin ledger: %v", err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for non-admin, got %d", resp2.StatusCode)
+	}
+}
+
+func TestAdminFlagsEndpoint(t *testing.T) {
+	prev := config.Current()
+	cfg := *prev
+	cfg.Server.AdminUsers = []string{"flag_admin"}
+	config.Set(&cfg)
+	defer config.Set(prev)
+
+	srv, ts := setupTestServer(t)
+	adminToken := registerAndLogin(t, ts, "flag_admin", "password1")
+	userToken := registerAndLogin(t, ts, "flag_user", "password1")
+
+	acct, err := srv.store.GetAccountByUsername("flag_user")
+	if err != nil {
+		t.Fatalf("GetAccountByUsername: %v", err)
+	}
+	if err := srv.store.FlagAccount(acct.ID, "xp_rate", "90000 XP/hour"); err != nil {
+		t.Fatalf("FlagAccount: %v", err)
+	}
+
+	do := func(method, path, token, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		return resp
+	}
+
+	resp := do(http.MethodGet, "/api/admin/flags", userToken, "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for non-admin, got %d", resp.StatusCode)
+	}
+
+	resp = do(http.MethodGet, "/api/admin/flags", adminToken, "")
+	var flags []db.AccountFlag
+	json.NewDecoder(resp.Body).Decode(&flags)
+	resp.Body.Close()
+	if len(flags) != 1 || flags[0].Username != "flag_user" {
+		t.Fatalf("unexpected flags: %+v", flags)
+	}
+
+	resp = do(http.MethodPost, fmt.Sprintf("/api/admin/flags/%d", flags[0].ID), adminToken, `{"status":"cleared"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 clearing flag, got %d", resp.StatusCode)
+	}
+
+	resp = do(http.MethodGet, "/api/admin/flags", adminToken, "")
+	flags = nil
+	json.NewDecoder(resp.Body).Decode(&flags)
+	resp.Body.Close()
+	if len(flags) != 0 {
+		t.Errorf("expected no open flags after clearing, got %+v", flags)
+	}
+}
+
+func TestAccountExportAndDelete(t *testing.T) {
+	srv, ts := setupTestServer(t)
+	token := registerAndLogin(t, ts, "leaving_user", "password1")
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/api/characters", `{"name":"Leaver"}`)
+	resp.Body.Close()
+	resp = do(http.MethodPost, "/api/characters", `{"name":"Solo"}`)
+	resp.Body.Close()
+
+	// Another account shares one of the names; town records naming it must
+	// survive the export and the delete.
+	otherToken := registerAndLogin(t, ts, "staying_user", "password2")
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/characters", strings.NewReader(`{"name":"Leaver"}`))
+	req.Header.Set("Authorization", "Bearer "+otherToken)
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+	gossip := []string{"Leaver was seen at the inn", "Solo left town", "Soloman sings tonight"}
+	if err := srv.store.SaveTown(models.Town{Name: "Testtown", GossipBoard: gossip}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp = do(http.MethodGet, "/api/account/export", "")
+	var archive struct {
+		Account     struct{ Username string }   `json:"account"`
+		Characters  []models.Character          `json:"characters"`
+		Towns       []struct{ Gossip []string } `json:"towns"`
+		SharedNames []string                    `json:"shared_names"`
+	}
+	json.NewDecoder(resp.Body).Decode(&archive)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || archive.Account.Username != "leaving_user" || len(archive.Characters) != 2 {
+		t.Fatalf("unexpected export (status %d): %+v", resp.StatusCode, archive)
+	}
+	if len(archive.SharedNames) != 1 || len(archive.Towns) != 1 || len(archive.Towns[0].Gossip) != 1 ||
+		archive.Towns[0].Gossip[0] != "Solo left town" {
+		t.Errorf("export should hold only Solo's gossip and flag Leaver as shared: %+v", archive)
+	}
+
+	resp = do(http.MethodPost, "/api/account/delete", `{"password":"wrong"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 with wrong password, got %d", resp.StatusCode)
+	}
+
+	resp = do(http.MethodPost, "/api/account/delete", `{"password":"password1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 deleting account, got %d", resp.StatusCode)
+	}
+	if acct, _ := srv.store.GetAccountByUsername("leaving_user"); acct != nil {
+		t.Error("account still exists after delete")
+	}
+	if town, _ := srv.store.LoadTown("Testtown"); len(town.GossipBoard) != 2 {
+		t.Errorf("delete should only scrub Solo's gossip, left %v", town.GossipBoard)
+	}
+
+	resp = do(http.MethodGet, "/api/characters", "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected old token to be rejected, got %d", resp.StatusCode)
+	}
+}
@@ -13,7 +13,7 @@ function modalSystem() {
             const modalScreens = ['harvest_select', 'hunt_count_select', 'hunt_tracking',
                 'combat_guard_prompt', 'combat_skill_reward',
                 'autoplay_speed',
-                'character_select', 'character_create'];
+                'character_select', 'character_create', 'character_class_select'];
             return !g.inCombat && !g.prompt && g.options.length > 0 && modalScreens.includes(g.serverScreen);
         },
 
@@ -41,6 +41,7 @@ function modalSystem() {
                 'autoplay_speed': 'Auto-Play Speed',
                 'character_select': 'Select Character',
                 'character_create': 'Create Character',
+                'character_class_select': 'Choose a Class',
             };
             return titles[s] || 'Choose an Option';
         }
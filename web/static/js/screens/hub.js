@@ -58,6 +58,9 @@ function hubScreen() {
         goVillage() {
             this.$store.game.sendCommand('select', '10');
         },
+        goGuild() {
+            this.$store.game.sendCommand('select', '18');
+        },
         saveExit() {
             this.$store.game.sendCommand('select', 'exit');
         },
@@ -69,6 +72,8 @@ function hubScreen() {
                 heal: '\u2764', loot: '\uD83C\uDF81', buff: '\u2B06',
                 debuff: '\u2B07', narrative: '\uD83D\uDCDC', error: '\u26A0',
                 levelup: '\u2B50',
+                melt: '\uD83D\uDD25', steam: '\u2668', chain: '\u26A1', fumes: '\u2620',
+                boss: '\u2655',
                 broadcast: '\uD83D\uDCE2'
             };
             return icons[category] || '\u2699';
@@ -96,6 +101,16 @@ function hubScreen() {
         get autoplayOptions() {
             if (!this.isAutoPlayMenu) return [];
             return this.$store.game.options;
+        },
+
+        get isGuildScreen() {
+            const s = this.$store.game.serverScreen || '';
+            return s === 'guild' || s.startsWith('guild_');
+        },
+
+        get guildOptions() {
+            if (!this.isGuildScreen || this.$store.game.prompt) return [];
+            return this.$store.game.options;
         }
     };
 }
@@ -44,6 +44,15 @@ function villageScreen() {
             return remaining > 0 ? remaining : 0;
         },
 
+        // Time left on the village's raid shield, '' when it has none
+        shieldLeft() {
+            void this._harvestTick;
+            if (!this.v || !this.v.shield_until) return '';
+            const left = this.v.shield_until - Math.floor(Date.now() / 1000);
+            if (left <= 0) return '';
+            return Math.floor(left / 3600) + 'h ' + Math.floor((left % 3600) / 60) + 'm';
+        },
+
         // List of villagers actively harvesting
         activeHarvesters() {
             if (!this.v || !this.v.villagers) return [];
@@ -7,6 +7,7 @@ document.addEventListener('alpine:init', () => {
         combat: null,            // CombatView from server
         village: null,           // VillageView from server
         town: null,              // TownView from server
+        guild: null,             // GuildView from server
         dungeon: null,           // DungeonView from server
         serverScreen: null,      // raw screen field from server
         options: [],             // current server options
@@ -97,7 +98,7 @@ document.addEventListener('alpine:init', () => {
 
             // Handle broadcast messages (login, guardian defeat, etc.)
             // Batched with a short debounce to avoid rapid DOM mutations
-            if (resp.type === 'broadcast' || resp.type === 'auto_tide') {
+            if (resp.type === 'broadcast' || resp.type === 'auto_tide' || resp.type === 'village_job' || resp.type === 'village_event' || resp.type === 'guild_chat') {
                 if (resp.messages && resp.messages.length > 0) {
                     const batchMsgs = resp.messages
                         .filter(m => m.text && m.text.trim())
@@ -110,11 +111,15 @@ document.addEventListener('alpine:init', () => {
                         }, 300);
                     }
                 }
-                // For auto_tide, also update player and village state
-                if (resp.type === 'auto_tide' && resp.state) {
+                // For auto_tide and village pushes, also update player and village state
+                if ((resp.type === 'auto_tide' || resp.type === 'village_job' || resp.type === 'village_event') && resp.state) {
                     if (resp.state.player) this.player = resp.state.player;
                     if (resp.state.village) this.village = resp.state.village;
                 }
+                // Guild chat lines also land in the open guild card
+                if (resp.type === 'guild_chat' && this.guild && resp.messages) {
+                    this.guild.chat = (this.guild.chat || []).concat(resp.messages.filter(m => m.text).map(m => m.text));
+                }
                 if (resp.type === 'broadcast') return;
                 return;
             }
@@ -154,6 +159,11 @@ document.addEventListener('alpine:init', () => {
                 if (resp.state.town) {
                     this.town = resp.state.town;
                 }
+                if (resp.state.guild) {
+                    this.guild = resp.state.guild;
+                } else if (resp.state.screen && !resp.state.screen.startsWith('guild')) {
+                    this.guild = null;
+                }
                 if (resp.state.dungeon) {
                     this.dungeon = resp.state.dungeon;
                 } else if (!this.combat && resp.state.screen && !resp.state.screen.startsWith('dungeon')) {
@@ -257,6 +267,7 @@ document.addEventListener('alpine:init', () => {
                     this.combat = null;
                     this.village = null;
                     this.town = null;
+                    this.guild = null;
                     this.activeTab = 'hub';
                     this.onlinePlayers = [];
                 }, 1500);
@@ -314,6 +325,12 @@ document.addEventListener('alpine:init', () => {
                 return;
             }
 
+            // Guild screens
+            if (s === 'guild' || s.startsWith('guild_')) {
+                this.activeTab = 'hub';
+                return;
+            }
+
             // Auto-play screens
             if (s.startsWith('autoplay_')) {
                 this.activeTab = 'hub';